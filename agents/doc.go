@@ -10,7 +10,8 @@
 // Package agents provides and implementation of the agent interface called
 // OneShotZeroAgent. This agent uses the ReAct Framework (based on the
 // descriptions of tools) to decide what action to take. This agent is
-// optimized to be used with LLMs.
+// optimized to be used with LLMs. The ToolsAgent uses the native tool calling
+// of chat models instead, the tools being given to the model as functions.
 //
 // To make agents more powerful we need to make them iterative, ie. call the
 // model multiple times until they arrive at the final answer. That's the job of
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/schema"
@@ -20,6 +21,13 @@ type Executor struct {
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+
+	// ParallelToolCalls runs the actions planned in a single step
+	// concurrently. The steps are always returned in the order of the actions.
+	ParallelToolCalls bool
+	// MaxToolConcurrency limits the number of tools called concurrently when
+	// ParallelToolCalls is set. Zero means no limit.
+	MaxToolConcurrency int
 }
 
 var _ chains.Chain = Executor{}
@@ -37,6 +45,8 @@ func NewExecutor(agent Agent, tools []tools.Tool, opts ...CreationOption) Execut
 		Memory:                  options.memory,
 		MaxIterations:           options.maxIterations,
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
+		ParallelToolCalls:       options.parallelToolCalls,
+		MaxToolConcurrency:      options.maxToolConcurrency,
 	}
 }
 
@@ -62,29 +72,82 @@ func (e Executor) Call(ctx context.Context, inputValues map[string]any, _ ...cha
 			return e.getReturn(finish, steps), nil
 		}
 
-		for _, action := range actions {
-			tool, ok := nameToTool[strings.ToUpper(action.Tool)]
-			if !ok {
-				steps = append(steps, schema.AgentStep{
-					Action:      action,
-					Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-				})
-				continue
-			}
+		for j := range actions {
+			actions[j].Turn = i
+		}
+		newSteps, err := e.doActions(ctx, nameToTool, actions)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, newSteps...)
+	}
+
+	return nil, ErrNotFinished
+}
 
-			observation, err := tool.Call(ctx, action.ToolInput)
+// doActions calls the tools of the actions, concurrently if ParallelToolCalls
+// is set, and returns a step for each action in the order of the actions.
+func (e Executor) doActions(
+	ctx context.Context,
+	nameToTool map[string]tools.Tool,
+	actions []schema.AgentAction,
+) ([]schema.AgentStep, error) {
+	steps := make([]schema.AgentStep, len(actions))
+	if !e.ParallelToolCalls || len(actions) < 2 { //nolint:gomnd
+		for i, action := range actions {
+			step, err := doAction(ctx, nameToTool, action)
 			if err != nil {
 				return nil, err
 			}
+			steps[i] = step
+		}
+		return steps, nil
+	}
 
-			steps = append(steps, schema.AgentStep{
-				Action:      action,
-				Observation: observation,
-			})
+	limit := e.MaxToolConcurrency
+	if limit <= 0 {
+		limit = len(actions)
+	}
+	sem := make(chan struct{}, limit)
+	errs := make([]error, len(actions))
+	var wg sync.WaitGroup
+	for i, action := range actions {
+		wg.Add(1)
+		go func(i int, action schema.AgentAction) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			steps[i], errs[i] = doAction(ctx, nameToTool, action)
+		}(i, action)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
 	}
+	return steps, nil
+}
+
+func doAction(ctx context.Context, nameToTool map[string]tools.Tool, action schema.AgentAction) (schema.AgentStep, error) { //nolint:lll
+	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
+	if !ok {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
+		}, nil
+	}
 
-	return nil, ErrNotFinished
+	observation, err := tool.Call(ctx, action.ToolInput)
+	if err != nil {
+		return schema.AgentStep{}, err
+	}
+
+	return schema.AgentStep{
+		Action:      action,
+		Observation: observation,
+	}, nil
 }
 
 func (e Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
@@ -1,6 +1,8 @@
 package agents
 
 import (
+	"fmt"
+
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/tools"
 )
@@ -17,6 +19,9 @@ const (
 	// ConversationalReactDescription is an AgentType constant that represents
 	// the "conversationalReactDescription" agent type.
 	ConversationalReactDescription AgentType = "conversationalReactDescription"
+	// ToolCalling is an AgentType constant that represents the "toolCalling"
+	// agent type, using the native tool calling of chat models.
+	ToolCalling AgentType = "toolCalling"
 )
 
 // Initialize is a function that creates a new executor with the specified LLM
@@ -34,6 +39,12 @@ func Initialize(
 		agent = NewOneShotAgent(llm, tools, opts...)
 	case ConversationalReactDescription:
 		agent = NewConversationalAgent(llm, tools, opts...)
+	case ToolCalling:
+		chat, ok := llm.(llms.ChatLLM)
+		if !ok {
+			return Executor{}, fmt.Errorf("%w: the tool calling agent needs a chat model", ErrInvalidOptions)
+		}
+		agent = NewToolsAgent(chat, tools, opts...)
 	default:
 		return Executor{}, ErrUnknownAgentType
 	}
@@ -16,6 +16,8 @@ type CreationOptions struct {
 	promptPrefix            string
 	formatInstructions      string
 	promptSuffix            string
+	parallelToolCalls       bool
+	maxToolConcurrency      int
 }
 
 // CreationOption is a function type that can be used to modify the creation of the agents
@@ -48,6 +50,13 @@ func conversationalDefaultOptions() CreationOptions {
 	}
 }
 
+func toolsAgentDefaultOptions() CreationOptions {
+	return CreationOptions{
+		promptPrefix: _defaultToolsAgentSystemMessage,
+		outputKey:    _defaultOutputKey,
+	}
+}
+
 func (co CreationOptions) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
@@ -131,3 +140,13 @@ func WithMemory(m schema.Memory) CreationOption {
 		co.memory = m
 	}
 }
+
+// WithParallelToolCalls is an option for making the executor call the tools of
+// the actions planned in a single step concurrently, with at most
+// maxConcurrency calls at a time. A maxConcurrency of zero means no limit.
+func WithParallelToolCalls(maxConcurrency int) CreationOption {
+	return func(co *CreationOptions) {
+		co.parallelToolCalls = true
+		co.maxToolConcurrency = maxConcurrency
+	}
+}
@@ -0,0 +1,138 @@
+package agents
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type slowTool struct {
+	name    string
+	delay   time.Duration
+	running *int32
+	peak    *int32
+}
+
+func (t slowTool) Name() string        { return t.name }
+func (t slowTool) Description() string { return t.name }
+
+func (t slowTool) Call(_ context.Context, input string) (string, error) {
+	n := atomic.AddInt32(t.running, 1)
+	defer atomic.AddInt32(t.running, -1)
+	for {
+		peak := atomic.LoadInt32(t.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(t.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(t.delay)
+	return t.name + ":" + input, nil
+}
+
+// toolCallingAgent plans three tool calls in a single turn and finishes once
+// it got the observations.
+type toolCallingAgent struct{}
+
+func (toolCallingAgent) Plan(
+	_ context.Context,
+	steps []schema.AgentStep,
+	_ map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if len(steps) > 0 {
+		return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": "done"}}, nil
+	}
+	return []schema.AgentAction{
+		{Tool: "slow", ToolInput: "a", ToolID: "call_1", Log: "turn"},
+		{Tool: "fast", ToolInput: "b", ToolID: "call_2", Log: "turn"},
+		{Tool: "missing", ToolInput: "c", ToolID: "call_3", Log: "turn"},
+	}, nil, nil
+}
+
+func (toolCallingAgent) GetInputKeys() []string  { return []string{"input"} }
+func (toolCallingAgent) GetOutputKeys() []string { return []string{"output"} }
+
+func TestExecutorParallelToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var running, peak int32
+	executor := NewExecutor(toolCallingAgent{}, []tools.Tool{
+		slowTool{name: "slow", delay: 50 * time.Millisecond, running: &running, peak: &peak},
+		slowTool{name: "fast", delay: 10 * time.Millisecond, running: &running, peak: &peak},
+	}, WithParallelToolCalls(0), WithReturnIntermediateSteps())
+
+	result, err := executor.Call(context.Background(), map[string]any{"input": "go"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+
+	steps, ok := result[_intermediateStepsOutputKey].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 3)
+	assert.Equal(t, "slow:a", steps[0].Observation)
+	assert.Equal(t, "fast:b", steps[1].Observation)
+	assert.Equal(t, "missing is not a valid tool, try another one", steps[2].Observation)
+
+	messages := ConstructToolMessages(steps)
+	require.Len(t, messages, 4)
+	turn, ok := messages[0].(schema.AIChatMessage)
+	require.True(t, ok)
+	require.Len(t, turn.ToolCalls, 3)
+	assert.Equal(t, "call_1", turn.ToolCalls[0].ID)
+	assert.Equal(t, schema.ToolChatMessage{ID: "call_1", Content: "slow:a"}, messages[1])
+	assert.Equal(t, schema.ToolChatMessage{ID: "call_2", Content: "fast:b"}, messages[2])
+}
+
+func TestExecutorSequentialToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var running, peak int32
+	executor := NewExecutor(toolCallingAgent{}, []tools.Tool{
+		slowTool{name: "slow", delay: 10 * time.Millisecond, running: &running, peak: &peak},
+		slowTool{name: "fast", delay: 10 * time.Millisecond, running: &running, peak: &peak},
+	})
+
+	_, err := executor.Call(context.Background(), map[string]any{"input": "go"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&peak))
+}
+
+func TestConstructToolMessagesTurns(t *testing.T) {
+	t.Parallel()
+
+	// Tool-call-only turns have an empty log, the turn index separates them.
+	steps := []schema.AgentStep{
+		{Action: schema.AgentAction{Tool: "a", ToolID: "call_1", Turn: 0}, Observation: "1"},
+		{Action: schema.AgentAction{Tool: "b", ToolID: "call_2", Turn: 0}, Observation: "2"},
+		{Action: schema.AgentAction{Tool: "c", ToolID: "call_3", Turn: 1}, Observation: "3"},
+	}
+
+	messages := ConstructToolMessages(steps)
+	require.Len(t, messages, 5)
+	first, ok := messages[0].(schema.AIChatMessage)
+	require.True(t, ok)
+	assert.Len(t, first.ToolCalls, 2)
+	second, ok := messages[3].(schema.AIChatMessage)
+	require.True(t, ok)
+	require.Len(t, second.ToolCalls, 1)
+	assert.Equal(t, "call_3", second.ToolCalls[0].ID)
+}
+
+func TestToolCallActions(t *testing.T) {
+	t.Parallel()
+
+	actions := ToolCallActions(schema.AIChatMessage{
+		ToolCalls: []schema.ToolCall{
+			{ID: "call_1", Type: "function", FunctionCall: &schema.FunctionCall{Name: "slow", Arguments: `{"a":1}`}},
+			{ID: "call_2", Type: "function", FunctionCall: &schema.FunctionCall{Name: "fast", Arguments: `{}`}},
+		},
+	})
+	assert.Equal(t, []schema.AgentAction{
+		{Tool: "slow", ToolInput: `{"a":1}`, ToolID: "call_1"},
+		{Tool: "fast", ToolInput: `{}`, ToolID: "call_2"},
+	}, actions)
+}
@@ -0,0 +1,85 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ToolCallActions converts the tool calls of a message of a model with native
+// tool calling into actions, one per tool call in the order of the calls. The
+// content of the message is the log of every action, and the arguments of the
+// calls not given as a string are the JSON input of the tools.
+func ToolCallActions(msg schema.AIChatMessage) []schema.AgentAction {
+	actions := make([]schema.AgentAction, 0, len(msg.ToolCalls))
+	for _, call := range msg.ToolCalls {
+		if call.FunctionCall == nil {
+			continue
+		}
+		actions = append(actions, schema.AgentAction{
+			Tool:      call.FunctionCall.Name,
+			ToolInput: argumentsString(call.FunctionCall.Arguments),
+			Log:       msg.Content,
+			ToolID:    call.ID,
+		})
+	}
+	return actions
+}
+
+func argumentsString(arguments any) string {
+	if s, ok := arguments.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Sprint(arguments)
+	}
+	return string(b)
+}
+
+// ConstructToolMessages converts the intermediate steps of an agent using
+// native tool calling into chat messages. Consecutive steps planned in the
+// same turn, identified by their turn index, become a single AI message with
+// a tool call for each step, followed by the tool messages with the
+// observations in the order of the tool calls. Steps without a tool call id
+// become an AI message with the log and a human message with the observation.
+func ConstructToolMessages(steps []schema.AgentStep) []schema.ChatMessage {
+	messages := make([]schema.ChatMessage, 0, len(steps)*2) //nolint:gomnd
+	for i := 0; i < len(steps); {
+		if steps[i].Action.ToolID == "" {
+			messages = append(messages,
+				schema.AIChatMessage{Content: steps[i].Action.Log},
+				schema.HumanChatMessage{Content: fmt.Sprintf("Observation: %s", steps[i].Observation)},
+			)
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(steps) && steps[j].Action.ToolID != "" && steps[j].Action.Turn == steps[i].Action.Turn {
+			j++
+		}
+
+		turn := schema.AIChatMessage{Content: steps[i].Action.Log}
+		results := make([]schema.ChatMessage, 0, j-i)
+		for _, step := range steps[i:j] {
+			turn.ToolCalls = append(turn.ToolCalls, schema.ToolCall{
+				ID:   step.Action.ToolID,
+				Type: "function",
+				FunctionCall: &schema.FunctionCall{
+					Name:      step.Action.Tool,
+					Arguments: step.Action.ToolInput,
+				},
+			})
+			results = append(results, schema.ToolChatMessage{
+				ID:      step.Action.ToolID,
+				Content: step.Observation,
+			})
+		}
+		messages = append(messages, turn)
+		messages = append(messages, results...)
+		i = j
+	}
+	return messages
+}
@@ -0,0 +1,202 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/jsonschema"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_defaultToolsAgentSystemMessage = "You are a helpful assistant. Use the tools to answer the questions of the user."
+	// _toolInputParameter is the parameter of the text input of the tools
+	// that are not parameterized.
+	_toolInputParameter = "input"
+	// _maxToolNameLength is the maximum length of the names of the functions
+	// of the providers.
+	_maxToolNameLength = 64
+)
+
+// _invalidToolNameCharsRegex matches the characters not allowed in the names
+// of the functions of the providers.
+var _invalidToolNameCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// ToolsAgent is an agent driving the native tool calling of chat models, e.g.
+// of OpenAI and Anthropic, instead of parsing the actions from the text of a
+// ReAct prompt. The tools are given to the model as functions, see
+// ToolDefinitions, and the tool calls of a turn become the actions of a step,
+// run concurrently by an executor with parallel tool calls.
+type ToolsAgent struct {
+	// LLM is the chat model calling the tools.
+	LLM llms.ChatLLM
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// SystemMessage is the instructions of the model, if any.
+	SystemMessage string
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallOptions are the options of the calls of the model.
+	CallOptions []llms.CallOption
+}
+
+var _ Agent = (*ToolsAgent)(nil)
+
+// NewToolsAgent creates a new ToolsAgent with the given chat model, tools and
+// options. The prompt prefix of the options is the system message.
+func NewToolsAgent(llm llms.ChatLLM, tools []tools.Tool, opts ...CreationOption) *ToolsAgent {
+	options := toolsAgentDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolsAgent{
+		LLM:           llm,
+		Tools:         tools,
+		SystemMessage: options.promptPrefix,
+		OutputKey:     options.outputKey,
+	}
+}
+
+// Plan calls the model with the input, the tool calls of the previous steps
+// and their results, and returns the tool calls of the model as actions or
+// its answer as the finish.
+func (a *ToolsAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	messages := make([]schema.ChatMessage, 0, len(intermediateSteps)*2+2) //nolint:gomnd
+	if a.SystemMessage != "" {
+		messages = append(messages, schema.SystemChatMessage{Content: a.SystemMessage})
+	}
+	messages = append(messages, schema.HumanChatMessage{Content: inputs[_toolInputParameter]})
+	messages = append(messages, ConstructToolMessages(a.toolCallSteps(intermediateSteps))...)
+
+	options := append(a.CallOptions[:len(a.CallOptions):len(a.CallOptions)], llms.WithTools(ToolDefinitions(a.Tools)))
+	msg, err := a.LLM.Call(ctx, messages, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actions := ToolCallActions(*msg)
+	if len(actions) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: msg.Content},
+			Log:          msg.Content,
+		}, nil
+	}
+
+	byFunctionName := make(map[string]tools.Tool, len(a.Tools))
+	for _, tool := range a.Tools {
+		byFunctionName[toolFunctionName(tool.Name())] = tool
+	}
+	for i, action := range actions {
+		tool, ok := byFunctionName[action.Tool]
+		if !ok {
+			continue
+		}
+		actions[i].Tool = tool.Name()
+		if _, ok := tool.(tools.Parameterized); !ok {
+			actions[i].ToolInput = unwrapToolInput(action.ToolInput)
+		}
+	}
+	return actions, nil, nil
+}
+
+// toolCallSteps returns the steps with the actions of the tools as called by
+// the model: the names of their functions and their JSON arguments.
+func (a *ToolsAgent) toolCallSteps(steps []schema.AgentStep) []schema.AgentStep {
+	byName := make(map[string]tools.Tool, len(a.Tools))
+	for _, tool := range a.Tools {
+		byName[tool.Name()] = tool
+	}
+
+	toolCallSteps := make([]schema.AgentStep, len(steps))
+	for i, step := range steps {
+		tool, ok := byName[step.Action.Tool]
+		if ok && step.Action.ToolID != "" {
+			step.Action.Tool = toolFunctionName(tool.Name())
+			if _, ok := tool.(tools.Parameterized); !ok {
+				step.Action.ToolInput = wrapToolInput(step.Action.ToolInput)
+			}
+		}
+		toolCallSteps[i] = step
+	}
+	return toolCallSteps
+}
+
+func (a *ToolsAgent) GetInputKeys() []string {
+	return []string{_toolInputParameter}
+}
+
+func (a *ToolsAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+// ToolDefinitions converts the tools into the function tools of the models
+// with native tool calling. The parameters of the Parameterized tools are
+// their own, the other tools have a single "input" string parameter. The
+// names of the functions are the names of the tools with the characters not
+// allowed by the providers replaced by underscores.
+func ToolDefinitions(ts []tools.Tool) []llms.Tool {
+	definitions := make([]llms.Tool, 0, len(ts))
+	for _, tool := range ts {
+		var parameters any = jsonschema.Definition{
+			Type: jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{
+				_toolInputParameter: {Type: jsonschema.String, Description: "The input of the tool."},
+			},
+			Required: []string{_toolInputParameter},
+		}
+		if parameterized, ok := tool.(tools.Parameterized); ok {
+			parameters = parameterized.Parameters()
+		}
+		definitions = append(definitions, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        toolFunctionName(tool.Name()),
+				Description: tool.Description(),
+				Parameters:  parameters,
+			},
+		})
+	}
+	return definitions
+}
+
+func toolFunctionName(name string) string {
+	name = _invalidToolNameCharsRegex.ReplaceAllString(name, "_")
+	if len(name) > _maxToolNameLength {
+		name = name[:_maxToolNameLength]
+	}
+	return name
+}
+
+// unwrapToolInput returns the "input" argument of the JSON arguments of a
+// tool call, or the arguments if they have none.
+func unwrapToolInput(arguments string) string {
+	var input map[string]any
+	if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+		return arguments
+	}
+	switch value := input[_toolInputParameter].(type) {
+	case string:
+		return value
+	case nil:
+		return arguments
+	default:
+		return argumentsString(value)
+	}
+}
+
+// wrapToolInput returns the JSON arguments of the text input of a tool.
+func wrapToolInput(input string) string {
+	b, err := json.Marshal(map[string]string{_toolInputParameter: input})
+	if err != nil {
+		return input
+	}
+	return string(b)
+}
@@ -0,0 +1,132 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/jsonschema"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// toolCallingChat is a chat model answering with its messages in order,
+// recording the messages and the tools of its calls.
+type toolCallingChat struct {
+	responses []schema.AIChatMessage
+	messages  [][]schema.ChatMessage
+	tools     []llms.Tool
+}
+
+func (c *toolCallingChat) Call(_ context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	c.tools = opts.Tools
+	c.messages = append(c.messages, messages)
+	response := c.responses[0]
+	c.responses = c.responses[1:]
+	return &response, nil
+}
+
+func (c *toolCallingChat) Generate(context.Context, [][]schema.ChatMessage, ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	return nil, nil
+}
+
+func (c *toolCallingChat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, c, promptValues, options...)
+}
+
+func (c *toolCallingChat) GetNumTokens(text string) int {
+	return len(text)
+}
+
+// upperTool upper cases its input.
+type upperTool struct{}
+
+func (upperTool) Name() string        { return "Upper Case" }
+func (upperTool) Description() string { return "Upper cases a text." }
+
+func (upperTool) Call(_ context.Context, input string) (string, error) {
+	return strings.ToUpper(input), nil
+}
+
+// repeatTool repeats its text parameter.
+type repeatTool struct{}
+
+var _ tools.Parameterized = repeatTool{}
+
+func (repeatTool) Name() string        { return "repeat" }
+func (repeatTool) Description() string { return "Repeats a text." }
+
+func (repeatTool) Parameters() any {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"text":  {Type: jsonschema.String},
+			"times": {Type: jsonschema.Integer},
+		},
+	}
+}
+
+func (repeatTool) Call(_ context.Context, input string) (string, error) {
+	return "repeated " + input, nil
+}
+
+func TestToolsAgent(t *testing.T) {
+	t.Parallel()
+
+	chat := &toolCallingChat{responses: []schema.AIChatMessage{
+		{ToolCalls: []schema.ToolCall{
+			{ID: "call_1", Type: "function", FunctionCall: &schema.FunctionCall{
+				Name: "Upper_Case", Arguments: `{"input": "foo"}`,
+			}},
+			{ID: "call_2", Type: "function", FunctionCall: &schema.FunctionCall{
+				Name: "repeat", Arguments: `{"text": "bar", "times": 2}`,
+			}},
+		}},
+		{Content: "FOO and bar bar"},
+	}}
+	toolList := []tools.Tool{upperTool{}, repeatTool{}}
+	executor, err := Initialize(chat, toolList, ToolCalling, WithParallelToolCalls(0), WithReturnIntermediateSteps())
+	require.NoError(t, err)
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Upper case foo, repeat bar"})
+	require.NoError(t, err)
+	assert.Equal(t, "FOO and bar bar", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "Upper Case", steps[0].Action.Tool)
+	assert.Equal(t, "foo", steps[0].Action.ToolInput)
+	assert.Equal(t, "FOO", steps[0].Observation)
+	assert.Equal(t, `repeated {"text": "bar", "times": 2}`, steps[1].Observation)
+
+	require.Len(t, chat.tools, 2)
+	assert.Equal(t, "Upper_Case", chat.tools[0].Function.Name)
+	assert.Equal(t, "Upper cases a text.", chat.tools[0].Function.Description)
+	assert.Equal(t, repeatTool{}.Parameters(), chat.tools[1].Function.Parameters)
+
+	// The tool calls of the first turn are sent back with their results.
+	require.Len(t, chat.messages, 2)
+	assert.Equal(t, []schema.ChatMessage{
+		schema.SystemChatMessage{Content: _defaultToolsAgentSystemMessage},
+		schema.HumanChatMessage{Content: "Upper case foo, repeat bar"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{
+			{ID: "call_1", Type: "function", FunctionCall: &schema.FunctionCall{
+				Name: "Upper_Case", Arguments: `{"input":"foo"}`,
+			}},
+			{ID: "call_2", Type: "function", FunctionCall: &schema.FunctionCall{
+				Name: "repeat", Arguments: `{"text": "bar", "times": 2}`,
+			}},
+		}},
+		schema.ToolChatMessage{ID: "call_1", Content: "FOO"},
+		schema.ToolChatMessage{ID: "call_2", Content: `repeated {"text": "bar", "times": 2}`},
+	}, chat.messages[1])
+}
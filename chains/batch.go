@@ -0,0 +1,77 @@
+package chains
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the result of the call of a chain with one of the inputs of
+// Batch.
+type BatchResult struct {
+	// Outputs are the outputs of the call, nil if it failed.
+	Outputs map[string]any
+	// Err is the error of the call, if any.
+	Err error
+}
+
+// Batch calls the chain with each of the inputs concurrently, at most 5 at a
+// time unless set with WithMaxConcurrency, and returns the results in the
+// order of the inputs. Unlike Apply, the failure of a call does not stop the
+// others: the error of each call is returned in its result, and the inputs
+// not called when the context is done get the error of the context.
+//
+// The options are shared by the calls, so that their functions, e.g. the
+// streaming functions, are called concurrently. WithBatchResultFunc notifies
+// the results as the calls finish.
+func Batch(ctx context.Context, c Chain, inputValues []map[string]any, options ...ChainCallOption) []BatchResult {
+	opts := chainCallOption{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = _defaultApplyMaxNumberWorkers
+	}
+
+	results := make([]BatchResult, len(inputValues))
+	var mu sync.Mutex
+	notify := func(i int) {
+		if opts.BatchResultFunc == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		opts.BatchResultFunc(ctx, i, results[i])
+	}
+
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i, input := range inputValues {
+		if err := ctx.Err(); err != nil {
+			results[i] = BatchResult{Err: err}
+			notify(i)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			results[i] = BatchResult{Err: ctx.Err()}
+			notify(i)
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, input map[string]any) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			outputs, err := Call(ctx, c, input, options...)
+			results[i] = BatchResult{Outputs: outputs, Err: err}
+			notify(i)
+		}(i, input)
+	}
+	wg.Wait()
+
+	return results
+}
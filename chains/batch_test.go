@@ -0,0 +1,105 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// concurrencyChain is a chain recording the maximum number of its concurrent
+// calls.
+type concurrencyChain struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (c *concurrencyChain) Call(_ context.Context, values map[string]any, _ ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	c.mu.Lock()
+	c.running++
+	if c.running > c.maxRunning {
+		c.maxRunning = c.running
+	}
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+	return map[string]any{"output": values["input"]}, nil
+}
+
+func (c *concurrencyChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c *concurrencyChain) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (c *concurrencyChain) GetOutputKeys() []string {
+	return []string{"output"}
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	inputs := make([]map[string]any, 10)
+	for i := range inputs {
+		inputs[i] = map[string]any{"text": fmt.Sprint(i)}
+	}
+	// The call of the input missing the text fails alone.
+	inputs[3] = map[string]any{}
+
+	c := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.text}}", []string{"text"}))
+	notified := make(map[int]bool)
+	results := Batch(context.Background(), c, inputs, WithBatchResultFunc(func(_ context.Context, i int, _ BatchResult) {
+		notified[i] = true
+	}))
+	require.Len(t, results, len(inputs))
+	require.Len(t, notified, len(inputs))
+	for i, result := range results {
+		if i == 3 {
+			require.ErrorIs(t, result.Err, ErrMissingInputValues)
+			require.Nil(t, result.Outputs)
+			continue
+		}
+		require.NoError(t, result.Err)
+		require.Equal(t, map[string]any{"text": fmt.Sprint(i)}, result.Outputs)
+	}
+}
+
+func TestBatchMaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	inputs := make([]map[string]any, 8)
+	for i := range inputs {
+		inputs[i] = map[string]any{"input": i}
+	}
+	c := &concurrencyChain{}
+	results := Batch(context.Background(), c, inputs, WithMaxConcurrency(2))
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		require.Equal(t, i, result.Outputs["output"])
+	}
+	require.LessOrEqual(t, c.maxRunning, 2)
+}
+
+func TestBatchWithCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := Batch(ctx, &concurrencyChain{}, []map[string]any{{"input": 1}, {"input": 2}})
+	for _, result := range results {
+		require.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
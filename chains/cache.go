@@ -0,0 +1,284 @@
+package chains
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// CachedOutputs are the outputs of a chain call stored in an OutputCache.
+type CachedOutputs struct {
+	Outputs   map[string]any `json:"outputs"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// OutputCache stores the outputs of chain calls.
+type OutputCache interface {
+	// Get returns the outputs cached for the key and whether they were found.
+	Get(ctx context.Context, key string) (*CachedOutputs, bool, error)
+	// Put caches the outputs for the key.
+	Put(ctx context.Context, key string, outputs *CachedOutputs) error
+}
+
+// InMemoryOutputCache is an OutputCache keeping the outputs in memory, with
+// their values as returned by the chains.
+type InMemoryOutputCache struct {
+	mu      sync.Mutex
+	entries map[string]*CachedOutputs
+}
+
+var _ OutputCache = (*InMemoryOutputCache)(nil)
+
+// NewInMemoryOutputCache returns an empty in-memory output cache.
+func NewInMemoryOutputCache() *InMemoryOutputCache {
+	return &InMemoryOutputCache{entries: make(map[string]*CachedOutputs)}
+}
+
+// Get returns the outputs cached for the key.
+func (m *InMemoryOutputCache) Get(_ context.Context, key string) (*CachedOutputs, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	return entry, ok, nil
+}
+
+// Put caches the outputs for the key.
+func (m *InMemoryOutputCache) Put(_ context.Context, key string, outputs *CachedOutputs) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = outputs
+	return nil
+}
+
+// Len returns the number of cached entries.
+func (m *InMemoryOutputCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// cacherOutputCache is an OutputCache storing the outputs as JSON in the
+// generations of an LLM cacher.
+type cacherOutputCache struct {
+	cacher cache.Cacher
+}
+
+// NewOutputCacheFromCacher returns an output cache storing the outputs in the
+// cacher of the llms/cache package, e.g. a Redis or SQLite cacher. The
+// outputs are stored as JSON, so the values other than strings, numbers and
+// booleans are returned as their JSON decoding, e.g. map[string]any.
+func NewOutputCacheFromCacher(cacher cache.Cacher) OutputCache { //nolint:ireturn
+	return cacherOutputCache{cacher: cacher}
+}
+
+func (c cacherOutputCache) Get(ctx context.Context, key string) (*CachedOutputs, bool, error) {
+	generation, ok, err := c.cacher.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	var outputs CachedOutputs
+	if err := json.Unmarshal([]byte(generation.Text), &outputs); err != nil {
+		return nil, false, err
+	}
+	return &outputs, true, nil
+}
+
+func (c cacherOutputCache) Put(ctx context.Context, key string, outputs *CachedOutputs) error {
+	data, err := json.Marshal(outputs)
+	if err != nil {
+		return err
+	}
+	return c.cacher.Put(ctx, key, &llms.Generation{Text: string(data)})
+}
+
+// CachedChain is a chain caching the outputs of the wrapped chain, keyed on
+// the signature of the chain, its inputs and the call options, so that
+// repeated identical calls do not call the models again. The memory of the
+// wrapped chain is not updated by the calls answered from the cache.
+type CachedChain struct {
+	Chain Chain
+	// Cache stores the outputs, by default in memory.
+	Cache OutputCache
+	// TTL is how long the outputs are used once cached. Zero keeps them
+	// until they are evicted by the cache.
+	TTL time.Duration
+	// Namespace is added to the keys. Use it to keep the entries of chains
+	// with different models apart when they share a cache, as the models are
+	// only part of the signature of the chains loaded from configurations.
+	Namespace string
+}
+
+var _ Chain = CachedChain{}
+
+// CachedChainOption is a function configuring a CachedChain.
+type CachedChainOption func(c *CachedChain)
+
+// WithCacheBackend sets the cache storing the outputs, e.g. one returned by
+// NewOutputCacheFromCacher to share the outputs between processes.
+func WithCacheBackend(outputCache OutputCache) CachedChainOption {
+	return func(c *CachedChain) {
+		c.Cache = outputCache
+	}
+}
+
+// WithCacheTTL sets how long the outputs are used once cached.
+func WithCacheTTL(ttl time.Duration) CachedChainOption {
+	return func(c *CachedChain) {
+		c.TTL = ttl
+	}
+}
+
+// WithCacheNamespace sets the namespace added to the keys.
+func WithCacheNamespace(namespace string) CachedChainOption {
+	return func(c *CachedChain) {
+		c.Namespace = namespace
+	}
+}
+
+// NewCachedChain wraps the chain to cache its outputs.
+func NewCachedChain(chain Chain, opts ...CachedChainOption) CachedChain {
+	c := CachedChain{Chain: chain}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.Cache == nil {
+		c.Cache = NewInMemoryOutputCache()
+	}
+	return c
+}
+
+// Call returns the cached outputs of the inputs, or calls the wrapped chain
+// and caches its outputs. The inputs that cannot be serialized are not
+// cached. A cached string output is replayed to the streaming funcs as a
+// single chunk.
+func (c CachedChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	opts := &chainCallOption{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	key, err := c.key(values, opts)
+	if err != nil {
+		return Call(ctx, c.Chain, values, options...)
+	}
+	cached, ok, err := c.Cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if ok && (c.TTL <= 0 || time.Since(cached.CreatedAt) < c.TTL) {
+		outputs := make(map[string]any, len(cached.Outputs))
+		for k, v := range cached.Outputs {
+			outputs[k] = v
+		}
+		return outputs, replayOutputs(ctx, c.Chain.GetOutputKeys(), outputs, opts)
+	}
+
+	outputs, err := Call(ctx, c.Chain, values, options...)
+	if err != nil {
+		return nil, err
+	}
+	entry := &CachedOutputs{Outputs: make(map[string]any, len(outputs)), CreatedAt: time.Now()}
+	for k, v := range outputs {
+		entry.Outputs[k] = v
+	}
+	if err := c.Cache.Put(ctx, key, entry); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// key returns the cache key of the inputs and the call options: the hash of
+// their canonical JSON, the keys of the maps being sorted and the texts
+// trimmed, with the namespace and the signature of the chain.
+func (c CachedChain) key(values map[string]any, opts *chainCallOption) (string, error) {
+	canonicalValues := make(map[string]any, len(values))
+	for k, v := range values {
+		if text, ok := v.(string); ok {
+			v = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+		}
+		canonicalValues[k] = v
+	}
+
+	data, err := json.Marshal(struct {
+		Namespace string         `json:"namespace"`
+		Signature string         `json:"signature"`
+		Inputs    map[string]any `json:"inputs"`
+		Options   map[string]any `json:"options"`
+	}{c.Namespace, chainSignature(c.Chain), canonicalValues, normalizeChainOptions(opts)})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// chainSignature returns the JSON of the configuration of the chain, if it is
+// serializable, or its type and keys.
+func chainSignature(c Chain) string {
+	if config, err := ChainToConfig(c); err == nil {
+		if data, err := json.Marshal(config); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%T%v%v", c, c.GetInputKeys(), c.GetOutputKeys())
+}
+
+// normalizeChainOptions returns the fields of the call options as a map, but
+// the fields of func type and those of Batch.
+func normalizeChainOptions(opts *chainCallOption) map[string]any {
+	v := reflect.ValueOf(*opts)
+	t := v.Type()
+	normalized := make(map[string]any, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Func || field.Name == "MaxConcurrency" {
+			continue
+		}
+		normalized[field.Name] = v.Field(i).Interface()
+	}
+	return normalized
+}
+
+// replayOutputs passes the string output of a chain with one output to the
+// streaming funcs of the options as a single chunk.
+func replayOutputs(ctx context.Context, outputKeys []string, outputs map[string]any, opts *chainCallOption) error {
+	if len(outputKeys) != 1 {
+		return nil
+	}
+	text, ok := outputs[outputKeys[0]].(string)
+	if !ok || text == "" {
+		return nil
+	}
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte(text)); err != nil {
+			return err
+		}
+	}
+	if opts.StreamingChunkFunc != nil {
+		return opts.StreamingChunkFunc(ctx, llms.StreamChunk{Content: text})
+	}
+	return nil
+}
+
+func (c CachedChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c CachedChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+func (c CachedChain) GetOutputKeys() []string {
+	return c.Chain.GetOutputKeys()
+}
@@ -0,0 +1,64 @@
+package chains
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/cache"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestCachedChain(t *testing.T) {
+	t.Parallel()
+
+	llm := &sequenceLanguageModel{responses: []string{"foo is 34", "foo is 35"}}
+	chain := NewCachedChain(NewLLMChain(llm, prompts.NewPromptTemplate("What is {{.input}}?", []string{"input"})))
+
+	answer, err := Run(context.Background(), chain, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo is 34", answer)
+
+	// The canonical inputs are the same, the cached output is streamed.
+	var streamed []string
+	answer, err = Run(context.Background(), chain, " foo\r\n", WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		streamed = append(streamed, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "foo is 34", answer)
+	require.Equal(t, []string{"foo is 34"}, streamed)
+	require.Len(t, llm.prompts, 1)
+
+	// The call options are part of the key.
+	answer, err = Run(context.Background(), chain, "foo", WithTemperature(0.5))
+	require.NoError(t, err)
+	require.Equal(t, "foo is 35", answer)
+	require.Equal(t, 2, chain.Cache.(*InMemoryOutputCache).Len()) //nolint:forcetypeassert
+}
+
+func TestCachedChainTTL(t *testing.T) {
+	t.Parallel()
+
+	llm := &sequenceLanguageModel{responses: []string{"foo is 34", "foo is 35"}}
+	chain := NewCachedChain(
+		NewLLMChain(llm, prompts.NewPromptTemplate("What is {{.input}}?", []string{"input"})),
+		WithCacheBackend(NewOutputCacheFromCacher(cache.NewInMemory(0))),
+		WithCacheTTL(time.Nanosecond),
+	)
+
+	_, err := Run(context.Background(), chain, "foo")
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	answer, err := Run(context.Background(), chain, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo is 35", answer)
+	require.Len(t, llm.prompts, 2)
+
+	chain.TTL = time.Hour
+	answer, err = Run(context.Background(), chain, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo is 35", answer)
+	require.Len(t, llm.prompts, 2)
+}
@@ -20,10 +20,13 @@ type testLanguageModel struct {
 	simulateWork time.Duration
 	// record the prompt that was passed to the language model
 	recordedPrompt []schema.PromptValue
+	mu             sync.Mutex
 }
 
-func (l *testLanguageModel) GeneratePrompt(_ context.Context, promptValue []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+func (l *testLanguageModel) GeneratePrompt(ctx context.Context, promptValue []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.mu.Lock()
 	l.recordedPrompt = promptValue
+	l.mu.Unlock()
 	if l.simulateWork > 0 {
 		time.Sleep(l.simulateWork)
 	}
@@ -34,6 +37,22 @@ func (l *testLanguageModel) GeneratePrompt(_ context.Context, promptValue []sche
 	} else {
 		llmResult = promptValue[0].String()
 	}
+
+	// The result is streamed as one chunk.
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte(llmResult)); err != nil {
+			return llms.LLMResult{}, err
+		}
+	}
+	if opts.StreamingChunkFunc != nil {
+		if err := opts.StreamingChunkFunc(ctx, llms.StreamChunk{Content: llmResult}); err != nil {
+			return llms.LLMResult{}, err
+		}
+	}
 	return llms.LLMResult{
 		Generations: [][]*llms.Generation{{&llms.Generation{
 			Text: llmResult,
@@ -0,0 +1,133 @@
+package chains
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CheckpointStore stores the intermediate results of long running chains,
+// e.g. the summaries of the chunks of MapReduceDocuments, so that a failed
+// call resumes from them when called again with the same inputs.
+type CheckpointStore interface {
+	// Get returns the result stored for the key and whether it was found.
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Put stores the result for the key.
+	Put(ctx context.Context, key string, result string) error
+}
+
+// InMemoryCheckpointStore is a CheckpointStore keeping the results in memory,
+// surviving the failures of the calls but not of the process.
+type InMemoryCheckpointStore struct {
+	mu      sync.Mutex
+	results map[string]string
+}
+
+var _ CheckpointStore = (*InMemoryCheckpointStore)(nil)
+
+// NewInMemoryCheckpointStore returns an empty in-memory checkpoint store.
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{results: make(map[string]string)}
+}
+
+// Get returns the result stored for the key.
+func (s *InMemoryCheckpointStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.results[key]
+	return result, ok, nil
+}
+
+// Put stores the result for the key.
+func (s *InMemoryCheckpointStore) Put(_ context.Context, key string, result string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[key] = result
+	return nil
+}
+
+// Results returns a copy of the results stored, by key.
+func (s *InMemoryCheckpointStore) Results() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make(map[string]string, len(s.results))
+	for key, result := range s.results {
+		results[key] = result
+	}
+	return results
+}
+
+// FileCheckpointStore is a CheckpointStore keeping each result in a text
+// file of a directory, named after its key, so that the results survive the
+// crashes of the process and can be inspected while the chain runs.
+type FileCheckpointStore struct {
+	dir string
+}
+
+var _ CheckpointStore = FileCheckpointStore{}
+
+// NewFileCheckpointStore returns a checkpoint store keeping the results in
+// the directory, created if it does not exist.
+func NewFileCheckpointStore(dir string) (FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return FileCheckpointStore{}, err
+	}
+	return FileCheckpointStore{dir: dir}, nil
+}
+
+// Get returns the result stored for the key.
+func (s FileCheckpointStore) Get(_ context.Context, key string) (string, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// Put stores the result for the key. The file is replaced atomically, with a
+// temporary file in the same directory, so that a crash does not leave a
+// partial result.
+func (s FileCheckpointStore) Put(_ context.Context, key string, result string) error {
+	f, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(result); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path(key))
+}
+
+func (s FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.dir, key+".txt")
+}
+
+// checkpointKey returns the key of the result of the call of the chain with
+// the inputs: the hash of the signature of the chain and of the JSON of the
+// inputs, so that the results of other chains or prompts are not reused.
+func checkpointKey(c Chain, inputValues map[string]any) (string, error) {
+	data, err := json.Marshal(struct {
+		Signature string         `json:"signature"`
+		Inputs    map[string]any `json:"inputs"`
+	}{chainSignature(c), inputValues})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,94 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var errTestCrash = errors.New("crash")
+
+// crashingLanguageModel is a language model upper casing the prompts, failing
+// on the prompts containing failOn.
+type crashingLanguageModel struct {
+	mu      sync.Mutex
+	failOn  string
+	prompts []string
+}
+
+func (l *crashingLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prompt := promptValues[0].String()
+	l.prompts = append(l.prompts, prompt)
+	if l.failOn != "" && strings.Contains(prompt, l.failOn) {
+		return llms.LLMResult{}, errTestCrash
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: strings.ToUpper(prompt)}}}}, nil
+}
+
+func (l *crashingLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestMapReduceCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	llm := &crashingLanguageModel{failOn: "boo"}
+	checkpoints := NewInMemoryCheckpointStore()
+	c := NewMapReduceDocuments(
+		NewLLMChain(llm, prompts.NewPromptTemplate("{{.context}}", []string{"context"})),
+		NewStuffDocuments(NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.context}}", []string{"context"}))),
+	)
+	c.Checkpoints = checkpoints
+	docs := []schema.Document{
+		{PageContent: "foo"},
+		{PageContent: "boo"},
+		{PageContent: "zoo"},
+		{PageContent: "doo"},
+	}
+
+	_, err := Run(context.Background(), c, docs)
+	require.ErrorIs(t, err, errTestCrash)
+	results := make([]string, 0, len(docs))
+	for _, result := range checkpoints.Results() {
+		results = append(results, result)
+	}
+	require.ElementsMatch(t, []string{"FOO", "ZOO", "DOO"}, results)
+
+	// The call resumes from the checkpoints.
+	llm.failOn = ""
+	llm.prompts = nil
+	result, err := Run(context.Background(), c, docs)
+	require.NoError(t, err)
+	require.Equal(t, "FOO\n\nBOO\n\nZOO\n\nDOO", result)
+	require.Equal(t, []string{"boo"}, llm.prompts)
+	require.Len(t, checkpoints.Results(), 4)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, ok, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, store.Put(ctx, "key", "summary"))
+	require.NoError(t, store.Put(ctx, "key", "new summary"))
+	result, ok, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "new summary", result)
+}
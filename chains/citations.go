@@ -0,0 +1,111 @@
+package chains
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// _citationMarkerRegex matches the citation markers of the answers, the
+	// numbers of the sources in brackets, e.g. "[1]" or "[1, 3]".
+	_citationMarkerRegex = regexp.MustCompile(`\[\s*\d+(?:\s*,\s*\d+)*\s*\]`)
+	// _leadingMarkersRegex matches the citation markers following the end of
+	// a sentence, e.g. in "Leo is a lion. [1]".
+	_leadingMarkersRegex = regexp.MustCompile(`^(?:[ \t]*\[\s*\d+(?:\s*,\s*\d+)*\s*\])+`)
+	_spacesRegex         = regexp.MustCompile(`\s+`)
+)
+
+// Citation is a claim of an answer with the source documents it cites.
+type Citation struct {
+	// Claim is the sentence of the answer citing the documents, without its
+	// citation markers.
+	Claim string
+	// Sources are the numbers of the documents cited, from 1.
+	Sources []int
+	// Documents are the documents cited.
+	Documents []schema.Document
+}
+
+// ParseCitations returns the claims of the answer citing the documents, the
+// sentences with citation markers, the numbers from 1 of the documents in
+// brackets, e.g. "Leo is a lion [1, 3].". The markers of documents out of
+// range are ignored.
+func ParseCitations(answer string, docs []schema.Document) []Citation {
+	var citations []Citation
+	for _, sentence := range splitSentences(answer) {
+		var sources []int
+		seen := make(map[int]bool)
+		for _, marker := range _citationMarkerRegex.FindAllString(sentence, -1) {
+			for _, field := range strings.Split(strings.Trim(marker, "[] \t"), ",") {
+				source, err := strconv.Atoi(strings.TrimSpace(field))
+				if err != nil || source < 1 || source > len(docs) || seen[source] {
+					continue
+				}
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+		if len(sources) == 0 {
+			continue
+		}
+
+		claim := _citationMarkerRegex.ReplaceAllString(sentence, "")
+		claim = strings.TrimSpace(_spacesRegex.ReplaceAllString(claim, " "))
+		claim = strings.NewReplacer(" .", ".", " ,", ",", " !", "!", " ?", "?").Replace(claim)
+		citation := Citation{Claim: claim, Sources: sources}
+		for _, source := range sources {
+			citation.Documents = append(citation.Documents, docs[source-1])
+		}
+		citations = append(citations, citation)
+	}
+
+	return citations
+}
+
+// splitSentences splits the text into sentences, ending at the lines ends and
+// at the punctuation marks followed by spaces, with the citation markers
+// following them.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		end := -1
+		switch text[i] {
+		case '\n':
+			end = i + 1
+		case '.', '!', '?':
+			if i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t' || text[i+1] == '\n' {
+				end = i + 1
+				end += len(_leadingMarkersRegex.FindString(text[end:]))
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		if sentence := strings.TrimSpace(text[start:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+		i = end - 1
+	}
+	if sentence := strings.TrimSpace(text[start:]); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// numberDocuments returns copies of the documents with their contents preceded
+// by their numbers in brackets, from 1, for the answers to cite them.
+func numberDocuments(docs []schema.Document) []schema.Document {
+	numbered := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		doc.PageContent = fmt.Sprintf("[%d] %s", i+1, doc.PageContent)
+		numbered = append(numbered, doc)
+	}
+	return numbered
+}
@@ -0,0 +1,26 @@
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestParseCitations(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"}}
+
+	citations := ParseCitations(
+		"Leo is a lion [1]. He lives in the savanna. [2, 3]\nHe eats meat [4]! Version 1.5 is out [3][1]",
+		docs,
+	)
+	require.Equal(t, []Citation{
+		{Claim: "Leo is a lion.", Sources: []int{1}, Documents: docs[:1]},
+		{Claim: "He lives in the savanna.", Sources: []int{2, 3}, Documents: docs[1:]},
+		{Claim: "Version 1.5 is out", Sources: []int{3, 1}, Documents: []schema.Document{docs[2], docs[0]}},
+	}, citations)
+
+	require.Empty(t, ParseCitations("No citations here.", docs))
+}
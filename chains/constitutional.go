@@ -0,0 +1,217 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultCritiqueTemplate = `Below is a conversation between a human and an AI model, followed by a request to critique the response of the model.
+If the response already complies with the request, respond only with "No critique needed.".
+
+Human: {{.input_prompt}}
+
+Model: {{.output_from_model}}
+
+Critique Request: {{.critique_request}}
+
+Critique:`
+
+//nolint:lll
+const _defaultRevisionTemplate = `Below is a conversation between a human and an AI model, followed by a critique of the response of the model and a request to revise it.
+Respond only with the revised response of the model.
+
+Human: {{.input_prompt}}
+
+Model: {{.output_from_model}}
+
+Critique Request: {{.critique_request}}
+
+Critique: {{.critique}}
+
+Revision Request: {{.revision_request}}
+
+Revision:`
+
+const (
+	_constitutionalDefaultMaxIterations = 3
+	// _noCritiqueNeeded is the critique of the outputs complying with a
+	// principle.
+	_noCritiqueNeeded = "no critique needed"
+)
+
+// ConstitutionalPrinciple is a principle the outputs of a ConstitutionalChain
+// must comply with: the request of the critique of an output, and the request
+// of its revision according to the critique.
+type ConstitutionalPrinciple struct {
+	Name            string
+	CritiqueRequest string
+	RevisionRequest string
+}
+
+// ConstitutionalRevision is a critique of an output according to a
+// principle, and its revision if the output did not comply with the principle.
+type ConstitutionalRevision struct {
+	// Principle is the name of the principle.
+	Principle string
+	Critique  string
+	// Revision is the revised output, empty if no critique was needed.
+	Revision string
+}
+
+// ConstitutionalChain is a chain post-processing the output of another chain:
+// the output is critiqued against each principle and revised if it does not
+// comply with it, until the output complies with all the principles or
+// MaxIterations rounds of critiques.
+type ConstitutionalChain struct {
+	// Chain is the chain of the output revised, returning one string output.
+	Chain Chain
+	// CritiqueChain critiques an output from the "input_prompt", the
+	// "output_from_model" and the "critique_request" of a principle.
+	CritiqueChain *LLMChain
+	// RevisionChain revises an output from the variables of the CritiqueChain,
+	// the "critique" and the "revision_request" of the principle.
+	RevisionChain *LLMChain
+	Principles    []ConstitutionalPrinciple
+
+	// MaxIterations is the maximum number of rounds of critiques of the
+	// principles.
+	MaxIterations int
+	// ReturnIntermediateSteps returns the []ConstitutionalRevision critiques
+	// and revisions under the "intermediateSteps" key.
+	ReturnIntermediateSteps bool
+}
+
+var _ Chain = ConstitutionalChain{}
+
+// NewConstitutionalChain creates a new chain revising the output of the chain
+// according to the principles with the model.
+func NewConstitutionalChain(
+	llm llms.LanguageModel,
+	chain Chain,
+	principles []ConstitutionalPrinciple,
+) ConstitutionalChain {
+	return ConstitutionalChain{
+		Chain: chain,
+		CritiqueChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultCritiqueTemplate, []string{"input_prompt", "output_from_model", "critique_request"},
+		)),
+		RevisionChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultRevisionTemplate,
+			[]string{"input_prompt", "output_from_model", "critique_request", "critique", "revision_request"},
+		)),
+		Principles:    principles,
+		MaxIterations: _constitutionalDefaultMaxIterations,
+	}
+}
+
+// Call calls the chain and revises its output according to the principles.
+// The calls of the models are not streamed, as the outputs may be revised.
+func (c ConstitutionalChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	outputKeys := c.Chain.GetOutputKeys()
+	if len(outputKeys) != 1 {
+		return nil, fmt.Errorf("%w: the chain revised must return one output", ErrChainInitialization)
+	}
+	options = withoutStreaming(options)
+
+	outputs, err := Call(ctx, c.Chain, values, options...)
+	if err != nil {
+		return nil, err
+	}
+	output, ok := outputs[outputKeys[0]].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a string", ErrInvalidOutputValues, outputKeys[0])
+	}
+	inputPrompt, err := chainInputPrompt(c.Chain, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []ConstitutionalRevision
+	for iteration := 0; iteration < c.MaxIterations; iteration++ {
+		complied := true
+		for _, principle := range c.Principles {
+			critiqueValues := map[string]any{
+				"input_prompt":      inputPrompt,
+				"output_from_model": output,
+				"critique_request":  principle.CritiqueRequest,
+			}
+			critique, err := Predict(ctx, c.CritiqueChain, critiqueValues,
+				append(options[:len(options):len(options)], WithStopWords([]string{"\nRevision Request:"}))...)
+			if err != nil {
+				return nil, err
+			}
+			step := ConstitutionalRevision{Principle: principle.Name, Critique: strings.TrimSpace(critique)}
+			if strings.Contains(strings.ToLower(step.Critique), _noCritiqueNeeded) {
+				steps = append(steps, step)
+				continue
+			}
+
+			complied = false
+			critiqueValues["critique"] = step.Critique
+			critiqueValues["revision_request"] = principle.RevisionRequest
+			revision, err := Predict(ctx, c.RevisionChain, critiqueValues, options...)
+			if err != nil {
+				return nil, err
+			}
+			output = strings.TrimSpace(revision)
+			step.Revision = output
+			steps = append(steps, step)
+		}
+		if complied {
+			break
+		}
+	}
+
+	result := map[string]any{outputKeys[0]: output}
+	if c.ReturnIntermediateSteps {
+		result[_intermediateStepsOutputKey] = steps
+	}
+	return result, nil
+}
+
+// chainInputPrompt returns the prompt of the inputs of the chain, formatted
+// with the prompt of an LLM chain and listed otherwise.
+func chainInputPrompt(c Chain, values map[string]any) (string, error) {
+	if llmChain, ok := c.(*LLMChain); ok {
+		promptValue, err := llmChain.Prompt.FormatPrompt(values)
+		if err != nil {
+			return "", err
+		}
+		return promptValue.String(), nil
+	}
+
+	keys := append([]string{}, c.GetInputKeys()...)
+	if len(keys) == 1 {
+		return fmt.Sprint(values[keys[0]]), nil
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %v", key, values[key]))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c ConstitutionalChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c ConstitutionalChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+func (c ConstitutionalChain) GetOutputKeys() []string {
+	outputKeys := c.Chain.GetOutputKeys()
+	if c.ReturnIntermediateSteps {
+		outputKeys = append(outputKeys[:len(outputKeys):len(outputKeys)], _intermediateStepsOutputKey)
+	}
+	return outputKeys
+}
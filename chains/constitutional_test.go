@@ -0,0 +1,65 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestConstitutionalChain(t *testing.T) {
+	t.Parallel()
+
+	chain := NewLLMChain(
+		&testLanguageModel{expResult: "Break a window."},
+		prompts.NewPromptTemplate("How do I get into {{.place}}?", []string{"place"}),
+	)
+	llm := &sequenceLanguageModel{responses: []string{
+		"The response encourages breaking into a house.",
+		"Ask the owner for the key.",
+		"No critique needed.",
+	}}
+	c := NewConstitutionalChain(llm, chain, []ConstitutionalPrinciple{{
+		Name:            "legal",
+		CritiqueRequest: "Identify how the response encourages illegal activities.",
+		RevisionRequest: "Rewrite the response to only suggest legal activities.",
+	}})
+	c.ReturnIntermediateSteps = true
+
+	result, err := Call(context.Background(), c, map[string]any{"place": "my neighbor's house"})
+	require.NoError(t, err)
+	require.Equal(t, "Ask the owner for the key.", result["text"])
+	require.Equal(t, []ConstitutionalRevision{
+		{
+			Principle: "legal",
+			Critique:  "The response encourages breaking into a house.",
+			Revision:  "Ask the owner for the key.",
+		},
+		{Principle: "legal", Critique: "No critique needed."},
+	}, result["intermediateSteps"])
+
+	require.Len(t, llm.prompts, 3)
+	require.Contains(t, llm.prompts[0], "Human: How do I get into my neighbor's house?")
+	require.Contains(t, llm.prompts[0], "Model: Break a window.")
+	require.Contains(t, llm.prompts[1], "Critique: The response encourages breaking into a house.")
+	require.Contains(t, llm.prompts[2], "Model: Ask the owner for the key.")
+}
+
+func TestConstitutionalChainMaxIterations(t *testing.T) {
+	t.Parallel()
+
+	chain := NewLLMChain(&testLanguageModel{expResult: "Shout."}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	llm := &sequenceLanguageModel{responses: []string{"Too loud.", "Speak.", "Still too loud.", "Whisper."}}
+	c := NewConstitutionalChain(llm, chain, []ConstitutionalPrinciple{{
+		Name:            "quiet",
+		CritiqueRequest: "Is it loud?",
+		RevisionRequest: "Make it quieter.",
+	}})
+	c.MaxIterations = 2
+
+	result, err := Run(context.Background(), c, "What should I do?")
+	require.NoError(t, err)
+	require.Equal(t, "Whisper.", result)
+	require.Empty(t, llm.responses)
+}
@@ -108,7 +108,8 @@ func (c ConversationalRetrievalQA) Call(ctx context.Context, values map[string]a
 		chatHistoryStr = bufferStr
 	}
 
-	question, err := c.getQuestion(ctx, query, chatHistoryStr)
+	// The generated question does not stream to the caller.
+	question, err := c.getQuestion(ctx, query, chatHistoryStr, withoutStreaming(options)...)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +161,7 @@ func (c ConversationalRetrievalQA) getQuestion(
 	ctx context.Context,
 	question string,
 	chatHistoryStr string,
+	options ...ChainCallOption,
 ) (string, error) {
 	if len(chatHistoryStr) == 0 {
 		return question, nil
@@ -172,6 +174,7 @@ func (c ConversationalRetrievalQA) getQuestion(
 			"chat_history": chatHistoryStr,
 			"question":     question,
 		},
+		options...,
 	)
 	if err != nil {
 		return "", err
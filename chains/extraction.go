@@ -0,0 +1,115 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_extractionDefaultInputKey  = "input"
+	_extractionDefaultOutputKey = "items"
+
+	// nolint: lll
+	_extractionTemplate = `Extract all the entities matching the schema of the items from the following passage, with the values of their fields as stated in the passage. If a field is not stated, use its zero value. If no entity is found, respond with no items.
+
+Passage:
+{{.input}}`
+)
+
+// extractionResult is the structured output of the extraction chains.
+type extractionResult[T any] struct {
+	Items []T `json:"items" description:"The entities extracted from the passage."`
+}
+
+// Extraction is a chain extracting the entities described by T, typically a
+// struct, from unstructured text. The output of the model is constrained by
+// the JSON schema of T, see llms.GenerateStructured, and the descriptions of
+// the fields are given with their description struct tags, e.g.
+//
+//	type Person struct {
+//		Name string `json:"name" description:"The full name of the person"`
+//		Age  int    `json:"age,omitempty" description:"The age of the person"`
+//	}
+type Extraction[T any] struct {
+	// LLM is the chat model extracting the entities.
+	LLM llms.ChatLLM
+	// Prompt formats the instructions of the model with the text in the
+	// "input" variable.
+	Prompt prompts.FormatPrompter
+
+	// InputKey is the input key of the text, by default "input".
+	InputKey string
+	// OutputKey is the output key of the []T extracted, by default "items".
+	OutputKey string
+}
+
+var _ Chain = Extraction[struct{}]{}
+
+// NewExtraction creates a new extraction chain of the entities described by T
+// with the chat model.
+func NewExtraction[T any](llm llms.ChatLLM) Extraction[T] {
+	return Extraction[T]{
+		LLM:       llm,
+		Prompt:    prompts.NewPromptTemplate(_extractionTemplate, []string{"input"}),
+		InputKey:  _extractionDefaultInputKey,
+		OutputKey: _extractionDefaultOutputKey,
+	}
+}
+
+// Extract returns the entities extracted from the text.
+func (c Extraction[T]) Extract(ctx context.Context, text string, options ...ChainCallOption) ([]T, error) {
+	outputs, err := Call(ctx, c, map[string]any{c.InputKey: text}, options...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := outputs[c.OutputKey].([]T)
+	if !ok {
+		return nil, ErrInvalidOutputValues
+	}
+	return items, nil
+}
+
+// Call extracts the entities of the text of the input key.
+func (c Extraction[T]) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	text, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+	promptValue, err := c.Prompt.FormatPrompt(map[string]any{"input": text})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := llms.GenerateStructured[extractionResult[T]](
+		ctx, c.LLM, promptValue.String(), getLLMCallOptions(withoutStreaming(options)...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	items := result.Items
+	if items == nil {
+		items = []T{}
+	}
+
+	return map[string]any{c.OutputKey: items}, nil
+}
+
+// GetMemory returns a simple memory.
+func (c Extraction[T]) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the input key of the text, by default "input".
+func (c Extraction[T]) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the output key of the entities, by default "items".
+func (c Extraction[T]) GetOutputKeys() []string {
+	return []string{c.OutputKey}
+}
@@ -0,0 +1,59 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type testPerson struct {
+	Name string `json:"name" description:"The full name of the person"`
+	Age  int    `json:"age,omitempty" description:"The age of the person"`
+}
+
+// testToolCallModel is a chat model responding with a call of the tool of
+// the structured outputs.
+type testToolCallModel struct {
+	arguments string
+	messages  []schema.ChatMessage
+	options   llms.CallOptions
+}
+
+func (m *testToolCallModel) Call(_ context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	m.messages = messages
+	for _, opt := range options {
+		opt(&m.options)
+	}
+	return &schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &schema.FunctionCall{Name: m.options.Tools[0].Function.Name, Arguments: m.arguments},
+	}}}, nil
+}
+
+func (m *testToolCallModel) Generate(context.Context, [][]schema.ChatMessage, ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	return nil, nil
+}
+
+func TestExtraction(t *testing.T) {
+	t.Parallel()
+
+	model := &testToolCallModel{arguments: `{"items": [{"name": "Alex", "age": 32}, {"name": "Claudia"}]}`}
+	chain := NewExtraction[testPerson](model)
+
+	people, err := chain.Extract(context.Background(), "Alex is 32. Claudia is taller than Alex.")
+	require.NoError(t, err)
+	require.Equal(t, []testPerson{{Name: "Alex", Age: 32}, {Name: "Claudia"}}, people)
+
+	require.Contains(t, model.messages[0].GetContent(), "Alex is 32. Claudia is taller than Alex.")
+	require.Contains(t, model.messages[0].GetContent(), "The full name of the person")
+	require.Equal(t, "respond", model.options.Tools[0].Function.Name)
+
+	model.arguments = `{"items": []}`
+	people, err = chain.Extract(context.Background(), "No one is there.")
+	require.NoError(t, err)
+	require.Empty(t, people)
+}
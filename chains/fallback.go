@@ -0,0 +1,145 @@
+package chains
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// IsRetryableChainError reports whether a chain failing with the error may
+// succeed if called again: transient errors of the models, as reported by
+// llms.IsRetryableError, and outputs of the models that failed to parse.
+func IsRetryableChainError(err error) bool {
+	var parseErr outputparser.ParseError
+	return llms.IsRetryableError(err) || errors.As(err, &parseErr) || errors.Is(err, ErrInvalidOutputValues)
+}
+
+// FallbackChain is a chain calling fallback chains, e.g. with other prompts
+// or models, when the primary chain fails. The fallbacks are given the same
+// inputs and must return the output keys of the primary chain.
+type FallbackChain struct {
+	Chains []Chain
+
+	// ShouldFallback reports whether a failed call is sent to the next chain.
+	// Defaults to falling back on all the errors but the cancellation of the
+	// context.
+	ShouldFallback func(err error) bool
+}
+
+var _ Chain = FallbackChain{}
+
+// WithFallbackChain returns a chain calling the fallbacks in order when the
+// chain fails, returning the outputs of the first one succeeding, or the
+// errors of all of them. A streamed call does not fall back once chunks were
+// sent to the streaming funcs, as they cannot be taken back.
+func WithFallbackChain(chain Chain, fallbacks ...Chain) FallbackChain {
+	return FallbackChain{Chains: append([]Chain{chain}, fallbacks...)}
+}
+
+// Call calls the chains in order until one succeeds.
+func (c FallbackChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	shouldFallback := c.ShouldFallback
+	if shouldFallback == nil {
+		shouldFallback = func(error) bool { return true }
+	}
+	options, streamed := trackChainStreaming(options)
+
+	errs := make([]error, 0, len(c.Chains))
+	for _, chain := range c.Chains {
+		outputs, err := Call(ctx, chain, values, options...)
+		if err == nil {
+			return outputs, nil
+		}
+		errs = append(errs, err)
+		if *streamed || ctx.Err() != nil || !shouldFallback(err) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (c FallbackChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c FallbackChain) GetInputKeys() []string {
+	return c.Chains[0].GetInputKeys()
+}
+
+func (c FallbackChain) GetOutputKeys() []string {
+	return c.Chains[0].GetOutputKeys()
+}
+
+// RetryChain is a chain retrying the failures of the wrapped chain.
+type RetryChain struct {
+	Chain Chain
+	// Policy is the retry policy, whose IsRetryable defaults to
+	// IsRetryableChainError.
+	Policy llms.RetryPolicy
+}
+
+var _ Chain = RetryChain{}
+
+// WithRetry returns a chain retrying the failures of the chain with the
+// policy, e.g. llms.DefaultRetryPolicy(). A streamed call is not retried once
+// chunks were sent to the streaming funcs.
+func WithRetry(chain Chain, policy llms.RetryPolicy) RetryChain {
+	return RetryChain{Chain: chain, Policy: policy}
+}
+
+// Call calls the chain, retrying its failures.
+func (c RetryChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	options, streamed := trackChainStreaming(options)
+	policy := c.Policy
+	isRetryable := policy.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableChainError
+	}
+	policy.IsRetryable = func(err error) bool {
+		return !*streamed && isRetryable(err)
+	}
+
+	return llms.Retry(ctx, policy, func() (map[string]any, error) {
+		return Call(ctx, c.Chain, values, options...)
+	})
+}
+
+func (c RetryChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c RetryChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+func (c RetryChain) GetOutputKeys() []string {
+	return c.Chain.GetOutputKeys()
+}
+
+// trackChainStreaming wraps the streaming funcs of the options to report
+// whether chunks were streamed.
+func trackChainStreaming(options []ChainCallOption) ([]ChainCallOption, *bool) {
+	opts := &chainCallOption{}
+	for _, option := range options {
+		option(opts)
+	}
+	streamed := new(bool)
+	options = options[:len(options):len(options)]
+	if streamingFunc := opts.StreamingFunc; streamingFunc != nil {
+		options = append(options, WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			*streamed = true
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+	if streamingChunkFunc := opts.StreamingChunkFunc; streamingChunkFunc != nil {
+		options = append(options, WithStreamingChunkFunc(func(ctx context.Context, chunk llms.StreamChunk) error {
+			*streamed = true
+			return streamingChunkFunc(ctx, chunk)
+		}))
+	}
+	return options, streamed
+}
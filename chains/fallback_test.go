@@ -0,0 +1,118 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// flakyChain is a chain failing with its errors in order before answering,
+// streaming its answer if a streaming func is given.
+type flakyChain struct {
+	errs   []error
+	answer string
+	calls  int
+}
+
+func (c *flakyChain) Call(ctx context.Context, _ map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	c.calls++
+	opts := &chainCallOption{}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte(c.answer)); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	return map[string]any{"text": c.answer}, nil
+}
+
+func (c *flakyChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c *flakyChain) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (c *flakyChain) GetOutputKeys() []string {
+	return []string{"text"}
+}
+
+func TestWithFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	errOutage := errors.New("model outage")
+	primary := &flakyChain{errs: []error{errOutage}, answer: "primary"}
+	fallback := &flakyChain{answer: "fallback"}
+	answer, err := Run(context.Background(), WithFallbackChain(primary, fallback), "question")
+	require.NoError(t, err)
+	require.Equal(t, "fallback", answer)
+
+	errParse := outputparser.ParseError{Text: "yes?", Reason: "not a boolean"}
+	primary = &flakyChain{errs: []error{errOutage}}
+	fallback = &flakyChain{errs: []error{errParse}}
+	_, err = Run(context.Background(), WithFallbackChain(primary, fallback), "question")
+	require.ErrorIs(t, err, errOutage)
+	require.ErrorIs(t, err, errParse)
+
+	// The errors rejected by ShouldFallback are returned.
+	primary = &flakyChain{errs: []error{errOutage}}
+	fallback = &flakyChain{answer: "fallback"}
+	chain := WithFallbackChain(primary, fallback)
+	chain.ShouldFallback = func(err error) bool { return !errors.Is(err, errOutage) }
+	_, err = Run(context.Background(), chain, "question")
+	require.ErrorIs(t, err, errOutage)
+	require.Zero(t, fallback.calls)
+
+	// A streamed call does not fall back.
+	primary = &flakyChain{errs: []error{errOutage}, answer: "partial"}
+	fallback = &flakyChain{answer: "fallback"}
+	var streamed []string
+	_, err = Run(context.Background(), WithFallbackChain(primary, fallback), "question",
+		WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			streamed = append(streamed, string(chunk))
+			return nil
+		}))
+	require.ErrorIs(t, err, errOutage)
+	require.Equal(t, []string{"partial"}, streamed)
+	require.Zero(t, fallback.calls)
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	policy := llms.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}
+	errParse := outputparser.ParseError{Text: "yes?", Reason: "not a boolean"}
+	chain := &flakyChain{errs: []error{errParse, ErrInvalidOutputValues}, answer: "answer"}
+	answer, err := Run(context.Background(), WithRetry(chain, policy), "question")
+	require.NoError(t, err)
+	require.Equal(t, "answer", answer)
+	require.Equal(t, 3, chain.calls)
+
+	// The errors that are not retryable are returned.
+	errInput := errors.New("invalid input")
+	chain = &flakyChain{errs: []error{errInput}, answer: "answer"}
+	_, err = Run(context.Background(), WithRetry(chain, policy), "question")
+	require.ErrorIs(t, err, errInput)
+	require.Equal(t, 1, chain.calls)
+
+	// The retries are exhausted.
+	chain = &flakyChain{errs: []error{errParse, errParse, errParse}, answer: "answer"}
+	_, err = Run(context.Background(), WithRetry(chain, policy), "question")
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+	require.Equal(t, 3, chain.calls)
+}
@@ -0,0 +1,155 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _defaultDocumentTemplate formats the documents with their title and source
+// metadata, if any.
+const _defaultDocumentTemplate = `{{if .title}}Title: {{.title}}
+{{end}}{{if .source}}Source: {{.source}}
+{{end}}{{.page_content}}`
+
+// FormattedStuffDocuments is a chain that formats each document with a
+// template, joins them with a separator within a token budget and uses the
+// formatted context in an LLMChain, like StuffDocuments. It is a combine
+// documents chain of RetrievalQA and ConversationalRetrievalQA, injecting the
+// structure of the retrieved documents, e.g. their titles, sources and
+// scores, into the prompt.
+type FormattedStuffDocuments struct {
+	// LLMChain is the LLMChain called after formatting the documents.
+	LLMChain *LLMChain
+
+	// DocumentPrompt formats each document. Its "page_content", "score" and
+	// "rank", from 1, variables are those of the document, its other input
+	// variables are the metadata of the document, empty if missing.
+	DocumentPrompt prompts.PromptTemplate
+
+	// TokenBudget is the maximum number of tokens, as counted by the llm of
+	// the LLMChain, of the formatted documents. The documents are expected in
+	// the order of their rank, as returned by the retrievers, and the lowest
+	// ranked ones are dropped until the others fit. Zero disables the budget.
+	TokenBudget int
+
+	// Input key is the input key the chain expects the documents to be in.
+	InputKey string
+
+	// DocumentVariableName is the variable name used in the llm chain to put
+	// the formatted documents in.
+	DocumentVariableName string
+
+	// Separator is the string used to join the formatted documents.
+	Separator string
+}
+
+var _ Chain = FormattedStuffDocuments{}
+
+// NewFormattedStuffDocuments creates a new formatted stuff documents chain
+// with a llm chain used after formatting the documents, formatting their
+// title and source metadata by default.
+func NewFormattedStuffDocuments(llmChain *LLMChain) FormattedStuffDocuments {
+	return FormattedStuffDocuments{
+		LLMChain: llmChain,
+		DocumentPrompt: prompts.NewPromptTemplate(
+			_defaultDocumentTemplate, []string{"title", "source", "page_content"},
+		),
+
+		InputKey:             _combineDocumentsDefaultInputKey,
+		DocumentVariableName: _combineDocumentsDefaultDocumentVariableName,
+		Separator:            _stuffDocumentsDefaultSeparator,
+	}
+}
+
+// Call formats the documents within the token budget and calls the llm chain.
+func (c FormattedStuffDocuments) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	docs, ok := values[c.InputKey].([]schema.Document)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	text, err := c.formatDocuments(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	inputValues := make(map[string]any)
+	for key, value := range values {
+		inputValues[key] = value
+	}
+
+	inputValues[c.DocumentVariableName] = text
+	return Call(ctx, c.LLMChain, inputValues, options...)
+}
+
+// formatDocuments formats the documents in order and joins them, the
+// documents exceeding the token budget and the following ones being dropped.
+// The first document must fit in the budget.
+func (c FormattedStuffDocuments) formatDocuments(docs []schema.Document) (string, error) {
+	formatted := make([]string, 0, len(docs))
+	tokens := 0
+	for i, doc := range docs {
+		text, err := c.formatDocument(i+1, doc)
+		if err != nil {
+			return "", err
+		}
+
+		if c.TokenBudget > 0 {
+			docTokens := c.LLMChain.LLM.GetNumTokens(text)
+			if len(formatted) > 0 {
+				docTokens += c.LLMChain.LLM.GetNumTokens(c.Separator)
+			}
+			if tokens+docTokens > c.TokenBudget {
+				if len(formatted) == 0 {
+					return "", fmt.Errorf("%w: the first document of %d tokens does not fit in %d tokens",
+						ErrDocumentsTooLong, docTokens, c.TokenBudget)
+				}
+				break
+			}
+			tokens += docTokens
+		}
+		formatted = append(formatted, text)
+	}
+	return strings.Join(formatted, c.Separator), nil
+}
+
+func (c FormattedStuffDocuments) formatDocument(rank int, doc schema.Document) (string, error) {
+	values := make(map[string]any, len(c.DocumentPrompt.InputVariables))
+	for _, variable := range c.DocumentPrompt.GetInputVariables() {
+		switch variable {
+		case "page_content":
+			values[variable] = doc.PageContent
+		case "score":
+			values[variable] = doc.Score
+		case "rank":
+			values[variable] = rank
+		default:
+			value, ok := doc.Metadata[variable]
+			if !ok {
+				value = ""
+			}
+			values[variable] = value
+		}
+	}
+	return c.DocumentPrompt.Format(values)
+}
+
+// GetMemory returns a simple memory.
+func (c FormattedStuffDocuments) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the expected input keys, by default "input_documents".
+func (c FormattedStuffDocuments) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the output keys the chain will return.
+func (c FormattedStuffDocuments) GetOutputKeys() []string {
+	return append([]string{}, c.LLMChain.GetOutputKeys()...)
+}
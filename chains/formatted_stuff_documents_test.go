@@ -0,0 +1,46 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestFormattedStuffDocuments(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{
+		{PageContent: "foo is 34", Metadata: map[string]any{"title": "Foo", "source": "foo.txt"}, Score: 0.9},
+		{PageContent: "bar is 1", Metadata: map[string]any{"source": "bar.txt"}, Score: 0.5},
+		{PageContent: "baz is 2", Score: 0.1},
+	}
+	chain := NewFormattedStuffDocuments(NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate(
+		"{{.context}}", []string{"context"},
+	)))
+
+	result, err := Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "Title: Foo\nSource: foo.txt\nfoo is 34\n\nSource: bar.txt\nbar is 1\n\nbaz is 2", result)
+
+	chain.DocumentPrompt = prompts.NewPromptTemplate(
+		"[{{.rank}}] {{.page_content}} ({{.score}})", []string{"rank", "page_content", "score"},
+	)
+	chain.Separator = "\n---\n"
+	result, err = Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "[1] foo is 34 (0.9)\n---\n[2] bar is 1 (0.5)\n---\n[3] baz is 2 (0.1)", result)
+
+	// The lowest ranked documents are dropped to fit in the budget, the
+	// testLanguageModel counting a token per byte.
+	chain.TokenBudget = len("[1] foo is 34 (0.9)\n---\n[2] bar is 1 (0.5)")
+	result, err = Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "[1] foo is 34 (0.9)\n---\n[2] bar is 1 (0.5)", result)
+
+	chain.TokenBudget = 5
+	_, err = Run(context.Background(), chain, docs)
+	require.ErrorIs(t, err, ErrDocumentsTooLong)
+}
@@ -0,0 +1,238 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/graphs"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultCypherGenerationTemplate = `Task: Generate a Cypher statement to query a graph database.
+Instructions:
+Use only the provided relationship types and properties in the schema.
+Do not use any other relationship types or properties that are not provided.
+Schema:
+{{.schema}}
+Note: Do not include any explanations or apologies in your responses.
+Do not respond to any questions that might ask anything else than for you to construct a Cypher statement.
+Do not include any text except the generated Cypher statement.
+
+The question is:
+{{.question}}`
+
+//nolint:lll
+const _defaultCypherRepairTemplate = `Task: Fix a Cypher statement querying a graph database, rejected with an error.
+Instructions:
+Use only the provided relationship types and properties in the schema.
+Schema:
+{{.schema}}
+Note: Do not include any explanations or apologies in your responses.
+Do not include any text except the fixed Cypher statement.
+
+The question is:
+{{.question}}
+
+The Cypher statement is:
+{{.query}}
+
+The error is:
+{{.error}}`
+
+//nolint:lll
+const _defaultCypherQATemplate = `You are an assistant that helps to form nice and human understandable answers.
+The information part contains the provided information that you must use to construct an answer.
+The provided information is authoritative, you must never doubt it or try to use your internal knowledge to correct it.
+Make the answer sound as a response to the question. Do not mention that you based the result on the given information.
+If the provided information is empty, say that you don't know the answer.
+Information:
+{{.context}}
+
+Question: {{.question}}
+Helpful Answer:`
+
+const (
+	_graphQADefaultInputKey   = "query"
+	_graphQADefaultOutputKey  = "result"
+	_graphQADefaultTopK       = 10
+	_graphQADefaultMaxRepairs = 2
+)
+
+var (
+	// ErrInvalidCypherQuery is returned when the Cypher statement generated is
+	// still invalid after all the repairs.
+	ErrInvalidCypherQuery = errors.New("invalid cypher query")
+
+	_cypherBlockRegex   = regexp.MustCompile("(?s)```(?:cypher)?(.*?)```")
+	_cypherStringsRegex = regexp.MustCompile(`'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|` + "`[^`]*`")
+	_cypherWritesRegex  = regexp.MustCompile(`(?i)\b(?:CREATE|MERGE|DELETE|DETACH|SET|REMOVE|DROP|FOREACH|LOAD\s+CSV)\b`)
+)
+
+// GraphCypherQAChain is a chain answering questions about a graph database:
+// it generates a Cypher statement from the question with the schema of the
+// graph, validates it, asking the model to repair it if the database rejects
+// it, runs it and answers from its records.
+type GraphCypherQAChain struct {
+	// CypherLLMChain generates the Cypher statements from the "schema" and
+	// the "question".
+	CypherLLMChain *LLMChain
+	// RepairLLMChain repairs the invalid Cypher statements from the "schema",
+	// the "question", the "query" and the "error" of the database.
+	RepairLLMChain *LLMChain
+	// QALLMChain answers the questions from the "context", the records of the
+	// statements, and the "question".
+	QALLMChain *LLMChain
+	Graph      graphs.Graph
+
+	// MaxRepairs is the maximum number of repairs of an invalid statement.
+	// Zero disables the repairs.
+	MaxRepairs int
+	// TopK is the maximum number of records given to the QALLMChain.
+	TopK int
+	// AllowWrites allows the statements writing to the graph. By default the
+	// statements with write clauses are rejected, which does not replace the
+	// credentials of a read-only user.
+	AllowWrites bool
+	// ReturnIntermediateSteps returns the statement run and its records under
+	// the "intermediateSteps" key.
+	ReturnIntermediateSteps bool
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = GraphCypherQAChain{}
+
+// NewGraphCypherQAChain creates a new chain answering questions about the
+// graph with the model.
+func NewGraphCypherQAChain(llm llms.LanguageModel, graph graphs.Graph) GraphCypherQAChain {
+	return GraphCypherQAChain{
+		CypherLLMChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultCypherGenerationTemplate, []string{"schema", "question"},
+		)),
+		RepairLLMChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultCypherRepairTemplate, []string{"schema", "question", "query", "error"},
+		)),
+		QALLMChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultCypherQATemplate, []string{"context", "question"},
+		)),
+		Graph:      graph,
+		MaxRepairs: _graphQADefaultMaxRepairs,
+		TopK:       _graphQADefaultTopK,
+		InputKey:   _graphQADefaultInputKey,
+		OutputKey:  _graphQADefaultOutputKey,
+	}
+}
+
+// Call answers the question of the input key from the records of the Cypher
+// statement generated.
+func (c GraphCypherQAChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	question, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+	graphSchema, err := c.Graph.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := Predict(ctx, c.CypherLLMChain, map[string]any{
+		"schema":   graphSchema,
+		"question": question,
+	}, withoutStreaming(options)...)
+	if err != nil {
+		return nil, err
+	}
+	query := extractCypher(out)
+
+	records, err := c.query(ctx, query)
+	for attempt := 0; errors.Is(err, graphs.ErrInvalidQuery); attempt++ {
+		if attempt == c.MaxRepairs {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCypherQuery, err)
+		}
+		out, err = Predict(ctx, c.RepairLLMChain, map[string]any{
+			"schema":   graphSchema,
+			"question": question,
+			"query":    query,
+			"error":    err.Error(),
+		}, withoutStreaming(options)...)
+		if err != nil {
+			return nil, err
+		}
+		query = extractCypher(out)
+		records, err = c.query(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.TopK > 0 && len(records) > c.TopK {
+		records = records[:c.TopK]
+	}
+	recordsJSON, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	answer, err := Predict(ctx, c.QALLMChain, map[string]any{
+		"context":  string(recordsJSON),
+		"question": question,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]any{c.OutputKey: strings.TrimSpace(answer)}
+	if c.ReturnIntermediateSteps {
+		outputs[_intermediateStepsOutputKey] = []map[string]any{
+			{"query": query},
+			{"context": records},
+		}
+	}
+	return outputs, nil
+}
+
+// query validates the statement and runs it. The statements rejected, by the
+// database or because they write to the graph, return errors wrapping
+// graphs.ErrInvalidQuery.
+func (c GraphCypherQAChain) query(ctx context.Context, query string) ([]map[string]any, error) {
+	if !c.AllowWrites && _cypherWritesRegex.MatchString(_cypherStringsRegex.ReplaceAllString(query, "")) {
+		return nil, fmt.Errorf("%w: the statement must only read from the graph", graphs.ErrInvalidQuery)
+	}
+	if err := c.Graph.Validate(ctx, query); err != nil {
+		return nil, err
+	}
+	return c.Graph.Query(ctx, query, nil)
+}
+
+// extractCypher returns the statement of the code block of the output, if
+// any, or the output.
+func extractCypher(out string) string {
+	if match := _cypherBlockRegex.FindStringSubmatch(out); len(match) > 1 {
+		out = match[1]
+	}
+	return strings.TrimSpace(out)
+}
+
+func (c GraphCypherQAChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c GraphCypherQAChain) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c GraphCypherQAChain) GetOutputKeys() []string {
+	outputKeys := []string{c.OutputKey}
+	if c.ReturnIntermediateSteps {
+		outputKeys = append(outputKeys, _intermediateStepsOutputKey)
+	}
+	return outputKeys
+}
@@ -0,0 +1,85 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/graphs"
+)
+
+// testGraph is a graph rejecting the statements without a RETURN clause.
+type testGraph struct {
+	records []map[string]any
+	queries []string
+}
+
+func (g *testGraph) Query(_ context.Context, query string, _ map[string]any) ([]map[string]any, error) {
+	g.queries = append(g.queries, query)
+	return g.records, nil
+}
+
+func (g *testGraph) Validate(_ context.Context, query string) error {
+	if !strings.Contains(query, "RETURN") {
+		return fmt.Errorf("%w: missing RETURN clause", graphs.ErrInvalidQuery)
+	}
+	return nil
+}
+
+func (g *testGraph) Schema(context.Context) (string, error) {
+	return "Node properties:\nPerson {name: String}\nMovie {title: String}\n" +
+		"The relationships:\n(:Person)-[:ACTED_IN]->(:Movie)", nil
+}
+
+func TestGraphCypherQAChain(t *testing.T) {
+	t.Parallel()
+
+	graph := &testGraph{records: []map[string]any{{"m.title": "The Matrix"}}}
+	llm := &sequenceLanguageModel{responses: []string{
+		"```cypher\nMATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie)\n```",
+		"MATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie) RETURN m.title",
+		"Keanu Reeves acted in The Matrix.",
+	}}
+	chain := NewGraphCypherQAChain(llm, graph)
+	chain.ReturnIntermediateSteps = true
+
+	result, err := Call(context.Background(), chain, map[string]any{"query": "Which movies did Keanu Reeves act in?"})
+	require.NoError(t, err)
+	require.Equal(t, "Keanu Reeves acted in The Matrix.", result["result"])
+	require.Equal(t, []map[string]any{
+		{"query": "MATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie) RETURN m.title"},
+		{"context": graph.records},
+	}, result["intermediateSteps"])
+
+	require.Len(t, llm.prompts, 3)
+	require.Contains(t, llm.prompts[0], "(:Person)-[:ACTED_IN]->(:Movie)")
+	require.Contains(t, llm.prompts[1], "missing RETURN clause")
+	require.Contains(t, llm.prompts[2], `[{"m.title":"The Matrix"}]`)
+	require.Equal(t, []string{"MATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie) RETURN m.title"}, graph.queries)
+}
+
+func TestGraphCypherQAChainRejectsWrites(t *testing.T) {
+	t.Parallel()
+
+	graph := &testGraph{}
+	llm := &sequenceLanguageModel{responses: []string{
+		"MATCH (p:Person) DETACH DELETE p RETURN count(p)",
+		"MATCH (p:Person) SET p.name = 'Leo' RETURN p",
+	}}
+	chain := NewGraphCypherQAChain(llm, graph)
+	chain.MaxRepairs = 1
+
+	_, err := Run(context.Background(), chain, "Remove all the people")
+	require.ErrorIs(t, err, ErrInvalidCypherQuery)
+	require.ErrorIs(t, err, graphs.ErrInvalidQuery)
+	require.Empty(t, graph.queries)
+
+	// The write keywords of the strings are not write clauses.
+	graph.records = []map[string]any{{"m.title": "Set It Up"}}
+	llm.responses = []string{"MATCH (m:Movie {title: 'Set It Up'}) RETURN m.title", "Set It Up is a movie."}
+	answer, err := Run(context.Background(), chain, "Is Set It Up a movie?")
+	require.NoError(t, err)
+	require.Equal(t, "Set It Up is a movie.", answer)
+}
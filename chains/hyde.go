@@ -0,0 +1,138 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultHypotheticalDocumentTemplate = `Please write a passage to answer the question.
+Question: {{.question}}
+Passage:`
+
+const _hydeHypotheticalDocumentKey = "hypothetical_document"
+
+// HyDERetrievalQA is a chain used for question-answering against a retriever
+// with Hypothetical Document Embeddings: the chain first generates a
+// hypothetical document answering the query, and retrieves the documents
+// similar to it rather than to the query, as the hypothetical document is
+// closer to the documents than a short query. A vector store retriever embeds
+// the hypothetical document with the embedder of the store. The documents and
+// the query are then given to the combine documents chain, as with
+// RetrievalQA.
+//
+// See https://arxiv.org/abs/2212.10496.
+type HyDERetrievalQA struct {
+	// Retriever used to retrieve the documents similar to the hypothetical
+	// document.
+	Retriever schema.Retriever
+
+	// HypotheticalDocumentChain generates the hypothetical document from the
+	// "question".
+	HypotheticalDocumentChain *LLMChain
+
+	// The chain the documents and query is given to.
+	CombineDocumentsChain Chain
+
+	// The input key to get the query from, by default "query".
+	InputKey string
+
+	// If the chain should return the documents used by the combine
+	// documents chain in the "source_documents" key.
+	ReturnSourceDocuments bool
+
+	// If the chain should return the hypothetical document in the
+	// "hypothetical_document" key.
+	ReturnHypotheticalDocument bool
+}
+
+var _ Chain = HyDERetrievalQA{}
+
+// NewHyDERetrievalQA creates a new HyDERetrievalQA generating the
+// hypothetical documents with the llm. As with NewRetrievalQA, the chain for
+// combining documents is expected to have the "question" and
+// "input_documents" input values.
+func NewHyDERetrievalQA(
+	combineDocumentsChain Chain,
+	llm llms.LanguageModel,
+	retriever schema.Retriever,
+) HyDERetrievalQA {
+	return HyDERetrievalQA{
+		Retriever: retriever,
+		HypotheticalDocumentChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultHypotheticalDocumentTemplate, []string{"question"},
+		)),
+		CombineDocumentsChain: combineDocumentsChain,
+		InputKey:              _retrievalQADefaultInputKey,
+	}
+}
+
+// NewHyDERetrievalQAFromLLM loads a question answering combine documents
+// chain from the llm and creates a new HyDERetrievalQA chain, the drop-in
+// replacement of NewRetrievalQAFromLLM.
+func NewHyDERetrievalQAFromLLM(llm llms.LanguageModel, retriever schema.Retriever) HyDERetrievalQA {
+	return NewHyDERetrievalQA(LoadStuffQA(llm), llm, retriever)
+}
+
+// Call generates the hypothetical document of the query, gets the documents
+// similar to it from the retriever and gives them to the combine documents
+// chain. The hypothetical document is not streamed.
+func (c HyDERetrievalQA) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	query, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	hypotheticalDocument, err := Predict(ctx, c.HypotheticalDocumentChain, map[string]any{
+		"question": query,
+	}, withoutStreaming(options)...)
+	if err != nil {
+		return nil, err
+	}
+	hypotheticalDocument = strings.TrimSpace(hypotheticalDocument)
+
+	docs, err := c.Retriever.GetRelevantDocuments(ctx, hypotheticalDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := Call(ctx, c.CombineDocumentsChain, map[string]any{
+		"question":        query,
+		"input_documents": docs,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ReturnSourceDocuments {
+		result[_retrievalQADefaultSourceDocumentKey] = docs
+	}
+	if c.ReturnHypotheticalDocument {
+		result[_hydeHypotheticalDocumentKey] = hypotheticalDocument
+	}
+	return result, nil
+}
+
+func (c HyDERetrievalQA) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c HyDERetrievalQA) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c HyDERetrievalQA) GetOutputKeys() []string {
+	outputKeys := append([]string{}, c.CombineDocumentsChain.GetOutputKeys()...)
+	if c.ReturnSourceDocuments {
+		outputKeys = append(outputKeys, _retrievalQADefaultSourceDocumentKey)
+	}
+	if c.ReturnHypotheticalDocument {
+		outputKeys = append(outputKeys, _hydeHypotheticalDocumentKey)
+	}
+	return outputKeys
+}
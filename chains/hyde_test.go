@@ -0,0 +1,46 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// recordingRetriever is a retriever recording its queries.
+type recordingRetriever struct {
+	testRetriever
+	queries []string
+}
+
+func (r *recordingRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	r.queries = append(r.queries, query)
+	return r.testRetriever.GetRelevantDocuments(ctx, query)
+}
+
+func TestHyDERetrievalQA(t *testing.T) {
+	t.Parallel()
+
+	llm := &sequenceLanguageModel{responses: []string{
+		" Foo is a number, usually 34. ",
+		"foo is 34",
+	}}
+	retriever := &recordingRetriever{}
+	chain := NewHyDERetrievalQAFromLLM(llm, retriever)
+	chain.ReturnSourceDocuments = true
+	chain.ReturnHypotheticalDocument = true
+
+	result, err := Call(context.Background(), chain, map[string]any{"query": "what is foo?"})
+	require.NoError(t, err)
+	require.Equal(t, "foo is 34", result["text"])
+	require.Equal(t, "Foo is a number, usually 34.", result["hypothetical_document"])
+	require.Len(t, result["source_documents"], 2)
+
+	require.Equal(t, []string{"Foo is a number, usually 34."}, retriever.queries)
+	require.Len(t, llm.prompts, 2)
+	require.Contains(t, llm.prompts[0], "Question: what is foo?\nPassage:")
+	require.Contains(t, llm.prompts[1], "foo is 34")
+	require.Contains(t, llm.prompts[1], "what is foo?")
+	require.Equal(t, []string{"text", "source_documents", "hypothetical_document"}, chain.GetOutputKeys())
+}
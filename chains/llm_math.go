@@ -10,23 +10,24 @@ import (
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
-	"go.starlark.net/lib/math"
-	"go.starlark.net/starlark"
 )
 
 const (
-	_llmMathPrompt = `Translate a math problem into a expression that can be evaluated as Starlark.
-Use the output of running this code to answer the question.
+	_llmMathPrompt = `Translate a math problem into an arithmetic expression that can be evaluated.
+Use the value of the expression to answer the question.
+The expression can only use numbers, the operators +, -, *, /, // (floor division), % and ** (power),
+parentheses, the constants pi and e and the functions abs, ceil, floor, round, min, max, sqrt, pow,
+exp, log, log2, log10, sin, cos, tan, asin, acos and atan.
 
 ---
 Question: (Question with math problem.)
-` + "```" + `starlark
+` + "```" + `text
 $(single line expression that solves the problem)
 ` + "```" + `
 
 ---
 Question: What is 37593 * 67?
-` + "```" + `starlark
+` + "```" + `text
 37593 * 67
 ` + "```" + `
 
@@ -35,13 +36,16 @@ Question: {{.question}}
 `
 )
 
-// LLMMathChain is a chain used for evaluating math expressions.
+// LLMMathChain is a chain used for evaluating math expressions. The model
+// translates the question into an arithmetic expression, evaluated without
+// executing any code by EvaluateMathExpression.
 type LLMMathChain struct {
 	LLMChain *LLMChain
 }
 
 var _ Chain = LLMMathChain{}
 
+// NewLLMMathChain creates a new math chain with the model.
 func NewLLMMathChain(llm llms.LanguageModel) LLMMathChain {
 	p := prompts.NewPromptTemplate(_llmMathPrompt, []string{"question"})
 	c := NewLLMChain(llm, p)
@@ -50,8 +54,8 @@ func NewLLMMathChain(llm llms.LanguageModel) LLMMathChain {
 	}
 }
 
-// Call gets relevant documents from the retriever and gives them to the combine
-// documents chain.
+// Call translates the question into an expression and returns its value as
+// the answer.
 func (c LLMMathChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint: lll
 	question, ok := values["question"].(string)
 	if !ok {
@@ -79,11 +83,13 @@ func (c LLMMathChain) GetOutputKeys() []string {
 	return []string{"answer"}
 }
 
-var starlarkBlockRegex = regexp.MustCompile("(?s)```starlark(.*)```")
+// mathBlockRegex matches the expression in a code block, which is a starlark
+// block in the outputs of the previous prompts.
+var mathBlockRegex = regexp.MustCompile("(?s)```(?:text|starlark)?(.*?)```")
 
 func (c LLMMathChain) processLLMResult(llmOutput string) (string, error) {
 	llmOutput = strings.TrimSpace(llmOutput)
-	textMatch := starlarkBlockRegex.FindStringSubmatch(llmOutput)
+	textMatch := mathBlockRegex.FindStringSubmatch(llmOutput)
 	if len(textMatch) > 0 {
 		expression := textMatch[1]
 		output, err := c.evaluateExpression(expression)
@@ -99,10 +105,5 @@ func (c LLMMathChain) processLLMResult(llmOutput string) (string, error) {
 }
 
 func (c LLMMathChain) evaluateExpression(expression string) (string, error) {
-	expression = strings.TrimSpace(expression)
-	v, err := starlark.Eval(&starlark.Thread{Name: "main"}, "input", expression, math.Module.Members)
-	if err != nil {
-		return "", err
-	}
-	return v.String(), nil
+	return EvaluateMathExpression(strings.TrimSpace(expression))
 }
@@ -25,3 +25,17 @@ func TestLLMMath(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, strings.Contains(result, "58.708"), "expected 58.708 in result")
 }
+
+func TestLLMMathEvaluatesExpression(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{expResult: "```text\n(40 + 3) * 10000 / 7324.3\n```"}
+	chain := NewLLMMathChain(llm)
+	result, err := Run(context.Background(), chain, "what is forty plus three times ten thousand divided by 7324.3?")
+	require.NoError(t, err)
+	require.Equal(t, "58.708682058353699", result)
+
+	llm.expResult = "```text\n__import__('os').system('ls')\n```"
+	_, err = Run(context.Background(), chain, "what is in the directory?")
+	require.ErrorIs(t, err, ErrInvalidMathExpression)
+}
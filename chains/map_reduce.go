@@ -2,6 +2,7 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/tmc/langchaingo/memory"
@@ -9,6 +10,15 @@ import (
 	"golang.org/x/exp/maps"
 )
 
+// _mapReduceDefaultTokenMax is the default maximum number of tokens of the
+// documents combined by the reduce chain.
+const _mapReduceDefaultTokenMax = 3000
+
+// ErrDocumentsTooLong is returned by the map reduce documents chain when the
+// results of the LLMChain can not be collapsed to fit the maximum number of
+// tokens of the reduce chain.
+var ErrDocumentsTooLong = errors.New("documents too long to be combined")
+
 // MapReduceDocuments is a chain that combines documents by mapping a chain over them, then
 // combining the results using another chain.
 type MapReduceDocuments struct {
@@ -18,6 +28,15 @@ type MapReduceDocuments struct {
 	// The chain to combine the mapped results of the LLMChain.
 	ReduceChain Chain
 
+	// The chain to collapse groups of the mapped results into one document each
+	// while they do not fit in TokenMax. The reduce chain if nil.
+	CollapseChain Chain
+
+	// TokenMax is the maximum number of tokens, as counted by the llm of the
+	// LLMChain, of the documents given to the reduce chain. The mapped results
+	// are collapsed recursively until they fit. Zero disables the collapse.
+	TokenMax int
+
 	// The memory of the chain.
 	Memory schema.Memory
 
@@ -38,6 +57,12 @@ type MapReduceDocuments struct {
 
 	// Wether or not to add the intermediate steps to the output.
 	ReturnIntermediateSteps bool
+
+	// Checkpoints stores the result of the LLMChain for each document as it
+	// finishes, if set. A call failing, e.g. at the 800th of 1000 documents,
+	// resumes from the results stored when called again with the same
+	// documents, and the results stored can be inspected while it runs.
+	Checkpoints CheckpointStore
 }
 
 var _ Chain = MapReduceDocuments{}
@@ -52,6 +77,7 @@ func NewMapReduceDocuments(llmChain *LLMChain, reduceChain Chain) MapReduceDocum
 		LLMChainInputVariableName:  _combineDocumentsDefaultDocumentVariableName,
 		MaxNumberOfConcurrent:      _defaultApplyMaxNumberWorkers,
 		InputKey:                   _combineDocumentsDefaultInputKey,
+		TokenMax:                   _mapReduceDefaultTokenMax,
 	}
 }
 
@@ -64,7 +90,8 @@ func (c MapReduceDocuments) Call(ctx context.Context, values map[string]any, opt
 	}
 
 	// Execute the chain with each of the documents asynchronously.
-	mapResults, err := Apply(ctx, c.LLMChain, c.getApplyInputs(values, docs), c.MaxNumberOfConcurrent, options...)
+	// Only the reduce chain streams to the caller.
+	mapResults, err := c.mapDocuments(ctx, c.getApplyInputs(values, docs), withoutStreaming(options)...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,10 +102,81 @@ func (c MapReduceDocuments) Call(ctx context.Context, values map[string]any, opt
 		return nil, err
 	}
 
+	// Collapse the map results until they fit in the context of the reduce chain.
+	documentInputVariable := c.getInputVariable(c.ReduceDocumentVariableName, c.ReduceChain.GetInputKeys())
+	resultDocs, _ := reduceInputs[documentInputVariable].([]schema.Document)
+	resultDocs, err = c.collapse(ctx, resultDocs, values, options...)
+	if err != nil {
+		return nil, err
+	}
+	reduceInputs[documentInputVariable] = resultDocs
+
 	result, err := Call(ctx, c.ReduceChain, reduceInputs, options...)
 	return c.maybeAddIntermediateSteps(result, mapResults), err
 }
 
+// mapDocuments calls the LLMChain with the inputs of the documents. With
+// checkpoints, the results stored are reused and the others are stored as the
+// calls finish, the calls failing not stopping the others.
+func (c MapReduceDocuments) mapDocuments(
+	ctx context.Context,
+	inputs []map[string]any,
+	options ...ChainCallOption,
+) ([]map[string]any, error) {
+	if c.Checkpoints == nil {
+		return Apply(ctx, c.LLMChain, inputs, c.MaxNumberOfConcurrent, options...)
+	}
+
+	results := make([]map[string]any, len(inputs))
+	keys := make([]string, len(inputs))
+	var missing []int
+	for i, input := range inputs {
+		key, err := checkpointKey(c.LLMChain, input)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+		result, ok, err := c.Checkpoints.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		results[i] = map[string]any{c.LLMChain.OutputKey: result}
+	}
+
+	missingInputs := make([]map[string]any, 0, len(missing))
+	for _, i := range missing {
+		missingInputs = append(missingInputs, inputs[i])
+	}
+	var errs []error
+	Batch(ctx, c.LLMChain, missingInputs, append(options[:len(options):len(options)],
+		WithMaxConcurrency(c.MaxNumberOfConcurrent),
+		WithBatchResultFunc(func(ctx context.Context, j int, result BatchResult) {
+			if result.Err != nil {
+				errs = append(errs, result.Err)
+				return
+			}
+			i := missing[j]
+			results[i] = result.Outputs
+			text, ok := result.Outputs[c.LLMChain.OutputKey].(string)
+			if !ok {
+				errs = append(errs, ErrInvalidOutputValues)
+				return
+			}
+			if err := c.Checkpoints.Put(ctx, keys[i], text); err != nil {
+				errs = append(errs, err)
+			}
+		}),
+	)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
+
 // If the LLMChain or the reduce chain only has one input variable, it will be used to place the
 // input automatically.
 func (c MapReduceDocuments) getInputVariable(givenInputName string, chainInputVariables []string) string {
@@ -135,6 +233,97 @@ func (c MapReduceDocuments) mapResultsToReduceInputs(
 	return reduceInputs, nil
 }
 
+// collapse combines groups of the documents with the collapse chain, in
+// parallel, until the documents fit in TokenMax.
+func (c MapReduceDocuments) collapse(
+	ctx context.Context,
+	docs []schema.Document,
+	inputValues map[string]any,
+	options ...ChainCallOption,
+) ([]schema.Document, error) {
+	if c.TokenMax <= 0 {
+		return docs, nil
+	}
+	collapseChain := c.CollapseChain
+	if collapseChain == nil {
+		collapseChain = c.ReduceChain
+	}
+	documentInputVariable := c.getInputVariable(c.ReduceDocumentVariableName, collapseChain.GetInputKeys())
+	outputKeys := collapseChain.GetOutputKeys()
+	if len(outputKeys) == 0 {
+		return nil, ErrInvalidOutputValues
+	}
+
+	for c.numTokens(docs...) > c.TokenMax {
+		groups, err := c.splitDocuments(docs)
+		if err != nil {
+			return nil, err
+		}
+		// Collapsing documents one by one would not converge.
+		if len(groups) == len(docs) {
+			return nil, fmt.Errorf("%w: %d documents of %d tokens do not fit in %d tokens",
+				ErrDocumentsTooLong, len(docs), c.numTokens(docs...), c.TokenMax)
+		}
+
+		inputs := make([]map[string]any, 0, len(groups))
+		for _, group := range groups {
+			input := c.copyInputValuesWithoutInputKey(inputValues)
+			input[documentInputVariable] = group
+			inputs = append(inputs, input)
+		}
+		results, err := Apply(ctx, collapseChain, inputs, c.MaxNumberOfConcurrent, withoutStreaming(options)...)
+		if err != nil {
+			return nil, err
+		}
+
+		docs = make([]schema.Document, 0, len(results))
+		for _, result := range results {
+			text, ok := result[outputKeys[0]].(string)
+			if !ok {
+				return nil, ErrInvalidOutputValues
+			}
+			docs = append(docs, schema.Document{PageContent: text})
+		}
+	}
+
+	return docs, nil
+}
+
+// splitDocuments splits the documents into consecutive groups of at most
+// TokenMax tokens.
+func (c MapReduceDocuments) splitDocuments(docs []schema.Document) ([][]schema.Document, error) {
+	var groups [][]schema.Document
+	var group []schema.Document
+	groupTokens := 0
+	for _, doc := range docs {
+		tokens := c.numTokens(doc)
+		if tokens > c.TokenMax {
+			return nil, fmt.Errorf("%w: a document of %d tokens does not fit in %d tokens",
+				ErrDocumentsTooLong, tokens, c.TokenMax)
+		}
+		if len(group) > 0 && groupTokens+tokens > c.TokenMax {
+			groups = append(groups, group)
+			group, groupTokens = nil, 0
+		}
+		group = append(group, doc)
+		groupTokens += tokens
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// numTokens returns the number of tokens of the contents of the documents.
+func (c MapReduceDocuments) numTokens(docs ...schema.Document) int {
+	tokens := 0
+	for _, doc := range docs {
+		tokens += c.LLMChain.LLM.GetNumTokens(doc.PageContent)
+	}
+	return tokens
+}
+
 func (c MapReduceDocuments) copyInputValuesWithoutInputKey(inputValues map[string]any) map[string]any {
 	inputValuesCopy := make(map[string]any)
 	maps.Copy(inputValuesCopy, inputValues)
@@ -158,6 +347,15 @@ func (c MapReduceDocuments) GetInputKeys() []string {
 		inputKeys[key] = true
 	}
 
+	if c.CollapseChain != nil {
+		for _, key := range c.CollapseChain.GetInputKeys() {
+			if key == c.ReduceDocumentVariableName {
+				continue
+			}
+			inputKeys[key] = true
+		}
+	}
+
 	return maps.Keys(inputKeys)
 }
 
@@ -56,3 +56,70 @@ func TestMapReduce(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "foo\n\nboo\n\nzoo\n\ndoo", result)
 }
+
+func TestMapReduceCollapse(t *testing.T) {
+	t.Parallel()
+
+	c := NewMapReduceDocuments(
+		NewLLMChain(
+			&testLanguageModel{},
+			prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+		),
+		NewStuffDocuments(
+			NewLLMChain(
+				&testLanguageModel{},
+				prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+			),
+		),
+	)
+	c.CollapseChain = NewStuffDocuments(
+		NewLLMChain(
+			&testLanguageModel{expResult: "sum"},
+			prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+		),
+	)
+	c.TokenMax = 8
+
+	docs := []schema.Document{
+		{PageContent: "foo"},
+		{PageContent: "boo"},
+		{PageContent: "zoo"},
+		{PageContent: "doo"},
+	}
+	result, err := Run(context.Background(), c, docs)
+	require.NoError(t, err)
+	require.Equal(t, "sum\n\nsum", result)
+
+	c.TokenMax = 2
+	_, err = Run(context.Background(), c, docs)
+	require.ErrorIs(t, err, ErrDocumentsTooLong)
+}
+
+func TestMapReduceStreaming(t *testing.T) {
+	t.Parallel()
+
+	c := NewMapReduceDocuments(
+		NewLLMChain(
+			&testLanguageModel{},
+			prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+		),
+		NewStuffDocuments(
+			NewLLMChain(
+				&testLanguageModel{expResult: "summary"},
+				prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+			),
+		),
+	)
+
+	// Only the reduce chain streams to the caller.
+	var chunks []string
+	_, err := Run(context.Background(), c, []schema.Document{
+		{PageContent: "foo"},
+		{PageContent: "boo"},
+	}, WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"summary"}, chunks)
+}
@@ -2,7 +2,9 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 
@@ -18,6 +20,19 @@ const (
 	_mapRerankDocumentsDefaultAnswerKey        = "answer"
 )
 
+var (
+	// ErrNoScoredAnswer is returned when none of the answers of the map rerank
+	// documents chain has a valid score.
+	ErrNoScoredAnswer = errors.New("no answer with a valid score")
+
+	// _mapRerankScoreRegex matches the number of a score, e.g. "85" in "85/100".
+	_mapRerankScoreRegex = regexp.MustCompile(`[-+]?\d+(?:\.\d+)?`)
+)
+
+// MapRerankDocuments is a chain combining documents by answering against each
+// document independently, the answers being scored by the model, and
+// returning the answer with the highest score. It suits the questions answered
+// by one of the documents only.
 type MapRerankDocuments struct {
 	// Chain used to rerank the documents.
 	LLMChain *LLMChain
@@ -45,13 +60,17 @@ type MapRerankDocuments struct {
 
 	// When true, the intermediate steps of the map rerank are returned.
 	ReturnIntermediateSteps bool
+
+	// MetadataKeys are the keys of the metadata of the document of the answer
+	// returned as outputs, e.g. "source".
+	MetadataKeys []string
 }
 
 var _ Chain = MapRerankDocuments{}
 
 // NewMapRerankDocuments creates a new map rerank documents chain.
 func NewMapRerankDocuments(mapRerankLLMChain *LLMChain) *MapRerankDocuments {
-	mapRerankRE := `\s*(?P<answer>.*?)\nScore: (?P<score>.*)`
+	mapRerankRE := `(?s)\s*(?P<answer>.*?)\s*\n\s*Score:[ \t]*(?P<score>[^\n]*)`
 	mapRerankLLMChain.OutputParser = outputparser.NewRegexParser(mapRerankRE)
 
 	return &MapRerankDocuments{
@@ -80,38 +99,51 @@ func (c MapRerankDocuments) Call(ctx context.Context, values map[string]any, opt
 	}
 
 	applyInputs := c.getApplyInputs(values, docs)
-	mapResults, err := Apply(ctx, c.LLMChain, applyInputs, c.MaxConcurrentWorkers, options...)
+	// The answers are scored, not streamed.
+	mapResults, err := Apply(ctx, c.LLMChain, applyInputs, c.MaxConcurrentWorkers, withoutStreaming(options)...)
 	if err != nil {
 		return nil, err
 	}
 
-	// create a slice of outputs to return after sorting the ranks.
-	outputs := make([]map[string]any, len(mapResults))
-
+	answers := make([]rankedAnswer, len(mapResults))
 	for i, res := range mapResults {
-		rankedAnswer, ok := res[c.LLMChain.OutputKey].(map[string]string)
+		result, ok := res[c.LLMChain.OutputKey].(map[string]string)
 		if !ok {
 			return nil, ErrInvalidOutputValues
 		}
 
-		outputs[i] = c.parseMapResults(rankedAnswer)
+		answers[i] = rankedAnswer{output: c.parseMapResults(result), doc: docs[i]}
+		answers[i].score, answers[i].scored = parseScore(result[c.RankKey])
 	}
 
-	sort.Slice(outputs, func(i, j int) bool {
-		curr, err := strconv.Atoi(outputs[i][c.RankKey].(string))
-		if err != nil {
-			return false
+	// The answers without valid scores are ranked last, and the answers with
+	// the same scores in the order of their documents.
+	sort.SliceStable(answers, func(i, j int) bool {
+		if answers[i].scored != answers[j].scored {
+			return answers[i].scored
 		}
+		return answers[i].score > answers[j].score
+	})
+	if !answers[0].scored {
+		return nil, ErrNoScoredAnswer
+	}
 
-		compare, err := strconv.Atoi(outputs[j][c.RankKey].(string))
-		if err != nil {
-			return true
-		}
+	return c.formatOutputs(answers), nil
+}
 
-		return curr > compare
-	})
+// rankedAnswer is the answer of a document with its score.
+type rankedAnswer struct {
+	output map[string]any
+	doc    schema.Document
+	score  float64
+	scored bool
+}
 
-	return c.formatOutputs(outputs), nil
+// parseScore returns the first number of the score given by the model, e.g.
+// 85 for "85/100".
+func parseScore(score string) (float64, bool) {
+	f, err := strconv.ParseFloat(_mapRerankScoreRegex.FindString(score), 64)
+	return f, err == nil
 }
 
 // getInputVariable returns the input variable name to use for the LLM chain.
@@ -155,21 +187,29 @@ func (c MapRerankDocuments) parseMapResults(inputs map[string]string) map[string
 	return outputs
 }
 
-// formatOutputs returns the first output and the intermediate steps, if enabled.
-func (c MapRerankDocuments) formatOutputs(outputs []map[string]any) map[string]any {
-	if len(outputs) == 0 {
+// formatOutputs returns the first answer with the metadata of its document and
+// the intermediate steps, if enabled.
+func (c MapRerankDocuments) formatOutputs(answers []rankedAnswer) map[string]any {
+	if len(answers) == 0 {
 		return nil
 	}
 
 	formattedOutputs := make(map[string]any)
-	answerOutput := maps.Clone(outputs[0])
+	answerOutput := maps.Clone(answers[0].output)
 
 	formattedOutputs[c.LLMChain.OutputKey] = answerOutput[c.AnswerKey]
+	for _, key := range c.MetadataKeys {
+		formattedOutputs[key] = answers[0].doc.Metadata[key]
+	}
 
 	if !c.ReturnIntermediateSteps {
 		return formattedOutputs
 	}
 
+	outputs := make([]map[string]any, 0, len(answers))
+	for _, answer := range answers {
+		outputs = append(outputs, answer.output)
+	}
 	formattedOutputs[_intermediateStepsOutputKey] = outputs
 
 	return formattedOutputs
@@ -190,7 +230,7 @@ func (c MapRerankDocuments) GetInputKeys() []string {
 
 // GetOutputKeys returns the output keys for the MapRerankDocuments chain.
 func (c MapRerankDocuments) GetOutputKeys() []string {
-	outputKeys := c.LLMChain.GetOutputKeys()
+	outputKeys := append(c.LLMChain.GetOutputKeys(), c.MetadataKeys...)
 
 	if c.ReturnIntermediateSteps {
 		outputKeys = append(outputKeys, _intermediateStepsOutputKey)
@@ -67,3 +67,36 @@ func TestMapRerankDocumentsCall(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestMapRerankDocumentsRanking(t *testing.T) {
+	t.Parallel()
+
+	mapRerankLLMChain := NewLLMChain(
+		&testLanguageModel{},
+		prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+	)
+	mapRerankDocumentsChain := NewMapRerankDocuments(mapRerankLLMChain)
+	mapRerankDocumentsChain.MetadataKeys = []string{"source"}
+	mapRerankDocumentsChain.ReturnIntermediateSteps = true
+
+	docs := []schema.Document{
+		{PageContent: "No answer\nScore: unknown", Metadata: map[string]any{"source": "a.txt"}},
+		{PageContent: "Leo is a lion.\nHe lives in Africa.\nScore: 87.5/100", Metadata: map[string]any{"source": "b.txt"}},
+		{PageContent: "Leo is a cat.\nScore: 87.5", Metadata: map[string]any{"source": "c.txt"}},
+		{PageContent: "Leo is a dog.\nScore: 9", Metadata: map[string]any{"source": "d.txt"}},
+	}
+	result, err := Call(context.Background(), mapRerankDocumentsChain, map[string]any{"input_documents": docs})
+	require.NoError(t, err)
+
+	// The ties are broken by the order of the documents.
+	require.Equal(t, "Leo is a lion.\nHe lives in Africa.", result["text"])
+	require.Equal(t, "b.txt", result["source"])
+	steps, ok := result["intermediateSteps"].([]map[string]any)
+	require.True(t, ok)
+	scores := make([]any, 0, len(steps))
+	for _, step := range steps {
+		scores = append(scores, step["score"])
+	}
+	require.Equal(t, []any{"87.5/100", "87.5", "9", "unknown"}, scores)
+	require.ElementsMatch(t, []string{"text", "source", "intermediateSteps"}, mapRerankDocumentsChain.GetOutputKeys())
+}
@@ -0,0 +1,603 @@
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// _maxMathExpressionLength bounds the length of the expressions evaluated.
+	_maxMathExpressionLength = 1024
+	// _maxMathExpressionDepth bounds the nesting of the expressions evaluated.
+	_maxMathExpressionDepth = 64
+	// _maxExactBits bounds the size of the exact values, beyond which the
+	// values are approximated by floats.
+	_maxExactBits = 1 << 16
+	// _mathDecimals is the number of decimals of the values with infinite
+	// decimal expansions, e.g. 1/3.
+	_mathDecimals = 15
+)
+
+var (
+	// ErrInvalidMathExpression is returned when an expression can not be
+	// parsed.
+	ErrInvalidMathExpression = errors.New("invalid math expression")
+	// ErrMathEvaluation is returned when an expression can not be evaluated,
+	// e.g. when dividing by zero.
+	ErrMathEvaluation = errors.New("math evaluation error")
+)
+
+// EvaluateMathExpression evaluates an arithmetic expression and returns its
+// value. The expressions are made of numbers, the operators +, -, *, /, //
+// (floor division), % (modulo), ** and ^ (both power), parentheses, the
+// constants pi and e and the functions abs, ceil, floor, round, min, max,
+// sqrt, pow, exp, log (natural or of a base), ln, log2, log10, sin, cos,
+// tan, asin, acos and atan.
+//
+// Nothing but arithmetic is evaluated. The rational values are computed
+// exactly, e.g. 0.1 + 0.2 is 0.3, and only the irrational functions and the
+// fractional powers are approximated.
+func EvaluateMathExpression(expression string) (string, error) {
+	if len(expression) > _maxMathExpressionLength {
+		return "", fmt.Errorf("%w: expression longer than %d characters",
+			ErrInvalidMathExpression, _maxMathExpressionLength)
+	}
+	tokens, err := tokenizeMath(expression)
+	if err != nil {
+		return "", err
+	}
+	p := &mathParser{tokens: tokens}
+	v, err := p.parseExpression(0)
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("%w: unexpected %q", ErrInvalidMathExpression, p.tokens[p.pos].text)
+	}
+	return v.String(), nil
+}
+
+// mathValue is an exact rational value, or a float if it is approximated.
+type mathValue struct {
+	rat *big.Rat
+	f   float64
+}
+
+func ratValue(r *big.Rat) (mathValue, error) {
+	if r.Num().BitLen()+r.Denom().BitLen() > _maxExactBits {
+		f, _ := r.Float64()
+		return floatValue(f)
+	}
+	return mathValue{rat: r}, nil
+}
+
+func floatValue(f float64) (mathValue, error) {
+	if math.IsNaN(f) {
+		return mathValue{}, fmt.Errorf("%w: undefined result", ErrMathEvaluation)
+	}
+	if math.IsInf(f, 0) {
+		return mathValue{}, fmt.Errorf("%w: result out of range", ErrMathEvaluation)
+	}
+	return mathValue{f: f}, nil
+}
+
+func (v mathValue) exact() bool { return v.rat != nil }
+
+func (v mathValue) float() float64 {
+	if v.rat != nil {
+		f, _ := v.rat.Float64()
+		return f
+	}
+	return v.f
+}
+
+func (v mathValue) sign() int {
+	if v.rat != nil {
+		return v.rat.Sign()
+	}
+	switch {
+	case v.f > 0:
+		return 1
+	case v.f < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// String returns the integers without decimals and the other values with
+// their decimals, exactly if their expansion is finite.
+func (v mathValue) String() string {
+	if v.rat == nil {
+		if math.Abs(v.f) >= 1e21 {
+			return strconv.FormatFloat(v.f, 'g', -1, 64)
+		}
+		return strconv.FormatFloat(v.f, 'f', -1, 64)
+	}
+	if v.rat.IsInt() {
+		return v.rat.Num().String()
+	}
+	if decimals, ok := finiteDecimals(v.rat.Denom()); ok {
+		return v.rat.FloatString(decimals)
+	}
+	s := strings.TrimRight(v.rat.FloatString(_mathDecimals), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// finiteDecimals returns the number of decimals of the fractions with the
+// denominator if their expansion is finite, when the denominator has no other
+// prime factors than 2 and 5.
+func finiteDecimals(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	twos, fives := 0, 0
+	two, five, m := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for d.Cmp(big.NewInt(1)) != 0 {
+		switch {
+		case m.Mod(d, two).Sign() == 0:
+			d.Quo(d, two)
+			twos++
+		case m.Mod(d, five).Sign() == 0:
+			d.Quo(d, five)
+			fives++
+		default:
+			return 0, false
+		}
+	}
+	if twos > fives {
+		return twos, true
+	}
+	return fives, true
+}
+
+type mathTokenKind int
+
+const (
+	mathNumber mathTokenKind = iota
+	mathIdent
+	mathOperator
+)
+
+type mathToken struct {
+	kind mathTokenKind
+	text string
+}
+
+// tokenizeMath splits the expression into numbers, identifiers and operators.
+func tokenizeMath(expression string) ([]mathToken, error) {
+	var tokens []mathToken
+	for i := 0; i < len(expression); {
+		c := rune(expression[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(expression) && (isDigit(expression[j]) || expression[j] == '.' || expression[j] == '_') {
+				j++
+			}
+			// Exponent of the scientific notation.
+			if j < len(expression) && (expression[j] == 'e' || expression[j] == 'E') {
+				k := j + 1
+				if k < len(expression) && (expression[k] == '+' || expression[k] == '-') {
+					k++
+				}
+				if k < len(expression) && isDigit(expression[k]) {
+					for k < len(expression) && isDigit(expression[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			tokens = append(tokens, mathToken{kind: mathNumber, text: expression[i:j]})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(expression) && (isDigit(expression[j]) || expression[j] == '_' ||
+				unicode.IsLetter(rune(expression[j]))) {
+				j++
+			}
+			tokens = append(tokens, mathToken{kind: mathIdent, text: strings.ToLower(expression[i:j])})
+			i = j
+		case strings.HasPrefix(expression[i:], "**") || strings.HasPrefix(expression[i:], "//"):
+			tokens = append(tokens, mathToken{kind: mathOperator, text: expression[i : i+2]})
+			i += 2
+		case strings.ContainsRune("+-*/%^(),", c):
+			tokens = append(tokens, mathToken{kind: mathOperator, text: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidMathExpression, expression[i])
+		}
+	}
+	return tokens, nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// parseNumber parses a number, with its digits possibly separated by
+// underscores.
+func parseNumber(text string) (mathValue, error) {
+	text = strings.ReplaceAll(text, "_", "")
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exponent, err := strconv.Atoi(text[i+1:])
+		if err != nil || exponent > 1000 || exponent < -1000 {
+			return mathValue{}, fmt.Errorf("%w: number %q", ErrInvalidMathExpression, text)
+		}
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return mathValue{}, fmt.Errorf("%w: number %q", ErrInvalidMathExpression, text)
+	}
+	return ratValue(r)
+}
+
+// mathParser is a recursive descent parser evaluating the expressions with
+// the precedences of Python: the powers first, right-associative, then the
+// signs, the products and the sums.
+type mathParser struct {
+	tokens []mathToken
+	pos    int
+}
+
+func (p *mathParser) peek(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == mathOperator && p.tokens[p.pos].text == text
+}
+
+func (p *mathParser) expect(text string) error {
+	if !p.peek(text) {
+		if p.pos < len(p.tokens) {
+			return fmt.Errorf("%w: expected %q, got %q", ErrInvalidMathExpression, text, p.tokens[p.pos].text)
+		}
+		return fmt.Errorf("%w: expected %q at the end", ErrInvalidMathExpression, text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *mathParser) parseExpression(depth int) (mathValue, error) {
+	if depth > _maxMathExpressionDepth {
+		return mathValue{}, fmt.Errorf("%w: expression nested too deeply", ErrInvalidMathExpression)
+	}
+	v, err := p.parseTerm(depth)
+	if err != nil {
+		return mathValue{}, err
+	}
+	for p.peek("+") || p.peek("-") {
+		op := p.tokens[p.pos].text
+		p.pos++
+		w, err := p.parseTerm(depth)
+		if err != nil {
+			return mathValue{}, err
+		}
+		if v, err = applyMathOperator(op, v, w); err != nil {
+			return mathValue{}, err
+		}
+	}
+	return v, nil
+}
+
+func (p *mathParser) parseTerm(depth int) (mathValue, error) {
+	v, err := p.parseUnary(depth)
+	if err != nil {
+		return mathValue{}, err
+	}
+	for p.peek("*") || p.peek("/") || p.peek("//") || p.peek("%") {
+		op := p.tokens[p.pos].text
+		p.pos++
+		w, err := p.parseUnary(depth)
+		if err != nil {
+			return mathValue{}, err
+		}
+		if v, err = applyMathOperator(op, v, w); err != nil {
+			return mathValue{}, err
+		}
+	}
+	return v, nil
+}
+
+func (p *mathParser) parseUnary(depth int) (mathValue, error) {
+	if depth > _maxMathExpressionDepth {
+		return mathValue{}, fmt.Errorf("%w: expression nested too deeply", ErrInvalidMathExpression)
+	}
+	if p.peek("+") || p.peek("-") {
+		op := p.tokens[p.pos].text
+		p.pos++
+		v, err := p.parseUnary(depth + 1)
+		if err != nil || op == "+" {
+			return v, err
+		}
+		return negateMath(v), nil
+	}
+	return p.parsePower(depth)
+}
+
+func (p *mathParser) parsePower(depth int) (mathValue, error) {
+	v, err := p.parsePrimary(depth)
+	if err != nil {
+		return mathValue{}, err
+	}
+	if p.peek("**") || p.peek("^") {
+		p.pos++
+		// The exponent may be signed, e.g. 2 ** -1.
+		w, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return mathValue{}, err
+		}
+		return powMath(v, w)
+	}
+	return v, nil
+}
+
+func (p *mathParser) parsePrimary(depth int) (mathValue, error) {
+	if p.pos >= len(p.tokens) {
+		return mathValue{}, fmt.Errorf("%w: unexpected end", ErrInvalidMathExpression)
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case token.kind == mathNumber:
+		return parseNumber(token.text)
+	case token.kind == mathIdent && p.peek("("):
+		p.pos++
+		var args []mathValue
+		for !p.peek(")") {
+			if len(args) > 0 {
+				if err := p.expect(","); err != nil {
+					return mathValue{}, err
+				}
+			}
+			arg, err := p.parseExpression(depth + 1)
+			if err != nil {
+				return mathValue{}, err
+			}
+			args = append(args, arg)
+		}
+		p.pos++
+		return callMathFunction(token.text, args)
+	case token.kind == mathIdent:
+		switch token.text {
+		case "pi":
+			return floatValue(math.Pi)
+		case "e":
+			return floatValue(math.E)
+		default:
+			return mathValue{}, fmt.Errorf("%w: unknown name %q", ErrInvalidMathExpression, token.text)
+		}
+	case token.text == "(":
+		v, err := p.parseExpression(depth + 1)
+		if err != nil {
+			return mathValue{}, err
+		}
+		return v, p.expect(")")
+	default:
+		return mathValue{}, fmt.Errorf("%w: unexpected %q", ErrInvalidMathExpression, token.text)
+	}
+}
+
+func negateMath(v mathValue) mathValue {
+	if v.exact() {
+		return mathValue{rat: new(big.Rat).Neg(v.rat)}
+	}
+	return mathValue{f: -v.f}
+}
+
+func applyMathOperator(op string, v, w mathValue) (mathValue, error) {
+	if (op == "/" || op == "//" || op == "%") && w.sign() == 0 {
+		return mathValue{}, fmt.Errorf("%w: division by zero", ErrMathEvaluation)
+	}
+	if v.exact() && w.exact() {
+		r := new(big.Rat)
+		switch op {
+		case "+":
+			r.Add(v.rat, w.rat)
+		case "-":
+			r.Sub(v.rat, w.rat)
+		case "*":
+			r.Mul(v.rat, w.rat)
+		case "/":
+			r.Quo(v.rat, w.rat)
+		case "//":
+			r.SetInt(floorRat(r.Quo(v.rat, w.rat)))
+		case "%":
+			// The modulo has the sign of the divisor, as in Python.
+			r.Sub(v.rat, r.Mul(w.rat, r.SetInt(floorRat(r.Quo(v.rat, w.rat)))))
+		}
+		return ratValue(r)
+	}
+
+	a, b := v.float(), w.float()
+	switch op {
+	case "+":
+		return floatValue(a + b)
+	case "-":
+		return floatValue(a - b)
+	case "*":
+		return floatValue(a * b)
+	case "/":
+		return floatValue(a / b)
+	case "//":
+		return floatValue(math.Floor(a / b))
+	default:
+		return floatValue(a - b*math.Floor(a/b))
+	}
+}
+
+func floorRat(r *big.Rat) *big.Int {
+	// The Euclidean division of big.Int rounds towards negative infinity for
+	// the positive denominators of big.Rat.
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+func ceilRat(r *big.Rat) *big.Int {
+	q := floorRat(r)
+	if !r.IsInt() {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// powMath raises v to the power w, exactly for the exact values raised to
+// integer powers.
+func powMath(v, w mathValue) (mathValue, error) {
+	if v.exact() && w.exact() && w.rat.IsInt() && w.rat.Num().IsInt64() {
+		n := w.rat.Num().Int64()
+		if n < 0 && v.rat.Sign() == 0 {
+			return mathValue{}, fmt.Errorf("%w: division by zero", ErrMathEvaluation)
+		}
+		abs := n
+		if abs < 0 {
+			abs = -abs
+		}
+		bits := int64(v.rat.Num().BitLen() + v.rat.Denom().BitLen())
+		if abs <= _maxExactBits && bits*abs <= _maxExactBits {
+			e := big.NewInt(abs)
+			r := new(big.Rat).SetFrac(
+				new(big.Int).Exp(v.rat.Num(), e, nil),
+				new(big.Int).Exp(v.rat.Denom(), e, nil),
+			)
+			if n < 0 {
+				r.Inv(r)
+			}
+			return ratValue(r)
+		}
+	}
+	return floatValue(math.Pow(v.float(), w.float()))
+}
+
+// callMathFunction calls the function of the name with the arguments.
+func callMathFunction(name string, args []mathValue) (mathValue, error) {
+	arity := map[string]int{
+		"abs": 1, "ceil": 1, "floor": 1, "sqrt": 1, "exp": 1, "ln": 1, "log2": 1, "log10": 1,
+		"sin": 1, "cos": 1, "tan": 1, "asin": 1, "acos": 1, "atan": 1, "pow": 2,
+	}
+	if n, ok := arity[name]; ok && len(args) != n {
+		return mathValue{}, fmt.Errorf("%w: %s takes %d arguments, got %d", ErrInvalidMathExpression, name, n, len(args))
+	}
+
+	switch name {
+	case "abs":
+		if args[0].sign() < 0 {
+			return negateMath(args[0]), nil
+		}
+		return args[0], nil
+	case "ceil", "floor":
+		if args[0].exact() {
+			if name == "ceil" {
+				return ratValue(new(big.Rat).SetInt(ceilRat(args[0].rat)))
+			}
+			return ratValue(new(big.Rat).SetInt(floorRat(args[0].rat)))
+		}
+		if name == "ceil" {
+			return floatValue(math.Ceil(args[0].f))
+		}
+		return floatValue(math.Floor(args[0].f))
+	case "round":
+		return roundMath(args)
+	case "min", "max":
+		return extremumMath(name, args)
+	case "pow":
+		return powMath(args[0], args[1])
+	case "sqrt":
+		return sqrtMath(args[0])
+	case "log":
+		return logMath(args)
+	}
+
+	functions := map[string]func(float64) float64{
+		"exp": math.Exp, "ln": math.Log, "log2": math.Log2, "log10": math.Log10,
+		"sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+		"asin": math.Asin, "acos": math.Acos, "atan": math.Atan,
+	}
+	f, ok := functions[name]
+	if !ok {
+		return mathValue{}, fmt.Errorf("%w: unknown function %q", ErrInvalidMathExpression, name)
+	}
+	return floatValue(f(args[0].float()))
+}
+
+// roundMath rounds half away from zero, to the number of decimals of the
+// optional second argument.
+func roundMath(args []mathValue) (mathValue, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return mathValue{}, fmt.Errorf("%w: round takes 1 or 2 arguments, got %d", ErrInvalidMathExpression, len(args))
+	}
+	decimals := int64(0)
+	if len(args) == 2 {
+		if !args[1].exact() || !args[1].rat.IsInt() || !args[1].rat.Num().IsInt64() ||
+			args[1].rat.Num().Int64() < 0 || args[1].rat.Num().Int64() > 100 {
+			return mathValue{}, fmt.Errorf("%w: the decimals of round must be an integer between 0 and 100",
+				ErrMathEvaluation)
+		}
+		decimals = args[1].rat.Num().Int64()
+	}
+	if !args[0].exact() {
+		scale := math.Pow(10, float64(decimals))
+		return floatValue(math.Round(args[0].f*scale) / scale)
+	}
+
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+	r := new(big.Rat).Mul(new(big.Rat).Abs(args[0].rat), scale)
+	r.Add(r, big.NewRat(1, 2))
+	r.SetInt(floorRat(r))
+	r.Quo(r, scale)
+	if args[0].rat.Sign() < 0 {
+		r.Neg(r)
+	}
+	return ratValue(r)
+}
+
+func extremumMath(name string, args []mathValue) (mathValue, error) {
+	if len(args) == 0 {
+		return mathValue{}, fmt.Errorf("%w: %s takes at least 1 argument", ErrInvalidMathExpression, name)
+	}
+	result := args[0]
+	for _, arg := range args[1:] {
+		var cmp int
+		if result.exact() && arg.exact() {
+			cmp = arg.rat.Cmp(result.rat)
+		} else {
+			cmp = big.NewFloat(arg.float()).Cmp(big.NewFloat(result.float()))
+		}
+		if (name == "min" && cmp < 0) || (name == "max" && cmp > 0) {
+			result = arg
+		}
+	}
+	return result, nil
+}
+
+// sqrtMath returns the square root of v, exactly for the squares of rational
+// values.
+func sqrtMath(v mathValue) (mathValue, error) {
+	if v.sign() < 0 {
+		return mathValue{}, fmt.Errorf("%w: square root of a negative number", ErrMathEvaluation)
+	}
+	if v.exact() {
+		num, denom := new(big.Int).Sqrt(v.rat.Num()), new(big.Int).Sqrt(v.rat.Denom())
+		if new(big.Int).Mul(num, num).Cmp(v.rat.Num()) == 0 && new(big.Int).Mul(denom, denom).Cmp(v.rat.Denom()) == 0 {
+			return ratValue(new(big.Rat).SetFrac(num, denom))
+		}
+	}
+	return floatValue(math.Sqrt(v.float()))
+}
+
+// logMath returns the natural logarithm of the first argument, or its
+// logarithm in the base of the second argument.
+func logMath(args []mathValue) (mathValue, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return mathValue{}, fmt.Errorf("%w: log takes 1 or 2 arguments, got %d", ErrInvalidMathExpression, len(args))
+	}
+	if args[0].sign() <= 0 {
+		return mathValue{}, fmt.Errorf("%w: logarithm of a non-positive number", ErrMathEvaluation)
+	}
+	if len(args) == 1 {
+		return floatValue(math.Log(args[0].float()))
+	}
+	if args[1].sign() <= 0 || args[1].float() == 1 {
+		return mathValue{}, fmt.Errorf("%w: invalid logarithm base", ErrMathEvaluation)
+	}
+	return floatValue(math.Log(args[0].float()) / math.Log(args[1].float()))
+}
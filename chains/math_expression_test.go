@@ -0,0 +1,51 @@
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateMathExpression(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expression string
+		expected   string
+		err        error
+	}{
+		{expression: "37593 * 67", expected: "2518731"},
+		{expression: "0.1 + 0.2", expected: "0.3"},
+		{expression: "1 / 3", expected: "0.333333333333333"},
+		{expression: "2 ** -2", expected: "0.25"},
+		{expression: "-2 ** 2", expected: "-4"},
+		{expression: "2 ^ 10", expected: "1024"},
+		{expression: "10 ** 30", expected: "1000000000000000000000000000000"},
+		{expression: "7 // -2", expected: "-4"},
+		{expression: "-7 % 3", expected: "2"},
+		{expression: "1e3 + 1_000", expected: "2000"},
+		{expression: "sqrt(16 / 9) * 3", expected: "4"},
+		{expression: "sqrt(2)", expected: "1.4142135623730951"},
+		{expression: "round(-1.2345, 2) + max(1, 3.5, 2)", expected: "2.27"},
+		{expression: "floor(-2.5) + ceil(2.1) + abs(-3)", expected: "3"},
+		{expression: "1024 ** 0.43", expected: "19.69831061351866"},
+		{expression: "sin(pi / 2)", expected: "1"},
+		{expression: "1 / 0", err: ErrMathEvaluation},
+		{expression: "sqrt(-1)", err: ErrMathEvaluation},
+		{expression: "2 ** 100000", err: ErrMathEvaluation},
+		{expression: "(1 + 2", err: ErrInvalidMathExpression},
+		{expression: "1 2", err: ErrInvalidMathExpression},
+		{expression: "x + 1", err: ErrInvalidMathExpression},
+		{expression: "1e400000", err: ErrInvalidMathExpression},
+		{expression: "__import__('os').system('ls')", err: ErrInvalidMathExpression},
+	}
+	for _, c := range cases {
+		result, err := EvaluateMathExpression(c.expression)
+		if c.err != nil {
+			require.ErrorIs(t, err, c.err, c.expression)
+			continue
+		}
+		require.NoError(t, err, c.expression)
+		require.Equal(t, c.expected, result, c.expression)
+	}
+}
@@ -0,0 +1,341 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms/moderation"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ModerationAction is the action of the moderation chain on flagged texts.
+type ModerationAction int
+
+const (
+	// ModerationReject fails the call with moderation.ErrFlagged.
+	ModerationReject ModerationAction = iota
+	// ModerationRedact replaces the flagged texts with the redaction.
+	ModerationRedact
+	// ModerationAllow passes the flagged texts, the texts are not moderated.
+	ModerationAllow
+	// ModerationAnnotate passes the flagged texts and returns their
+	// []ModerationAnnotation under the "moderation" output key.
+	ModerationAnnotate
+)
+
+// DefaultRedaction replaces the flagged texts redacted by the moderation chain.
+const DefaultRedaction = "[redacted]"
+
+const _moderationAnnotationsOutputKey = "moderation"
+
+// ModerationPolicy is the action of the moderation chain by category of
+// harmful content, see the categories of the moderation package.
+type ModerationPolicy struct {
+	// Default is the action on the flagged categories without an action,
+	// reject by default.
+	Default ModerationAction
+	// Categories are the actions by category. A text flagged in several
+	// categories gets the most severe of their actions: reject, redact,
+	// annotate then allow.
+	Categories map[string]ModerationAction
+	// Thresholds are the minimum scores flagging a text in their categories,
+	// in place of the categories flagged by the moderator.
+	Thresholds map[string]float64
+}
+
+// ModerationAnnotation is a text flagged by the moderation chain, and not
+// rejected.
+type ModerationAnnotation struct {
+	// Kind is "input" or "output".
+	Kind string
+	// Key is the input or output key of the text.
+	Key        string
+	Categories []string
+	Scores     map[string]float64
+	Action     ModerationAction
+}
+
+// Moderation is a chain moderating the string inputs of a chain before they
+// reach the model, and its string outputs before they reach the user.
+type Moderation struct {
+	Chain     Chain
+	Moderator moderation.Moderator
+	// InputAction is the action on flagged inputs, reject by default.
+	InputAction ModerationAction
+	// OutputAction is the action on flagged outputs, reject by default.
+	OutputAction ModerationAction
+	// InputPolicy is the action by category on flagged inputs, in place of
+	// the InputAction if set.
+	InputPolicy *ModerationPolicy
+	// OutputPolicy is the action by category on flagged outputs, in place of
+	// the OutputAction if set.
+	OutputPolicy *ModerationPolicy
+	// Redaction replaces the redacted texts.
+	Redaction string
+}
+
+var _ Chain = Moderation{}
+
+// ModerationOption is a function that configures a moderation chain.
+type ModerationOption func(*Moderation)
+
+// WithInputModeration sets the action on flagged inputs.
+func WithInputModeration(action ModerationAction) ModerationOption {
+	return func(c *Moderation) {
+		c.InputAction = action
+	}
+}
+
+// WithOutputModeration sets the action on flagged outputs.
+func WithOutputModeration(action ModerationAction) ModerationOption {
+	return func(c *Moderation) {
+		c.OutputAction = action
+	}
+}
+
+// WithInputPolicy sets the actions by category on flagged inputs.
+func WithInputPolicy(policy ModerationPolicy) ModerationOption {
+	return func(c *Moderation) {
+		c.InputPolicy = &policy
+	}
+}
+
+// WithOutputPolicy sets the actions by category on flagged outputs.
+func WithOutputPolicy(policy ModerationPolicy) ModerationOption {
+	return func(c *Moderation) {
+		c.OutputPolicy = &policy
+	}
+}
+
+// WithRedaction sets the text replacing the redacted texts.
+func WithRedaction(redaction string) ModerationOption {
+	return func(c *Moderation) {
+		c.Redaction = redaction
+	}
+}
+
+// NewModeration creates a chain moderating the inputs and the outputs of the
+// chain with the moderator.
+func NewModeration(chain Chain, moderator moderation.Moderator, opts ...ModerationOption) Moderation {
+	c := Moderation{
+		Chain:     chain,
+		Moderator: moderator,
+		Redaction: DefaultRedaction,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Call moderates the inputs, calls the chain and moderates its outputs. The
+// flagged texts annotated or redacted are returned under the "moderation"
+// output key if a policy annotates texts.
+func (c Moderation) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	inputs, inputAnnotations, err := c.moderate(ctx, "input", inputs, c.Chain.GetInputKeys(), c.inputPolicy())
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := c.Chain.Call(ctx, inputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	outputs, outputAnnotations, err := c.moderate(ctx, "output", outputs, c.Chain.GetOutputKeys(), c.outputPolicy())
+	if err != nil {
+		return nil, err
+	}
+	if !c.annotates() {
+		return outputs, nil
+	}
+
+	annotated := make(map[string]any, len(outputs)+1)
+	for key, value := range outputs {
+		annotated[key] = value
+	}
+	annotated[_moderationAnnotationsOutputKey] = append(
+		append([]ModerationAnnotation{}, inputAnnotations...), outputAnnotations...)
+	return annotated, nil
+}
+
+// moderate returns the values with the flagged string values of the keys
+// redacted, and the annotations of the texts flagged and not rejected, or an
+// error if the action of a text is reject.
+func (c Moderation) moderate(
+	ctx context.Context,
+	kind string,
+	values map[string]any,
+	keys []string,
+	policy ModerationPolicy,
+) (map[string]any, []ModerationAnnotation, error) {
+	if policy.allowsAll() {
+		return values, nil, nil
+	}
+	var moderatedKeys, texts []string
+	for _, key := range keys {
+		if text, ok := values[key].(string); ok && text != "" {
+			moderatedKeys = append(moderatedKeys, key)
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return values, nil, nil
+	}
+	results, err := c.Moderator.Moderate(ctx, texts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("moderate %s: %w", kind, err)
+	}
+	if len(results) != len(texts) {
+		return nil, nil, fmt.Errorf("moderate %s: got %d results for %d texts", kind, len(results), len(texts))
+	}
+
+	var redacted map[string]any
+	var annotations []ModerationAnnotation
+	for i, result := range results {
+		categories, flagged := policy.flaggedCategories(result)
+		if !flagged {
+			continue
+		}
+		action := policy.action(categories)
+		switch action {
+		case ModerationAllow:
+			continue
+		case ModerationReject:
+			return nil, nil, fmt.Errorf("%w: %s %q: %s",
+				moderation.ErrFlagged, kind, moderatedKeys[i], strings.Join(categories, ", "))
+		case ModerationRedact:
+			if redacted == nil {
+				redacted = make(map[string]any, len(values))
+				for key, value := range values {
+					redacted[key] = value
+				}
+			}
+			redacted[moderatedKeys[i]] = c.Redaction
+		case ModerationAnnotate:
+		}
+		annotations = append(annotations, ModerationAnnotation{
+			Kind:       kind,
+			Key:        moderatedKeys[i],
+			Categories: categories,
+			Scores:     result.Scores,
+			Action:     action,
+		})
+	}
+	if redacted == nil {
+		return values, annotations, nil
+	}
+	return redacted, annotations, nil
+}
+
+func (c Moderation) inputPolicy() ModerationPolicy {
+	if c.InputPolicy != nil {
+		return *c.InputPolicy
+	}
+	return ModerationPolicy{Default: c.InputAction}
+}
+
+func (c Moderation) outputPolicy() ModerationPolicy {
+	if c.OutputPolicy != nil {
+		return *c.OutputPolicy
+	}
+	return ModerationPolicy{Default: c.OutputAction}
+}
+
+// annotates reports whether the policies may annotate texts.
+func (c Moderation) annotates() bool {
+	return c.inputPolicy().mayTake(ModerationAnnotate) || c.outputPolicy().mayTake(ModerationAnnotate)
+}
+
+// flaggedCategories returns the sorted categories the text is flagged in by
+// the policy, and whether it is flagged.
+func (p ModerationPolicy) flaggedCategories(result moderation.Result) ([]string, bool) {
+	if len(p.Thresholds) == 0 {
+		return result.FlaggedCategories(), result.Flagged
+	}
+
+	var categories []string
+	for category, flagged := range result.Categories {
+		if _, ok := p.Thresholds[category]; !ok && flagged {
+			categories = append(categories, category)
+		}
+	}
+	for category, threshold := range p.Thresholds {
+		if score, ok := result.Scores[category]; ok && score >= threshold {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories, len(categories) > 0
+}
+
+// action returns the most severe action of the categories, the default action
+// if the text is flagged without categories.
+func (p ModerationPolicy) action(categories []string) ModerationAction {
+	if len(categories) == 0 {
+		return p.Default
+	}
+	action := ModerationAllow
+	for _, category := range categories {
+		categoryAction, ok := p.Categories[category]
+		if !ok {
+			categoryAction = p.Default
+		}
+		if moderationSeverity(categoryAction) > moderationSeverity(action) {
+			action = categoryAction
+		}
+	}
+	return action
+}
+
+// allowsAll reports whether the policy allows all the texts, which are then
+// not moderated.
+func (p ModerationPolicy) allowsAll() bool {
+	return !p.mayTake(ModerationReject) && !p.mayTake(ModerationRedact) && !p.mayTake(ModerationAnnotate)
+}
+
+// mayTake reports whether the policy may take the action on a text.
+func (p ModerationPolicy) mayTake(action ModerationAction) bool {
+	if p.Default == action {
+		return true
+	}
+	for _, categoryAction := range p.Categories {
+		if categoryAction == action {
+			return true
+		}
+	}
+	return false
+}
+
+func moderationSeverity(action ModerationAction) int {
+	switch action {
+	case ModerationReject:
+		return 3 //nolint:gomnd
+	case ModerationRedact:
+		return 2 //nolint:gomnd
+	case ModerationAnnotate:
+		return 1
+	case ModerationAllow:
+	}
+	return 0
+}
+
+// GetMemory gets the memory of the moderated chain.
+func (c Moderation) GetMemory() schema.Memory {
+	return c.Chain.GetMemory()
+}
+
+// GetInputKeys returns the input keys of the moderated chain.
+func (c Moderation) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the moderated chain, and the
+// "moderation" key if the policies may annotate texts.
+func (c Moderation) GetOutputKeys() []string {
+	outputKeys := c.Chain.GetOutputKeys()
+	if c.annotates() {
+		outputKeys = append(outputKeys[:len(outputKeys):len(outputKeys)], _moderationAnnotationsOutputKey)
+	}
+	return outputKeys
+}
@@ -0,0 +1,150 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/moderation"
+)
+
+// keywordModerator flags the texts containing the keyword.
+type keywordModerator struct {
+	keyword string
+	calls   [][]string
+}
+
+func (m *keywordModerator) Moderate(_ context.Context, texts []string) ([]moderation.Result, error) {
+	m.calls = append(m.calls, texts)
+	results := make([]moderation.Result, len(texts))
+	for i, text := range texts {
+		flagged := strings.Contains(text, m.keyword)
+		results[i] = moderation.Result{
+			Flagged:    flagged,
+			Categories: map[string]bool{moderation.CategoryHate: flagged},
+		}
+	}
+	return results, nil
+}
+
+func echoChain(output string) Transform {
+	return NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		if output != "" {
+			return map[string]any{"output": output}, nil
+		}
+		return map[string]any{"output": inputs["input"]}, nil
+	}, []string{"input"}, []string{"output"})
+}
+
+func TestModerationRejectsInput(t *testing.T) {
+	t.Parallel()
+
+	moderator := &keywordModerator{keyword: "hate"}
+	chain := NewModeration(echoChain(""), moderator)
+
+	_, err := Call(context.Background(), chain, map[string]any{"input": "I hate you"})
+	require.ErrorIs(t, err, moderation.ErrFlagged)
+	assert.Contains(t, err.Error(), `input "input": hate`)
+	assert.Len(t, moderator.calls, 1)
+
+	outputs, err := Call(context.Background(), chain, map[string]any{"input": "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", outputs["output"])
+	assert.Equal(t, [][]string{{"I hate you"}, {"hello"}, {"hello"}}, moderator.calls)
+}
+
+func TestModerationRedacts(t *testing.T) {
+	t.Parallel()
+
+	moderator := &keywordModerator{keyword: "hate"}
+	chain := NewModeration(echoChain("I hate you too"), moderator,
+		WithInputModeration(ModerationRedact),
+		WithOutputModeration(ModerationRedact),
+		WithRedaction("***"),
+	)
+
+	outputs, err := Call(context.Background(), chain, map[string]any{"input": "I hate you"})
+	require.NoError(t, err)
+	assert.Equal(t, "***", outputs["output"])
+}
+
+func TestModerationAllow(t *testing.T) {
+	t.Parallel()
+
+	moderator := &keywordModerator{keyword: "hate"}
+	chain := NewModeration(echoChain(""), moderator, WithInputModeration(ModerationAllow))
+
+	_, err := Call(context.Background(), chain, map[string]any{"input": "I hate you"})
+	require.ErrorIs(t, err, moderation.ErrFlagged)
+	assert.Contains(t, err.Error(), `output "output"`)
+	assert.Equal(t, [][]string{{"I hate you"}}, moderator.calls)
+}
+
+func TestModerationAnnotates(t *testing.T) {
+	t.Parallel()
+
+	moderator := &keywordModerator{keyword: "hate"}
+	chain := NewModeration(echoChain(""), moderator,
+		WithInputModeration(ModerationAnnotate),
+		WithOutputModeration(ModerationAnnotate),
+	)
+	assert.Equal(t, []string{"output", "moderation"}, chain.GetOutputKeys())
+
+	outputs, err := Call(context.Background(), chain, map[string]any{"input": "I hate you"})
+	require.NoError(t, err)
+	assert.Equal(t, "I hate you", outputs["output"])
+	assert.Equal(t, []ModerationAnnotation{
+		{Kind: "input", Key: "input", Categories: []string{"hate"}, Action: ModerationAnnotate},
+		{Kind: "output", Key: "output", Categories: []string{"hate"}, Action: ModerationAnnotate},
+	}, outputs["moderation"])
+
+	outputs, err = Call(context.Background(), chain, map[string]any{"input": "hello"})
+	require.NoError(t, err)
+	assert.Empty(t, outputs["moderation"])
+}
+
+// scoreModerator flags the texts containing "hate", and scores the texts
+// containing "fight" as violent without flagging them.
+type scoreModerator struct{}
+
+func (scoreModerator) Moderate(_ context.Context, texts []string) ([]moderation.Result, error) {
+	results := make([]moderation.Result, len(texts))
+	for i, text := range texts {
+		hate := strings.Contains(text, "hate")
+		violence := 0.1
+		if strings.Contains(text, "fight") {
+			violence = 0.7
+		}
+		results[i] = moderation.Result{
+			Flagged:    hate,
+			Categories: map[string]bool{moderation.CategoryHate: hate, moderation.CategoryViolence: false},
+			Scores:     map[string]float64{moderation.CategoryViolence: violence},
+		}
+	}
+	return results, nil
+}
+
+func TestModerationPolicy(t *testing.T) {
+	t.Parallel()
+
+	chain := NewModeration(echoChain(""), scoreModerator{}, WithInputPolicy(ModerationPolicy{
+		Default:    ModerationReject,
+		Categories: map[string]ModerationAction{moderation.CategoryHate: ModerationRedact},
+		Thresholds: map[string]float64{moderation.CategoryViolence: 0.5},
+	}))
+
+	outputs, err := Call(context.Background(), chain, map[string]any{"input": "I hate you"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRedaction, outputs["output"])
+
+	// The most severe action of the categories is taken.
+	_, err = Call(context.Background(), chain, map[string]any{"input": "I hate you, let's fight"})
+	require.ErrorIs(t, err, moderation.ErrFlagged)
+	assert.Contains(t, err.Error(), `input "input": hate, violence`)
+
+	outputs, err = Call(context.Background(), chain, map[string]any{"input": "let's talk"})
+	require.NoError(t, err)
+	assert.Equal(t, "let's talk", outputs["output"])
+}
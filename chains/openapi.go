@@ -0,0 +1,467 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// nolint: lll
+	_openAPISelectPrompt = `You are given the operations of an API, one per line, with their IDs, their methods, their paths and their summaries:
+
+{{.operations}}
+
+Select the operation to call to answer the following input.
+
+Input: {{.input}}
+
+Respond with the ID of the operation only.`
+
+	// nolint: lll
+	_openAPIRequestPrompt = `You are given the documentation of an operation of an API:
+
+{{.operation}}
+
+Construct the request of the operation answering the following input. Only use the parameters documented, and exclude any unnecessary data.
+
+Input: {{.input}}
+
+Respond with a JSON object.
+
+{
+	"path_params":  [object of the path parameters],
+	"query_params": [object of the query parameters],
+	"body":         [the JSON body of the request, if needed]
+}`
+
+	// nolint: lll
+	_openAPIAnswerPrompt = `You are given the documentation of an operation of an API:
+
+{{.operation}}
+
+Here is the response of the API to the request of the operation for the input "{{.input}}":
+
+{{.api_response}}
+
+Now, summarize this response. Your summary should reflect the original input and highlight the key information from the API response that answers or relates to that input. Try to make your summary concise, yet informative.
+
+Summary:`
+
+	// _openAPIMaxResponseSize is the maximum number of bytes of the responses
+	// summarized, the rest is truncated.
+	_openAPIMaxResponseSize = 16 * 1024
+)
+
+var (
+	// ErrInvalidOpenAPISpec is returned when the OpenAPI spec given is invalid.
+	ErrInvalidOpenAPISpec = errors.New("invalid OpenAPI spec")
+	// ErrOperationNotFound is returned when the operation selected by the llm
+	// is not an operation of the spec.
+	ErrOperationNotFound = errors.New("operation not found in OpenAPI spec")
+	// ErrRequestNotAllowed is returned when the request constructed by the llm
+	// is not allowed by the OpenAPI chain.
+	ErrRequestNotAllowed = errors.New("request not allowed")
+)
+
+// openAPIParameter is a parameter of an operation of an OpenAPI spec.
+type openAPIParameter struct {
+	Name        string `yaml:"name" json:"name"`
+	In          string `yaml:"in" json:"in"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	Required    bool   `yaml:"required" json:"required,omitempty"`
+	Schema      any    `yaml:"schema" json:"schema,omitempty"`
+}
+
+// openAPIOperation is an operation of an OpenAPI spec.
+type openAPIOperation struct {
+	OperationID string             `yaml:"operationId"`
+	Summary     string             `yaml:"summary"`
+	Description string             `yaml:"description"`
+	Parameters  []openAPIParameter `yaml:"parameters"`
+	RequestBody *struct {
+		Required bool `yaml:"required"`
+		Content  map[string]struct {
+			Schema any `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+
+	method string
+	path   string
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+	Head       *openAPIOperation  `yaml:"head"`
+}
+
+type openAPISpec struct {
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]openAPIPathItem `yaml:"paths"`
+}
+
+// OpenAPIChain is a chain answering the inputs with the operations of an API
+// described by an OpenAPI spec. The chain selects an operation of the spec
+// with an llm, constructs its request with the llm, executes it and
+// summarizes the response with the llm.
+type OpenAPIChain struct {
+	// SelectChain selects the ID of the operation to call from the
+	// "operations" of the spec and the "input".
+	SelectChain *LLMChain
+	// RequestChain constructs the parameters and the body of the request from
+	// the documentation of the "operation" and the "input", as a JSON object.
+	RequestChain *LLMChain
+	// AnswerChain summarizes the "api_response" to the "input".
+	AnswerChain *LLMChain
+	// Request executes the requests.
+	Request HTTPRequest
+
+	// BaseURL is the URL the paths of the operations are relative to, by
+	// default the URL of the first server of the spec.
+	BaseURL string
+	// AllowedHosts are the hosts the chain sends requests to, by default the
+	// host of the base URL.
+	AllowedHosts []string
+	// AllowedMethods are the HTTP methods of the operations the chain calls,
+	// by default GET only, so that the inputs do not modify the resources of
+	// the API unless allowed.
+	AllowedMethods []string
+
+	operations []openAPIOperation
+}
+
+var _ Chain = OpenAPIChain{}
+
+// NewOpenAPIChain creates a new OpenAPIChain from the OpenAPI 3 spec, in JSON
+// or YAML. The operations of the spec are the operations of its paths, with
+// their parameters inline.
+func NewOpenAPIChain(llm llms.LanguageModel, spec []byte, request HTTPRequest) (OpenAPIChain, error) {
+	var s openAPISpec
+	if err := yaml.Unmarshal(spec, &s); err != nil {
+		return OpenAPIChain{}, fmt.Errorf("%w: %w", ErrInvalidOpenAPISpec, err)
+	}
+	operations := s.operations()
+	if len(operations) == 0 {
+		return OpenAPIChain{}, fmt.Errorf("%w: no operations", ErrInvalidOpenAPISpec)
+	}
+
+	c := OpenAPIChain{
+		SelectChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_openAPISelectPrompt, []string{"operations", "input"},
+		)),
+		RequestChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_openAPIRequestPrompt, []string{"operation", "input"},
+		)),
+		AnswerChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_openAPIAnswerPrompt, []string{"operation", "input", "api_response"},
+		)),
+		Request:        request,
+		AllowedMethods: []string{http.MethodGet},
+		operations:     operations,
+	}
+	if len(s.Servers) > 0 {
+		c.BaseURL = s.Servers[0].URL
+		if u, err := url.Parse(c.BaseURL); err == nil && u.Host != "" {
+			c.AllowedHosts = []string{u.Host}
+		}
+	}
+
+	return c, nil
+}
+
+// Call selects the operation answering the input, executes its request and
+// returns the summary of the response in the "answer" key.
+func (c OpenAPIChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values["input"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+	llmOptions := append(withoutStreaming(options), WithTemperature(0))
+
+	operationID, err := Predict(ctx, c.SelectChain, map[string]any{
+		"operations": c.describeOperations(),
+		"input":      input,
+	}, llmOptions...)
+	if err != nil {
+		return nil, err
+	}
+	operation, err := c.findOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	documentation := operation.describe()
+	requestText, err := Predict(ctx, c.RequestChain, map[string]any{
+		"operation": documentation,
+		"input":     input,
+	}, llmOptions...)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, operation, requestText)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse, err := c.runRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := Predict(ctx, c.AnswerChain, map[string]any{
+		"operation":    documentation,
+		"input":        input,
+		"api_response": apiResponse,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"answer": answer}, nil
+}
+
+// GetMemory returns the memory of the OpenAPIChain.
+func (c OpenAPIChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the input keys of the OpenAPIChain, "input".
+func (c OpenAPIChain) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+// GetOutputKeys returns the output keys of the OpenAPIChain, "answer".
+func (c OpenAPIChain) GetOutputKeys() []string {
+	return []string{"answer"}
+}
+
+// describeOperations returns the operations of the spec, one per line.
+func (c OpenAPIChain) describeOperations() string {
+	var b strings.Builder
+	for _, operation := range c.operations {
+		fmt.Fprintf(&b, "%s: %s %s", operation.OperationID, operation.method, operation.path)
+		if operation.Summary != "" {
+			fmt.Fprintf(&b, " - %s", operation.Summary)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// findOperation returns the operation of the ID selected by the llm.
+func (c OpenAPIChain) findOperation(text string) (openAPIOperation, error) {
+	id := strings.Trim(strings.TrimSpace(text), "`'\".")
+	for _, operation := range c.operations {
+		if operation.OperationID == id {
+			return operation, nil
+		}
+	}
+	return openAPIOperation{}, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+}
+
+// _jsonObjectRegex matches the JSON object of the outputs of the llms.
+var _jsonObjectRegex = regexp.MustCompile(`(?s)\{.*\}`)
+
+// newRequest creates the request of the operation from the JSON object
+// constructed by the llm, if the method and the host are allowed.
+func (c OpenAPIChain) newRequest(ctx context.Context, operation openAPIOperation, text string) (*http.Request, error) { //nolint:lll
+	var output struct {
+		PathParams  map[string]any `json:"path_params"`
+		QueryParams map[string]any `json:"query_params"`
+		Body        any            `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(_jsonObjectRegex.FindString(text)), &output); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOutputValues, err)
+	}
+
+	if !containsFold(c.AllowedMethods, operation.method) {
+		return nil, fmt.Errorf("%w: method %s", ErrRequestNotAllowed, operation.method)
+	}
+
+	path := operation.path
+	query := url.Values{}
+	for _, parameter := range operation.Parameters {
+		switch parameter.In {
+		case "path":
+			value, ok := output.PathParams[parameter.Name]
+			if !ok {
+				return nil, fmt.Errorf("%w: missing path parameter %s", ErrInvalidOutputValues, parameter.Name)
+			}
+			path = strings.ReplaceAll(path, "{"+parameter.Name+"}", url.PathEscape(fmt.Sprint(value)))
+		case "query":
+			value, ok := output.QueryParams[parameter.Name]
+			if !ok {
+				if parameter.Required {
+					return nil, fmt.Errorf("%w: missing query parameter %s", ErrInvalidOutputValues, parameter.Name)
+				}
+				continue
+			}
+			if values, ok := value.([]any); ok {
+				for _, v := range values {
+					query.Add(parameter.Name, fmt.Sprint(v))
+				}
+				continue
+			}
+			query.Set(parameter.Name, fmt.Sprint(value))
+		}
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(c.BaseURL, "/") + path)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" || !containsFold(c.AllowedHosts, u.Host) {
+		return nil, fmt.Errorf("%w: host %q", ErrRequestNotAllowed, u.Host)
+	}
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if operation.RequestBody != nil && output.Body != nil {
+		bodyBytes, err := json.Marshal(output.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = strings.NewReader(string(bodyBytes))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, operation.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// runRequest executes the request and returns the status and the body of the
+// response, truncated.
+func (c OpenAPIChain) runRequest(req *http.Request) (string, error) {
+	resp, err := c.Request.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, _openAPIMaxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Status: %s\n\n%s", resp.Status, body), nil
+}
+
+// operations returns the operations of the paths of the spec, sorted by path
+// and method, with the parameters of their paths.
+func (s openAPISpec) operations() []openAPIOperation {
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var operations []openAPIOperation
+	for _, path := range paths {
+		item := s.Paths[path]
+		for _, o := range []struct {
+			method    string
+			operation *openAPIOperation
+		}{
+			{http.MethodGet, item.Get},
+			{http.MethodPut, item.Put},
+			{http.MethodPost, item.Post},
+			{http.MethodDelete, item.Delete},
+			{http.MethodPatch, item.Patch},
+			{http.MethodHead, item.Head},
+		} {
+			if o.operation == nil {
+				continue
+			}
+			operation := *o.operation
+			operation.method, operation.path = o.method, path
+			if operation.OperationID == "" {
+				operation.OperationID = strings.ToLower(o.method) + " " + path
+			}
+			operation.Parameters = mergeParameters(item.Parameters, operation.Parameters)
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// mergeParameters returns the parameters of a path overridden by the
+// parameters of an operation of the path.
+func mergeParameters(pathParameters, operationParameters []openAPIParameter) []openAPIParameter {
+	merged := make([]openAPIParameter, 0, len(pathParameters)+len(operationParameters))
+	for _, parameter := range pathParameters {
+		overridden := false
+		for _, p := range operationParameters {
+			if p.Name == parameter.Name && p.In == parameter.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, parameter)
+		}
+	}
+	return append(merged, operationParameters...)
+}
+
+// describe returns the documentation of the operation given to the llm.
+func (o openAPIOperation) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", o.method, o.path)
+	if o.Summary != "" {
+		fmt.Fprintf(&b, "Summary: %s\n", o.Summary)
+	}
+	if o.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", o.Description)
+	}
+	for _, parameter := range o.Parameters {
+		if parameter.In != "path" && parameter.In != "query" {
+			continue
+		}
+		p, err := json.Marshal(parameter)
+		if err == nil {
+			fmt.Fprintf(&b, "Parameter: %s\n", p)
+		}
+	}
+	if o.RequestBody != nil {
+		if content, ok := o.RequestBody.Content["application/json"]; ok {
+			if body, err := json.Marshal(content.Schema); err == nil {
+				fmt.Fprintf(&b, "Body schema: %s\n", body)
+			}
+		}
+	}
+	return b.String()
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package chains
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _petStoreSpec = `
+openapi: 3.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          required: false
+          schema:
+            type: integer
+    post:
+      operationId: createPet
+      summary: Create a pet
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: showPetById
+      summary: Info for a specific pet
+`
+
+// sequenceLanguageModel is a language model generating its responses in order.
+type sequenceLanguageModel struct {
+	mu        sync.Mutex
+	responses []string
+	prompts   []string
+}
+
+func (l *sequenceLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prompts = append(l.prompts, promptValues[0].String())
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: response}}}}, nil
+}
+
+func (l *sequenceLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+type recordingDoer struct {
+	requests []*http.Request
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"id":"42","name":"Leo"}`)),
+	}, nil
+}
+
+func TestOpenAPIChain(t *testing.T) {
+	t.Parallel()
+
+	llm := &sequenceLanguageModel{responses: []string{
+		"showPetById",
+		`The request is {"path_params": {"petId": "42"}, "query_params": {}}`,
+		"The pet 42 is Leo.",
+	}}
+	doer := &recordingDoer{}
+	chain, err := NewOpenAPIChain(llm, []byte(_petStoreSpec), doer)
+	require.NoError(t, err)
+
+	result, err := Run(context.Background(), chain, "What is the name of the pet 42?")
+	require.NoError(t, err)
+	require.Equal(t, "The pet 42 is Leo.", result)
+
+	require.Contains(t, llm.prompts[0], "listPets: GET /pets - List all pets")
+	require.Contains(t, llm.prompts[0], "showPetById: GET /pets/{petId} - Info for a specific pet")
+	require.Contains(t, llm.prompts[1], `"name":"petId"`)
+	require.Contains(t, llm.prompts[2], `{"id":"42","name":"Leo"}`)
+
+	require.Len(t, doer.requests, 1)
+	require.Equal(t, http.MethodGet, doer.requests[0].Method)
+	require.Equal(t, "https://petstore.example.com/v1/pets/42", doer.requests[0].URL.String())
+}
+
+func TestOpenAPIChainNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{}
+	chain, err := NewOpenAPIChain(&sequenceLanguageModel{responses: []string{
+		"createPet",
+		`{"body": {"name": "Rex"}}`,
+	}}, []byte(_petStoreSpec), doer)
+	require.NoError(t, err)
+
+	_, err = Run(context.Background(), chain, "Add the pet Rex.")
+	require.ErrorIs(t, err, ErrRequestNotAllowed)
+
+	chain.SelectChain.LLM = &sequenceLanguageModel{responses: []string{"deletePet"}}
+	_, err = Run(context.Background(), chain, "Delete the pet 42.")
+	require.ErrorIs(t, err, ErrOperationNotFound)
+	require.Empty(t, doer.requests)
+
+	_, err = NewOpenAPIChain(&sequenceLanguageModel{}, []byte("openapi: 3.0.0"), doer)
+	require.ErrorIs(t, err, ErrInvalidOpenAPISpec)
+}
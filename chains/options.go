@@ -21,18 +21,28 @@ type chainCallOption struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming earl.
 	StreamingFunc func(ctx context.Context, chunk []byte) error
+	// StreamingChunkFunc is a function to be called for each structured chunk of a
+	// streaming response, with its reasoning and its tool calls.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error
 	// TopK is the number of tokens to consider for top-k sampling in an llm call.
 	TopK int
 	// TopP is the cumulative probability for top-p sampling in an llm call.
 	TopP float64
 	// Seed is a seed for deterministic sampling in an llm call.
 	Seed int
+	// SeedSet is true if Seed is set with WithSeed.
+	SeedSet bool
 	// MinLength is the minimum length of the generated text in an llm call.
 	MinLength int
 	// MaxLength is the maximum length of the generated text in an llm call.
 	MaxLength int
 	// RepetitionPenalty is the repetition penalty for sampling in an llm call.
 	RepetitionPenalty float64
+	// MaxConcurrency is the maximum number of concurrent calls of Batch.
+	MaxConcurrency int
+	// BatchResultFunc is a function to be called with the result of each call
+	// of Batch.
+	BatchResultFunc func(ctx context.Context, index int, result BatchResult)
 }
 
 // WithModel is an option for LLM.Call.
@@ -70,6 +80,14 @@ func WithStreamingFunc(streamingFunc func(ctx context.Context, chunk []byte) err
 	}
 }
 
+// WithStreamingChunkFunc is an option for LLM.Call that allows streaming the
+// structured chunks of the responses.
+func WithStreamingChunkFunc(streamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error) ChainCallOption { //nolint:lll
+	return func(o *chainCallOption) {
+		o.StreamingChunkFunc = streamingChunkFunc
+	}
+}
+
 // WithTopK will add an option to use top-k sampling for LLM.Call.
 func WithTopK(topK int) ChainCallOption {
 	return func(o *chainCallOption) {
@@ -88,6 +106,7 @@ func WithTopP(topP float64) ChainCallOption {
 func WithSeed(seed int) ChainCallOption {
 	return func(o *chainCallOption) {
 		o.Seed = seed
+		o.SeedSet = true
 	}
 }
 
@@ -119,6 +138,23 @@ func WithStopWords(stopWords []string) ChainCallOption {
 	}
 }
 
+// WithMaxConcurrency is an option for setting the maximum number of
+// concurrent calls of Batch, 5 by default.
+func WithMaxConcurrency(maxConcurrency int) ChainCallOption {
+	return func(o *chainCallOption) {
+		o.MaxConcurrency = maxConcurrency
+	}
+}
+
+// WithBatchResultFunc is an option for Batch to be notified of the result of
+// each call as it finishes, e.g. to report the progress. The function is not
+// called concurrently.
+func WithBatchResultFunc(batchResultFunc func(ctx context.Context, index int, result BatchResult)) ChainCallOption {
+	return func(o *chainCallOption) {
+		o.BatchResultFunc = batchResultFunc
+	}
+}
+
 func getLLMCallOptions(options ...ChainCallOption) []llms.CallOption {
 	opts := &chainCallOption{}
 	for _, option := range options {
@@ -131,12 +167,25 @@ func getLLMCallOptions(options ...ChainCallOption) []llms.CallOption {
 		llms.WithTemperature(opts.Temperature),
 		llms.WithStopWords(opts.StopWords),
 		llms.WithStreamingFunc(opts.StreamingFunc),
+		llms.WithStreamingChunkFunc(opts.StreamingChunkFunc),
 		llms.WithTopK(opts.TopK),
-		llms.WithSeed(opts.Seed),
 		llms.WithMinLength(opts.MinLength),
 		llms.WithMaxLength(opts.MaxLength),
 		llms.WithRepetitionPenalty(opts.RepetitionPenalty),
 	}
+	if opts.SeedSet {
+		chainCallOption = append(chainCallOption, llms.WithSeed(opts.Seed))
+	}
 
 	return chainCallOption
 }
+
+// withoutStreaming returns the options without the streaming functions, for
+// the intermediate llm calls of the chains, so that only the llm calls
+// producing the output of a chain stream to the caller.
+func withoutStreaming(options []ChainCallOption) []ChainCallOption {
+	return append(options[:len(options):len(options)], func(o *chainCallOption) {
+		o.StreamingFunc = nil
+		o.StreamingChunkFunc = nil
+	})
+}
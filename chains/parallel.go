@@ -0,0 +1,125 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/internal/util"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ParallelChain is a chain that runs multiple chains concurrently with the same
+// inputs and merges their outputs into one output. Used as a step of a
+// sequential chain, it fans the values out to its chains and their outputs in
+// to the next steps.
+type ParallelChain struct {
+	names      []string
+	chains     map[string]Chain
+	inputKeys  []string
+	outputKeys []string
+	memory     schema.Memory
+}
+
+var _ Chain = &ParallelChain{}
+
+// NewParallelChain creates a new parallel chain from the chains by name. The
+// output keys of the chains must not overlap, so that the outputs are merged
+// without conflicts.
+func NewParallelChain(chains map[string]Chain) (*ParallelChain, error) {
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("%w: no chains in the parallel chain", ErrChainInitialization)
+	}
+
+	c := &ParallelChain{
+		names:  util.ListKeys(chains),
+		chains: chains,
+		memory: memory.NewSimple(),
+	}
+	sort.Strings(c.names)
+
+	inputKeys := make(map[string]struct{})
+	outputKeys := make(map[string]string)
+	for _, name := range c.names {
+		chain := chains[name]
+		for _, key := range chainRequiredInputKeys(chain) {
+			if _, ok := inputKeys[key]; !ok {
+				inputKeys[key] = struct{}{}
+				c.inputKeys = append(c.inputKeys, key)
+			}
+		}
+		for _, key := range chain.GetOutputKeys() {
+			if other, ok := outputKeys[key]; ok {
+				return nil, fmt.Errorf(
+					"%w: chains %s and %s have the same output key: %s",
+					ErrChainInitialization, other, name, key,
+				)
+			}
+			outputKeys[key] = name
+			c.outputKeys = append(c.outputKeys, key)
+		}
+	}
+
+	return c, nil
+}
+
+// Call runs the chains concurrently and returns their merged outputs. The
+// first error of a chain cancels the others. This method should not be called
+// directly. Use rather the Call function that handles the memory and other
+// aspects of the chain.
+func (c *ParallelChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		outputs  = make(map[string]any, len(c.outputKeys))
+	)
+	for _, name := range c.names {
+		name, chain := name, c.chains[name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The chains do not stream, as their chunks would interleave.
+			chainOutputs, err := Call(ctx, chain, inputs, withoutStreaming(options)...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("chain %s: %w", name, err)
+					cancel()
+				}
+				return
+			}
+			for _, key := range chain.GetOutputKeys() {
+				outputs[key] = chainOutputs[key]
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return outputs, nil
+}
+
+// GetMemory gets the memory of the chain.
+func (c *ParallelChain) GetMemory() schema.Memory { //nolint:ireturn
+	return c.memory
+}
+
+// GetInputKeys returns the input keys of all the chains.
+func (c *ParallelChain) GetInputKeys() []string {
+	return c.inputKeys
+}
+
+// GetOutputKeys returns the output keys of all the chains.
+func (c *ParallelChain) GetOutputKeys() []string {
+	return c.outputKeys
+}
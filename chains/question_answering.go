@@ -13,6 +13,15 @@ const _defaultStuffQATemplate = `Use the following pieces of context to answer t
 Question: {{.question}}
 Helpful Answer:`
 
+//nolint:lll
+const _defaultStuffQAWithSourcesTemplate = `Use the following numbered sources to answer the question at the end. If you don't know the answer, just say that you don't know, don't try to make up an answer.
+Cite the sources supporting each sentence of your answer with their numbers in brackets at the end of the sentence, e.g. "The sky is blue [1][3].". Only cite the sources given.
+
+{{.context}}
+
+Question: {{.question}}
+Helpful Answer:`
+
 const _defaultRefineQATemplate = `The original question is as follows: {{.question}}
 We have provided an existing answer: {{.existing_answer}}
 We have the opportunity to refine the existing answer
@@ -116,6 +125,32 @@ func LoadStuffQA(llm llms.LanguageModel) StuffDocuments {
 	return NewStuffDocuments(llmChain)
 }
 
+// LoadStuffQAWithSources loads a StuffDocuments chain answering with citations
+// of the documents, numbered in brackets by the RetrievalQA chains returning
+// the citations, see ParseCitations.
+func LoadStuffQAWithSources(llm llms.LanguageModel) StuffDocuments {
+	prompt := prompts.NewPromptTemplate(
+		_defaultStuffQAWithSourcesTemplate,
+		[]string{"context", "question"},
+	)
+	return NewStuffDocuments(NewLLMChain(llm, prompt))
+}
+
+// LoadFormattedStuffQA loads a FormattedStuffDocuments chain with the default
+// prompt of LoadStuffQA, formatting the titles and sources of the documents
+// within the token budget, zero for no budget. It is the combine documents
+// chain of RetrievalQA or, with LoadCondenseQuestionGenerator, of
+// ConversationalRetrievalQA.
+func LoadFormattedStuffQA(llm llms.LanguageModel, tokenBudget int) FormattedStuffDocuments {
+	qaPromptSelector := ConditionalPromptSelector{
+		DefaultPrompt: prompts.NewPromptTemplate(_defaultStuffQATemplate, []string{"context", "question"}),
+	}
+
+	chain := NewFormattedStuffDocuments(NewLLMChain(llm, qaPromptSelector.GetPrompt(llm)))
+	chain.TokenBudget = tokenBudget
+	return chain
+}
+
 // LoadRefineQA loads a refine documents chain for question answering. Inputs are
 // "question" and "input_documents".
 func LoadRefineQA(llm llms.LanguageModel) RefineDocuments {
@@ -134,8 +169,8 @@ func LoadRefineQA(llm llms.LanguageModel) RefineDocuments {
 	)
 }
 
-// LoadRefineQA loads a refine documents chain for question answering. Inputs are
-// "question" and "input_documents".
+// LoadMapReduceQA loads a map reduce documents chain for question answering.
+// Inputs are "question" and "input_documents".
 func LoadMapReduceQA(llm llms.LanguageModel) MapReduceDocuments {
 	getInfoPrompt := prompts.NewPromptTemplate(
 		_defaultMapReduceGetInformationQATemplate,
@@ -0,0 +1,148 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+//nolint:lll
+const _defaultQAGenerationTemplate = `You are a smart assistant designed to help teachers come up with reading comprehension questions.
+Given a piece of text, you must come up with {{.num_questions}} question and answer pairs that can be used to test the reading comprehension abilities of a student.
+Each question must be answerable from the text alone, and each answer must be stated in the text.
+When coming up with the question and answer pairs, you must respond in the following JSON format:
+{"pairs": [{"question": "$YOUR_QUESTION_HERE", "answer": "$THE_ANSWER_HERE"}]}
+
+Please come up with question and answer pairs, in the specified JSON format, for the following text:
+----------------
+{{.text}}`
+
+const (
+	_qaGenerationDefaultOutputKey    = "qa_pairs"
+	_qaGenerationDefaultNumQuestions = 3
+)
+
+// QAPair is a question generated from a document, its answer and the chunk
+// of the document it was generated from.
+type QAPair struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+	// Source is the chunk of the document the pair was generated from, with
+	// the metadata of the document.
+	Source schema.Document `json:"-"`
+}
+
+// QAGenerationChain is a chain generating question and answer pairs from
+// documents, to bootstrap the evaluation datasets of question answering
+// chains: the documents are split into chunks by the TextSplitter, if any, and
+// the model generates NumQuestions pairs from each chunk.
+type QAGenerationChain struct {
+	// LLMChain generates the pairs of the "text" of a chunk as a JSON object,
+	// the number of pairs requested being the "num_questions".
+	LLMChain *LLMChain
+	// TextSplitter splits the documents into chunks. If nil, the pairs are
+	// generated from the whole documents.
+	TextSplitter textsplitter.TextSplitter
+	// NumQuestions is the number of pairs requested for each chunk.
+	NumQuestions int
+
+	// The input key of the documents, by default "input_documents".
+	InputKey string
+	// The output key of the []QAPair generated, by default "qa_pairs".
+	OutputKey string
+}
+
+var _ Chain = QAGenerationChain{}
+
+// NewQAGenerationChain creates a new chain generating question and answer
+// pairs from documents with the model.
+func NewQAGenerationChain(llm llms.LanguageModel, textSplitter textsplitter.TextSplitter) QAGenerationChain {
+	return QAGenerationChain{
+		LLMChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultQAGenerationTemplate, []string{"text", "num_questions"},
+		)),
+		TextSplitter: textSplitter,
+		NumQuestions: _qaGenerationDefaultNumQuestions,
+		InputKey:     _combineDocumentsDefaultInputKey,
+		OutputKey:    _qaGenerationDefaultOutputKey,
+	}
+}
+
+// Call generates the question and answer pairs of the chunks of the
+// documents, in the order of the chunks. The outputs of the model are not
+// streamed.
+func (c QAGenerationChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	docs, ok := values[c.InputKey].([]schema.Document)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+	if c.TextSplitter != nil {
+		var err error
+		docs, err = textsplitter.SplitDocuments(c.TextSplitter, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pairs := make([]QAPair, 0, len(docs)*c.NumQuestions)
+	for _, doc := range docs {
+		text, err := Predict(ctx, c.LLMChain, map[string]any{
+			"text":          doc.PageContent,
+			"num_questions": c.NumQuestions,
+		}, withoutStreaming(options)...)
+		if err != nil {
+			return nil, err
+		}
+		docPairs, err := parseQAPairs(text)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range docPairs {
+			pair.Source = doc
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return map[string]any{c.OutputKey: pairs}, nil
+}
+
+// parseQAPairs parses the pairs of the JSON object of the output of the
+// model, skipping the pairs without a question or an answer.
+func parseQAPairs(text string) ([]QAPair, error) {
+	var output struct {
+		Pairs []QAPair `json:"pairs"`
+	}
+	if err := json.Unmarshal([]byte(_jsonObjectRegex.FindString(text)), &output); err != nil {
+		return nil, fmt.Errorf("%w: question generation output %q: %w", ErrInvalidOutputValues, text, err)
+	}
+
+	pairs := make([]QAPair, 0, len(output.Pairs))
+	for _, pair := range output.Pairs {
+		pair.Question = strings.TrimSpace(pair.Question)
+		pair.Answer = strings.TrimSpace(pair.Answer)
+		if pair.Question == "" || pair.Answer == "" {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+func (c QAGenerationChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c QAGenerationChain) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c QAGenerationChain) GetOutputKeys() []string {
+	return []string{c.OutputKey}
+}
@@ -0,0 +1,57 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// paragraphSplitter splits the texts into paragraphs.
+type paragraphSplitter struct{}
+
+func (paragraphSplitter) SplitText(text string) ([]string, error) {
+	return strings.Split(text, "\n\n"), nil
+}
+
+func TestQAGenerationChain(t *testing.T) {
+	t.Parallel()
+
+	llm := &sequenceLanguageModel{responses: []string{
+		"```json\n" + `{"pairs": [{"question": "What is foo?", "answer": "34"}, {"question": " ", "answer": "1"}]}` + "\n```",
+		`Here are the pairs: {"pairs": [{"question": "What is bar?", "answer": " 1 "}]}`,
+	}}
+	chain := NewQAGenerationChain(llm, paragraphSplitter{})
+	chain.NumQuestions = 1
+
+	result, err := Call(context.Background(), chain, map[string]any{
+		"input_documents": []schema.Document{{
+			PageContent: "foo is 34\n\nbar is 1",
+			Metadata:    map[string]any{"source": "numbers.txt"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []QAPair{
+		{
+			Question: "What is foo?",
+			Answer:   "34",
+			Source:   schema.Document{PageContent: "foo is 34", Metadata: map[string]any{"source": "numbers.txt"}},
+		},
+		{
+			Question: "What is bar?",
+			Answer:   "1",
+			Source:   schema.Document{PageContent: "bar is 1", Metadata: map[string]any{"source": "numbers.txt"}},
+		},
+	}, result["qa_pairs"])
+	require.Len(t, llm.prompts, 2)
+	require.Contains(t, llm.prompts[0], "come up with 1 question and answer pairs")
+	require.True(t, strings.HasSuffix(llm.prompts[1], "----------------\nbar is 1"))
+
+	llm.responses = []string{"What is foo? 34"}
+	_, err = Call(context.Background(), chain, map[string]any{
+		"input_documents": []schema.Document{{PageContent: "foo is 34"}},
+	})
+	require.ErrorIs(t, err, ErrInvalidOutputValues)
+}
@@ -34,6 +34,10 @@ type RefineDocuments struct {
 	OutputKey            string
 	DocumentVariableName string
 	InitialResponseName  string
+
+	// Wether or not to add the successive answers, one per document, to the
+	// output.
+	ReturnIntermediateSteps bool
 }
 
 var _ Chain = RefineDocuments{}
@@ -81,10 +85,17 @@ func (c RefineDocuments) Call(ctx context.Context, values map[string]any, option
 	if err != nil {
 		return nil, err
 	}
-	response, err := Predict(ctx, c.LLMChain, initialInputs, options...)
+	// Only the last answer streams to the caller.
+	initialOptions := options
+	if len(docs) > 1 {
+		initialOptions = withoutStreaming(options)
+	}
+	response, err := Predict(ctx, c.LLMChain, initialInputs, initialOptions...)
 	if err != nil {
 		return nil, err
 	}
+	steps := make([]string, 0, len(docs))
+	steps = append(steps, response)
 
 	// Refine the text using the rest of the documents.
 	for i := 1; i < len(docs); i++ {
@@ -92,15 +103,24 @@ func (c RefineDocuments) Call(ctx context.Context, values map[string]any, option
 		if err != nil {
 			return nil, err
 		}
-		response, err = Predict(ctx, c.RefineLLMChain, refineInputs, options...)
+		refineOptions := options
+		if i < len(docs)-1 {
+			refineOptions = withoutStreaming(options)
+		}
+		response, err = Predict(ctx, c.RefineLLMChain, refineInputs, refineOptions...)
 		if err != nil {
 			return nil, err
 		}
+		steps = append(steps, response)
 	}
 
-	return map[string]any{
+	outputs := map[string]any{
 		c.OutputKey: response,
-	}, nil
+	}
+	if c.ReturnIntermediateSteps {
+		outputs[_intermediateStepsOutputKey] = steps
+	}
+	return outputs, nil
 }
 
 func (c RefineDocuments) constructInitialInputs(doc schema.Document, rest map[string]any) (map[string]any, error) {
@@ -152,10 +172,12 @@ func (c RefineDocuments) getBaseInputs(doc schema.Document, rest map[string]any)
 
 func (c RefineDocuments) GetInputKeys() []string {
 	inputKeys := []string{c.InputKey}
-	for _, key := range c.LLMChain.GetInputKeys() {
-		if key == c.DocumentVariableName {
+	seen := map[string]bool{c.InputKey: true, c.DocumentVariableName: true, c.InitialResponseName: true}
+	for _, key := range append(c.LLMChain.GetInputKeys(), c.RefineLLMChain.GetInputKeys()...) {
+		if seen[key] {
 			continue
 		}
+		seen[key] = true
 		inputKeys = append(inputKeys, key)
 	}
 
@@ -163,6 +185,9 @@ func (c RefineDocuments) GetInputKeys() []string {
 }
 
 func (c RefineDocuments) GetOutputKeys() []string {
+	if c.ReturnIntermediateSteps {
+		return []string{c.OutputKey, _intermediateStepsOutputKey}
+	}
 	return []string{c.OutputKey}
 }
 
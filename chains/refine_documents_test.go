@@ -0,0 +1,47 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestRefineDocuments(t *testing.T) {
+	t.Parallel()
+
+	c := NewRefineDocuments(
+		NewLLMChain(
+			&testLanguageModel{},
+			prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+		),
+		NewLLMChain(
+			&testLanguageModel{},
+			prompts.NewPromptTemplate("{{.existing_answer}} {{.context}}", []string{"existing_answer", "context"}),
+		),
+	)
+	c.ReturnIntermediateSteps = true
+
+	result, err := Call(context.Background(), c, map[string]any{
+		"input_documents": []schema.Document{
+			{PageContent: "foo"},
+			{PageContent: "boo"},
+			{PageContent: "zoo"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "foo boo zoo", result["text"])
+	require.Equal(t, []string{"foo", "foo boo", "foo boo zoo"}, result[_intermediateStepsOutputKey])
+}
+
+func TestRefineDocumentsInputKeys(t *testing.T) {
+	t.Parallel()
+
+	c := LoadRefineQA(&testLanguageModel{})
+	require.ElementsMatch(t, []string{"input_documents", "question"}, c.GetInputKeys())
+
+	_, err := Call(context.Background(), c, map[string]any{"input_documents": []schema.Document{}, "question": "foo"})
+	require.ErrorIs(t, err, ErrInvalidInputValues)
+}
@@ -12,6 +12,7 @@ import (
 const (
 	_retrievalQADefaultInputKey          = "query"
 	_retrievalQADefaultSourceDocumentKey = "source_documents"
+	_retrievalQADefaultCitationsKey      = "citations"
 )
 
 // RetrievalQA is a chain used for question-answering against a retriever.
@@ -31,6 +32,11 @@ type RetrievalQA struct {
 	// If the chain should return the documents used by the combine
 	// documents chain in the "source_documents" key.
 	ReturnSourceDocuments bool
+
+	// If the chain should number the documents given to the combine documents
+	// chain, for the answer to cite them, and return the claims of the answer
+	// citing them as a []Citation in the "citations" key.
+	ReturnCitations bool
 }
 
 var _ Chain = RetrievalQA{}
@@ -57,6 +63,16 @@ func NewRetrievalQAFromLLM(llm llms.LanguageModel, retriever schema.Retriever) R
 	)
 }
 
+// NewRetrievalQAWithSourcesFromLLM creates a new RetrievalQA chain answering
+// with the prompt of LoadStuffQAWithSources, returning the source documents
+// and the citations of the answer.
+func NewRetrievalQAWithSourcesFromLLM(llm llms.LanguageModel, retriever schema.Retriever) RetrievalQA {
+	chain := NewRetrievalQA(LoadStuffQAWithSources(llm), retriever)
+	chain.ReturnSourceDocuments = true
+	chain.ReturnCitations = true
+	return chain
+}
+
 // Call gets relevant documents from the retriever and gives them to the combine
 // documents chain.
 func (c RetrievalQA) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint: lll
@@ -70,9 +86,14 @@ func (c RetrievalQA) Call(ctx context.Context, values map[string]any, options ..
 		return nil, err
 	}
 
+	inputDocs := docs
+	if c.ReturnCitations {
+		inputDocs = numberDocuments(docs)
+	}
+
 	result, err := Call(ctx, c.CombineDocumentsChain, map[string]any{
 		"question":        query,
-		"input_documents": docs,
+		"input_documents": inputDocs,
 	}, options...)
 	if err != nil {
 		return nil, err
@@ -81,6 +102,17 @@ func (c RetrievalQA) Call(ctx context.Context, values map[string]any, options ..
 	if c.ReturnSourceDocuments {
 		result[_retrievalQADefaultSourceDocumentKey] = docs
 	}
+	if c.ReturnCitations {
+		outputKeys := c.CombineDocumentsChain.GetOutputKeys()
+		if len(outputKeys) == 0 {
+			return nil, ErrInvalidOutputValues
+		}
+		answer, ok := result[outputKeys[0]].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: answer is not a string", ErrInvalidOutputValues)
+		}
+		result[_retrievalQADefaultCitationsKey] = ParseCitations(answer, docs)
+	}
 
 	return result, nil
 }
@@ -98,6 +130,9 @@ func (c RetrievalQA) GetOutputKeys() []string {
 	if c.ReturnSourceDocuments {
 		outputKeys = append(outputKeys, _retrievalQADefaultSourceDocumentKey)
 	}
+	if c.ReturnCitations {
+		outputKeys = append(outputKeys, _retrievalQADefaultCitationsKey)
+	}
 
 	return outputKeys
 }
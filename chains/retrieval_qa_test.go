@@ -63,3 +63,22 @@ func TestRetrievalQAFromLLM(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, strings.Contains(result, "34"), "expected 34 in result")
 }
+
+func TestRetrievalQAWithSources(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{expResult: "foo is 34 [1]. bar is 1 [2]."}
+	chain := NewRetrievalQAWithSourcesFromLLM(llm, testRetriever{})
+
+	result, err := Call(context.Background(), chain, map[string]any{"query": "what are foo and bar?"})
+	require.NoError(t, err)
+	require.Contains(t, llm.recordedPrompt[0].String(), "[1] foo is 34\n\n[2] bar is 1")
+
+	docs, err := testRetriever{}.GetRelevantDocuments(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, docs, result["source_documents"])
+	require.Equal(t, []Citation{
+		{Claim: "foo is 34.", Sources: []int{1}, Documents: docs[:1]},
+		{Claim: "bar is 1.", Sources: []int{2}, Documents: docs[1:]},
+	}, result["citations"])
+}
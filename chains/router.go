@@ -0,0 +1,203 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_routerDefaultInputKey      = "input"
+	_routerDefaultDestination   = "DEFAULT"
+	_routerDestinationOutputKey = "destination"
+	_routerConfidenceOutputKey  = "confidence"
+	_multiPromptDefaultTemplate = "{{.input}}"
+
+	// nolint: lll
+	_routerTemplate = `Given a raw text input to a language model select the model prompt best suited for the input. You will be given the names of the available prompts and a description of what the prompt is best suited for.
+
+<< FORMATTING >>
+Return a JSON object formatted to look like:
+{
+	"destination": string \ name of the prompt to use or "DEFAULT",
+	"confidence": number \ how confident you are that the prompt is suited for the input, between 0 and 1
+}
+
+REMEMBER: "destination" MUST be one of the candidate prompt names specified below OR it can be "DEFAULT" if the input is not well suited for any of the candidate prompts.
+
+<< CANDIDATE PROMPTS >>
+{{.destinations}}
+
+<< INPUT >>
+{{.input}}
+
+<< OUTPUT >>
+`
+)
+
+// Route is a destination of a router chain.
+type Route struct {
+	// Name is the name of the route given to the router.
+	Name string
+	// Description describes the inputs the route is best suited for.
+	Description string
+	// Chain is the chain called with the inputs routed.
+	Chain Chain
+}
+
+// PromptRoute is a destination of a multi prompt chain.
+type PromptRoute struct {
+	// Name is the name of the route given to the router.
+	Name string
+	// Description describes the inputs the prompt is best suited for.
+	Description string
+	// Prompt is the prompt of the route, with the "input" variable.
+	Prompt prompts.FormatPrompter
+	// LLM is the model of the route, the model of the router if nil.
+	LLM llms.LanguageModel
+}
+
+// RouterChain is a chain classifying the inputs with an llm and dispatching
+// them to the chain of the route selected, or to the default chain. The
+// outputs of the chains are returned with the name of the route in the
+// "destination" key, "DEFAULT" for the default chain, and the confidence of
+// the router in the "confidence" key. The chains of the routes are expected
+// to have the output keys of the default chain.
+type RouterChain struct {
+	// RouterLLMChain selects the route from the "destinations" and the
+	// "input", as a JSON object.
+	RouterLLMChain *LLMChain
+
+	// Routes are the destinations of the inputs.
+	Routes []Route
+
+	// DefaultChain is called with the inputs not suited for any route.
+	DefaultChain Chain
+
+	// MinConfidence is the minimum confidence of the router in the route
+	// selected for the inputs to be dispatched to it rather than to the
+	// default chain.
+	MinConfidence float64
+
+	// InputKey is the input key of the text classified, by default "input".
+	InputKey string
+}
+
+var _ Chain = RouterChain{}
+
+// NewRouterChain creates a new router chain selecting the routes with the llm.
+func NewRouterChain(llm llms.LanguageModel, routes []Route, defaultChain Chain) RouterChain {
+	return RouterChain{
+		RouterLLMChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_routerTemplate, []string{"destinations", "input"},
+		)),
+		Routes:       routes,
+		DefaultChain: defaultChain,
+		InputKey:     _routerDefaultInputKey,
+	}
+}
+
+// NewMultiPromptChain creates a new router chain dispatching the inputs to the
+// prompts of the routes, each with its own model, or to the llm without
+// prompt by default.
+func NewMultiPromptChain(llm llms.LanguageModel, promptRoutes []PromptRoute) RouterChain {
+	routes := make([]Route, 0, len(promptRoutes))
+	for _, route := range promptRoutes {
+		routeLLM := route.LLM
+		if routeLLM == nil {
+			routeLLM = llm
+		}
+		routes = append(routes, Route{
+			Name:        route.Name,
+			Description: route.Description,
+			Chain:       NewLLMChain(routeLLM, route.Prompt),
+		})
+	}
+	defaultChain := NewLLMChain(llm, prompts.NewPromptTemplate(
+		_multiPromptDefaultTemplate, []string{"input"},
+	))
+
+	return NewRouterChain(llm, routes, defaultChain)
+}
+
+// Call routes the input values to the chain of the route selected by the
+// router.
+func (c RouterChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	var destinations strings.Builder
+	for _, route := range c.Routes {
+		fmt.Fprintf(&destinations, "%s: %s\n", route.Name, route.Description)
+	}
+	text, err := Predict(ctx, c.RouterLLMChain, map[string]any{
+		"destinations": destinations.String(),
+		"input":        input,
+	}, withoutStreaming(options)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var output struct {
+		Destination string   `json:"destination"`
+		Confidence  *float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(_jsonObjectRegex.FindString(text)), &output); err != nil {
+		return nil, fmt.Errorf("%w: router output %q: %w", ErrInvalidOutputValues, text, err)
+	}
+	confidence := 1.0
+	if output.Confidence != nil {
+		confidence = *output.Confidence
+	}
+
+	destination, chain := _routerDefaultDestination, c.DefaultChain
+	if confidence >= c.MinConfidence {
+		for _, route := range c.Routes {
+			if route.Name == strings.TrimSpace(output.Destination) {
+				destination, chain = route.Name, route.Chain
+				break
+			}
+		}
+	}
+
+	result, err := Call(ctx, chain, values, options...)
+	if err != nil {
+		return nil, err
+	}
+	result[_routerDestinationOutputKey] = destination
+	result[_routerConfidenceOutputKey] = confidence
+
+	return result, nil
+}
+
+// GetMemory returns a simple memory.
+func (c RouterChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the input key of the router and the input keys of the
+// default chain.
+func (c RouterChain) GetInputKeys() []string {
+	inputKeys := []string{c.InputKey}
+	for _, key := range c.DefaultChain.GetInputKeys() {
+		if key != c.InputKey {
+			inputKeys = append(inputKeys, key)
+		}
+	}
+	return inputKeys
+}
+
+// GetOutputKeys returns the output keys of the default chain, with the
+// "destination" and the "confidence" keys.
+func (c RouterChain) GetOutputKeys() []string {
+	outputKeys := append([]string{}, c.DefaultChain.GetOutputKeys()...)
+	return append(outputKeys, _routerDestinationOutputKey, _routerConfidenceOutputKey)
+}
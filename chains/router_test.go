@@ -0,0 +1,69 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestMultiPromptChain(t *testing.T) {
+	t.Parallel()
+
+	router := &sequenceLanguageModel{responses: []string{
+		`{"destination": "physics", "confidence": 0.9}`,
+		`{"destination": "math", "confidence": 0.2}`,
+		`{"destination": "DEFAULT"}`,
+	}}
+	chain := NewMultiPromptChain(router, []PromptRoute{
+		{
+			Name:        "physics",
+			Description: "Good for answering questions about physics",
+			Prompt:      prompts.NewPromptTemplate("physicist: {{.input}}", []string{"input"}),
+			LLM:         &testLanguageModel{},
+		},
+		{
+			Name:        "math",
+			Description: "Good for answering math questions",
+			Prompt:      prompts.NewPromptTemplate("mathematician: {{.input}}", []string{"input"}),
+			LLM:         &testLanguageModel{},
+		},
+	})
+	chain.MinConfidence = 0.5
+	chain.DefaultChain = NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+
+	result, err := Call(context.Background(), chain, map[string]any{"input": "What is black body radiation?"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"text":        "physicist: What is black body radiation?",
+		"destination": "physics",
+		"confidence":  0.9,
+	}, result)
+	require.Contains(t, router.prompts[0], "math: Good for answering math questions")
+
+	// The routes selected with a low confidence fall back to the default chain.
+	result, err = Call(context.Background(), chain, map[string]any{"input": "What is 2 + 2?"})
+	require.NoError(t, err)
+	require.Equal(t, "What is 2 + 2?", result["text"])
+	require.Equal(t, "DEFAULT", result["destination"])
+	require.Equal(t, 0.2, result["confidence"])
+
+	result, err = Call(context.Background(), chain, map[string]any{"input": "Hello"})
+	require.NoError(t, err)
+	require.Equal(t, "Hello", result["text"])
+	require.Equal(t, "DEFAULT", result["destination"])
+	require.Equal(t, 1.0, result["confidence"])
+}
+
+func TestRouterChainInvalidOutput(t *testing.T) {
+	t.Parallel()
+
+	chain := NewRouterChain(
+		&sequenceLanguageModel{responses: []string{"physics"}},
+		nil,
+		NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.input}}", []string{"input"})),
+	)
+	_, err := Call(context.Background(), chain, map[string]any{"input": "What is black body radiation?"})
+	require.ErrorIs(t, err, ErrInvalidOutputValues)
+}
@@ -60,12 +60,12 @@ func (c *SequentialChain) validateSeqChain() error {
 	}
 
 	for i, c := range c.chains {
-		// Check that chain has input keys that are in knownKeys
-		missingKeys := util.Difference(c.GetInputKeys(), knownKeys)
+		// Check that chain has input keys that are in knownKeys or in its own memory
+		missingKeys := util.Difference(chainRequiredInputKeys(c), knownKeys)
 		if len(missingKeys) > 0 {
 			return fmt.Errorf(
-				"%w: missing required input keys: [%v], only had: [%v]",
-				ErrChainInitialization, strings.Join(missingKeys, delimiter), strings.Join(util.ListKeys(knownKeys), delimiter),
+				"%w: chain at index %d is missing required input keys: [%v], only had: [%v]",
+				ErrChainInitialization, i, strings.Join(missingKeys, delimiter), strings.Join(util.ListKeys(knownKeys), delimiter),
 			)
 		}
 
@@ -94,19 +94,40 @@ func (c *SequentialChain) validateSeqChain() error {
 	return nil
 }
 
+// chainRequiredInputKeys returns the input keys of the chain not given by its
+// memory.
+func chainRequiredInputKeys(c Chain) []string {
+	memoryKeys := util.ToSet(c.GetMemory().MemoryVariables(context.Background()))
+	return util.Difference(c.GetInputKeys(), memoryKeys)
+}
+
 // Call runs the logic of the chains and returns the outputs. This method should
 // not be called directly. Use rather the Call, Run or Predict functions that
 // handles the memory and other aspects of the chain.
 func (c *SequentialChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
-	var outputs map[string]any
-	var err error
-	for _, chain := range c.chains {
-		outputs, err = Call(ctx, chain, inputs, options...)
+	// The chains are given the inputs and the outputs of all the previous chains.
+	knownValues := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		knownValues[key] = value
+	}
+	for i, chain := range c.chains {
+		// Only the last chain streams to the caller.
+		chainOptions := options
+		if i < len(c.chains)-1 {
+			chainOptions = withoutStreaming(options)
+		}
+		outputs, err := Call(ctx, chain, knownValues, chainOptions...)
 		if err != nil {
 			return nil, err
 		}
-		// Set the input for the next chain to the output of the current chain
-		inputs = outputs
+		for key, value := range outputs {
+			knownValues[key] = value
+		}
+	}
+
+	outputs := make(map[string]any, len(c.outputKeys))
+	for _, key := range c.outputKeys {
+		outputs[key] = knownValues[key]
 	}
 	return outputs, nil
 }
@@ -176,9 +197,14 @@ func validateSimpleSeq(chains []Chain) error {
 // Use the Run function that handles the memory and other aspects of the chain.
 func (c *SimpleSequentialChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
 	input := inputs[input]
-	for _, chain := range c.chains {
+	for i, chain := range c.chains {
+		// Only the last chain streams to the caller.
+		chainOptions := options
+		if i < len(c.chains)-1 {
+			chainOptions = withoutStreaming(options)
+		}
 		var err error
-		input, err = Run(ctx, chain, input, options...)
+		input, err = Run(ctx, chain, input, chainOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -194,6 +195,99 @@ func TestSequentialChainErrors(t *testing.T) {
 	}
 }
 
+func TestSequentialChainFanOutFanIn(t *testing.T) {
+	t.Parallel()
+
+	storyChain := NewLLMChain(
+		&testLanguageModel{expResult: "chickens have taken over the world"},
+		prompts.NewPromptTemplate("Write a story titled {{.title}}", []string{"title"}),
+	)
+	storyChain.OutputKey = "story"
+	reviewChain := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("Review: {{.story}}", []string{"story"}))
+	reviewChain.OutputKey = "review"
+	summaryChain := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("Summary: {{.story}}", []string{"story"}))
+	summaryChain.OutputKey = "summary"
+	parallelChain, err := NewParallelChain(map[string]Chain{"review": reviewChain, "summary": summaryChain})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"story"}, parallelChain.GetInputKeys())
+	assert.Equal(t, []string{"review", "summary"}, parallelChain.GetOutputKeys())
+
+	// The last chain is given the inputs and the outputs of all the previous chains.
+	publishChain := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate(
+		"{{.title}}. {{.review}}. {{.summary}}", []string{"title", "review", "summary"},
+	))
+
+	seqChain, err := NewSequentialChain(
+		[]Chain{storyChain, parallelChain, publishChain},
+		[]string{"title"},
+		[]string{"summary", _llmChainDefaultOutputKey},
+	)
+	require.NoError(t, err)
+
+	res, err := Call(context.Background(), seqChain, map[string]any{"title": "Chicken Takeover"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"summary": "Summary: chickens have taken over the world",
+		_llmChainDefaultOutputKey: "Chicken Takeover. Review: chickens have taken over the world. " +
+			"Summary: chickens have taken over the world",
+	}, res)
+}
+
+func TestParallelChainErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewParallelChain(nil)
+	assert.ErrorIs(t, err, ErrChainInitialization)
+
+	_, err = NewParallelChain(map[string]Chain{
+		"a": &testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}},
+		"b": &testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}},
+	})
+	assert.ErrorIs(t, err, ErrChainInitialization)
+
+	c, err := NewParallelChain(map[string]Chain{
+		"a": &testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output1"}, err: errDummy},
+		"b": &testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output2"}, err: errDummy},
+	})
+	require.NoError(t, err)
+	_, err = Call(context.Background(), c, map[string]any{"input": "foo"})
+	assert.ErrorIs(t, err, errDummy)
+}
+
+func TestSequentialChainStreaming(t *testing.T) {
+	t.Parallel()
+
+	chains := []Chain{
+		NewLLMChain(
+			&testLanguageModel{expResult: "the chicken crossed the road"},
+			prompts.NewPromptTemplate("{{.input}}", []string{"input"}),
+		),
+		NewLLMChain(
+			&testLanguageModel{expResult: "The chicken made it to the other side"},
+			prompts.NewPromptTemplate("What happened after {{.output}}?", []string{"output"}),
+		),
+	}
+	simpleSeqChain, err := NewSimpleSequentialChain(chains)
+	require.NoError(t, err)
+
+	// Only the output of the last chain streams to the caller.
+	var chunks []string
+	var structuredChunks []llms.StreamChunk
+	_, err = Run(context.Background(), simpleSeqChain, "What did the chicken do?",
+		WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+		WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			structuredChunks = append(structuredChunks, chunk)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"The chicken made it to the other side"}, chunks)
+	assert.Equal(t, []llms.StreamChunk{{Content: "The chicken made it to the other side"}}, structuredChunks)
+}
+
 // LLMChain for testing purposes.
 type testLLMChain struct {
 	err        error
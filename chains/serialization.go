@@ -0,0 +1,646 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// The types of the chain configurations, those of LangChain.
+const (
+	ChainTypeLLM                = "llm_chain"
+	ChainTypeStuffDocuments     = "stuff_documents_chain"
+	ChainTypeRefineDocuments    = "refine_documents_chain"
+	ChainTypeMapReduceDocuments = "map_reduce_documents_chain"
+	ChainTypeMapRerankDocuments = "map_rerank_documents_chain"
+	ChainTypeSequential         = "sequential_chain"
+	ChainTypeSimpleSequential   = "simple_sequential_chain"
+
+	_promptConfigType      = "prompt"
+	_simpleParserType      = "simple"
+	_regexParserConfigType = "regex_parser"
+)
+
+var (
+	// ErrChainNotSerializable is returned when saving a chain, or one of its
+	// prompts or output parsers, of a type without configuration.
+	ErrChainNotSerializable = errors.New("chain can not be serialized")
+	// ErrInvalidChainConfig is returned when loading an invalid chain
+	// configuration.
+	ErrInvalidChainConfig = errors.New("invalid chain config")
+)
+
+// ChainConfig is the configuration of a chain saved to and loaded from JSON
+// or YAML files, in the format of LangChain: the fields of the chain of the
+// type, the other fields being omitted.
+//
+//nolint:lll
+type ChainConfig struct {
+	Type string `json:"_type" yaml:"_type"`
+
+	// The fields of the LLM chains.
+	LLM          *LLMConfig          `json:"llm,omitempty" yaml:"llm,omitempty"`
+	Prompt       *PromptConfig       `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	OutputParser *OutputParserConfig `json:"output_parser,omitempty" yaml:"output_parser,omitempty"`
+	OutputKey    string              `json:"output_key,omitempty" yaml:"output_key,omitempty"`
+
+	// The fields of the combine documents chains.
+	LLMChain                   *ChainConfig  `json:"llm_chain,omitempty" yaml:"llm_chain,omitempty"`
+	RefineLLMChain             *ChainConfig  `json:"refine_llm_chain,omitempty" yaml:"refine_llm_chain,omitempty"`
+	ReduceChain                *ChainConfig  `json:"reduce_chain,omitempty" yaml:"reduce_chain,omitempty"`
+	CollapseChain              *ChainConfig  `json:"collapse_chain,omitempty" yaml:"collapse_chain,omitempty"`
+	DocumentPrompt             *PromptConfig `json:"document_prompt,omitempty" yaml:"document_prompt,omitempty"`
+	InputKey                   string        `json:"input_key,omitempty" yaml:"input_key,omitempty"`
+	DocumentVariableName       string        `json:"document_variable_name,omitempty" yaml:"document_variable_name,omitempty"`
+	Separator                  string        `json:"separator,omitempty" yaml:"separator,omitempty"`
+	InitialResponseName        string        `json:"initial_response_name,omitempty" yaml:"initial_response_name,omitempty"`
+	LLMChainInputVariableName  string        `json:"llm_chain_input_variable_name,omitempty" yaml:"llm_chain_input_variable_name,omitempty"`
+	ReduceDocumentVariableName string        `json:"reduce_document_variable_name,omitempty" yaml:"reduce_document_variable_name,omitempty"`
+	TokenMax                   int           `json:"token_max,omitempty" yaml:"token_max,omitempty"`
+	MaxConcurrency             int           `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty"`
+	RankKey                    string        `json:"rank_key,omitempty" yaml:"rank_key,omitempty"`
+	AnswerKey                  string        `json:"answer_key,omitempty" yaml:"answer_key,omitempty"`
+	MetadataKeys               []string      `json:"metadata_keys,omitempty" yaml:"metadata_keys,omitempty"`
+	ReturnIntermediateSteps    bool          `json:"return_intermediate_steps,omitempty" yaml:"return_intermediate_steps,omitempty"`
+
+	// The fields of the sequential chains.
+	Chains          []ChainConfig `json:"chains,omitempty" yaml:"chains,omitempty"`
+	InputVariables  []string      `json:"input_variables,omitempty" yaml:"input_variables,omitempty"`
+	OutputVariables []string      `json:"output_variables,omitempty" yaml:"output_variables,omitempty"`
+}
+
+// LLMConfig is the configuration of a model, its type, the name of its loader
+// registered with RegisterLLMLoader, and the options of its calls.
+type LLMConfig struct {
+	Type        string   `json:"_type" yaml:"_type"`
+	Model       string   `json:"model,omitempty" yaml:"model,omitempty"`
+	Temperature float64  `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	TopP        float64  `json:"top_p,omitempty" yaml:"top_p,omitempty"`
+	TopK        int      `json:"top_k,omitempty" yaml:"top_k,omitempty"`
+	StopWords   []string `json:"stop_words,omitempty" yaml:"stop_words,omitempty"`
+	// Options are the options of the loader, e.g. the URL of a server.
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// PromptConfig is the configuration of a prompt template.
+type PromptConfig struct {
+	Type             string         `json:"_type" yaml:"_type"`
+	Template         string         `json:"template" yaml:"template"`
+	InputVariables   []string       `json:"input_variables" yaml:"input_variables"`
+	TemplateFormat   string         `json:"template_format,omitempty" yaml:"template_format,omitempty"`
+	PartialVariables map[string]any `json:"partial_variables,omitempty" yaml:"partial_variables,omitempty"`
+}
+
+// OutputParserConfig is the configuration of the output parser of an LLM
+// chain, "simple" or "regex_parser".
+type OutputParserConfig struct {
+	Type  string `json:"_type" yaml:"_type"`
+	Regex string `json:"regex,omitempty" yaml:"regex,omitempty"`
+}
+
+// LLMLoaderFunc is the function that creates the model of a configuration.
+type LLMLoaderFunc func(config LLMConfig) (llms.LanguageModel, error)
+
+//nolint:gochecknoglobals
+var llmLoaders = make(map[string]LLMLoaderFunc)
+
+// RegisterLLMLoader registers the loader of the models of the type, e.g.
+//
+//	chains.RegisterLLMLoader("openai", func(c chains.LLMConfig) (llms.LanguageModel, error) {
+//		return openai.New(openai.WithModel(c.Model))
+//	})
+func RegisterLLMLoader(llmType string, loader LLMLoaderFunc) {
+	llmLoaders[llmType] = loader
+}
+
+// NewLLMFromConfig creates the model of the configuration with its registered
+// loader. The model is called with the options of the configuration, unless
+// set in the calls, and its configuration is saved with the chains.
+func NewLLMFromConfig(config LLMConfig) (llms.LanguageModel, error) { //nolint:ireturn
+	loader, ok := llmLoaders[config.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: no loader registered for the models of type %q", ErrInvalidChainConfig, config.Type)
+	}
+	llm, err := loader(config)
+	if err != nil {
+		return nil, err
+	}
+	return configuredLLM{LanguageModel: llm, config: config}, nil
+}
+
+// configuredLLM is a model created from a configuration.
+type configuredLLM struct {
+	llms.LanguageModel
+	config LLMConfig
+}
+
+// GeneratePrompt calls the model with the options of the configuration not set
+// in the call.
+func (m configuredLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	options = append(options, func(o *llms.CallOptions) {
+		if o.Model == "" {
+			o.Model = m.config.Model
+		}
+		if o.Temperature == 0 {
+			o.Temperature = m.config.Temperature
+		}
+		if o.MaxTokens == 0 {
+			o.MaxTokens = m.config.MaxTokens
+		}
+		if o.TopP == 0 {
+			o.TopP = m.config.TopP
+		}
+		if o.TopK == 0 {
+			o.TopK = m.config.TopK
+		}
+		if len(o.StopWords) == 0 {
+			o.StopWords = m.config.StopWords
+		}
+	})
+	return m.LanguageModel.GeneratePrompt(ctx, promptValues, options...)
+}
+
+// SaveChain saves the configuration of the chain to the file of the path, in
+// YAML if its extension is .yaml or .yml and in JSON otherwise. The models not
+// created with NewLLMFromConfig are not saved, see WithDefaultLLM, nor are
+// the memories.
+func SaveChain(path string, c Chain) error {
+	config, err := ChainToConfig(c)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = marshalYAML(config)
+	default:
+		data, err = json.MarshalIndent(config, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// marshalYAML encodes the configuration in YAML through JSON, as the YAML
+// encoder drops the leading line breaks of the strings, e.g. of the
+// separators, keeping these strings double quoted.
+func marshalYAML(config ChainConfig) ([]byte, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	restyleYAML(&node)
+	return yaml.Marshal(&node)
+}
+
+// restyleYAML replaces the flow style of the nodes decoded from JSON with the
+// block style, the multiline strings being literal.
+func restyleYAML(node *yaml.Node) {
+	switch {
+	case node.Kind != yaml.ScalarNode:
+		node.Style = 0
+	case node.Tag != "!!str":
+		node.Style = 0
+	case strings.TrimLeft(node.Value, " \t\n") != node.Value:
+		// The strings with leading spaces stay double quoted.
+	case strings.Contains(node.Value, "\n"):
+		node.Style = yaml.LiteralStyle
+	default:
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		restyleYAML(child)
+	}
+}
+
+// LoadOption is a function that can be used to modify the loading of chains.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	llm llms.LanguageModel
+}
+
+// WithDefaultLLM is an option for setting the model of the LLM chains of the
+// configurations without model configurations.
+func WithDefaultLLM(llm llms.LanguageModel) LoadOption {
+	return func(o *loadOptions) {
+		o.llm = llm
+	}
+}
+
+// LoadChain loads the chain of the configuration of the file of the path, in
+// JSON or YAML.
+func LoadChain(path string, options ...LoadOption) (Chain, error) { //nolint:ireturn
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config ChainConfig
+	// YAML is a superset of JSON.
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidChainConfig, err)
+	}
+	return ChainFromConfig(config, options...)
+}
+
+// ChainToConfig returns the configuration of the chain.
+func ChainToConfig(c Chain) (ChainConfig, error) { //nolint:cyclop
+	switch c := c.(type) {
+	case *LLMChain:
+		return llmChainToConfig(c)
+	case LLMChain:
+		return llmChainToConfig(&c)
+	case *StuffDocuments:
+		return ChainToConfig(*c)
+	case StuffDocuments:
+		llmChain, err := llmChainToConfig(c.LLMChain)
+		if err != nil {
+			return ChainConfig{}, err
+		}
+		return ChainConfig{
+			Type:                 ChainTypeStuffDocuments,
+			LLMChain:             &llmChain,
+			InputKey:             c.InputKey,
+			DocumentVariableName: c.DocumentVariableName,
+			Separator:            c.Separator,
+		}, nil
+	case RefineDocuments:
+		return refineDocumentsToConfig(c)
+	case *RefineDocuments:
+		return refineDocumentsToConfig(*c)
+	case MapReduceDocuments:
+		return mapReduceDocumentsToConfig(c)
+	case *MapReduceDocuments:
+		return mapReduceDocumentsToConfig(*c)
+	case MapRerankDocuments:
+		return mapRerankDocumentsToConfig(c)
+	case *MapRerankDocuments:
+		return mapRerankDocumentsToConfig(*c)
+	case *SequentialChain:
+		chains, err := chainsToConfigs(c.chains)
+		if err != nil {
+			return ChainConfig{}, err
+		}
+		return ChainConfig{
+			Type:            ChainTypeSequential,
+			Chains:          chains,
+			InputVariables:  c.inputKeys,
+			OutputVariables: c.outputKeys,
+		}, nil
+	case *SimpleSequentialChain:
+		chains, err := chainsToConfigs(c.chains)
+		if err != nil {
+			return ChainConfig{}, err
+		}
+		return ChainConfig{Type: ChainTypeSimpleSequential, Chains: chains}, nil
+	default:
+		return ChainConfig{}, fmt.Errorf("%w: %T", ErrChainNotSerializable, c)
+	}
+}
+
+func llmChainToConfig(c *LLMChain) (ChainConfig, error) {
+	if c == nil {
+		return ChainConfig{}, fmt.Errorf("%w: missing LLM chain", ErrChainNotSerializable)
+	}
+	prompt, err := promptToConfig(c.Prompt)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	config := ChainConfig{Type: ChainTypeLLM, Prompt: &prompt, OutputKey: c.OutputKey}
+	if llm, ok := c.LLM.(configuredLLM); ok {
+		llmConfig := llm.config
+		config.LLM = &llmConfig
+	}
+
+	switch parser := c.OutputParser.(type) {
+	case nil, outputparser.Simple:
+	case outputparser.RegexParser:
+		config.OutputParser = &OutputParserConfig{Type: _regexParserConfigType, Regex: parser.Expression.String()}
+	default:
+		return ChainConfig{}, fmt.Errorf("%w: output parser %T", ErrChainNotSerializable, parser)
+	}
+	return config, nil
+}
+
+func promptToConfig(prompt prompts.FormatPrompter) (PromptConfig, error) {
+	var template prompts.PromptTemplate
+	switch p := prompt.(type) {
+	case prompts.PromptTemplate:
+		template = p
+	case *prompts.PromptTemplate:
+		template = *p
+	default:
+		return PromptConfig{}, fmt.Errorf("%w: prompt %T", ErrChainNotSerializable, prompt)
+	}
+	if template.OutputParser != nil {
+		return PromptConfig{}, fmt.Errorf("%w: prompt with an output parser", ErrChainNotSerializable)
+	}
+	for name, value := range template.PartialVariables {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return PromptConfig{}, fmt.Errorf("%w: partial variable %q of type %T", ErrChainNotSerializable, name, value)
+		}
+	}
+	return PromptConfig{
+		Type:             _promptConfigType,
+		Template:         template.Template,
+		InputVariables:   template.InputVariables,
+		TemplateFormat:   string(template.TemplateFormat),
+		PartialVariables: template.PartialVariables,
+	}, nil
+}
+
+func refineDocumentsToConfig(c RefineDocuments) (ChainConfig, error) {
+	llmChain, err := llmChainToConfig(c.LLMChain)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	refineLLMChain, err := llmChainToConfig(c.RefineLLMChain)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	documentPrompt, err := promptToConfig(c.DocumentPrompt)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	return ChainConfig{
+		Type:                    ChainTypeRefineDocuments,
+		LLMChain:                &llmChain,
+		RefineLLMChain:          &refineLLMChain,
+		DocumentPrompt:          &documentPrompt,
+		InputKey:                c.InputKey,
+		OutputKey:               c.OutputKey,
+		DocumentVariableName:    c.DocumentVariableName,
+		InitialResponseName:     c.InitialResponseName,
+		ReturnIntermediateSteps: c.ReturnIntermediateSteps,
+	}, nil
+}
+
+func mapReduceDocumentsToConfig(c MapReduceDocuments) (ChainConfig, error) {
+	llmChain, err := llmChainToConfig(c.LLMChain)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	reduceChain, err := ChainToConfig(c.ReduceChain)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	config := ChainConfig{
+		Type:                       ChainTypeMapReduceDocuments,
+		LLMChain:                   &llmChain,
+		ReduceChain:                &reduceChain,
+		InputKey:                   c.InputKey,
+		LLMChainInputVariableName:  c.LLMChainInputVariableName,
+		ReduceDocumentVariableName: c.ReduceDocumentVariableName,
+		TokenMax:                   c.TokenMax,
+		MaxConcurrency:             c.MaxNumberOfConcurrent,
+		ReturnIntermediateSteps:    c.ReturnIntermediateSteps,
+	}
+	if c.CollapseChain != nil {
+		collapseChain, err := ChainToConfig(c.CollapseChain)
+		if err != nil {
+			return ChainConfig{}, err
+		}
+		config.CollapseChain = &collapseChain
+	}
+	return config, nil
+}
+
+func mapRerankDocumentsToConfig(c MapRerankDocuments) (ChainConfig, error) {
+	llmChain, err := llmChainToConfig(c.LLMChain)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	// The output parser is set by NewMapRerankDocuments.
+	llmChain.OutputParser = nil
+	return ChainConfig{
+		Type:                      ChainTypeMapRerankDocuments,
+		LLMChain:                  &llmChain,
+		InputKey:                  c.InputKey,
+		OutputKey:                 c.OutputKey,
+		DocumentVariableName:      c.DocumentVariableName,
+		LLMChainInputVariableName: c.LLMChainInputVariableName,
+		MaxConcurrency:            c.MaxConcurrentWorkers,
+		RankKey:                   c.RankKey,
+		AnswerKey:                 c.AnswerKey,
+		MetadataKeys:              c.MetadataKeys,
+		ReturnIntermediateSteps:   c.ReturnIntermediateSteps,
+	}, nil
+}
+
+func chainsToConfigs(chains []Chain) ([]ChainConfig, error) {
+	configs := make([]ChainConfig, 0, len(chains))
+	for _, c := range chains {
+		config, err := ChainToConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// ChainFromConfig creates the chain of the configuration, with the defaults of
+// the constructors of the chains for the fields omitted.
+func ChainFromConfig(config ChainConfig, options ...LoadOption) (Chain, error) { //nolint:ireturn,cyclop
+	opts := loadOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	switch config.Type {
+	case ChainTypeLLM:
+		c, err := llmChainFromConfig(&config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case ChainTypeStuffDocuments:
+		llmChain, err := llmChainFromConfig(config.LLMChain, opts)
+		if err != nil {
+			return nil, err
+		}
+		c := NewStuffDocuments(llmChain)
+		setIfNotEmpty(&c.InputKey, config.InputKey)
+		setIfNotEmpty(&c.DocumentVariableName, config.DocumentVariableName)
+		setIfNotEmpty(&c.Separator, config.Separator)
+		return c, nil
+	case ChainTypeRefineDocuments:
+		return refineDocumentsFromConfig(config, opts)
+	case ChainTypeMapReduceDocuments:
+		return mapReduceDocumentsFromConfig(config, options, opts)
+	case ChainTypeMapRerankDocuments:
+		llmChain, err := llmChainFromConfig(config.LLMChain, opts)
+		if err != nil {
+			return nil, err
+		}
+		c := NewMapRerankDocuments(llmChain)
+		setIfNotEmpty(&c.InputKey, config.InputKey)
+		setIfNotEmpty(&c.OutputKey, config.OutputKey)
+		setIfNotEmpty(&c.DocumentVariableName, config.DocumentVariableName)
+		setIfNotEmpty(&c.LLMChainInputVariableName, config.LLMChainInputVariableName)
+		setIfNotEmpty(&c.RankKey, config.RankKey)
+		setIfNotEmpty(&c.AnswerKey, config.AnswerKey)
+		if config.MaxConcurrency > 0 {
+			c.MaxConcurrentWorkers = config.MaxConcurrency
+		}
+		c.MetadataKeys = config.MetadataKeys
+		c.ReturnIntermediateSteps = config.ReturnIntermediateSteps
+		return c, nil
+	case ChainTypeSequential, ChainTypeSimpleSequential:
+		chains := make([]Chain, 0, len(config.Chains))
+		for _, chainConfig := range config.Chains {
+			c, err := ChainFromConfig(chainConfig, options...)
+			if err != nil {
+				return nil, err
+			}
+			chains = append(chains, c)
+		}
+		if config.Type == ChainTypeSimpleSequential {
+			c, err := NewSimpleSequentialChain(chains)
+			if err != nil {
+				return nil, err
+			}
+			return c, nil
+		}
+		c, err := NewSequentialChain(chains, config.InputVariables, config.OutputVariables)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown chain type %q", ErrInvalidChainConfig, config.Type)
+	}
+}
+
+func llmChainFromConfig(config *ChainConfig, opts loadOptions) (*LLMChain, error) {
+	if config == nil || config.Type != ChainTypeLLM {
+		return nil, fmt.Errorf("%w: missing LLM chain", ErrInvalidChainConfig)
+	}
+	if config.Prompt == nil {
+		return nil, fmt.Errorf("%w: LLM chain without prompt", ErrInvalidChainConfig)
+	}
+	prompt, err := promptFromConfig(*config.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	llm := opts.llm
+	if config.LLM != nil {
+		if llm, err = NewLLMFromConfig(*config.LLM); err != nil {
+			return nil, err
+		}
+	}
+	if llm == nil {
+		return nil, fmt.Errorf("%w: LLM chain without model, see WithDefaultLLM", ErrInvalidChainConfig)
+	}
+
+	c := NewLLMChain(llm, prompt)
+	setIfNotEmpty(&c.OutputKey, config.OutputKey)
+	if config.OutputParser != nil {
+		switch config.OutputParser.Type {
+		case _simpleParserType:
+		case _regexParserConfigType:
+			if _, err := regexp.Compile(config.OutputParser.Regex); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidChainConfig, err)
+			}
+			c.OutputParser = outputparser.NewRegexParser(config.OutputParser.Regex)
+		default:
+			return nil, fmt.Errorf("%w: unknown output parser type %q", ErrInvalidChainConfig, config.OutputParser.Type)
+		}
+	}
+	return c, nil
+}
+
+func promptFromConfig(config PromptConfig) (prompts.PromptTemplate, error) {
+	if config.Type != "" && config.Type != _promptConfigType {
+		return prompts.PromptTemplate{}, fmt.Errorf("%w: unknown prompt type %q", ErrInvalidChainConfig, config.Type)
+	}
+	prompt := prompts.NewPromptTemplate(config.Template, config.InputVariables)
+	if config.TemplateFormat != "" {
+		prompt.TemplateFormat = prompts.TemplateFormat(config.TemplateFormat)
+	}
+	prompt.PartialVariables = config.PartialVariables
+	if err := prompts.CheckValidTemplate(prompt.Template, prompt.TemplateFormat, prompt.InputVariables); err != nil {
+		return prompts.PromptTemplate{}, fmt.Errorf("%w: %w", ErrInvalidChainConfig, err)
+	}
+	return prompt, nil
+}
+
+func refineDocumentsFromConfig(config ChainConfig, opts loadOptions) (Chain, error) { //nolint:ireturn
+	llmChain, err := llmChainFromConfig(config.LLMChain, opts)
+	if err != nil {
+		return nil, err
+	}
+	refineLLMChain, err := llmChainFromConfig(config.RefineLLMChain, opts)
+	if err != nil {
+		return nil, err
+	}
+	c := NewRefineDocuments(llmChain, refineLLMChain)
+	if config.DocumentPrompt != nil {
+		if c.DocumentPrompt, err = promptFromConfig(*config.DocumentPrompt); err != nil {
+			return nil, err
+		}
+	}
+	setIfNotEmpty(&c.InputKey, config.InputKey)
+	setIfNotEmpty(&c.OutputKey, config.OutputKey)
+	setIfNotEmpty(&c.DocumentVariableName, config.DocumentVariableName)
+	setIfNotEmpty(&c.InitialResponseName, config.InitialResponseName)
+	c.ReturnIntermediateSteps = config.ReturnIntermediateSteps
+	return c, nil
+}
+
+func mapReduceDocumentsFromConfig(config ChainConfig, options []LoadOption, opts loadOptions) (Chain, error) { //nolint:ireturn,lll
+	llmChain, err := llmChainFromConfig(config.LLMChain, opts)
+	if err != nil {
+		return nil, err
+	}
+	if config.ReduceChain == nil {
+		return nil, fmt.Errorf("%w: map reduce documents chain without reduce chain", ErrInvalidChainConfig)
+	}
+	reduceChain, err := ChainFromConfig(*config.ReduceChain, options...)
+	if err != nil {
+		return nil, err
+	}
+	c := NewMapReduceDocuments(llmChain, reduceChain)
+	if config.CollapseChain != nil {
+		if c.CollapseChain, err = ChainFromConfig(*config.CollapseChain, options...); err != nil {
+			return nil, err
+		}
+	}
+	setIfNotEmpty(&c.InputKey, config.InputKey)
+	setIfNotEmpty(&c.LLMChainInputVariableName, config.LLMChainInputVariableName)
+	setIfNotEmpty(&c.ReduceDocumentVariableName, config.ReduceDocumentVariableName)
+	if config.TokenMax > 0 {
+		c.TokenMax = config.TokenMax
+	}
+	if config.MaxConcurrency > 0 {
+		c.MaxNumberOfConcurrent = config.MaxConcurrency
+	}
+	c.ReturnIntermediateSteps = config.ReturnIntermediateSteps
+	return c, nil
+}
+
+func setIfNotEmpty(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
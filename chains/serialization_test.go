@@ -0,0 +1,111 @@
+package chains
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// optionsRecordingModel is a language model recording the options of its
+// calls.
+type optionsRecordingModel struct {
+	testLanguageModel
+	options llms.CallOptions
+}
+
+func (m *optionsRecordingModel) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	m.options = llms.CallOptions{}
+	for _, opt := range options {
+		opt(&m.options)
+	}
+	return m.testLanguageModel.GeneratePrompt(ctx, promptValues, options...)
+}
+
+func TestSaveAndLoadChain(t *testing.T) {
+	t.Parallel()
+
+	model := &optionsRecordingModel{}
+	RegisterLLMLoader("recording", func(LLMConfig) (llms.LanguageModel, error) {
+		return model, nil
+	})
+	llm, err := NewLLMFromConfig(LLMConfig{Type: "recording", Model: "large", Temperature: 0.2, MaxTokens: 256})
+	require.NoError(t, err)
+
+	mapChain := NewLLMChain(llm, prompts.NewPromptTemplate("Summarize: {{.context}}", []string{"context"}))
+	reduceChain := NewStuffDocuments(NewLLMChain(llm, prompts.NewPromptTemplate(
+		"Combine: {{.context}}", []string{"context"},
+	)))
+	reduceChain.Separator = "\n---\n"
+	mapReduce := NewMapReduceDocuments(mapChain, reduceChain)
+	mapReduce.TokenMax = 1000
+	sequential, err := NewSimpleSequentialChain([]Chain{
+		NewLLMChain(llm, prompts.NewPromptTemplate("Translate: {{.input}}", []string{"input"})),
+		NewLLMChain(llm, prompts.NewPromptTemplate("Correct: {{.input}}", []string{"input"})),
+	})
+	require.NoError(t, err)
+
+	for _, c := range []Chain{mapReduce, sequential} {
+		config, err := ChainToConfig(c)
+		require.NoError(t, err)
+		for _, name := range []string{"chain.json", "chain.yaml"} {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, SaveChain(path, c))
+			loaded, err := LoadChain(path)
+			require.NoError(t, err)
+			require.IsType(t, c, loaded)
+			loadedConfig, err := ChainToConfig(loaded)
+			require.NoError(t, err)
+			require.Equal(t, config, loadedConfig, name)
+		}
+	}
+
+	// The options of the configuration are the defaults of the calls.
+	data, err := os.ReadFile(filepath.Join("testdata", "llm_chain.yaml"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "llm_chain.yaml")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	loaded, err := LoadChain(path)
+	require.NoError(t, err)
+	result, err := Run(context.Background(), loaded, "lion")
+	require.NoError(t, err)
+	require.Equal(t, "Write a poem about a lion.", result)
+	require.Equal(t, "large", model.options.Model)
+	require.InDelta(t, 0.7, model.options.Temperature, 1e-9)
+
+	_, err = Call(context.Background(), loaded, map[string]any{"animal": "cat"}, WithTemperature(0.1))
+	require.NoError(t, err)
+	require.InDelta(t, 0.1, model.options.Temperature, 1e-9)
+}
+
+func TestLoadChainDefaultLLM(t *testing.T) {
+	t.Parallel()
+
+	c := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("Hello {{.name}}", []string{"name"}))
+	path := filepath.Join(t.TempDir(), "chain.json")
+	require.NoError(t, SaveChain(path, c))
+
+	_, err := LoadChain(path)
+	require.ErrorIs(t, err, ErrInvalidChainConfig)
+
+	loaded, err := LoadChain(path, WithDefaultLLM(&testLanguageModel{}))
+	require.NoError(t, err)
+	result, err := Run(context.Background(), loaded, "Leo")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Leo", result)
+}
+
+func TestSaveChainNotSerializable(t *testing.T) {
+	t.Parallel()
+
+	c := NewLLMChain(&testLanguageModel{}, prompts.NewChatPromptTemplate([]prompts.MessageFormatter{
+		prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}),
+	}))
+	err := SaveChain(filepath.Join(t.TempDir(), "chain.json"), c)
+	require.ErrorIs(t, err, ErrChainNotSerializable)
+}
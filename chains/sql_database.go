@@ -107,7 +107,7 @@ func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, optio
 	}
 
 	// Predict sql query
-	opt := append(options, WithStopWords([]string{stopWord})) //nolint:cyclop
+	opt := append(withoutStreaming(options), WithStopWords([]string{stopWord})) //nolint:cyclop
 	out, err := Predict(ctx, s.LLMChain, llmInputs, opt...)
 	if err != nil {
 		return nil, err
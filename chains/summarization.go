@@ -50,7 +50,9 @@ func LoadRefineSummarization(llm llms.LanguageModel) RefineDocuments {
 }
 
 // LoadMapReduceSummarization loads a map reduce documents chain for
-// summarization of documents.
+// summarization of documents. The documents are summarized in parallel, then
+// their summaries are summarized together, recursively while they do not fit
+// in the TokenMax of the chain.
 func LoadMapReduceSummarization(llm llms.LanguageModel) MapReduceDocuments {
 	mapChain := NewLLMChain(llm, prompts.NewPromptTemplate(
 		_stuffSummarizationTemplate, []string{"context"},
@@ -59,3 +61,14 @@ func LoadMapReduceSummarization(llm llms.LanguageModel) MapReduceDocuments {
 
 	return NewMapReduceDocuments(mapChain, combineChain)
 }
+
+// LoadCheckpointedMapReduceSummarization loads a map reduce documents chain
+// for the summarization of very long documents, storing the summary of each
+// document in the checkpoint store as it finishes. Called again with the same
+// documents after a failure, the chain only summarizes the documents without
+// a stored summary.
+func LoadCheckpointedMapReduceSummarization(llm llms.LanguageModel, checkpoints CheckpointStore) MapReduceDocuments {
+	chain := LoadMapReduceSummarization(llm)
+	chain.Checkpoints = checkpoints
+	return chain
+}
@@ -0,0 +1,183 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	_arxivURL            = "https://export.arxiv.org/api/query"
+	_defaultArxivMaxDocs = 3
+)
+
+// ErrArxivAPI is returned when the arXiv api responds with a non 200 status
+// code.
+var ErrArxivAPI = errors.New("unexpected response from arxiv api")
+
+// Arxiv loads paper abstracts from arXiv, either by searching for a query or
+// by arXiv id.
+type Arxiv struct {
+	query      string
+	ids        []string
+	maxDocs    int
+	apiURL     string
+	httpClient *http.Client
+}
+
+var _ Loader = Arxiv{}
+
+// ArxivOptions are options for the arXiv loader.
+type ArxivOptions func(a *Arxiv)
+
+// WithArxivIDs loads the papers with the given arXiv ids, e.g. "1706.03762",
+// instead of searching for the query.
+func WithArxivIDs(ids ...string) ArxivOptions {
+	return func(a *Arxiv) {
+		a.ids = ids
+	}
+}
+
+// WithArxivMaxDocs sets the number of search results to load. Defaults to 3.
+func WithArxivMaxDocs(maxDocs int) ArxivOptions {
+	return func(a *Arxiv) {
+		a.maxDocs = maxDocs
+	}
+}
+
+// NewArxiv creates a new arXiv loader returning the papers matching the
+// query. The query uses the arXiv search syntax, e.g. "ti:attention AND cat:cs.CL".
+func NewArxiv(query string, opts ...ArxivOptions) Arxiv {
+	a := Arxiv{
+		query:      query,
+		maxDocs:    _defaultArxivMaxDocs,
+		apiURL:     _arxivURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&a)
+	}
+	return a
+}
+
+type arxivFeed struct {
+	Entries []struct {
+		ID        string    `xml:"id"`
+		Title     string    `xml:"title"`
+		Summary   string    `xml:"summary"`
+		Published time.Time `xml:"published"`
+		Updated   time.Time `xml:"updated"`
+		Authors   []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Links []struct {
+			Href  string `xml:"href,attr"`
+			Rel   string `xml:"rel,attr"`
+			Title string `xml:"title,attr"`
+		} `xml:"link"`
+		PrimaryCategory struct {
+			Term string `xml:"term,attr"`
+		} `xml:"primary_category"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+// Load returns one document per paper with the abstract as content and the
+// title, authors, canonical url and categories as metadata.
+func (a Arxiv) Load(ctx context.Context) ([]schema.Document, error) {
+	params := make(url.Values)
+	if len(a.ids) > 0 {
+		params.Add("id_list", strings.Join(a.ids, ","))
+		params.Add("max_results", fmt.Sprint(len(a.ids)))
+	} else {
+		params.Add("search_query", a.query)
+		params.Add("max_results", fmt.Sprint(a.maxDocs))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request in arxiv: %w", err)
+	}
+
+	res, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doing request in arxiv: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("%w: status %d: %s", ErrArxivAPI, res.StatusCode, msg)
+	}
+
+	var feed arxivFeed
+	if err := xml.NewDecoder(res.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("unmarshal data in arxiv: %w", err)
+	}
+
+	docs := make([]schema.Document, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		authors := make([]string, 0, len(entry.Authors))
+		for _, author := range entry.Authors {
+			authors = append(authors, author.Name)
+		}
+		categories := make([]string, 0, len(entry.Categories))
+		for _, category := range entry.Categories {
+			categories = append(categories, category.Term)
+		}
+		var pdfURL string
+		for _, link := range entry.Links {
+			if link.Title == "pdf" {
+				pdfURL = link.Href
+			}
+		}
+
+		docs = append(docs, schema.Document{
+			PageContent: strings.TrimSpace(entry.Summary),
+			Metadata: map[string]any{
+				"title":            strings.Join(strings.Fields(entry.Title), " "),
+				"arxiv_id":         arxivID(entry.ID),
+				"source":           entry.ID,
+				"pdf_url":          pdfURL,
+				"authors":          authors,
+				"published":        entry.Published,
+				"updated":          entry.Updated,
+				"primary_category": entry.PrimaryCategory.Term,
+				"categories":       categories,
+			},
+		})
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit loads the abstracts and splits them into multiple documents
+// using a text splitter.
+func (a Arxiv) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := a.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// arxivID returns the id of a paper from its abstract url, e.g.
+// http://arxiv.org/abs/1706.03762v7.
+func arxivID(absURL string) string {
+	if i := strings.LastIndex(absURL, "/abs/"); i >= 0 {
+		return absURL[i+len("/abs/"):]
+	}
+	return absURL
+}
@@ -0,0 +1,71 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArxivLoader(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1706.03762", r.URL.Query().Get("id_list"))
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/1706.03762v7</id>
+    <published>2017-06-12T17:57:34Z</published>
+    <updated>2023-08-02T00:41:18Z</updated>
+    <title>Attention Is All
+      You Need</title>
+    <summary>  The dominant sequence transduction models...
+    </summary>
+    <author><name>Ashish Vaswani</name></author>
+    <author><name>Noam Shazeer</name></author>
+    <link href="http://arxiv.org/abs/1706.03762v7" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/1706.03762v7" rel="related" type="application/pdf"/>
+    <arxiv:primary_category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	loader := NewArxiv("", WithArxivIDs("1706.03762"))
+	loader.apiURL = server.URL
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	assert.Equal(t, "The dominant sequence transduction models...", docs[0].PageContent)
+	assert.Equal(t, "Attention Is All You Need", docs[0].Metadata["title"])
+	assert.Equal(t, "1706.03762v7", docs[0].Metadata["arxiv_id"])
+	assert.Equal(t, "http://arxiv.org/pdf/1706.03762v7", docs[0].Metadata["pdf_url"])
+	assert.Equal(t, []string{"Ashish Vaswani", "Noam Shazeer"}, docs[0].Metadata["authors"])
+	assert.Equal(t, "cs.CL", docs[0].Metadata["primary_category"])
+	assert.Equal(t, []string{"cs.CL", "cs.LG"}, docs[0].Metadata["categories"])
+}
+
+func TestArxivLoaderError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("rate exceeded"))
+	}))
+	defer server.Close()
+
+	loader := NewArxiv("attention")
+	loader.apiURL = server.URL
+
+	_, err := loader.Load(context.Background())
+	require.ErrorIs(t, err, ErrArxivAPI)
+	require.ErrorContains(t, err, "rate exceeded")
+}
@@ -0,0 +1,288 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	_githubTokenEnvVarName = "GITHUB_TOKEN" //nolint:gosec
+	_defaultGitHubAPIURL   = "https://api.github.com"
+	_githubPerPage         = 100
+)
+
+// ErrGitHubAPI is returned when the GitHub api responds with a non 200 status code.
+var ErrGitHubAPI = errors.New("unexpected response from github api")
+
+// nolint:gochecknoglobals
+var _githubNextLinkRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// GitHubIssues loads the issues and pull requests of a GitHub repository. Each
+// issue or pull request becomes a document containing the title, the
+// description and optionally the comments.
+type GitHubIssues struct {
+	owner string
+	repo  string
+
+	token           string
+	state           string
+	labels          []string
+	since           time.Time
+	includePRs      bool
+	includeComments bool
+	apiURL          string
+	httpClient      *http.Client
+}
+
+var _ Loader = GitHubIssues{}
+
+// GitHubIssuesOptions are options for the GitHub issues loader.
+type GitHubIssuesOptions func(g *GitHubIssues)
+
+// WithGitHubToken sets the token used to authenticate to the GitHub api. If
+// not set the token is read from the GITHUB_TOKEN environment variable.
+func WithGitHubToken(token string) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.token = token
+	}
+}
+
+// WithGitHubState sets the state of the issues to load: "open", "closed" or
+// "all". Defaults to "all".
+func WithGitHubState(state string) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.state = state
+	}
+}
+
+// WithGitHubLabels only loads issues with all the given labels.
+func WithGitHubLabels(labels ...string) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.labels = labels
+	}
+}
+
+// WithGitHubSince only loads issues updated at or after the given time. Use
+// the "updated_at" metadata of previously loaded documents to sync incrementally.
+func WithGitHubSince(since time.Time) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.since = since
+	}
+}
+
+// WithGitHubPullRequests sets whether pull requests are loaded. Defaults to true.
+func WithGitHubPullRequests(include bool) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.includePRs = include
+	}
+}
+
+// WithGitHubComments sets whether the comments are appended to the documents.
+// Defaults to true.
+func WithGitHubComments(include bool) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.includeComments = include
+	}
+}
+
+// WithGitHubAPIURL sets the url of the GitHub api, e.g. for GitHub Enterprise.
+func WithGitHubAPIURL(apiURL string) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+// WithGitHubHTTPClient sets the http client used to call the GitHub api.
+func WithGitHubHTTPClient(client *http.Client) GitHubIssuesOptions {
+	return func(g *GitHubIssues) {
+		g.httpClient = client
+	}
+}
+
+// NewGitHubIssues creates a new loader for the issues and pull requests of the
+// repository owner/repo.
+func NewGitHubIssues(owner, repo string, opts ...GitHubIssuesOptions) GitHubIssues {
+	g := GitHubIssues{
+		owner:           owner,
+		repo:            repo,
+		token:           os.Getenv(_githubTokenEnvVarName),
+		state:           "all",
+		includePRs:      true,
+		includeComments: true,
+		apiURL:          _defaultGitHubAPIURL,
+		httpClient:      http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return g
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Number    int        `json:"number"`
+	HTMLURL   string     `json:"html_url"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	User      githubUser `json:"user"`
+	Comments  int        `json:"comments"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+}
+
+type githubComment struct {
+	User      githubUser `json:"user"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Load fetches the issues and pull requests and returns one document for each.
+func (g GitHubIssues) Load(ctx context.Context) ([]schema.Document, error) {
+	params := make(url.Values)
+	params.Add("state", g.state)
+	params.Add("per_page", fmt.Sprint(_githubPerPage))
+	params.Add("sort", "updated")
+	params.Add("direction", "asc")
+	if len(g.labels) > 0 {
+		params.Add("labels", strings.Join(g.labels, ","))
+	}
+	if !g.since.IsZero() {
+		params.Add("since", g.since.UTC().Format(time.RFC3339))
+	}
+
+	var issues []githubIssue
+	reqURL := fmt.Sprintf("%s/repos/%s/%s/issues?%s", g.apiURL, g.owner, g.repo, params.Encode())
+	err := getAllGitHub(ctx, g, reqURL, &issues)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(issues))
+	for _, issue := range issues {
+		isPR := issue.PullRequest != nil
+		if isPR && !g.includePRs {
+			continue
+		}
+
+		content := issue.Title
+		if issue.Body != "" {
+			content += "\n\n" + issue.Body
+		}
+		if g.includeComments && issue.Comments > 0 {
+			comments, err := g.getComments(ctx, issue.Number)
+			if err != nil {
+				return nil, err
+			}
+			for _, comment := range comments {
+				content += fmt.Sprintf("\n\n%s: %s", comment.User.Login, comment.Body)
+			}
+		}
+
+		labels := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+
+		docs = append(docs, schema.Document{
+			PageContent: content,
+			Metadata: map[string]any{
+				"url":             issue.HTMLURL,
+				"number":          issue.Number,
+				"title":           issue.Title,
+				"state":           issue.State,
+				"author":          issue.User.Login,
+				"labels":          labels,
+				"is_pull_request": isPR,
+				"created_at":      issue.CreatedAt,
+				"updated_at":      issue.UpdatedAt,
+			},
+		})
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit fetches the issues and pull requests and splits them into
+// multiple documents using a text splitter.
+func (g GitHubIssues) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := g.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (g GitHubIssues) getComments(ctx context.Context, number int) ([]githubComment, error) {
+	var comments []githubComment
+	err := getAllGitHub(
+		ctx,
+		g,
+		fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?per_page=%d", g.apiURL, g.owner, g.repo, number, _githubPerPage),
+		&comments,
+	)
+	return comments, err
+}
+
+// getAllGitHub follows the pagination links of the GitHub api starting at
+// reqURL and appends the results of every page to out.
+func getAllGitHub[T any](ctx context.Context, g GitHubIssues, reqURL string, out *[]T) error {
+	for reqURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return fmt.Errorf("creating request in github: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		}
+
+		res, err := g.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("doing request in github: %w", err)
+		}
+
+		if res.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return fmt.Errorf("%w: status %d: %s", ErrGitHubAPI, res.StatusCode, msg)
+		}
+
+		var page []T
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("unmarshal data in github: %w", err)
+		}
+		*out = append(*out, page...)
+
+		reqURL = ""
+		if m := _githubNextLinkRegexp.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+			reqURL = m[1]
+		}
+	}
+
+	return nil
+}
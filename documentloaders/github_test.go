@@ -0,0 +1,58 @@
+package documentloaders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubIssuesLoader(t *testing.T) {
+	t.Parallel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/tmc/langchaingo/issues":
+			if r.URL.Query().Get("page") == "" {
+				assert.Equal(t, "2023-07-01T00:00:00Z", r.URL.Query().Get("since"))
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, server.URL, r.URL.Path))
+				_, _ = w.Write([]byte(`[{"number": 1, "title": "Bug", "body": "It breaks", "state": "open",
+					"user": {"login": "alice"}, "comments": 1, "labels": [{"name": "bug"}]}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"number": 2, "title": "Fix", "body": "Fixes #1", "state": "closed",
+				"user": {"login": "bob"}, "pull_request": {"url": "x"}}]`))
+		case "/repos/tmc/langchaingo/issues/1/comments":
+			_, _ = w.Write([]byte(`[{"user": {"login": "bob"}, "body": "Confirmed"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := NewGitHubIssues(
+		"tmc",
+		"langchaingo",
+		WithGitHubAPIURL(server.URL),
+		WithGitHubToken("token"),
+		WithGitHubSince(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Bug\n\nIt breaks\n\nbob: Confirmed", docs[0].PageContent)
+	assert.Equal(t, "alice", docs[0].Metadata["author"])
+	assert.Equal(t, []string{"bug"}, docs[0].Metadata["labels"])
+	assert.Equal(t, false, docs[0].Metadata["is_pull_request"])
+	assert.Equal(t, true, docs[1].Metadata["is_pull_request"])
+	assert.Equal(t, "closed", docs[1].Metadata["state"])
+}
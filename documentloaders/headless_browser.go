@@ -0,0 +1,176 @@
+package documentloaders
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const _defaultHeadlessBrowserTimeout = 30 * time.Second
+
+// HeadlessBrowser loads a web page by rendering it in a headless Chrome
+// browser using chromedp. Unlike the HTML loader it executes the JavaScript
+// of the page, so content rendered by single page applications is included.
+type HeadlessBrowser struct {
+	url string
+
+	waitSelector    string
+	waitNetworkIdle bool
+	timeout         time.Duration
+	userAgent       string
+	allocatorOpts   []chromedp.ExecAllocatorOption
+}
+
+var _ Loader = HeadlessBrowser{}
+
+// HeadlessBrowserOptions are options for the headless browser loader.
+type HeadlessBrowserOptions func(b *HeadlessBrowser)
+
+// WithWaitSelector waits until the element matching the css selector is
+// visible before extracting the content of the page.
+func WithWaitSelector(selector string) HeadlessBrowserOptions {
+	return func(b *HeadlessBrowser) {
+		b.waitSelector = selector
+	}
+}
+
+// WithWaitNetworkIdle waits until the page has had no network activity for
+// 500ms before extracting the content of the page.
+func WithWaitNetworkIdle() HeadlessBrowserOptions {
+	return func(b *HeadlessBrowser) {
+		b.waitNetworkIdle = true
+	}
+}
+
+// WithBrowserTimeout sets the maximum time spent loading the page. Defaults to 30 seconds.
+func WithBrowserTimeout(timeout time.Duration) HeadlessBrowserOptions {
+	return func(b *HeadlessBrowser) {
+		b.timeout = timeout
+	}
+}
+
+// WithBrowserUserAgent sets the user agent of the browser.
+func WithBrowserUserAgent(userAgent string) HeadlessBrowserOptions {
+	return func(b *HeadlessBrowser) {
+		b.userAgent = userAgent
+	}
+}
+
+// WithBrowserAllocatorOptions adds options used to start the browser, e.g.
+// chromedp.ExecPath to use a specific Chrome binary.
+func WithBrowserAllocatorOptions(opts ...chromedp.ExecAllocatorOption) HeadlessBrowserOptions {
+	return func(b *HeadlessBrowser) {
+		b.allocatorOpts = append(b.allocatorOpts, opts...)
+	}
+}
+
+// NewHeadlessBrowser creates a new headless browser loader for the url.
+func NewHeadlessBrowser(url string, opts ...HeadlessBrowserOptions) HeadlessBrowser {
+	b := HeadlessBrowser{
+		url:     url,
+		timeout: _defaultHeadlessBrowserTimeout,
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return b
+}
+
+// Load renders the page and returns a single document with the text of the
+// rendered DOM. The metadata holds the source url and the title of the page.
+func (b HeadlessBrowser) Load(ctx context.Context) ([]schema.Document, error) {
+	allocatorOpts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+	if b.userAgent != "" {
+		allocatorOpts = append(allocatorOpts, chromedp.UserAgent(b.userAgent))
+	}
+	allocatorOpts = append(allocatorOpts, b.allocatorOpts...)
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocatorOpts...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+	browserCtx, cancelTimeout := context.WithTimeout(browserCtx, b.timeout)
+	defer cancelTimeout()
+
+	var html, title string
+	if err := chromedp.Run(browserCtx, b.actions(browserCtx, &html, &title)...); err != nil {
+		return nil, err
+	}
+
+	docs, err := NewHTML(strings.NewReader(html)).Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].Metadata["source"] = b.url
+		docs[i].Metadata["title"] = title
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit renders the page and splits the text into multiple documents
+// using a text splitter.
+func (b HeadlessBrowser) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := b.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (b HeadlessBrowser) actions(ctx context.Context, html, title *string) []chromedp.Action {
+	actions := make([]chromedp.Action, 0)
+
+	if b.waitNetworkIdle {
+		// Lifecycle events of the initial blank page are ignored by only
+		// listening once the navigation to the url has started.
+		var navigating atomic.Bool
+		idle := make(chan struct{})
+		chromedp.ListenTarget(ctx, func(ev any) {
+			e, ok := ev.(*page.EventLifecycleEvent)
+			if !ok || e.Name != "networkIdle" || !navigating.Load() {
+				return
+			}
+			select {
+			case <-idle:
+			default:
+				close(idle)
+			}
+		})
+		actions = append(actions,
+			page.SetLifecycleEventsEnabled(true),
+			chromedp.ActionFunc(func(context.Context) error {
+				navigating.Store(true)
+				return nil
+			}),
+			chromedp.Navigate(b.url),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				select {
+				case <-idle:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}),
+		)
+	} else {
+		actions = append(actions, chromedp.Navigate(b.url))
+	}
+
+	if b.waitSelector != "" {
+		actions = append(actions, chromedp.WaitVisible(b.waitSelector, chromedp.ByQuery))
+	}
+
+	return append(actions,
+		chromedp.Title(title),
+		chromedp.OuterHTML("html", html, chromedp.ByQuery),
+	)
+}
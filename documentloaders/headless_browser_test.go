@@ -0,0 +1,44 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadlessBrowserLoader(t *testing.T) {
+	t.Parallel()
+
+	found := false
+	for _, name := range []string{"google-chrome", "chromium", "chromium-browser", "headless-shell"} {
+		if _, err := exec.LookPath(name); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Skip("chrome not found")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head><title>SPA</title></head><body><div id="app"></div>
+<script>setTimeout(function() {
+	document.getElementById("app").innerHTML = "<p id='content'>Rendered by JavaScript</p>";
+}, 100);</script></body></html>`))
+	}))
+	defer server.Close()
+
+	loader := NewHeadlessBrowser(server.URL, WithWaitSelector("#content"))
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	assert.Contains(t, docs[0].PageContent, "Rendered by JavaScript")
+	assert.Equal(t, "SPA", docs[0].Metadata["title"])
+	assert.Equal(t, server.URL, docs[0].Metadata["source"])
+}
@@ -0,0 +1,95 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// ErrUnsupportedImageFormat is returned when the image loader is given
+// something other than a PNG, JPEG or TIFF image.
+var ErrUnsupportedImageFormat = errors.New("unsupported image format, expected png, jpeg or tiff")
+
+// Image loads text from a PNG, JPEG or TIFF image using an OCR engine.
+type Image struct {
+	r      io.Reader
+	engine OCREngine
+}
+
+var _ Loader = Image{}
+
+// NewImage creates a new image loader with an io.Reader and the OCR engine
+// used to recognize the text.
+func NewImage(r io.Reader, engine OCREngine) Image {
+	return Image{
+		r:      r,
+		engine: engine,
+	}
+}
+
+// Load reads the image from the io.Reader and returns a single document with the
+// recognized text. The metadata holds the image format, the mean confidence
+// of the recognition and the recognized blocks with their bounding boxes.
+func (i Image) Load(ctx context.Context) ([]schema.Document, error) {
+	data, err := io.ReadAll(i.r)
+	if err != nil {
+		return nil, err
+	}
+
+	format := detectImageFormat(data)
+	if format == "" {
+		return nil, ErrUnsupportedImageFormat
+	}
+
+	result, err := i.engine.Recognize(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var confidence float64
+	for _, block := range result.Blocks {
+		confidence += block.Confidence
+	}
+	if len(result.Blocks) > 0 {
+		confidence /= float64(len(result.Blocks))
+	}
+
+	return []schema.Document{
+		{
+			PageContent: result.Text,
+			Metadata: map[string]any{
+				"format":     format,
+				"confidence": confidence,
+				"blocks":     result.Blocks,
+			},
+		},
+	}, nil
+}
+
+// LoadAndSplit recognizes the text of the image and splits it into multiple
+// documents using a text splitter.
+func (i Image) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := i.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func detectImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return "jpeg"
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return "tiff"
+	default:
+		return ""
+	}
+}
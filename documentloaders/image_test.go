@@ -0,0 +1,110 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testOCREngine struct {
+	result OCRResult
+}
+
+func (e testOCREngine) Recognize(_ context.Context, _ []byte) (OCRResult, error) {
+	return e.result, nil
+}
+
+func TestImageLoader(t *testing.T) {
+	t.Parallel()
+
+	engine := testOCREngine{result: OCRResult{
+		Text: "Name: John\nDate: 2023",
+		Blocks: []OCRBlock{
+			{Text: "Name: John", Confidence: 0.9, BoundingBox: BoundingBox{Left: 1, Top: 2, Width: 3, Height: 4}},
+			{Text: "Date: 2023", Confidence: 0.7},
+		},
+	}}
+
+	loader := NewImage(strings.NewReader("\x89PNG\r\n\x1a\nrest"), engine)
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	assert.Equal(t, "Name: John\nDate: 2023", docs[0].PageContent)
+	assert.Equal(t, "png", docs[0].Metadata["format"])
+	assert.InDelta(t, 0.8, docs[0].Metadata["confidence"], 0.0001)
+	assert.Len(t, docs[0].Metadata["blocks"], 2)
+}
+
+func TestImageLoaderUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	loader := NewImage(strings.NewReader("GIF89a"), testOCREngine{})
+	_, err := loader.Load(context.Background())
+	require.ErrorIs(t, err, ErrUnsupportedImageFormat)
+}
+
+func TestParseTesseractTSV(t *testing.T) {
+	t.Parallel()
+
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"1\t1\t0\t0\t0\t0\t0\t0\t100\t100\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t10\t10\t20\t10\t90\tHello\n" +
+		"5\t1\t1\t1\t1\t2\t35\t12\t25\t10\t80\tworld\n" +
+		"5\t1\t2\t1\t1\t1\t10\t40\t30\t10\t70\tBye\n"
+
+	result, err := parseTesseractTSV([]byte(tsv))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world\nBye", result.Text)
+	require.Len(t, result.Blocks, 2)
+	assert.InDelta(t, 0.85, result.Blocks[0].Confidence, 0.0001)
+	assert.Equal(t, BoundingBox{Left: 10, Top: 10, Width: 50, Height: 12}, result.Blocks[0].BoundingBox)
+}
+
+func TestGoogleVisionOCR(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.URL.Query().Get("key"))
+		_, _ = w.Write([]byte(`{"responses": [{"fullTextAnnotation": {"text": "Hi there\n", "pages": [{"blocks": [{
+			"confidence": 0.95,
+			"boundingBox": {"vertices": [{"x": 1, "y": 2}, {"x": 11, "y": 2}, {"x": 11, "y": 7}, {"x": 1, "y": 7}]},
+			"paragraphs": [{"words": [{"symbols": [{"text": "H"}, {"text": "i"}]}, {"symbols": [{"text": "there"}]}]}]
+		}]}]}}]}`))
+	}))
+	defer server.Close()
+
+	engine, err := NewGoogleVisionOCR("key")
+	require.NoError(t, err)
+	engine.url = server.URL
+
+	result, err := engine.Recognize(context.Background(), []byte("image"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi there", result.Text)
+	require.Len(t, result.Blocks, 1)
+	assert.Equal(t, "Hi there", result.Blocks[0].Text)
+	assert.Equal(t, BoundingBox{Left: 1, Top: 2, Width: 10, Height: 5}, result.Blocks[0].BoundingBox)
+}
+
+func TestGoogleVisionOCRError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "API key not valid.", "status": "PERMISSION_DENIED"}}`))
+	}))
+	defer server.Close()
+
+	engine, err := NewGoogleVisionOCR("key")
+	require.NoError(t, err)
+	engine.url = server.URL
+
+	_, err = engine.Recognize(context.Background(), []byte("image"))
+	require.ErrorIs(t, err, ErrGoogleVisionAPI)
+	require.ErrorContains(t, err, "status 403: API key not valid.")
+}
@@ -0,0 +1,349 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	_googleVisionAPIKeyEnvVarName = "GOOGLE_VISION_API_KEY" //nolint:gosec
+	_googleVisionURL              = "https://vision.googleapis.com/v1/images:annotate"
+	_defaultTesseractPath         = "tesseract"
+	_defaultTesseractLanguage     = "eng"
+	_tesseractTSVColumns          = 12
+)
+
+var (
+	// ErrMissingGoogleVisionAPIKey is returned when no api key is given for the google vision ocr engine.
+	ErrMissingGoogleVisionAPIKey = errors.New(
+		"missing the Google Vision API key, set it in the GOOGLE_VISION_API_KEY environment variable",
+	)
+	// ErrGoogleVisionAPI is returned when the google vision api returns an error.
+	ErrGoogleVisionAPI = errors.New("error from google vision api")
+)
+
+// BoundingBox is the position of a recognized block of text in an image, in pixels.
+type BoundingBox struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// OCRBlock is a block of text recognized in an image.
+type OCRBlock struct {
+	Text string `json:"text"`
+	// Confidence is the confidence of the recognition, between 0 and 1.
+	Confidence  float64     `json:"confidence"`
+	BoundingBox BoundingBox `json:"bounding_box"`
+}
+
+// OCRResult is the result of running optical character recognition over an image.
+type OCRResult struct {
+	Text   string
+	Blocks []OCRBlock
+}
+
+// OCREngine is the interface for engines that extract text from images.
+type OCREngine interface {
+	Recognize(ctx context.Context, image []byte) (OCRResult, error)
+}
+
+// TesseractOCR is an OCR engine that runs the tesseract command line tool.
+type TesseractOCR struct {
+	// Path is the path to the tesseract binary.
+	Path string
+	// Language is the tesseract language code, e.g. "eng" or "eng+deu".
+	Language string
+}
+
+var _ OCREngine = TesseractOCR{}
+
+// NewTesseractOCR creates a new tesseract engine using the tesseract binary
+// found in the PATH and the english language data.
+func NewTesseractOCR() TesseractOCR {
+	return TesseractOCR{
+		Path:     _defaultTesseractPath,
+		Language: _defaultTesseractLanguage,
+	}
+}
+
+// Recognize runs tesseract over the image and returns one block per line of text.
+func (t TesseractOCR) Recognize(ctx context.Context, image []byte) (OCRResult, error) {
+	cmd := exec.CommandContext(ctx, t.Path, "stdin", "stdout", "-l", t.Language, "tsv") //nolint:gosec
+	cmd.Stdin = bytes.NewReader(image)
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return OCRResult{}, fmt.Errorf("running tesseract: %w: %s", err, stderr.String())
+	}
+
+	return parseTesseractTSV(out)
+}
+
+type tesseractLine struct {
+	words      []string
+	confidence float64
+	box        BoundingBox
+}
+
+func parseTesseractTSV(data []byte) (OCRResult, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = '\t'
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+
+	lines := make([]*tesseractLine, 0)
+	lineIndex := make(map[string]*tesseractLine)
+	header := true
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return OCRResult{}, err
+		}
+		if header {
+			header = false
+			continue
+		}
+		if len(row) < _tesseractTSVColumns {
+			continue
+		}
+		text := strings.TrimSpace(row[11])
+		conf, err := strconv.ParseFloat(row[10], 64)
+		if err != nil || conf < 0 || text == "" {
+			continue
+		}
+
+		box, err := parseTesseractBox(row[6:10])
+		if err != nil {
+			return OCRResult{}, err
+		}
+
+		key := strings.Join(row[1:5], "-")
+		line, ok := lineIndex[key]
+		if !ok {
+			line = &tesseractLine{box: box}
+			lineIndex[key] = line
+			lines = append(lines, line)
+		}
+		line.words = append(line.words, text)
+		line.confidence += conf
+		line.box = unionBoundingBox(line.box, box)
+	}
+
+	result := OCRResult{Blocks: make([]OCRBlock, 0, len(lines))}
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		text := strings.Join(line.words, " ")
+		texts = append(texts, text)
+		result.Blocks = append(result.Blocks, OCRBlock{
+			Text:        text,
+			Confidence:  line.confidence / float64(len(line.words)) / 100, //nolint:gomnd
+			BoundingBox: line.box,
+		})
+	}
+	result.Text = strings.Join(texts, "\n")
+
+	return result, nil
+}
+
+func parseTesseractBox(fields []string) (BoundingBox, error) {
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("parsing tesseract bounding box: %w", err)
+		}
+		values[i] = v
+	}
+	return BoundingBox{Left: values[0], Top: values[1], Width: values[2], Height: values[3]}, nil
+}
+
+func unionBoundingBox(a, b BoundingBox) BoundingBox {
+	left := minInt(a.Left, b.Left)
+	top := minInt(a.Top, b.Top)
+	right := maxInt(a.Left+a.Width, b.Left+b.Width)
+	bottom := maxInt(a.Top+a.Height, b.Top+b.Height)
+	return BoundingBox{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+// GoogleVisionOCR is an OCR engine using the Google Cloud Vision api.
+type GoogleVisionOCR struct {
+	apiKey     string
+	url        string
+	httpClient *http.Client
+}
+
+var _ OCREngine = GoogleVisionOCR{}
+
+// NewGoogleVisionOCR creates a new google vision engine. If the api key is
+// empty it is read from the GOOGLE_VISION_API_KEY environment variable.
+func NewGoogleVisionOCR(apiKey string) (GoogleVisionOCR, error) {
+	if apiKey == "" {
+		apiKey = os.Getenv(_googleVisionAPIKeyEnvVarName)
+	}
+	if apiKey == "" {
+		return GoogleVisionOCR{}, ErrMissingGoogleVisionAPIKey
+	}
+	return GoogleVisionOCR{
+		apiKey:     apiKey,
+		url:        _googleVisionURL,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+type visionVertex struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type visionBoundingPoly struct {
+	Vertices []visionVertex `json:"vertices"`
+}
+
+type visionResponse struct {
+	Responses []struct {
+		FullTextAnnotation struct {
+			Text  string `json:"text"`
+			Pages []struct {
+				Blocks []struct {
+					BoundingBox visionBoundingPoly `json:"boundingBox"`
+					Confidence  float64            `json:"confidence"`
+					Paragraphs  []struct {
+						Words []struct {
+							Symbols []struct {
+								Text string `json:"text"`
+							} `json:"symbols"`
+						} `json:"words"`
+					} `json:"paragraphs"`
+				} `json:"blocks"`
+			} `json:"pages"`
+		} `json:"fullTextAnnotation"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"responses"`
+}
+
+// Recognize sends the image to the google vision api using document text
+// detection and returns one block per detected text block.
+func (g GoogleVisionOCR) Recognize(ctx context.Context, image []byte) (OCRResult, error) {
+	payload := map[string]any{
+		"requests": []any{
+			map[string]any{
+				"image":    map[string]any{"content": base64.StdEncoding.EncodeToString(image)},
+				"features": []any{map[string]any{"type": "DOCUMENT_TEXT_DETECTION"}},
+			},
+		},
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return OCRResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url+"?key="+g.apiKey, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return OCRResult{}, fmt.Errorf("creating request in google vision: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := g.httpClient.Do(req)
+	if err != nil {
+		return OCRResult{}, fmt.Errorf("doing request in google vision: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		msg := string(body)
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			msg = errResp.Error.Message
+		}
+		return OCRResult{}, fmt.Errorf("%w: status %d: %s", ErrGoogleVisionAPI, res.StatusCode, msg)
+	}
+
+	var response visionResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return OCRResult{}, fmt.Errorf("unmarshal data in google vision: %w", err)
+	}
+	if len(response.Responses) == 0 {
+		return OCRResult{}, fmt.Errorf("%w: empty response", ErrGoogleVisionAPI)
+	}
+	if e := response.Responses[0].Error; e != nil {
+		return OCRResult{}, fmt.Errorf("%w: %s", ErrGoogleVisionAPI, e.Message)
+	}
+
+	annotation := response.Responses[0].FullTextAnnotation
+	result := OCRResult{Text: strings.TrimSpace(annotation.Text)}
+	for _, page := range annotation.Pages {
+		for _, block := range page.Blocks {
+			paragraphs := make([]string, 0, len(block.Paragraphs))
+			for _, paragraph := range block.Paragraphs {
+				words := make([]string, 0, len(paragraph.Words))
+				for _, word := range paragraph.Words {
+					var sb strings.Builder
+					for _, symbol := range word.Symbols {
+						sb.WriteString(symbol.Text)
+					}
+					words = append(words, sb.String())
+				}
+				paragraphs = append(paragraphs, strings.Join(words, " "))
+			}
+			result.Blocks = append(result.Blocks, OCRBlock{
+				Text:        strings.Join(paragraphs, "\n"),
+				Confidence:  block.Confidence,
+				BoundingBox: visionBoundingBox(block.BoundingBox),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func visionBoundingBox(poly visionBoundingPoly) BoundingBox {
+	if len(poly.Vertices) == 0 {
+		return BoundingBox{}
+	}
+	left, top := poly.Vertices[0].X, poly.Vertices[0].Y
+	right, bottom := left, top
+	for _, v := range poly.Vertices[1:] {
+		left, top = minInt(left, v.X), minInt(top, v.Y)
+		right, bottom = maxInt(right, v.X), maxInt(bottom, v.Y)
+	}
+	return BoundingBox{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
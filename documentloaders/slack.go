@@ -0,0 +1,235 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/exp/slices"
+)
+
+// nolint:gochecknoglobals
+var _slackMentionRegexp = regexp.MustCompile(`<@([A-Z0-9]+)(\|[^>]*)?>`)
+
+// SlackExport loads the messages of a Slack workspace export archive. The
+// messages of every channel are grouped into threads and each thread becomes
+// a document.
+type SlackExport struct {
+	r        io.ReaderAt
+	s        int64
+	channels []string
+}
+
+var _ Loader = SlackExport{}
+
+// SlackExportOptions are options for the Slack export loader.
+type SlackExportOptions func(s *SlackExport)
+
+// WithSlackChannels only loads the messages of the channels with the given names.
+func WithSlackChannels(channels ...string) SlackExportOptions {
+	return func(s *SlackExport) {
+		s.channels = channels
+	}
+}
+
+// NewSlackExport creates a new Slack export loader with an io.ReaderAt of the
+// zip archive and its size.
+func NewSlackExport(r io.ReaderAt, size int64, opts ...SlackExportOptions) SlackExport {
+	s := SlackExport{
+		r: r,
+		s: size,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+type slackUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Profile  struct {
+		DisplayName string `json:"display_name"`
+	} `json:"profile"`
+}
+
+type slackMessage struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	User     string `json:"user"`
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+type slackThread struct {
+	channel  string
+	threadTS string
+	messages []slackMessage
+}
+
+// Load reads the archive and returns one document per thread. Top level
+// messages without replies are returned as threads with a single message.
+func (s SlackExport) Load(_ context.Context) ([]schema.Document, error) {
+	zr, err := zip.NewReader(s.r, s.s)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := readSlackUsers(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make(map[string]*slackThread)
+	order := make([]*slackThread, 0)
+	for _, f := range zr.File {
+		channel, name := path.Split(f.Name)
+		channel = strings.Trim(channel, "/")
+		if channel == "" || path.Ext(name) != ".json" {
+			continue
+		}
+		if len(s.channels) > 0 && !slices.Contains(s.channels, channel) {
+			continue
+		}
+
+		var messages []slackMessage
+		if err := readZipJSON(f, &messages); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		for _, msg := range messages {
+			if msg.Type != "message" || msg.Subtype == "channel_join" || msg.Subtype == "channel_leave" {
+				continue
+			}
+			threadTS := msg.ThreadTS
+			if threadTS == "" {
+				threadTS = msg.TS
+			}
+			key := channel + "/" + threadTS
+			thread, ok := threads[key]
+			if !ok {
+				thread = &slackThread{channel: channel, threadTS: threadTS}
+				threads[key] = thread
+				order = append(order, thread)
+			}
+			thread.messages = append(thread.messages, msg)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].channel != order[j].channel {
+			return order[i].channel < order[j].channel
+		}
+		return parseSlackTS(order[i].threadTS).Before(parseSlackTS(order[j].threadTS))
+	})
+
+	docs := make([]schema.Document, 0, len(order))
+	for _, thread := range order {
+		docs = append(docs, thread.toDocument(users))
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit reads the archive and splits the threads into multiple
+// documents using a text splitter.
+func (s SlackExport) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (t *slackThread) toDocument(users map[string]string) schema.Document {
+	sort.SliceStable(t.messages, func(i, j int) bool {
+		return parseSlackTS(t.messages[i].TS).Before(parseSlackTS(t.messages[j].TS))
+	})
+
+	lines := make([]string, 0, len(t.messages))
+	participants := make([]string, 0)
+	for _, msg := range t.messages {
+		name := slackUserName(users, msg.User)
+		if !slices.Contains(participants, name) {
+			participants = append(participants, name)
+		}
+		text := _slackMentionRegexp.ReplaceAllStringFunc(msg.Text, func(mention string) string {
+			id := _slackMentionRegexp.FindStringSubmatch(mention)[1]
+			return "@" + slackUserName(users, id)
+		})
+		lines = append(lines, fmt.Sprintf("%s: %s", name, text))
+	}
+
+	start := parseSlackTS(t.threadTS)
+	return schema.Document{
+		PageContent: strings.Join(lines, "\n"),
+		Metadata: map[string]any{
+			"channel":      t.channel,
+			"thread_ts":    t.threadTS,
+			"date":         start.Format("2006-01-02"),
+			"timestamp":    start,
+			"participants": participants,
+			"replies":      len(t.messages) - 1,
+		},
+	}
+}
+
+func readSlackUsers(zr *zip.Reader) (map[string]string, error) {
+	users := make(map[string]string)
+	for _, f := range zr.File {
+		if f.Name != "users.json" {
+			continue
+		}
+		var list []slackUser
+		if err := readZipJSON(f, &list); err != nil {
+			return nil, fmt.Errorf("reading users.json: %w", err)
+		}
+		for _, u := range list {
+			switch {
+			case u.RealName != "":
+				users[u.ID] = u.RealName
+			case u.Profile.DisplayName != "":
+				users[u.ID] = u.Profile.DisplayName
+			default:
+				users[u.ID] = u.Name
+			}
+		}
+	}
+	return users, nil
+}
+
+func slackUserName(users map[string]string, id string) string {
+	if name, ok := users[id]; ok {
+		return name
+	}
+	return id
+}
+
+func readZipJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(rc).Decode(v)
+}
+
+func parseSlackTS(ts string) time.Time {
+	seconds, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+}
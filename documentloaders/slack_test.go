@@ -0,0 +1,61 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSlackExport(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestSlackExportLoader(t *testing.T) {
+	t.Parallel()
+
+	r := createSlackExport(t, map[string]string{
+		"users.json": `[{"id": "U1", "name": "alice", "real_name": "Alice"}, {"id": "U2", "name": "bob"}]`,
+		"general/2023-07-01.json": `[
+			{"type": "message", "user": "U1", "text": "Hello <@U2>", "ts": "1688212800.000100", "thread_ts": "1688212800.000100"},
+			{"type": "message", "user": "U2", "text": "Hi!", "ts": "1688212860.000200", "thread_ts": "1688212800.000100"},
+			{"type": "message", "subtype": "channel_join", "user": "U2", "text": "joined", "ts": "1688212700.000000"},
+			{"type": "message", "user": "U2", "text": "Unrelated", "ts": "1688216400.000300"}
+		]`,
+		"random/2023-07-02.json": `[{"type": "message", "user": "U1", "text": "Lunch?", "ts": "1688299200.000100"}]`,
+	})
+
+	loader := NewSlackExport(r, r.Size())
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	assert.Equal(t, "Alice: Hello @bob\nbob: Hi!", docs[0].PageContent)
+	assert.Equal(t, "general", docs[0].Metadata["channel"])
+	assert.Equal(t, "2023-07-01", docs[0].Metadata["date"])
+	assert.Equal(t, []string{"Alice", "bob"}, docs[0].Metadata["participants"])
+	assert.Equal(t, 1, docs[0].Metadata["replies"])
+	assert.Equal(t, "bob: Unrelated", docs[1].PageContent)
+	assert.Equal(t, "random", docs[2].Metadata["channel"])
+
+	loader = NewSlackExport(r, r.Size(), WithSlackChannels("random"))
+	docs, err = loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Alice: Lunch?", docs[0].PageContent)
+}
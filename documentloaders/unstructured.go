@@ -0,0 +1,210 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	_unstructuredAPIKeyEnvVarName = "UNSTRUCTURED_API_KEY" //nolint:gosec
+	_unstructuredAPIURLEnvVarName = "UNSTRUCTURED_API_URL"
+	_defaultUnstructuredAPIURL    = "https://api.unstructured.io/general/v0/general"
+	_defaultUnstructuredFileName  = "document"
+)
+
+// ErrUnstructuredAPI is returned when the partition endpoint responds with a
+// non 200 status code.
+var ErrUnstructuredAPI = errors.New("unexpected response from unstructured api")
+
+// Unstructured loads documents by posting a file to an Unstructured.io
+// compatible partition endpoint. Each element returned by the endpoint becomes
+// a document with the element type stored in the "category" metadata key.
+type Unstructured struct {
+	r            io.Reader
+	fileName     string
+	apiURL       string
+	apiKey       string
+	strategy     string
+	elementTypes []string
+	httpClient   *http.Client
+}
+
+var _ Loader = Unstructured{}
+
+// UnstructuredOptions are options for the Unstructured loader.
+type UnstructuredOptions func(u *Unstructured)
+
+// WithUnstructuredAPIURL sets the url of the partition endpoint. If not set the
+// url is read from the UNSTRUCTURED_API_URL environment variable, falling back
+// to the hosted Unstructured API.
+func WithUnstructuredAPIURL(apiURL string) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.apiURL = apiURL
+	}
+}
+
+// WithUnstructuredAPIKey sets the api key sent to the partition endpoint. If
+// not set the key is read from the UNSTRUCTURED_API_KEY environment variable.
+func WithUnstructuredAPIKey(apiKey string) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.apiKey = apiKey
+	}
+}
+
+// WithUnstructuredFileName sets the file name sent with the upload. The
+// endpoint uses the extension to detect the file type.
+func WithUnstructuredFileName(fileName string) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.fileName = fileName
+	}
+}
+
+// WithUnstructuredStrategy sets the partitioning strategy, e.g. "fast",
+// "hi_res" or "ocr_only".
+func WithUnstructuredStrategy(strategy string) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.strategy = strategy
+	}
+}
+
+// WithUnstructuredElementTypes limits the returned documents to elements of the
+// given types, e.g. "Title", "NarrativeText" and "Table". All elements are
+// returned by default.
+func WithUnstructuredElementTypes(elementTypes ...string) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.elementTypes = elementTypes
+	}
+}
+
+// WithUnstructuredHTTPClient sets the http client used to call the endpoint.
+func WithUnstructuredHTTPClient(client *http.Client) UnstructuredOptions {
+	return func(u *Unstructured) {
+		u.httpClient = client
+	}
+}
+
+// NewUnstructured creates a new unstructured loader with an io.Reader holding
+// the file to partition.
+func NewUnstructured(r io.Reader, opts ...UnstructuredOptions) Unstructured {
+	u := Unstructured{
+		r:          r,
+		fileName:   _defaultUnstructuredFileName,
+		apiURL:     os.Getenv(_unstructuredAPIURLEnvVarName),
+		apiKey:     os.Getenv(_unstructuredAPIKeyEnvVarName),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&u)
+	}
+	if u.apiURL == "" {
+		u.apiURL = _defaultUnstructuredAPIURL
+	}
+	return u
+}
+
+type unstructuredElement struct {
+	Type      string         `json:"type"`
+	ElementID string         `json:"element_id"`
+	Text      string         `json:"text"`
+	Metadata  map[string]any `json:"metadata"`
+}
+
+// Load posts the file to the partition endpoint and returns one document per
+// returned element.
+func (u Unstructured) Load(ctx context.Context) ([]schema.Document, error) {
+	elements, err := u.partition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(elements))
+	for _, element := range elements {
+		if len(u.elementTypes) > 0 && !slices.Contains(u.elementTypes, element.Type) {
+			continue
+		}
+
+		metadata := make(map[string]any, len(element.Metadata)+2)
+		for key, value := range element.Metadata {
+			metadata[key] = value
+		}
+		metadata["category"] = element.Type
+		metadata["element_id"] = element.ElementID
+
+		docs = append(docs, schema.Document{
+			PageContent: element.Text,
+			Metadata:    metadata,
+		})
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit partitions the file and splits the resulting documents using a
+// text splitter.
+func (u Unstructured) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := u.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (u Unstructured) partition(ctx context.Context) ([]unstructuredElement, error) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("files", u.fileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, u.r); err != nil {
+		return nil, err
+	}
+	if u.strategy != "" {
+		if err := w.WriteField("strategy", u.strategy); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.apiURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request in unstructured: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	if u.apiKey != "" {
+		req.Header.Set("unstructured-api-key", u.apiKey)
+	}
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doing request in unstructured: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnstructuredAPI, res.StatusCode, msg)
+	}
+
+	var elements []unstructuredElement
+	if err := json.NewDecoder(res.Body).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("unmarshal data in unstructured: %w", err)
+	}
+
+	return elements, nil
+}
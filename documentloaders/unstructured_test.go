@@ -0,0 +1,86 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _unstructuredTestResponse = `[
+	{"type": "Title", "element_id": "1", "text": "A Title", "metadata": {"filename": "test.docx", "page_number": 1}},
+	{"type": "NarrativeText", "element_id": "2", "text": "Some text.", "metadata": {"filename": "test.docx"}},
+	{"type": "Table", "element_id": "3", "text": "a b", "metadata": {"text_as_html": "<table></table>"}}
+]`
+
+func TestUnstructuredLoader(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("unstructured-api-key"))
+		file, header, err := r.FormFile("files")
+		require.NoError(t, err)
+		defer file.Close()
+		assert.Equal(t, "test.docx", header.Filename)
+		assert.Equal(t, "hi_res", r.FormValue("strategy"))
+
+		_, _ = w.Write([]byte(_unstructuredTestResponse))
+	}))
+	defer server.Close()
+
+	loader := NewUnstructured(
+		strings.NewReader("content"),
+		WithUnstructuredAPIURL(server.URL),
+		WithUnstructuredAPIKey("key"),
+		WithUnstructuredFileName("test.docx"),
+		WithUnstructuredStrategy("hi_res"),
+	)
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	assert.Equal(t, "A Title", docs[0].PageContent)
+	assert.Equal(t, "Title", docs[0].Metadata["category"])
+	assert.Equal(t, "1", docs[0].Metadata["element_id"])
+	assert.Equal(t, "test.docx", docs[0].Metadata["filename"])
+	assert.Equal(t, "Table", docs[2].Metadata["category"])
+	assert.Equal(t, "<table></table>", docs[2].Metadata["text_as_html"])
+}
+
+func TestUnstructuredLoaderElementTypes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(_unstructuredTestResponse))
+	}))
+	defer server.Close()
+
+	loader := NewUnstructured(
+		strings.NewReader("content"),
+		WithUnstructuredAPIURL(server.URL),
+		WithUnstructuredElementTypes("NarrativeText"),
+	)
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Some text.", docs[0].PageContent)
+}
+
+func TestUnstructuredLoaderError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	loader := NewUnstructured(strings.NewReader("content"), WithUnstructuredAPIURL(server.URL))
+	_, err := loader.Load(context.Background())
+	require.ErrorIs(t, err, ErrUnstructuredAPI)
+}
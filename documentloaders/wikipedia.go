@@ -0,0 +1,260 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	_wikipediaURL              = "https://%s.wikipedia.org/w/api.php"
+	_defaultWikipediaLanguage  = "en"
+	_defaultWikipediaMaxDocs   = 3
+	_defaultWikipediaUserAgent = "langchaingo (https://github.com/tmc/langchaingo)"
+)
+
+// Wikipedia loads articles from Wikipedia, either by searching for a query or
+// by page id.
+type Wikipedia struct {
+	query        string
+	pageIDs      []int
+	maxDocs      int
+	languageCode string
+	userAgent    string
+	apiURL       string
+	httpClient   *http.Client
+}
+
+var _ Loader = Wikipedia{}
+
+// WikipediaOptions are options for the Wikipedia loader.
+type WikipediaOptions func(w *Wikipedia)
+
+// WithWikipediaPageIDs loads the articles with the given page ids instead of
+// searching for the query.
+func WithWikipediaPageIDs(pageIDs ...int) WikipediaOptions {
+	return func(w *Wikipedia) {
+		w.pageIDs = pageIDs
+	}
+}
+
+// WithWikipediaMaxDocs sets the number of search results to load. Defaults to 3.
+func WithWikipediaMaxDocs(maxDocs int) WikipediaOptions {
+	return func(w *Wikipedia) {
+		w.maxDocs = maxDocs
+	}
+}
+
+// WithWikipediaLanguage sets the language code of the Wikipedia to use. Defaults to "en".
+func WithWikipediaLanguage(languageCode string) WikipediaOptions {
+	return func(w *Wikipedia) {
+		w.languageCode = languageCode
+	}
+}
+
+// WithWikipediaUserAgent sets the user agent sent to the api. See
+// https://www.mediawiki.org/wiki/API:Etiquette.
+func WithWikipediaUserAgent(userAgent string) WikipediaOptions {
+	return func(w *Wikipedia) {
+		w.userAgent = userAgent
+	}
+}
+
+// NewWikipedia creates a new Wikipedia loader returning the articles matching the query.
+func NewWikipedia(query string, opts ...WikipediaOptions) Wikipedia {
+	w := Wikipedia{
+		query:        query,
+		maxDocs:      _defaultWikipediaMaxDocs,
+		languageCode: _defaultWikipediaLanguage,
+		userAgent:    _defaultWikipediaUserAgent,
+		httpClient:   http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&w)
+	}
+	if w.apiURL == "" {
+		w.apiURL = fmt.Sprintf(_wikipediaURL, w.languageCode)
+	}
+	return w
+}
+
+// ErrWikipediaAPI is returned when the Wikipedia api responds with a non 200
+// status code.
+var ErrWikipediaAPI = errors.New("unexpected response from wikipedia api")
+
+type wikipediaPage struct {
+	PageID     int    `json:"pageid"`
+	Title      string `json:"title"`
+	Extract    string `json:"extract"`
+	FullURL    string `json:"fullurl"`
+	Index      int    `json:"index"`
+	Missing    *any   `json:"missing"`
+	Categories []struct {
+		Title string `json:"title"`
+	} `json:"categories"`
+}
+
+type wikipediaResponse struct {
+	// Continue holds the parameters of the next request when the properties
+	// of the pages did not fit in the response.
+	Continue map[string]any `json:"continue"`
+	Query    struct {
+		Pages map[string]wikipediaPage `json:"pages"`
+	} `json:"query"`
+}
+
+// Load returns one document per article with the plain text of the article as
+// content and the title, page id, canonical url and categories as metadata.
+func (w Wikipedia) Load(ctx context.Context) ([]schema.Document, error) {
+	params := make(url.Values)
+	params.Add("format", "json")
+	params.Add("action", "query")
+	params.Add("prop", "extracts|info|categories")
+	params.Add("explaintext", "1")
+	params.Add("exlimit", "max")
+	params.Add("inprop", "url")
+	params.Add("cllimit", "max")
+	params.Add("clshow", "!hidden")
+	if len(w.pageIDs) > 0 {
+		ids := make([]string, 0, len(w.pageIDs))
+		for _, id := range w.pageIDs {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		params.Add("pageids", strings.Join(ids, "|"))
+	} else {
+		params.Add("generator", "search")
+		params.Add("gsrsearch", w.query)
+		params.Add("gsrlimit", fmt.Sprint(w.maxDocs))
+	}
+
+	// The full text extracts are returned for one page per response, the
+	// continuations are followed until the properties of all the pages are
+	// filled. The continuations of the search itself would load more results
+	// than maxDocs and are not followed.
+	pages := make(map[string]*wikipediaPage)
+	for {
+		result, err := w.request(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		mergeWikipediaPages(pages, result.Query.Pages)
+		if len(result.Continue) == 0 || result.Continue["gsroffset"] != nil {
+			break
+		}
+		for key, value := range result.Continue {
+			params.Set(key, fmt.Sprint(value))
+		}
+	}
+
+	keys := make([]string, 0, len(pages))
+	for key, page := range pages {
+		if page.Missing != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	// Search results keep the order of the search ranking, pages loaded by id
+	// the order of the ids.
+	rank := func(page *wikipediaPage) int { return page.Index }
+	if len(w.pageIDs) > 0 {
+		positions := make(map[int]int, len(w.pageIDs))
+		for i, id := range w.pageIDs {
+			if _, ok := positions[id]; !ok {
+				positions[id] = i
+			}
+		}
+		rank = func(page *wikipediaPage) int { return positions[page.PageID] }
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return rank(pages[keys[i]]) < rank(pages[keys[j]])
+	})
+
+	docs := make([]schema.Document, 0, len(keys))
+	for _, key := range keys {
+		page := pages[key]
+		categories := make([]string, 0, len(page.Categories))
+		for _, category := range page.Categories {
+			categories = append(categories, strings.TrimPrefix(category.Title, "Category:"))
+		}
+		docs = append(docs, schema.Document{
+			PageContent: page.Extract,
+			Metadata: map[string]any{
+				"title":      page.Title,
+				"page_id":    page.PageID,
+				"source":     page.FullURL,
+				"categories": categories,
+			},
+		})
+	}
+
+	return docs, nil
+}
+
+// request sends one request of the query to the api.
+func (w Wikipedia) request(ctx context.Context, params url.Values) (wikipediaResponse, error) {
+	var result wikipediaResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return result, fmt.Errorf("creating request in wikipedia: %w", err)
+	}
+	req.Header.Add("User-Agent", w.userAgent)
+
+	res, err := w.httpClient.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("doing request in wikipedia: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return result, fmt.Errorf("reading data in wikipedia: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("%w: status %d: %s", ErrWikipediaAPI, res.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("unmarshal data in wikipedia: %w", err)
+	}
+	return result, nil
+}
+
+// mergeWikipediaPages merges the properties of the pages of a continued
+// response into the pages already loaded.
+func mergeWikipediaPages(pages map[string]*wikipediaPage, continued map[string]wikipediaPage) {
+	for key, page := range continued {
+		page := page
+		loaded, ok := pages[key]
+		if !ok {
+			pages[key] = &page
+			continue
+		}
+		if loaded.Extract == "" {
+			loaded.Extract = page.Extract
+		}
+		if loaded.FullURL == "" {
+			loaded.FullURL = page.FullURL
+		}
+		loaded.Categories = append(loaded.Categories, page.Categories...)
+	}
+}
+
+// LoadAndSplit loads the articles and splits them into multiple documents
+// using a text splitter.
+func (w Wikipedia) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := w.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
@@ -0,0 +1,93 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWikipediaLoader(t *testing.T) {
+	t.Parallel()
+
+	// As the api, the full text extract of only one page is returned per
+	// response, the others with the continuation of the extracts.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "golang", r.URL.Query().Get("gsrsearch"))
+		assert.Equal(t, "2", r.URL.Query().Get("gsrlimit"))
+		assert.Empty(t, r.URL.Query().Get("gsroffset"))
+		if r.URL.Query().Get("excontinue") == "" {
+			_, _ = w.Write([]byte(`{"continue": {"excontinue": 1, "continue": "||info|categories"}, "query": {"pages": {
+				"20": {"pageid": 20, "title": "Gopher", "index": 2, "fullurl": "https://en.wikipedia.org/wiki/Gopher"},
+				"10": {"pageid": 10, "title": "Go", "extract": "A language.", "index": 1,
+					"fullurl": "https://en.wikipedia.org/wiki/Go", "categories": [{"title": "Category:Languages"}]}
+			}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"continue": {"gsroffset": 2, "continue": "gsroffset||"}, "query": {"pages": {
+			"20": {"pageid": 20, "title": "Gopher", "extract": "A rodent.", "index": 2},
+			"10": {"pageid": 10, "title": "Go", "index": 1}
+		}}}`))
+	}))
+	defer server.Close()
+
+	loader := NewWikipedia("golang", WithWikipediaMaxDocs(2))
+	loader.apiURL = server.URL
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "A language.", docs[0].PageContent)
+	assert.Equal(t, "Go", docs[0].Metadata["title"])
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Go", docs[0].Metadata["source"])
+	assert.Equal(t, []string{"Languages"}, docs[0].Metadata["categories"])
+	assert.Equal(t, "A rodent.", docs[1].PageContent)
+	assert.Equal(t, "Gopher", docs[1].Metadata["title"])
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Gopher", docs[1].Metadata["source"])
+}
+
+func TestWikipediaLoaderPageIDs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "30|10|20", r.URL.Query().Get("pageids"))
+		_, _ = w.Write([]byte(`{"query": {"pages": {
+			"10": {"pageid": 10, "title": "Go", "extract": "A language."},
+			"20": {"pageid": 20, "title": "Gopher", "extract": "A rodent."},
+			"30": {"pageid": 30, "title": "Rob Pike", "extract": "A programmer."}
+		}}}`))
+	}))
+	defer server.Close()
+
+	loader := NewWikipedia("", WithWikipediaPageIDs(30, 10, 20))
+	loader.apiURL = server.URL
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	titles := make([]any, 0, len(docs))
+	for _, doc := range docs {
+		titles = append(titles, doc.Metadata["title"])
+	}
+	assert.Equal(t, []any{"Rob Pike", "Go", "Gopher"}, titles)
+}
+
+func TestWikipediaLoaderError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("too many requests"))
+	}))
+	defer server.Close()
+
+	loader := NewWikipedia("golang")
+	loader.apiURL = server.URL
+
+	_, err := loader.Load(context.Background())
+	require.ErrorIs(t, err, ErrWikipediaAPI)
+	require.ErrorContains(t, err, "too many requests")
+}
@@ -0,0 +1,66 @@
+package documenttransformers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _contentHashMetadataKey = "content_hash"
+
+// Deduplicator removes documents whose content was already seen. Documents are
+// compared using a sha256 hash of their content, which is stored in the
+// "content_hash" metadata key of the returned documents.
+type Deduplicator struct {
+	// NormalizeWhitespace collapses whitespace before hashing, so documents
+	// only differing in whitespace are considered duplicates.
+	NormalizeWhitespace bool
+	// IgnoreCase lowercases the content before hashing.
+	IgnoreCase bool
+}
+
+var _ DocumentTransformer = Deduplicator{}
+
+// NewDeduplicator creates a new deduplicator that normalizes whitespace.
+func NewDeduplicator() Deduplicator {
+	return Deduplicator{
+		NormalizeWhitespace: true,
+	}
+}
+
+// TransformDocuments returns the documents in order, keeping only the first
+// document for each content hash.
+func (d Deduplicator) TransformDocuments(_ context.Context, docs []schema.Document) ([]schema.Document, error) {
+	seen := make(map[string]struct{}, len(docs))
+	result := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		hash := d.Hash(doc.PageContent)
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		metadata := copyMetadata(doc.Metadata)
+		metadata[_contentHashMetadataKey] = hash
+		result = append(result, schema.Document{
+			PageContent: doc.PageContent,
+			Metadata:    metadata,
+		})
+	}
+	return result, nil
+}
+
+// Hash returns the hex encoded sha256 hash of the normalized content.
+func (d Deduplicator) Hash(content string) string {
+	if d.NormalizeWhitespace {
+		content = strings.Join(strings.Fields(content), " ")
+	}
+	if d.IgnoreCase {
+		content = strings.ToLower(content)
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package documenttransformers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestDeduplicator(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{
+		{PageContent: "Hello  world", Metadata: map[string]any{"source": "a"}},
+		{PageContent: "Hello world\n", Metadata: map[string]any{"source": "b"}},
+		{PageContent: "hello world", Metadata: map[string]any{"source": "c"}},
+	}
+
+	result, err := NewDeduplicator().TransformDocuments(context.Background(), docs)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "a", result[0].Metadata["source"])
+	assert.Equal(t, "c", result[1].Metadata["source"])
+	assert.Len(t, result[0].Metadata[_contentHashMetadataKey], 64)
+	assert.NotContains(t, docs[0].Metadata, _contentHashMetadataKey)
+
+	result, err = Deduplicator{NormalizeWhitespace: true, IgnoreCase: true}.
+		TransformDocuments(context.Background(), docs)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	result, err = Deduplicator{}.TransformDocuments(context.Background(), docs)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+}
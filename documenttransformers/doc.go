@@ -0,0 +1,15 @@
+/*
+Package documenttransformers contains the DocumentTransformer interface for
+modifying documents between loading and splitting, and a set of built-in
+transformers.
+
+The main components of this package are:
+
+- DocumentTransformer interface: a common interface for transforming a list of documents.
+- HTMLToMarkdown: converts html page content to markdown.
+- LanguageDetector: detects the language of documents and stores it in the metadata.
+- PIIRedactor: redacts personally identifiable information like emails and phone numbers.
+- Deduplicator: removes documents with duplicated content using a content hash.
+- Pipeline: composes a loader, transformers, a text splitter and a vector store.
+*/
+package documenttransformers
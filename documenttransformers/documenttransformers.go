@@ -0,0 +1,41 @@
+package documenttransformers
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// DocumentTransformer is the interface for transforming a list of documents.
+// Transformers can modify the content and metadata of documents, drop
+// documents or create new ones.
+type DocumentTransformer interface {
+	TransformDocuments(ctx context.Context, docs []schema.Document) ([]schema.Document, error)
+}
+
+// Sequence is a transformer that applies a list of transformers in order.
+type Sequence []DocumentTransformer
+
+var _ DocumentTransformer = Sequence{}
+
+// TransformDocuments applies each of the transformers to the output of the previous one.
+func (s Sequence) TransformDocuments(ctx context.Context, docs []schema.Document) ([]schema.Document, error) {
+	var err error
+	for _, t := range s {
+		docs, err = t.TransformDocuments(ctx, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// copyMetadata returns a copy of the metadata that can be modified without
+// changing the metadata of the input document.
+func copyMetadata(metadata map[string]any) map[string]any {
+	m := make(map[string]any, len(metadata)+1)
+	for key, value := range metadata {
+		m[key] = value
+	}
+	return m
+}
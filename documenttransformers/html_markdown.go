@@ -0,0 +1,202 @@
+package documenttransformers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"golang.org/x/net/html"
+)
+
+// nolint:gochecknoglobals
+var (
+	_blankLinesRegexp    = regexp.MustCompile(`\n{3,}`)
+	_trailingSpaceRegexp = regexp.MustCompile(`(?m)[ \t]+$`)
+	_whitespaceRegexp    = regexp.MustCompile(`\s+`)
+)
+
+// HTMLToMarkdown converts the html content of documents to markdown. Headings,
+// paragraphs, links, lists, emphasis and code blocks are preserved while
+// scripts, styles and other markup are dropped.
+type HTMLToMarkdown struct{}
+
+var _ DocumentTransformer = HTMLToMarkdown{}
+
+// NewHTMLToMarkdown creates a new html to markdown transformer.
+func NewHTMLToMarkdown() HTMLToMarkdown {
+	return HTMLToMarkdown{}
+}
+
+// TransformDocuments returns the documents with their content converted to markdown.
+func (t HTMLToMarkdown) TransformDocuments(_ context.Context, docs []schema.Document) ([]schema.Document, error) {
+	result := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		content, err := t.Convert(doc.PageContent)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, schema.Document{
+			PageContent: content,
+			Metadata:    copyMetadata(doc.Metadata),
+		})
+	}
+	return result, nil
+}
+
+// Convert converts a html string to markdown.
+func (t HTMLToMarkdown) Convert(s string) (string, error) {
+	root, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return "", fmt.Errorf("parsing html: %w", err)
+	}
+
+	var b strings.Builder
+	w := markdownWriter{b: &b}
+	w.writeChildren(root)
+
+	out := _trailingSpaceRegexp.ReplaceAllString(b.String(), "")
+	out = _blankLinesRegexp.ReplaceAllString(out, "\n\n")
+	return strings.TrimSpace(out), nil
+}
+
+type markdownWriter struct {
+	b       *strings.Builder
+	listDep int
+	pre     bool
+}
+
+func (w *markdownWriter) writeChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.write(c)
+	}
+}
+
+func (w *markdownWriter) write(n *html.Node) { //nolint:cyclop
+	switch n.Type {
+	case html.TextNode:
+		w.writeText(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		w.writeChildren(n)
+		return
+	}
+
+	switch n.Data {
+	case "script", "style", "noscript", "head", "template":
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block()
+		w.b.WriteString(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		w.writeChildren(n)
+		w.block()
+	case "p", "div", "section", "article", "header", "footer", "blockquote", "table", "tr":
+		w.block()
+		w.writeChildren(n)
+		w.block()
+	case "br":
+		w.b.WriteString("\n")
+	case "hr":
+		w.block()
+		w.b.WriteString("---")
+		w.block()
+	case "ul", "ol":
+		w.block()
+		w.listDep++
+		w.writeChildren(n)
+		w.listDep--
+		w.block()
+	case "li":
+		w.line()
+		w.b.WriteString(strings.Repeat("  ", maxInt(w.listDep-1, 0)) + "- ")
+		w.writeChildren(n)
+		w.line()
+	case "a":
+		href := attr(n, "href")
+		if href == "" {
+			w.writeChildren(n)
+			return
+		}
+		w.b.WriteString("[")
+		w.writeChildren(n)
+		w.b.WriteString("](" + href + ")")
+	case "strong", "b":
+		w.wrap(n, "**")
+	case "em", "i":
+		w.wrap(n, "*")
+	case "code":
+		if w.pre {
+			w.writeChildren(n)
+			return
+		}
+		w.wrap(n, "`")
+	case "pre":
+		w.block()
+		w.b.WriteString("```\n")
+		w.pre = true
+		w.writeChildren(n)
+		w.pre = false
+		w.line()
+		w.b.WriteString("```")
+		w.block()
+	case "img":
+		if alt := attr(n, "alt"); alt != "" {
+			w.b.WriteString(fmt.Sprintf("![%s](%s)", alt, attr(n, "src")))
+		}
+	case "td", "th":
+		w.writeChildren(n)
+		w.b.WriteString(" ")
+	default:
+		w.writeChildren(n)
+	}
+}
+
+func (w *markdownWriter) writeText(text string) {
+	if w.pre {
+		w.b.WriteString(text)
+		return
+	}
+	text = _whitespaceRegexp.ReplaceAllString(text, " ")
+	current := w.b.String()
+	if current == "" || strings.HasSuffix(current, "\n") || strings.HasSuffix(current, " ") {
+		text = strings.TrimLeft(text, " ")
+	}
+	w.b.WriteString(text)
+}
+
+func (w *markdownWriter) wrap(n *html.Node, marker string) {
+	w.b.WriteString(marker)
+	w.writeChildren(n)
+	w.b.WriteString(marker)
+}
+
+// block ends the current paragraph.
+func (w *markdownWriter) block() {
+	if w.b.Len() > 0 {
+		w.b.WriteString("\n\n")
+	}
+}
+
+// line ends the current line.
+func (w *markdownWriter) line() {
+	if w.b.Len() > 0 && !strings.HasSuffix(w.b.String(), "\n") {
+		w.b.WriteString("\n")
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,37 @@
+package documenttransformers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	t.Parallel()
+
+	input := `<html><head><title>Ignored</title><style>p {}</style></head><body>
+<h1>Title</h1>
+<p>Some <strong>bold</strong> and <em>italic</em> text with a <a href="https://example.com">link</a>.</p>
+<ul><li>one</li><li>two <code>x</code></li></ul>
+<pre><code>func main() {
+	fmt.Println("hi")
+}</code></pre>
+<script>alert("no")</script>
+</body></html>`
+
+	expected := "# Title\n\n" +
+		"Some **bold** and *italic* text with a [link](https://example.com).\n\n" +
+		"- one\n- two `x`\n\n" +
+		"```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```"
+
+	result, err := NewHTMLToMarkdown().TransformDocuments(context.Background(), []schema.Document{
+		{PageContent: input, Metadata: map[string]any{"source": "a"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, expected, result[0].PageContent)
+	assert.Equal(t, map[string]any{"source": "a"}, result[0].Metadata)
+}
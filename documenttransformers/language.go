@@ -0,0 +1,152 @@
+package documenttransformers
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// LanguageUndetermined is the language code used when the language of a
+// document could not be detected.
+const LanguageUndetermined = "und"
+
+const _languageMetadataKey = "language"
+
+// nolint:gochecknoglobals
+var _scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// nolint:gochecknoglobals
+var _stopWords = map[string][]string{
+	"en": {"the", "and", "is", "of", "to", "in", "that", "it", "with", "for", "was", "on", "are", "this", "be"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "ein", "eine", "zu", "den", "von", "auch", "sich", "ich"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "un", "pas", "que", "pour", "dans", "du", "avec", "sur"},
+	"es": {"el", "la", "los", "las", "y", "es", "que", "una", "por", "con", "para", "del", "como", "pero", "muy"},
+	"it": {"il", "di", "che", "non", "una", "sono", "per", "con", "gli", "della", "anche", "come", "questo", "ma", "è"},
+	"pt": {"o", "os", "as", "que", "não", "uma", "com", "para", "do", "da", "em", "um", "mas", "por", "são"},
+	"nl": {"de", "het", "een", "en", "is", "van", "niet", "dat", "op", "te", "zijn", "voor", "met", "ook", "maar"},
+}
+
+// LanguageDetector detects the language of documents and stores the ISO 639-1
+// code in the "language" metadata key. Documents in non latin scripts are
+// detected by script, latin script documents by the frequency of common words.
+type LanguageDetector struct {
+	// Languages restricts the detected languages. All supported languages are
+	// used if empty.
+	Languages []string
+}
+
+var _ DocumentTransformer = LanguageDetector{}
+
+// NewLanguageDetector creates a new language detector for all supported languages.
+func NewLanguageDetector() LanguageDetector {
+	return LanguageDetector{}
+}
+
+// TransformDocuments returns the documents with the detected language added to the metadata.
+func (d LanguageDetector) TransformDocuments(_ context.Context, docs []schema.Document) ([]schema.Document, error) {
+	result := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		metadata := copyMetadata(doc.Metadata)
+		metadata[_languageMetadataKey] = d.Detect(doc.PageContent)
+		result = append(result, schema.Document{
+			PageContent: doc.PageContent,
+			Metadata:    metadata,
+		})
+	}
+	return result, nil
+}
+
+// Detect returns the language code of the text, or LanguageUndetermined.
+func (d LanguageDetector) Detect(text string) string {
+	if language := d.detectScript(text); language != "" {
+		return language
+	}
+	return d.detectStopWords(text)
+}
+
+func (d LanguageDetector) detectScript(text string) string {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range _scriptLanguages {
+			if unicode.Is(s.table, r) {
+				counts[s.language]++
+				break
+			}
+		}
+	}
+
+	// Japanese text mixes kana with kanji, so any kana wins over chinese.
+	if counts["ja"] > 0 && d.allowed("ja") {
+		counts["ja"] += counts["zh"]
+		counts["zh"] = 0
+	}
+
+	best, bestCount := "", 0
+	for language, count := range counts {
+		if count > bestCount && d.allowed(language) {
+			best, bestCount = language, count
+		}
+	}
+	if bestCount*2 < letters {
+		return ""
+	}
+	return best
+}
+
+func (d LanguageDetector) detectStopWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	wordCounts := make(map[string]int, len(words))
+	for _, word := range words {
+		wordCounts[word]++
+	}
+
+	best, bestScore := LanguageUndetermined, 0
+	for language, stopWords := range _stopWords {
+		if !d.allowed(language) {
+			continue
+		}
+		score := 0
+		for _, stopWord := range stopWords {
+			score += wordCounts[stopWord]
+		}
+		if score > bestScore || (score == bestScore && score > 0 && language < best) {
+			best, bestScore = language, score
+		}
+	}
+	return best
+}
+
+func (d LanguageDetector) allowed(language string) bool {
+	if len(d.Languages) == 0 {
+		return true
+	}
+	for _, l := range d.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
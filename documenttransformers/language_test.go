@@ -0,0 +1,41 @@
+package documenttransformers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestLanguageDetector(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"The quick brown fox jumps over the lazy dog and it is fast.": "en",
+		"Der Hund ist nicht mit der Katze und das ist gut.":           "de",
+		"Le chat est dans la maison et les enfants sont avec lui.":    "fr",
+		"El perro y la casa de los niños es muy grande para todos.":   "es",
+		"これは日本語の文章です。":                                                "ja",
+		"这是一个中文句子。":                                                   "zh",
+		"이것은 한국어 문장입니다.":                                              "ko",
+		"Это предложение на русском языке.":                           "ru",
+		"12345 !!!": LanguageUndetermined,
+	}
+
+	d := NewLanguageDetector()
+	for text, expected := range cases {
+		assert.Equal(t, expected, d.Detect(text), text)
+	}
+
+	result, err := d.TransformDocuments(context.Background(), []schema.Document{
+		{PageContent: "This is the text of the document.", Metadata: map[string]any{}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "en", result[0].Metadata["language"])
+
+	restricted := LanguageDetector{Languages: []string{"de", "nl"}}
+	assert.Equal(t, "de", restricted.Detect("Der Hund ist nicht mit der Katze."))
+}
@@ -0,0 +1,110 @@
+package documenttransformers
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// PIIType is a kind of personally identifiable information.
+type PIIType string
+
+const (
+	// PIIEmail matches email addresses.
+	PIIEmail PIIType = "EMAIL"
+	// PIIPhone matches phone numbers.
+	PIIPhone PIIType = "PHONE"
+	// PIICreditCard matches credit card numbers passing the Luhn check.
+	PIICreditCard PIIType = "CREDIT_CARD"
+	// PIISSN matches US social security numbers.
+	PIISSN PIIType = "SSN"
+	// PIIIPAddress matches IPv4 addresses.
+	PIIIPAddress PIIType = "IP_ADDRESS"
+)
+
+// nolint:gochecknoglobals
+var _piiPatterns = map[PIIType]*regexp.Regexp{
+	PIIEmail:      regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	PIICreditCard: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+	PIISSN:        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	PIIPhone:      regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{2,4}\)|\d{2,4})[ .\-]\d{3,4}[ .\-]\d{3,4}\b`),
+	PIIIPAddress:  regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+}
+
+// The order the patterns are applied in. Credit cards and social security
+// numbers are redacted before phone numbers as they would partially match.
+// nolint:gochecknoglobals
+var _defaultPIITypes = []PIIType{PIIEmail, PIICreditCard, PIISSN, PIIIPAddress, PIIPhone}
+
+// PIIRedactor replaces personally identifiable information in the content of
+// documents with placeholders such as "[EMAIL]". The number of redactions is
+// stored in the "pii_redactions" metadata key.
+type PIIRedactor struct {
+	types []PIIType
+}
+
+var _ DocumentTransformer = PIIRedactor{}
+
+// NewPIIRedactor creates a new redactor for the given types of information.
+// If no types are given all supported types are redacted.
+func NewPIIRedactor(types ...PIIType) PIIRedactor {
+	if len(types) == 0 {
+		types = _defaultPIITypes
+	}
+	return PIIRedactor{types: types}
+}
+
+// TransformDocuments returns the documents with the information redacted.
+func (r PIIRedactor) TransformDocuments(_ context.Context, docs []schema.Document) ([]schema.Document, error) {
+	result := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		content, n := r.Redact(doc.PageContent)
+		metadata := copyMetadata(doc.Metadata)
+		metadata["pii_redactions"] = n
+		result = append(result, schema.Document{
+			PageContent: content,
+			Metadata:    metadata,
+		})
+	}
+	return result, nil
+}
+
+// Redact replaces the information in the text and returns the redacted text
+// and the number of replacements.
+func (r PIIRedactor) Redact(text string) (string, int) {
+	count := 0
+	for _, typ := range r.types {
+		pattern, ok := _piiPatterns[typ]
+		if !ok {
+			continue
+		}
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if typ == PIICreditCard && !luhnValid(match) {
+				return match
+			}
+			count++
+			return "[" + string(typ) + "]"
+		})
+	}
+	return text, count
+}
+
+func luhnValid(number string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(number)
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 { //nolint:gomnd
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,58 @@
+package documenttransformers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestPIIRedactor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		types    []PIIType
+		text     string
+		expected string
+		count    int
+	}{
+		{
+			text:     "Mail jane.doe@example.com or call +1 555-123-4567.",
+			expected: "Mail [EMAIL] or call [PHONE].",
+			count:    2,
+		},
+		{
+			types:    []PIIType{PIICreditCard},
+			text:     "Card 4111 1111 1111 1111, order 1234 5678 9012 3456.",
+			expected: "Card [CREDIT_CARD], order 1234 5678 9012 3456.",
+			count:    1,
+		},
+		{
+			text:     "SSN 123-45-6789 from 192.168.0.1",
+			expected: "SSN [SSN] from [IP_ADDRESS]",
+			count:    2,
+		},
+		{
+			types:    []PIIType{PIIEmail},
+			text:     "jane@example.com 123-45-6789",
+			expected: "[EMAIL] 123-45-6789",
+			count:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		text, count := NewPIIRedactor(tc.types...).Redact(tc.text)
+		assert.Equal(t, tc.expected, text)
+		assert.Equal(t, tc.count, count)
+	}
+
+	result, err := NewPIIRedactor().TransformDocuments(context.Background(), []schema.Document{
+		{PageContent: "contact: jane@example.com", Metadata: map[string]any{"source": "a"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "contact: [EMAIL]", result[0].PageContent)
+	assert.Equal(t, map[string]any{"source": "a", "pii_redactions": 1}, result[0].Metadata)
+}
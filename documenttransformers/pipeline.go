@@ -0,0 +1,52 @@
+package documenttransformers
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// Pipeline is an ingestion pipeline going from a loader, through the
+// transformers and the text splitter, to a vector store. Only the loader is
+// required, the other steps are skipped when not set.
+type Pipeline struct {
+	Loader       documentloaders.Loader
+	Transformers []DocumentTransformer
+	Splitter     textsplitter.TextSplitter
+	Store        vectorstores.VectorStore
+
+	// StoreOptions are the options passed to AddDocuments of the store.
+	StoreOptions []vectorstores.Option
+}
+
+// Run loads, transforms and splits the documents, adds them to the store and
+// returns the documents that were added.
+func (p Pipeline) Run(ctx context.Context) ([]schema.Document, error) {
+	docs, err := p.Loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err = Sequence(p.Transformers).TransformDocuments(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Splitter != nil {
+		docs, err = textsplitter.SplitDocuments(p.Splitter, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Store != nil && len(docs) > 0 {
+		if _, err := p.Store.AddDocuments(ctx, docs, p.StoreOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,62 @@
+package documenttransformers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+type fakeStore struct {
+	docs []schema.Document
+}
+
+func (s *fakeStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) { //nolint:lll
+	s.docs = append(s.docs, docs...)
+	return make([]string, len(docs)), nil
+}
+
+func (s *fakeStore) SimilaritySearch(context.Context, string, int, ...vectorstores.Option) ([]schema.Document, error) {
+	return s.docs, nil
+}
+
+func (s *fakeStore) Delete(context.Context, []string, ...vectorstores.Option) error {
+	return nil
+}
+
+func (s *fakeStore) DeleteByFilter(context.Context, any, ...vectorstores.Option) error {
+	return nil
+}
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	splitter := textsplitter.NewRecursiveCharacter()
+	splitter.ChunkSize = 20
+	splitter.ChunkOverlap = 0
+
+	store := &fakeStore{}
+	p := Pipeline{
+		Loader: documentloaders.NewText(strings.NewReader("Mail me at jane@example.com please")),
+		Transformers: []DocumentTransformer{
+			NewPIIRedactor(),
+			NewDeduplicator(),
+		},
+		Splitter: splitter,
+		Store:    store,
+	}
+
+	docs, err := p.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "Mail me at [EMAIL]", docs[0].PageContent)
+	assert.Equal(t, "please", docs[1].PageContent)
+	assert.Equal(t, 1, docs[0].Metadata["pii_redactions"])
+	assert.Equal(t, docs, store.docs)
+}
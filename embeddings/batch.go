@@ -0,0 +1,345 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	_defaultTextsPerRequest = 512
+	_defaultMaxParallel     = 1
+)
+
+// ErrVectorCount is returned when an embedding request returns a number of
+// vectors different from the number of texts.
+var ErrVectorCount = errors.New("unexpected number of vectors")
+
+// BatchError is the error of a batch of texts failing after its retries.
+type BatchError struct {
+	// Start and End are the indices of the first text of the batch and of the
+	// text following its last text.
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("embed texts %d to %d: %v", e.Start, e.End, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned with the vectors of the texts embedded when
+// batches fail with WithContinueOnError. The vectors of the texts of the
+// failed batches are nil, to embed again.
+type PartialError struct {
+	Errors []*BatchError
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d batches failed, first: %v", len(e.Errors), e.Errors[0])
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ProgressFunc reports the progress of EmbedInBatches after each batch: the
+// number of texts embedded, the total number of texts and the errors of the
+// batches failed so far.
+type ProgressFunc func(done, total int, errs []error)
+
+// EmbedFunc embeds the texts of a single request.
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float64, error)
+
+type batchOptions struct {
+	textsPerRequest int
+	maxParallel     int
+	retryPolicy     llms.RetryPolicy
+	progress        ProgressFunc
+	continueOnError bool
+}
+
+// BatchOption is an option of EmbedInBatches.
+type BatchOption func(*batchOptions)
+
+// WithTextsPerRequest sets the maximum number of texts of a request, 512 by
+// default. Set it to the limit of the provider.
+func WithTextsPerRequest(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.textsPerRequest = n
+	}
+}
+
+// WithMaxParallel sets the maximum number of requests sent concurrently, 1 by
+// default.
+func WithMaxParallel(maxParallel int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxParallel = maxParallel
+	}
+}
+
+// WithProgress sets the function reporting the progress after each batch,
+// e.g. to show a progress bar. The function is not called concurrently, but
+// blocks the workers while it runs.
+func WithProgress(progress ProgressFunc) BatchOption {
+	return func(o *batchOptions) {
+		o.progress = progress
+	}
+}
+
+// WithContinueOnError embeds all the batches even if some fail after their
+// retries, returning the vectors of the texts embedded with a *PartialError
+// listing the failed batches, instead of cancelling the others at the first
+// failure. Long ingestions can so embed again only the failed texts.
+func WithContinueOnError() BatchOption {
+	return func(o *batchOptions) {
+		o.continueOnError = true
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the failed requests, the default
+// policy of the llms package by default.
+func WithRetryPolicy(policy llms.RetryPolicy) BatchOption {
+	return func(o *batchOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// EmbedInBatches splits the texts into batches, embeds each batch with a
+// request by a pool of workers, retrying the failed requests, and returns the
+// vectors in the order of the texts. The first request failing after its
+// retries cancels the others, unless WithContinueOnError is set.
+func EmbedInBatches(ctx context.Context, texts []string, embed EmbedFunc, opts ...BatchOption) ([][]float64, error) {
+	return embedInBatches(ctx, texts, embed, opts...)
+}
+
+// EmbedSparseInBatches is EmbedInBatches for sparse vectors.
+func EmbedSparseInBatches(
+	ctx context.Context,
+	texts []string,
+	embed func(ctx context.Context, texts []string) ([]SparseVector, error),
+	opts ...BatchOption,
+) ([]SparseVector, error) {
+	return embedInBatches(ctx, texts, embed, opts...)
+}
+
+func embedInBatches[V any](
+	ctx context.Context,
+	texts []string,
+	embed func(ctx context.Context, texts []string) ([]V, error),
+	opts ...BatchOption,
+) ([]V, error) {
+	o := batchOptions{
+		textsPerRequest: _defaultTextsPerRequest,
+		maxParallel:     _defaultMaxParallel,
+		retryPolicy:     llms.DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.textsPerRequest <= 0 {
+		o.textsPerRequest = len(texts)
+	}
+	if o.maxParallel <= 0 {
+		o.maxParallel = _defaultMaxParallel
+	}
+
+	vectors := make([]V, len(texts))
+	if len(texts) == 0 {
+		return vectors, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+		errs []*BatchError
+	)
+	// report records the result of a batch, cancelling the other batches if
+	// it failed, and reports the progress.
+	report := func(start, end int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, &BatchError{Start: start, End: end, Err: err})
+			if !o.continueOnError {
+				cancel()
+			}
+		} else {
+			done += end - start
+		}
+		if o.progress != nil {
+			progressErrs := make([]error, 0, len(errs))
+			for _, err := range errs {
+				progressErrs = append(progressErrs, err)
+			}
+			o.progress(done, len(texts), progressErrs)
+		}
+	}
+
+	type batch struct{ start, end int }
+	batches := make(chan batch)
+	workers := (len(texts) + o.textsPerRequest - 1) / o.textsPerRequest
+	if workers > o.maxParallel {
+		workers = o.maxParallel
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				// The batches sent before a failure cancelled the others are
+				// skipped.
+				if ctx.Err() != nil {
+					continue
+				}
+				vectorBatch, err := llms.Retry(ctx, o.retryPolicy, func() ([]V, error) {
+					return embed(ctx, texts[b.start:b.end])
+				})
+				if err == nil && len(vectorBatch) != b.end-b.start {
+					err = fmt.Errorf("%w: %d for %d texts", ErrVectorCount, len(vectorBatch), b.end-b.start)
+				}
+				if err == nil {
+					copy(vectors[b.start:b.end], vectorBatch)
+				}
+				report(b.start, b.end, err)
+			}
+		}()
+	}
+
+	for start := 0; start < len(texts) && ctx.Err() == nil; start += o.textsPerRequest {
+		end := start + o.textsPerRequest
+		if end > len(texts) {
+			end = len(texts)
+		}
+		select {
+		case batches <- batch{start: start, end: end}:
+		case <-ctx.Done():
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	switch {
+	case len(errs) > 0 && !o.continueOnError:
+		return nil, errs[0]
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case len(errs) > 0:
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Start < errs[j].Start })
+		return vectors, &PartialError{Errors: errs}
+	}
+	return vectors, nil
+}
+
+// EmbedChunks embeds texts longer than chunkSize runes as the weighted
+// average of the vectors of their chunks, see BatchTexts and CombineVectors.
+// The chunks of all the texts are embedded in batches with EmbedInBatches,
+// the progress being reported in chunks. With WithContinueOnError, the vectors
+// of the texts of the failed chunks are nil and the *PartialError lists the
+// batches of texts to embed again.
+func EmbedChunks(ctx context.Context, texts []string, chunkSize int, embed EmbedFunc, opts ...BatchOption) ([][]float64, error) { //nolint:lll
+	chunkedTexts := BatchTexts(texts, chunkSize)
+	var chunks []string
+	for i, textChunks := range chunkedTexts {
+		// Empty texts have no chunks but are embedded.
+		if len(textChunks) == 0 {
+			chunkedTexts[i] = []string{""}
+		}
+		chunks = append(chunks, chunkedTexts[i]...)
+	}
+
+	chunkVectors, err := EmbedInBatches(ctx, chunks, embed, opts...)
+	var partial *PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+
+	vectors := make([][]float64, 0, len(texts))
+	// textOf is the index of the text of each chunk.
+	textOf := make([]int, 0, len(chunks))
+	offset := 0
+	for i, textChunks := range chunkedTexts {
+		for range textChunks {
+			textOf = append(textOf, i)
+		}
+		if failed(chunkVectors[offset : offset+len(textChunks)]) {
+			vectors = append(vectors, nil)
+			offset += len(textChunks)
+			continue
+		}
+		lengths := make([]int, 0, len(textChunks))
+		for _, chunk := range textChunks {
+			// The chunk of an empty text weighs as much as a character.
+			length := len(chunk)
+			if length == 0 {
+				length = 1
+			}
+			lengths = append(lengths, length)
+		}
+		combined, err := CombineVectors(chunkVectors[offset:offset+len(textChunks)], lengths)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, combined)
+		offset += len(textChunks)
+	}
+
+	if partial != nil {
+		// The failed batches of chunks are reported as batches of texts.
+		textErrs := make([]*BatchError, 0, len(partial.Errors))
+		for _, err := range partial.Errors {
+			textErrs = append(textErrs, &BatchError{
+				Start: textOf[err.Start],
+				End:   textOf[err.End-1] + 1,
+				Err:   err.Err,
+			})
+		}
+		return vectors, &PartialError{Errors: textErrs}
+	}
+	return vectors, nil
+}
+
+// failed reports whether a vector is missing, its batch having failed.
+func failed(vectors [][]float64) bool {
+	for _, vector := range vectors {
+		if vector == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchedEmbedder is an embedder sending the documents of the wrapped
+// embedder in batches.
+type BatchedEmbedder struct {
+	Embedder
+	opts []BatchOption
+}
+
+var _ Embedder = BatchedEmbedder{}
+
+// NewBatchedEmbedder wraps the embedder to embed the documents in batches,
+// e.g. to embed many documents with concurrent requests.
+func NewBatchedEmbedder(embedder Embedder, opts ...BatchOption) BatchedEmbedder {
+	return BatchedEmbedder{Embedder: embedder, opts: opts}
+}
+
+// EmbedDocuments embeds the texts in batches with the wrapped embedder.
+func (e BatchedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	return EmbedInBatches(ctx, texts, e.Embedder.EmbedDocuments, e.opts...)
+}
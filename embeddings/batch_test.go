@@ -0,0 +1,204 @@
+package embeddings
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// lengthEmbedding embeds each text as a vector holding its length.
+func lengthEmbedding(_ context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		vectors = append(vectors, []float64{float64(len(text))})
+	}
+	return vectors, nil
+}
+
+func fastRetryPolicy() llms.RetryPolicy {
+	policy := llms.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	return policy
+}
+
+func TestEmbedInBatches(t *testing.T) {
+	t.Parallel()
+
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff", "ggggggg"}
+	var (
+		mu      sync.Mutex
+		batches [][]string
+		failed  atomic.Bool
+		running atomic.Int32
+		maxRun  atomic.Int32
+	)
+	embed := func(ctx context.Context, batch []string) ([][]float64, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > maxRun.Load() {
+			maxRun.Store(n)
+		}
+		time.Sleep(5 * time.Millisecond)
+		// The batch of the third text fails once.
+		if batch[0] == "ccc" && failed.CompareAndSwap(false, true) {
+			return nil, &llms.StatusError{StatusCode: http.StatusTooManyRequests}
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+		return lengthEmbedding(ctx, batch)
+	}
+
+	vectors, err := EmbedInBatches(context.Background(), texts, embed,
+		WithTextsPerRequest(2), WithMaxParallel(2), WithRetryPolicy(fastRetryPolicy()))
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}, {2}, {3}, {4}, {5}, {6}, {7}}, vectors)
+	assert.Len(t, batches, 4)
+	assert.True(t, failed.Load())
+	assert.LessOrEqual(t, maxRun.Load(), int32(2))
+}
+
+func TestEmbedInBatchesErrors(t *testing.T) {
+	t.Parallel()
+
+	missing := func(context.Context, []string) ([][]float64, error) { return [][]float64{{1}}, nil }
+	_, err := EmbedInBatches(context.Background(), []string{"a", "b"}, missing)
+	require.ErrorIs(t, err, ErrVectorCount)
+
+	calls := 0
+	permanent := &llms.StatusError{StatusCode: http.StatusUnauthorized}
+	failing := func(context.Context, []string) ([][]float64, error) {
+		calls++
+		return nil, permanent
+	}
+	_, err = EmbedInBatches(context.Background(), []string{"a", "b", "c"}, failing, WithTextsPerRequest(1))
+	require.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+
+	vectors, err := EmbedInBatches(context.Background(), nil, failing)
+	require.NoError(t, err)
+	assert.Empty(t, vectors)
+}
+
+func TestEmbedInBatchesProgress(t *testing.T) {
+	t.Parallel()
+
+	permanent := &llms.StatusError{StatusCode: http.StatusBadRequest}
+	// The batches of bb always fail.
+	embed := func(ctx context.Context, batch []string) ([][]float64, error) {
+		for _, text := range batch {
+			if text == "bb" {
+				return nil, permanent
+			}
+		}
+		return lengthEmbedding(ctx, batch)
+	}
+	type progress struct{ done, total, errs int }
+	var reports []progress
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	vectors, err := EmbedInBatches(context.Background(), texts, embed,
+		WithTextsPerRequest(2), WithMaxParallel(3), WithContinueOnError(),
+		WithProgress(func(done, total int, errs []error) {
+			reports = append(reports, progress{done, total, len(errs)})
+		}))
+
+	var partial *PartialError
+	require.ErrorAs(t, err, &partial)
+	require.ErrorIs(t, err, permanent)
+	require.Len(t, partial.Errors, 1)
+	assert.Equal(t, 0, partial.Errors[0].Start)
+	assert.Equal(t, 2, partial.Errors[0].End)
+	assert.Equal(t, [][]float64{nil, nil, {3}, {4}, {5}}, vectors)
+
+	require.Len(t, reports, 3)
+	assert.Equal(t, progress{3, 5, 1}, reports[2])
+	for _, report := range reports {
+		assert.Equal(t, 5, report.total)
+	}
+
+	// The partial failures of the chunks are reported by text.
+	vectors, err = EmbedChunks(context.Background(), []string{"a", "bbbb", "cc"}, 2, embed,
+		WithTextsPerRequest(1), WithContinueOnError())
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Errors, 2)
+	for _, batchErr := range partial.Errors {
+		assert.Equal(t, 1, batchErr.Start)
+		assert.Equal(t, 2, batchErr.End)
+	}
+	assert.Equal(t, [][]float64{{1}, nil, {1}}, vectors)
+}
+
+func TestEmbedSparseInBatches(t *testing.T) {
+	t.Parallel()
+
+	embed := func(_ context.Context, batch []string) ([]SparseVector, error) {
+		vectors := make([]SparseVector, 0, len(batch))
+		for _, text := range batch {
+			vectors = append(vectors, SparseVector{Indices: []int{len(text)}, Values: []float64{1}})
+		}
+		return vectors, nil
+	}
+	vectors, err := EmbedSparseInBatches(context.Background(), []string{"a", "bb", "ccc"}, embed, WithTextsPerRequest(2))
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	assert.Equal(t, []int{3}, vectors[2].Indices)
+}
+
+func TestEmbedChunks(t *testing.T) {
+	t.Parallel()
+
+	var requests [][]string
+	embed := func(ctx context.Context, batch []string) ([][]float64, error) {
+		requests = append(requests, batch)
+		return lengthEmbedding(ctx, batch)
+	}
+	vectors, err := EmbedChunks(context.Background(), []string{"foo bar", "x", "baz"}, 4, embed, WithTextsPerRequest(3))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"foo ", "bar", "x"}, {"baz"}}, requests)
+	// The vectors of the chunks are averaged and normalized.
+	require.Len(t, vectors, 3)
+	assert.Equal(t, []float64{1}, vectors[0])
+	assert.Equal(t, []float64{1}, vectors[2])
+
+	// Empty texts are embedded too.
+	constant := func(context.Context, []string) ([][]float64, error) { return [][]float64{{2}}, nil }
+	vectors, err = EmbedChunks(context.Background(), []string{""}, 4, constant)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}}, vectors)
+}
+
+func TestBatchedEmbedder(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingEmbedder{}
+	e := NewBatchedEmbedder(inner, WithTextsPerRequest(2))
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"a", "bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}, {2}, {3}}, vectors)
+	assert.Equal(t, 2, inner.calls)
+
+	vector, err := e.EmbedQuery(context.Background(), "dddd")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{4}, vector)
+}
+
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	e.calls++
+	return lengthEmbedding(ctx, texts)
+}
+
+func (e *countingEmbedder) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	vectors, err := lengthEmbedding(ctx, []string{text})
+	return vectors[0], err
+}
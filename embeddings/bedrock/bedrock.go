@@ -0,0 +1,90 @@
+package bedrock
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/bedrock/internal/bedrockclient"
+)
+
+var (
+	ErrMissingRegion      = errors.New("missing the AWS region, set it in the AWS_REGION environment variable")
+	ErrMissingCredentials = errors.New("missing the AWS credentials, set them in the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables") //nolint:lll
+)
+
+// Bedrock is the embedder using the Amazon Titan text embeddings models of
+// Amazon Bedrock.
+type Bedrock struct {
+	client *bedrockclient.Client
+	// Model is the id of the model, e.g. amazon.titan-embed-text-v2:0.
+	Model string
+	// Dimensions is the number of dimensions of the embeddings of the v2
+	// models, 1024, 512 or 256. The model default if zero.
+	Dimensions int
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+
+	region      string
+	credentials bedrockclient.Credentials
+	clientOpts  []bedrockclient.Option
+}
+
+var _ embeddings.Embedder = Bedrock{}
+
+// NewBedrock creates a new Bedrock with options. The region and the
+// credentials are read from the AWS environment variables if not set.
+func NewBedrock(opts ...Option) (Bedrock, error) {
+	b, err := applyClientOptions(opts...)
+	if err != nil {
+		return Bedrock{}, err
+	}
+
+	return b, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e Bedrock) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The models embed a single text per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(1)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+		e.createEmbedding,
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e Bedrock) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.createEmbedding(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+func (e Bedrock) createEmbedding(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		resp, err := e.client.CreateEmbedding(ctx, e.Model, &bedrockclient.EmbeddingRequest{
+			InputText:  text,
+			Dimensions: e.Dimensions,
+		})
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, resp.Embedding)
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,87 @@
+package bedrock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestBedrockEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		inputs []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/model/amazon.titan-embed-text-v2%3A0/invoke", r.URL.EscapedPath())
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/bedrock/aws4_request")
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, 256.0, req["dimensions"])
+		mu.Lock()
+		inputs = append(inputs, req["inputText"].(string))
+		mu.Unlock()
+		fmt.Fprint(w, `{"embedding":[0.6,0.8],"inputTextTokenCount":3}`)
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewBedrock(
+		WithRegion("eu-west-1"),
+		WithCredentials("AKID", "secret", "session"),
+		WithBaseURL(server.URL),
+		WithDimensions(256),
+		WithBatchOptions(embeddings.WithMaxParallel(2)),
+	)
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello\nworld!")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.6, 0.8}, embedding)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 3)
+	assert.ElementsMatch(t, []string{"Hello world!", "Hello world", "The world is ending", "good bye"}, inputs)
+}
+
+func TestBedrockEmbeddingsError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Amzn-Errortype", "AccessDeniedException:http://internal.amazon.com/coral/")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"You don't have access to the model with the specified model ID."}`)
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewBedrock(WithRegion("us-east-1"), WithCredentials("AKID", "secret", ""), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "Hello world!")
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusForbidden, statusErr.StatusCode)
+	assert.Equal(t, "AccessDeniedException", statusErr.Code)
+}
+
+func TestNewBedrockMissingRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	_, err := NewBedrock(WithCredentials("AKID", "secret", ""))
+	require.ErrorIs(t, err, ErrMissingRegion)
+}
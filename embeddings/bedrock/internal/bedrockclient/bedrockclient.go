@@ -0,0 +1,141 @@
+package bedrockclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrEmptyResponse is returned when the API returns an empty embedding.
+var ErrEmptyResponse = errors.New("empty response")
+
+// signingName is the service name of the Bedrock runtime in the signatures.
+const signingName = "bedrock"
+
+// Credentials are the AWS credentials signing the requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials.
+	SessionToken string
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client of the Bedrock runtime API.
+type Client struct {
+	baseURL     string
+	region      string
+	credentials Credentials
+	httpClient  Doer
+	now         func() time.Time
+}
+
+// Option is an option of the client.
+type Option func(*Client)
+
+// WithBaseURL sends the requests to the base url instead of the regional
+// endpoint, e.g. a VPC endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http client used for the requests.
+func WithHTTPClient(httpClient Doer) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New returns a new client of the Bedrock runtime API of the region.
+func New(region string, credentials Credentials, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     "https://bedrock-runtime." + region + ".amazonaws.com",
+		region:      region,
+		credentials: credentials,
+		httpClient:  http.DefaultClient,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// EmbeddingRequest is a request to a Titan text embeddings model.
+type EmbeddingRequest struct {
+	InputText string `json:"inputText"`
+	// Dimensions is the number of dimensions of the embedding of the v2
+	// models, 1024, 512 or 256. The model default if zero.
+	Dimensions int `json:"dimensions,omitempty"`
+}
+
+// EmbeddingResponse is the embedding of a text.
+type EmbeddingResponse struct {
+	Embedding           []float64 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+// CreateEmbedding invokes the model to embed the text of the request.
+func (c *Client) CreateEmbedding(ctx context.Context, model string, r *EmbeddingRequest) (*EmbeddingResponse, error) {
+	var response EmbeddingResponse
+	if err := c.invoke(ctx, model, r, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Embedding) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return &response, nil
+}
+
+// invoke invokes the model with the payload and decodes the response.
+func (c *Client) invoke(ctx context.Context, model string, payload, response any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	// Model ids contain colons, escaped in the path as the AWS SDKs do.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/model/"+uriEncode(model)+"/invoke", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	llms.SetRequestHeaders(req)
+	signV4(req, body, c.credentials, c.region, signingName, c.now())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&errResp)
+		// The error type header is e.g. "ValidationException:http://...".
+		code, _, _ := strings.Cut(res.Header.Get("X-Amzn-Errortype"), ":")
+		return llms.NewStatusError(res, code, errResp.Message)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
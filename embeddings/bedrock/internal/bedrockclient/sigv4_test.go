@@ -0,0 +1,38 @@
+package bedrockclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSignV4 checks the signature of the get-vanilla request of the AWS
+// Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	signV4(req, nil, Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, "+
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestCanonicalURI(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest(http.MethodPost,
+		"https://bedrock-runtime.us-east-1.amazonaws.com/model/"+uriEncode("amazon.titan-embed-text-v2:0")+"/invoke", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/model/amazon.titan-embed-text-v2%3A0/invoke", req.URL.EscapedPath())
+	assert.Equal(t, "/model/amazon.titan-embed-text-v2%253A0/invoke", canonicalURI(req))
+}
@@ -0,0 +1,124 @@
+package bedrock
+
+import (
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/bedrock/internal/bedrockclient"
+)
+
+const (
+	_defaultModel         = "amazon.titan-embed-text-v2:0"
+	_defaultBatchSize     = 512
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Bedrock)
+
+// WithModel is an option for specifying the model id, e.g.
+// amazon.titan-embed-text-v1. The default is amazon.titan-embed-text-v2:0.
+func WithModel(model string) Option {
+	return func(p *Bedrock) {
+		p.Model = model
+	}
+}
+
+// WithDimensions is an option for specifying the number of dimensions of the
+// embeddings of the v2 models, 1024, 512 or 256.
+func WithDimensions(dimensions int) Option {
+	return func(p *Bedrock) {
+		p.Dimensions = dimensions
+	}
+}
+
+// WithRegion is an option for specifying the AWS region. If not set, the
+// region is read from the AWS_REGION or AWS_DEFAULT_REGION environment
+// variables.
+func WithRegion(region string) Option {
+	return func(p *Bedrock) {
+		p.region = region
+	}
+}
+
+// WithCredentials is an option for specifying the AWS credentials, the
+// session token being set for temporary credentials. If not set, the
+// credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables.
+func WithCredentials(accessKeyID, secretAccessKey, sessionToken string) Option {
+	return func(p *Bedrock) {
+		p.credentials = bedrockclient.Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+		}
+	}
+}
+
+// WithBaseURL is an option for sending the requests to the base url instead
+// of the regional endpoint, e.g. a VPC endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(p *Bedrock) {
+		p.clientOpts = append(p.clientOpts, bedrockclient.WithBaseURL(baseURL))
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client bedrockclient.Doer) Option {
+	return func(p *Bedrock) {
+		p.clientOpts = append(p.clientOpts, bedrockclient.WithHTTPClient(client))
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *Bedrock) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size.
+func WithBatchSize(batchSize int) Option {
+	return func(p *Bedrock) {
+		p.BatchSize = batchSize
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithMaxParallel to embed several texts at once.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *Bedrock) {
+		p.batchOptions = opts
+	}
+}
+
+func applyClientOptions(opts ...Option) (Bedrock, error) {
+	b := &Bedrock{
+		Model:         _defaultModel,
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		region:        os.Getenv("AWS_REGION"),
+		credentials: bedrockclient.Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+	if b.region == "" {
+		b.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.region == "" {
+		return Bedrock{}, ErrMissingRegion
+	}
+	if b.credentials.AccessKeyID == "" || b.credentials.SecretAccessKey == "" {
+		return Bedrock{}, ErrMissingCredentials
+	}
+	b.client = bedrockclient.New(b.region, b.credentials, b.clientOpts...)
+
+	return *b, nil
+}
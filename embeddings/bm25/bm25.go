@@ -0,0 +1,149 @@
+package bm25
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// ErrNotFitted is returned when a text is embedded before the statistics of
+// the corpus are computed with Fit.
+var ErrNotFitted = errors.New("bm25: not fitted, call Fit with the corpus first")
+
+// BM25 is the sparse embedder weighting the words of the texts with Okapi
+// BM25. The index of a word is the 32-bit FNV-1a hash of the word.
+//
+// The documents are weighted by the saturated frequency of their words and
+// the queries by the inverse document frequency of their words, so that the
+// dot product of a query and a document is their BM25 score. The document
+// frequencies are computed from the corpus with Fit.
+type BM25 struct {
+	k1        float64
+	b         float64
+	stopWords map[string]bool
+
+	documents         int
+	averageLength     float64
+	documentFrequency map[int]int
+}
+
+var _ embeddings.SparseEmbedder = (*BM25)(nil)
+
+// New returns a new BM25 with options.
+func New(opts ...Option) *BM25 {
+	b := &BM25{
+		k1: _defaultK1,
+		b:  _defaultB,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Fit computes the document frequencies of the words and the average length
+// of the texts of the corpus. It must not be called concurrently with the
+// other methods.
+func (e *BM25) Fit(texts []string) {
+	e.documents = len(texts)
+	e.documentFrequency = make(map[int]int)
+	total := 0
+	for _, text := range texts {
+		frequencies, length := e.termFrequencies(text)
+		total += length
+		for index := range frequencies {
+			e.documentFrequency[index]++
+		}
+	}
+	if e.documents > 0 {
+		e.averageLength = float64(total) / float64(e.documents)
+	}
+}
+
+// EmbedDocuments returns the BM25 term frequency weights of the texts.
+func (e *BM25) EmbedDocuments(_ context.Context, texts []string) ([]embeddings.SparseVector, error) {
+	if e.documents == 0 {
+		return nil, ErrNotFitted
+	}
+	vectors := make([]embeddings.SparseVector, 0, len(texts))
+	for _, text := range texts {
+		frequencies, length := e.termFrequencies(text)
+		norm := e.k1 * (1 - e.b + e.b*float64(length)/e.averageLength)
+		weights := make(map[int]float64, len(frequencies))
+		for index, frequency := range frequencies {
+			tf := float64(frequency)
+			weights[index] = tf * (e.k1 + 1) / (tf + norm)
+		}
+		vectors = append(vectors, sparseVector(weights))
+	}
+	return vectors, nil
+}
+
+// EmbedQuery returns the inverse document frequency weights of the words of
+// the text, normalized to sum to one.
+func (e *BM25) EmbedQuery(_ context.Context, text string) (embeddings.SparseVector, error) {
+	if e.documents == 0 {
+		return embeddings.SparseVector{}, ErrNotFitted
+	}
+	frequencies, _ := e.termFrequencies(text)
+	weights := make(map[int]float64, len(frequencies))
+	var sum float64
+	for index := range frequencies {
+		df := float64(e.documentFrequency[index])
+		idf := math.Log(1 + (float64(e.documents)-df+0.5)/(df+0.5))
+		weights[index] = idf
+		sum += idf
+	}
+	if sum > 0 {
+		for index := range weights {
+			weights[index] /= sum
+		}
+	}
+	return sparseVector(weights), nil
+}
+
+// termFrequencies returns the frequencies of the words of the text by index
+// and the number of words of the text.
+func (e *BM25) termFrequencies(text string) (map[int]int, int) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	frequencies := make(map[int]int, len(words))
+	length := 0
+	for _, word := range words {
+		if e.stopWords[word] {
+			continue
+		}
+		frequencies[index(word)]++
+		length++
+	}
+	return frequencies, length
+}
+
+func index(word string) int {
+	h := fnv.New32a()
+	h.Write([]byte(word))
+	return int(h.Sum32())
+}
+
+// sparseVector returns the weights as a sparse vector sorted by index.
+func sparseVector(weights map[int]float64) embeddings.SparseVector {
+	v := embeddings.SparseVector{
+		Indices: make([]int, 0, len(weights)),
+		Values:  make([]float64, 0, len(weights)),
+	}
+	for index := range weights {
+		v.Indices = append(v.Indices, index)
+	}
+	sort.Ints(v.Indices)
+	for _, index := range v.Indices {
+		v.Values = append(v.Values, weights[index])
+	}
+	return v
+}
@@ -0,0 +1,74 @@
+package bm25
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+func dot(a, b embeddings.SparseVector) float64 {
+	weights := make(map[int]float64, len(a.Indices))
+	for i, index := range a.Indices {
+		weights[index] = a.Values[i]
+	}
+	var sum float64
+	for i, index := range b.Indices {
+		sum += weights[index] * b.Values[i]
+	}
+	return sum
+}
+
+func TestBM25(t *testing.T) {
+	t.Parallel()
+
+	corpus := []string{
+		"The quick brown fox jumps over the lazy dog.",
+		"A fox is a small wild animal.",
+		"Dogs are loyal pets.",
+	}
+	e := New(WithStopWords("the", "a", "is", "are", "over"))
+
+	_, err := e.EmbedQuery(context.Background(), "fox")
+	require.ErrorIs(t, err, ErrNotFitted)
+
+	e.Fit(corpus)
+	docs, err := e.EmbedDocuments(context.Background(), corpus)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	// The stop words are ignored: quick, brown, fox, jumps, lazy, dog.
+	assert.Len(t, docs[0].Indices, 6)
+	assert.IsIncreasing(t, docs[0].Indices)
+
+	query, err := e.EmbedQuery(context.Background(), "Wild fox")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, query.Values[0]+query.Values[1], 1e-9)
+
+	// The document with both words scores best, the one with none zero.
+	scores := []float64{dot(query, docs[0]), dot(query, docs[1]), dot(query, docs[2])}
+	assert.Greater(t, scores[1], scores[0])
+	assert.Greater(t, scores[0], 0.0)
+	assert.Zero(t, scores[2])
+}
+
+func TestBM25Weights(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithK1(1.5), WithB(0))
+	e.Fit([]string{"apple apple", "banana"})
+
+	docs, err := e.EmbedDocuments(context.Background(), []string{"apple apple"})
+	require.NoError(t, err)
+	// tf * (k1 + 1) / (tf + k1) without length normalization.
+	assert.Equal(t, []int{index("apple")}, docs[0].Indices)
+	assert.InDelta(t, 2*2.5/3.5, docs[0].Values[0], 1e-9)
+
+	query, err := e.EmbedQuery(context.Background(), "banana")
+	require.NoError(t, err)
+	assert.Equal(t, []int{index("banana")}, query.Indices)
+	assert.InDelta(t, 1, query.Values[0], 1e-9)
+	assert.False(t, math.IsNaN(query.Values[0]))
+}
@@ -0,0 +1,36 @@
+package bm25
+
+const (
+	_defaultK1 = 1.2
+	_defaultB  = 0.75
+)
+
+// Option is a function type that can be used to modify the embedder.
+type Option func(p *BM25)
+
+// WithK1 is an option for specifying the term frequency saturation, 1.2 by
+// default.
+func WithK1(k1 float64) Option {
+	return func(p *BM25) {
+		p.k1 = k1
+	}
+}
+
+// WithB is an option for specifying the document length normalization, from
+// 0 for none to 1 for full, 0.75 by default.
+func WithB(b float64) Option {
+	return func(p *BM25) {
+		p.b = b
+	}
+}
+
+// WithStopWords is an option for specifying the words ignored, e.g. "the"
+// and "a". The words are lower case.
+func WithStopWords(words ...string) Option {
+	return func(p *BM25) {
+		p.stopWords = make(map[string]bool, len(words))
+		for _, word := range words {
+			p.stopWords[word] = true
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cohere
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/cohere"
+)
+
+// Cohere is the embedder using the Cohere Embed API. The documents and the
+// queries are embedded with the search_document and search_query input types
+// required by the embed v3 models.
+type Cohere struct {
+	client *cohere.LLM
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+}
+
+var _ embeddings.Embedder = Cohere{}
+
+// NewCohere creates a new Cohere with options. Options for client, strip new lines and batch.
+func NewCohere(opts ...Option) (Cohere, error) {
+	o, err := applyClientOptions(opts...)
+	if err != nil {
+		return Cohere{}, err
+	}
+
+	return o, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e Cohere) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The API embeds at most 96 texts per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(96)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+		e.client.CreateEmbedding,
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e Cohere) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.client.CreateEmbeddingWithInputType(ctx, []string{text}, cohere.InputTypeSearchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
@@ -0,0 +1,49 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/cohere"
+)
+
+func TestCohereEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var inputTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embed", r.URL.Path)
+		var req struct {
+			Texts     []string `json:"texts"`
+			InputType string   `json:"input_type"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		inputTypes = append(inputTypes, req.InputType)
+		embeddings := make([][]float64, len(req.Texts))
+		for i := range req.Texts {
+			embeddings[i] = []float64{3, 4, 12}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings}))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := cohere.New(cohere.WithToken("token"), cohere.WithBaseURL(server.URL),
+		cohere.WithEmbeddingDimensions(2))
+	require.NoError(t, err)
+	e, err := NewCohere(WithClient(*client))
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello world!")
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.6, 0.8}, embedding, 1e-9)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 3)
+	assert.Equal(t, []string{"search_query", "search_document"}, inputTypes)
+}
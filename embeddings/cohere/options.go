@@ -0,0 +1,64 @@
+package cohere
+
+import (
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/cohere"
+)
+
+const (
+	_defaultBatchSize     = 512
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Cohere)
+
+// WithClient is an option for providing the LLM client.
+func WithClient(client cohere.LLM) Option {
+	return func(p *Cohere) {
+		p.client = &client
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *Cohere) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size.
+func WithBatchSize(batchSize int) Option {
+	return func(p *Cohere) {
+		p.BatchSize = batchSize
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *Cohere) {
+		p.batchOptions = opts
+	}
+}
+
+func applyClientOptions(opts ...Option) (Cohere, error) {
+	o := &Cohere{
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.client == nil {
+		client, err := cohere.New()
+		if err != nil {
+			return Cohere{}, err
+		}
+		o.client = client
+	}
+
+	return *o, nil
+}
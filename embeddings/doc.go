@@ -6,8 +6,32 @@ The main components of this package are:
 
 - Embedder interface: a common interface for creating vector embeddings from texts.
 - OpenAI: an Embedder implementation using the OpenAI API.
-- VertexAIPaLM: an Embedder implementation using Google PaLM (VertexAI) API.
+- VertexAIPaLM: an Embedder implementation using the Google Vertex AI text-embedding models.
+- Bedrock: an Embedder implementation using the Amazon Titan embeddings models of Amazon Bedrock.
+- Cohere: an Embedder implementation using the Cohere Embed API, embedding
+  documents and queries with their own input types.
+- VoyageAI and Jina: Embedder implementations using the Voyage AI and Jina
+  embeddings APIs, suited to code and long-document retrieval. Jina can embed
+  the chunks of a document with late chunking, keeping their context.
+- ONNX: an Embedder implementation running sentence-transformers models with
+  ONNX Runtime, for deployments without access to external APIs.
+- Ollama and LlamaCpp: Embedder implementations using local Ollama and llama.cpp
+  servers, for RAG pipelines running entirely on local models.
+- MultimodalEmbedder interface: embeds texts and images in the same space,
+  implemented by VertexAIMultimodal and CLIP (ONNX). ImageEmbedder indexes
+  images in the vector stores, searched by their descriptions.
+- HyDE: an Embedder wrapper expanding the queries into hypothetical documents
+  with an LLM before embedding them, improving the recall of short queries.
+- InstructedEmbedder: an Embedder wrapper prefixing the queries and the
+  documents with the instructions of models such as E5, BGE and Nomic, which
+  embed queries and documents differently.
+- SparseEmbedder interface: creates sparse term-weight vectors for hybrid
+  searches, implemented by BM25 and SPLADE (ONNX).
 - Helper functions: utility functions for embedding, such as `batchTexts` and `maybeRemoveNewLines`.
+- Batching: `EmbedInBatches` and `BatchedEmbedder` send the texts in batches of
+  limited size with a pool of workers, retrying the failed requests, reporting
+  the progress with `WithProgress` and returning partial results with
+  `WithContinueOnError`.
 
 The package provides a flexible way to handle different APIs for generating
 embeddings by using the Embedder interface as an abstraction.
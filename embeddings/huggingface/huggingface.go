@@ -16,6 +16,8 @@ type Huggingface struct {
 
 	StripNewLines bool
 	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
 }
 
 var _ embeddings.Embedder = &Huggingface{}
@@ -30,32 +32,15 @@ func NewHuggingface(opts ...Option) (*Huggingface, error) {
 }
 
 func (e *Huggingface) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
-	batchedTexts := embeddings.BatchTexts(
+	return embeddings.EmbedChunks(
+		ctx,
 		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
 		e.BatchSize,
+		func(ctx context.Context, texts []string) ([][]float64, error) {
+			return e.client.CreateEmbedding(ctx, texts, e.Model, e.Task)
+		},
+		e.batchOptions...,
 	)
-
-	emb := make([][]float64, 0, len(texts))
-	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts, e.Model, e.Task)
-		if err != nil {
-			return nil, err
-		}
-
-		textLengths := make([]int, 0, len(texts))
-		for _, text := range texts {
-			textLengths = append(textLengths, len(text))
-		}
-
-		combined, err := embeddings.CombineVectors(curTextEmbeddings, textLengths)
-		if err != nil {
-			return nil, err
-		}
-
-		emb = append(emb, combined)
-	}
-
-	return emb, nil
 }
 
 func (e *Huggingface) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
@@ -1,6 +1,7 @@
 package huggingface
 
 import (
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/huggingface"
 )
 
@@ -49,6 +50,14 @@ func WithBatchSize(batchSize int) Option {
 	}
 }
 
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *Huggingface) {
+		p.batchOptions = opts
+	}
+}
+
 func applyOptions(opts ...Option) (*Huggingface, error) {
 	o := &Huggingface{
 		StripNewLines: _defaultStripNewLines,
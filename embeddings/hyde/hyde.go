@@ -0,0 +1,110 @@
+package hyde
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+var (
+	// ErrMissingQuestion is returned when the prompt has no question input
+	// variable.
+	ErrMissingQuestion = errors.New("missing the question input variable of the prompt")
+	// ErrUnexpectedResponseLength is returned when the LLM returns a number
+	// of generations different from the number of prompts.
+	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
+)
+
+// HyDE is the embedder of Hypothetical Document Embeddings: the query is
+// expanded into hypothetical documents answering it, generated by an LLM,
+// and embedded as the average of their embeddings. As the hypothetical
+// documents are closer to the documents than short queries, the recall of
+// the searches improves. The documents are embedded by the wrapped embedder.
+//
+// See https://arxiv.org/abs/2212.10496.
+type HyDE struct {
+	embeddings.Embedder
+	llm llms.LLM
+
+	prompt         prompts.PromptTemplate
+	numGenerations int
+	includeQuery   bool
+	callOptions    []llms.CallOption
+}
+
+var _ embeddings.Embedder = (*HyDE)(nil)
+
+// NewHyDE returns an embedder generating the hypothetical documents with the
+// LLM and embedding them with the embedder.
+func NewHyDE(llm llms.LLM, embedder embeddings.Embedder, opts ...Option) (*HyDE, error) {
+	h := &HyDE{
+		Embedder:       embedder,
+		llm:            llm,
+		prompt:         prompts.NewPromptTemplate(_defaultPrompt, []string{"question"}),
+		numGenerations: _defaultNumGenerations,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if !hasQuestion(h.prompt) {
+		return nil, ErrMissingQuestion
+	}
+	if h.numGenerations < 1 {
+		h.numGenerations = _defaultNumGenerations
+	}
+
+	return h, nil
+}
+
+// EmbedQuery generates the hypothetical documents answering the query and
+// returns the average of their embeddings, rescaled to unit length.
+func (e *HyDE) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	prompt, err := e.prompt.Format(map[string]any{"question": text})
+	if err != nil {
+		return nil, fmt.Errorf("format prompt: %w", err)
+	}
+	inputs := make([]string, e.numGenerations)
+	for i := range inputs {
+		inputs[i] = prompt
+	}
+	generations, err := e.llm.Generate(ctx, inputs, e.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("generate hypothetical documents: %w", err)
+	}
+	if len(generations) != len(inputs) {
+		return nil, ErrUnexpectedResponseLength
+	}
+
+	documents := make([]string, 0, len(generations)+1)
+	for _, generation := range generations {
+		documents = append(documents, strings.TrimSpace(generation.Text))
+	}
+	if e.includeQuery {
+		documents = append(documents, text)
+	}
+
+	vectors, err := e.Embedder.EmbedDocuments(ctx, documents)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]int, len(vectors))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return embeddings.CombineVectors(vectors, weights)
+}
+
+func hasQuestion(prompt prompts.PromptTemplate) bool {
+	for _, variable := range prompt.GetInputVariables() {
+		if variable == "question" {
+			return true
+		}
+	}
+	return false
+}
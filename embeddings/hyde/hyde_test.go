@@ -0,0 +1,121 @@
+package hyde
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+type fakeLLM struct {
+	prompts []string
+	opts    llms.CallOptions
+	err     error
+}
+
+func (l *fakeLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	generations, err := l.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	return generations[0].Text, nil
+}
+
+func (l *fakeLLM) Generate(_ context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	l.prompts = append(l.prompts, prompts...)
+	for _, opt := range options {
+		opt(&l.opts)
+	}
+	generations := make([]*llms.Generation, len(prompts))
+	for i := range prompts {
+		// The generations answer in the direction of their rank.
+		generations[i] = &llms.Generation{Text: []string{" x \n", "y"}[i%2]}
+	}
+	return generations, nil
+}
+
+// axisEmbedder embeds x and y as unit vectors and anything else as their sum.
+type axisEmbedder struct {
+	texts []string
+}
+
+func (e *axisEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	e.texts = append(e.texts, texts...)
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		switch text {
+		case "x":
+			vectors[i] = []float64{1, 0}
+		case "y":
+			vectors[i] = []float64{0, 1}
+		default:
+			vectors[i] = []float64{1, 1}
+		}
+	}
+	return vectors, nil
+}
+
+func (e *axisEmbedder) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	vectors, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func TestHyDE(t *testing.T) {
+	t.Parallel()
+
+	llm := &fakeLLM{}
+	embedder := &axisEmbedder{}
+	e, err := NewHyDE(llm, embedder, WithNumGenerations(2), WithCallOptions(llms.WithTemperature(0.7)))
+	require.NoError(t, err)
+
+	vector, err := e.EmbedQuery(context.Background(), "What is x?")
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.70710678, 0.70710678}, vector, 1e-6)
+	assert.Equal(t, []string{"x", "y"}, embedder.texts)
+	require.Len(t, llm.prompts, 2)
+	assert.Contains(t, llm.prompts[0], "Question: What is x?\n")
+	assert.InDelta(t, 0.7, llm.opts.Temperature, 1e-9)
+
+	// The documents are embedded by the wrapped embedder.
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"x"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1, 0}}, vectors)
+}
+
+func TestHyDEIncludeQuery(t *testing.T) {
+	t.Parallel()
+
+	embedder := &axisEmbedder{}
+	e, err := NewHyDE(&fakeLLM{}, embedder,
+		WithIncludeQuery(true),
+		WithPrompt(prompts.NewPromptTemplate("Answer {{.question}}", []string{"question"})))
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "y")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, embedder.texts)
+}
+
+func TestHyDEErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewHyDE(&fakeLLM{}, &axisEmbedder{},
+		WithPrompt(prompts.NewPromptTemplate("{{.query}}", []string{"query"})))
+	require.ErrorIs(t, err, ErrMissingQuestion)
+
+	errLLM := errors.New("llm error")
+	e, err := NewHyDE(&fakeLLM{err: errLLM}, &axisEmbedder{})
+	require.NoError(t, err)
+	_, err = e.EmbedQuery(context.Background(), "x")
+	require.ErrorIs(t, err, errLLM)
+}
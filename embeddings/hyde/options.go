@@ -0,0 +1,51 @@
+package hyde
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+const (
+	_defaultPrompt = `Please write a passage to answer the question.
+Question: {{.question}}
+Passage:`
+	_defaultNumGenerations = 1
+)
+
+// Option is a function type that can be used to modify the embedder.
+type Option func(p *HyDE)
+
+// WithPrompt is an option for specifying the prompt generating the
+// hypothetical documents from the question input variable, e.g. a prompt
+// asking for a scientific paper passage for a corpus of papers.
+func WithPrompt(prompt prompts.PromptTemplate) Option {
+	return func(p *HyDE) {
+		p.prompt = prompt
+	}
+}
+
+// WithNumGenerations is an option for specifying the number of hypothetical
+// documents generated for a query, 1 by default. More documents make the
+// embedding of the query less dependent on a single generation, at the cost
+// of more tokens; set a temperature with WithCallOptions to diversify them.
+func WithNumGenerations(n int) Option {
+	return func(p *HyDE) {
+		p.numGenerations = n
+	}
+}
+
+// WithIncludeQuery is an option for specifying whether the query itself is
+// embedded and averaged with the hypothetical documents, false by default.
+func WithIncludeQuery(includeQuery bool) Option {
+	return func(p *HyDE) {
+		p.includeQuery = includeQuery
+	}
+}
+
+// WithCallOptions is an option for specifying the options of the LLM calls
+// generating the hypothetical documents, e.g. llms.WithTemperature.
+func WithCallOptions(opts ...llms.CallOption) Option {
+	return func(p *HyDE) {
+		p.callOptions = opts
+	}
+}
@@ -0,0 +1,64 @@
+package embeddings
+
+import "context"
+
+// The prefixes of the queries and of the documents expected by common
+// embedding models trained with instructions, see NewInstructedEmbedder.
+const (
+	// E5QueryPrefix and E5DocumentPrefix are the prefixes of the E5 models,
+	// e.g. intfloat/e5-base-v2 and intfloat/multilingual-e5-large.
+	E5QueryPrefix    = "query: "
+	E5DocumentPrefix = "passage: "
+	// BGEQueryPrefix is the prefix of the queries of the English BGE models,
+	// e.g. BAAI/bge-base-en-v1.5. The documents have no prefix.
+	BGEQueryPrefix = "Represent this sentence for searching relevant passages: "
+	// NomicQueryPrefix and NomicDocumentPrefix are the prefixes of the Nomic
+	// models, e.g. nomic-embed-text.
+	NomicQueryPrefix    = "search_query: "
+	NomicDocumentPrefix = "search_document: "
+)
+
+// InstructedEmbedder is an embedder prefixing the queries and the documents
+// with the instructions of the model, e.g. "query: " and "passage: " for the
+// E5 models, which embed the queries and the documents differently. Without
+// them, the queries and the documents of these models are embedded the same
+// way and the retrievers return less relevant documents.
+//
+// The embedders of the APIs taking the type of the inputs, e.g. Cohere,
+// VoyageAI and Jina, already distinguish the queries from the documents and
+// need no instructions.
+type InstructedEmbedder struct {
+	Embedder
+	queryPrefix    string
+	documentPrefix string
+}
+
+var _ Embedder = InstructedEmbedder{}
+
+// NewInstructedEmbedder wraps the embedder to prefix the queries with
+// queryPrefix and the documents with documentPrefix, e.g. E5QueryPrefix and
+// E5DocumentPrefix. Empty prefixes leave the texts as is. The documents
+// longer than the chunks of the embedder are prefixed only in their first
+// chunk.
+func NewInstructedEmbedder(embedder Embedder, queryPrefix, documentPrefix string) InstructedEmbedder {
+	return InstructedEmbedder{
+		Embedder:       embedder,
+		queryPrefix:    queryPrefix,
+		documentPrefix: documentPrefix,
+	}
+}
+
+// EmbedDocuments embeds the texts prefixed with the document prefix.
+func (e InstructedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The texts are copied, the embedders may modify them.
+	prefixed := make([]string, 0, len(texts))
+	for _, text := range texts {
+		prefixed = append(prefixed, e.documentPrefix+text)
+	}
+	return e.Embedder.EmbedDocuments(ctx, prefixed)
+}
+
+// EmbedQuery embeds the text prefixed with the query prefix.
+func (e InstructedEmbedder) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	return e.Embedder.EmbedQuery(ctx, e.queryPrefix+text)
+}
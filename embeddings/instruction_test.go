@@ -0,0 +1,52 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmbedder records the texts it embeds.
+type recordingEmbedder struct {
+	documents []string
+	query     string
+}
+
+func (e *recordingEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	e.documents = append(e.documents, texts...)
+	return lengthEmbedding(ctx, texts)
+}
+
+func (e *recordingEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	e.query = text
+	return []float64{float64(len(text))}, nil
+}
+
+func TestInstructedEmbedder(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recordingEmbedder{}
+	e := NewInstructedEmbedder(recorder, E5QueryPrefix, E5DocumentPrefix)
+
+	texts := []string{"Paris is the capital of France.", ""}
+	vectors, err := e.EmbedDocuments(context.Background(), texts)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{40}, {9}}, vectors)
+	assert.Equal(t, []string{"passage: Paris is the capital of France.", "passage: "}, recorder.documents)
+	// The texts of the caller are not modified.
+	assert.Equal(t, "Paris is the capital of France.", texts[0])
+
+	vector, err := e.EmbedQuery(context.Background(), "capital of France")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{24}, vector)
+	assert.Equal(t, "query: capital of France", recorder.query)
+
+	// The documents of the BGE models have no prefix.
+	recorder = &recordingEmbedder{}
+	e = NewInstructedEmbedder(recorder, BGEQueryPrefix, "")
+	_, err = e.EmbedDocuments(context.Background(), []string{"text"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"text"}, recorder.documents)
+}
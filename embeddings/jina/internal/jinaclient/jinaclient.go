@@ -0,0 +1,141 @@
+package jinaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const defaultBaseURL = "https://api.jina.ai/v1"
+
+// ErrUnexpectedResponseLength is returned when the API returns a number of
+// embeddings different from the number of texts.
+var ErrUnexpectedResponseLength = errors.New("unexpected length of response")
+
+// Task is the use of the embeddings, the jina-embeddings-v3 model having an
+// adapter per task.
+type Task string
+
+const (
+	TaskRetrievalPassage Task = "retrieval.passage"
+	TaskRetrievalQuery   Task = "retrieval.query"
+	TaskTextMatching     Task = "text-matching"
+	TaskClassification   Task = "classification"
+	TaskSeparation       Task = "separation"
+)
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client of the Jina API.
+type Client struct {
+	token      string
+	baseURL    string
+	httpClient Doer
+}
+
+// Option is an option of the client.
+type Option func(*Client)
+
+// WithBaseURL sets the base url of the API, https://api.jina.ai/v1 by
+// default.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http client used for the requests.
+func WithHTTPClient(httpClient Doer) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New returns a new client of the Jina API.
+func New(token string, opts ...Option) *Client {
+	c := &Client{
+		token:      token,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// EmbeddingRequest is a request to embed texts.
+type EmbeddingRequest struct {
+	Input []string `json:"input"`
+	Model string   `json:"model"`
+	Task  Task     `json:"task,omitempty"`
+	// Dimensions is the dimension of the embeddings, the model default if
+	// zero.
+	Dimensions int `json:"dimensions,omitempty"`
+	// LateChunking embeds the texts as the chunks of a single document: the
+	// texts are encoded together and the embedding of each text is pooled
+	// from its tokens, so that it has the context of the other texts.
+	LateChunking bool `json:"late_chunking,omitempty"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+// CreateEmbedding embeds the texts of the request.
+func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]float64, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	llms.SetRequestHeaders(req)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var errResp struct {
+			Detail string `json:"detail"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&errResp)
+		return nil, llms.NewStatusError(res, "", errResp.Detail)
+	}
+
+	var response embeddingResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if len(response.Data) != len(r.Input) {
+		return nil, fmt.Errorf("%w: %d embeddings for %d texts", ErrUnexpectedResponseLength,
+			len(response.Data), len(r.Input))
+	}
+	embeddings := make([][]float64, len(response.Data))
+	for _, data := range response.Data {
+		if data.Index < 0 || data.Index >= len(embeddings) {
+			return nil, fmt.Errorf("%w: index %d", ErrUnexpectedResponseLength, data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+	return embeddings, nil
+}
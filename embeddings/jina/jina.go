@@ -0,0 +1,102 @@
+package jina
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/jina/internal/jinaclient"
+)
+
+// ErrMissingToken is returned when the API key is not set.
+var ErrMissingToken = errors.New("missing the Jina API key, set it in the JINA_API_KEY environment variable")
+
+// Jina is the embedder using the Jina embeddings API.
+type Jina struct {
+	client *jinaclient.Client
+	// Model is the embedding model, e.g. jina-embeddings-v3 or
+	// jina-embeddings-v2-base-code for code.
+	Model string
+	// DocumentTask and QueryTask are the tasks of the documents and of the
+	// queries, e.g. retrieval.passage and retrieval.query. Not sent if empty,
+	// as for the models without task adapters.
+	DocumentTask string
+	QueryTask    string
+	// Dimensions is the dimension of the embeddings, the model default if
+	// zero.
+	Dimensions int
+	// LateChunking embeds the texts of EmbedDocuments as the consecutive
+	// chunks of a single document, with a single request.
+	LateChunking bool
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+
+	token      string
+	clientOpts []jinaclient.Option
+}
+
+var _ embeddings.Embedder = Jina{}
+
+// NewJina creates a new Jina with options. The API key is read from the
+// JINA_API_KEY environment variable if not set.
+func NewJina(opts ...Option) (Jina, error) {
+	j, err := applyClientOptions(opts...)
+	if err != nil {
+		return Jina{}, err
+	}
+
+	return j, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts. With
+// late chunking, the texts are the chunks of a single document and are
+// embedded together, neither split nor batched.
+func (e Jina) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	texts = embeddings.MaybeRemoveNewLines(texts, e.StripNewLines)
+	if e.LateChunking {
+		if len(texts) == 0 {
+			return [][]float64{}, nil
+		}
+		return e.createEmbedding(ctx, texts, e.DocumentTask)
+	}
+
+	// The API embeds at most 2048 texts per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(128)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
+		texts,
+		e.BatchSize,
+		func(ctx context.Context, texts []string) ([][]float64, error) {
+			return e.createEmbedding(ctx, texts, e.DocumentTask)
+		},
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e Jina) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.createEmbedding(ctx, []string{text}, e.QueryTask)
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+func (e Jina) createEmbedding(ctx context.Context, texts []string, task string) ([][]float64, error) {
+	return e.client.CreateEmbedding(ctx, &jinaclient.EmbeddingRequest{
+		Input:        texts,
+		Model:        e.Model,
+		Task:         jinaclient.Task(task),
+		Dimensions:   e.Dimensions,
+		LateChunking: e.LateChunking,
+	})
+}
@@ -0,0 +1,114 @@
+package jina
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// newServer returns a server embedding each text as its index, recording
+// the requests.
+func newServer(t *testing.T) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		input, _ := req["input"].([]any)
+		data := make([]map[string]any, 0, len(input))
+		for i := range input {
+			data = append(data, map[string]any{"embedding": []float64{float64(i), 1}, "index": i})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestJinaEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newServer(t)
+	e, err := NewJina(WithToken("key"), WithBaseURL(server.URL), WithDimensions(256))
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello\nworld!")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1}, embedding)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 3)
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, map[string]any{
+		"input":      []any{"Hello world!"},
+		"model":      "jina-embeddings-v3",
+		"task":       "retrieval.query",
+		"dimensions": 256.0,
+	}, (*requests)[0])
+	assert.Equal(t, "retrieval.passage", (*requests)[1]["task"])
+}
+
+func TestJinaLateChunking(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newServer(t)
+	e, err := NewJina(WithToken("key"), WithBaseURL(server.URL), WithLateChunking(true),
+		WithModel("jina-embeddings-v2-base-code"), WithTasks("", ""), WithBatchSize(4))
+	require.NoError(t, err)
+
+	// The chunks are neither split nor normalized.
+	chunks := []string{"Berlin is the capital of Germany.", "Its population is 3.85 million."}
+	vectors, err := e.EmbedDocuments(context.Background(), chunks)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0, 1}, {1, 1}}, vectors)
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, map[string]any{
+		"input":         []any{chunks[0], chunks[1]},
+		"model":         "jina-embeddings-v2-base-code",
+		"late_chunking": true,
+	}, (*requests)[0])
+}
+
+func TestJinaEmbeddingsError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		fmt.Fprint(w, `{"detail":"Insufficient balance"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewJina(WithToken("key"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "Hello world!")
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusPaymentRequired, statusErr.StatusCode)
+
+	if os.Getenv(tokenEnvVarName) == "" {
+		_, err = NewJina()
+		require.ErrorIs(t, err, ErrMissingToken)
+	}
+}
@@ -0,0 +1,124 @@
+package jina
+
+import (
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/jina/internal/jinaclient"
+)
+
+const (
+	tokenEnvVarName = "JINA_API_KEY" //nolint:gosec
+
+	_defaultModel         = "jina-embeddings-v3"
+	_defaultBatchSize     = 512
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Jina)
+
+// WithToken is an option for specifying the API key. If not set, the key is
+// read from the JINA_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(p *Jina) {
+		p.token = token
+	}
+}
+
+// WithModel is an option for specifying the embedding model, e.g.
+// jina-embeddings-v2-base-code for code retrieval. The default is
+// jina-embeddings-v3.
+func WithModel(model string) Option {
+	return func(p *Jina) {
+		p.Model = model
+	}
+}
+
+// WithTasks is an option for specifying the tasks of the documents and of the
+// queries, retrieval.passage and retrieval.query by default. Empty tasks are
+// not sent, as needed for the models without task adapters.
+func WithTasks(documentTask, queryTask string) Option {
+	return func(p *Jina) {
+		p.DocumentTask = documentTask
+		p.QueryTask = queryTask
+	}
+}
+
+// WithDimensions is an option for specifying the dimension of the
+// embeddings, e.g. 256 for jina-embeddings-v3 whose embeddings keep most of
+// their quality when shortened.
+func WithDimensions(dimensions int) Option {
+	return func(p *Jina) {
+		p.Dimensions = dimensions
+	}
+}
+
+// WithLateChunking is an option for embedding the texts of EmbedDocuments as
+// the consecutive chunks of a single document: the model encodes the whole
+// document, at most 8192 tokens, and pools the embedding of each chunk from
+// its tokens, so that the chunks keep the context of the document, e.g. the
+// subject of a pronoun. Call EmbedDocuments once per document.
+func WithLateChunking(lateChunking bool) Option {
+	return func(p *Jina) {
+		p.LateChunking = lateChunking
+	}
+}
+
+// WithBaseURL is an option for specifying the base url of the API.
+func WithBaseURL(baseURL string) Option {
+	return func(p *Jina) {
+		p.clientOpts = append(p.clientOpts, jinaclient.WithBaseURL(baseURL))
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client jinaclient.Doer) Option {
+	return func(p *Jina) {
+		p.clientOpts = append(p.clientOpts, jinaclient.WithHTTPClient(client))
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *Jina) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size.
+func WithBatchSize(batchSize int) Option {
+	return func(p *Jina) {
+		p.BatchSize = batchSize
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *Jina) {
+		p.batchOptions = opts
+	}
+}
+
+func applyClientOptions(opts ...Option) (Jina, error) {
+	j := &Jina{
+		Model:         _defaultModel,
+		DocumentTask:  string(jinaclient.TaskRetrievalPassage),
+		QueryTask:     string(jinaclient.TaskRetrievalQuery),
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		token:         os.Getenv(tokenEnvVarName),
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	if j.token == "" {
+		return Jina{}, ErrMissingToken
+	}
+	j.client = jinaclient.New(j.token, j.clientOpts...)
+
+	return *j, nil
+}
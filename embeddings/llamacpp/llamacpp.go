@@ -0,0 +1,57 @@
+package llamacpp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/llamacpp"
+)
+
+// LlamaCpp is the embedder using the embeddings endpoint of a llama.cpp
+// server, which must run with embeddings enabled, see llamacpp.WithEmbeddings.
+type LlamaCpp struct {
+	client *llamacpp.LLM
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+}
+
+var _ embeddings.Embedder = LlamaCpp{}
+
+// NewLlamaCpp creates a new LlamaCpp with options. Options for client, strip new lines and batch.
+func NewLlamaCpp(opts ...Option) (LlamaCpp, error) {
+	o, err := applyClientOptions(opts...)
+	if err != nil {
+		return LlamaCpp{}, err
+	}
+
+	return o, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e LlamaCpp) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	return embeddings.EmbedChunks(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+		e.client.CreateEmbedding,
+		e.batchOptions...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e LlamaCpp) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.client.CreateEmbedding(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
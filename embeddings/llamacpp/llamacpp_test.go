@@ -0,0 +1,47 @@
+package llamacpp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/llamacpp"
+)
+
+func TestLlamaCppEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var inputs [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		var req struct {
+			Input []string `json:"input"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		inputs = append(inputs, req.Input)
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float64{float64(i), 1}}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := llamacpp.New(llamacpp.WithServerURL(server.URL))
+	require.NoError(t, err)
+	e, err := NewLlamaCpp(WithClient(*client), WithStripNewLines(false))
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello\nworld!")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1}, embedding)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 3)
+	assert.Equal(t, [][]string{{"Hello\nworld!"}, {"Hello world", "The world is ending", "good bye"}}, inputs)
+}
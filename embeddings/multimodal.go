@@ -0,0 +1,63 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// MultimodalEmbedder is the interface for creating vector embeddings from
+// texts and images in the same space, as CLIP models do, so that texts can
+// be searched by images and images by texts. The images are encoded, e.g. in
+// JPEG or PNG.
+type MultimodalEmbedder interface {
+	Embedder
+	// EmbedImage embeds a single image.
+	EmbedImage(ctx context.Context, image []byte) ([]float64, error)
+	// EmbedTextAndImage embeds a text and an image, e.g. a caption and its
+	// picture, returning the vector of the text and the vector of the image.
+	EmbedTextAndImage(ctx context.Context, text string, image []byte) ([]float64, []float64, error)
+}
+
+// ImageLoader returns the encoded image of a reference, e.g. a file path or
+// a URL.
+type ImageLoader func(ctx context.Context, ref string) ([]byte, error)
+
+// ImageEmbedder is an embedder embedding the documents as images, the text
+// of a document being the reference of its image, and the queries as texts.
+// Used as the embedder of a vector store, it indexes images, searched by
+// their descriptions.
+type ImageEmbedder struct {
+	MultimodalEmbedder
+	load ImageLoader
+}
+
+var _ Embedder = ImageEmbedder{}
+
+// NewImageEmbedder wraps the multimodal embedder to embed the images loaded
+// by the loader, the images of the file paths if the loader is nil.
+func NewImageEmbedder(embedder MultimodalEmbedder, load ImageLoader) ImageEmbedder {
+	if load == nil {
+		load = func(_ context.Context, path string) ([]byte, error) {
+			return os.ReadFile(path)
+		}
+	}
+	return ImageEmbedder{MultimodalEmbedder: embedder, load: load}
+}
+
+// EmbedDocuments embeds the images referenced by the texts.
+func (e ImageEmbedder) EmbedDocuments(ctx context.Context, refs []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(refs))
+	for _, ref := range refs {
+		image, err := e.load(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("load image %s: %w", ref, err)
+		}
+		vector, err := e.EmbedImage(ctx, image)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,53 @@
+package embeddings
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMultimodalEmbedder embeds the texts and the images as vectors holding
+// their lengths, negative for the images.
+type fakeMultimodalEmbedder struct{}
+
+func (fakeMultimodalEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	return lengthEmbedding(ctx, texts)
+}
+
+func (fakeMultimodalEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	return []float64{float64(len(text))}, nil
+}
+
+func (fakeMultimodalEmbedder) EmbedImage(_ context.Context, image []byte) ([]float64, error) {
+	return []float64{-float64(len(image))}, nil
+}
+
+func (e fakeMultimodalEmbedder) EmbedTextAndImage(ctx context.Context, text string, image []byte) ([]float64, []float64, error) { //nolint:lll
+	textVector, _ := e.EmbedQuery(ctx, text)
+	imageVector, _ := e.EmbedImage(ctx, image)
+	return textVector, imageVector, nil
+}
+
+func TestImageEmbedder(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "image.png")
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+
+	e := NewImageEmbedder(fakeMultimodalEmbedder{}, nil)
+	vectors, err := e.EmbedDocuments(context.Background(), []string{path})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{-5}}, vectors)
+
+	// The queries are embedded as texts.
+	vector, err := e.EmbedQuery(context.Background(), "a cat")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{5}, vector)
+
+	_, err = e.EmbedDocuments(context.Background(), []string{filepath.Join(t.TempDir(), "missing.png")})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,66 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/ollama"
+)
+
+func TestOllamaEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var inputs [][]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req["model"])
+		input, _ := req["input"].([]any)
+		inputs = append(inputs, input)
+		embeddings := make([][]float64, len(input))
+		for i := range input {
+			embeddings[i] = []float64{float64(i), 1}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings}))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ollama.New(ollama.WithServerURL(server.URL), ollama.WithModel("nomic-embed-text"))
+	require.NoError(t, err)
+	e, err := NewOllama(WithClient(*client))
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello\nworld!")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1}, embedding)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 2)
+	assert.Equal(t, [][]any{{"Hello world!"}, {"Hello world", "good bye"}}, inputs)
+}
+
+func TestOllamaEmbeddingsError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":"model \"nomic-embed-text\" not found, try pulling it first"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ollama.New(ollama.WithServerURL(server.URL), ollama.WithModel("nomic-embed-text"))
+	require.NoError(t, err)
+	e, err := NewOllama(WithClient(*client))
+	require.NoError(t, err)
+
+	_, err = e.EmbedDocuments(context.Background(), []string{"Hello world"})
+	require.ErrorContains(t, err, "not found")
+}
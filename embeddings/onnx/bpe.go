@@ -0,0 +1,171 @@
+package onnx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	clipStartToken = "<|startoftext|>"
+	clipEndToken   = "<|endoftext|>"
+)
+
+// clipPattern splits the texts into the words of the CLIP tokenizer.
+var clipPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`<\|startoftext\|>|<\|endoftext\|>|'s|'t|'re|'ve|'m|'ll|'d|\p{L}+|\p{N}|[^\s\p{L}\p{N}]+`,
+)
+
+// bpeTokenizer is the byte-level BPE tokenizer of the CLIP models. The texts
+// are lower cased and the last symbol of each word ends with </w>.
+type bpeTokenizer struct {
+	vocab       map[string]int64
+	ranks       map[[2]string]int
+	byteEncoder [256]string
+	start       int64
+	end         int64
+}
+
+// loadBPETokenizer loads the tokenizer of the vocab.json and merges.txt
+// files.
+func loadBPETokenizer(vocabPath, mergesPath string) (*bpeTokenizer, error) {
+	data, err := os.ReadFile(vocabPath)
+	if err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+	var vocab map[string]int64
+	if err := json.Unmarshal(data, &vocab); err != nil {
+		return nil, fmt.Errorf("parse vocabulary: %w", err)
+	}
+
+	f, err := os.Open(mergesPath)
+	if err != nil {
+		return nil, fmt.Errorf("read merges: %w", err)
+	}
+	defer f.Close()
+	var merges [][2]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#version") {
+			continue
+		}
+		pair := strings.Fields(line)
+		if len(pair) != 2 { //nolint:gomnd
+			return nil, fmt.Errorf("%w: merge %q", ErrUnexpectedModel, line)
+		}
+		merges = append(merges, [2]string{pair[0], pair[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read merges: %w", err)
+	}
+
+	return newBPETokenizer(vocab, merges)
+}
+
+func newBPETokenizer(vocab map[string]int64, merges [][2]string) (*bpeTokenizer, error) {
+	t := &bpeTokenizer{
+		vocab: vocab,
+		ranks: make(map[[2]string]int, len(merges)),
+	}
+	for i, merge := range merges {
+		t.ranks[merge] = i
+	}
+	var ok bool
+	if t.start, ok = vocab[clipStartToken]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingToken, clipStartToken)
+	}
+	if t.end, ok = vocab[clipEndToken]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingToken, clipEndToken)
+	}
+
+	// The bytes are mapped to printable characters, the printable ones to
+	// themselves and the others to the characters following U+00FF.
+	n := 0
+	for b := 0; b < 256; b++ {
+		if ('!' <= b && b <= '~') || ('¡' <= b && b <= '¬') || ('®' <= b && b <= 'ÿ') {
+			t.byteEncoder[b] = string(rune(b))
+			continue
+		}
+		t.byteEncoder[b] = string(rune(256 + n))
+		n++
+	}
+
+	return t, nil
+}
+
+// encodeAll encodes the texts.
+func (t *bpeTokenizer) encodeAll(texts []string, maxLength int) [][]int64 {
+	ids := make([][]int64, len(texts))
+	for i, text := range texts {
+		ids[i] = t.encode(text, maxLength)
+	}
+	return ids
+}
+
+// encode returns the ids of the tokens of the text between the start and end
+// tokens, truncated to maxLength ids.
+func (t *bpeTokenizer) encode(text string, maxLength int) []int64 {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+
+	ids := []int64{t.start}
+	for _, word := range clipPattern.FindAllString(text, -1) {
+		var encoded strings.Builder
+		for _, b := range []byte(word) {
+			encoded.WriteString(t.byteEncoder[b])
+		}
+		for _, token := range t.bpe(encoded.String()) {
+			if id, ok := t.vocab[token]; ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	if len(ids) > maxLength-1 {
+		ids = ids[:maxLength-1]
+	}
+	return append(ids, t.end)
+}
+
+// bpe splits the word into its symbols and merges the adjacent symbols in
+// the order of the merges until no merge applies.
+func (t *bpeTokenizer) bpe(word string) []string {
+	runes := []rune(word)
+	symbols := make([]string, 0, len(runes))
+	for _, r := range runes {
+		symbols = append(symbols, string(r))
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+	symbols[len(symbols)-1] += "</w>"
+
+	for len(symbols) > 1 {
+		best, bestRank := -1, 0
+		for i := 0; i < len(symbols)-1; i++ {
+			rank, ok := t.ranks[[2]string{symbols[i], symbols[i+1]}]
+			if ok && (best == -1 || rank < bestRank) {
+				best, bestRank = i, rank
+			}
+		}
+		if best == -1 {
+			break
+		}
+
+		first, second := symbols[best], symbols[best+1]
+		merged := make([]string, 0, len(symbols))
+		for i := 0; i < len(symbols); i++ {
+			if i < len(symbols)-1 && symbols[i] == first && symbols[i+1] == second {
+				merged = append(merged, first+second)
+				i++
+				continue
+			}
+			merged = append(merged, symbols[i])
+		}
+		symbols = merged
+	}
+	return symbols
+}
@@ -0,0 +1,49 @@
+package onnx
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBPE(t *testing.T, dir string) {
+	t.Helper()
+
+	vocab, err := json.Marshal(map[string]int64{
+		"<|startoftext|>": 0, "<|endoftext|>": 1,
+		"h": 2, "e": 3, "l": 4, "o</w>": 5, "he": 6, "ll": 7, "hell": 8, "hello</w>": 9, "!</w>": 10,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vocab.json"), vocab, 0o600))
+	merges := "#version: 0.2\nh e\nl l\nhe ll\nhell o</w>\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "merges.txt"), []byte(merges), 0o600))
+}
+
+func TestBPETokenizer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeBPE(t, dir)
+	tok, err := loadBPETokenizer(filepath.Join(dir, "vocab.json"), filepath.Join(dir, "merges.txt"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"hello</w>"}, tok.bpe("hello"))
+	assert.Equal(t, []string{"he", "l", "o</w>"}, tok.bpe("helo"))
+	assert.Equal(t, []int64{0, 9, 10, 1}, tok.encode("Hello\n  !", clipContextLength))
+	assert.Equal(t, []int64{0, 9, 1}, tok.encode("hello hello", 3))
+	// The space is mapped to the first character after the control bytes.
+	assert.Equal(t, "Ġ", tok.byteEncoder[' '])
+	assert.Equal(t, "a", tok.byteEncoder['a'])
+}
+
+func TestBPETokenizerMissingToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := newBPETokenizer(map[string]int64{"<|startoftext|>": 0}, nil)
+	require.ErrorIs(t, err, ErrMissingToken)
+}
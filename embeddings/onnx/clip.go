@@ -0,0 +1,245 @@
+package onnx
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// clipContextLength is the maximum number of tokens of the texts of the CLIP
+// text models.
+const clipContextLength = 77
+
+// CLIP is the multimodal embedder running a CLIP model exported to ONNX, e.g.
+// openai/clip-vit-base-patch32 or an open_clip model, with ONNX Runtime. The
+// texts and the images are embedded in the same space by the text model and
+// the image model, so that the images can be searched by their descriptions.
+// It requires cgo.
+type CLIP struct {
+	textSession     *ort.DynamicAdvancedSession
+	imageSession    *ort.DynamicAdvancedSession
+	tokenizer       *bpeTokenizer
+	textInputNames  []string
+	imageInputNames []string
+
+	// MaxLength is the maximum number of tokens of a text, at most 77, longer
+	// texts are truncated.
+	MaxLength int
+	// ImageSize is the size in pixels of the square images of the model.
+	ImageSize int
+	// Normalize rescales the embeddings to unit length.
+	Normalize bool
+
+	StripNewLines bool
+
+	batchOptions []embeddings.BatchOption
+}
+
+var _ embeddings.MultimodalEmbedder = (*CLIP)(nil)
+
+// NewCLIP loads the text model, set with WithModelPath, and the image model,
+// set with WithImageModelPath, with the options of NewONNX. The texts are
+// tokenized with the vocab.json and merges.txt files of the model. The
+// embedder must be closed to release the models.
+func NewCLIP(opts ...Option) (*CLIP, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if o.imageModelPath == "" {
+		return nil, ErrMissingImageModel
+	}
+	if o.vocabPath == "" {
+		o.vocabPath = findFile(filepath.Dir(o.modelPath), "vocab.json")
+	}
+	if o.mergesPath == "" {
+		o.mergesPath = findFile(filepath.Dir(o.modelPath), "merges.txt")
+	}
+	t, err := loadBPETokenizer(o.vocabPath, o.mergesPath)
+	if err != nil {
+		return nil, err
+	}
+	if o.MaxLength <= 0 || o.MaxLength > clipContextLength {
+		o.MaxLength = clipContextLength
+	}
+
+	if err := initRuntime(o.libraryPath); err != nil {
+		return nil, err
+	}
+	e := &CLIP{
+		tokenizer:     t,
+		MaxLength:     o.MaxLength,
+		ImageSize:     o.imageSize,
+		Normalize:     o.Normalize,
+		StripNewLines: o.StripNewLines,
+		batchOptions:  o.batchOptions,
+	}
+	e.textSession, e.textInputNames, err = newCLIPSession(o.modelPath, "input_ids", "text_embeds")
+	if err != nil {
+		return nil, err
+	}
+	e.imageSession, e.imageInputNames, err = newCLIPSession(o.imageModelPath, "pixel_values", "image_embeds")
+	if err != nil {
+		e.textSession.Destroy()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Close releases the models.
+func (e *CLIP) Close() error {
+	textErr := e.textSession.Destroy()
+	if err := e.imageSession.Destroy(); err != nil {
+		return err
+	}
+	return textErr
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e *CLIP) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(32)}, e.batchOptions...)
+	return embeddings.EmbedInBatches(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.embedTexts,
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e *CLIP) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.embedTexts(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+// EmbedImage embeds a single image, encoded in JPEG, PNG or GIF.
+func (e *CLIP) EmbedImage(_ context.Context, image []byte) ([]float64, error) {
+	pixels, err := preprocessImage(image, e.ImageSize)
+	if err != nil {
+		return nil, err
+	}
+	tensor, err := ort.NewTensor(ort.NewShape(1, 3, int64(e.ImageSize), int64(e.ImageSize)), pixels)
+	if err != nil {
+		return nil, fmt.Errorf("create pixel_values tensor: %w", err)
+	}
+	defer tensor.Destroy()
+
+	output, err := runInputs(e.imageSession, []ort.Value{tensor})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Destroy()
+
+	emb, err := e.embeddings(output, 1)
+	if err != nil {
+		return nil, err
+	}
+	return emb[0], nil
+}
+
+// EmbedTextAndImage embeds a text and an image.
+func (e *CLIP) EmbedTextAndImage(ctx context.Context, text string, image []byte) ([]float64, []float64, error) {
+	textEmbedding, err := e.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, nil, err
+	}
+	imageEmbedding, err := e.EmbedImage(ctx, image)
+	if err != nil {
+		return nil, nil, err
+	}
+	return textEmbedding, imageEmbedding, nil
+}
+
+// embedTexts runs the text model on the texts.
+func (e *CLIP) embedTexts(_ context.Context, texts []string) ([][]float64, error) {
+	output, _, err := run(e.textSession, e.textInputNames, e.tokenizer.encodeAll(texts, e.MaxLength))
+	if err != nil {
+		return nil, err
+	}
+	defer output.Destroy()
+
+	return e.embeddings(output, len(texts))
+}
+
+// embeddings returns the embeddings of the output of a model, of shape
+// inputs x dimensions.
+func (e *CLIP) embeddings(output *ort.Tensor[float32], inputs int) ([][]float64, error) {
+	shape := output.GetShape()
+	if len(shape) != 2 || int(shape[0]) != inputs {
+		return nil, fmt.Errorf("%w: output shape %v", ErrUnexpectedModel, shape)
+	}
+	dimensions := int(shape[1])
+	values := output.GetData()
+	embeddings := make([][]float64, inputs)
+	for i := range embeddings {
+		embeddings[i] = make([]float64, dimensions)
+		for k := range embeddings[i] {
+			embeddings[i][k] = float64(values[i*dimensions+k])
+		}
+		if e.Normalize {
+			normalize(embeddings[i])
+		}
+	}
+	return embeddings, nil
+}
+
+// newCLIPSession creates the session of a CLIP model, returning the names of
+// its inputs.
+func newCLIPSession(path, input, output string) (*ort.DynamicAdvancedSession, []string, error) {
+	inputs, outputs, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read model: %w", err)
+	}
+	inputNames, outputName, err := clipInputsOutput(inputs, outputs, input, output)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := ort.NewDynamicAdvancedSession(path, inputNames, []string{outputName}, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create session: %w", err)
+	}
+	return session, inputNames, nil
+}
+
+// clipInputsOutput returns the names of the inputs of a CLIP model, the
+// input and the attention_mask if any, and the name of its output, the
+// output or else the first output of rank 2.
+func clipInputsOutput(inputs, outputs []ort.InputOutputInfo, input, output string) ([]string, string, error) {
+	var inputNames []string
+	for _, info := range inputs {
+		if info.Name == input {
+			inputNames = append([]string{input}, inputNames...)
+		}
+		if info.Name == "attention_mask" {
+			inputNames = append(inputNames, info.Name)
+		}
+	}
+	if len(inputNames) == 0 || inputNames[0] != input {
+		return nil, "", fmt.Errorf("%w: %s input required", ErrUnexpectedModel, input)
+	}
+
+	for _, info := range outputs {
+		if info.Name == output {
+			return inputNames, info.Name, nil
+		}
+	}
+	for _, info := range outputs {
+		if len(info.Dimensions) == 2 { //nolint:gomnd
+			return inputNames, info.Name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: %s output required", ErrUnexpectedModel, output)
+}
@@ -0,0 +1,69 @@
+package onnx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+func TestCLIPInputsOutput(t *testing.T) {
+	t.Parallel()
+
+	inputs, output, err := clipInputsOutput(
+		[]ort.InputOutputInfo{{Name: "attention_mask"}, {Name: "input_ids"}},
+		[]ort.InputOutputInfo{{Name: "last_hidden_state"}, {Name: "text_embeds"}},
+		"input_ids", "text_embeds")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"input_ids", "attention_mask"}, inputs)
+	assert.Equal(t, "text_embeds", output)
+
+	inputs, output, err = clipInputsOutput(
+		[]ort.InputOutputInfo{{Name: "pixel_values"}},
+		[]ort.InputOutputInfo{{Name: "output", Dimensions: ort.NewShape(-1, 512)}},
+		"pixel_values", "image_embeds")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pixel_values"}, inputs)
+	assert.Equal(t, "output", output)
+
+	_, _, err = clipInputsOutput([]ort.InputOutputInfo{{Name: "image"}}, nil, "pixel_values", "image_embeds")
+	require.ErrorIs(t, err, ErrUnexpectedModel)
+}
+
+func TestNewCLIPMissingImageModel(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCLIP(WithModelPath(filepath.Join(t.TempDir(), "text_model.onnx")))
+	require.ErrorIs(t, err, ErrMissingImageModel)
+}
+
+func TestCLIPEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	dir := os.Getenv("ONNX_CLIP_MODEL_DIR")
+	imagePath := os.Getenv("ONNX_CLIP_IMAGE_PATH")
+	if dir == "" || imagePath == "" || os.Getenv(sharedLibraryPathEnvVarName) == "" {
+		t.Skip("ONNX_CLIP_MODEL_DIR, ONNX_CLIP_IMAGE_PATH or ONNXRUNTIME_SHARED_LIBRARY_PATH not set")
+	}
+	image, err := os.ReadFile(imagePath)
+	require.NoError(t, err)
+
+	e, err := NewCLIP(
+		WithModelPath(filepath.Join(dir, "onnx", "text_model.onnx")),
+		WithImageModelPath(filepath.Join(dir, "onnx", "vision_model.onnx")),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, e.Close()) })
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"a photo of a cat", "a photo of a dog"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 2)
+
+	text, img, err := e.EmbedTextAndImage(context.Background(), "a photo of a cat", image)
+	require.NoError(t, err)
+	assert.Len(t, img, len(text))
+}
@@ -0,0 +1,144 @@
+package onnx
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+
+	// The decoders of the supported image formats.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// The mean and standard deviation of the RGB channels of the images the CLIP
+// models were trained on.
+var (
+	clipMean = [3]float32{0.48145466, 0.4578275, 0.40821073}  //nolint:gochecknoglobals
+	clipStd  = [3]float32{0.26862954, 0.26130258, 0.27577711} //nolint:gochecknoglobals
+)
+
+// preprocessImage decodes the image, resizes it so that its shortest side is
+// size pixels, crops its center square and returns its normalized RGB
+// channels, of shape 3 x size x size.
+func preprocessImage(data []byte, size int) ([]float32, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrInvalidImage)
+	}
+
+	pixels := make([]float64, 3*width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			i := 3 * (y*width + x)
+			pixels[i], pixels[i+1], pixels[i+2] = float64(r>>8), float64(g>>8), float64(b>>8)
+		}
+	}
+
+	shortest := width
+	if height < shortest {
+		shortest = height
+	}
+	scale := float64(size) / float64(shortest)
+	newWidth := int(math.Round(float64(width) * scale))
+	newHeight := int(math.Round(float64(height) * scale))
+	if newWidth < size {
+		newWidth = size
+	}
+	if newHeight < size {
+		newHeight = size
+	}
+	pixels = resize(pixels, width, height, newWidth, newHeight)
+
+	left := int(math.Round(float64(newWidth-size) / 2)) //nolint:gomnd
+	top := int(math.Round(float64(newHeight-size) / 2)) //nolint:gomnd
+	channels := make([]float32, 3*size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			i := 3 * ((top+y)*newWidth + left + x)
+			for c := 0; c < 3; c++ {
+				value := float32(pixels[i+c] / 255) //nolint:gomnd
+				channels[c*size*size+y*size+x] = (value - clipMean[c]) / clipStd[c]
+			}
+		}
+	}
+	return channels, nil
+}
+
+// resize resizes the RGB pixels with an antialiased bilinear filter, first
+// horizontally then vertically.
+func resize(pixels []float64, width, height, newWidth, newHeight int) []float64 {
+	horizontal := make([]float64, 3*newWidth*height)
+	weights := filterWeights(width, newWidth)
+	for y := 0; y < height; y++ {
+		for x, w := range weights {
+			for k, weight := range w.weights {
+				src := 3 * (y*width + w.start + k)
+				dst := 3 * (y*newWidth + x)
+				for c := 0; c < 3; c++ {
+					horizontal[dst+c] += weight * pixels[src+c]
+				}
+			}
+		}
+	}
+
+	resized := make([]float64, 3*newWidth*newHeight)
+	weights = filterWeights(height, newHeight)
+	for y, w := range weights {
+		for k, weight := range w.weights {
+			for x := 0; x < newWidth; x++ {
+				src := 3 * ((w.start+k)*newWidth + x)
+				dst := 3 * (y*newWidth + x)
+				for c := 0; c < 3; c++ {
+					resized[dst+c] += weight * horizontal[src+c]
+				}
+			}
+		}
+	}
+	return resized
+}
+
+type filterWeight struct {
+	start   int
+	weights []float64
+}
+
+// filterWeights returns the weights of the input pixels of each output pixel
+// of a triangle filter, widened when downscaling to average all the input
+// pixels.
+func filterWeights(in, out int) []filterWeight {
+	scale := float64(in) / float64(out)
+	filterScale := math.Max(scale, 1)
+	support := filterScale
+
+	weights := make([]filterWeight, out)
+	for x := range weights {
+		center := (float64(x) + 0.5) * scale //nolint:gomnd
+		start := int(math.Max(center-support+0.5, 0))
+		end := int(math.Min(center+support+0.5, float64(in)))
+		w := make([]float64, 0, end-start)
+		var total float64
+		for i := start; i < end; i++ {
+			weight := 1 - math.Abs((float64(i)-center+0.5)/filterScale)
+			if weight < 0 {
+				weight = 0
+			}
+			w = append(w, weight)
+			total += weight
+		}
+		if total > 0 {
+			for i := range w {
+				w[i] /= total
+			}
+		}
+		weights[x] = filterWeight{start: start, weights: w}
+	}
+	return weights
+}
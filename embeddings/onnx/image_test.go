@@ -0,0 +1,72 @@
+package onnx
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int, colorAt func(x, y int) color.Color) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, colorAt(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestPreprocessImage(t *testing.T) {
+	t.Parallel()
+
+	// The colors of a uniform image are kept by the resizing.
+	red := encodePNG(t, 6, 3, func(_, _ int) color.Color { return color.RGBA{R: 255, A: 255} })
+	pixels, err := preprocessImage(red, 2)
+	require.NoError(t, err)
+	require.Len(t, pixels, 12)
+	for i := 0; i < 4; i++ {
+		assert.InDelta(t, (1-clipMean[0])/clipStd[0], pixels[i], 1e-5)
+		assert.InDelta(t, (0-clipMean[1])/clipStd[1], pixels[4+i], 1e-5)
+		assert.InDelta(t, (0-clipMean[2])/clipStd[2], pixels[8+i], 1e-5)
+	}
+
+	// The center square of an image red on the left and blue on the right
+	// is red on the left and blue on the right.
+	split := encodePNG(t, 8, 4, func(x, _ int) color.Color {
+		if x < 4 {
+			return color.RGBA{R: 255, A: 255}
+		}
+		return color.RGBA{B: 255, A: 255}
+	})
+	pixels, err = preprocessImage(split, 2)
+	require.NoError(t, err)
+	assert.Greater(t, pixels[0], pixels[1])
+	assert.Less(t, pixels[8], pixels[9])
+
+	_, err = preprocessImage([]byte("not an image"), 2)
+	require.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestFilterWeights(t *testing.T) {
+	t.Parallel()
+
+	for _, sizes := range [][2]int{{10, 3}, {3, 10}, {7, 7}} {
+		for _, w := range filterWeights(sizes[0], sizes[1]) {
+			var total float64
+			for _, weight := range w.weights {
+				total += weight
+			}
+			assert.InDelta(t, 1, total, 1e-9)
+			assert.LessOrEqual(t, w.start+len(w.weights), sizes[0])
+		}
+	}
+}
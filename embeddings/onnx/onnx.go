@@ -0,0 +1,300 @@
+package onnx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+var (
+	ErrMissingModel = errors.New("missing the path of the ONNX model, set it with WithModelPath")
+	// ErrMissingImageModel is returned when the path of the image model of
+	// CLIP is not set.
+	ErrMissingImageModel = errors.New("missing the path of the ONNX image model, set it with WithImageModelPath")
+	// ErrMissingToken is returned when a special token of the tokenizer,
+	// e.g. [CLS], is not in the vocabulary.
+	ErrMissingToken = errors.New("missing token in the vocabulary")
+	// ErrUnexpectedModel is returned when the inputs or the outputs of the
+	// model are not the ones of a sentence-transformers model.
+	ErrUnexpectedModel = errors.New("unexpected inputs or outputs of the model")
+	// ErrInvalidImage is returned when an image cannot be decoded.
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// runtimeMu guards the initialization of the ONNX Runtime environment,
+// shared by all the sessions of the process.
+var runtimeMu sync.Mutex //nolint:gochecknoglobals
+
+// ONNX is the embedder running a sentence-transformers model exported to
+// ONNX, e.g. all-MiniLM-L6-v2, with ONNX Runtime. The texts are tokenized
+// with the WordPiece vocabulary of the model and the token embeddings are
+// mean pooled, so no external API is called. It requires cgo.
+type ONNX struct {
+	session    *ort.DynamicAdvancedSession
+	tokenizer  *tokenizer
+	inputNames []string
+	// pooled is set when the model outputs the sentence embeddings rather
+	// than the token embeddings.
+	pooled bool
+
+	// MaxLength is the maximum number of tokens of a text, longer texts are
+	// truncated.
+	MaxLength int
+	// Normalize rescales the embeddings to unit length.
+	Normalize bool
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+
+	modelPath   string
+	vocabPath   string
+	libraryPath string
+	lowerCase   bool
+
+	imageModelPath string
+	mergesPath     string
+	imageSize      int
+}
+
+var _ embeddings.Embedder = (*ONNX)(nil)
+
+// NewONNX loads the model with options. The ONNX Runtime shared library is
+// loaded on first use, from the path set with WithSharedLibraryPath or the
+// ONNXRUNTIME_SHARED_LIBRARY_PATH environment variable. The embedder must be
+// closed to release the model.
+func NewONNX(opts ...Option) (*ONNX, error) {
+	o, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := initRuntime(o.libraryPath); err != nil {
+		return nil, err
+	}
+	inputs, outputs, err := ort.GetInputOutputInfo(o.modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("read model: %w", err)
+	}
+	outputName, err := o.setInputsOutputs(inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	o.session, err = ort.NewDynamicAdvancedSession(o.modelPath, o.inputNames, []string{outputName}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	return o, nil
+}
+
+// Close releases the model.
+func (e *ONNX) Close() error {
+	return e.session.Destroy()
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e *ONNX) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The texts of a batch are padded to the longest one.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(32)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+		e.embed,
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e *ONNX) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+func initRuntime(libraryPath string) error {
+	runtimeMu.Lock()
+	defer runtimeMu.Unlock()
+
+	if ort.IsInitialized() {
+		return nil
+	}
+	if libraryPath != "" {
+		ort.SetSharedLibraryPath(libraryPath)
+	}
+	if err := ort.InitializeEnvironment(); err != nil {
+		return fmt.Errorf("initialize onnx runtime: %w", err)
+	}
+	return nil
+}
+
+// setInputsOutputs sets the inputs of the model used by the embedder and
+// returns the name of the output, the sentence embeddings if the model
+// outputs them or else the token embeddings.
+func (e *ONNX) setInputsOutputs(inputs, outputs []ort.InputOutputInfo) (string, error) {
+	var err error
+	e.inputNames, err = inputNames(inputs)
+	if err != nil {
+		return "", err
+	}
+
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("%w: no outputs", ErrUnexpectedModel)
+	}
+	for _, output := range outputs {
+		if output.Name == "sentence_embedding" {
+			e.pooled = true
+			return output.Name, nil
+		}
+	}
+	for _, output := range outputs {
+		if output.Name == "last_hidden_state" || output.Name == "token_embeddings" {
+			return output.Name, nil
+		}
+	}
+	e.pooled = len(outputs[0].Dimensions) == 2
+	return outputs[0].Name, nil
+}
+
+// inputNames returns the names of the inputs of the model fed with the
+// tokens of the texts.
+func inputNames(inputs []ort.InputOutputInfo) ([]string, error) {
+	names := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		names[input.Name] = true
+	}
+	if !names["input_ids"] || !names["attention_mask"] {
+		return nil, fmt.Errorf("%w: input_ids and attention_mask inputs required", ErrUnexpectedModel)
+	}
+	inputNames := []string{"input_ids", "attention_mask"}
+	if names["token_type_ids"] {
+		inputNames = append(inputNames, "token_type_ids")
+	}
+	return inputNames, nil
+}
+
+// embed runs the model on the texts.
+func (e *ONNX) embed(_ context.Context, texts []string) ([][]float64, error) {
+	output, mask, err := run(e.session, e.inputNames, e.tokenizer.encodeAll(texts, e.MaxLength))
+	if err != nil {
+		return nil, err
+	}
+	defer output.Destroy()
+
+	embeddings, err := e.pool(output, len(texts), mask)
+	if err != nil {
+		return nil, err
+	}
+	if e.Normalize {
+		for _, embedding := range embeddings {
+			normalize(embedding)
+		}
+	}
+	return embeddings, nil
+}
+
+// run runs the model of the session on the token ids of the texts, padded
+// to the longest text, and returns its output, to destroy, with the attention
+// mask of the texts.
+func run(
+	session *ort.DynamicAdvancedSession,
+	inputNames []string,
+	ids [][]int64,
+) (*ort.Tensor[float32], [][]int64, error) {
+	length := 0
+	for i := range ids {
+		if len(ids[i]) > length {
+			length = len(ids[i])
+		}
+	}
+
+	// The texts are padded with zeros, masked by the attention mask.
+	inputIDs := make([]int64, len(ids)*length)
+	attentionMask := make([]int64, len(ids)*length)
+	mask := make([][]int64, len(ids))
+	for i := range ids {
+		copy(inputIDs[i*length:], ids[i])
+		mask[i] = attentionMask[i*length : (i+1)*length]
+		for j := range ids[i] {
+			mask[i][j] = 1
+		}
+	}
+	data := map[string][]int64{
+		"input_ids":      inputIDs,
+		"attention_mask": attentionMask,
+		"token_type_ids": make([]int64, len(ids)*length),
+	}
+
+	shape := ort.NewShape(int64(len(ids)), int64(length))
+	inputs := make([]ort.Value, 0, len(inputNames))
+	defer func() {
+		for _, input := range inputs {
+			input.Destroy()
+		}
+	}()
+	for _, name := range inputNames {
+		tensor, err := ort.NewTensor(shape, data[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("create %s tensor: %w", name, err)
+		}
+		inputs = append(inputs, tensor)
+	}
+
+	output, err := runInputs(session, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, mask, nil
+}
+
+// runInputs runs the model of the session on the inputs and returns its
+// float32 output, to destroy.
+func runInputs(session *ort.DynamicAdvancedSession, inputs []ort.Value) (*ort.Tensor[float32], error) {
+	outputs := []ort.Value{nil}
+	if err := session.Run(inputs, outputs); err != nil {
+		return nil, fmt.Errorf("run model: %w", err)
+	}
+
+	output, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		outputs[0].Destroy()
+		return nil, fmt.Errorf("%w: float32 tensor output required", ErrUnexpectedModel)
+	}
+	return output, nil
+}
+
+// pool returns the embeddings of the texts from the output of the model.
+func (e *ONNX) pool(output *ort.Tensor[float32], texts int, mask [][]int64) ([][]float64, error) {
+	shape := output.GetShape()
+	values := output.GetData()
+	switch {
+	case e.pooled && len(shape) == 2 && int(shape[0]) == texts:
+		dimensions := int(shape[1])
+		embeddings := make([][]float64, texts)
+		for i := range embeddings {
+			embeddings[i] = make([]float64, dimensions)
+			for k := range embeddings[i] {
+				embeddings[i][k] = float64(values[i*dimensions+k])
+			}
+		}
+		return embeddings, nil
+	case !e.pooled && len(shape) == 3 && int(shape[0]) == texts:
+		return meanPool(values, texts, int(shape[1]), int(shape[2]), mask), nil
+	default:
+		return nil, fmt.Errorf("%w: output shape %v", ErrUnexpectedModel, shape)
+	}
+}
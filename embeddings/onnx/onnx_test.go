@@ -0,0 +1,87 @@
+package onnx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+func TestMeanPool(t *testing.T) {
+	t.Parallel()
+
+	// Two texts of two tokens of two dimensions, the last token of the
+	// second text being padding.
+	embeddings := meanPool([]float32{1, 2, 3, 4, 5, 6, 100, 100}, 2, 2, 2, [][]int64{{1, 1}, {1, 0}})
+	assert.Equal(t, [][]float64{{2, 3}, {5, 6}}, embeddings)
+
+	embedding := []float64{3, 4}
+	normalize(embedding)
+	assert.InDeltaSlice(t, []float64{0.6, 0.8}, embedding, 1e-9)
+}
+
+func TestSetInputsOutputs(t *testing.T) {
+	t.Parallel()
+
+	inputs := []ort.InputOutputInfo{{Name: "input_ids"}, {Name: "attention_mask"}, {Name: "token_type_ids"}}
+
+	var e ONNX
+	name, err := e.setInputsOutputs(inputs, []ort.InputOutputInfo{
+		{Name: "last_hidden_state", Dimensions: ort.NewShape(-1, -1, 384)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "last_hidden_state", name)
+	assert.Equal(t, []string{"input_ids", "attention_mask", "token_type_ids"}, e.inputNames)
+	assert.False(t, e.pooled)
+
+	e = ONNX{}
+	name, err = e.setInputsOutputs(inputs[:2], []ort.InputOutputInfo{
+		{Name: "token_embeddings"}, {Name: "sentence_embedding"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sentence_embedding", name)
+	assert.Equal(t, []string{"input_ids", "attention_mask"}, e.inputNames)
+	assert.True(t, e.pooled)
+
+	_, err = e.setInputsOutputs(inputs[:1], nil)
+	require.ErrorIs(t, err, ErrUnexpectedModel)
+}
+
+func TestApplyOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := applyOptions()
+	require.ErrorIs(t, err, ErrMissingModel)
+
+	// The vocabulary is found in the parent directory of the model.
+	dir := t.TempDir()
+	writeVocab(t, dir)
+	o, err := applyOptions(WithModelPath(filepath.Join(dir, "onnx", "model.onnx")), WithMaxLength(128))
+	require.NoError(t, err)
+	assert.Equal(t, 128, o.MaxLength)
+	assert.True(t, o.Normalize)
+	assert.Equal(t, []int64{2, 4, 3}, o.tokenizer.encode("hello", o.MaxLength))
+}
+
+func TestONNXEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	modelPath := os.Getenv("ONNX_EMBEDDING_MODEL_PATH")
+	if modelPath == "" || os.Getenv(sharedLibraryPathEnvVarName) == "" {
+		t.Skip("ONNX_EMBEDDING_MODEL_PATH or ONNXRUNTIME_SHARED_LIBRARY_PATH not set")
+	}
+	e, err := NewONNX(WithModelPath(modelPath))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, e.Close()) })
+
+	_, err = e.EmbedQuery(context.Background(), "Hello world!")
+	require.NoError(t, err)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 3)
+}
@@ -0,0 +1,172 @@
+package onnx
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	sharedLibraryPathEnvVarName = "ONNXRUNTIME_SHARED_LIBRARY_PATH"
+
+	_defaultMaxLength     = 256
+	_defaultImageSize     = 224
+	_defaultBatchSize     = 512
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *ONNX)
+
+// WithModelPath is an option for specifying the path of the ONNX model, e.g.
+// onnx/model.onnx of a sentence-transformers model repository, or the path of
+// the text model of CLIP, e.g. onnx/text_model.onnx.
+func WithModelPath(path string) Option {
+	return func(p *ONNX) {
+		p.modelPath = path
+	}
+}
+
+// WithVocabPath is an option for specifying the path of the vocab.txt file of
+// the WordPiece tokenizer of the model, or of the vocab.json file of the BPE
+// tokenizer of CLIP. If not set, the file of the directory of the model or of
+// its parent directory is used.
+func WithVocabPath(path string) Option {
+	return func(p *ONNX) {
+		p.vocabPath = path
+	}
+}
+
+// WithSharedLibraryPath is an option for specifying the path of the ONNX
+// Runtime shared library, e.g. /usr/lib/libonnxruntime.so. If not set, the
+// path is read from the ONNXRUNTIME_SHARED_LIBRARY_PATH environment variable.
+func WithSharedLibraryPath(path string) Option {
+	return func(p *ONNX) {
+		p.libraryPath = path
+	}
+}
+
+// WithMaxLength is an option for specifying the maximum number of tokens of a
+// text, 256 by default.
+func WithMaxLength(maxLength int) Option {
+	return func(p *ONNX) {
+		p.MaxLength = maxLength
+	}
+}
+
+// WithNormalize is an option for specifying whether the embeddings are
+// rescaled to unit length, true by default.
+func WithNormalize(normalize bool) Option {
+	return func(p *ONNX) {
+		p.Normalize = normalize
+	}
+}
+
+// WithLowerCase is an option for specifying whether the texts are lower cased
+// and their accents removed before the tokenization, true by default as for
+// the uncased models.
+func WithLowerCase(lowerCase bool) Option {
+	return func(p *ONNX) {
+		p.lowerCase = lowerCase
+	}
+}
+
+// WithImageModelPath is an option for specifying the path of the image model
+// of CLIP, e.g. onnx/vision_model.onnx. Must be set for NewCLIP.
+func WithImageModelPath(path string) Option {
+	return func(p *ONNX) {
+		p.imageModelPath = path
+	}
+}
+
+// WithMergesPath is an option for specifying the path of the merges.txt file
+// of the BPE tokenizer of CLIP. If not set, the merges.txt file of the
+// directory of the model or of its parent directory is used.
+func WithMergesPath(path string) Option {
+	return func(p *ONNX) {
+		p.mergesPath = path
+	}
+}
+
+// WithImageSize is an option for specifying the size in pixels of the square
+// images of the image model of CLIP, 224 by default.
+func WithImageSize(size int) Option {
+	return func(p *ONNX) {
+		p.imageSize = size
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *ONNX) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size.
+func WithBatchSize(batchSize int) Option {
+	return func(p *ONNX) {
+		p.BatchSize = batchSize
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *ONNX) {
+		p.batchOptions = opts
+	}
+}
+
+func applyOptions(opts ...Option) (*ONNX, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.vocabPath == "" {
+		o.vocabPath = findFile(filepath.Dir(o.modelPath), "vocab.txt")
+	}
+	t, err := loadTokenizer(o.vocabPath, o.lowerCase)
+	if err != nil {
+		return nil, err
+	}
+	o.tokenizer = t
+
+	return o, nil
+}
+
+// newOptions returns the options, without loading the tokenizer.
+func newOptions(opts ...Option) (*ONNX, error) {
+	o := &ONNX{
+		MaxLength:     _defaultMaxLength,
+		Normalize:     true,
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		libraryPath:   os.Getenv(sharedLibraryPathEnvVarName),
+		lowerCase:     true,
+		imageSize:     _defaultImageSize,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.modelPath == "" {
+		return nil, ErrMissingModel
+	}
+
+	return o, nil
+}
+
+// findFile returns the path of the file of the directory or of its parent
+// directory, the models of the sentence-transformers repositories being in an
+// onnx directory.
+func findFile(dir, name string) string {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return filepath.Join(filepath.Dir(dir), name)
+}
@@ -0,0 +1,45 @@
+package onnx
+
+import "math"
+
+// meanPool averages the token embeddings of each text, of shape [texts,
+// tokens, dimensions], over the tokens of the attention mask.
+func meanPool(tokenEmbeddings []float32, texts, tokens, dimensions int, mask [][]int64) [][]float64 {
+	embeddings := make([][]float64, texts)
+	for i := range embeddings {
+		embedding := make([]float64, dimensions)
+		var count float64
+		for j := 0; j < tokens; j++ {
+			if mask[i][j] == 0 {
+				continue
+			}
+			count++
+			offset := (i*tokens + j) * dimensions
+			for k := range embedding {
+				embedding[k] += float64(tokenEmbeddings[offset+k])
+			}
+		}
+		if count > 0 {
+			for k := range embedding {
+				embedding[k] /= count
+			}
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings
+}
+
+// normalize rescales the embedding to unit length.
+func normalize(embedding []float64) {
+	var norm float64
+	for _, v := range embedding {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return
+	}
+	for i := range embedding {
+		embedding[i] /= norm
+	}
+}
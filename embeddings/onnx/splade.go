@@ -0,0 +1,161 @@
+package onnx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// SPLADE is the sparse embedder running a SPLADE model exported to ONNX, e.g.
+// naver/splade-cocondenser-ensembledistil, with ONNX Runtime. The weight of
+// each term of the vocabulary is the maximum over the tokens of the text of
+// log(1 + relu(logit)) of the masked language model head, and the index of a
+// term is its id in the vocabulary. It requires cgo.
+type SPLADE struct {
+	session    *ort.DynamicAdvancedSession
+	tokenizer  *tokenizer
+	inputNames []string
+
+	// MaxLength is the maximum number of tokens of a text, longer texts are
+	// truncated.
+	MaxLength int
+
+	StripNewLines bool
+
+	batchOptions []embeddings.BatchOption
+}
+
+var _ embeddings.SparseEmbedder = (*SPLADE)(nil)
+
+// NewSPLADE loads the model with the options of NewONNX. The options of the
+// dense embeddings, such as WithNormalize and WithBatchSize, are ignored. The
+// embedder must be closed to release the model.
+func NewSPLADE(opts ...Option) (*SPLADE, error) {
+	o, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := initRuntime(o.libraryPath); err != nil {
+		return nil, err
+	}
+	inputs, outputs, err := ort.GetInputOutputInfo(o.modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("read model: %w", err)
+	}
+	names, err := inputNames(inputs)
+	if err != nil {
+		return nil, err
+	}
+	outputName, err := logitsOutput(outputs)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ort.NewDynamicAdvancedSession(o.modelPath, names, []string{outputName}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	return &SPLADE{
+		session:       session,
+		tokenizer:     o.tokenizer,
+		inputNames:    names,
+		MaxLength:     o.MaxLength,
+		StripNewLines: o.StripNewLines,
+		batchOptions:  o.batchOptions,
+	}, nil
+}
+
+// Close releases the model.
+func (e *SPLADE) Close() error {
+	return e.session.Destroy()
+}
+
+// EmbedDocuments creates one sparse vector for each of the texts.
+func (e *SPLADE) EmbedDocuments(ctx context.Context, texts []string) ([]embeddings.SparseVector, error) {
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(32)}, e.batchOptions...)
+	return embeddings.EmbedSparseInBatches(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.embed,
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e *SPLADE) EmbedQuery(ctx context.Context, text string) (embeddings.SparseVector, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	vectors, err := e.embed(ctx, []string{text})
+	if err != nil {
+		return embeddings.SparseVector{}, err
+	}
+
+	return vectors[0], nil
+}
+
+// embed runs the model on the texts.
+func (e *SPLADE) embed(_ context.Context, texts []string) ([]embeddings.SparseVector, error) {
+	output, mask, err := run(e.session, e.inputNames, e.tokenizer.encodeAll(texts, e.MaxLength))
+	if err != nil {
+		return nil, err
+	}
+	defer output.Destroy()
+
+	shape := output.GetShape()
+	if len(shape) != 3 || int(shape[0]) != len(texts) {
+		return nil, fmt.Errorf("%w: output shape %v", ErrUnexpectedModel, shape)
+	}
+	return maxPool(output.GetData(), len(texts), int(shape[1]), int(shape[2]), mask), nil
+}
+
+// logitsOutput returns the name of the logits output of the masked language
+// model head, the first output if none is named logits.
+func logitsOutput(outputs []ort.InputOutputInfo) (string, error) {
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("%w: no outputs", ErrUnexpectedModel)
+	}
+	for _, output := range outputs {
+		if output.Name == "logits" {
+			return output.Name, nil
+		}
+	}
+	return outputs[0].Name, nil
+}
+
+// maxPool returns the sparse vectors of the texts from the logits of their
+// tokens, of shape texts x tokens x vocabulary size: the weight of a term is
+// the maximum over the tokens not masked of log(1 + relu(logit)).
+func maxPool(logits []float32, texts, tokens, size int, mask [][]int64) []embeddings.SparseVector {
+	vectors := make([]embeddings.SparseVector, texts)
+	for i := range vectors {
+		weights := make([]float64, size)
+		for j := 0; j < tokens; j++ {
+			if mask[i][j] == 0 {
+				continue
+			}
+			row := logits[(i*tokens+j)*size : (i*tokens+j+1)*size]
+			for k, logit := range row {
+				if logit <= 0 {
+					continue
+				}
+				if w := math.Log1p(float64(logit)); w > weights[k] {
+					weights[k] = w
+				}
+			}
+		}
+		for k, w := range weights {
+			if w > 0 {
+				vectors[i].Indices = append(vectors[i].Indices, k)
+				vectors[i].Values = append(vectors[i].Values, w)
+			}
+		}
+	}
+	return vectors
+}
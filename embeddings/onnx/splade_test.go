@@ -0,0 +1,66 @@
+package onnx
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+func TestMaxPool(t *testing.T) {
+	t.Parallel()
+
+	// Two texts of two tokens of a vocabulary of three terms, the last token
+	// of the second text being padding.
+	vectors := maxPool([]float32{
+		1, -1, 0,
+		3, 0, 0,
+		0, 0, 2,
+		9, 9, 9,
+	}, 2, 2, 3, [][]int64{{1, 1}, {1, 0}})
+	require.Len(t, vectors, 2)
+	assert.Equal(t, []int{0}, vectors[0].Indices)
+	assert.InDeltaSlice(t, []float64{1.3862943611}, vectors[0].Values, 1e-9)
+	assert.Equal(t, []int{2}, vectors[1].Indices)
+	assert.InDeltaSlice(t, []float64{1.0986122887}, vectors[1].Values, 1e-9)
+}
+
+func TestLogitsOutput(t *testing.T) {
+	t.Parallel()
+
+	name, err := logitsOutput([]ort.InputOutputInfo{{Name: "hidden_states"}, {Name: "logits"}})
+	require.NoError(t, err)
+	assert.Equal(t, "logits", name)
+
+	name, err = logitsOutput([]ort.InputOutputInfo{{Name: "output_0"}})
+	require.NoError(t, err)
+	assert.Equal(t, "output_0", name)
+
+	_, err = logitsOutput(nil)
+	require.ErrorIs(t, err, ErrUnexpectedModel)
+}
+
+func TestSPLADEEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	modelPath := os.Getenv("ONNX_SPLADE_MODEL_PATH")
+	if modelPath == "" || os.Getenv(sharedLibraryPathEnvVarName) == "" {
+		t.Skip("ONNX_SPLADE_MODEL_PATH or ONNXRUNTIME_SHARED_LIBRARY_PATH not set")
+	}
+	e, err := NewSPLADE(WithModelPath(modelPath))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, e.Close()) })
+
+	var _ embeddings.SparseEmbedder = e
+	query, err := e.EmbedQuery(context.Background(), "Hello world!")
+	require.NoError(t, err)
+	assert.NotEmpty(t, query.Indices)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 3)
+}
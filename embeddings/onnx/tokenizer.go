@@ -0,0 +1,168 @@
+package onnx
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxWordLength is the length in characters of the longest word split into
+// word pieces, longer words are unknown tokens.
+const maxWordLength = 100
+
+// tokenizer is the WordPiece tokenizer of the BERT models, used by most
+// sentence-transformers models.
+type tokenizer struct {
+	vocab     map[string]int64
+	lowerCase bool
+
+	unk, cls, sep int64
+}
+
+// loadTokenizer reads the vocabulary of the tokenizer from a vocab.txt file,
+// one token per line, the line number being the id of the token.
+func loadTokenizer(path string, lowerCase bool) (*tokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open vocabulary: %w", err)
+	}
+	defer f.Close()
+
+	vocab := make(map[string]int64)
+	scanner := bufio.NewScanner(f)
+	for id := int64(0); scanner.Scan(); id++ {
+		vocab[strings.TrimRight(scanner.Text(), "\r")] = id
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+	return newTokenizer(vocab, lowerCase)
+}
+
+func newTokenizer(vocab map[string]int64, lowerCase bool) (*tokenizer, error) {
+	t := &tokenizer{vocab: vocab, lowerCase: lowerCase}
+	for token, id := range map[string]*int64{"[UNK]": &t.unk, "[CLS]": &t.cls, "[SEP]": &t.sep} {
+		var ok bool
+		if *id, ok = vocab[token]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingToken, token)
+		}
+	}
+	return t, nil
+}
+
+// encodeAll encodes the texts.
+func (t *tokenizer) encodeAll(texts []string, maxLength int) [][]int64 {
+	ids := make([][]int64, len(texts))
+	for i, text := range texts {
+		ids[i] = t.encode(text, maxLength)
+	}
+	return ids
+}
+
+// encode returns the ids of the tokens of the text between the [CLS] and
+// [SEP] tokens, truncated to maxLength ids.
+func (t *tokenizer) encode(text string, maxLength int) []int64 {
+	ids := []int64{t.cls}
+	for _, word := range t.words(text) {
+		ids = append(ids, t.wordPieces(word)...)
+	}
+	if maxLength > 1 && len(ids) > maxLength-1 {
+		ids = ids[:maxLength-1]
+	}
+	return append(ids, t.sep)
+}
+
+// words splits the text on whitespaces and punctuation, lower casing it and
+// removing its accents for uncased models. Chinese characters are words.
+func (t *tokenizer) words(text string) []string {
+	if t.lowerCase {
+		text = strings.ToLower(text)
+		text = stripAccents(text)
+	}
+
+	var (
+		words []string
+		word  strings.Builder
+	)
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r == 0 || r == unicode.ReplacementChar || isControl(r):
+		case unicode.IsSpace(r):
+			flush()
+		case isPunctuation(r) || unicode.Is(unicode.Han, r):
+			flush()
+			words = append(words, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return words
+}
+
+// wordPieces splits the word in the longest pieces of the vocabulary, the
+// pieces following the first being prefixed with ##.
+func (t *tokenizer) wordPieces(word string) []int64 {
+	runes := []rune(word)
+	if len(runes) > maxWordLength {
+		return []int64{t.unk}
+	}
+
+	var ids []int64
+	for start := 0; start < len(runes); {
+		end := len(runes)
+		found := false
+		for ; end > start; end-- {
+			piece := string(runes[start:end])
+			if start > 0 {
+				piece = "##" + piece
+			}
+			if id, ok := t.vocab[piece]; ok {
+				ids = append(ids, id)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []int64{t.unk}
+		}
+		start = end
+	}
+	return ids
+}
+
+func stripAccents(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isControl(r rune) bool {
+	if r == '\t' || r == '\n' || r == '\r' {
+		return false
+	}
+	return unicode.In(r, unicode.Cc, unicode.Cf)
+}
+
+// isPunctuation reports whether the rune is a punctuation character, all the
+// non-alphanumeric ASCII characters being punctuation as in BERT.
+func isPunctuation(r rune) bool {
+	if r >= 33 && r <= 47 || r >= 58 && r <= 64 || r >= 91 && r <= 96 || r >= 123 && r <= 126 {
+		return true
+	}
+	return unicode.IsPunct(r)
+}
@@ -0,0 +1,59 @@
+package onnx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testVocab = []string{ //nolint:gochecknoglobals
+	"[PAD]", "[UNK]", "[CLS]", "[SEP]", "hello", ",", "un", "##aff", "##able", "world", "!", "cafe", "中",
+}
+
+func writeVocab(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "vocab.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(testVocab, "\n")+"\n"), 0o600))
+	return path
+}
+
+func TestTokenizer(t *testing.T) {
+	t.Parallel()
+
+	tok, err := loadTokenizer(writeVocab(t, t.TempDir()), true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		text      string
+		maxLength int
+		want      []int64
+	}{
+		{"Hello, unaffable World!", 0, []int64{2, 4, 5, 6, 7, 8, 9, 10, 3}},
+		{"  Café\tXYZ\n中", 0, []int64{2, 11, 1, 12, 3}},
+		{"hello unaffable", 4, []int64{2, 4, 6, 3}},
+		{"", 0, []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tok.encode(tt.text, tt.maxLength), tt.text)
+	}
+}
+
+func TestTokenizerCased(t *testing.T) {
+	t.Parallel()
+
+	tok, err := loadTokenizer(writeVocab(t, t.TempDir()), false)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1, 4, 3}, tok.encode("Hello hello", 0))
+}
+
+func TestTokenizerMissingToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTokenizer(map[string]int64{"[CLS]": 0, "[SEP]": 1}, true)
+	require.ErrorIs(t, err, ErrMissingToken)
+}
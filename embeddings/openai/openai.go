@@ -14,6 +14,8 @@ type OpenAI struct {
 
 	StripNewLines bool
 	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
 }
 
 var _ embeddings.Embedder = OpenAI{}
@@ -30,32 +32,13 @@ func NewOpenAI(opts ...Option) (OpenAI, error) {
 
 // EmbedDocuments creates one vector embedding for each of the texts.
 func (e OpenAI) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
-	batchedTexts := embeddings.BatchTexts(
+	return embeddings.EmbedChunks(
+		ctx,
 		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
 		e.BatchSize,
+		e.client.CreateEmbedding,
+		e.batchOptions...,
 	)
-
-	emb := make([][]float64, 0, len(texts))
-	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts)
-		if err != nil {
-			return nil, err
-		}
-
-		textLengths := make([]int, 0, len(texts))
-		for _, text := range texts {
-			textLengths = append(textLengths, len(text))
-		}
-
-		combined, err := embeddings.CombineVectors(curTextEmbeddings, textLengths)
-		if err != nil {
-			return nil, err
-		}
-
-		emb = append(emb, combined)
-	}
-
-	return emb, nil
 }
 
 // EmbedQuery embeds a single text.
@@ -14,6 +14,8 @@ type ChatOpenAI struct {
 
 	StripNewLines bool
 	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
 }
 
 var _ embeddings.Embedder = ChatOpenAI{}
@@ -29,32 +31,13 @@ func NewChatOpenAI(opts ...ChatOption) (ChatOpenAI, error) {
 }
 
 func (e ChatOpenAI) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
-	batchedTexts := embeddings.BatchTexts(
+	return embeddings.EmbedChunks(
+		ctx,
 		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
 		e.BatchSize,
+		e.client.CreateEmbedding,
+		e.batchOptions...,
 	)
-
-	emb := make([][]float64, 0, len(texts))
-	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts)
-		if err != nil {
-			return nil, err
-		}
-
-		textLengths := make([]int, 0, len(texts))
-		for _, text := range texts {
-			textLengths = append(textLengths, len(text))
-		}
-
-		combined, err := embeddings.CombineVectors(curTextEmbeddings, textLengths)
-		if err != nil {
-			return nil, err
-		}
-
-		emb = append(emb, combined)
-	}
-
-	return emb, nil
 }
 
 func (e ChatOpenAI) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
@@ -1,6 +1,7 @@
 package openaichat
 
 import (
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -32,6 +33,14 @@ func WithBatchSize(batchSize int) ChatOption {
 	}
 }
 
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) ChatOption {
+	return func(p *ChatOpenAI) {
+		p.batchOptions = opts
+	}
+}
+
 func applyChatClientOptions(opts ...ChatOption) (ChatOpenAI, error) {
 	o := &ChatOpenAI{
 		StripNewLines: _defaultStripNewLines,
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -33,6 +34,14 @@ func WithBatchSize(batchSize int) Option {
 	}
 }
 
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *OpenAI) {
+		p.batchOptions = opts
+	}
+}
+
 func applyClientOptions(opts ...Option) (OpenAI, error) {
 	o := &OpenAI{
 		StripNewLines: _defaultStripNewLines,
@@ -0,0 +1,19 @@
+package embeddings
+
+import "context"
+
+// SparseVector is a vector of term weights, such as the BM25 weights of the
+// words of a text. The weights of the indices absent from Indices are zero.
+type SparseVector struct {
+	Indices []int
+	Values  []float64
+}
+
+// SparseEmbedder is the interface for creating sparse vector embeddings from
+// texts, searched along with dense embeddings in hybrid searches.
+type SparseEmbedder interface {
+	// EmbedDocuments returns a sparse vector for each text.
+	EmbedDocuments(ctx context.Context, texts []string) ([]SparseVector, error)
+	// EmbedQuery embeds a single text.
+	EmbedQuery(ctx context.Context, text string) (SparseVector, error)
+}
@@ -1,6 +1,7 @@
 package vertexai
 
 import (
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/vertexai"
 )
 
@@ -33,6 +34,14 @@ func WithBatchSize(batchSize int) Option {
 	}
 }
 
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *VertexAIPaLM) {
+		p.batchOptions = opts
+	}
+}
+
 func applyClientOptions(opts ...Option) (*VertexAIPaLM, error) {
 	v := &VertexAIPaLM{
 		StripNewLines: _defaultStripNewLines,
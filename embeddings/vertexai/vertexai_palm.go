@@ -14,6 +14,8 @@ type VertexAIPaLM struct { //nolint:revive
 
 	StripNewLines bool
 	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
 }
 
 var _ embeddings.Embedder = VertexAIPaLM{}
@@ -30,32 +32,15 @@ func NewVertexAIPaLM(opts ...Option) (*VertexAIPaLM, error) {
 
 // EmbedDocuments creates one vector embedding for each of the texts.
 func (e VertexAIPaLM) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
-	batchedTexts := embeddings.BatchTexts(
+	// The API embeds at most 5 texts per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(5)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
 		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
 		e.BatchSize,
+		e.client.CreateEmbedding,
+		opts...,
 	)
-
-	emb := make([][]float64, 0, len(texts))
-	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts)
-		if err != nil {
-			return nil, err
-		}
-
-		textLengths := make([]int, 0, len(texts))
-		for _, text := range texts {
-			textLengths = append(textLengths, len(text))
-		}
-
-		combined, err := embeddings.CombineVectors(curTextEmbeddings, textLengths)
-		if err != nil {
-			return nil, err
-		}
-
-		emb = append(emb, combined)
-	}
-
-	return emb, nil
 }
 
 // EmbedQuery embeds a single text.
@@ -64,7 +49,7 @@ func (e VertexAIPaLM) EmbedQuery(ctx context.Context, text string) ([]float64, e
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := e.client.CreateEmbedding(ctx, []string{text})
+	emb, err := e.client.CreateEmbeddingWithTaskType(ctx, []string{text}, vertexai.TaskTypeRetrievalQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package vertexaichat
 
 import (
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/vertexai"
 )
 
@@ -32,6 +33,14 @@ func WithBatchSize(batchSize int) ChatOption {
 	}
 }
 
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) ChatOption {
+	return func(p *ChatVertexAI) {
+		p.batchOptions = opts
+	}
+}
+
 func applyChatClientOptions(opts ...ChatOption) (ChatVertexAI, error) {
 	o := &ChatVertexAI{
 		StripNewLines: _defaultStripNewLines,
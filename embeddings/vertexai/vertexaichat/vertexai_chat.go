@@ -14,6 +14,8 @@ type ChatVertexAI struct {
 
 	StripNewLines bool
 	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
 }
 
 var _ embeddings.Embedder = ChatVertexAI{}
@@ -29,32 +31,15 @@ func NewChatVertexAI(opts ...ChatOption) (ChatVertexAI, error) {
 }
 
 func (e ChatVertexAI) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
-	batchedTexts := embeddings.BatchTexts(
+	// The API embeds at most 5 texts per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(5)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
 		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
 		e.BatchSize,
+		e.client.CreateEmbedding,
+		opts...,
 	)
-
-	emb := make([][]float64, 0, len(texts))
-	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts)
-		if err != nil {
-			return nil, err
-		}
-
-		textLengths := make([]int, 0, len(texts))
-		for _, text := range texts {
-			textLengths = append(textLengths, len(text))
-		}
-
-		combined, err := embeddings.CombineVectors(curTextEmbeddings, textLengths)
-		if err != nil {
-			return nil, err
-		}
-
-		emb = append(emb, combined)
-	}
-
-	return emb, nil
 }
 
 func (e ChatVertexAI) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
@@ -62,7 +47,7 @@ func (e ChatVertexAI) EmbedQuery(ctx context.Context, text string) ([]float64, e
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := e.client.CreateEmbedding(ctx, []string{text})
+	emb, err := e.client.CreateEmbeddingWithTaskType(ctx, []string{text}, vertexai.TaskTypeRetrievalQuery)
 	if err != nil {
 		return nil, err
 	}
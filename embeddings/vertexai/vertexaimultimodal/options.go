@@ -0,0 +1,71 @@
+package vertexaimultimodal
+
+import (
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/vertexai"
+)
+
+const (
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *VertexAIMultimodal)
+
+// WithClient is an option for providing the LLM client.
+func WithClient(client vertexai.LLM) Option {
+	return func(p *VertexAIMultimodal) {
+		p.client = &client
+	}
+}
+
+// WithModel is an option for specifying the multimodal embedding model.
+func WithModel(model string) Option {
+	return func(p *VertexAIMultimodal) {
+		p.Model = model
+	}
+}
+
+// WithDimension is an option for specifying the dimension of the
+// embeddings, 128, 256, 512 or 1408 by default. Smaller embeddings take less
+// space in the vector store.
+func WithDimension(dimension int) Option {
+	return func(p *VertexAIMultimodal) {
+		p.Dimension = dimension
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *VertexAIMultimodal) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent, e.g.
+// embeddings.WithMaxParallel, the API embedding a single text per request.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *VertexAIMultimodal) {
+		p.batchOptions = opts
+	}
+}
+
+func applyClientOptions(opts ...Option) (VertexAIMultimodal, error) {
+	o := &VertexAIMultimodal{
+		StripNewLines: _defaultStripNewLines,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.client == nil {
+		client, err := vertexai.New()
+		if err != nil {
+			return VertexAIMultimodal{}, err
+		}
+		o.client = client
+	}
+
+	return *o, nil
+}
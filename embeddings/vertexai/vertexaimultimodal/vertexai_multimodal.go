@@ -0,0 +1,93 @@
+package vertexaimultimodal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/vertexai"
+)
+
+// VertexAIMultimodal is the embedder using the Vertex AI multimodal embedding
+// model, embedding texts and images in the same space.
+type VertexAIMultimodal struct {
+	client *vertexai.LLM
+
+	// Dimension is the dimension of the embeddings, 128, 256, 512 or 1408,
+	// the model default if zero.
+	Dimension int
+	// Model is the multimodal embedding model, multimodalembedding@001 if
+	// not set.
+	Model string
+
+	StripNewLines bool
+
+	batchOptions []embeddings.BatchOption
+}
+
+var _ embeddings.MultimodalEmbedder = VertexAIMultimodal{}
+
+// NewVertexAIMultimodal creates a new VertexAIMultimodal with options.
+func NewVertexAIMultimodal(opts ...Option) (VertexAIMultimodal, error) {
+	o, err := applyClientOptions(opts...)
+	if err != nil {
+		return VertexAIMultimodal{}, err
+	}
+
+	return o, nil
+}
+
+// EmbedDocuments embeds the texts. The model embeds the first 32 tokens of a
+// text, so the texts are best kept short, e.g. captions.
+func (e VertexAIMultimodal) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The API embeds a single text per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(1)}, e.batchOptions...)
+	return embeddings.EmbedInBatches(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		func(ctx context.Context, texts []string) ([][]float64, error) {
+			vector, err := e.EmbedQuery(ctx, texts[0])
+			if err != nil {
+				return nil, err
+			}
+			return [][]float64{vector}, nil
+		},
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e VertexAIMultimodal) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	vector, _, err := e.client.CreateMultimodalEmbedding(ctx, text, nil, e.options()...)
+	return vector, err
+}
+
+// EmbedImage embeds a single image.
+func (e VertexAIMultimodal) EmbedImage(ctx context.Context, image []byte) ([]float64, error) {
+	_, vector, err := e.client.CreateMultimodalEmbedding(ctx, "", image, e.options()...)
+	return vector, err
+}
+
+// EmbedTextAndImage embeds a text and an image with a single request.
+func (e VertexAIMultimodal) EmbedTextAndImage(ctx context.Context, text string, image []byte) ([]float64, []float64, error) { //nolint:lll
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	return e.client.CreateMultimodalEmbedding(ctx, text, image, e.options()...)
+}
+
+func (e VertexAIMultimodal) options() []vertexai.MultimodalEmbeddingOption {
+	var opts []vertexai.MultimodalEmbeddingOption
+	if e.Model != "" {
+		opts = append(opts, vertexai.WithMultimodalEmbeddingModel(e.Model))
+	}
+	if e.Dimension > 0 {
+		opts = append(opts, vertexai.WithMultimodalEmbeddingDimension(e.Dimension))
+	}
+	return opts
+}
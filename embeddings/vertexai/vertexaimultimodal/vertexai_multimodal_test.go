@@ -0,0 +1,41 @@
+package vertexaimultimodal
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVertexAIMultimodalEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	if gcpProjectID := os.Getenv("GOOGLE_CLOUD_PROJECT"); gcpProjectID == "" {
+		t.Skip("GOOGLE_CLOUD_PROJECT not set")
+	}
+	imagePath := os.Getenv("VERTEXAI_MULTIMODAL_IMAGE_PATH")
+	if imagePath == "" {
+		t.Skip("VERTEXAI_MULTIMODAL_IMAGE_PATH not set")
+	}
+	image, err := os.ReadFile(imagePath)
+	require.NoError(t, err)
+
+	e, err := NewVertexAIMultimodal(WithDimension(256))
+	require.NoError(t, err)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"a cat", "a dog"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 2)
+	assert.Len(t, embeddings[0], 256)
+
+	imageEmbedding, err := e.EmbedImage(context.Background(), image)
+	require.NoError(t, err)
+	assert.Len(t, imageEmbedding, 256)
+
+	text, img, err := e.EmbedTextAndImage(context.Background(), "a cat", image)
+	require.NoError(t, err)
+	assert.Len(t, text, 256)
+	assert.Len(t, img, 256)
+}
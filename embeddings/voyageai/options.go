@@ -0,0 +1,99 @@
+package voyageai
+
+import (
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/voyageai/internal/voyageaiclient"
+)
+
+const (
+	tokenEnvVarName = "VOYAGE_API_KEY" //nolint:gosec
+
+	_defaultModel         = "voyage-3.5"
+	_defaultBatchSize     = 512
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *VoyageAI)
+
+// WithToken is an option for specifying the API key. If not set, the key is
+// read from the VOYAGE_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(p *VoyageAI) {
+		p.token = token
+	}
+}
+
+// WithModel is an option for specifying the embedding model, e.g.
+// voyage-code-3 for code retrieval. The default is voyage-3.5.
+func WithModel(model string) Option {
+	return func(p *VoyageAI) {
+		p.Model = model
+	}
+}
+
+// WithDimensions is an option for specifying the dimension of the
+// embeddings, e.g. 256, 512, 1024 or 2048 for the models supporting it.
+func WithDimensions(dimensions int) Option {
+	return func(p *VoyageAI) {
+		p.Dimensions = dimensions
+	}
+}
+
+// WithBaseURL is an option for specifying the base url of the API.
+func WithBaseURL(baseURL string) Option {
+	return func(p *VoyageAI) {
+		p.clientOpts = append(p.clientOpts, voyageaiclient.WithBaseURL(baseURL))
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client voyageaiclient.Doer) Option {
+	return func(p *VoyageAI) {
+		p.clientOpts = append(p.clientOpts, voyageaiclient.WithHTTPClient(client))
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *VoyageAI) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size.
+func WithBatchSize(batchSize int) Option {
+	return func(p *VoyageAI) {
+		p.BatchSize = batchSize
+	}
+}
+
+// WithBatchOptions is an option for specifying how the texts are sent in
+// batches, e.g. embeddings.WithTextsPerRequest and embeddings.WithMaxParallel.
+func WithBatchOptions(opts ...embeddings.BatchOption) Option {
+	return func(p *VoyageAI) {
+		p.batchOptions = opts
+	}
+}
+
+func applyClientOptions(opts ...Option) (VoyageAI, error) {
+	v := &VoyageAI{
+		Model:         _defaultModel,
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		token:         os.Getenv(tokenEnvVarName),
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.token == "" {
+		return VoyageAI{}, ErrMissingToken
+	}
+	v.client = voyageaiclient.New(v.token, v.clientOpts...)
+
+	return *v, nil
+}
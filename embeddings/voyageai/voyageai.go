@@ -0,0 +1,88 @@
+package voyageai
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/voyageai/internal/voyageaiclient"
+)
+
+// ErrMissingToken is returned when the API key is not set.
+var ErrMissingToken = errors.New("missing the Voyage AI API key, set it in the VOYAGE_API_KEY environment variable")
+
+// VoyageAI is the embedder using the Voyage AI embeddings API. The documents
+// and the queries are embedded with their own input types, as the models are
+// trained for retrieval.
+type VoyageAI struct {
+	client *voyageaiclient.Client
+	// Model is the embedding model, e.g. voyage-3.5 or voyage-code-3 for code.
+	Model string
+	// Dimensions is the dimension of the embeddings, e.g. 256, 512, 1024 or
+	// 2048 for the models supporting it. The model default if zero.
+	Dimensions int
+
+	StripNewLines bool
+	BatchSize     int
+
+	batchOptions []embeddings.BatchOption
+
+	token      string
+	clientOpts []voyageaiclient.Option
+}
+
+var _ embeddings.Embedder = VoyageAI{}
+
+// NewVoyageAI creates a new VoyageAI with options. The API key is read from
+// the VOYAGE_API_KEY environment variable if not set.
+func NewVoyageAI(opts ...Option) (VoyageAI, error) {
+	v, err := applyClientOptions(opts...)
+	if err != nil {
+		return VoyageAI{}, err
+	}
+
+	return v, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e VoyageAI) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	// The API embeds at most 1000 texts and 120K tokens per request.
+	opts := append([]embeddings.BatchOption{embeddings.WithTextsPerRequest(128)}, e.batchOptions...)
+	return embeddings.EmbedChunks(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+		func(ctx context.Context, texts []string) ([][]float64, error) {
+			return e.createEmbedding(ctx, texts, voyageaiclient.InputTypeDocument)
+		},
+		opts...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e VoyageAI) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.createEmbedding(ctx, []string{text}, voyageaiclient.InputTypeQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+func (e VoyageAI) createEmbedding(
+	ctx context.Context,
+	texts []string,
+	inputType voyageaiclient.InputType,
+) ([][]float64, error) {
+	return e.client.CreateEmbedding(ctx, &voyageaiclient.EmbeddingRequest{
+		Input:           texts,
+		Model:           e.Model,
+		InputType:       inputType,
+		OutputDimension: e.Dimensions,
+	})
+}
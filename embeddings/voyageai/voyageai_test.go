@@ -0,0 +1,88 @@
+package voyageai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestVoyageAIEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		// The embeddings are returned out of order.
+		input, _ := req["input"].([]any)
+		data := make([]map[string]any, 0, len(input))
+		for i := len(input) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{"embedding": []float64{float64(i), 1}, "index": i})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewVoyageAI(WithToken("key"), WithBaseURL(server.URL), WithModel("voyage-code-3"), WithDimensions(256))
+	require.NoError(t, err)
+
+	embedding, err := e.EmbedQuery(context.Background(), "Hello\nworld!")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1}, embedding)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	// The embeddings are placed by index and normalized.
+	assert.InDeltaSlice(t, []float64{0.894427191, 0.447213595}, vectors[2], 1e-9)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, map[string]any{
+		"input":            []any{"Hello world!"},
+		"model":            "voyage-code-3",
+		"input_type":       "query",
+		"output_dimension": 256.0,
+	}, requests[0])
+	assert.Equal(t, "document", requests[1]["input_type"])
+}
+
+func TestVoyageAIEmbeddingsError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"detail":"Provided API key is invalid."}`)
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewVoyageAI(WithToken("key"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "Hello world!")
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+	assert.Contains(t, err.Error(), "Provided API key is invalid.")
+
+	if os.Getenv(tokenEnvVarName) == "" {
+		_, err = NewVoyageAI()
+		require.ErrorIs(t, err, ErrMissingToken)
+	}
+}
@@ -0,0 +1,3 @@
+// Package graphs contains the interface of the graph databases queried by the
+// graph chains, see chains.GraphCypherQAChain.
+package graphs
@@ -0,0 +1,24 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidQuery is returned when a graph database rejects a query, e.g.
+// because of a syntax error or of an unknown function.
+var ErrInvalidQuery = errors.New("invalid graph query")
+
+// Graph is the interface of the graph databases queried with Cypher.
+type Graph interface {
+	// Query runs the query with the parameters and returns its records, the
+	// values of the columns by their names.
+	Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+	// Validate checks the query without running it. It returns an error
+	// wrapping ErrInvalidQuery if the query is rejected.
+	Validate(ctx context.Context, query string) error
+	// Schema returns the description of the schema of the graph, the
+	// properties of its nodes and relationships and the relationships
+	// between the labels of the nodes.
+	Schema(ctx context.Context) (string, error)
+}
@@ -0,0 +1,3 @@
+// Package neo4j contains an implementation of the graphs.Graph interface
+// querying a Neo4j database with the Cypher transactional HTTP API.
+package neo4j
@@ -0,0 +1,251 @@
+package neo4j
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/graphs"
+)
+
+const (
+	_nodePropertiesQuery = `CALL db.schema.nodeTypeProperties() YIELD nodeLabels, propertyName, propertyTypes
+RETURN nodeLabels, propertyName, propertyTypes`
+	_relationshipPropertiesQuery = `CALL db.schema.relTypeProperties() YIELD relType, propertyName, propertyTypes
+RETURN relType, propertyName, propertyTypes`
+	_relationshipsQuery = `MATCH (start)-[r]->(end)
+WITH DISTINCT labels(start) AS start, type(r) AS type, labels(end) AS end
+RETURN start, type, end LIMIT 1000`
+)
+
+// ErrQueryFailed is returned when a query fails for another reason than the
+// query itself, e.g. when the database is unavailable.
+var ErrQueryFailed = errors.New("neo4j query failed")
+
+// Graph is a Neo4j graph database queried with the Cypher transactional HTTP
+// API.
+type Graph struct {
+	url        string
+	username   string
+	password   string
+	database   string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	schema string
+}
+
+var _ graphs.Graph = &Graph{}
+
+// New creates a new Neo4j graph with options.
+func New(opts ...Option) (*Graph, error) {
+	return applyClientOptions(opts...)
+}
+
+type statement struct {
+	Statement          string         `json:"statement"`
+	Parameters         map[string]any `json:"parameters,omitempty"`
+	ResultDataContents []string       `json:"resultDataContents"`
+}
+
+type queryResponse struct {
+	Results []struct {
+		Columns []string `json:"columns"`
+		Data    []struct {
+			Row []any `json:"row"`
+		} `json:"data"`
+	} `json:"results"`
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Query runs the query in a transaction and returns its records. The errors of
+// the statements, e.g. the syntax errors, wrap graphs.ErrInvalidQuery.
+func (g *Graph) Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	payload, err := json.Marshal(map[string]any{
+		"statements": []statement{{Statement: query, Parameters: params, ResultDataContents: []string{"row"}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSuffix(g.url, "/") + "/db/" + url.PathEscape(g.database) + "/tx/commit"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json;charset=UTF-8")
+	if g.username != "" {
+		req.SetBasicAuth(g.username, g.password)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return nil, fmt.Errorf("%w: status %d: %s", ErrQueryFailed, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var res queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Errors) > 0 {
+		code, message := res.Errors[0].Code, res.Errors[0].Message
+		// The client errors of the statements and of the procedures called
+		// are errors of the query, the other errors those of the database.
+		if strings.HasPrefix(code, "Neo.ClientError.Statement.") || strings.HasPrefix(code, "Neo.ClientError.Procedure.") {
+			return nil, fmt.Errorf("%w: %s: %s", graphs.ErrInvalidQuery, code, message)
+		}
+		return nil, fmt.Errorf("%w: %s: %s", ErrQueryFailed, code, message)
+	}
+
+	records := []map[string]any{}
+	for _, result := range res.Results {
+		for _, data := range result.Data {
+			record := make(map[string]any, len(result.Columns))
+			for i, column := range result.Columns {
+				if i < len(data.Row) {
+					record[column] = data.Row[i]
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// Validate checks the query by explaining it, which plans the query without
+// running it.
+func (g *Graph) Validate(ctx context.Context, query string) error {
+	_, err := g.Query(ctx, "EXPLAIN "+query, nil)
+	return err
+}
+
+// Schema returns the schema of the graph, loaded on the first call and then
+// cached until RefreshSchema is called.
+func (g *Graph) Schema(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.schema != "" {
+		return g.schema, nil
+	}
+	schema, err := g.loadSchema(ctx)
+	if err != nil {
+		return "", err
+	}
+	g.schema = schema
+	return schema, nil
+}
+
+// RefreshSchema reloads the schema of the graph, after the graph changed.
+func (g *Graph) RefreshSchema(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	schema, err := g.loadSchema(ctx)
+	if err != nil {
+		return err
+	}
+	g.schema = schema
+	return nil
+}
+
+// loadSchema describes the properties of the nodes by their labels, the
+// properties of the relationships by their types and the relationships
+// between the labels of the nodes.
+func (g *Graph) loadSchema(ctx context.Context) (string, error) {
+	nodes, err := g.Query(ctx, _nodePropertiesQuery, nil)
+	if err != nil {
+		return "", err
+	}
+	relationshipProperties, err := g.Query(ctx, _relationshipPropertiesQuery, nil)
+	if err != nil {
+		return "", err
+	}
+	relationships, err := g.Query(ctx, _relationshipsQuery, nil)
+	if err != nil {
+		return "", err
+	}
+
+	nodeProperties := make(map[string][]string)
+	for _, record := range nodes {
+		addProperty(nodeProperties, strings.Join(toStrings(record["nodeLabels"]), ":"), record)
+	}
+	relProperties := make(map[string][]string)
+	for _, record := range relationshipProperties {
+		// The types are formatted as :`TYPE`.
+		relType, _ := record["relType"].(string)
+		addProperty(relProperties, strings.Trim(strings.TrimPrefix(relType, ":"), "`"), record)
+	}
+
+	var b strings.Builder
+	b.WriteString("Node properties:\n")
+	writeProperties(&b, nodeProperties)
+	b.WriteString("Relationship properties:\n")
+	writeProperties(&b, relProperties)
+	b.WriteString("The relationships:\n")
+	lines := make([]string, 0, len(relationships))
+	for _, record := range relationships {
+		relType, _ := record["type"].(string)
+		lines = append(lines, fmt.Sprintf("(:%s)-[:%s]->(:%s)",
+			strings.Join(toStrings(record["start"]), ":"), relType, strings.Join(toStrings(record["end"]), ":")))
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// addProperty adds the property of the record, if any, to the properties of
+// the name, e.g. "name: String".
+func addProperty(properties map[string][]string, name string, record map[string]any) {
+	if name == "" {
+		return
+	}
+	if _, ok := properties[name]; !ok {
+		properties[name] = []string{}
+	}
+	property, _ := record["propertyName"].(string)
+	if property == "" {
+		return
+	}
+	properties[name] = append(properties[name],
+		fmt.Sprintf("%s: %s", property, strings.Join(toStrings(record["propertyTypes"]), " | ")))
+}
+
+func writeProperties(b *strings.Builder, properties map[string][]string) {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(b, "%s {%s}\n", name, strings.Join(properties[name], ", "))
+	}
+}
+
+// toStrings returns the strings of a list decoded from JSON.
+func toStrings(v any) []string {
+	values, _ := v.([]any)
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
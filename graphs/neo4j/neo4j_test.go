@@ -0,0 +1,124 @@
+package neo4j
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/graphs"
+)
+
+// newTestServer returns a server responding to the statements with the
+// responses of their prefixes.
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "neo4j" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/db/movies/tx/commit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var payload struct {
+			Statements []statement `json:"statements"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// The response of the longest prefix is written.
+		var match string
+		for prefix := range responses {
+			if strings.HasPrefix(payload.Statements[0].Statement, prefix) && len(prefix) > len(match) {
+				match = prefix
+			}
+		}
+		if match != "" {
+			_, _ = w.Write([]byte(responses[match]))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [], "errors": [{"code": "Neo.TransientError.General.DatabaseUnavailable", "message": "unavailable"}]}`)) //nolint:lll
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGraphQuery(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, map[string]string{
+		"MATCH":         `{"results": [{"columns": ["name", "born"], "data": [{"row": ["Keanu Reeves", 1964]}, {"row": ["Carrie-Anne Moss", 1967]}]}], "errors": []}`, //nolint:lll
+		"EXPLAIN MATCH": `{"results": [{"columns": [], "data": []}], "errors": []}`,
+		"EXPLAIN":       `{"results": [], "errors": [{"code": "Neo.ClientError.Statement.SyntaxError", "message": "Invalid input"}]}`,
+	})
+	graph, err := New(WithURL(server.URL), WithAuth("neo4j", "secret"), WithDatabase("movies"))
+	require.NoError(t, err)
+
+	records, err := graph.Query(context.Background(), "MATCH (p:Person) RETURN p.name AS name, p.born AS born", nil)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"name": "Keanu Reeves", "born": float64(1964)},
+		{"name": "Carrie-Anne Moss", "born": float64(1967)},
+	}, records)
+
+	require.NoError(t, graph.Validate(context.Background(), "MATCH (p:Person) RETURN p"))
+	require.ErrorIs(t, graph.Validate(context.Background(), "RETURN p.name FROM Person"), graphs.ErrInvalidQuery)
+
+	_, err = graph.Query(context.Background(), "RETURN 1", nil)
+	require.ErrorIs(t, err, ErrQueryFailed)
+
+	graph, err = New(WithURL(server.URL), WithAuth("neo4j", "wrong"), WithDatabase("movies"))
+	require.NoError(t, err)
+	_, err = graph.Query(context.Background(), "RETURN 1", nil)
+	require.ErrorIs(t, err, ErrQueryFailed)
+}
+
+func TestGraphSchema(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, map[string]string{
+		"CALL db.schema.nodeTypeProperties": `{"results": [{"columns": ["nodeLabels", "propertyName", "propertyTypes"], "data": [
+			{"row": [["Person"], "name", ["String"]]},
+			{"row": [["Person"], "born", ["Long"]]},
+			{"row": [["Movie"], "title", ["String"]]},
+			{"row": [["Genre"], null, null]}
+		]}], "errors": []}`,
+		"CALL db.schema.relTypeProperties": `{"results": [{"columns": ["relType", "propertyName", "propertyTypes"], "data": [
+			{"row": [":` + "`ACTED_IN`" + `", "roles", ["StringArray"]]},
+			{"row": [":` + "`IN_GENRE`" + `", null, null]}
+		]}], "errors": []}`,
+		"MATCH": `{"results": [{"columns": ["start", "type", "end"], "data": [
+			{"row": [["Person"], "ACTED_IN", ["Movie"]]},
+			{"row": [["Movie"], "IN_GENRE", ["Genre"]]}
+		]}], "errors": []}`,
+	})
+	graph, err := New(WithURL(server.URL), WithAuth("neo4j", "secret"), WithDatabase("movies"))
+	require.NoError(t, err)
+
+	schema, err := graph.Schema(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, `Node properties:
+Genre {}
+Movie {title: String}
+Person {name: String, born: Long}
+Relationship properties:
+ACTED_IN {roles: StringArray}
+IN_GENRE {}
+The relationships:
+(:Movie)-[:IN_GENRE]->(:Genre)
+(:Person)-[:ACTED_IN]->(:Movie)`, schema)
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(WithURL("bolt://localhost:7687"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
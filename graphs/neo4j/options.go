@@ -0,0 +1,87 @@
+package neo4j
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+const (
+	_urlEnvVarName      = "NEO4J_URL"
+	_usernameEnvVarName = "NEO4J_USERNAME"
+	_passwordEnvVarName = "NEO4J_PASSWORD"
+	_databaseEnvVarName = "NEO4J_DATABASE"
+	_defaultURL         = "http://localhost:7474"
+	_defaultDatabase    = "neo4j"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the client.
+type Option func(g *Graph)
+
+// WithURL is an option for setting the URL of the HTTP API of the server, by
+// default the NEO4J_URL environment variable or http://localhost:7474.
+func WithURL(u string) Option {
+	return func(g *Graph) {
+		g.url = u
+	}
+}
+
+// WithAuth is an option for setting the credentials of the basic
+// authentication, by default the NEO4J_USERNAME and NEO4J_PASSWORD
+// environment variables.
+func WithAuth(username, password string) Option {
+	return func(g *Graph) {
+		g.username = username
+		g.password = password
+	}
+}
+
+// WithDatabase is an option for setting the database queried, by default the
+// NEO4J_DATABASE environment variable or neo4j.
+func WithDatabase(database string) Option {
+	return func(g *Graph) {
+		g.database = database
+	}
+}
+
+// WithHTTPClient is an option for setting the HTTP client of the requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(g *Graph) {
+		g.httpClient = client
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Graph, error) {
+	g := &Graph{
+		url:        os.Getenv(_urlEnvVarName),
+		username:   os.Getenv(_usernameEnvVarName),
+		password:   os.Getenv(_passwordEnvVarName),
+		database:   os.Getenv(_databaseEnvVarName),
+		httpClient: http.DefaultClient,
+	}
+	if g.url == "" {
+		g.url = _defaultURL
+	}
+	if g.database == "" {
+		g.database = _defaultDatabase
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	u, err := url.Parse(g.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: the URL %q is not the URL of an HTTP API", ErrInvalidOptions, g.url)
+	}
+	if g.database == "" {
+		return nil, fmt.Errorf("%w: missing database", ErrInvalidOptions)
+	}
+
+	return g, nil
+}
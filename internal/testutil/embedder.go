@@ -0,0 +1,37 @@
+// Package testutil contains helpers shared by the tests of the packages.
+package testutil
+
+import (
+	"context"
+	"strings"
+)
+
+// WordCountEmbedder embeds the texts as vectors of the counts of the words
+// "cat", "dog" and "car".
+type WordCountEmbedder struct{}
+
+func (WordCountEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		vector, _ := WordCountEmbedder{}.EmbedQuery(ctx, text)
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+func (WordCountEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	vector := make([]float64, 3)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		switch strings.Trim(word, ".,!?") {
+		case "cat", "cats":
+			vector[0]++
+		case "dog", "dogs":
+			vector[1]++
+		case "car", "cars":
+			vector[2]++
+		}
+	}
+	// The vectors are never zero, for the cosine similarity.
+	vector[0] += 0.1
+	return vector, nil
+}
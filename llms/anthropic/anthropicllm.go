@@ -47,7 +47,18 @@ func newClient(opts ...Option) (*anthropicclient.Client, error) {
 		return nil, ErrMissingToken
 	}
 
-	return anthropicclient.New(options.token, options.model)
+	clientOpts := make([]anthropicclient.Option, 0)
+	if options.baseURL != "" {
+		clientOpts = append(clientOpts, anthropicclient.WithBaseURL(options.baseURL))
+	}
+	if options.httpClient != nil {
+		clientOpts = append(clientOpts, anthropicclient.WithHTTPClient(options.httpClient))
+	}
+	if options.rateLimiter != nil {
+		clientOpts = append(clientOpts, anthropicclient.WithRateLimiter(options.rateLimiter))
+	}
+
+	return anthropicclient.New(options.token, options.model, clientOpts...)
 }
 
 // Call requests a completion for the given prompt.
@@ -62,6 +73,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return r[0].Text, nil
 }
 
+// Generate sends each prompt as a user message to the Messages API.
 func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
 	opts := llms.CallOptions{}
 	for _, opt := range options {
@@ -70,21 +82,13 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 
 	generations := make([]*llms.Generation, 0, len(prompts))
 	for _, prompt := range prompts {
-		result, err := o.client.CreateCompletion(ctx, &anthropicclient.CompletionRequest{
-			Model:         opts.Model,
-			Prompt:        prompt,
-			MaxTokens:     opts.MaxTokens,
-			StopWords:     opts.StopWords,
-			Temperature:   opts.Temperature,
-			TopP:          opts.TopP,
-			StreamingFunc: opts.StreamingFunc,
-		})
+		generation, err := generateMessage(ctx, o.client, []schema.ChatMessage{
+			schema.HumanChatMessage{Content: prompt},
+		}, opts)
 		if err != nil {
 			return nil, err
 		}
-		generations = append(generations, &llms.Generation{
-			Text: result.Text,
-		})
+		generations = append(generations, generation)
 	}
 
 	return generations, nil
@@ -0,0 +1,526 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic/internal/anthropicclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _jsonResponseToolName = "json_response"
+
+// Chat is an Anthropic chat model using the Messages API, supporting images,
+// tool calls and JSON responses.
+type Chat struct {
+	client *anthropicclient.Client
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat returns a new Anthropic chat LLM using the Messages API.
+func NewChat(opts ...Option) (*Chat, error) {
+	c, err := newClient(opts...)
+	return &Chat{
+		client: c,
+	}, err
+}
+
+// Image is an image sent to a vision capable model.
+type Image struct {
+	// MediaType is one of "image/jpeg", "image/png", "image/gif" or "image/webp".
+	MediaType string
+	// Data is the raw image data.
+	Data []byte
+}
+
+// ImageChatMessage is a message sent by a human containing images in addition
+// to the text content.
+type ImageChatMessage struct {
+	Content string
+	Images  []Image
+}
+
+var _ schema.ChatMessage = ImageChatMessage{}
+
+func (m ImageChatMessage) GetType() schema.ChatMessageType { return schema.ChatMessageTypeHuman }
+func (m ImageChatMessage) GetContent() string              { return m.Content }
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// Generate requests a chat response for each of the message sets. System
+// messages are sent as the system prompt and function definitions as tools.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		generation, err := generateMessage(ctx, o.client, messageSet, opts)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, generation)
+	}
+
+	return generations, nil
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
+
+func (o *Chat) GetNumTokens(text string) int {
+	return llms.CountTokens(o.client.Model, text)
+}
+
+// CountTokens returns the number of input tokens of the messages with the
+// Anthropic token counting endpoint, including the tools of the options.
+func (o *Chat) CountTokens(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (int, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	req, _, err := messageRequest(messages, opts)
+	if err != nil {
+		return 0, err
+	}
+	return o.client.CountTokens(ctx, req)
+}
+
+func generateMessage(ctx context.Context, client *anthropicclient.Client, messages []schema.ChatMessage, opts llms.CallOptions) (*llms.Generation, error) { // nolint:lll
+	req, formatTool, err := messageRequest(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Content) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	msg := &schema.AIChatMessage{
+		Content: result.Text(),
+	}
+	generationInfo := map[string]any{
+		"InputTokens":  result.Usage.InputTokens,
+		"OutputTokens": result.Usage.OutputTokens,
+		"StopReason":   result.StopReason,
+	}
+	if thinking := result.Thinking(); thinking != "" {
+		generationInfo[llms.ReasoningKey] = thinking
+	}
+	for _, c := range result.Content {
+		if c.Type != anthropicclient.ContentTypeToolUse {
+			continue
+		}
+		if formatTool != nil && c.Name == formatTool.Name {
+			msg.Content = string(c.Input)
+			continue
+		}
+		call := &schema.FunctionCall{
+			Name:      c.Name,
+			Arguments: string(c.Input),
+		}
+		if msg.FunctionCall == nil {
+			msg.FunctionCall = call
+			generationInfo["ToolUseID"] = c.ID
+		}
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:           c.ID,
+			Type:         "function",
+			FunctionCall: call,
+		})
+	}
+
+	// The input tokens of the response exclude the tokens read from and
+	// written to the prompt cache.
+	promptTokens := result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + result.Usage.CacheReadInputTokens
+	return &llms.Generation{
+		Message:        msg,
+		Text:           msg.Content,
+		GenerationInfo: generationInfo,
+		Usage: llms.Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: result.Usage.OutputTokens,
+			TotalTokens:      promptTokens + result.Usage.OutputTokens,
+			CachedTokens:     result.Usage.CacheReadInputTokens,
+			CacheWriteTokens: result.Usage.CacheCreationInputTokens,
+		},
+	}, nil
+}
+
+// samplingLimits are the limits of the Messages API on the sampling options.
+// The API does not support logit biases, and ignores the penalties.
+var samplingLimits = llms.SamplingLimits{} //nolint:gochecknoglobals
+
+// messageRequest returns the request of the messages with the call options,
+// and the tool enforcing the response format if one is set.
+func messageRequest(messages []schema.ChatMessage, opts llms.CallOptions) (*anthropicclient.MessageRequest, *anthropicclient.Tool, error) { // nolint:lll
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, nil, err
+	}
+	system, msgs, err := toAnthropicMessages(messages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &anthropicclient.MessageRequest{
+		Model:         opts.Model,
+		Messages:      msgs,
+		System:        anthropicclient.SystemPrompt(system, nil),
+		MaxTokens:     opts.MaxTokens,
+		Temperature:   opts.Temperature,
+		TopP:          opts.TopP,
+		TopK:          opts.TopK,
+		StopWords:     opts.StopWords,
+		StreamingFunc: opts.StreamingFunc,
+
+		StreamingChunkFunc: opts.StreamingChunkFunc,
+	}
+	if opts.FunctionCallBehavior != llms.FunctionCallBehaviorNone {
+		for _, fn := range opts.Functions {
+			req.Tools = append(req.Tools, anthropicclient.Tool{
+				Name:        fn.Name,
+				Description: fn.Description,
+				InputSchema: fn.Parameters,
+			})
+		}
+		if len(req.Tools) > 0 {
+			req.ToolChoice = toolChoice(opts.FunctionCallBehavior)
+		}
+	}
+	if len(opts.Tools) > 0 && opts.ToolChoice != "none" {
+		for _, tool := range opts.Tools {
+			if tool.Function == nil {
+				continue
+			}
+			req.Tools = append(req.Tools, anthropicclient.Tool{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				InputSchema: tool.Function.Parameters,
+			})
+		}
+		req.ToolChoice = toolChoiceFromOptions(opts.ToolChoice)
+	}
+	// The Messages API has no JSON mode, the response format is enforced by
+	// forcing a tool whose input schema is the requested schema.
+	formatTool := responseFormatTool(opts.ResponseFormat)
+	if formatTool != nil {
+		req.Tools = append(req.Tools, *formatTool)
+		req.ToolChoice = &anthropicclient.ToolChoice{Type: "tool", Name: formatTool.Name}
+	}
+	if opts.PromptCaching != nil {
+		if err := markCachedPrefix(req, system, messages, opts.PromptCaching); err != nil {
+			return nil, nil, err
+		}
+	}
+	setThinking(req, opts)
+	return req, formatTool, nil
+}
+
+// defaultThinkingResponseTokens are the max tokens of the response after the
+// thinking, without max tokens.
+const defaultThinkingResponseTokens = 1024
+
+// thinkingBudgets are the thinking budgets of the reasoning efforts.
+var thinkingBudgets = map[llms.ReasoningEffort]int{ //nolint:gochecknoglobals
+	llms.ReasoningEffortLow:    1024,
+	llms.ReasoningEffortMedium: 8192,
+	llms.ReasoningEffortHigh:   24576,
+}
+
+// setThinking enables extended thinking with the thinking budget of the call
+// options, or the budget of the reasoning effort. The max tokens include the
+// thinking tokens, max tokens not above the budget are added to the budget.
+func setThinking(req *anthropicclient.MessageRequest, opts llms.CallOptions) {
+	budget := opts.ThinkingBudget
+	if budget == 0 {
+		budget = thinkingBudgets[opts.ReasoningEffort]
+	}
+	if budget == 0 {
+		return
+	}
+	req.Thinking = &anthropicclient.Thinking{Type: "enabled", BudgetTokens: budget}
+	switch {
+	case req.MaxTokens == 0:
+		req.MaxTokens = budget + defaultThinkingResponseTokens
+	case req.MaxTokens <= budget:
+		req.MaxTokens += budget
+	}
+}
+
+// markCachedPrefix sets cache control breakpoints at the end of the system
+// prompt, or of the tools without a system prompt, and at the end of the
+// cached messages.
+func markCachedPrefix(req *anthropicclient.MessageRequest, system string, messages []schema.ChatMessage, caching *llms.PromptCaching) error { // nolint:lll
+	cacheControl := &anthropicclient.CacheControl{Type: "ephemeral", TTL: caching.TTL}
+	switch {
+	case system != "":
+		req.System = anthropicclient.SystemPrompt(system, cacheControl)
+	case len(req.Tools) > 0:
+		req.Tools[len(req.Tools)-1].CacheControl = cacheControl
+	}
+
+	prefix := messages
+	if caching.Messages > 0 && caching.Messages < len(messages) {
+		prefix = messages[:caching.Messages]
+	}
+	// The conversion of a prefix of the messages is a prefix of the
+	// conversion of all the messages, its last block ends the cached prefix.
+	_, prefixMsgs, err := toAnthropicMessages(prefix)
+	if err != nil {
+		return err
+	}
+	if len(prefixMsgs) == 0 {
+		return nil
+	}
+	last := len(prefixMsgs) - 1
+	if blocks := len(prefixMsgs[last].Content); blocks > 0 {
+		req.Messages[last].Content[blocks-1].CacheControl = cacheControl
+	}
+	return nil
+}
+
+// responseFormatTool returns the tool used to enforce a JSON response
+// format, or nil if the response format does not require JSON.
+func responseFormatTool(format *llms.ResponseFormat) *anthropicclient.Tool {
+	if !format.IsJSON() {
+		return nil
+	}
+	tool := &anthropicclient.Tool{
+		Name:        _jsonResponseToolName,
+		Description: "Respond to the user with a JSON object.",
+		InputSchema: map[string]any{"type": "object"},
+	}
+	if format.JSONSchema != nil {
+		if format.JSONSchema.Name != "" {
+			tool.Name = format.JSONSchema.Name
+		}
+		if format.JSONSchema.Description != "" {
+			tool.Description = format.JSONSchema.Description
+		}
+		if format.JSONSchema.Schema != nil {
+			tool.InputSchema = format.JSONSchema.Schema
+		}
+	}
+	return tool
+}
+
+// toolChoice converts the function call behavior to a tool choice. A
+// behavior of the form `{"name": "my_function"}` forces the use of that tool.
+func toolChoice(behavior llms.FunctionCallBehavior) *anthropicclient.ToolChoice {
+	switch behavior {
+	case "", llms.FunctionCallBehaviorAuto:
+		return &anthropicclient.ToolChoice{Type: "auto"}
+	case llms.FunctionCallBehaviorNone:
+		return nil
+	}
+
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(behavior), &named); err == nil && named.Name != "" {
+		return &anthropicclient.ToolChoice{Type: "tool", Name: named.Name}
+	}
+	return &anthropicclient.ToolChoice{Type: "tool", Name: string(behavior)}
+}
+
+// toolChoiceFromOptions converts the tool choice of the call options.
+func toolChoiceFromOptions(choice any) *anthropicclient.ToolChoice {
+	switch c := choice.(type) {
+	case string:
+		if c == "required" {
+			return &anthropicclient.ToolChoice{Type: "any"}
+		}
+	case llms.ToolChoice:
+		if c.Function != nil {
+			return &anthropicclient.ToolChoice{Type: "tool", Name: c.Function.Name}
+		}
+	case *llms.ToolChoice:
+		if c != nil && c.Function != nil {
+			return &anthropicclient.ToolChoice{Type: "tool", Name: c.Function.Name}
+		}
+	}
+	return &anthropicclient.ToolChoice{Type: "auto"}
+}
+
+// toAnthropicMessages converts the messages to the format of the Messages
+// API. System messages are joined into the system prompt and consecutive
+// messages of the same role are merged, as the roles must alternate. Tool
+// and function calls and their results become tool use and tool result blocks.
+func toAnthropicMessages(messages []schema.ChatMessage) (string, []anthropicclient.ChatMessage, error) { // nolint:cyclop
+	system := make([]string, 0)
+	msgs := make([]anthropicclient.ChatMessage, 0, len(messages))
+	toolUseID := ""
+	toolUses := 0
+
+	add := func(role string, content ...anthropicclient.Content) {
+		if len(msgs) > 0 && msgs[len(msgs)-1].Role == role {
+			msgs[len(msgs)-1].Content = append(msgs[len(msgs)-1].Content, content...)
+			return
+		}
+		msgs = append(msgs, anthropicclient.ChatMessage{Role: role, Content: content})
+	}
+
+	for _, m := range messages {
+		switch m := m.(type) {
+		case schema.SystemChatMessage:
+			system = append(system, m.Content)
+		case schema.AIChatMessage:
+			content := make([]anthropicclient.Content, 0, 2) //nolint:gomnd
+			if m.Content != "" {
+				content = append(content, textContent(m.Content))
+			}
+			for _, tc := range m.ToolCalls {
+				if tc.FunctionCall == nil {
+					continue
+				}
+				input, err := toolInput(tc.FunctionCall.Arguments)
+				if err != nil {
+					return "", nil, err
+				}
+				content = append(content, anthropicclient.Content{
+					Type:  anthropicclient.ContentTypeToolUse,
+					ID:    tc.ID,
+					Name:  tc.FunctionCall.Name,
+					Input: input,
+				})
+			}
+			if m.FunctionCall != nil && len(m.ToolCalls) == 0 {
+				input, err := toolInput(m.FunctionCall.Arguments)
+				if err != nil {
+					return "", nil, err
+				}
+				toolUses++
+				toolUseID = fmt.Sprintf("toolu_%d", toolUses)
+				content = append(content, anthropicclient.Content{
+					Type:  anthropicclient.ContentTypeToolUse,
+					ID:    toolUseID,
+					Name:  m.FunctionCall.Name,
+					Input: input,
+				})
+			}
+			add("assistant", content...)
+		case schema.FunctionChatMessage:
+			if toolUseID == "" {
+				add("user", textContent(fmt.Sprintf("%s: %s", m.Name, m.Content)))
+				continue
+			}
+			add("user", anthropicclient.Content{
+				Type:      anthropicclient.ContentTypeToolResult,
+				ToolUseID: toolUseID,
+				Content:   m.Content,
+			})
+			toolUseID = ""
+		case schema.ToolChatMessage:
+			add("user", anthropicclient.Content{
+				Type:      anthropicclient.ContentTypeToolResult,
+				ToolUseID: m.ID,
+				Content:   m.Content,
+			})
+		case ImageChatMessage:
+			content := make([]anthropicclient.Content, 0, len(m.Images)+1)
+			for _, image := range m.Images {
+				content = append(content, anthropicclient.Content{
+					Type:   anthropicclient.ContentTypeImage,
+					Source: base64Source(image.MediaType, image.Data),
+				})
+			}
+			if m.Content != "" {
+				content = append(content, textContent(m.Content))
+			}
+			add("user", content...)
+		case schema.HumanChatMessage:
+			add("user", humanContent(m)...)
+		default:
+			if m.GetType() == schema.ChatMessageTypeAI {
+				add("assistant", textContent(m.GetContent()))
+				continue
+			}
+			add("user", textContent(m.GetContent()))
+		}
+	}
+
+	return strings.Join(system, "\n\n"), msgs, nil
+}
+
+// humanContent returns the content blocks of a human message and its multimodal parts.
+// Binary parts become image blocks and file parts become document blocks.
+func humanContent(m schema.HumanChatMessage) []anthropicclient.Content {
+	content := make([]anthropicclient.Content, 0, len(m.Parts)+1)
+	if m.Content != "" || len(m.Parts) == 0 {
+		content = append(content, textContent(m.Content))
+	}
+	for _, part := range m.Parts {
+		switch p := part.(type) {
+		case schema.TextPart:
+			content = append(content, textContent(p.Text))
+		case schema.ImageURLPart:
+			content = append(content, anthropicclient.Content{
+				Type:   anthropicclient.ContentTypeImage,
+				Source: &anthropicclient.ImageSource{Type: "url", URL: p.URL},
+			})
+		case schema.BinaryPart:
+			content = append(content, anthropicclient.Content{
+				Type:   anthropicclient.ContentTypeImage,
+				Source: base64Source(p.MIMEType, p.Data),
+			})
+		case schema.FilePart:
+			content = append(content, anthropicclient.Content{
+				Type:   anthropicclient.ContentTypeDocument,
+				Source: base64Source(p.MIMEType, p.Data),
+			})
+		}
+	}
+	return content
+}
+
+func base64Source(mediaType string, data []byte) *anthropicclient.ImageSource {
+	return &anthropicclient.ImageSource{
+		Type:      "base64",
+		MediaType: mediaType,
+		Data:      base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+func textContent(text string) anthropicclient.Content {
+	return anthropicclient.Content{Type: anthropicclient.ContentTypeText, Text: text}
+}
+
+// toolInput returns the arguments of a function call as a json object.
+func toolInput(arguments any) (json.RawMessage, error) {
+	switch arguments := arguments.(type) {
+	case nil:
+		return json.RawMessage("{}"), nil
+	case string:
+		if json.Valid([]byte(arguments)) {
+			return json.RawMessage(arguments), nil
+		}
+		return json.Marshal(map[string]string{"input": arguments})
+	default:
+		return json.Marshal(arguments)
+	}
+}
@@ -0,0 +1,447 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatGenerate(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("x-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"role": "assistant",
+			"content": [
+				{"type": "text", "text": "Let me check."},
+				{"type": "tool_use", "id": "toolu_abc", "name": "weather", "input": {"city": "Paris"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "Weather in Paris?"},
+		schema.AIChatMessage{FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+		schema.FunctionChatMessage{Name: "weather", Content: "sunny"},
+		ImageChatMessage{Content: "And here?", Images: []Image{{MediaType: "image/png", Data: []byte("png")}}},
+	}, llms.WithFunctions([]llms.FunctionDefinition{{
+		Name:       "weather",
+		Parameters: map[string]any{"type": "object"},
+	}}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Let me check.", msg.Content)
+	require.NotNil(t, msg.FunctionCall)
+	assert.Equal(t, "weather", msg.FunctionCall.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, msg.FunctionCall.Arguments.(string))
+
+	assert.Equal(t, "Be brief.", got["system"])
+	assert.Equal(t, map[string]any{"type": "auto"}, got["tool_choice"])
+	messages, ok := got["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 3)
+	user, ok := messages[2].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "user", user["role"])
+	content, ok := user["content"].([]any)
+	require.True(t, ok)
+	require.Len(t, content, 3)
+	assert.Equal(t, "tool_result", content[0].(map[string]any)["type"])
+	assert.Equal(t, "toolu_1", content[0].(map[string]any)["tool_use_id"])
+	assert.Equal(t, "image", content[1].(map[string]any)["type"])
+	assert.Equal(t, "text", content[2].(map[string]any)["type"])
+}
+
+func TestChatStreaming(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_1","role":"assistant","content":[],"usage":{"input_tokens":3,"cache_read_input_tokens":4}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" world"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, e := range events {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", e)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []string
+	generations, err := llm.Generate(context.Background(), []string{"Hi"},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, []string{"Hello", " world"}, chunks)
+	assert.Equal(t, 3, generations[0].GenerationInfo["InputTokens"])
+	assert.Equal(t, 2, generations[0].GenerationInfo["OutputTokens"])
+	assert.Equal(t, llms.Usage{PromptTokens: 7, CompletionTokens: 2, TotalTokens: 9, CachedTokens: 4}, generations[0].Usage)
+	assert.Equal(t, "end_turn", generations[0].GenerationInfo["StopReason"])
+}
+
+func TestChatStreamingOverloaded(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_1","role":"assistant","content":[]}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}`,
+		}
+		if r.Header.Get("X-Case") != "truncated" {
+			events = append(events, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+		}
+		for _, e := range events {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", e)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	streaming := llms.WithStreamingFunc(func(context.Context, []byte) error { return nil })
+
+	_, err = llm.Generate(context.Background(), []string{"Hi"}, streaming)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "overloaded_error", statusErr.Code)
+	assert.True(t, llms.IsRetryableError(err))
+
+	ctx := llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"truncated"}})
+	_, err = llm.Generate(ctx, []string{"Hi"}, streaming)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+}
+
+func TestChatParallelToolUse(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"role": "assistant",
+			"content": [
+				{"type": "tool_use", "id": "toolu_a", "name": "weather", "input": {"city": "Paris"}},
+				{"type": "tool_use", "id": "toolu_b", "name": "weather", "input": {"city": "Rome"}}
+			],
+			"stop_reason": "tool_use"
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	tool, err := llms.NewFunctionTool("weather", "Get the weather", struct {
+		City string `json:"city"`
+	}{})
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Weather in Oslo, Paris and Rome?"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+			ID:           "toolu_0",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Oslo"}`},
+		}}},
+		schema.ToolChatMessage{ID: "toolu_0", Content: "rain"},
+	}, llms.WithTools([]llms.Tool{tool}), llms.WithToolChoice("required"))
+	require.NoError(t, err)
+
+	require.Len(t, msg.ToolCalls, 2)
+	assert.Equal(t, "toolu_a", msg.ToolCalls[0].ID)
+	assert.Equal(t, "toolu_b", msg.ToolCalls[1].ID)
+	assert.JSONEq(t, `{"city":"Rome"}`, msg.ToolCalls[1].FunctionCall.Arguments.(string))
+
+	assert.Equal(t, map[string]any{"type": "any"}, got["tool_choice"])
+	messages, ok := got["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 3)
+	b, err := json.Marshal(messages[1:])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"role": "assistant", "content": [{"type": "tool_use", "id": "toolu_0", "name": "weather", "input": {"city": "Oslo"}}]},
+		{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "toolu_0", "content": "rain"}]}
+	]`, string(b))
+}
+
+func TestChatResponseFormat(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"role": "assistant",
+			"content": [{"type": "tool_use", "id": "toolu_abc", "name": "city", "input": {"name": "Paris"}}],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Capital of France?"},
+	}, llms.WithResponseFormat(llms.JSONSchemaFormat(llms.JSONSchema{
+		Name:   "city",
+		Schema: map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}},
+	})))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"name":"Paris"}`, msg.Content)
+	assert.Empty(t, msg.ToolCalls)
+	assert.Nil(t, msg.FunctionCall)
+	assert.Equal(t, map[string]any{"type": "tool", "name": "city"}, got["tool_choice"])
+	require.Len(t, got["tools"], 1)
+}
+
+func TestToAnthropicMessagesContentParts(t *testing.T) {
+	t.Parallel()
+
+	_, msgs, err := toAnthropicMessages([]schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Compare", Parts: []schema.ContentPart{
+			schema.ImageURLPart{URL: "https://example.com/cat.png"},
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")},
+			schema.FilePart{Filename: "doc.pdf", MIMEType: "application/pdf", Data: []byte("pdf")},
+		}},
+	})
+	require.NoError(t, err)
+	b, err := json.Marshal(msgs)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[{"role":"user","content":[
+		{"type":"text","text":"Compare"},
+		{"type":"image","source":{"type":"url","url":"https://example.com/cat.png"}},
+		{"type":"image","source":{"type":"base64","media_type":"image/png","data":"cG5n"}},
+		{"type":"document","source":{"type":"base64","media_type":"application/pdf","data":"cGRm"}}
+	]}]`, string(b))
+}
+
+func TestChatStreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_1","role":"assistant","content":[],"usage":{"input_tokens":3}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Check the weather."}}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Checking."}}`,
+			`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"weather","input":{}}}`,
+			`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+			`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":9}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, e := range events {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", e)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Weather in Paris?"},
+	}, llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []llms.StreamChunk{
+		{Reasoning: "Check the weather."},
+		{Content: "Checking."},
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, ID: "toolu_1", Name: "weather"}}},
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, Arguments: `{"city":`}}},
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, Arguments: `"Paris"}`}}},
+		{FinishReason: "tool_use"},
+	}, chunks)
+	assert.Equal(t, "Checking.", msg.Content)
+	require.Len(t, msg.ToolCalls, 1)
+	assert.JSONEq(t, `{"city":"Paris"}`, msg.ToolCalls[0].FunctionCall.Arguments.(string))
+}
+
+func TestChatCountTokens(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages/count_tokens", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"input_tokens":14}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	tokens, err := llms.CountChatTokens(context.Background(), chat, []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "Hi"},
+	}, llms.WithMaxTokens(100))
+	require.NoError(t, err)
+	assert.Equal(t, 14, tokens)
+
+	assert.Equal(t, "Be brief.", got["system"])
+	assert.NotEmpty(t, got["model"])
+	assert.NotContains(t, got, "max_tokens")
+}
+
+func TestChatPromptCaching(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"role": "assistant",
+			"content": [{"type": "text", "text": "Sure."}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 10, "output_tokens": 2,
+				"cache_creation_input_tokens": 2048, "cache_read_input_tokens": 0}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.SystemChatMessage{Content: "You answer questions about the manual."},
+		schema.HumanChatMessage{Content: "The manual."},
+		schema.AIChatMessage{Content: "Read it."},
+		schema.HumanChatMessage{Content: "How do I reset it?"},
+	}}, llms.WithPromptCaching(llms.PromptCaching{Messages: 3, TTL: "1h"}))
+	require.NoError(t, err)
+
+	cacheControl := map[string]any{"type": "ephemeral", "ttl": "1h"}
+	assert.Equal(t, []any{map[string]any{
+		"type":          "text",
+		"text":          "You answer questions about the manual.",
+		"cache_control": cacheControl,
+	}}, got["system"])
+	messages, ok := got["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 3)
+	for i, message := range messages {
+		content, ok := message.(map[string]any)["content"].([]any)
+		require.True(t, ok)
+		last := content[len(content)-1].(map[string]any) //nolint:forcetypeassert
+		if i == 1 {
+			assert.Equal(t, cacheControl, last["cache_control"])
+		} else {
+			assert.NotContains(t, last, "cache_control")
+		}
+	}
+	assert.Equal(t, 2048, generations[0].Usage.CacheWriteTokens)
+}
+
+func TestChatPromptCachingTools(t *testing.T) {
+	t.Parallel()
+
+	req, _, err := messageRequest([]schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Weather in Paris?"},
+	}, llms.CallOptions{
+		Functions:     []llms.FunctionDefinition{{Name: "weather", Parameters: map[string]any{"type": "object"}}},
+		PromptCaching: &llms.PromptCaching{},
+	})
+	require.NoError(t, err)
+
+	assert.Nil(t, req.System)
+	require.Len(t, req.Tools, 1)
+	require.NotNil(t, req.Tools[0].CacheControl)
+	assert.Equal(t, "ephemeral", req.Tools[0].CacheControl.Type)
+	require.NotNil(t, req.Messages[0].Content[0].CacheControl)
+}
+
+func TestChatThinking(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"role": "assistant",
+			"content": [
+				{"type": "thinking", "thinking": "17 is prime.", "signature": "sig"},
+				{"type": "text", "text": "Yes."}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 10, "output_tokens": 50}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	messages := [][]schema.ChatMessage{{schema.HumanChatMessage{Content: "Is 17 prime?"}}}
+
+	generations, err := chat.Generate(context.Background(), messages, llms.WithThinkingBudget(2048))
+	require.NoError(t, err)
+	assert.Equal(t, "Yes.", generations[0].Text)
+	assert.Equal(t, "17 is prime.", generations[0].Reasoning())
+	assert.Equal(t, map[string]any{"type": "enabled", "budget_tokens": float64(2048)}, got["thinking"])
+	assert.Equal(t, float64(2048+1024), got["max_tokens"])
+
+	// The max tokens of the response are added to the budget of the effort.
+	_, err = chat.Generate(context.Background(), messages,
+		llms.WithReasoningEffort(llms.ReasoningEffortMedium), llms.WithMaxTokens(500))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "enabled", "budget_tokens": float64(8192)}, got["thinking"])
+	assert.Equal(t, float64(8192+500), got["max_tokens"])
+
+	_, err = chat.Generate(context.Background(), messages)
+	require.NoError(t, err)
+	assert.NotContains(t, got, "thinking")
+}
@@ -1,12 +1,20 @@
 package anthropic
 
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic/internal/anthropicclient"
+)
+
 const (
 	tokenEnvVarName = "ANTHROPIC_API_KEY" //nolint:gosec
 )
 
 type options struct {
-	token string
-	model string
+	token       string
+	model       string
+	baseURL     string
+	httpClient  anthropicclient.Doer
+	rateLimiter *llms.RateLimiter
 }
 
 type Option func(*options)
@@ -25,3 +33,26 @@ func WithModel(model string) Option {
 		opts.model = model
 	}
 }
+
+// WithBaseURL passes the base url of the Anthropic API to the client.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client anthropicclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request, e.g.
+// llms.NewRateLimiter(50, 40000) for 50 requests and 40k tokens per minute.
+// The limiter may be shared by several clients using the same quota.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
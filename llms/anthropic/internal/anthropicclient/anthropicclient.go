@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -20,6 +23,9 @@ type Client struct {
 	baseURL string
 
 	httpClient Doer
+
+	// rateLimiter limits the requests of the client.
+	rateLimiter *llms.RateLimiter
 }
 
 // Option is an option for the Anthropic client.
@@ -39,6 +45,24 @@ func WithHTTPClient(client Doer) Option {
 	}
 }
 
+// WithBaseURL allows setting the base url of the Anthropic API.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+
+		return nil
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(c *Client) error {
+		c.rateLimiter = limiter
+
+		return nil
+	}
+}
+
 // New returns a new Anthropic client.
 func New(token string, model string, opts ...Option) (*Client, error) {
 	c := &Client{
@@ -79,6 +103,9 @@ type Completion struct {
 
 // CreateCompletion creates a completion.
 func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*Completion, error) {
+	if err := c.rateLimiter.Wait(ctx, llms.EstimateTokens(r.Prompt)+r.MaxTokens); err != nil {
+		return nil, err
+	}
 	resp, err := c.createCompletion(ctx, &completionPayload{
 		Model:         r.Model,
 		Prompt:        r.Prompt,
@@ -102,4 +129,5 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("x-api-key", c.token)
 	// TODO: expose version as a option/parameter
 	req.Header.Set("anthropic-version", "2023-06-01")
+	llms.SetRequestHeaders(req)
 }
@@ -5,11 +5,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -43,6 +44,10 @@ type errorMessage struct {
 	} `json:"error"`
 }
 
+func (e errorMessage) code() string {
+	return e.Error.Type
+}
+
 func (c *Client) setCompletionDefaults(payload *completionPayload) {
 	// Set defaults
 	if payload.MaxTokens == 0 {
@@ -100,16 +105,14 @@ func (c *Client) createCompletion(ctx context.Context, payload *completionPayloa
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, llms.NewStatusError(r, "", "")
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
 	}
 	if payload.StreamingFunc != nil {
 		// Read chunks
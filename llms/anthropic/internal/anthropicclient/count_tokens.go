@@ -0,0 +1,75 @@
+package anthropicclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// countTokensRequest is a request to the token counting endpoint. It holds
+// the fields of a message request that count towards the input tokens.
+type countTokensRequest struct {
+	Model      string        `json:"model"`
+	Messages   []ChatMessage `json:"messages"`
+	System     any           `json:"system,omitempty"`
+	Tools      []Tool        `json:"tools,omitempty"`
+	ToolChoice *ToolChoice   `json:"tool_choice,omitempty"`
+}
+
+type countTokensResponse struct {
+	InputTokens int `json:"input_tokens"`
+}
+
+// CountTokens returns the number of input tokens of the message request
+// without sending it to the model.
+func (c *Client) CountTokens(ctx context.Context, payload *MessageRequest) (int, error) {
+	c.setMessageDefaults(payload)
+
+	payloadBytes, err := json.Marshal(countTokensRequest{
+		Model:      payload.Model,
+		Messages:   payload.Messages,
+		System:     payload.System,
+		Tools:      payload.Tools,
+		ToolChoice: payload.ToolChoice,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+
+	url := fmt.Sprintf("%s/messages/count_tokens", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return 0, llms.NewStatusError(r, "", "")
+		}
+
+		return 0, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
+	}
+
+	var response countTokensResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("parse response: %w", err)
+	}
+	return response.InputTokens, nil
+}
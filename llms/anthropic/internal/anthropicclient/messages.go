@@ -0,0 +1,446 @@
+package anthropicclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	defaultMessagesModel     = "claude-3-5-sonnet-20240620"
+	defaultMessagesMaxTokens = 1024
+)
+
+// Content block types of the Messages API.
+const (
+	ContentTypeText       = "text"
+	ContentTypeThinking   = "thinking"
+	ContentTypeImage      = "image"
+	ContentTypeDocument   = "document"
+	ContentTypeToolUse    = "tool_use"
+	ContentTypeToolResult = "tool_result"
+)
+
+// MessageRequest is a request to the Messages API.
+type MessageRequest struct {
+	Model       string         `json:"model"`
+	Messages    []ChatMessage  `json:"messages"`
+	System      any            `json:"system,omitempty"`
+	MaxTokens   int            `json:"max_tokens"`
+	Temperature float64        `json:"temperature,omitempty"`
+	TopP        float64        `json:"top_p,omitempty"`
+	TopK        int            `json:"top_k,omitempty"`
+	StopWords   []string       `json:"stop_sequences,omitempty"`
+	Tools       []Tool         `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice    `json:"tool_choice,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Stream      bool           `json:"stream,omitempty"`
+	// Thinking enables extended thinking.
+	Thinking *Thinking `json:"thinking,omitempty"`
+
+	// StreamingFunc is a function to be called for each text chunk of a streaming response.
+	// Return an error to stop streaming early.
+	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each structured chunk
+	// of a streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
+}
+
+// Thinking configures extended thinking.
+type Thinking struct {
+	// Type is "enabled".
+	Type string `json:"type"`
+	// BudgetTokens is the max number of thinking tokens, at least 1024 and
+	// less than the max tokens.
+	BudgetTokens int `json:"budget_tokens"`
+}
+
+// ChatMessage is a message of the conversation sent to the Messages API.
+type ChatMessage struct {
+	// Role is either "user" or "assistant".
+	Role    string    `json:"role"`
+	Content []Content `json:"content"`
+}
+
+// Content is a content block of a message.
+type Content struct {
+	Type string `json:"type"`
+
+	// Text is set for text blocks.
+	Text string `json:"text,omitempty"`
+	// Thinking is set for thinking blocks of extended thinking responses.
+	Thinking string `json:"thinking,omitempty"`
+	// Source is set for image and document blocks.
+	Source *ImageSource `json:"source,omitempty"`
+
+	// ID, Name and Input are set for tool use blocks.
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// ToolUseID, Content and IsError are set for tool result blocks.
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+
+	// CacheControl marks the block as the end of a cached prompt prefix.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// ImageSource is the source of an image or document block.
+type ImageSource struct {
+	// Type is "base64" or "url".
+	Type string `json:"type"`
+	// MediaType is one of "image/jpeg", "image/png", "image/gif" or "image/webp"
+	// for images and "application/pdf" for documents.
+	MediaType string `json:"media_type,omitempty"`
+	// Data is the base64 encoded image or document.
+	Data string `json:"data,omitempty"`
+	// URL is set for url sources.
+	URL string `json:"url,omitempty"`
+}
+
+// Tool is a tool the model may use.
+type Tool struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	InputSchema  any           `json:"input_schema"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// CacheControl marks the end of a prompt prefix to be cached.
+type CacheControl struct {
+	// Type is "ephemeral".
+	Type string `json:"type"`
+	// TTL is the lifetime of the cache entry, "5m" or "1h".
+	TTL string `json:"ttl,omitempty"`
+}
+
+// SystemPrompt returns the system of a request with the text: nil if the text
+// is empty, the text if it has no cache control and a text block otherwise.
+func SystemPrompt(text string, cacheControl *CacheControl) any {
+	switch {
+	case text == "":
+		return nil
+	case cacheControl == nil:
+		return text
+	default:
+		return []Content{{Type: ContentTypeText, Text: text, CacheControl: cacheControl}}
+	}
+}
+
+// ToolChoice controls how the model uses the tools.
+type ToolChoice struct {
+	// Type is one of "auto", "any" or "tool".
+	Type string `json:"type"`
+	// Name is the name of the tool to use when Type is "tool".
+	Name string `json:"name,omitempty"`
+}
+
+// MessageUsage is the token usage of a Messages API request.
+type MessageUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// CacheCreationInputTokens is the number of input tokens written to the prompt cache.
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	// CacheReadInputTokens is the number of input tokens read from the prompt cache.
+	CacheReadInputTokens int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// MessageResponse is a response of the Messages API.
+type MessageResponse struct {
+	ID           string       `json:"id"`
+	Type         string       `json:"type"`
+	Role         string       `json:"role"`
+	Content      []Content    `json:"content"`
+	Model        string       `json:"model"`
+	StopReason   string       `json:"stop_reason"`
+	StopSequence string       `json:"stop_sequence"`
+	Usage        MessageUsage `json:"usage"`
+}
+
+// Text returns the concatenated text blocks of the response.
+func (r *MessageResponse) Text() string {
+	var b strings.Builder
+	for _, c := range r.Content {
+		if c.Type == ContentTypeText {
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String()
+}
+
+// Thinking returns the concatenated thinking blocks of the response.
+func (r *MessageResponse) Thinking() string {
+	var b strings.Builder
+	for _, c := range r.Content {
+		if c.Type == ContentTypeThinking {
+			b.WriteString(c.Thinking)
+		}
+	}
+	return b.String()
+}
+
+type messageStreamEvent struct {
+	Type         string           `json:"type"`
+	Index        int              `json:"index"`
+	Message      *MessageResponse `json:"message"`
+	ContentBlock *Content         `json:"content_block"`
+	Delta        struct {
+		Type         string `json:"type"`
+		Text         string `json:"text"`
+		Thinking     string `json:"thinking"`
+		PartialJSON  string `json:"partial_json"`
+		StopReason   string `json:"stop_reason"`
+		StopSequence string `json:"stop_sequence"`
+	} `json:"delta"`
+	Usage *MessageUsage `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ErrStream is returned when the Messages API sends an error event while
+// streaming, e.g. overloaded_error. The error of the event is a
+// *llms.StatusError.
+var ErrStream = errors.New("error event in stream")
+
+func (c *Client) setMessageDefaults(payload *MessageRequest) {
+	if payload.MaxTokens == 0 {
+		payload.MaxTokens = defaultMessagesMaxTokens
+	}
+
+	if len(payload.StopWords) == 0 {
+		payload.StopWords = nil
+	}
+
+	switch {
+	// Prefer the model specified in the payload.
+	case payload.Model != "":
+
+	// If no model is set in the payload, take the one specified in the client.
+	case c.Model != "":
+		payload.Model = c.Model
+	// Fallback: use the default model
+	default:
+		payload.Model = defaultMessagesModel
+	}
+	if payload.StreamingFunc != nil || payload.StreamingChunkFunc != nil {
+		payload.Stream = true
+	}
+}
+
+// estimateTokens estimates the input tokens and the max tokens of the request
+// for rate limiting.
+func (r *MessageRequest) estimateTokens() int {
+	var texts []string
+	switch system := r.System.(type) {
+	case string:
+		texts = append(texts, system)
+	case []Content:
+		for _, block := range system {
+			texts = append(texts, block.Text)
+		}
+	}
+	for _, m := range r.Messages {
+		for _, block := range m.Content {
+			texts = append(texts, block.Text, string(block.Input), block.Content)
+		}
+	}
+	return llms.EstimateTokens(texts...) + r.MaxTokens
+}
+
+// CreateMessage sends the conversation to the Messages API and returns the
+// response of the model.
+func (c *Client) CreateMessage(ctx context.Context, payload *MessageRequest) (*MessageResponse, error) {
+	c.setMessageDefaults(payload)
+	if err := c.rateLimiter.Wait(ctx, payload.estimateTokens()); err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+
+	url := fmt.Sprintf("%s/messages", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, llms.NewStatusError(r, "", "")
+		}
+
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
+	}
+
+	if payload.Stream {
+		return parseStreamingMessageResponse(ctx, r, payload)
+	}
+
+	var response MessageResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// parseStreamingMessageResponse reads the server sent events of a streaming
+// response and assembles them into a single response. Text deltas are passed
+// to the streaming func as they arrive; text, thinking and tool use deltas
+// are passed to the streaming chunk func. If the stream is interrupted, by the
+// context, the connection or an error event, it returns a *llms.StreamError
+// holding the text streamed so far.
+func parseStreamingMessageResponse(ctx context.Context, r *http.Response, payload *MessageRequest) (*MessageResponse, error) { // nolint:lll,cyclop,gocognit
+	stop := llms.CloseOnCancel(ctx, r.Body)
+	defer stop()
+	response := &MessageResponse{}
+	var partial strings.Builder
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, partial.String(), err)
+	}
+	partialJSON := make(map[int]*strings.Builder)
+	// toolIndexes maps the index of a tool use block to the index of the tool call.
+	toolIndexes := make(map[int]int)
+	sendChunk := func(chunk llms.StreamChunk) error {
+		if payload.StreamingChunkFunc == nil {
+			return nil
+		}
+		if err := payload.StreamingChunkFunc(ctx, chunk); err != nil {
+			return fmt.Errorf("streaming func returned an error: %w", err)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20) //nolint:gomnd
+	stopped := false
+	for !stopped && ctx.Err() == nil && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+		var event messageStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, fmt.Errorf("parse stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				response = event.Message
+			}
+		case "content_block_start":
+			if event.ContentBlock != nil {
+				for len(response.Content) <= event.Index {
+					response.Content = append(response.Content, Content{})
+				}
+				response.Content[event.Index] = *event.ContentBlock
+				if event.ContentBlock.Type == ContentTypeToolUse {
+					toolIndexes[event.Index] = len(toolIndexes)
+					if err := sendChunk(llms.StreamChunk{ToolCalls: []llms.ToolCallChunk{{
+						Index: toolIndexes[event.Index],
+						ID:    event.ContentBlock.ID,
+						Name:  event.ContentBlock.Name,
+					}}}); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case "content_block_delta":
+			if event.Index >= len(response.Content) {
+				continue
+			}
+			switch event.Delta.Type {
+			case "text_delta":
+				response.Content[event.Index].Text += event.Delta.Text
+				partial.WriteString(event.Delta.Text)
+				if payload.StreamingFunc != nil {
+					if err := payload.StreamingFunc(ctx, []byte(event.Delta.Text)); err != nil {
+						return nil, fmt.Errorf("streaming func returned an error: %w", err)
+					}
+				}
+				if err := sendChunk(llms.StreamChunk{Content: event.Delta.Text}); err != nil {
+					return nil, err
+				}
+			case "thinking_delta":
+				response.Content[event.Index].Thinking += event.Delta.Thinking
+				if err := sendChunk(llms.StreamChunk{Reasoning: event.Delta.Thinking}); err != nil {
+					return nil, err
+				}
+			case "input_json_delta":
+				if _, ok := partialJSON[event.Index]; !ok {
+					partialJSON[event.Index] = &strings.Builder{}
+				}
+				partialJSON[event.Index].WriteString(event.Delta.PartialJSON)
+				if err := sendChunk(llms.StreamChunk{ToolCalls: []llms.ToolCallChunk{{
+					Index:     toolIndexes[event.Index],
+					Arguments: event.Delta.PartialJSON,
+				}}}); err != nil {
+					return nil, err
+				}
+			}
+		case "message_delta":
+			response.StopReason = event.Delta.StopReason
+			response.StopSequence = event.Delta.StopSequence
+			if event.Usage != nil {
+				response.Usage.OutputTokens = event.Usage.OutputTokens
+			}
+			if event.Delta.StopReason != "" {
+				if err := sendChunk(llms.StreamChunk{FinishReason: event.Delta.StopReason}); err != nil {
+					return nil, err
+				}
+			}
+		case "message_stop":
+			stopped = true
+		case "error":
+			if event.Error != nil {
+				return nil, interrupted(fmt.Errorf("%w: %w", ErrStream,
+					&llms.StatusError{Code: event.Error.Type, Message: event.Error.Message}))
+			}
+			return nil, interrupted(ErrStream)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, interrupted(fmt.Errorf("read stream: %w", err))
+	}
+	if ctx.Err() != nil || (!stopped && response.StopReason == "") {
+		return nil, interrupted(io.ErrUnexpectedEOF)
+	}
+
+	for index, input := range partialJSON {
+		if input.Len() > 0 {
+			response.Content[index].Input = json.RawMessage(input.String())
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,89 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultBatchConcurrency = 5
+
+// ErrEmptyBatchResult is returned for an input of a batch without generation.
+var ErrEmptyBatchResult = errors.New("no generation for batch input")
+
+// BatchResult is the result of one input of a batch. Either the generation
+// or the error is set.
+type BatchResult struct {
+	Generation *Generation
+	Err        error
+}
+
+// BatchGenerator is implemented by chat models with a batch endpoint, such as
+// the OpenAI Batch API. GenerateBatch uses it when WithNativeBatch is set.
+type BatchGenerator interface {
+	// GenerateBatch generates a response for each of the message sets. The
+	// error is set if the batch as a whole failed.
+	GenerateBatch(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]BatchResult, error)
+}
+
+// GenerateBatch generates a response for each of the message sets, sending
+// up to WithBatchConcurrency requests at once. The failure of an input does
+// not stop the others, its error is reported in its result. The results are
+// in the order of the message sets.
+//
+// With WithNativeBatch the batch endpoint of the model is used if it
+// implements BatchGenerator.
+func GenerateBatch(ctx context.Context, model ChatLLM, messageSets [][]schema.ChatMessage, options ...CallOption) ([]BatchResult, error) { //nolint:lll
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if batcher, ok := model.(BatchGenerator); ok && opts.NativeBatch {
+		return batcher.GenerateBatch(ctx, messageSets, options...)
+	}
+
+	concurrency := opts.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = _defaultBatchConcurrency
+	}
+
+	results := make([]BatchResult, len(messageSets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, messages := range messageSets {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(messageSets); j++ {
+				results[j].Err = ctx.Err()
+			}
+			wg.Wait()
+			return results, nil
+		}
+
+		wg.Add(1)
+		go func(i int, messages []schema.ChatMessage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = generateOne(ctx, model, messages, options)
+		}(i, messages)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func generateOne(ctx context.Context, model ChatLLM, messages []schema.ChatMessage, options []CallOption) BatchResult {
+	generations, err := model.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+	if len(generations) == 0 {
+		return BatchResult{Err: ErrEmptyBatchResult}
+	}
+	return BatchResult{Generation: generations[0]}
+}
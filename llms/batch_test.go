@@ -0,0 +1,108 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var errBatchTest = errors.New("boom")
+
+// concurrentChat echoes the last message, failing for "fail", and records the
+// maximum number of concurrent calls.
+type concurrentChat struct {
+	active  atomic.Int32
+	maxSeen atomic.Int32
+}
+
+func (c *concurrentChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (c *concurrentChat) Generate(_ context.Context, messageSets [][]schema.ChatMessage, _ ...CallOption) ([]*Generation, error) { //nolint:lll
+	active := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		seen := c.maxSeen.Load()
+		if active <= seen || c.maxSeen.CompareAndSwap(seen, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	content := messageSets[0][len(messageSets[0])-1].GetContent()
+	if content == "fail" {
+		return nil, errBatchTest
+	}
+	msg := &schema.AIChatMessage{Content: content}
+	return []*Generation{{Message: msg, Text: content}}, nil
+}
+
+type nativeBatchChat struct {
+	concurrentChat
+	batches int
+}
+
+func (c *nativeBatchChat) GenerateBatch(_ context.Context, messageSets [][]schema.ChatMessage, _ ...CallOption) ([]BatchResult, error) { //nolint:lll
+	c.batches++
+	return make([]BatchResult, len(messageSets)), nil
+}
+
+func batchInputs(contents ...string) [][]schema.ChatMessage {
+	inputs := make([][]schema.ChatMessage, 0, len(contents))
+	for _, content := range contents {
+		inputs = append(inputs, []schema.ChatMessage{schema.HumanChatMessage{Content: content}})
+	}
+	return inputs
+}
+
+func TestGenerateBatch(t *testing.T) {
+	t.Parallel()
+
+	chat := &concurrentChat{}
+	results, err := GenerateBatch(context.Background(), chat, batchInputs("a", "fail", "c", "d", "e", "f"),
+		WithBatchConcurrency(2))
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	assert.Equal(t, "a", results[0].Generation.Text)
+	require.ErrorIs(t, results[1].Err, errBatchTest)
+	assert.Nil(t, results[1].Generation)
+	assert.Equal(t, "f", results[5].Generation.Text)
+	assert.LessOrEqual(t, chat.maxSeen.Load(), int32(2))
+}
+
+func TestGenerateBatchCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := GenerateBatch(ctx, &concurrentChat{}, batchInputs("a", "b", "c"), WithBatchConcurrency(1))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.ErrorIs(t, results[2].Err, context.Canceled)
+}
+
+func TestGenerateBatchNative(t *testing.T) {
+	t.Parallel()
+
+	chat := &nativeBatchChat{}
+	_, err := GenerateBatch(context.Background(), chat, batchInputs("a", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, chat.batches)
+
+	results, err := GenerateBatch(context.Background(), chat, batchInputs("a", "b"), WithNativeBatch())
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 1, chat.batches)
+}
@@ -0,0 +1,321 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Cacher stores the generations of LLM calls.
+type Cacher interface {
+	// Get returns the generation cached for the key and whether it was found.
+	Get(ctx context.Context, key string) (*llms.Generation, bool, error)
+	// Put caches the generation for the key.
+	Put(ctx context.Context, key string, generation *llms.Generation) error
+}
+
+// Option is a function configuring the cache wrappers.
+type Option func(o *options)
+
+type options struct {
+	namespace string
+}
+
+// WithNamespace sets a namespace added to the cache keys. Use it to keep the
+// entries of wrappers of different models, e.g. the model name, apart when
+// they share a cacher, as the default model of a client is not part of the
+// call options.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// LLM is an LLM caching the generations of the wrapped LLM.
+type LLM struct {
+	llm    llms.LLM
+	cacher Cacher
+	opts   options
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// New wraps the LLM to cache its generations in the cacher.
+func New(llm llms.LLM, cacher Cacher, opts ...Option) *LLM {
+	c := &LLM{llm: llm, cacher: cacher}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	return c
+}
+
+// Call returns the cached completion of the prompt, or calls the wrapped LLM.
+func (c *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	generations, err := c.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(generations) == 0 {
+		return "", nil
+	}
+	return generations[0].Text, nil
+}
+
+// Generate returns the cached generations of the prompts and calls the
+// wrapped LLM for the others.
+func (c *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	inputs := make([]any, len(prompts))
+	for i, prompt := range prompts {
+		inputs[i] = normalizeText(prompt)
+	}
+	return generate(ctx, c.cacher, c.opts, inputs, options, func(missing []int) ([]*llms.Generation, error) {
+		missingPrompts := make([]string, 0, len(missing))
+		for _, i := range missing {
+			missingPrompts = append(missingPrompts, prompts[i])
+		}
+		return c.llm.Generate(ctx, missingPrompts, options...)
+	})
+}
+
+func (c *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, c, promptValues, options...)
+}
+
+func (c *LLM) GetNumTokens(text string) int {
+	if lm, ok := c.llm.(llms.LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return llms.CountTokens("", text)
+}
+
+// Chat is a chat LLM caching the generations of the wrapped chat LLM.
+type Chat struct {
+	chat   llms.ChatLLM
+	cacher Cacher
+	opts   options
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat wraps the chat LLM to cache its generations in the cacher.
+func NewChat(chat llms.ChatLLM, cacher Cacher, opts ...Option) *Chat {
+	c := &Chat{chat: chat, cacher: cacher}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	return c
+}
+
+// Call returns the cached response to the messages, or calls the wrapped chat LLM.
+func (c *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0].Message == nil {
+		return &schema.AIChatMessage{}, nil
+	}
+	return generations[0].Message, nil
+}
+
+// Generate returns the cached generations of the message sets and calls the
+// wrapped chat LLM for the others.
+func (c *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	inputs := make([]any, len(messageSets))
+	for i, messages := range messageSets {
+		inputs[i] = normalizeMessages(messages)
+	}
+	return generate(ctx, c.cacher, c.opts, inputs, options, func(missing []int) ([]*llms.Generation, error) {
+		missingSets := make([][]schema.ChatMessage, 0, len(missing))
+		for _, i := range missing {
+			missingSets = append(missingSets, messageSets[i])
+		}
+		return c.chat.Generate(ctx, missingSets, options...)
+	})
+}
+
+func (c *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, c, promptValues, options...)
+}
+
+func (c *Chat) GetNumTokens(text string) int {
+	if lm, ok := c.chat.(llms.LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return llms.CountTokens("", text)
+}
+
+// CountTokens counts the input tokens of the messages with the wrapped chat LLM.
+func (c *Chat) CountTokens(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (int, error) { //nolint:lll
+	return llms.CountChatTokens(ctx, c.chat, messages, options...)
+}
+
+// generate looks up the generation of each input and calls fn with the
+// indexes of the inputs that are not cached. Cached text is replayed to the
+// streaming func as a single chunk.
+func generate(
+	ctx context.Context,
+	cacher Cacher,
+	o options,
+	inputs []any,
+	options []llms.CallOption,
+	fn func(missing []int) ([]*llms.Generation, error),
+) ([]*llms.Generation, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	generations := make([]*llms.Generation, len(inputs))
+	keys := make([]string, len(inputs))
+	missing := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		key, err := Key(o.namespace, input, opts)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+
+		generation, ok, err := cacher.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		generations[i] = cacheHit(generation)
+		if err := replay(ctx, opts, generation); err != nil {
+			return nil, err
+		}
+	}
+	if len(missing) == 0 {
+		return generations, nil
+	}
+
+	results, err := fn(missing)
+	if err != nil {
+		return nil, err
+	}
+	// Without one generation per input the results cannot be matched to
+	// the inputs and are returned as is.
+	if len(results) != len(missing) {
+		return results, nil
+	}
+	for j, i := range missing {
+		generations[i] = results[j]
+		if err := cacher.Put(ctx, keys[i], results[j]); err != nil {
+			return nil, err
+		}
+	}
+	return generations, nil
+}
+
+// replay passes a cached generation to the streaming funcs of the options as a
+// single chunk.
+func replay(ctx context.Context, opts llms.CallOptions, generation *llms.Generation) error {
+	if opts.StreamingFunc != nil && generation.Text != "" {
+		if err := opts.StreamingFunc(ctx, []byte(generation.Text)); err != nil {
+			return err
+		}
+	}
+	if opts.StreamingChunkFunc == nil {
+		return nil
+	}
+	chunk := llms.StreamChunk{Content: generation.Text}
+	if generation.Message != nil {
+		for i, tc := range generation.Message.ToolCalls {
+			if tc.FunctionCall == nil {
+				continue
+			}
+			arguments, _ := tc.FunctionCall.Arguments.(string)
+			chunk.ToolCalls = append(chunk.ToolCalls, llms.ToolCallChunk{
+				Index:     i,
+				ID:        tc.ID,
+				Name:      tc.FunctionCall.Name,
+				Arguments: arguments,
+			})
+		}
+	}
+	return opts.StreamingChunkFunc(ctx, chunk)
+}
+
+// cacheHit returns a copy of the cached generation marked as a cache hit.
+// Its usage is zero as no tokens were consumed.
+func cacheHit(generation *llms.Generation) *llms.Generation {
+	hit := *generation
+	hit.Usage = llms.Usage{}
+	hit.GenerationInfo = make(map[string]any, len(generation.GenerationInfo)+1)
+	for k, v := range generation.GenerationInfo {
+		hit.GenerationInfo[k] = v
+	}
+	hit.GenerationInfo["CacheHit"] = true
+	return &hit
+}
+
+// Key returns the cache key of the input, a prompt or a list of chat
+// messages, and the call options. Options that cannot be serialized, such as
+// the streaming func, are not part of the key.
+func Key(namespace string, input any, opts llms.CallOptions) (string, error) {
+	data, err := json.Marshal(struct {
+		Namespace string         `json:"namespace"`
+		Input     any            `json:"input"`
+		Options   map[string]any `json:"options"`
+	}{namespace, input, normalizeOptions(opts)})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalizeText(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+// normalizedMessage holds the type of the message, as messages of different
+// types may serialize to the same json.
+type normalizedMessage struct {
+	Type    schema.ChatMessageType `json:"type"`
+	Message schema.ChatMessage     `json:"message"`
+}
+
+func normalizeMessages(messages []schema.ChatMessage) []normalizedMessage {
+	normalized := make([]normalizedMessage, 0, len(messages))
+	for _, m := range messages {
+		normalized = append(normalized, normalizedMessage{Type: m.GetType(), Message: m})
+	}
+	return normalized
+}
+
+// normalizeOptions returns the call options as a map keyed by their json
+// names, without the fields of func type.
+func normalizeOptions(opts llms.CallOptions) map[string]any {
+	v := reflect.ValueOf(opts)
+	t := v.Type()
+	normalized := make(map[string]any, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Func {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+		normalized[name] = v.Field(i).Interface()
+	}
+	return normalized
+}
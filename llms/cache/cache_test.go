@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// countingLLM echoes the prompts and counts the prompts it generated.
+type countingLLM struct {
+	prompts []string
+}
+
+func (c *countingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	generations, err := c.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	return generations[0].Text, nil
+}
+
+func (c *countingLLM) Generate(_ context.Context, prompts []string, _ ...llms.CallOption) ([]*llms.Generation, error) {
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		c.prompts = append(c.prompts, prompt)
+		generations = append(generations, &llms.Generation{
+			Text:  "echo: " + prompt,
+			Usage: llms.Usage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+		})
+	}
+	return generations, nil
+}
+
+type countingChat struct {
+	calls int
+}
+
+func (c *countingChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (c *countingChat) Generate(_ context.Context, messageSets [][]schema.ChatMessage, _ ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messages := range messageSets {
+		c.calls++
+		msg := &schema.AIChatMessage{Content: "reply to " + messages[len(messages)-1].GetContent()}
+		generations = append(generations, &llms.Generation{Message: msg, Text: msg.Content})
+	}
+	return generations, nil
+}
+
+func TestLLM(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &countingLLM{}
+	cached := New(llm, NewInMemory(10))
+
+	text, err := cached.Call(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", text)
+
+	generations, err := cached.Generate(ctx, []string{" hello\r\n", "world"})
+	require.NoError(t, err)
+	require.Len(t, generations, 2)
+	assert.Equal(t, "echo: hello", generations[0].Text)
+	assert.Equal(t, true, generations[0].GenerationInfo["CacheHit"])
+	assert.Equal(t, llms.Usage{}, generations[0].Usage)
+	assert.Equal(t, "echo: world", generations[1].Text)
+	assert.Equal(t, 3, generations[1].Usage.TotalTokens)
+	assert.Equal(t, []string{"hello", "world"}, llm.prompts)
+
+	// Different options are cached separately.
+	_, err = cached.Call(ctx, "hello", llms.WithTemperature(0.5))
+	require.NoError(t, err)
+	assert.Len(t, llm.prompts, 3)
+
+	// Cached text is replayed to the streaming func.
+	var chunks []string
+	_, err = cached.Call(ctx, "hello", llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"echo: hello"}, chunks)
+
+	var streamChunks []llms.StreamChunk
+	_, err = cached.Call(ctx, "hello", llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		streamChunks = append(streamChunks, chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []llms.StreamChunk{{Content: "echo: hello"}}, streamChunks)
+	assert.Len(t, llm.prompts, 3)
+}
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	chat := &countingChat{}
+	cacher := NewInMemory(0)
+	cached := NewChat(chat, cacher, WithNamespace("model-a"))
+
+	messages := []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "Hi"},
+	}
+	msg, err := cached.Call(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "reply to Hi", msg.Content)
+	msg, err = cached.Call(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "reply to Hi", msg.Content)
+	assert.Equal(t, 1, chat.calls)
+
+	// The same content with another message type is a different input.
+	_, err = cached.Call(ctx, []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.AIChatMessage{Content: "Hi"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, chat.calls)
+
+	// Another namespace does not share the entries.
+	_, err = NewChat(chat, cacher, WithNamespace("model-b")).Call(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, 3, chat.calls)
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	opts := llms.CallOptions{Model: "gpt-4", Temperature: 0.2}
+	key, err := Key("", "hello", opts)
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	opts.StreamingFunc = func(context.Context, []byte) error { return nil }
+	same, err := Key("", "hello", opts)
+	require.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	other, err := Key("", "hello", llms.CallOptions{Model: "gpt-4", Temperature: 0.3})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
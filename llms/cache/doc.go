@@ -0,0 +1,8 @@
+// Package cache provides a caching layer for LLMs. The LLM and Chat wrappers
+// look up the generation of each prompt in a Cacher before calling the
+// wrapped model, so repeated calls, e.g. in agents and tests, do not hit the
+// API.
+//
+// The package includes an in-memory LRU cacher, the subpackages redis and
+// sqlite3 provide persistent ones.
+package cache
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// InMemory is a Cacher keeping the generations in memory. When the capacity
+// is reached the least recently used entry is evicted.
+type InMemory struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+var _ Cacher = (*InMemory)(nil)
+
+type inMemoryEntry struct {
+	key   string
+	value []byte
+}
+
+// NewInMemory returns an in-memory LRU cacher holding up to capacity
+// entries. A capacity of zero or less is unbounded.
+func NewInMemory(capacity int) *InMemory {
+	return &InMemory{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the generation cached for the key.
+func (m *InMemory) Get(_ context.Context, key string) (*llms.Generation, bool, error) {
+	m.mu.Lock()
+	element, ok := m.entries[key]
+	if !ok {
+		m.mu.Unlock()
+		return nil, false, nil
+	}
+	m.order.MoveToFront(element)
+	value := element.Value.(*inMemoryEntry).value //nolint:forcetypeassert
+	m.mu.Unlock()
+
+	var generation llms.Generation
+	if err := json.Unmarshal(value, &generation); err != nil {
+		return nil, false, err
+	}
+	return &generation, true, nil
+}
+
+// Put caches the generation for the key. The generation is copied, later
+// changes to it are not cached.
+func (m *InMemory) Put(_ context.Context, key string, generation *llms.Generation) error {
+	value, err := json.Marshal(generation)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.entries[key]; ok {
+		element.Value.(*inMemoryEntry).value = value //nolint:forcetypeassert
+		m.order.MoveToFront(element)
+		return nil
+	}
+	m.entries[key] = m.order.PushFront(&inMemoryEntry{key: key, value: value})
+	if m.capacity > 0 && m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*inMemoryEntry).key) //nolint:forcetypeassert
+	}
+	return nil
+}
+
+// Len returns the number of cached entries.
+func (m *InMemory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
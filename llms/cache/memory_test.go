@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestInMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewInMemory(2)
+	_, ok, err := m.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	generation := &llms.Generation{Text: "a"}
+	require.NoError(t, m.Put(ctx, "a", generation))
+	generation.Text = "changed"
+	require.NoError(t, m.Put(ctx, "b", &llms.Generation{Text: "b"}))
+
+	got, ok, err := m.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "a", got.Text)
+
+	// "b" is the least recently used entry.
+	require.NoError(t, m.Put(ctx, "c", &llms.Generation{Text: "c"}))
+	assert.Equal(t, 2, m.Len())
+	_, ok, err = m.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = m.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
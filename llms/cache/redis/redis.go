@@ -0,0 +1,79 @@
+// Package redis provides a Redis backed cacher for the llms/cache package.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+const _defaultPrefix = "langchaingo:llmcache:"
+
+// Cache is a Cacher storing the generations in Redis.
+type Cache struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+var _ cache.Cacher = (*Cache)(nil)
+
+// Option is a function configuring the Redis cacher.
+type Option func(c *Cache)
+
+// WithPrefix sets the prefix of the keys. Defaults to "langchaingo:llmcache:".
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// WithTTL sets how long the entries are kept. Defaults to no expiration.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// New returns a cacher storing the generations with the Redis client.
+func New(client redis.UniversalClient, opts ...Option) *Cache {
+	c := &Cache{
+		client: client,
+		prefix: _defaultPrefix,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the generation cached for the key.
+func (c *Cache) Get(ctx context.Context, key string) (*llms.Generation, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var generation llms.Generation
+	if err := json.Unmarshal(value, &generation); err != nil {
+		return nil, false, err
+	}
+	return &generation, true, nil
+}
+
+// Put caches the generation for the key.
+func (c *Cache) Put(ctx context.Context, key string, generation *llms.Generation) error {
+	value, err := json.Marshal(generation)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.prefix+key, value, c.ttl).Err()
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		t.Skip("Must set REDIS_URL to run test")
+	}
+	opts, err := redis.ParseURL(redisURL)
+	require.NoError(t, err)
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	c := New(client, WithPrefix("langchaingo:test:"+t.Name()+":"), WithTTL(time.Minute))
+
+	_, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put(ctx, "key", &llms.Generation{Text: "hello"}))
+	generation, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "hello", generation.Text)
+}
@@ -0,0 +1,116 @@
+// Package sqlite3 provides a SQLite backed cacher for the llms/cache package.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+const _defaultTableName = "llm_cache"
+
+// nolint:gochecknoglobals
+var _tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrInvalidTableName is returned when the table name is not a valid identifier.
+var ErrInvalidTableName = errors.New("invalid table name")
+
+// Cache is a Cacher storing the generations in a SQLite table.
+type Cache struct {
+	db        *sql.DB
+	tableName string
+}
+
+var _ cache.Cacher = (*Cache)(nil)
+
+// Option is a function configuring the SQLite cacher.
+type Option func(c *Cache)
+
+// WithTableName sets the name of the table. Defaults to "llm_cache".
+func WithTableName(tableName string) Option {
+	return func(c *Cache) {
+		c.tableName = tableName
+	}
+}
+
+// New returns a cacher storing the generations in the database, creating the
+// table if it does not exist.
+func New(ctx context.Context, db *sql.DB, opts ...Option) (*Cache, error) {
+	c := &Cache{
+		db:        db,
+		tableName: _defaultTableName,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if !_tableNameRegexp.MatchString(c.tableName) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTableName, c.tableName)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		key TEXT PRIMARY KEY,
+		value BLOB NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`, c.tableName))
+	if err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+	return c, nil
+}
+
+// Open opens the SQLite database of the data source name, e.g.
+// "file:cache.db", and returns a cacher using it.
+func Open(ctx context.Context, dsn string, opts ...Option) (*Cache, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	c, err := New(ctx, db, opts...)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns the generation cached for the key.
+func (c *Cache) Get(ctx context.Context, key string) (*llms.Generation, bool, error) {
+	var value []byte
+	err := c.db.QueryRowContext(ctx, fmt.Sprintf("SELECT value FROM %s WHERE key = ?", c.tableName), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var generation llms.Generation
+	if err := json.Unmarshal(value, &generation); err != nil {
+		return nil, false, err
+	}
+	return &generation, true, nil
+}
+
+// Put caches the generation for the key, replacing an existing entry.
+func (c *Cache) Put(ctx context.Context, key string, generation *llms.Generation) error {
+	value, err := json.Marshal(generation)
+	if err != nil {
+		return err
+	}
+	_, err = c.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT OR REPLACE INTO %s (key, value) VALUES (?, ?)", c.tableName), key, value)
+	return err
+}
+
+// Close closes the database.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,46 @@
+package sqlite3
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c, err := Open(ctx, "file:"+filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	_, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put(ctx, "key", &llms.Generation{Text: "old"}))
+	require.NoError(t, c.Put(ctx, "key", &llms.Generation{
+		Text:    "hello",
+		Message: &schema.AIChatMessage{Content: "hello"},
+		Usage:   llms.Usage{TotalTokens: 3},
+	}))
+
+	generation, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "hello", generation.Text)
+	assert.Equal(t, "hello", generation.Message.Content)
+	assert.Equal(t, 3, generation.Usage.TotalTokens)
+}
+
+func TestInvalidTableName(t *testing.T) {
+	t.Parallel()
+
+	_, err := Open(context.Background(), "file::memory:", WithTableName("cache; DROP TABLE x"))
+	require.ErrorIs(t, err, ErrInvalidTableName)
+}
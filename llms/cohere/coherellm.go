@@ -18,7 +18,7 @@ var (
 )
 
 type LLM struct {
-	client *cohereclient.Client
+	chat *Chat
 }
 
 var (
@@ -37,6 +37,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return r[0].Text, nil
 }
 
+// Generate generates a completion for each of the prompts with the Chat API.
 func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
 	opts := llms.CallOptions{}
 	for _, opt := range options {
@@ -46,23 +47,18 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	generations := make([]*llms.Generation, 0, len(prompts))
 
 	for _, prompt := range prompts {
-		result, err := o.client.CreateGeneration(ctx, &cohereclient.GenerationRequest{
-			Prompt: prompt,
-		})
+		generation, err := o.chat.generate(ctx, []schema.ChatMessage{schema.HumanChatMessage{Content: prompt}}, opts)
 		if err != nil {
 			return nil, err
 		}
-
-		generations = append(generations, &llms.Generation{
-			Text: result.Text,
-		})
+		generations = append(generations, generation)
 	}
 
 	return generations, nil
 }
 
 func (o *LLM) GetNumTokens(text string) int {
-	return o.client.GetNumTokens(text)
+	return o.chat.GetNumTokens(text)
 }
 
 func (o *LLM) GeneratePrompt(
@@ -73,14 +69,15 @@ func (o *LLM) GeneratePrompt(
 	return llms.GeneratePrompt(ctx, o, promptValues, options...)
 }
 
+// New returns a Cohere LLM generating completions with the Chat API.
 func New(opts ...Option) (*LLM, error) {
-	c, err := newClient(opts...)
+	chat, err := NewChat(opts...)
 	return &LLM{
-		client: c,
+		chat: chat,
 	}, err
 }
 
-func newClient(opts ...Option) (*cohereclient.Client, error) {
+func newClient(opts ...Option) (*cohereclient.Client, *options, error) {
 	options := &options{
 		token:   os.Getenv(tokenEnvVarName),
 		baseURL: os.Getenv(baseURLEnvVarName),
@@ -92,8 +89,14 @@ func newClient(opts ...Option) (*cohereclient.Client, error) {
 	}
 
 	if len(options.token) == 0 {
-		return nil, ErrMissingToken
+		return nil, nil, ErrMissingToken
+	}
+
+	var clientOpts []cohereclient.Option
+	if options.httpClient != nil {
+		clientOpts = append(clientOpts, cohereclient.WithHTTPClient(options.httpClient))
 	}
 
-	return cohereclient.New(options.token, options.baseURL, options.model)
+	c, err := cohereclient.New(options.token, options.baseURL, options.model, clientOpts...)
+	return c, options, err
 }
@@ -0,0 +1,217 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cohere/internal/cohereclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// CitationsKey is the key of the citations of a response in the generation
+// info, a []Citation.
+const CitationsKey = "Citations"
+
+// Chat is a Cohere chat LLM using the Chat API.
+type Chat struct {
+	client     *cohereclient.Client
+	connectors []cohereclient.Connector
+
+	embeddingModel      string
+	embeddingDimensions int
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat returns a new Cohere chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	c, options, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	chat := &Chat{
+		client:              c,
+		embeddingModel:      options.embeddingModel,
+		embeddingDimensions: options.embeddingDimensions,
+	}
+	for _, id := range options.connectors {
+		chat.connectors = append(chat.connectors, cohereclient.Connector{ID: id})
+	}
+	return chat, nil
+}
+
+// DocumentsChatMessage is a message sent by a human with documents grounding
+// the response. The response cites the documents by id, the "id" metadata of
+// the document or its index.
+type DocumentsChatMessage struct {
+	Content   string
+	Documents []schema.Document
+}
+
+var _ schema.ChatMessage = DocumentsChatMessage{}
+
+func (m DocumentsChatMessage) GetType() schema.ChatMessageType { return schema.ChatMessageTypeHuman }
+func (m DocumentsChatMessage) GetContent() string              { return m.Content }
+
+// Citation is a span of a response citing documents.
+type Citation struct {
+	// Start and End are the offsets of the span in the response.
+	Start int
+	End   int
+	Text  string
+	// DocumentIDs are the ids of the cited documents.
+	DocumentIDs []string
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// Generate requests a chat response for each of the message sets. The system
+// messages are sent as the preamble and the last message, a human message, as
+// the message of the user.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		generation, err := o.generate(ctx, messageSet, opts)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, generation)
+	}
+	return generations, nil
+}
+
+// GeneratePrompt generates a chat response for each of the prompt values.
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text.
+func (o *Chat) GetNumTokens(text string) int {
+	return o.client.GetNumTokens(text)
+}
+
+func (o *Chat) generate(ctx context.Context, messages []schema.ChatMessage, opts llms.CallOptions) (*llms.Generation, error) { // nolint:lll
+	// The chat API of Cohere only accepts text.
+	if err := llms.RequireTextParts(messages); err != nil {
+		return nil, err
+	}
+	req, err := chatRequest(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+	req.Connectors = o.connectors
+
+	result, err := o.client.CreateChat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	citations := make([]Citation, 0, len(result.Citations))
+	for _, c := range result.Citations {
+		citations = append(citations, Citation(c))
+	}
+	inputTokens := int(result.Meta.BilledUnits.InputTokens)
+	outputTokens := int(result.Meta.BilledUnits.OutputTokens)
+	return &llms.Generation{
+		Text:    result.Text,
+		Message: &schema.AIChatMessage{Content: result.Text},
+		GenerationInfo: map[string]any{
+			"GenerationID": result.GenerationID,
+			"FinishReason": result.FinishReason,
+			CitationsKey:   citations,
+		},
+		Usage: llms.Usage{
+			PromptTokens:     inputTokens,
+			CompletionTokens: outputTokens,
+			TotalTokens:      inputTokens + outputTokens,
+		},
+	}, nil
+}
+
+// chatRequest converts the messages and the call options to a request.
+func chatRequest(messages []schema.ChatMessage, opts llms.CallOptions) (*cohereclient.ChatRequest, error) {
+	if len(messages) == 0 || messages[len(messages)-1].GetType() != schema.ChatMessageTypeHuman {
+		return nil, fmt.Errorf("%w: the last message must be a human message", schema.ErrUnexpectedChatMessageType)
+	}
+
+	req := &cohereclient.ChatRequest{
+		Model:              opts.Model,
+		Temperature:        opts.Temperature,
+		MaxTokens:          opts.MaxTokens,
+		K:                  opts.TopK,
+		P:                  opts.TopP,
+		StopSequences:      opts.StopWords,
+		FrequencyPenalty:   opts.FrequencyPenalty,
+		PresencePenalty:    opts.PresencePenalty,
+		StreamingFunc:      opts.StreamingFunc,
+		StreamingChunkFunc: opts.StreamingChunkFunc,
+	}
+	if opts.SeedSet {
+		req.Seed = &opts.Seed
+	}
+
+	var preamble []string
+	last := messages[len(messages)-1]
+	for _, m := range messages[:len(messages)-1] {
+		switch m.GetType() {
+		case schema.ChatMessageTypeSystem:
+			preamble = append(preamble, m.GetContent())
+		case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric:
+			req.ChatHistory = append(req.ChatHistory, cohereclient.ChatMessage{
+				Role:    cohereclient.RoleUser,
+				Message: m.GetContent(),
+			})
+		case schema.ChatMessageTypeAI:
+			req.ChatHistory = append(req.ChatHistory, cohereclient.ChatMessage{
+				Role:    cohereclient.RoleChatbot,
+				Message: m.GetContent(),
+			})
+		default:
+			return nil, fmt.Errorf("%w: %s", schema.ErrUnexpectedChatMessageType, m.GetType())
+		}
+	}
+	req.Preamble = strings.Join(preamble, "\n")
+	req.Message = last.GetContent()
+	if m, ok := last.(DocumentsChatMessage); ok {
+		req.Documents = documents(m.Documents)
+	}
+	return req, nil
+}
+
+// documents converts the documents to the string maps of the API. The string
+// metadata of the documents are sent with their content.
+func documents(docs []schema.Document) []map[string]string {
+	converted := make([]map[string]string, 0, len(docs))
+	for i, doc := range docs {
+		fields := map[string]string{"id": strconv.Itoa(i)}
+		for key, value := range doc.Metadata {
+			if s, ok := value.(string); ok {
+				fields[key] = s
+			}
+		}
+		fields["text"] = doc.PageContent
+		converted = append(converted, fields)
+	}
+	return converted
+}
@@ -0,0 +1,143 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatGenerate(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat", r.URL.Path)
+		assert.Equal(t, "bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"text": "Emperor penguins are the tallest.",
+			"generation_id": "gen_1",
+			"finish_reason": "COMPLETE",
+			"citations": [{"start": 0, "end": 16, "text": "Emperor penguins", "document_ids": ["doc_a"]}],
+			"meta": {"billed_units": {"input_tokens": 20, "output_tokens": 7}}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithModel("command-r"),
+		WithConnectors("web-search"))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "Hi"},
+		schema.AIChatMessage{Content: "Hello"},
+		DocumentsChatMessage{
+			Content: "Which penguins are the tallest?",
+			Documents: []schema.Document{
+				{PageContent: "Emperor penguins are the tallest.", Metadata: map[string]any{"id": "doc_a", "title": "Tall"}},
+				{PageContent: "Penguins live in the south.", Metadata: map[string]any{"year": 2020}},
+			},
+		},
+	}}, llms.WithTemperature(0.3), llms.WithMaxTokens(100))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+
+	assert.Equal(t, map[string]any{
+		"message":  "Which penguins are the tallest?",
+		"model":    "command-r",
+		"preamble": "Be brief.",
+		"chat_history": []any{
+			map[string]any{"role": "USER", "message": "Hi"},
+			map[string]any{"role": "CHATBOT", "message": "Hello"},
+		},
+		"connectors": []any{map[string]any{"id": "web-search"}},
+		"documents": []any{
+			map[string]any{"id": "doc_a", "title": "Tall", "text": "Emperor penguins are the tallest."},
+			map[string]any{"id": "1", "text": "Penguins live in the south."},
+		},
+		"temperature": 0.3,
+		"max_tokens":  float64(100),
+	}, got)
+
+	generation := generations[0]
+	assert.Equal(t, "Emperor penguins are the tallest.", generation.Message.Content)
+	assert.Equal(t, []Citation{{Start: 0, End: 16, Text: "Emperor penguins", DocumentIDs: []string{"doc_a"}}},
+		generation.GenerationInfo[CitationsKey])
+	assert.Equal(t, "COMPLETE", generation.GenerationInfo["FinishReason"])
+	assert.Equal(t, llms.Usage{PromptTokens: 20, CompletionTokens: 7, TotalTokens: 27}, generation.Usage)
+}
+
+func TestChatStreaming(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var got map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		assert.Equal(t, true, got["stream"])
+		fmt.Fprintln(w, `{"is_finished":false,"event_type":"stream-start","generation_id":"gen_1"}`)
+		fmt.Fprintln(w, `{"is_finished":false,"event_type":"text-generation","text":"Hello"}`)
+		fmt.Fprintln(w, `{"is_finished":false,"event_type":"text-generation","text":" world"}`)
+		if r.Header.Get("X-Case") == "error" {
+			fmt.Fprintln(w, `{"is_finished":true,"event_type":"stream-end","finish_reason":"ERROR"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"is_finished":true,"event_type":"stream-end","finish_reason":"COMPLETE",`+
+			`"response":{"text":"Hello world","generation_id":"gen_1","meta":{"billed_units":{"input_tokens":3,"output_tokens":2}}}}`) //nolint:lll
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []string
+	streaming := llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+	generations, err := llm.Generate(context.Background(), []string{"Hi"}, streaming)
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, []string{"Hello", " world"}, chunks)
+	assert.Equal(t, "COMPLETE", generations[0].GenerationInfo["FinishReason"])
+	assert.Equal(t, 5, generations[0].Usage.TotalTokens)
+
+	ctx := llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"error"}})
+	_, err = llm.Generate(ctx, []string{"Hi"}, streaming)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello world", streamErr.Partial)
+}
+
+func TestChatErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"model 'command-x' not found"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.AIChatMessage{Content: "Hi"}})
+	require.ErrorIs(t, err, schema.ErrUnexpectedChatMessageType)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}})
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+
+	_, err = NewChat(WithToken(""))
+	require.ErrorIs(t, err, ErrMissingToken)
+}
@@ -0,0 +1,80 @@
+package cohere
+
+import (
+	"context"
+	"math"
+
+	"github.com/tmc/langchaingo/llms/cohere/internal/cohereclient"
+)
+
+// InputType is the use of the embedded texts. The embed v3 models embed the
+// documents and the queries of a search differently.
+type InputType string
+
+const (
+	InputTypeSearchDocument InputType = cohereclient.InputTypeSearchDocument
+	InputTypeSearchQuery    InputType = cohereclient.InputTypeSearchQuery
+	InputTypeClassification InputType = cohereclient.InputTypeClassification
+	InputTypeClustering     InputType = cohereclient.InputTypeClustering
+)
+
+// CreateEmbedding creates embeddings for the given input texts, embedded as
+// documents of a search.
+func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return o.chat.CreateEmbedding(ctx, inputTexts)
+}
+
+// CreateEmbeddingWithInputType creates embeddings for the given input texts,
+// embedded for the input type.
+func (o *LLM) CreateEmbeddingWithInputType(ctx context.Context, inputTexts []string, inputType InputType) ([][]float64, error) { //nolint:lll
+	return o.chat.CreateEmbeddingWithInputType(ctx, inputTexts, inputType)
+}
+
+// CreateEmbedding creates embeddings for the given input texts, embedded as
+// documents of a search.
+func (o *Chat) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return o.CreateEmbeddingWithInputType(ctx, inputTexts, InputTypeSearchDocument)
+}
+
+// CreateEmbeddingWithInputType creates embeddings for the given input texts,
+// embedded for the input type.
+func (o *Chat) CreateEmbeddingWithInputType(ctx context.Context, inputTexts []string, inputType InputType) ([][]float64, error) { //nolint:lll
+	embeddings, err := o.client.Embed(ctx, &cohereclient.EmbedRequest{
+		Texts:     inputTexts,
+		Model:     o.embeddingModel,
+		InputType: string(inputType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	if o.embeddingDimensions > 0 {
+		for i, embedding := range embeddings {
+			embeddings[i] = truncate(embedding, o.embeddingDimensions)
+		}
+	}
+	return embeddings, nil
+}
+
+// truncate returns the first dimensions of the embedding rescaled to unit
+// length, or the embedding if it has no more dimensions.
+func truncate(embedding []float64, dimensions int) []float64 {
+	if len(embedding) <= dimensions {
+		return embedding
+	}
+	truncated := embedding[:dimensions]
+	var norm float64
+	for _, v := range truncated {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return truncated
+	}
+	for i := range truncated {
+		truncated[i] /= norm
+	}
+	return truncated
+}
@@ -0,0 +1,65 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEmbedding(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embed", r.URL.Path)
+		var got map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		requests <- got
+		fmt.Fprint(w, `{"id":"1","embeddings":[[3,4,12],[1,0,0]]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	embeddings, err := llm.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{3, 4, 12}, {1, 0, 0}}, embeddings)
+	assert.Equal(t, map[string]any{
+		"texts":      []any{"a", "b"},
+		"model":      "embed-english-v3.0",
+		"input_type": "search_document",
+	}, <-requests)
+
+	llm, err = New(WithToken("token"), WithBaseURL(server.URL),
+		WithEmbeddingModel("embed-v4.0"), WithEmbeddingDimensions(2))
+	require.NoError(t, err)
+
+	embeddings, err = llm.CreateEmbeddingWithInputType(context.Background(), []string{"a", "b"}, InputTypeSearchQuery)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.6, 0.8}, {1, 0}}, embeddings)
+	got := <-requests
+	assert.Equal(t, "embed-v4.0", got["model"])
+	assert.Equal(t, "search_query", got["input_type"])
+}
+
+func TestCreateEmbeddingUnexpectedLength(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"embeddings":[[1,0]]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = chat.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.ErrorIs(t, err, ErrUnexpectedResponseLength)
+}
@@ -1,5 +1,7 @@
 package cohere
 
+import "github.com/tmc/langchaingo/llms/cohere/internal/cohereclient"
+
 const (
 	tokenEnvVarName   = "COHERE_API_KEY"  //nolint:gosec
 	modelEnvVarName   = "COHERE_MODEL"    //nolint:gosec
@@ -7,9 +9,15 @@ const (
 )
 
 type options struct {
-	token   string
-	model   string
-	baseURL string
+	token       string
+	model       string
+	baseURL     string
+	httpClient  cohereclient.Doer
+	connectors  []string
+	rerankModel string
+
+	embeddingModel      string
+	embeddingDimensions int
 }
 
 type Option func(*options)
@@ -38,3 +46,44 @@ func WithBaseURL(baseURL string) Option {
 		opts.baseURL = baseURL
 	}
 }
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client cohereclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithConnectors grounds the chat responses in the results of the connectors,
+// e.g. "web-search".
+func WithConnectors(ids ...string) Option {
+	return func(opts *options) {
+		opts.connectors = ids
+	}
+}
+
+// WithRerankModel sets the model of the Reranker, rerank-english-v3.0 if not
+// set.
+func WithRerankModel(model string) Option {
+	return func(opts *options) {
+		opts.rerankModel = model
+	}
+}
+
+// WithEmbeddingModel sets the model of the embeddings, embed-english-v3.0 if
+// not set.
+func WithEmbeddingModel(model string) Option {
+	return func(opts *options) {
+		opts.embeddingModel = model
+	}
+}
+
+// WithEmbeddingDimensions shortens the embeddings to their first dimensions,
+// rescaled to unit length. The embeddings of models trained with Matryoshka
+// representation learning, such as embed-v4.0, keep most of their quality
+// when shortened, and take less space in the vector store.
+func WithEmbeddingDimensions(dimensions int) Option {
+	return func(opts *options) {
+		opts.embeddingDimensions = dimensions
+	}
+}
@@ -0,0 +1,179 @@
+package cohereclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Roles of the messages of the chat history.
+const (
+	RoleUser    = "USER"
+	RoleChatbot = "CHATBOT"
+	RoleSystem  = "SYSTEM"
+)
+
+// ErrGeneration is returned when the API ends a streaming response with an
+// error finish reason.
+var ErrGeneration = errors.New("generation failed")
+
+// ChatRequest is a request to the Chat API.
+type ChatRequest struct {
+	// Message is the last message of the user.
+	Message string `json:"message"`
+	Model   string `json:"model,omitempty"`
+	// Preamble replaces the default system prompt of the model.
+	Preamble    string        `json:"preamble,omitempty"`
+	ChatHistory []ChatMessage `json:"chat_history,omitempty"`
+	// Connectors are the ids of the connectors searched to ground the
+	// response, e.g. "web-search".
+	Connectors []Connector `json:"connectors,omitempty"`
+	// Documents ground the response, each document is a map of string fields
+	// such as "id", "title" and "text".
+	Documents        []map[string]string `json:"documents,omitempty"`
+	Temperature      float64             `json:"temperature,omitempty"`
+	MaxTokens        int                 `json:"max_tokens,omitempty"`
+	K                int                 `json:"k,omitempty"`
+	P                float64             `json:"p,omitempty"`
+	Seed             *int                `json:"seed,omitempty"`
+	StopSequences    []string            `json:"stop_sequences,omitempty"`
+	FrequencyPenalty float64             `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64             `json:"presence_penalty,omitempty"`
+	Stream           bool                `json:"stream,omitempty"`
+
+	// StreamingFunc is a function to be called for each chunk of a streaming response.
+	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each structured chunk
+	// of a streaming response.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
+}
+
+// ChatMessage is a message of the chat history.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+// Connector is a data source searched to ground the response.
+type Connector struct {
+	ID string `json:"id"`
+}
+
+// Citation is a span of the response citing documents.
+type Citation struct {
+	Start       int      `json:"start"`
+	End         int      `json:"end"`
+	Text        string   `json:"text"`
+	DocumentIDs []string `json:"document_ids"`
+}
+
+// ChatResponse is a response of the Chat API.
+type ChatResponse struct {
+	Text         string     `json:"text"`
+	GenerationID string     `json:"generation_id"`
+	FinishReason string     `json:"finish_reason"`
+	Citations    []Citation `json:"citations"`
+	// Documents are the documents cited by the response, including the
+	// results of the connectors.
+	Documents []map[string]string `json:"documents"`
+	Meta      struct {
+		BilledUnits struct {
+			InputTokens  float64 `json:"input_tokens"`
+			OutputTokens float64 `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// streamEvent is an event of a streaming response.
+type streamEvent struct {
+	EventType    string              `json:"event_type"`
+	Text         string              `json:"text"`
+	Citations    []Citation          `json:"citations"`
+	Documents    []map[string]string `json:"documents"`
+	FinishReason string              `json:"finish_reason"`
+	Response     *ChatResponse       `json:"response"`
+}
+
+// CreateChat sends the request to the Chat API. If the request has a
+// streaming func the response is streamed and assembled into a single
+// response.
+func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatResponse, error) {
+	if r.Model == "" {
+		r.Model = c.model
+	}
+	r.Stream = r.StreamingFunc != nil || r.StreamingChunkFunc != nil
+	if !r.Stream {
+		var response ChatResponse
+		if err := c.doJSON(ctx, "/v1/chat", r, &response); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+
+	res, err := c.post(ctx, "/v1/chat", r)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return parseStreamingChatResponse(ctx, res.Body, r)
+}
+
+// parseStreamingChatResponse reads the newline delimited events of a
+// streaming response. The last event holds the full response.
+func parseStreamingChatResponse(ctx context.Context, body io.ReadCloser, r *ChatRequest) (*ChatResponse, error) {
+	stop := llms.CloseOnCancel(ctx, body)
+	defer stop()
+	var text strings.Builder
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, text.String(), err)
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20) //nolint:gomnd
+	for ctx.Err() == nil && scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event streamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("parse stream event: %w", err)
+		}
+
+		switch event.EventType {
+		case "text-generation":
+			text.WriteString(event.Text)
+			if r.StreamingFunc != nil {
+				if err := r.StreamingFunc(ctx, []byte(event.Text)); err != nil {
+					return nil, fmt.Errorf("streaming func returned an error: %w", err)
+				}
+			}
+			if r.StreamingChunkFunc != nil {
+				if err := r.StreamingChunkFunc(ctx, llms.StreamChunk{Content: event.Text}); err != nil {
+					return nil, fmt.Errorf("streaming func returned an error: %w", err)
+				}
+			}
+		case "stream-end":
+			if strings.HasPrefix(event.FinishReason, "ERROR") {
+				return nil, interrupted(fmt.Errorf("%w: %s", ErrGeneration, event.FinishReason))
+			}
+			if event.Response == nil {
+				return &ChatResponse{Text: text.String(), FinishReason: event.FinishReason}, nil
+			}
+			if event.Response.FinishReason == "" {
+				event.Response.FinishReason = event.FinishReason
+			}
+			return event.Response, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, interrupted(fmt.Errorf("read stream: %w", err))
+	}
+	return nil, interrupted(io.ErrUnexpectedEOF)
+}
@@ -10,8 +10,11 @@ import (
 	"strings"
 
 	"github.com/cohere-ai/tokenizer"
+	"github.com/tmc/langchaingo/llms"
 )
 
+const defaultBaseURL = "https://api.cohere.ai"
+
 var (
 	ErrEmptyResponse = errors.New("empty response")
 	ErrModelNotFound = errors.New("model not found")
@@ -47,9 +50,12 @@ func New(token string, baseURL string, model string, opts ...Option) (*Client, e
 		return nil, fmt.Errorf("create tokenizer: %w", err)
 	}
 
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
 	c := &Client{
 		token:      token,
-		baseURL:    baseURL,
+		baseURL:    strings.TrimRight(baseURL, "/"),
 		model:      model,
 		httpClient: http.DefaultClient,
 		encoder:    encoder,
@@ -64,78 +70,54 @@ func New(token string, baseURL string, model string, opts ...Option) (*Client, e
 	return c, nil
 }
 
-type GenerationRequest struct {
-	Prompt string `json:"prompt"`
-}
-
-type Generation struct {
-	Text string `json:"text"`
-}
-
-type generateRequestPayload struct {
-	Prompt string `json:"prompt"`
-	Model  string `json:"model"`
-}
-
-type generateResponsePayload struct {
-	ID          string `json:"id,omitempty"`
-	Message     string `json:"message,omitempty"`
-	Generations []struct {
-		ID   string `json:"id,omitempty"`
-		Text string `json:"text,omitempty"`
-	} `json:"generations,omitempty"`
-}
-
-func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*Generation, error) {
-	if c.baseURL == "" {
-		c.baseURL = "https://api.cohere.ai"
+// doJSON posts the payload to the path of the API and decodes the response.
+func (c *Client) doJSON(ctx context.Context, path string, payload, response any) error {
+	res, err := c.post(ctx, path, payload)
+	if err != nil {
+		return err
 	}
+	defer res.Body.Close()
 
-	payload := generateRequestPayload{
-		Prompt: r.Prompt,
-		Model:  c.model,
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		return fmt.Errorf("parse response: %w", err)
 	}
+	return nil
+}
 
-	payloadBytes, err := json.Marshal(&payload)
+// post posts the payload to the path of the API and returns the response if
+// its status is OK.
+func (c *Client) post(ctx context.Context, path string, payload any) (*http.Response, error) {
+	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		fmt.Sprintf("%s/v1/generate", c.baseURL),
-		bytes.NewReader(payloadBytes),
-	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("content-type", "application/json")
 	req.Header.Set("authorization", "bearer "+c.token)
+	llms.SetRequestHeaders(req)
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
-	defer res.Body.Close()
-
-	var response generateResponsePayload
-	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
-	}
-
-	if len(response.Generations) == 0 {
-		if strings.HasPrefix(response.Message, "model not found") {
-			return nil, ErrModelNotFound
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&errResp)
+		statusErr := llms.NewStatusError(res, "", errResp.Message)
+		if res.StatusCode == http.StatusNotFound && strings.HasPrefix(errResp.Message, "model") {
+			return nil, fmt.Errorf("%w: %w", ErrModelNotFound, statusErr)
 		}
-		return nil, ErrEmptyResponse
+		return nil, statusErr
 	}
-
-	var generation Generation
-	generation.Text = response.Generations[0].Text
-
-	return &generation, nil
+	return res, nil
 }
 
 func (c *Client) GetNumTokens(text string) int {
@@ -0,0 +1,45 @@
+package cohereclient
+
+import "context"
+
+const defaultEmbedModel = "embed-english-v3.0"
+
+// Input types of the embed v3 models, which embed the documents and the
+// queries of a search differently.
+const (
+	InputTypeSearchDocument = "search_document"
+	InputTypeSearchQuery    = "search_query"
+	InputTypeClassification = "classification"
+	InputTypeClustering     = "clustering"
+)
+
+// EmbedRequest is a request to the Embed API.
+type EmbedRequest struct {
+	Texts []string `json:"texts"`
+	Model string   `json:"model"`
+	// InputType is required by the embed v3 models.
+	InputType string `json:"input_type,omitempty"`
+	// Truncate is how texts longer than the maximum length are handled, one
+	// of "NONE", "START" and "END".
+	Truncate string `json:"truncate,omitempty"`
+}
+
+type embedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// Embed returns the embeddings of the texts of the request. The model of the
+// client, a chat model, is not used.
+func (c *Client) Embed(ctx context.Context, r *EmbedRequest) ([][]float64, error) {
+	if r.Model == "" {
+		r.Model = defaultEmbedModel
+	}
+	var response embedResponse
+	if err := c.doJSON(ctx, "/v1/embed", r, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return response.Embeddings, nil
+}
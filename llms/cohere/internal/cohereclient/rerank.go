@@ -0,0 +1,38 @@
+package cohereclient
+
+import "context"
+
+const defaultRerankModel = "rerank-english-v3.0"
+
+// RerankRequest is a request to the Rerank API.
+type RerankRequest struct {
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	Model     string   `json:"model"`
+	// TopN is the number of results, all the documents if zero.
+	TopN int `json:"top_n,omitempty"`
+}
+
+// RerankResult is the relevance of a document to the query.
+type RerankResult struct {
+	// Index is the index of the document in the request.
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type rerankResponse struct {
+	Results []RerankResult `json:"results"`
+}
+
+// Rerank orders the documents of the request by relevance to the query, the
+// most relevant first. The model of the client, a chat model, is not used.
+func (c *Client) Rerank(ctx context.Context, r *RerankRequest) ([]RerankResult, error) {
+	if r.Model == "" {
+		r.Model = defaultRerankModel
+	}
+	var response rerankResponse
+	if err := c.doJSON(ctx, "/v1/rerank", r, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
@@ -0,0 +1,91 @@
+package cohere
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms/cohere/internal/cohereclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// RelevanceScoreKey is the metadata key of the relevance score of the
+// documents returned by the Reranker.
+const RelevanceScoreKey = "relevance_score"
+
+// Reranker orders documents by relevance to a query with the Rerank API.
+type Reranker struct {
+	client *cohereclient.Client
+	model  string
+}
+
+// NewReranker returns a new Reranker. The model is set with WithRerankModel.
+func NewReranker(opts ...Option) (*Reranker, error) {
+	c, options, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Reranker{client: c, model: options.rerankModel}, nil
+}
+
+// Rerank returns the topN documents most relevant to the query, the most
+// relevant first, or all the documents if topN is zero. The relevance score
+// of the documents is set in their metadata under RelevanceScoreKey.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []schema.Document, topN int) ([]schema.Document, error) { //nolint:lll
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	results, err := r.client.Rerank(ctx, &cohereclient.RerankRequest{
+		Query:     query,
+		Documents: texts,
+		Model:     r.model,
+		TopN:      topN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]schema.Document, 0, len(results))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(docs) {
+			return nil, ErrUnexpectedResponseLength
+		}
+		doc := docs[result.Index]
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		metadata[RelevanceScoreKey] = result.RelevanceScore
+		reranked = append(reranked, schema.Document{PageContent: doc.PageContent, Metadata: metadata})
+	}
+	return reranked, nil
+}
+
+// RerankRetriever is a retriever reranking the documents of another
+// retriever, e.g. retrieving many candidates from a vector store and keeping
+// the most relevant ones.
+type RerankRetriever struct {
+	Retriever schema.Retriever
+	Reranker  *Reranker
+	// TopN is the number of documents returned, all the documents if zero.
+	TopN int
+}
+
+var _ schema.Retriever = RerankRetriever{}
+
+// NewRerankRetriever returns a retriever reranking the documents of the
+// retriever and returning the topN most relevant.
+func NewRerankRetriever(retriever schema.Retriever, reranker *Reranker, topN int) RerankRetriever {
+	return RerankRetriever{Retriever: retriever, Reranker: reranker, TopN: topN}
+}
+
+// GetRelevantDocuments retrieves the documents and reranks them.
+func (r RerankRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	docs, err := r.Retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.Reranker.Rerank(ctx, query, docs, r.TopN)
+}
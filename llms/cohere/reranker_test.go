@@ -0,0 +1,52 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type fakeRetriever []schema.Document
+
+func (r fakeRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	return r, nil
+}
+
+func TestRerankRetriever(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/rerank", r.URL.Path)
+		var got map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		assert.Equal(t, map[string]any{
+			"query":     "tallest penguin",
+			"documents": []any{"Penguins live in the south.", "Emperor penguins are the tallest."},
+			"model":     "rerank-english-v3.0",
+			"top_n":     float64(1),
+		}, got)
+		fmt.Fprint(w, `{"results":[{"index":1,"relevance_score":0.98}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	reranker, err := NewReranker(WithToken("token"), WithBaseURL(server.URL), WithModel("command-r"))
+	require.NoError(t, err)
+	retriever := NewRerankRetriever(fakeRetriever{
+		{PageContent: "Penguins live in the south."},
+		{PageContent: "Emperor penguins are the tallest.", Metadata: map[string]any{"source": "wiki"}},
+	}, reranker, 1)
+
+	docs, err := retriever.GetRelevantDocuments(context.Background(), "tallest penguin")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{
+		PageContent: "Emperor penguins are the tallest.",
+		Metadata:    map[string]any{"source": "wiki", RelevanceScoreKey: 0.98},
+	}}, docs)
+}
@@ -0,0 +1,30 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrUnsupportedContentPart is returned by the providers when a message has a
+// multimodal part their API does not support, instead of dropping the part.
+var ErrUnsupportedContentPart = errors.New("unsupported content part")
+
+// RequireTextParts returns an error wrapping ErrUnsupportedContentPart if a
+// human message has a part other than a text part. It is used by the
+// providers only supporting text.
+func RequireTextParts(messages []schema.ChatMessage) error {
+	for _, m := range messages {
+		human, ok := m.(schema.HumanChatMessage)
+		if !ok {
+			continue
+		}
+		for _, part := range human.Parts {
+			if _, ok := part.(schema.TextPart); !ok {
+				return fmt.Errorf("%w: %T", ErrUnsupportedContentPart, part)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestRequireTextParts(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, RequireTextParts([]schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Parts: []schema.ContentPart{schema.TextPart{Text: "Hi"}}},
+	}))
+
+	err := RequireTextParts([]schema.ChatMessage{
+		schema.HumanChatMessage{Parts: []schema.ContentPart{schema.ImageURLPart{URL: "https://example.com/cat.png"}}},
+	})
+	require.ErrorIs(t, err, ErrUnsupportedContentPart)
+}
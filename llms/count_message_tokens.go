@@ -0,0 +1,84 @@
+package llms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkoukk/tiktoken-go"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Tokens added by the chat format of OpenAI models, see
+// https://github.com/openai/openai-cookbook/blob/main/examples/How_to_count_tokens_with_tiktoken.ipynb
+const (
+	_tokensPerMessage = 3
+	_tokensPerName    = 1
+	_tokensPerReply   = 3
+)
+
+// MessageTokenCounter is implemented by chat models that count the input
+// tokens of messages themselves, e.g. with an endpoint of the provider. The
+// vertexai models do not implement it, as they are PaLM models without the
+// countTokens endpoint of Gemini.
+type MessageTokenCounter interface {
+	CountTokens(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (int, error)
+}
+
+// CountChatTokens returns the number of input tokens of the messages for the
+// chat model. Models implementing MessageTokenCounter count the tokens
+// themselves, for other models the tokens are counted with CountMessageTokens
+// for the model of the options.
+func CountChatTokens(ctx context.Context, llm ChatLLM, messages []schema.ChatMessage, options ...CallOption) (int, error) { //nolint:lll
+	if counter, ok := llm.(MessageTokenCounter); ok {
+		return counter.CountTokens(ctx, messages, options...)
+	}
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return CountMessageTokens(opts.Model, messages)
+}
+
+// CountMessageTokens returns the number of input tokens of the messages for
+// an OpenAI chat model, counted with tiktoken and including the tokens added
+// by the chat format. Models without a known encoding are counted with the
+// encoding of gpt-4, cl100k_base.
+func CountMessageTokens(model string, messages []schema.ChatMessage) (int, error) {
+	e, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		e, err = tiktoken.GetEncoding("cl100k_base")
+		if err != nil {
+			return 0, fmt.Errorf("get encoding: %w", err)
+		}
+	}
+	return countMessageTokens(messages, func(text string) int {
+		return len(e.Encode(text, nil, nil))
+	}), nil
+}
+
+// countMessageTokens returns the number of tokens of the messages, counting
+// the tokens of each text with the given function.
+func countMessageTokens(messages []schema.ChatMessage, count func(text string) int) int {
+	tokens := _tokensPerReply
+	for _, m := range messages {
+		tokens += _tokensPerMessage + count(string(m.GetType())) + count(m.GetContent())
+		if n, ok := m.(schema.Named); ok && n.GetName() != "" {
+			tokens += _tokensPerName + count(n.GetName())
+		}
+		ai, ok := m.(schema.AIChatMessage)
+		if !ok {
+			continue
+		}
+		calls := ai.ToolCalls
+		if ai.FunctionCall != nil && len(calls) == 0 {
+			calls = []schema.ToolCall{{FunctionCall: ai.FunctionCall}}
+		}
+		for _, call := range calls {
+			if call.FunctionCall == nil {
+				continue
+			}
+			tokens += count(call.FunctionCall.Name) + count(fmt.Sprint(call.FunctionCall.Arguments))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,46 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type countingChatLLM struct {
+	fakeChatLLM
+	tokens int
+}
+
+func (c *countingChatLLM) CountTokens(context.Context, []schema.ChatMessage, ...CallOption) (int, error) {
+	return c.tokens, nil
+}
+
+func TestCountMessageTokensFormat(t *testing.T) {
+	t.Parallel()
+
+	// Count words to test the tokens added by the chat format.
+	words := func(text string) int { return len(strings.Fields(text)) }
+	tokens := countMessageTokens([]schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "What is the weather?"},
+		schema.AIChatMessage{FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city": "Paris"}`}},
+		schema.FunctionChatMessage{Name: "weather", Content: "sunny"},
+	}, words)
+	// 3 for the reply, 3 per message, 1 per role, the words of the contents,
+	// the function call and the name of the function message.
+	assert.Equal(t, 3+4*3+4+(2+4+0+1)+(1+2)+(1+1), tokens)
+}
+
+func TestCountChatTokens(t *testing.T) {
+	t.Parallel()
+
+	tokens, err := CountChatTokens(context.Background(), &countingChatLLM{tokens: 42}, []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Hi"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, tokens)
+}
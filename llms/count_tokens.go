@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/pkoukk/tiktoken-go"
+	"github.com/tmc/langchaingo/llms/models"
 )
 
 const (
@@ -38,14 +39,18 @@ var modelToContextSize = map[string]int{
 	"code-cushman-001": _codeCushman1ContextSize,
 }
 
-// ModelContextSize gets the max number of tokens for a language model. If the model
-// name isn't recognized the default value 2048 is returned.
+// ModelContextSize gets the max number of tokens for a language model, looking
+// up the models registry for the models not listed here. If the model name
+// isn't recognized the default value 2048 is returned.
 func GetModelContextSize(model string) int {
 	contextSize, ok := modelToContextSize[model]
-	if !ok {
-		return _defaultContextSize
+	if ok {
+		return contextSize
 	}
-	return contextSize
+	if m, ok := models.Lookup(model); ok && m.ContextWindow > 0 {
+		return m.ContextWindow
+	}
+	return _defaultContextSize
 }
 
 // CountTokens gets the number of tokens the text contains.
@@ -0,0 +1,79 @@
+package deepseek
+
+import (
+	"errors"
+	"os"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+var (
+	// ErrEmptyResponse is returned when the API returns no choices.
+	ErrEmptyResponse = openaicompat.ErrEmptyResponse
+	ErrMissingToken  = errors.New("missing the DeepSeek API key, set it in the DEEPSEEK_API_KEY environment variable")
+)
+
+// Chat is a chat model of the DeepSeek API. The reasoning of deepseek-reasoner
+// is returned in the generation info under llms.ReasoningKey; it is not sent
+// back with the AI messages of the history, which the API rejects.
+type Chat struct {
+	*openaicompat.Chat
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// LLM is a DeepSeek chat model used with text prompts.
+type LLM struct {
+	*openaicompat.LLM
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// New returns a new DeepSeek LLM.
+func New(opts ...Option) (*LLM, error) {
+	c, err := NewChat(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{openaicompat.NewLLM(c.Chat)}, nil
+}
+
+// NewChat returns a new DeepSeek chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	options := options{
+		token:   os.Getenv(tokenEnvVarName),
+		model:   defaultModel,
+		baseURL: defaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if len(options.token) == 0 {
+		return nil, ErrMissingToken
+	}
+
+	client := openaicompat.New(options.baseURL, options.token, options.httpClient,
+		openaicompat.WithRateLimiter(options.rateLimiter))
+	return &Chat{openaicompat.NewChat(client, openaicompat.Provider{
+		Model: options.model,
+		Extra: options.extra,
+	})}, nil
+}
+
+// extra returns the fields of the DeepSeek API of the requests. DeepSeek does
+// not support seeds.
+func (o options) extra(opts llms.CallOptions) map[string]any {
+	extra := make(map[string]any)
+	if opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil {
+		extra["stream_options"] = map[string]any{"include_usage": true}
+	}
+	return extra
+}
@@ -0,0 +1,59 @@
+package deepseek
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+const (
+	tokenEnvVarName = "DEEPSEEK_API_KEY" //nolint:gosec
+	defaultBaseURL  = "https://api.deepseek.com"
+	defaultModel    = "deepseek-chat"
+)
+
+type options struct {
+	token       string
+	model       string
+	baseURL     string
+	httpClient  openaicompat.Doer
+	rateLimiter *llms.RateLimiter
+}
+
+type Option func(*options)
+
+// WithToken passes the DeepSeek API key to the client. If not set, the key is
+// read from the DEEPSEEK_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel passes the DeepSeek model to the client. Defaults to "deepseek-chat".
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL passes the base url of the DeepSeek API to the client.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client openaicompat.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request. Share the
+// limiter between the clients of the same account.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,73 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatReasoner(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"role":"assistant","reasoning_content":"Paris is "}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"reasoning_content":"the capital."}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"content":"Paris"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":8,"completion_tokens":6,"total_tokens":14,"prompt_cache_hit_tokens":4,`+
+			`"completion_tokens_details":{"reasoning_tokens":5}}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithModel("deepseek-reasoner"))
+	require.NoError(t, err)
+
+	var chunks []string
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Capital of France?"},
+		schema.AIChatMessage{Content: "Paris"},
+		schema.HumanChatMessage{Content: "Again?"},
+	}, llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", msg.Content)
+	assert.Equal(t, []string{"Paris"}, chunks)
+
+	assert.Equal(t, "deepseek-reasoner", got["model"])
+	assert.Equal(t, map[string]any{"include_usage": true}, got["stream_options"])
+	assert.Equal(t, []any{
+		map[string]any{"role": "user", "content": "Capital of France?"},
+		map[string]any{"role": "assistant", "content": "Paris"},
+		map[string]any{"role": "user", "content": "Again?"},
+	}, got["messages"])
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Capital of France?"},
+	}}, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris is the capital.", generations[0].GenerationInfo[llms.ReasoningKey])
+	assert.Equal(t, llms.Usage{
+		PromptTokens: 8, CompletionTokens: 6, TotalTokens: 14, CachedTokens: 4, ReasoningTokens: 5,
+	}, generations[0].Usage)
+}
+
+func TestMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingToken)
+}
@@ -8,6 +8,13 @@
 // 3. OpenAI:            llms/openai/
 // 4. Vertex AI:         llms/vertexai/
 // 5. Cohere:            llms/cohere/
+// 6. Anthropic:         llms/anthropic/
+// 7. Ollama:            llms/ollama/
+// 8. Mistral:           llms/mistral/
+// 9. Groq:              llms/groq/
+// 10. llama.cpp:        llms/llamacpp/
+// 11. xAI:              llms/xai/
+// 12. DeepSeek:         llms/deepseek/
 //
 // Each subpackage includes provider-specific LLM implementations and helper files for communication
 // with supported LLM providers. The internal directories within these subpackages contain provider-specific
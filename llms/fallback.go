@@ -0,0 +1,169 @@
+package llms
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// FallbackIndexKey is the key of the generation info holding the index of
+// the model that served a request of a fallback wrapper: 0 for the primary
+// model and i for the i-th fallback.
+const FallbackIndexKey = "FallbackIndex"
+
+// nolint:gochecknoglobals
+var _fallbackErrorCodes = map[string]bool{
+	"insufficient_quota":       true,
+	"content_filter":           true,
+	"content_policy_violation": true,
+}
+
+// IsFallbackError reports whether a request failing with the error should be
+// sent to the next model: transient errors as reported by IsRetryableError,
+// such as rate limits and overloaded servers, exhausted quotas and requests
+// rejected by the content filter of the provider.
+func IsFallbackError(err error) bool {
+	if IsRetryableError(err) {
+		return true
+	}
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && _fallbackErrorCodes[statusErr.Code]
+}
+
+// FallbackLLM is an LLM sending requests to fallback LLMs when the primary LLM fails.
+type FallbackLLM struct {
+	llms []LLM
+
+	// ShouldFallback reports whether a failed request is sent to the next
+	// LLM. Defaults to IsFallbackError.
+	ShouldFallback func(err error) bool
+}
+
+var (
+	_ LLM           = (*FallbackLLM)(nil)
+	_ LanguageModel = (*FallbackLLM)(nil)
+)
+
+// NewWithFallbacks returns an LLM sending requests to the primary LLM and,
+// if a request fails with a fallback error, to the fallbacks in order. The
+// index of the LLM that served a generation is recorded in its generation
+// info under FallbackIndexKey.
+func NewWithFallbacks(primary LLM, fallbacks ...LLM) *FallbackLLM {
+	return &FallbackLLM{llms: append([]LLM{primary}, fallbacks...)}
+}
+
+// Call calls the LLMs in order until one succeeds.
+func (f *FallbackLLM) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	text, _, err := withFallbacks(ctx, f.llms, f.ShouldFallback, options, func(llm LLM, options []CallOption) (string, error) {
+		return llm.Call(ctx, prompt, options...)
+	})
+	return text, err
+}
+
+// Generate calls the LLMs in order until one succeeds.
+func (f *FallbackLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	generations, index, err := withFallbacks(ctx, f.llms, f.ShouldFallback, options,
+		func(llm LLM, options []CallOption) ([]*Generation, error) {
+			return llm.Generate(ctx, prompts, options...)
+		})
+	recordGenerationInfo(generations, FallbackIndexKey, index)
+	return generations, err
+}
+
+func (f *FallbackLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GeneratePrompt(ctx, f, promptValues, options...)
+}
+
+func (f *FallbackLLM) GetNumTokens(text string) int {
+	return numTokens(f.llms[0], text)
+}
+
+// FallbackChatLLM is a chat LLM sending requests to fallback chat LLMs when
+// the primary chat LLM fails.
+type FallbackChatLLM struct {
+	chats []ChatLLM
+
+	// ShouldFallback reports whether a failed request is sent to the next
+	// chat LLM. Defaults to IsFallbackError.
+	ShouldFallback func(err error) bool
+}
+
+var (
+	_ ChatLLM       = (*FallbackChatLLM)(nil)
+	_ LanguageModel = (*FallbackChatLLM)(nil)
+)
+
+// NewChatWithFallbacks returns a chat LLM sending requests to the primary
+// chat LLM and, if a request fails with a fallback error, to the fallbacks in
+// order. The index of the chat LLM that served a generation is recorded in
+// its generation info under FallbackIndexKey.
+func NewChatWithFallbacks(primary ChatLLM, fallbacks ...ChatLLM) *FallbackChatLLM {
+	return &FallbackChatLLM{chats: append([]ChatLLM{primary}, fallbacks...)}
+}
+
+// Call calls the chat LLMs in order until one succeeds.
+func (f *FallbackChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	msg, _, err := withFallbacks(ctx, f.chats, f.ShouldFallback, options,
+		func(chat ChatLLM, options []CallOption) (*schema.AIChatMessage, error) {
+			return chat.Call(ctx, messages, options...)
+		})
+	return msg, err
+}
+
+// Generate calls the chat LLMs in order until one succeeds.
+func (f *FallbackChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	generations, index, err := withFallbacks(ctx, f.chats, f.ShouldFallback, options,
+		func(chat ChatLLM, options []CallOption) ([]*Generation, error) {
+			return chat.Generate(ctx, messageSets, options...)
+		})
+	recordGenerationInfo(generations, FallbackIndexKey, index)
+	return generations, err
+}
+
+func (f *FallbackChatLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, f, promptValues, options...)
+}
+
+func (f *FallbackChatLLM) GetNumTokens(text string) int {
+	return numTokens(f.chats[0], text)
+}
+
+// withFallbacks calls fn with each of the models until a call succeeds or
+// fails with an error that is not a fallback error, and returns the index of
+// the model of the last call. A streamed call does not fall back once chunks
+// were sent to the streaming funcs. If all models fail the errors are joined.
+func withFallbacks[M, T any](ctx context.Context, models []M, shouldFallback func(error) bool, options []CallOption, fn func(M, []CallOption) (T, error)) (T, int, error) { //nolint:lll
+	if shouldFallback == nil {
+		shouldFallback = IsFallbackError
+	}
+	options, streamed := trackStreaming(options)
+
+	var result T
+	errs := make([]error, 0, len(models))
+	for i, model := range models {
+		var err error
+		result, err = fn(model, options)
+		if err == nil {
+			return result, i, nil
+		}
+		errs = append(errs, err)
+		if *streamed || ctx.Err() != nil || !shouldFallback(err) {
+			return result, i, err
+		}
+	}
+	return result, len(models) - 1, errors.Join(errs...)
+}
+
+// recordGenerationInfo sets the value of the key in the generation info of the generations.
+func recordGenerationInfo(generations []*Generation, key string, value any) {
+	for _, generation := range generations {
+		if generation == nil {
+			continue
+		}
+		if generation.GenerationInfo == nil {
+			generation.GenerationInfo = make(map[string]any, 1)
+		}
+		generation.GenerationInfo[key] = value
+	}
+}
@@ -0,0 +1,105 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// failingChatLLM fails every call with the error.
+type failingChatLLM struct {
+	err   error
+	calls int
+}
+
+func (f *failingChatLLM) Call(context.Context, []schema.ChatMessage, ...CallOption) (*schema.AIChatMessage, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *failingChatLLM) Generate(context.Context, [][]schema.ChatMessage, ...CallOption) ([]*Generation, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func TestIsFallbackError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsFallbackError(&StatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, IsFallbackError(&StatusError{StatusCode: _statusOverloaded, Code: "overloaded_error"}))
+	assert.True(t, IsFallbackError(&StatusError{StatusCode: http.StatusTooManyRequests, Code: "insufficient_quota"}))
+	assert.True(t, IsFallbackError(&StatusError{StatusCode: http.StatusBadRequest, Code: "content_filter"}))
+	assert.False(t, IsFallbackError(&StatusError{StatusCode: http.StatusBadRequest, Code: "invalid_request_error"}))
+	assert.False(t, IsFallbackError(context.Canceled))
+	assert.False(t, IsFallbackError(nil))
+}
+
+func TestFallbackLLM(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	primary := &flakyLLM{errs: []error{&StatusError{StatusCode: http.StatusTooManyRequests}}}
+	fallback := &flakyLLM{}
+	llm := NewWithFallbacks(primary, fallback)
+
+	generations, err := llm.Generate(ctx, []string{"hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", generations[0].Text)
+	assert.Equal(t, 1, generations[0].GenerationInfo[FallbackIndexKey])
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, fallback.calls)
+
+	// The primary LLM serves the request once it recovers.
+	generations, err = llm.Generate(ctx, []string{"hi"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, generations[0].GenerationInfo[FallbackIndexKey])
+	assert.Equal(t, 1, fallback.calls)
+
+	// Errors that are not fallback errors are returned.
+	invalid := &StatusError{StatusCode: http.StatusBadRequest}
+	primary.errs = []error{invalid}
+	_, err = llm.Call(ctx, "hi")
+	assert.ErrorIs(t, err, invalid)
+	assert.Equal(t, 1, fallback.calls)
+
+	// Streamed requests do not fall back.
+	primary.errs = []error{&StatusError{StatusCode: http.StatusServiceUnavailable}}
+	_, err = llm.Call(ctx, "hi", WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.Error(t, err)
+	assert.Equal(t, 1, fallback.calls)
+}
+
+func TestFallbackChatLLM(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	overloaded := &StatusError{StatusCode: _statusOverloaded}
+	rateLimited := &StatusError{StatusCode: http.StatusTooManyRequests}
+	primary := &failingChatLLM{err: overloaded}
+	second := &failingChatLLM{err: rateLimited}
+	third := usageChatLLM{fakeChatLLM: &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "hello"}}}}
+
+	chat := NewChatWithFallbacks(primary, second, third)
+	generations, err := chat.Generate(ctx, [][]schema.ChatMessage{{schema.HumanChatMessage{Content: "hi"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", generations[0].Text)
+	assert.Equal(t, 2, generations[0].GenerationInfo[FallbackIndexKey])
+
+	// The errors of all chat LLMs are returned if they all fail.
+	chat = NewChatWithFallbacks(primary, second)
+	_, err = chat.Call(ctx, []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}})
+	assert.ErrorIs(t, err, overloaded)
+	assert.ErrorIs(t, err, rateLimited)
+
+	// ShouldFallback selects the errors that fall back.
+	chat.ShouldFallback = func(err error) bool { return !errors.Is(err, overloaded) }
+	calls := second.calls
+	_, err = chat.Call(ctx, []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}})
+	assert.ErrorIs(t, err, overloaded)
+	assert.Equal(t, calls, second.calls)
+}
@@ -0,0 +1,167 @@
+// Package finetuning defines the management of the fine-tuning jobs of the
+// providers: uploading training files, creating, polling and cancelling jobs,
+// and listing the fine-tuned models.
+//
+// The providers implement Provider, e.g. openai.NewFineTuning:
+//
+//	provider, err := openai.NewFineTuning()
+//	fileID, err := provider.UploadFile(ctx, "train.jsonl", file)
+//	job, err := provider.CreateJob(ctx, finetuning.JobRequest{
+//		Model:        "gpt-4o-mini-2024-07-18",
+//		TrainingFile: fileID,
+//	})
+//	job, err = finetuning.Wait(ctx, provider, job.ID, time.Minute)
+//	llm, err := openai.NewChat(openai.WithModel(job.FineTunedModel))
+package finetuning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrJobFailed is returned by Wait when a job fails or is cancelled.
+var ErrJobFailed = errors.New("fine-tuning job failed")
+
+// Provider manages the fine-tuning jobs of a provider.
+type Provider interface {
+	// UploadFile uploads a training or validation file and returns its id.
+	UploadFile(ctx context.Context, name string, data io.Reader) (string, error)
+	// CreateJob creates a fine-tuning job.
+	CreateJob(ctx context.Context, req JobRequest) (*Job, error)
+	// GetJob returns the job of the id.
+	GetJob(ctx context.Context, id string) (*Job, error)
+	// CancelJob cancels the job of the id.
+	CancelJob(ctx context.Context, id string) (*Job, error)
+	// ListJobs lists the jobs, the most recent first.
+	ListJobs(ctx context.Context) ([]Job, error)
+	// ListModels lists the fine-tuned models.
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// JobRequest is a request to create a fine-tuning job.
+type JobRequest struct {
+	// Model is the base model to fine-tune.
+	Model string
+	// TrainingFile is the id of the uploaded training file.
+	TrainingFile string
+	// ValidationFile is the id of the uploaded validation file, if any.
+	ValidationFile string
+	// Suffix is added to the name of the fine-tuned model.
+	Suffix string
+	// Seed makes the job reproducible, if set.
+	Seed *int
+	// Hyperparameters are the hyperparameters of the job, chosen by the
+	// provider if zero.
+	Hyperparameters Hyperparameters
+}
+
+// Hyperparameters are the hyperparameters of a fine-tuning job.
+type Hyperparameters struct {
+	Epochs                 int
+	BatchSize              int
+	LearningRateMultiplier float64
+}
+
+// Status is the status of a fine-tuning job.
+type Status string
+
+const (
+	StatusValidatingFiles Status = "validating_files"
+	StatusQueued          Status = "queued"
+	StatusRunning         Status = "running"
+	StatusSucceeded       Status = "succeeded"
+	StatusFailed          Status = "failed"
+	StatusCancelled       Status = "cancelled"
+)
+
+// Done reports whether the job of the status is finished.
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Job is a fine-tuning job.
+type Job struct {
+	ID     string
+	Model  string
+	Status Status
+	// FineTunedModel is the name of the fine-tuned model, set once the job
+	// succeeded.
+	FineTunedModel string
+	TrainingFile   string
+	ValidationFile string
+	// TrainedTokens is the number of billed tokens, set once the job
+	// succeeded.
+	TrainedTokens int
+	CreatedAt     time.Time
+	// FinishedAt is zero until the job is finished.
+	FinishedAt time.Time
+	// Error is the error of a failed job.
+	Error string
+}
+
+// Wait polls the job of the id at the interval until it is finished. It
+// returns ErrJobFailed if the job failed or was cancelled.
+func Wait(ctx context.Context, provider Provider, id string, interval time.Duration) (*Job, error) {
+	for {
+		job, err := provider.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch job.Status {
+		case StatusSucceeded:
+			return job, nil
+		case StatusFailed, StatusCancelled:
+			msg := fmt.Sprintf("job %s is %s", job.ID, job.Status)
+			if job.Error != "" {
+				msg += ": " + job.Error
+			}
+			return job, fmt.Errorf("%w: %s", ErrJobFailed, msg)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// EncodeChatExamples writes the conversations as a JSONL training file in the
+// chat format, one conversation per line. The conversations contain system,
+// human and AI messages.
+func EncodeChatExamples(w io.Writer, examples [][]schema.ChatMessage) error {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	encoder := json.NewEncoder(w)
+	for i, example := range examples {
+		messages := make([]message, 0, len(example))
+		for _, m := range example {
+			var role string
+			switch m.GetType() {
+			case schema.ChatMessageTypeSystem:
+				role = "system"
+			case schema.ChatMessageTypeHuman:
+				role = "user"
+			case schema.ChatMessageTypeAI:
+				role = "assistant"
+			default:
+				return fmt.Errorf("example %d: %w: %s", i, schema.ErrUnexpectedChatMessageType, m.GetType())
+			}
+			messages = append(messages, message{Role: role, Content: m.GetContent()})
+		}
+		if err := encoder.Encode(map[string]any{"messages": messages}); err != nil {
+			return fmt.Errorf("encode example %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package finetuning
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// fakeProvider returns the statuses of a job in order.
+type fakeProvider struct {
+	statuses []Status
+	polls    int
+}
+
+func (p *fakeProvider) UploadFile(context.Context, string, io.Reader) (string, error) { return "", nil }
+func (p *fakeProvider) CreateJob(context.Context, JobRequest) (*Job, error)           { return nil, nil }
+func (p *fakeProvider) CancelJob(context.Context, string) (*Job, error)               { return nil, nil }
+func (p *fakeProvider) ListJobs(context.Context) ([]Job, error)                       { return nil, nil }
+func (p *fakeProvider) ListModels(context.Context) ([]string, error)                  { return nil, nil }
+
+func (p *fakeProvider) GetJob(_ context.Context, id string) (*Job, error) {
+	status := p.statuses[p.polls]
+	p.polls++
+	return &Job{ID: id, Status: status, Error: "bad file"}, nil
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeProvider{statuses: []Status{StatusQueued, StatusRunning, StatusSucceeded}}
+	job, err := Wait(context.Background(), provider, "job-1", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, job.Status)
+	assert.Equal(t, 3, provider.polls)
+
+	provider = &fakeProvider{statuses: []Status{StatusFailed}}
+	_, err = Wait(context.Background(), provider, "job-1", time.Millisecond)
+	require.ErrorIs(t, err, ErrJobFailed)
+	assert.Contains(t, err.Error(), "job job-1 is failed: bad file")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	provider = &fakeProvider{statuses: []Status{StatusRunning}}
+	_, err = Wait(ctx, provider, "job-1", time.Hour)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStatusDone(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, StatusRunning.Done())
+	assert.True(t, StatusSucceeded.Done())
+	assert.True(t, StatusCancelled.Done())
+}
+
+func TestEncodeChatExamples(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := EncodeChatExamples(&buf, [][]schema.ChatMessage{
+		{
+			schema.SystemChatMessage{Content: "Be brief."},
+			schema.HumanChatMessage{Content: "Hi"},
+			schema.AIChatMessage{Content: "Hello"},
+		},
+		{schema.HumanChatMessage{Content: "Bye"}, schema.AIChatMessage{Content: "Bye"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello"}]}`+"\n"+ //nolint:lll
+			`{"messages":[{"role":"user","content":"Bye"},{"role":"assistant","content":"Bye"}]}`+"\n",
+		buf.String())
+
+	err = EncodeChatExamples(&buf, [][]schema.ChatMessage{{schema.FunctionChatMessage{Name: "f"}}})
+	require.ErrorIs(t, err, schema.ErrUnexpectedChatMessageType)
+}
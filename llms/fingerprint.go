@@ -0,0 +1,17 @@
+package llms
+
+// SystemFingerprintKey is the key of the system fingerprint in the
+// generation info.
+const SystemFingerprintKey = "SystemFingerprint"
+
+// SystemFingerprint returns the fingerprint of the backend configuration of
+// the provider that served the generation, or an empty string if the
+// provider does not report it. Generations sampled with the same seed are
+// only expected to be identical when their fingerprints match.
+func (g *Generation) SystemFingerprint() string {
+	if g == nil {
+		return ""
+	}
+	fingerprint, _ := g.GenerationInfo[SystemFingerprintKey].(string)
+	return fingerprint
+}
@@ -0,0 +1,88 @@
+package groq
+
+import (
+	"errors"
+	"os"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+var (
+	// ErrEmptyResponse is returned when the API returns no choices.
+	ErrEmptyResponse = openaicompat.ErrEmptyResponse
+	ErrMissingToken  = errors.New("missing the Groq API key, set it in the GROQ_API_KEY environment variable")
+)
+
+// Chat is a chat model of the Groq API.
+type Chat struct {
+	*openaicompat.Chat
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// LLM is a Groq chat model used with text prompts.
+type LLM struct {
+	*openaicompat.LLM
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// New returns a new Groq LLM.
+func New(opts ...Option) (*LLM, error) {
+	c, err := NewChat(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{openaicompat.NewLLM(c.Chat)}, nil
+}
+
+// NewChat returns a new Groq chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	options := options{
+		token:   os.Getenv(tokenEnvVarName),
+		model:   defaultModel,
+		baseURL: defaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if len(options.token) == 0 {
+		return nil, ErrMissingToken
+	}
+
+	client := openaicompat.New(options.baseURL, options.token, options.httpClient,
+		openaicompat.WithRateLimiter(options.rateLimiter))
+	return &Chat{openaicompat.NewChat(client, openaicompat.Provider{
+		Model:  options.model,
+		Limits: &samplingLimits,
+		Extra:  options.extra,
+	})}, nil
+}
+
+// samplingLimits are the limits of the API on the sampling options.
+// Groq does not support logit biases.
+var samplingLimits = llms.SamplingLimits{ //nolint:gochecknoglobals
+	MaxStopWords: 4,
+	MinPenalty:   -2,
+	MaxPenalty:   2,
+}
+
+// extra returns the fields of the Groq API of the requests.
+func (o options) extra(opts llms.CallOptions) map[string]any {
+	extra := make(map[string]any)
+	if o.serviceTier != "" {
+		extra["service_tier"] = o.serviceTier
+	}
+	if opts.SeedSet {
+		extra["seed"] = opts.Seed
+	}
+	return extra
+}
@@ -0,0 +1,68 @@
+package groq
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+const (
+	tokenEnvVarName = "GROQ_API_KEY" //nolint:gosec
+	defaultBaseURL  = "https://api.groq.com/openai/v1"
+	defaultModel    = "llama3-8b-8192"
+)
+
+type options struct {
+	token       string
+	model       string
+	baseURL     string
+	httpClient  openaicompat.Doer
+	rateLimiter *llms.RateLimiter
+	serviceTier string
+}
+
+type Option func(*options)
+
+// WithToken passes the Groq API key to the client. If not set, the key is
+// read from the GROQ_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel passes the Groq model to the client. Defaults to "llama3-8b-8192".
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL passes the base url of the Groq API to the client.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client openaicompat.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request. Share the
+// limiter between the clients of the same account.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
+
+// WithServiceTier sets the service tier of the requests: "on_demand", "flex"
+// or "auto". Defaults to the tier of the organization.
+func WithServiceTier(serviceTier string) Option {
+	return func(opts *options) {
+		opts.serviceTier = serviceTier
+	}
+}
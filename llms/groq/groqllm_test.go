@@ -0,0 +1,203 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestLLMStreaming(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"role":"assistant","content":""}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"content":"Fast"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"content":" answer"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{},"finish_reason":"stop"}],`+
+			`"x_groq":{"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL), WithServiceTier("flex"), WithModel("mixtral"))
+	require.NoError(t, err)
+
+	var chunks []string
+	generations, err := llm.Generate(context.Background(), []string{"Hi"},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Fast answer", generations[0].Text)
+	assert.Equal(t, []string{"Fast", " answer"}, chunks)
+	assert.Equal(t, 5, generations[0].GenerationInfo["TotalTokens"])
+	assert.Equal(t, "stop", generations[0].GenerationInfo["FinishReason"])
+
+	assert.Equal(t, "mixtral", got["model"])
+	assert.Equal(t, true, got["stream"])
+	assert.Equal(t, "flex", got["service_tier"])
+}
+
+func TestChatStreamingToolCalls(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"role":"assistant","tool_calls":[`+
+			`{"index":0,"id":"call1","type":"function","function":{"name":"weather","arguments":"{\"city\":"}}]}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"tool_calls":[`+
+			`{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{},"finish_reason":"tool_calls"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.ToolCallChunk
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Weather in Paris?"},
+	}}, llms.WithTools([]llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{Name: "weather"}}}),
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk.ToolCalls...)
+			return nil
+		}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, []schema.ToolCall{{
+		ID:           "call1",
+		Type:         "function",
+		FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}, generations[0].Message.ToolCalls)
+	assert.Len(t, chunks, 2)
+}
+
+func TestChatStreamingLargeChunk(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("a", 256<<10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, `data: {"id":"1","choices":[{"delta":{"content":%q},"finish_reason":"stop"}]}`+"\n\n", content)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	completion, err := llm.Call(context.Background(), "Hi",
+		llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.NoError(t, err)
+	assert.Equal(t, content, completion)
+}
+
+func TestLLMStreamingError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"content":"Fast"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"error":{"message":"Service unavailable","type":"internal_server_error"}}`+"\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Generate(context.Background(), []string{"Hi"},
+		llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Fast", streamErr.Partial)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "Service unavailable", statusErr.Message)
+}
+
+func TestSeed(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"4"}}],"system_fingerprint":"fp_179b0f92c9"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"2+2?"}, llms.WithSeed(42))
+	require.NoError(t, err)
+	assert.Equal(t, float64(42), got["seed"])
+	assert.Equal(t, "fp_179b0f92c9", generations[0].SystemFingerprint())
+
+	got = nil
+	_, err = llm.Generate(context.Background(), []string{"2+2?"}, llms.WithSeed(0))
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), got["seed"])
+}
+
+func TestSamplingLimits(t *testing.T) {
+	t.Parallel()
+
+	llm, err := New(WithToken("token"), WithBaseURL("http://localhost:0"))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "Hi", llms.WithStopWords([]string{"a", "b", "c", "d", "e"}))
+	require.ErrorIs(t, err, llms.ErrInvalidCallOption)
+	_, err = llm.Call(context.Background(), "Hi", llms.WithLogitBias(map[string]int{"1": 1}))
+	require.ErrorIs(t, err, llms.ErrInvalidCallOption)
+}
+
+func TestChatImages(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"A cat"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{
+		Content: "What is this?",
+		Parts: []schema.ContentPart{
+			schema.ImageURLPart{URL: "https://example.com/cat.png"},
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte{1, 2}},
+		},
+	}})
+	require.NoError(t, err)
+	messages, ok := got["messages"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{
+		map[string]any{"type": "text", "text": "What is this?"},
+		map[string]any{"type": "image_url", "image_url": map[string]any{"url": "https://example.com/cat.png"}},
+		map[string]any{"type": "image_url", "image_url": map[string]any{"url": "data:image/png;base64,AQI="}},
+	}, messages[0].(map[string]any)["content"])
+
+	_, err = llm.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{
+		Parts: []schema.ContentPart{schema.FilePart{Filename: "a.pdf", MIMEType: "application/pdf"}},
+	}})
+	require.ErrorIs(t, err, llms.ErrUnsupportedContentPart)
+}
@@ -16,8 +16,11 @@ var (
 	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
 )
 
+// LLM is a text generation model of the Hugging Face Inference API or of a
+// Text Generation Inference server.
 type LLM struct {
-	client *huggingfaceclient.Client
+	client  *huggingfaceclient.Client
+	options options
 }
 
 var (
@@ -36,29 +39,51 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return r[0].Text, nil
 }
 
+// Generate requests a completion for each of the prompts.
 func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
-	opts := &llms.CallOptions{Model: defaultModel}
+	opts := llms.CallOptions{}
 	for _, opt := range options {
-		opt(opts)
-	}
-	result, err := o.client.RunInference(ctx, &huggingfaceclient.InferenceRequest{
-		Model:             o.client.Model,
-		Prompt:            prompts[0],
-		Task:              huggingfaceclient.InferenceTaskTextGeneration,
-		Temperature:       opts.Temperature,
-		TopP:              opts.TopP,
-		TopK:              opts.TopK,
-		MinLength:         opts.MinLength,
-		MaxLength:         opts.MaxLength,
-		RepetitionPenalty: opts.RepetitionPenalty,
-		Seed:              opts.Seed,
-	})
-	if err != nil {
+		opt(&opts)
+	}
+	if err := samplingLimits.Validate(opts); err != nil {
 		return nil, err
 	}
-	return []*llms.Generation{
-		{Text: result.Text},
-	}, nil
+
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		req := o.options.textGenerationRequest(prompt, opts)
+
+		var text string
+		var details huggingfaceclient.TextGenerationDetails
+		err := o.client.TextGeneration(ctx, req, func(resp huggingfaceclient.TextGenerationResponse) error {
+			if resp.Details != nil {
+				details = *resp.Details
+			}
+			if !req.Stream {
+				text = resp.GeneratedText
+				return nil
+			}
+			var content string
+			if resp.Token != nil && !resp.Token.Special {
+				content = resp.Token.Text
+				text += content
+			}
+			return llms.SendStreamChunk(ctx, opts, llms.StreamChunk{Content: content, FinishReason: details.FinishReason})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		generations = append(generations, &llms.Generation{
+			Text: text,
+			GenerationInfo: map[string]any{
+				"CompletionTokens": details.GeneratedTokens,
+				"FinishReason":     details.FinishReason,
+			},
+			Usage: llms.Usage{CompletionTokens: details.GeneratedTokens},
+		})
+	}
+	return generations, nil
 }
 
 func (o *LLM) GeneratePrompt(ctx context.Context, prompts []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
@@ -70,27 +95,69 @@ func (o *LLM) GetNumTokens(text string) int {
 }
 
 func New(opts ...Option) (*LLM, error) {
-	options := &options{
+	options, c, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{
+		client:  c,
+		options: options,
+	}, nil
+}
+
+func newClient(opts ...Option) (options, *huggingfaceclient.Client, error) {
+	options := options{
 		token: os.Getenv(tokenEnvVarName),
 		model: defaultModel,
 	}
 
 	for _, opt := range opts {
-		opt(options)
+		opt(&options)
 	}
 
-	if len(options.token) == 0 {
-		return nil, ErrMissingToken
+	if len(options.token) == 0 && options.endpoint == "" {
+		return options, nil, ErrMissingToken
 	}
 
-	c, err := huggingfaceclient.New(options.token, options.model)
-	if err != nil {
-		return nil, err
+	clientOpts := []huggingfaceclient.Option{huggingfaceclient.WithEndpoint(options.endpoint)}
+	if options.httpClient != nil {
+		clientOpts = append(clientOpts, huggingfaceclient.WithHTTPClient(options.httpClient))
 	}
+	c, err := huggingfaceclient.New(options.token, options.model, clientOpts...)
+	return options, c, err
+}
 
-	return &LLM{
-		client: c,
-	}, nil
+// textGenerationRequest returns the request for the prompt with the call options.
+func (o options) textGenerationRequest(prompt string, opts llms.CallOptions) *huggingfaceclient.TextGenerationRequest {
+	req := &huggingfaceclient.TextGenerationRequest{
+		Inputs: prompt,
+		Parameters: huggingfaceclient.TextGenerationParameters{
+			MaxNewTokens:      opts.MaxTokens,
+			Temperature:       opts.Temperature,
+			TopK:              opts.TopK,
+			TopP:              opts.TopP,
+			TypicalP:          o.typicalP,
+			RepetitionPenalty: opts.RepetitionPenalty,
+			FrequencyPenalty:  opts.FrequencyPenalty,
+			Stop:              opts.StopWords,
+			DoSample:          o.doSample,
+			Details:           true,
+			Truncate:          o.truncate,
+			Watermark:         o.watermark,
+		},
+		Stream: opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil,
+	}
+	if opts.SeedSet {
+		req.Parameters.Seed = &opts.Seed
+	}
+	if opts.ResponseFormat.IsJSON() {
+		var schema any = map[string]any{"type": "object"}
+		if opts.ResponseFormat.JSONSchema != nil && opts.ResponseFormat.JSONSchema.Schema != nil {
+			schema = opts.ResponseFormat.JSONSchema.Schema
+		}
+		req.Parameters.Grammar = &huggingfaceclient.Grammar{Type: "json", Value: schema}
+	}
+	return req
 }
 
 // CreateEmbedding creates embeddings for the given input texts.
@@ -0,0 +1,87 @@
+package huggingface
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Chat is a chat model of the Hugging Face Inference API or of a Text
+// Generation Inference server. The messages are formatted with the chat
+// template of the model by the server.
+type Chat struct {
+	client  *openaicompat.Client
+	options options
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat returns a new Hugging Face chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	options, c, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Chat{
+		client:  openaicompat.New(c.ModelURL()+"/v1", options.token, options.httpClient),
+		options: options,
+	}, nil
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// samplingLimits are the limits of the API on the sampling options.
+// Text Generation Inference does not support logit biases.
+var samplingLimits = llms.SamplingLimits{ //nolint:gochecknoglobals
+	MinPenalty: -2,
+	MaxPenalty: 2,
+}
+
+// Generate requests a chat response for each of the message sets.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	extra := make(map[string]any)
+	if opts.SeedSet {
+		extra["seed"] = opts.Seed
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		generation, err := openaicompat.GenerateChat(ctx, o.client, o.options.model, messageSet, opts, extra)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, generation)
+	}
+	return generations, nil
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
+
+func (o *Chat) GetNumTokens(text string) int {
+	return llms.CountTokens("gpt2", text)
+}
@@ -1,13 +1,21 @@
 package huggingface
 
+import "github.com/tmc/langchaingo/llms/huggingface/internal/huggingfaceclient"
+
 const (
 	tokenEnvVarName = "HUGGINGFACEHUB_API_TOKEN"
 	defaultModel    = "gpt2"
 )
 
 type options struct {
-	token string
-	model string
+	token      string
+	model      string
+	endpoint   string
+	httpClient huggingfaceclient.Doer
+	typicalP   float64
+	truncate   int
+	watermark  bool
+	doSample   bool
 }
 
 type Option func(*options)
@@ -27,3 +35,50 @@ func WithModel(model string) Option {
 		opts.model = model
 	}
 }
+
+// WithEndpoint sends the requests to a Text Generation Inference server or an
+// Inference Endpoint, e.g. http://localhost:8080, instead of the Inference
+// API. The token is optional for an endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(opts *options) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client huggingfaceclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithTypicalP enables typical decoding with the given mass, see
+// https://arxiv.org/abs/2202.00666.
+func WithTypicalP(typicalP float64) Option {
+	return func(opts *options) {
+		opts.typicalP = typicalP
+	}
+}
+
+// WithTruncate truncates the prompts to their last tokens.
+func WithTruncate(tokens int) Option {
+	return func(opts *options) {
+		opts.truncate = tokens
+	}
+}
+
+// WithWatermark watermarks the generated text, see
+// https://arxiv.org/abs/2301.10226.
+func WithWatermark() Option {
+	return func(opts *options) {
+		opts.watermark = true
+	}
+}
+
+// WithDoSample samples the tokens instead of greedy decoding. It is implied
+// by a temperature, top_k, top_p or typical_p.
+func WithDoSample() Option {
+	return func(opts *options) {
+		opts.doSample = true
+	}
+}
@@ -0,0 +1,195 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func newTestServer(t *testing.T, requests chan<- map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+
+		switch {
+		case r.URL.Path == "/v1/chat/completions":
+			fmt.Fprint(w, `{
+				"choices":[{"message":{"role":"assistant","content":"Hi there"},"finish_reason":"stop"}],
+				"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}
+			}`)
+		case req["inputs"] == "fail":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error":"Input validation error: inputs must have less than 1024 tokens","error_type":"validation"}`)
+		case req["stream"] == true:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data:{\"token\":{\"id\":1,\"text\":\"Hello\",\"special\":false},\"generated_text\":null}\n\n")
+			switch req["inputs"] {
+			case "error event":
+				fmt.Fprint(w, "data:{\"error\":\"Request failed during generation\",\"error_type\":\"generation\"}\n\n")
+				return
+			case "cut off":
+				return
+			}
+			fmt.Fprint(w, "data:{\"token\":{\"id\":2,\"text\":\" world\",\"special\":false},\"generated_text\":null}\n\n")
+			fmt.Fprint(w, "data:{\"token\":{\"id\":3,\"text\":\"</s>\",\"special\":true},\"generated_text\":\"Hello world\","+
+				"\"details\":{\"finish_reason\":\"eos_token\",\"generated_tokens\":3}}\n\n")
+		default:
+			fmt.Fprint(w, `[{"generated_text":"Hello world","details":{"finish_reason":"length","generated_tokens":2}}]`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithEndpoint(server.URL), WithTypicalP(0.9), WithTruncate(512))
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"Say hello"},
+		llms.WithMaxTokens(2), llms.WithTopK(10), llms.WithStopWords([]string{"\n"}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, "length", generations[0].GenerationInfo["FinishReason"])
+	assert.Equal(t, 2, generations[0].Usage.CompletionTokens)
+
+	req := <-requests
+	assert.Equal(t, "Say hello", req["inputs"])
+	assert.Equal(t, map[string]any{
+		"max_new_tokens":   2.0,
+		"top_k":            10.0,
+		"typical_p":        0.9,
+		"stop":             []any{"\n"},
+		"return_full_text": false,
+		"details":          true,
+		"truncate":         512.0,
+	}, req["parameters"])
+}
+
+func TestGenerateStreaming(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithEndpoint(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	text, err := llm.Call(context.Background(), "Say hello",
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello world", text)
+	assert.Equal(t, []llms.StreamChunk{
+		{Content: "Hello"},
+		{Content: " world"},
+		{FinishReason: "eos_token"},
+	}, chunks)
+	assert.Equal(t, true, (<-requests)["stream"])
+}
+
+func TestGenerateStreamInterrupted(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 2)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithEndpoint(server.URL))
+	require.NoError(t, err)
+	stream := llms.WithStreamingFunc(func(context.Context, []byte) error { return nil })
+
+	_, err = llm.Call(context.Background(), "error event", stream)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+	assert.Contains(t, err.Error(), "Request failed during generation")
+
+	_, err = llm.Call(context.Background(), "cut off", stream)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithEndpoint(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "Answer in JSON", llms.WithResponseFormat(llms.JSONMode))
+	require.NoError(t, err)
+
+	parameters, ok := (<-requests)["parameters"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"type": "json", "value": map[string]any{"type": "object"}}, parameters["grammar"])
+}
+
+func TestGenerateError(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithEndpoint(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "fail")
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusErr.StatusCode)
+	assert.Contains(t, err.Error(), "inputs must have less than 1024 tokens")
+}
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	chat, err := NewChat(WithEndpoint(server.URL), WithModel("tgi"))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.HumanChatMessage{Content: "Hi"},
+	}, llms.WithSeed(42))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi there", msg.Content)
+
+	req := <-requests
+	assert.Equal(t, "tgi", req["model"])
+	assert.Equal(t, 42.0, req["seed"])
+	assert.Len(t, req["messages"], 2)
+}
+
+func TestMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingToken)
+}
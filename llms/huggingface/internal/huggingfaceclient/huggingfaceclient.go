@@ -4,30 +4,72 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 var (
 	ErrInvalidToken  = errors.New("invalid token")
 	ErrEmptyResponse = errors.New("empty response")
+	// ErrTextGeneration is returned when a streaming generation fails.
+	ErrTextGeneration = errors.New("text generation error")
 )
 
 const huggingfaceAPIBaseURL = "https://api-inference.huggingface.co"
 
 type Client struct {
-	Token string
-	Model string
-	url   string
+	Token      string
+	Model      string
+	url        string
+	endpoint   string
+	httpClient Doer
 }
 
-func New(token string, model string) (*Client, error) {
-	if token == "" {
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Option is an option of the client.
+type Option func(*Client)
+
+// WithEndpoint sends the requests to a Text Generation Inference server or an
+// Inference Endpoint instead of the Inference API. The token is optional.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) {
+		c.endpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sets the http client used for the requests.
+func WithHTTPClient(httpClient Doer) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func New(token string, model string, opts ...Option) (*Client, error) {
+	c := &Client{
+		Token:      token,
+		Model:      model,
+		url:        huggingfaceAPIBaseURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if token == "" && c.endpoint == "" {
 		return nil, ErrInvalidToken
 	}
-	return &Client{
-		Token: token,
-		Model: model,
-		url:   huggingfaceAPIBaseURL,
-	}, nil
+	return c, nil
+}
+
+// ModelURL returns the url serving the model, the endpoint if set.
+func (c *Client) ModelURL() string {
+	if c.endpoint != "" {
+		return c.endpoint
+	}
+	return c.url + "/models/" + c.Model
 }
 
 type InferenceRequest struct {
@@ -40,7 +82,7 @@ type InferenceRequest struct {
 	MinLength         int           `json:"min_length,omitempty"`
 	MaxLength         int           `json:"max_length,omitempty"`
 	RepetitionPenalty float64       `json:"repetition_penalty,omitempty"`
-	Seed              int           `json:"seed,omitempty"`
+	Seed              *int          `json:"seed,omitempty"`
 }
 
 type InferenceResponse struct {
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 var ErrUnexpectedStatusCode = errors.New("unexpected status code")
@@ -33,7 +35,7 @@ type parameters struct {
 	MinLength         int     `json:"min_length,omitempty"`
 	MaxLength         int     `json:"max_length,omitempty"`
 	RepetitionPenalty float64 `json:"repetition_penalty,omitempty"`
-	Seed              int     `json:"seed,omitempty"`
+	Seed              *int    `json:"seed,omitempty"`
 }
 
 type (
@@ -55,6 +57,7 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 	}
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 	req.Header.Set("Content-Type", "application/json")
+	llms.SetRequestHeaders(req)
 
 	// debug print the http request with httputil:
 
@@ -64,7 +67,7 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 	// }
 	// fmt.Fprintf(os.Stderr, "%s", reqDump)
 
-	r, err := http.DefaultClient.Do(req)
+	r, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,208 @@
+package huggingfaceclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TextGenerationRequest is a request to the text generation task of the
+// Inference API or a Text Generation Inference server. See
+// https://huggingface.github.io/text-generation-inference/.
+type TextGenerationRequest struct {
+	Inputs     string                   `json:"inputs"`
+	Parameters TextGenerationParameters `json:"parameters"`
+	Stream     bool                     `json:"stream,omitempty"`
+}
+
+// TextGenerationParameters are the generation parameters of a request.
+type TextGenerationParameters struct {
+	MaxNewTokens      int      `json:"max_new_tokens,omitempty"`
+	Temperature       float64  `json:"temperature,omitempty"`
+	TopK              int      `json:"top_k,omitempty"`
+	TopP              float64  `json:"top_p,omitempty"`
+	TypicalP          float64  `json:"typical_p,omitempty"`
+	RepetitionPenalty float64  `json:"repetition_penalty,omitempty"`
+	FrequencyPenalty  float64  `json:"frequency_penalty,omitempty"`
+	Seed              *int     `json:"seed,omitempty"`
+	Stop              []string `json:"stop,omitempty"`
+	DoSample          bool     `json:"do_sample,omitempty"`
+	// ReturnFullText prepends the prompt to the generated text.
+	ReturnFullText bool `json:"return_full_text"`
+	// Details returns the finish reason and the number of generated tokens.
+	Details bool `json:"details,omitempty"`
+	// Truncate truncates the prompt to its last tokens.
+	Truncate  int      `json:"truncate,omitempty"`
+	Watermark bool     `json:"watermark,omitempty"`
+	Grammar   *Grammar `json:"grammar,omitempty"`
+}
+
+// Grammar constrains the generated text, either to a JSON schema with the
+// type "json" or to a regular expression with the type "regex".
+type Grammar struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// TextGenerationDetails are the details of a generation.
+type TextGenerationDetails struct {
+	// FinishReason is one of "length", "eos_token" or "stop_sequence".
+	FinishReason    string `json:"finish_reason"`
+	GeneratedTokens int    `json:"generated_tokens"`
+	Seed            *int64 `json:"seed,omitempty"`
+}
+
+// TextGenerationResponse is a response, or a chunk of a streaming response,
+// of the text generation task. The chunks of a streaming response only have
+// the generated text and the details in the last chunk.
+type TextGenerationResponse struct {
+	Token         *Token                 `json:"token,omitempty"`
+	GeneratedText string                 `json:"generated_text"`
+	Details       *TextGenerationDetails `json:"details,omitempty"`
+}
+
+// Token is a generated token of a streaming response.
+type Token struct {
+	ID      int     `json:"id"`
+	Text    string  `json:"text"`
+	LogProb float64 `json:"logprob"`
+	// Special tokens, e.g. the end of sequence token, are not part of the text.
+	Special bool `json:"special"`
+}
+
+// TextGenerationResponseFunc is called for each chunk of a streaming text
+// generation response.
+type TextGenerationResponseFunc func(TextGenerationResponse) error
+
+type textGenerationError struct {
+	// Error is a string, or a list of strings for validation errors.
+	Error     json.RawMessage `json:"error"`
+	ErrorType string          `json:"error_type"`
+}
+
+func (e textGenerationError) message() string {
+	var msg string
+	if json.Unmarshal(e.Error, &msg) == nil {
+		return msg
+	}
+	var msgs []string
+	if json.Unmarshal(e.Error, &msgs) == nil {
+		return strings.Join(msgs, "; ")
+	}
+	return string(e.Error)
+}
+
+// TextGeneration generates the text for the inputs of the request. The
+// function is called for every chunk if the request is streaming, otherwise
+// once with the full response. If the stream is interrupted, by the context,
+// the connection or an error event, it returns a *llms.StreamError holding
+// the text streamed so far.
+func (c *Client) TextGeneration(ctx context.Context, request *TextGenerationRequest, fn TextGenerationResponseFunc) error { //nolint:lll
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ModelURL(), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	llms.SetRequestHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp textGenerationError
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
+		return fmt.Errorf("%w: %w", ErrUnexpectedStatusCode,
+			llms.NewStatusError(r, errResp.ErrorType, errResp.message()))
+	}
+
+	if request.Stream {
+		return parseTextGenerationStream(ctx, r.Body, fn)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	// The Inference API returns a list with a single generation.
+	var responses []TextGenerationResponse
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &responses); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	} else {
+		var resp TextGenerationResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) == 0 {
+		return ErrEmptyResponse
+	}
+	return fn(responses[0])
+}
+
+func parseTextGenerationStream(ctx context.Context, body io.ReadCloser, fn TextGenerationResponseFunc) error {
+	stop := llms.CloseOnCancel(ctx, body)
+	defer stop()
+	var partial strings.Builder
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, partial.String(), err)
+	}
+
+	scanner := bufio.NewScanner(body)
+	done := false
+	for !done && ctx.Err() == nil && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := []byte(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+
+		var errResp textGenerationError
+		if err := json.Unmarshal(data, &errResp); err != nil {
+			return fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if msg := errResp.message(); msg != "" {
+			return interrupted(fmt.Errorf("%w: %w", ErrTextGeneration,
+				&llms.StatusError{Code: errResp.ErrorType, Message: msg}))
+		}
+
+		var resp TextGenerationResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if resp.Token != nil && !resp.Token.Special {
+			partial.WriteString(resp.Token.Text)
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+		// The last chunk has the generated text and the details.
+		done = resp.GeneratedText != "" || resp.Details != nil
+	}
+	if err := scanner.Err(); err != nil {
+		return interrupted(fmt.Errorf("read stream: %w", err))
+	}
+	if ctx.Err() != nil || !done {
+		return interrupted(io.ErrUnexpectedEOF)
+	}
+	return nil
+}
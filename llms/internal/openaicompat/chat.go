@@ -0,0 +1,133 @@
+package openaicompat
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Provider is an API compatible with the OpenAI API: the default model, the
+// limits of the API on the sampling options and its specific request fields.
+type Provider struct {
+	// Model is the model of the requests, unless set in the call options.
+	Model string
+	// Limits are the limits on the sampling options validated before the
+	// requests, if set.
+	Limits *llms.SamplingLimits
+	// Extra returns the provider specific fields of a request with the call
+	// options, if set.
+	Extra func(opts llms.CallOptions) map[string]any
+}
+
+// Chat is a chat model of an OpenAI compatible API, embedded by the chat
+// models of the providers.
+type Chat struct {
+	client   *Client
+	provider Provider
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat returns a new chat model of the provider sending its requests with
+// the client.
+func NewChat(client *Client, provider Provider) *Chat {
+	return &Chat{client: client, provider: provider}
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// Generate requests a chat response for each of the message sets.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if o.provider.Limits != nil {
+		if err := o.provider.Limits.Validate(opts); err != nil {
+			return nil, err
+		}
+	}
+	var extra map[string]any
+	if o.provider.Extra != nil {
+		extra = o.provider.Extra(opts)
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		generation, err := GenerateChat(ctx, o.client, o.provider.Model, messageSet, opts, extra)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, generation)
+	}
+
+	return generations, nil
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
+
+func (o *Chat) GetNumTokens(text string) int {
+	return llms.CountTokens(o.provider.Model, text)
+}
+
+// LLM is a chat model of an OpenAI compatible API used with text prompts,
+// embedded by the LLMs of the providers.
+type LLM struct {
+	chat *Chat
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// NewLLM returns a new LLM sending the prompts as user messages to the chat
+// model.
+func NewLLM(chat *Chat) *LLM {
+	return &LLM{chat: chat}
+}
+
+// Call requests a completion for the given prompt.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	r, err := o.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(r) == 0 {
+		return "", ErrEmptyResponse
+	}
+	return r[0].Text, nil
+}
+
+// Generate sends each prompt as a user message.
+func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	messageSets := make([][]schema.ChatMessage, 0, len(prompts))
+	for _, prompt := range prompts {
+		messageSets = append(messageSets, []schema.ChatMessage{schema.HumanChatMessage{Content: prompt}})
+	}
+	return o.chat.Generate(ctx, messageSets, options...)
+}
+
+func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, o, promptValues, options...)
+}
+
+func (o *LLM) GetNumTokens(text string) int {
+	return o.chat.GetNumTokens(text)
+}
@@ -0,0 +1,196 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// GenerateChat sends the messages to the chat completions endpoint and
+// returns the generation. The model of the call options takes precedence
+// over the given model and extra holds provider specific request fields.
+func GenerateChat(
+	ctx context.Context,
+	client *Client,
+	model string,
+	messages []schema.ChatMessage,
+	opts llms.CallOptions,
+	extra map[string]any,
+) (*llms.Generation, error) {
+	msgs := make([]*ChatMessage, 0, len(messages))
+	for _, m := range messages {
+		msg := &ChatMessage{
+			Content: m.GetContent(),
+		}
+		switch m.GetType() {
+		case schema.ChatMessageTypeSystem:
+			msg.Role = "system"
+		case schema.ChatMessageTypeAI:
+			msg.Role = "assistant"
+			if ai, ok := m.(schema.AIChatMessage); ok {
+				toolCalls, err := toClientToolCalls(ai.ToolCalls)
+				if err != nil {
+					return nil, err
+				}
+				msg.ToolCalls = toolCalls
+			}
+		case schema.ChatMessageTypeFunction:
+			// Function results are passed as user messages, as tool messages
+			// require the id of a tool call.
+			msg.Role = "user"
+			if n, ok := m.(schema.Named); ok {
+				msg.Content = n.GetName() + ": " + msg.Content
+			}
+		case schema.ChatMessageTypeTool:
+			msg.Role = "tool"
+			if tool, ok := m.(schema.ToolChatMessage); ok {
+				msg.ToolCallID = tool.ID
+			}
+		case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric:
+			msg.Role = "user"
+		}
+		if human, ok := m.(schema.HumanChatMessage); ok && len(human.Parts) > 0 {
+			parts, err := contentParts(human)
+			if err != nil {
+				return nil, err
+			}
+			msg.Content = ""
+			msg.MultiContent = parts
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	result, err := client.CreateChat(ctx, &ChatRequest{
+		Model:            model,
+		Messages:         msgs,
+		Temperature:      opts.Temperature,
+		TopP:             opts.TopP,
+		MaxTokens:        opts.MaxTokens,
+		N:                opts.N,
+		StopWords:        opts.StopWords,
+		FrequencyPenalty: opts.FrequencyPenalty,
+		PresencePenalty:  opts.PresencePenalty,
+		ResponseFormat:   opts.ResponseFormat,
+		Tools:            opts.Tools,
+		ToolChoice:       toolChoice(opts),
+		ReasoningEffort:  string(opts.ReasoningEffort),
+		Extra:            extra,
+		StreamingFunc:    opts.StreamingFunc,
+
+		StreamingChunkFunc: opts.StreamingChunkFunc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &schema.AIChatMessage{
+		Content: result.Choices[0].Message.Content,
+	}
+	for _, tc := range result.Choices[0].Message.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:   tc.ID,
+			Type: tc.Type,
+			FunctionCall: &schema.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+	generationInfo := map[string]any{
+		"CompletionTokens": result.Usage.CompletionTokens,
+		"PromptTokens":     result.Usage.PromptTokens,
+		"TotalTokens":      result.Usage.TotalTokens,
+		"FinishReason":     result.Choices[0].FinishReason,
+	}
+	if result.SystemFingerprint != "" {
+		generationInfo[llms.SystemFingerprintKey] = result.SystemFingerprint
+	}
+	if reasoning := result.Choices[0].Message.Reasoning + result.Choices[0].Message.ReasoningContent; reasoning != "" {
+		generationInfo[llms.ReasoningKey] = reasoning
+	}
+	return &llms.Generation{
+		Message:        msg,
+		Text:           msg.Content,
+		GenerationInfo: generationInfo,
+		Usage: llms.Usage{
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+			CachedTokens:     result.Usage.cachedTokens(),
+			ReasoningTokens:  result.Usage.CompletionTokensDetails.ReasoningTokens,
+		},
+	}, nil
+}
+
+// toolChoice returns the tool choice of the call options, nil without tools.
+func toolChoice(opts llms.CallOptions) any {
+	if len(opts.Tools) == 0 {
+		return nil
+	}
+	if choice, ok := opts.ToolChoice.(*llms.ToolChoice); ok {
+		if choice == nil {
+			return nil
+		}
+		return *choice
+	}
+	return opts.ToolChoice
+}
+
+// toClientToolCalls converts the tool calls of an AI message, encoding the
+// arguments which are not a string as JSON.
+func toClientToolCalls(toolCalls []schema.ToolCall) ([]ToolCall, error) {
+	clientToolCalls := make([]ToolCall, 0, len(toolCalls))
+	for _, tc := range toolCalls {
+		clientToolCall := ToolCall{ID: tc.ID, Type: tc.Type}
+		if clientToolCall.Type == "" {
+			clientToolCall.Type = "function"
+		}
+		if tc.FunctionCall != nil {
+			clientToolCall.Function.Name = tc.FunctionCall.Name
+			if arguments, ok := tc.FunctionCall.Arguments.(string); ok {
+				clientToolCall.Function.Arguments = arguments
+			} else if tc.FunctionCall.Arguments != nil {
+				b, err := json.Marshal(tc.FunctionCall.Arguments)
+				if err != nil {
+					return nil, fmt.Errorf("marshal arguments of tool call %s: %w", tc.ID, err)
+				}
+				clientToolCall.Function.Arguments = string(b)
+			}
+		}
+		clientToolCalls = append(clientToolCalls, clientToolCall)
+	}
+	return clientToolCalls, nil
+}
+
+// contentParts returns the content and the parts of a multimodal message as
+// content parts. The images are sent as image urls, inline images as data
+// urls. Files are not supported by the compatible APIs.
+func contentParts(m schema.HumanChatMessage) ([]ContentPart, error) {
+	parts := make([]ContentPart, 0, len(m.Parts)+1)
+	if m.Content != "" {
+		parts = append(parts, ContentPart{Type: "text", Text: m.Content})
+	}
+	for _, part := range m.Parts {
+		switch p := part.(type) {
+		case schema.TextPart:
+			parts = append(parts, ContentPart{Type: "text", Text: p.Text})
+		case schema.ImageURLPart:
+			parts = append(parts, ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: p.URL, Detail: p.Detail}})
+		case schema.BinaryPart:
+			if !strings.HasPrefix(p.MIMEType, "image/") {
+				return nil, fmt.Errorf("%w: %s data", llms.ErrUnsupportedContentPart, p.MIMEType)
+			}
+			parts = append(parts, ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: p.DataURL()}})
+		default:
+			return nil, fmt.Errorf("%w: %T", llms.ErrUnsupportedContentPart, part)
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,430 @@
+// Package openaicompat is a client for the chat completions endpoint of APIs
+// compatible with the OpenAI API, shared by the providers built on them.
+package openaicompat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrEmptyResponse is returned when the API returns no choices.
+var ErrEmptyResponse = errors.New("empty response")
+
+// maxStreamLineSize is the maximum size of a line of a streaming response,
+// which holds whole tool call arguments or reasoning chunks.
+const maxStreamLineSize = 8 << 20
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client for an OpenAI compatible API.
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  Doer
+	rateLimiter *llms.RateLimiter
+}
+
+// Option is an option of the client.
+type Option func(*Client)
+
+// WithRateLimiter waits for the rate limiter before each request.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// New returns a new client for the API at baseURL, authenticating with the
+// bearer token.
+func New(baseURL, token string, httpClient Doer, opts ...Option) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ChatMessage is a message in a chat request.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// Reasoning and ReasoningContent are the reasoning of the responses of
+	// reasoning models on Groq and DeepSeek respectively.
+	Reasoning        string `json:"reasoning,omitempty"`
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// MultiContent are the parts of a multimodal message, sent in place of
+	// Content when set.
+	MultiContent []ContentPart `json:"-"`
+
+	// ToolCalls are the tool calls requested by the model.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is the id of the tool call a tool message is the result of.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// ToolCall is a call to a tool requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall is the function of a tool call, with its arguments encoded as
+// a JSON object.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ContentPart is a part of the content of a multimodal message.
+type ContentPart struct {
+	// Type is either "text" or "image_url".
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL is the url of an image part, possibly a data url.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// MarshalJSON sends the multi content of the message in place of the content when set.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	type chatMessage ChatMessage
+	if len(m.MultiContent) == 0 {
+		return json.Marshal(chatMessage(m))
+	}
+	return json.Marshal(struct {
+		chatMessage
+		Content []ContentPart `json:"content"`
+	}{chatMessage: chatMessage(m), Content: m.MultiContent})
+}
+
+// ChatRequest is a request to the chat completions endpoint.
+type ChatRequest struct {
+	Model            string         `json:"model"`
+	Messages         []*ChatMessage `json:"messages"`
+	Temperature      float64        `json:"temperature,omitempty"`
+	TopP             float64        `json:"top_p,omitempty"`
+	MaxTokens        int            `json:"max_tokens,omitempty"`
+	N                int            `json:"n,omitempty"`
+	StopWords        []string       `json:"stop,omitempty"`
+	FrequencyPenalty float64        `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64        `json:"presence_penalty,omitempty"`
+	Stream           bool           `json:"stream,omitempty"`
+	// ReasoningEffort is the reasoning effort of reasoning models.
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+
+	// ResponseFormat constrains the format of the response.
+	ResponseFormat *llms.ResponseFormat `json:"response_format,omitempty"`
+
+	// Tools are the tools the model may call.
+	Tools []llms.Tool `json:"tools,omitempty"`
+	// ToolChoice is one of "none", "auto" and "required", or a llms.ToolChoice.
+	ToolChoice any `json:"tool_choice,omitempty"`
+
+	// Extra holds provider specific fields added to the request body.
+	Extra map[string]any `json:"-"`
+
+	// StreamingFunc is a function to be called for each chunk of a streaming response.
+	// Return an error to stop streaming early.
+	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each structured chunk
+	// of a streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
+}
+
+// ChatChoice is a choice in a chat response.
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// ChatUsage is the token usage of a chat request.
+type ChatUsage struct {
+	PromptTokens            int `json:"prompt_tokens"`
+	CompletionTokens        int `json:"completion_tokens"`
+	TotalTokens             int `json:"total_tokens"`
+	CompletionTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	// PromptCacheHitTokens is the number of cached prompt tokens on DeepSeek.
+	PromptCacheHitTokens int `json:"prompt_cache_hit_tokens"`
+}
+
+// cachedTokens returns the number of cached prompt tokens.
+func (u ChatUsage) cachedTokens() int {
+	if u.PromptCacheHitTokens > 0 {
+		return u.PromptCacheHitTokens
+	}
+	return u.PromptTokensDetails.CachedTokens
+}
+
+// ChatResponse is a response of the chat completions endpoint.
+type ChatResponse struct {
+	ID      string        `json:"id"`
+	Model   string        `json:"model"`
+	Choices []*ChatChoice `json:"choices"`
+	Usage   ChatUsage     `json:"usage"`
+	// SystemFingerprint identifies the backend configuration of the response.
+	SystemFingerprint string `json:"system_fingerprint"`
+}
+
+type streamedChatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+			// Reasoning is the reasoning of reasoning models on Groq.
+			Reasoning string `json:"reasoning"`
+			// ReasoningContent is the reasoning of reasoning models on DeepSeek.
+			ReasoningContent string `json:"reasoning_content"`
+			ToolCalls        []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage             *ChatUsage `json:"usage"`
+	SystemFingerprint string     `json:"system_fingerprint"`
+	// XGroq holds the usage of Groq streaming responses.
+	XGroq *struct {
+		Usage *ChatUsage `json:"usage"`
+	} `json:"x_groq"`
+}
+
+type errorMessage struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+	Message string `json:"message"`
+}
+
+// CreateChat creates a chat completion. If the request has a streaming func
+// the response is streamed and assembled into a single response.
+func (c *Client) CreateChat(ctx context.Context, payload *ChatRequest) (*ChatResponse, error) {
+	if payload.StreamingFunc != nil || payload.StreamingChunkFunc != nil {
+		payload.Stream = true
+	}
+	texts := make([]string, 0, len(payload.Messages))
+	for _, m := range payload.Messages {
+		texts = append(texts, m.Content)
+		for _, part := range m.MultiContent {
+			texts = append(texts, part.Text)
+		}
+	}
+	if err := c.rateLimiter.Wait(ctx, llms.EstimateTokens(texts...)+payload.MaxTokens); err != nil {
+		return nil, err
+	}
+
+	body, err := payload.marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	llms.SetRequestHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, llms.NewStatusError(r, "", "")
+		}
+		if errResp.Error.Message == "" {
+			errResp.Error.Message = errResp.Message
+		}
+
+		return nil, llms.NewStatusError(r, errResp.Error.Type, errResp.Error.Message)
+	}
+
+	var response *ChatResponse
+	if payload.Stream {
+		response, err = parseStreamingChatResponse(ctx, r, payload)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		response = &ChatResponse{}
+		if err := json.NewDecoder(r.Body).Decode(response); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+	}
+	if len(response.Choices) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return response, nil
+}
+
+// marshal encodes the request and adds the provider specific fields.
+func (r *ChatRequest) marshal() ([]byte, error) {
+	type request ChatRequest
+	body, err := json.Marshal((*request)(r))
+	if err != nil || len(r.Extra) == 0 {
+		return body, err
+	}
+
+	fields := make(map[string]any)
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range r.Extra {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
+
+// parseStreamingChatResponse assembles the chunks of a streaming response. If
+// the stream is interrupted, by the context, the connection or an error event,
+// it returns a *llms.StreamError holding the content streamed so far.
+func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *ChatRequest) (*ChatResponse, error) {
+	stop := llms.CloseOnCancel(ctx, r.Body)
+	defer stop()
+	response := &ChatResponse{
+		Choices: []*ChatChoice{{Message: ChatMessage{Role: "assistant"}}},
+	}
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, response.Choices[0].Message.Content, err)
+	}
+
+	// The arguments of the tool calls are streamed in fragments, the calls
+	// are identified by their index in the stream.
+	positions := make(map[int]int)
+	var arguments []*strings.Builder
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxStreamLineSize)
+	done := false
+	for !done && ctx.Err() == nil && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+
+		var errResp errorMessage
+		if err := json.Unmarshal([]byte(data), &errResp); err == nil && errResp.Error.Message != "" {
+			return nil, interrupted(&llms.StatusError{Code: errResp.Error.Type, Message: errResp.Error.Message})
+		}
+		var chunk streamedChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("parse stream chunk: %w", err)
+		}
+		response.ID = chunk.ID
+		response.Model = chunk.Model
+		if chunk.SystemFingerprint != "" {
+			response.SystemFingerprint = chunk.SystemFingerprint
+		}
+		if chunk.Usage != nil {
+			response.Usage = *chunk.Usage
+		}
+		if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+			response.Usage = *chunk.XGroq.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			response.Choices[0].FinishReason = choice.FinishReason
+		}
+		response.Choices[0].Message.Content += choice.Delta.Content
+		response.Choices[0].Message.Reasoning += choice.Delta.Reasoning + choice.Delta.ReasoningContent
+		if payload.StreamingFunc != nil && choice.Delta.Content != "" {
+			if err := payload.StreamingFunc(ctx, []byte(choice.Delta.Content)); err != nil {
+				return nil, fmt.Errorf("streaming func returned an error: %w", err)
+			}
+		}
+		streamChunk := llms.StreamChunk{
+			Content:      choice.Delta.Content,
+			Reasoning:    choice.Delta.Reasoning + choice.Delta.ReasoningContent,
+			FinishReason: choice.FinishReason,
+		}
+		for _, delta := range choice.Delta.ToolCalls {
+			position, ok := positions[delta.Index]
+			if !ok {
+				position = len(arguments)
+				positions[delta.Index] = position
+				arguments = append(arguments, &strings.Builder{})
+				response.Choices[0].Message.ToolCalls = append(response.Choices[0].Message.ToolCalls, ToolCall{
+					ID:       delta.ID,
+					Type:     delta.Type,
+					Function: FunctionCall{Name: delta.Function.Name},
+				})
+			}
+			arguments[position].WriteString(delta.Function.Arguments)
+			streamChunk.ToolCalls = append(streamChunk.ToolCalls, llms.ToolCallChunk{
+				Index:     delta.Index,
+				ID:        delta.ID,
+				Name:      delta.Function.Name,
+				Arguments: delta.Function.Arguments,
+			})
+		}
+		if payload.StreamingChunkFunc != nil && !streamChunk.IsEmpty() {
+			if err := payload.StreamingChunkFunc(ctx, streamChunk); err != nil {
+				return nil, fmt.Errorf("streaming func returned an error: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, interrupted(fmt.Errorf("read stream: %w", err))
+	}
+	if ctx.Err() != nil || (!done && response.Choices[0].FinishReason == "") {
+		return nil, interrupted(io.ErrUnexpectedEOF)
+	}
+	for i := range arguments {
+		response.Choices[0].Message.ToolCalls[i].Function.Arguments = arguments[i].String()
+	}
+
+	return response, nil
+}
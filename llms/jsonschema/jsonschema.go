@@ -0,0 +1,270 @@
+// Package jsonschema provides types for JSON schema definitions, used to
+// describe the parameters of tools and functions, and a helper to derive a
+// definition from a Go struct.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DataType is the type of a JSON value.
+type DataType string
+
+const (
+	Object  DataType = "object"
+	Number  DataType = "number"
+	Integer DataType = "integer"
+	String  DataType = "string"
+	Array   DataType = "array"
+	Null    DataType = "null"
+	Boolean DataType = "boolean"
+)
+
+// ErrUnsupportedType is returned when a Go type can not be described by a JSON schema.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// Definition is a JSON schema definition.
+type Definition struct {
+	// Type is the type of the value.
+	Type DataType `json:"type,omitempty"`
+	// Description is the description of the value.
+	Description string `json:"description,omitempty"`
+	// Enum is the list of allowed values.
+	Enum []string `json:"enum,omitempty"`
+	// Properties are the properties of an object.
+	Properties map[string]Definition `json:"properties,omitempty"`
+	// Required are the names of the required properties of an object.
+	Required []string `json:"required,omitempty"`
+	// Items is the definition of the elements of an array.
+	Items *Definition `json:"items,omitempty"`
+	// AdditionalProperties is false to disallow properties not listed in
+	// Properties, or the definition of the additional properties.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+}
+
+// MarshalJSON encodes the definition. Objects without properties and
+// additional properties are encoded with empty properties, as some APIs
+// require the properties of function parameters.
+func (d Definition) MarshalJSON() ([]byte, error) {
+	type definition Definition
+	if d.Type != Object || len(d.Properties) > 0 || d.AdditionalProperties != nil {
+		return json.Marshal(definition(d))
+	}
+	return json.Marshal(struct {
+		definition
+		Properties map[string]Definition `json:"properties"`
+	}{
+		definition: definition(d),
+		Properties: map[string]Definition{},
+	})
+}
+
+// Reflect returns the definition of the type of v, which is typically a
+// struct. The name of a property is taken from the json tag of the field and
+// fields are required unless the json tag has the omitempty option. The
+// description and the allowed values of a field are set with the description
+// and enum tags:
+//
+//	type Weather struct {
+//		Location string `json:"location" description:"The city, e.g. Paris"`
+//		Unit     string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+//	}
+//
+// Recursive types, such as a struct with a slice of itself, can not be
+// described without references and return ErrUnsupportedType.
+func Reflect(v any) (*Definition, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	d, err := reflectType(t, make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// nolint:gochecknoglobals
+var _timeType = reflect.TypeOf(time.Time{})
+
+// reflectType returns the definition of the type. The struct types being
+// reflected are tracked in visiting to reject the recursive types.
+func reflectType(t reflect.Type, visiting map[reflect.Type]bool) (Definition, error) { //nolint:cyclop
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == _timeType {
+		return Definition{Type: String, Description: "RFC 3339 date and time"}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return Definition{Type: String}, nil
+	case reflect.Bool:
+		return Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings.
+			return Definition{Type: String}, nil
+		}
+		items, err := reflectType(t.Elem(), visiting)
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Array, Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Definition{}, fmt.Errorf("%w: map key %s", ErrUnsupportedType, t.Key())
+		}
+		values, err := reflectType(t.Elem(), visiting)
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Object, AdditionalProperties: values}, nil
+	case reflect.Interface:
+		return Definition{}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return Definition{}, fmt.Errorf("%w: recursive type %s", ErrUnsupportedType, t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		return reflectStruct(t, visiting)
+	default:
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func reflectStruct(t reflect.Type, visiting map[reflect.Type]bool) (Definition, error) { //nolint:cyclop
+	d := Definition{
+		Type:                 Object,
+		Properties:           make(map[string]Definition),
+		AdditionalProperties: false,
+	}
+
+	// The fields of the struct take precedence over the fields of its
+	// embedded structs, as in encoding/json.
+	own := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, ok := fieldName(t.Field(i)); ok && !isEmbeddedStruct(t.Field(i)) {
+			own[name] = true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, required, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+
+		if isEmbeddedStruct(field) {
+			if err := reflectEmbedded(&d, field, own, visiting); err != nil {
+				return Definition{}, err
+			}
+			continue
+		}
+
+		property, err := reflectType(field.Type, visiting)
+		if err != nil {
+			return Definition{}, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if description, ok := field.Tag.Lookup("description"); ok {
+			property.Description = description
+		}
+		if enum, ok := field.Tag.Lookup("enum"); ok {
+			property.Enum = strings.Split(enum, ",")
+		}
+
+		d.Properties[name] = property
+		if required {
+			d.Required = append(d.Required, name)
+		}
+	}
+	return d, nil
+}
+
+// reflectEmbedded adds the properties of an embedded struct to d, except the
+// properties already defined by the fields of the embedding struct. The
+// properties of an embedded pointer are not required, as encoding/json omits
+// them when the pointer is nil.
+func reflectEmbedded(d *Definition, field reflect.StructField, own map[string]bool, visiting map[reflect.Type]bool) error { //nolint:lll
+	embedded, err := reflectType(field.Type, visiting)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	added := make(map[string]bool, len(embedded.Properties))
+	for name, property := range embedded.Properties {
+		if _, ok := d.Properties[name]; ok || own[name] {
+			continue
+		}
+		d.Properties[name] = property
+		added[name] = true
+	}
+	if field.Type.Kind() == reflect.Pointer {
+		return nil
+	}
+	for _, name := range embedded.Required {
+		if added[name] {
+			d.Required = append(d.Required, name)
+		}
+	}
+	return nil
+}
+
+// fieldName returns the JSON name of a struct field and whether it is
+// required, or false if the field is not encoded.
+func fieldName(field reflect.StructField) (string, bool, bool) {
+	if !field.IsExported() && !(field.Anonymous && structType(field.Type)) {
+		return "", false, false
+	}
+
+	name := field.Name
+	required := true
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] == "-" {
+			return "", false, false
+		}
+		if parts[0] != "" {
+			name = parts[0]
+		}
+		for _, option := range parts[1:] {
+			if option == "omitempty" {
+				required = false
+			}
+		}
+	}
+	return name, required, true
+}
+
+// isEmbeddedStruct reports whether field is an embedded struct, or pointer to
+// a struct, without a JSON name, whose fields are encoded as fields of the
+// embedding struct. Embedded structs are flattened even if their type is
+// unexported, as their exported fields are still encoded.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	if tag := field.Tag.Get("json"); strings.Split(tag, ",")[0] != "" {
+		return false
+	}
+	return structType(field.Type)
+}
+
+func structType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != _timeType
+}
@@ -0,0 +1,135 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherParams struct {
+	Location string            `json:"location" description:"The city, e.g. Paris"`
+	Unit     string            `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+	Days     int               `json:"days"`
+	Hourly   *bool             `json:"hourly,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Since    time.Time         `json:"since,omitempty"`
+	Ignored  string            `json:"-"`
+	private  string
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+
+	d, err := Reflect(weatherParams{})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"location": {"type": "string", "description": "The city, e.g. Paris"},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+			"days": {"type": "integer"},
+			"hourly": {"type": "boolean"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"since": {"type": "string", "description": "RFC 3339 date and time"}
+		},
+		"required": ["location", "days"],
+		"additionalProperties": false
+	}`, string(b))
+
+	b, err = json.Marshal(Definition{Type: Object})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object", "properties": {}}`, string(b))
+
+	_, err = Reflect(map[int]string{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = Reflect(struct{ C chan int }{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Children []treeNode `json:"children"`
+}
+
+type linkedNode struct {
+	Value int `json:"value"`
+	Next  *struct {
+		Node *linkedNode `json:"node"`
+	} `json:"next,omitempty"`
+}
+
+func TestReflectRecursive(t *testing.T) {
+	t.Parallel()
+
+	_, err := Reflect(treeNode{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = Reflect(&linkedNode{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	// A struct type used by several fields is not recursive.
+	d, err := Reflect(struct {
+		From weatherParams `json:"from"`
+		To   weatherParams `json:"to"`
+	}{})
+	require.NoError(t, err)
+	assert.Equal(t, d.Properties["from"], d.Properties["to"])
+}
+
+type timestamps struct {
+	Created string `json:"created"`
+	Updated string `json:"updated,omitempty"`
+}
+
+type Page struct {
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+type searchParams struct {
+	timestamps
+	*Page
+	Query   string `json:"query"`
+	Limit   string `json:"limit"`
+	Payload []byte `json:"payload"`
+}
+
+func TestReflectEmbedded(t *testing.T) {
+	t.Parallel()
+
+	d, err := Reflect(searchParams{})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"created": {"type": "string"},
+			"updated": {"type": "string"},
+			"cursor": {"type": "string"},
+			"limit": {"type": "string"},
+			"query": {"type": "string"},
+			"payload": {"type": "string"}
+		},
+		"required": ["created", "query", "limit", "payload"],
+		"additionalProperties": false
+	}`, string(b))
+
+	// Embedded structs with a JSON name are objects.
+	d, err = Reflect(struct {
+		Page `json:"page"`
+	}{})
+	require.NoError(t, err)
+	assert.Equal(t, Object, d.Properties["page"].Type)
+	assert.Equal(t, []string{"page"}, d.Required)
+}
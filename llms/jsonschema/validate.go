@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrValidation is returned when a value does not match a definition.
+var ErrValidation = errors.New("value does not match the schema")
+
+// Validate checks that the json data matches the definition. It checks the
+// types, the required properties, the allowed values and the additional
+// properties of objects.
+func (d Definition) Validate(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	return d.validate("$", v)
+}
+
+func (d Definition) validate(path string, v any) error { //nolint:cyclop
+	if d.Type != "" && !d.hasType(v) {
+		return fmt.Errorf("%w: %s must be of type %s", ErrValidation, path, d.Type)
+	}
+	if len(d.Enum) > 0 {
+		s, _ := v.(string)
+		found := false
+		for _, e := range d.Enum {
+			if e == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s must be one of %s", ErrValidation, path, strings.Join(d.Enum, ", "))
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range d.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%w: %s.%s is required", ErrValidation, path, name)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := d.Properties[key]
+			if !ok {
+				if additional, ok := d.AdditionalProperties.(Definition); ok {
+					property = additional
+				} else if d.AdditionalProperties == false && d.Properties != nil {
+					return fmt.Errorf("%w: %s.%s is not allowed", ErrValidation, path, key)
+				} else {
+					continue
+				}
+			}
+			if err := property.validate(path+"."+key, v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if d.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := d.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d Definition) hasType(v any) bool {
+	switch d.Type {
+	case Object:
+		_, ok := v.(map[string]any)
+		return ok
+	case Array:
+		_, ok := v.([]any)
+		return ok
+	case String:
+		_, ok := v.(string)
+		return ok
+	case Number:
+		_, ok := v.(float64)
+		return ok
+	case Integer:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case Boolean:
+		_, ok := v.(bool)
+		return ok
+	case Null:
+		return v == nil
+	}
+	return true
+}
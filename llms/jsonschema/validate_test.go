@@ -0,0 +1,38 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	d, err := Reflect(weatherParams{})
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		`{"location": "Paris", "days": 3}`:                                "",
+		`{"location": "Paris", "days": 3, "unit": "celsius"}`:             "",
+		`{"location": "Paris", "days": 3, "tags": ["a"], "hourly": true}`: "",
+		`{"location": "Paris"}`:                                           "$.days is required",
+		`{"location": "Paris", "days": 1.5}`:                              "$.days must be of type integer",
+		`{"location": "Paris", "days": 3, "unit": "kelvin"}`:              "$.unit must be one of celsius, fahrenheit",
+		`{"location": "Paris", "days": 3, "tags": [1]}`:                   "$.tags[0] must be of type string",
+		`{"location": "Paris", "days": 3, "other": 1}`:                    "$.other is not allowed",
+		`{"location": "Paris", "days": 3, "labels": {"a": 1}}`:            "$.labels.a must be of type string",
+		`[]`:       "$ must be of type object",
+		`not json`: "invalid character",
+	}
+	for data, expected := range cases {
+		err := d.Validate([]byte(data))
+		if expected == "" {
+			assert.NoError(t, err, data)
+			continue
+		}
+		require.ErrorIs(t, err, ErrValidation, data)
+		assert.Contains(t, err.Error(), expected, data)
+	}
+}
@@ -0,0 +1,275 @@
+package llamacppclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrAPI is returned when the llama.cpp server responds with an error.
+var ErrAPI = errors.New("llama.cpp api error")
+
+// Client is a client for the HTTP server of llama.cpp.
+type Client struct {
+	baseURL    *url.URL
+	httpClient Doer
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// New returns a new llama.cpp client for the server at baseURL, e.g.
+// http://localhost:8080.
+func New(baseURL string, httpClient Doer) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse server url: %w", err)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    u,
+		httpClient: httpClient,
+	}, nil
+}
+
+// CompletionRequest is a request to the completion endpoint. See
+// https://github.com/ggerganov/llama.cpp/blob/master/examples/server/README.md.
+type CompletionRequest struct {
+	Prompt           string   `json:"prompt"`
+	NPredict         int      `json:"n_predict,omitempty"`
+	Temperature      float64  `json:"temperature,omitempty"`
+	TopK             int      `json:"top_k,omitempty"`
+	TopP             float64  `json:"top_p,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	RepeatPenalty    float64  `json:"repeat_penalty,omitempty"`
+	FrequencyPenalty float64  `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64  `json:"presence_penalty,omitempty"`
+	// LogitBias are pairs of a token id, or a string, and the bias added to
+	// its logits.
+	LogitBias [][2]any `json:"logit_bias,omitempty"`
+	// Grammar is a GBNF grammar constraining the sampled tokens.
+	Grammar string `json:"grammar,omitempty"`
+	// JSONSchema constrains the sampled tokens to JSON documents of the schema.
+	JSONSchema any `json:"json_schema,omitempty"`
+	// CachePrompt reuses the evaluated prompt of the previous request of the slot.
+	CachePrompt bool `json:"cache_prompt,omitempty"`
+	Stream      bool `json:"stream"`
+}
+
+// Timings are the timings of a completion.
+type Timings struct {
+	PromptN             int     `json:"prompt_n"`
+	PromptMS            float64 `json:"prompt_ms"`
+	PredictedN          int     `json:"predicted_n"`
+	PredictedMS         float64 `json:"predicted_ms"`
+	PredictedPerSecond  float64 `json:"predicted_per_second"`
+	PromptPerSecond     float64 `json:"prompt_per_second"`
+	PredictedPerTokenMS float64 `json:"predicted_per_token_ms"`
+}
+
+// CompletionResponse is a response, or a chunk of a streaming response, of
+// the completion endpoint.
+type CompletionResponse struct {
+	Content string `json:"content"`
+	// Stop is true for the last chunk of a streaming response.
+	Stop bool `json:"stop"`
+	// StoppedEOS, StoppedWord and StoppedLimit give the reason the
+	// generation stopped.
+	StoppedEOS      bool     `json:"stopped_eos,omitempty"`
+	StoppedWord     bool     `json:"stopped_word,omitempty"`
+	StoppedLimit    bool     `json:"stopped_limit,omitempty"`
+	TokensPredicted int      `json:"tokens_predicted,omitempty"`
+	TokensEvaluated int      `json:"tokens_evaluated,omitempty"`
+	Timings         *Timings `json:"timings,omitempty"`
+}
+
+// FinishReason returns "length" if the generation stopped at the token
+// limit, "stop" if it stopped at the end of the text or a stop word, and an
+// empty string while the generation goes on.
+func (r CompletionResponse) FinishReason() string {
+	switch {
+	case r.StoppedLimit:
+		return "length"
+	case r.StoppedEOS, r.StoppedWord:
+		return "stop"
+	default:
+		return ""
+	}
+}
+
+// CompletionResponseFunc is called for each chunk of a streaming completion response.
+type CompletionResponseFunc func(CompletionResponse) error
+
+type errorMessage struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// Completion calls the completion endpoint. The function is called for every
+// chunk if the request is streaming, otherwise once with the full response.
+// If the stream is interrupted, by the context, the connection or an error
+// event, it returns a *llms.StreamError holding the content streamed so far.
+func (c *Client) Completion(ctx context.Context, req *CompletionRequest, fn CompletionResponseFunc) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	reqURL := c.baseURL.JoinPath("/completion").String()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	llms.SetRequestHeaders(httpReq)
+
+	r, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode >= http.StatusBadRequest {
+		// The error message is optional, the status code is enough.
+		var errResp errorMessage
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
+		return fmt.Errorf("%w: %w", ErrAPI, llms.NewStatusError(r, errResp.Error.Type, errResp.Error.Message))
+	}
+
+	if !req.Stream {
+		var resp CompletionResponse
+		if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		return fn(resp)
+	}
+
+	stop := llms.CloseOnCancel(ctx, r.Body)
+	defer stop()
+	var partial strings.Builder
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, partial.String(), err)
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	done := false
+	for !done && ctx.Err() == nil && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := []byte(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+
+		var errResp errorMessage
+		if err := json.Unmarshal(data, &errResp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		if errResp.Error.Message != "" {
+			return interrupted(fmt.Errorf("%w: %w", ErrAPI,
+				&llms.StatusError{Code: errResp.Error.Type, Message: errResp.Error.Message}))
+		}
+
+		var resp CompletionResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		partial.WriteString(resp.Content)
+		if err := fn(resp); err != nil {
+			return err
+		}
+		done = resp.Stop
+	}
+	if err := scanner.Err(); err != nil {
+		return interrupted(fmt.Errorf("read response: %w", err))
+	}
+	if ctx.Err() != nil || !done {
+		return interrupted(io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// EmbeddingRequest is a request to the OpenAI compatible embeddings
+// endpoint. The server must be started with the --embeddings flag.
+type EmbeddingRequest struct {
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embeddings returns the embeddings of the inputs, in the order of the
+// inputs.
+func (c *Client) Embeddings(ctx context.Context, req *EmbeddingRequest) ([][]float64, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	reqURL := c.baseURL.JoinPath("/v1/embeddings").String()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	llms.SetRequestHeaders(httpReq)
+
+	r, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode >= http.StatusBadRequest {
+		var errResp errorMessage
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
+		return nil, fmt.Errorf("%w: %w", ErrAPI, llms.NewStatusError(r, errResp.Error.Type, errResp.Error.Message))
+	}
+
+	var resp embeddingResponse
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	embeddings := make([][]float64, len(req.Input))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(embeddings) {
+			return nil, fmt.Errorf("%w: embedding index %d out of range", ErrAPI, d.Index)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+	return embeddings, nil
+}
+
+// Healthy reports whether the server has loaded the model and accepts requests.
+func (c *Client) Healthy(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.JoinPath("/health").String(), nil)
+	if err != nil {
+		return false
+	}
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	r.Body.Close()
+	return r.StatusCode == http.StatusOK
+}
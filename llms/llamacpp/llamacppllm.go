@@ -0,0 +1,221 @@
+package llamacpp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/llamacpp/internal/llamacppclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrEmptyResponse is returned when the llama.cpp server returns an empty response.
+	ErrEmptyResponse = errors.New("no response")
+	// ErrUnexpectedResponseLength is returned when the server returns a
+	// number of embeddings different from the number of texts.
+	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
+)
+
+// LLM is a client for a GGUF model served by the HTTP server of llama.cpp,
+// either a running server or a server started for a local model file.
+type LLM struct {
+	client  *llamacppclient.Client
+	server  *server
+	options options
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// New returns a new llama.cpp LLM. With WithModelPath it starts a llama.cpp
+// server for the model, which must be stopped with Close.
+func New(opts ...Option) (*LLM, error) {
+	o := options{
+		serverURL:      os.Getenv(serverURLEnvVarName),
+		serverBin:      os.Getenv(serverBinEnvVarName),
+		startupTimeout: defaultStartupTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.serverURL == "" {
+		o.serverURL = defaultServerURL
+	}
+	if o.serverBin == "" {
+		o.serverBin = defaultServerBin
+	}
+
+	llm := &LLM{options: o}
+	serverURL := o.serverURL
+	if o.modelPath != "" {
+		s, url, err := startServer(o)
+		if err != nil {
+			return nil, err
+		}
+		llm.server, serverURL = s, url
+	}
+
+	c, err := o.client(serverURL)
+	if err != nil {
+		llm.Close()
+		return nil, err
+	}
+	llm.client = c
+	return llm, nil
+}
+
+func (o options) client(serverURL string) (*llamacppclient.Client, error) {
+	var doer llamacppclient.Doer
+	if o.httpClient != nil {
+		doer = o.httpClient
+	}
+	return llamacppclient.New(serverURL, doer)
+}
+
+// Close stops the server started by New, if any.
+func (o *LLM) Close() {
+	if o.server != nil {
+		o.server.stop()
+		o.server = nil
+	}
+}
+
+// Call requests a completion for the given prompt.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	r, err := o.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(r) == 0 {
+		return "", ErrEmptyResponse
+	}
+	return r[0].Text, nil
+}
+
+// Generate requests a completion for each of the prompts.
+func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		req := o.options.completionRequest(prompt, opts)
+
+		var text string
+		var last llamacppclient.CompletionResponse
+		err := o.client.Completion(ctx, req, func(resp llamacppclient.CompletionResponse) error {
+			text += resp.Content
+			last = resp
+			return llms.SendStreamChunk(ctx, opts, llms.StreamChunk{Content: resp.Content, FinishReason: resp.FinishReason()})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		generations = append(generations, &llms.Generation{
+			Text:           text,
+			GenerationInfo: generationInfo(last),
+			Usage: llms.Usage{
+				PromptTokens:     last.TokensEvaluated,
+				CompletionTokens: last.TokensPredicted,
+				TotalTokens:      last.TokensEvaluated + last.TokensPredicted,
+			},
+		})
+	}
+
+	return generations, nil
+}
+
+func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, o, promptValues, options...)
+}
+
+// GetNumTokens approximates the number of tokens of the text, the tokenizer
+// of the model is only known to the server.
+func (o *LLM) GetNumTokens(text string) int {
+	return llms.CountTokens("gpt2", text)
+}
+
+// CreateEmbedding creates embeddings for the given input texts. The server
+// must be started with the --embeddings flag, see WithEmbeddings.
+func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	embeddings, err := o.client.Embeddings(ctx, &llamacppclient.EmbeddingRequest{Input: inputTexts})
+	if err != nil {
+		return nil, err
+	}
+	for _, embedding := range embeddings {
+		if len(embedding) == 0 {
+			return nil, ErrUnexpectedResponseLength
+		}
+	}
+	return embeddings, nil
+}
+
+// completionRequest returns the request for the prompt with the call options.
+func (o options) completionRequest(prompt string, opts llms.CallOptions) *llamacppclient.CompletionRequest {
+	req := &llamacppclient.CompletionRequest{
+		Prompt:           prompt,
+		NPredict:         opts.MaxTokens,
+		Temperature:      opts.Temperature,
+		TopK:             opts.TopK,
+		TopP:             opts.TopP,
+		Stop:             opts.StopWords,
+		RepeatPenalty:    opts.RepetitionPenalty,
+		FrequencyPenalty: opts.FrequencyPenalty,
+		PresencePenalty:  opts.PresencePenalty,
+		Grammar:          o.grammar,
+		CachePrompt:      o.cachePrompt,
+		Stream:           opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil,
+	}
+	if opts.SeedSet {
+		req.Seed = &opts.Seed
+	}
+	for _, token := range sortedKeys(opts.LogitBias) {
+		// The server takes the token ids as numbers, other keys are
+		// tokenized as strings.
+		var key any = token
+		if id, err := strconv.Atoi(token); err == nil {
+			key = id
+		}
+		req.LogitBias = append(req.LogitBias, [2]any{key, opts.LogitBias[token]})
+	}
+	if opts.ResponseFormat.IsJSON() {
+		// An empty schema constrains the text to any JSON document.
+		req.JSONSchema = map[string]any{}
+		if opts.ResponseFormat.JSONSchema != nil && opts.ResponseFormat.JSONSchema.Schema != nil {
+			req.JSONSchema = opts.ResponseFormat.JSONSchema.Schema
+		}
+		req.Grammar = ""
+	}
+	return req
+}
+
+func generationInfo(resp llamacppclient.CompletionResponse) map[string]any {
+	info := map[string]any{
+		"PromptTokens":     resp.TokensEvaluated,
+		"CompletionTokens": resp.TokensPredicted,
+		"TotalTokens":      resp.TokensEvaluated + resp.TokensPredicted,
+		"FinishReason":     resp.FinishReason(),
+	}
+	if resp.Timings != nil {
+		info["PredictedPerSecond"] = resp.Timings.PredictedPerSecond
+	}
+	return info
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
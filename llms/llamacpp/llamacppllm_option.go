@@ -0,0 +1,131 @@
+package llamacpp
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	serverURLEnvVarName = "LLAMACPP_SERVER_URL"
+	serverBinEnvVarName = "LLAMACPP_SERVER_BIN"
+	defaultServerURL    = "http://localhost:8080"
+	defaultServerBin    = "llama-server"
+
+	defaultStartupTimeout = 2 * time.Minute
+)
+
+type options struct {
+	serverURL   string
+	httpClient  *http.Client
+	grammar     string
+	cachePrompt bool
+
+	// The options of a server started by New.
+	serverBin      string
+	modelPath      string
+	gpuLayers      *int
+	contextSize    int
+	threads        int
+	embeddings     bool
+	serverArgs     []string
+	startupTimeout time.Duration
+}
+
+type Option func(*options)
+
+// WithServerURL sets the url of a running llama.cpp server. If not set, the
+// url is read from the LLAMACPP_SERVER_URL environment variable and defaults
+// to http://localhost:8080. It is ignored if the server is started by New.
+func WithServerURL(serverURL string) Option {
+	return func(opts *options) {
+		opts.serverURL = serverURL
+	}
+}
+
+// WithHTTPClient sets the http client used to call the llama.cpp server.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithGrammar constrains the generated text to a GBNF grammar, see
+// https://github.com/ggerganov/llama.cpp/blob/master/grammars/README.md.
+// The JSON schema of llms.WithResponseFormat takes precedence.
+func WithGrammar(grammar string) Option {
+	return func(opts *options) {
+		opts.grammar = grammar
+	}
+}
+
+// WithCachePrompt reuses the evaluated prompt of the previous request when
+// the prompts share a prefix, e.g. in multi-turn conversations.
+func WithCachePrompt() Option {
+	return func(opts *options) {
+		opts.cachePrompt = true
+	}
+}
+
+// WithModelPath starts a llama.cpp server for the GGUF model at the path,
+// stopped by Close. The server binary is read from the LLAMACPP_SERVER_BIN
+// environment variable and defaults to llama-server.
+func WithModelPath(path string) Option {
+	return func(opts *options) {
+		opts.modelPath = path
+	}
+}
+
+// WithServerBin sets the path of the llama.cpp server binary started by New.
+func WithServerBin(bin string) Option {
+	return func(opts *options) {
+		opts.serverBin = bin
+	}
+}
+
+// WithGPULayers sets the number of layers offloaded to the GPU by the started
+// server. A number larger than the number of layers, e.g. 999, offloads the
+// whole model, zero runs it on the CPU.
+func WithGPULayers(layers int) Option {
+	return func(opts *options) {
+		opts.gpuLayers = &layers
+	}
+}
+
+// WithContextSize sets the size of the context window of the started server.
+// Defaults to the context size of the model.
+func WithContextSize(size int) Option {
+	return func(opts *options) {
+		opts.contextSize = size
+	}
+}
+
+// WithThreads sets the number of threads used by the started server.
+func WithThreads(threads int) Option {
+	return func(opts *options) {
+		opts.threads = threads
+	}
+}
+
+// WithEmbeddings enables the embeddings endpoint of the started server, used
+// by CreateEmbedding, e.g. for an embedding model.
+func WithEmbeddings() Option {
+	return func(opts *options) {
+		opts.embeddings = true
+	}
+}
+
+// WithServerArgs passes additional command line arguments to the started
+// server, e.g. "--flash-attn".
+func WithServerArgs(args ...string) Option {
+	return func(opts *options) {
+		opts.serverArgs = append(opts.serverArgs, args...)
+	}
+}
+
+// WithStartupTimeout sets how long New waits for the started server to load
+// the model. Defaults to 2 minutes.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.startupTimeout = timeout
+	}
+}
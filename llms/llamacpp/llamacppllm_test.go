@@ -0,0 +1,242 @@
+package llamacpp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/llamacpp/internal/llamacppclient"
+)
+
+func newTestServer(t *testing.T, requests chan<- map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/completion", r.URL.Path)
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+
+		if req["prompt"] == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"code":400,"message":"bad grammar","type":"invalid_request_error"}}`)
+			return
+		}
+		if req["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"content\":\"Hello\",\"stop\":false}\n\n")
+			switch req["prompt"] {
+			case "error event":
+				fmt.Fprint(w, "data: {\"error\":{\"code\":500,\"message\":\"slot unavailable\",\"type\":\"server_error\"}}\n\n")
+				return
+			case "cut off":
+				return
+			}
+			fmt.Fprint(w, "data: {\"content\":\" world\",\"stop\":false}\n\n")
+			fmt.Fprint(w, "data: {\"content\":\"\",\"stop\":true,\"stopped_eos\":true,"+
+				"\"tokens_evaluated\":4,\"tokens_predicted\":2}\n\n")
+			return
+		}
+		fmt.Fprint(w, `{"content":"Hello world","stop":true,"stopped_limit":true,"tokens_evaluated":4,"tokens_predicted":2}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL), WithGrammar(`root ::= "Hello world"`), WithCachePrompt())
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"Say hello"},
+		llms.WithMaxTokens(16), llms.WithTemperature(0.5), llms.WithStopWords([]string{"\n"}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, llms.Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}, generations[0].Usage)
+	assert.Equal(t, "length", generations[0].GenerationInfo["FinishReason"])
+
+	req := <-requests
+	assert.Equal(t, "Say hello", req["prompt"])
+	assert.Equal(t, 16.0, req["n_predict"])
+	assert.Equal(t, 0.5, req["temperature"])
+	assert.Equal(t, []any{"\n"}, req["stop"])
+	assert.Equal(t, `root ::= "Hello world"`, req["grammar"])
+	assert.Equal(t, true, req["cache_prompt"])
+	assert.Equal(t, false, req["stream"])
+}
+
+func TestGenerateStreaming(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	var streamed string
+	var chunks []llms.StreamChunk
+	text, err := llm.Call(context.Background(), "Say hello",
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			streamed += string(chunk)
+			return nil
+		}),
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello world", text)
+	assert.Equal(t, "Hello world", streamed)
+	assert.Equal(t, []llms.StreamChunk{{Content: "Hello"}, {Content: " world"}, {FinishReason: "stop"}}, chunks)
+	assert.Equal(t, true, (<-requests)["stream"])
+}
+
+func TestGenerateStreamInterrupted(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 2)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+	stream := llms.WithStreamingFunc(func(context.Context, []byte) error { return nil })
+
+	_, err = llm.Call(context.Background(), "error event", stream)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	require.ErrorIs(t, err, llamacppclient.ErrAPI)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+	assert.Contains(t, err.Error(), "slot unavailable")
+
+	_, err = llm.Call(context.Background(), "cut off", stream)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL), WithGrammar(`root ::= "ignored"`))
+	require.NoError(t, err)
+
+	schema := map[string]any{"type": "object"}
+	_, err = llm.Call(context.Background(), "Answer in JSON", llms.WithResponseFormat(llms.JSONSchemaFormat(llms.JSONSchema{
+		Name:   "answer",
+		Schema: schema,
+	})))
+	require.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, schema, req["json_schema"])
+	assert.NotContains(t, req, "grammar")
+}
+
+func TestGenerateLogitBias(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "Hi", llms.WithLogitBias(map[string]int{"15043": 5, " Hello": -100}))
+	require.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, []any{[]any{" Hello", float64(-100)}, []any{float64(15043), float64(5)}}, req["logit_bias"])
+}
+
+func TestGenerateError(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 1)
+	server := newTestServer(t, requests)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "fail")
+	require.ErrorIs(t, err, llamacppclient.ErrAPI)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	assert.Contains(t, err.Error(), "bad grammar")
+}
+
+func TestServerArgs(t *testing.T) {
+	t.Parallel()
+
+	o := options{modelPath: "model.gguf", contextSize: 4096, serverArgs: []string{"--flash-attn"}}
+	WithGPULayers(0)(&o)
+	assert.Equal(t, []string{
+		"--model", "model.gguf", "--host", "127.0.0.1", "--port", "8081",
+		"--n-gpu-layers", "0", "--ctx-size", "4096", "--flash-attn",
+	}, o.args(8081))
+
+	WithEmbeddings()(&o)
+	assert.Contains(t, o.args(8081), "--embeddings")
+}
+
+func TestStartServerErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(WithModelPath("model.gguf"), WithServerBin("llama-server-not-installed"))
+	require.ErrorIs(t, err, ErrMissingServerBin)
+
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false is not installed")
+	}
+	_, err = New(WithModelPath("model.gguf"), WithServerBin("false"), WithStartupTimeout(10*time.Second))
+	require.ErrorIs(t, err, ErrServerExited)
+}
+
+func TestCreateEmbedding(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		var req llamacppclient.EmbeddingRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Input[0] == "fail" {
+			w.WriteHeader(http.StatusNotImplemented)
+			fmt.Fprint(w, `{"error":{"code":501,"message":"This server does not support embeddings.","type":"not_supported_error"}}`) //nolint:lll
+			return
+		}
+		assert.Equal(t, []string{"a", "b"}, req.Input)
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0.3,0.4]},{"index":0,"embedding":[0.1,0.2]}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	embeddings, err := llm.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1, 0.2}, {0.3, 0.4}}, embeddings)
+
+	_, err = llm.CreateEmbedding(context.Background(), []string{"fail"})
+	require.ErrorIs(t, err, llamacppclient.ErrAPI)
+	assert.Contains(t, err.Error(), "does not support embeddings")
+}
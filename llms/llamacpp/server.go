@@ -0,0 +1,122 @@
+package llamacpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/tmc/langchaingo/llms/llamacpp/internal/llamacppclient"
+)
+
+var (
+	// ErrMissingServerBin is returned when the llama.cpp server binary is not found.
+	ErrMissingServerBin = errors.New("missing the llama.cpp server binary, set the LLAMACPP_SERVER_BIN environment variable") //nolint:lll
+	// ErrServerExited is returned when the started server exits before loading the model.
+	ErrServerExited = errors.New("llama.cpp server exited")
+	// ErrServerStartup is returned when the started server does not load the
+	// model within the startup timeout.
+	ErrServerStartup = errors.New("llama.cpp server startup timed out")
+)
+
+const healthInterval = 100 * time.Millisecond
+
+// server is a llama.cpp server process started for a model.
+type server struct {
+	cmd    *exec.Cmd
+	exited chan struct{}
+	err    error
+}
+
+// startServer starts a llama.cpp server for the model of the options on a
+// free local port and waits until it has loaded the model.
+func startServer(o options) (*server, string, error) {
+	bin, err := exec.LookPath(o.serverBin)
+	if err != nil {
+		return nil, "", errors.Join(ErrMissingServerBin, err)
+	}
+	port, err := freePort()
+	if err != nil {
+		return nil, "", err
+	}
+	serverURL := "http://127.0.0.1:" + strconv.Itoa(port)
+
+	s := &server{
+		cmd:    exec.Command(bin, o.args(port)...), //nolint:gosec
+		exited: make(chan struct{}),
+	}
+	if err := s.cmd.Start(); err != nil {
+		return nil, "", fmt.Errorf("start llama.cpp server: %w", err)
+	}
+	go func() {
+		s.err = s.cmd.Wait()
+		close(s.exited)
+	}()
+
+	client, err := o.client(serverURL)
+	if err != nil {
+		s.stop()
+		return nil, "", err
+	}
+	if err := s.waitHealthy(client, o.startupTimeout); err != nil {
+		s.stop()
+		return nil, "", err
+	}
+	return s, serverURL, nil
+}
+
+// args returns the command line arguments of a server listening on the port.
+func (o options) args(port int) []string {
+	args := []string{"--model", o.modelPath, "--host", "127.0.0.1", "--port", strconv.Itoa(port)}
+	if o.gpuLayers != nil {
+		args = append(args, "--n-gpu-layers", strconv.Itoa(*o.gpuLayers))
+	}
+	if o.contextSize != 0 {
+		args = append(args, "--ctx-size", strconv.Itoa(o.contextSize))
+	}
+	if o.threads != 0 {
+		args = append(args, "--threads", strconv.Itoa(o.threads))
+	}
+	if o.embeddings {
+		args = append(args, "--embeddings")
+	}
+	return append(args, o.serverArgs...)
+}
+
+func (s *server) waitHealthy(client *llamacppclient.Client, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		if client.Healthy(ctx) {
+			return nil
+		}
+		select {
+		case <-s.exited:
+			return fmt.Errorf("%w: %w", ErrServerExited, s.err)
+		case <-ctx.Done():
+			return ErrServerStartup
+		case <-ticker.C:
+		}
+	}
+}
+
+// stop kills the server and waits for it to exit.
+func (s *server) stop() {
+	_ = s.cmd.Process.Kill()
+	<-s.exited
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("find a free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil //nolint:forcetypeassert
+}
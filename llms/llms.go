@@ -34,22 +34,32 @@ type Generation struct {
 	Message *schema.AIChatMessage `json:"message"`
 	// GenerationInfo is the generation info. This can contain vendor-specific information.
 	GenerationInfo map[string]any `json:"generation_info"`
+	// Usage is the token usage of the generation. It is zero if the provider
+	// does not report it.
+	Usage Usage `json:"usage"`
 }
 
 // LLMResult is the class that contains all relevant information for an LLM Result.
 type LLMResult struct {
 	Generations [][]*Generation
 	LLMOutput   map[string]any
+	// Usage is the token usage of all the generations.
+	Usage Usage
 }
 
 func GeneratePrompt(ctx context.Context, l LLM, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
 	prompts := make([]string, 0, len(promptValues))
 	for _, promptValue := range promptValues {
+		// The prompts of text LLMs cannot carry the multimodal parts.
+		if err := RequireTextParts(promptValue.Messages()); err != nil {
+			return LLMResult{}, err
+		}
 		prompts = append(prompts, promptValue.String())
 	}
 	generations, err := l.Generate(ctx, prompts, options...)
 	return LLMResult{
 		Generations: [][]*Generation{generations},
+		Usage:       TotalUsage(generations),
 	}, err
 }
 
@@ -61,5 +71,6 @@ func GenerateChatPrompt(ctx context.Context, l ChatLLM, promptValues []schema.Pr
 	generations, err := l.Generate(ctx, messages, options...)
 	return LLMResult{
 		Generations: [][]*Generation{generations},
+		Usage:       TotalUsage(generations),
 	}, err
 }
@@ -62,7 +62,7 @@ func (o *LLM) appendGlobalsToArgs(opts llms.CallOptions) []string {
 	if opts.RepetitionPenalty != 0 {
 		o.client.Args = append(o.client.Args, fmt.Sprintf("--repetition_penalty=%f", opts.RepetitionPenalty))
 	}
-	if opts.Seed != 0 {
+	if opts.SeedSet {
 		o.client.Args = append(o.client.Args, fmt.Sprintf("--seed=%d", opts.Seed))
 	}
 
@@ -88,8 +88,18 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	if err != nil {
 		return nil, err
 	}
+	// The local binary does not report its token usage, it is estimated with
+	// the tokenizer of GetNumTokens.
+	promptTokens, completionTokens := o.GetNumTokens(prompts[0]), o.GetNumTokens(result.Text)
 	return []*llms.Generation{
-		{Text: result.Text},
+		{
+			Text: result.Text,
+			Usage: llms.Usage{
+				PromptTokens:     promptTokens,
+				CompletionTokens: completionTokens,
+				TotalTokens:      promptTokens + completionTokens,
+			},
+		},
 	}, nil
 }
 
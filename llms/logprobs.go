@@ -0,0 +1,70 @@
+package llms
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// LogProbsKey is the key of the log probabilities in the generation info.
+const LogProbsKey = "LogProbs"
+
+// TokenLogProb is the log probability of a generated token.
+type TokenLogProb struct {
+	Token   string  `json:"token"`
+	LogProb float64 `json:"logprob"`
+	// Bytes are the UTF-8 bytes of the token, if reported by the provider.
+	Bytes []int `json:"bytes,omitempty"`
+	// TopLogProbs are the most likely tokens at this position, requested
+	// with WithLogProbs.
+	TopLogProbs []TopLogProb `json:"top_logprobs,omitempty"`
+}
+
+// TopLogProb is the log probability of an alternative token.
+type TopLogProb struct {
+	Token   string  `json:"token"`
+	LogProb float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
+// Prob returns the probability of the token.
+func (t TokenLogProb) Prob() float64 {
+	return math.Exp(t.LogProb)
+}
+
+// LogProbs returns the log probabilities of the generated tokens, or nil if
+// they were not requested or the provider does not support them.
+func (g *Generation) LogProbs() []TokenLogProb {
+	if g == nil {
+		return nil
+	}
+	switch logProbs := g.GenerationInfo[LogProbsKey].(type) {
+	case nil:
+		return nil
+	case []TokenLogProb:
+		return logProbs
+	default:
+		// The generation info was decoded from json, e.g. by a cache.
+		b, err := json.Marshal(logProbs)
+		if err != nil {
+			return nil
+		}
+		var tokens []TokenLogProb
+		if err := json.Unmarshal(b, &tokens); err != nil {
+			return nil
+		}
+		return tokens
+	}
+}
+
+// MeanLogProb returns the mean log probability of the tokens, a measure of the
+// confidence of the model in a generation. It returns 0 for no tokens.
+func MeanLogProb(tokens []TokenLogProb) float64 {
+	if len(tokens) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, token := range tokens {
+		sum += token.LogProb
+	}
+	return sum / float64(len(tokens))
+}
@@ -0,0 +1,33 @@
+package llms
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationLogProbs(t *testing.T) {
+	t.Parallel()
+
+	tokens := []TokenLogProb{
+		{Token: "a", LogProb: -1, TopLogProbs: []TopLogProb{{Token: "a", LogProb: -1}}},
+		{Token: "b", LogProb: -3},
+	}
+	generation := &Generation{GenerationInfo: map[string]any{LogProbsKey: tokens}}
+	assert.Equal(t, tokens, generation.LogProbs())
+	assert.InDelta(t, -2.0, MeanLogProb(tokens), 1e-9)
+	assert.InDelta(t, math.Exp(-1), tokens[0].Prob(), 1e-9)
+
+	// Log probabilities survive a json round trip of the generation.
+	b, err := json.Marshal(generation)
+	require.NoError(t, err)
+	var decoded Generation
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, tokens, decoded.LogProbs())
+
+	assert.Nil(t, (&Generation{}).LogProbs())
+	assert.Zero(t, MeanLogProb(nil))
+}
@@ -0,0 +1,138 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Request is a request to a model passing through middleware: the prompts of
+// an LLM or the message sets of a chat LLM, and the call options.
+type Request struct {
+	// Prompts are the prompts of a request to an LLM.
+	Prompts []string
+	// MessageSets are the message sets of a request to a chat LLM.
+	MessageSets [][]schema.ChatMessage
+	Options     []CallOption
+}
+
+// CallFunc sends a request to a model.
+type CallFunc func(ctx context.Context, req *Request) ([]*Generation, error)
+
+// Middleware intercepts the requests to a model. It may change the request
+// and the context before calling next, e.g. to redact prompts or to set
+// headers with WithRequestHeaders, and inspect or change the generations
+// and the error returned by next.
+type Middleware func(next CallFunc) CallFunc
+
+// HeaderMiddleware returns a middleware setting the headers on the HTTP
+// requests sent by the providers, e.g. for custom authentication.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, req *Request) ([]*Generation, error) {
+			return next(WithRequestHeaders(ctx, header), req)
+		}
+	}
+}
+
+// chain returns the call func passing the requests through the middleware,
+// the first middleware being the outermost.
+func chain(call CallFunc, middleware []Middleware) CallFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		call = middleware[i](call)
+	}
+	return call
+}
+
+// MiddlewareLLM is an LLM passing its requests through middleware.
+type MiddlewareLLM struct {
+	llm  LLM
+	call CallFunc
+}
+
+var (
+	_ LLM           = (*MiddlewareLLM)(nil)
+	_ LanguageModel = (*MiddlewareLLM)(nil)
+)
+
+// WithMiddleware wraps the LLM to pass its requests through the middleware.
+func WithMiddleware(llm LLM, middleware ...Middleware) *MiddlewareLLM {
+	return &MiddlewareLLM{
+		llm: llm,
+		call: chain(func(ctx context.Context, req *Request) ([]*Generation, error) {
+			return llm.Generate(ctx, req.Prompts, req.Options...)
+		}, middleware),
+	}
+}
+
+// Call passes the prompt through the middleware to the wrapped LLM.
+func (m *MiddlewareLLM) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	generations, err := m.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(generations) == 0 {
+		return "", nil
+	}
+	return generations[0].Text, nil
+}
+
+// Generate passes the prompts through the middleware to the wrapped LLM.
+func (m *MiddlewareLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	return m.call(ctx, &Request{Prompts: prompts, Options: options})
+}
+
+func (m *MiddlewareLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GeneratePrompt(ctx, m, promptValues, options...)
+}
+
+func (m *MiddlewareLLM) GetNumTokens(text string) int {
+	return numTokens(m.llm, text)
+}
+
+// MiddlewareChatLLM is a chat LLM passing its requests through middleware.
+type MiddlewareChatLLM struct {
+	chat ChatLLM
+	call CallFunc
+}
+
+var (
+	_ ChatLLM       = (*MiddlewareChatLLM)(nil)
+	_ LanguageModel = (*MiddlewareChatLLM)(nil)
+)
+
+// WithChatMiddleware wraps the chat LLM to pass its requests through the middleware.
+func WithChatMiddleware(chat ChatLLM, middleware ...Middleware) *MiddlewareChatLLM {
+	return &MiddlewareChatLLM{
+		chat: chat,
+		call: chain(func(ctx context.Context, req *Request) ([]*Generation, error) {
+			return chat.Generate(ctx, req.MessageSets, req.Options...)
+		}, middleware),
+	}
+}
+
+// Call passes the messages through the middleware to the wrapped chat LLM.
+func (m *MiddlewareChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := m.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0].Message == nil {
+		return &schema.AIChatMessage{}, nil
+	}
+	return generations[0].Message, nil
+}
+
+// Generate passes the message sets through the middleware to the wrapped chat LLM.
+func (m *MiddlewareChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	return m.call(ctx, &Request{MessageSets: messageSets, Options: options})
+}
+
+func (m *MiddlewareChatLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, m, promptValues, options...)
+}
+
+func (m *MiddlewareChatLLM) GetNumTokens(text string) int {
+	return numTokens(m.chat, text)
+}
@@ -0,0 +1,92 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatMiddleware(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var order []string
+	logging := func(name string) Middleware {
+		return func(next CallFunc) CallFunc {
+			return func(ctx context.Context, req *Request) ([]*Generation, error) {
+				order = append(order, name)
+				generations, err := next(ctx, req)
+				order = append(order, name+" done")
+				return generations, err
+			}
+		}
+	}
+	redact := func(next CallFunc) CallFunc {
+		return func(ctx context.Context, req *Request) ([]*Generation, error) {
+			for _, messages := range req.MessageSets {
+				for i, m := range messages {
+					if h, ok := m.(schema.HumanChatMessage); ok {
+						h.Content = strings.ReplaceAll(h.Content, "secret", "[redacted]")
+						messages[i] = h
+					}
+				}
+			}
+			req.Options = append(req.Options, WithModel("redacted-model"))
+			return next(ctx, req)
+		}
+	}
+
+	fake := &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "ok"}}}
+	chat := WithChatMiddleware(usageChatLLM{fakeChatLLM: fake}, logging("outer"), logging("inner"), redact)
+
+	msg, err := chat.Call(ctx, []schema.ChatMessage{schema.HumanChatMessage{Content: "my secret"}})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", msg.Content)
+	assert.Equal(t, []string{"outer", "inner", "inner done", "outer done"}, order)
+	assert.Equal(t, "my [redacted]", fake.calls[0][0].GetContent())
+	assert.Equal(t, "redacted-model", fake.options[0].Model)
+}
+
+func TestMiddlewareLLM(t *testing.T) {
+	t.Parallel()
+
+	var prompts []string
+	llm := WithMiddleware(&flakyLLM{}, func(next CallFunc) CallFunc {
+		return func(ctx context.Context, req *Request) ([]*Generation, error) {
+			prompts = append(prompts, req.Prompts...)
+			return next(ctx, req)
+		}
+	})
+	text, err := llm.Call(context.Background(), "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", text)
+	assert.Equal(t, []string{"hi"}, prompts)
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got http.Header
+	call := HeaderMiddleware(http.Header{"authorization": {"Bearer custom"}, "X-Trace": {"1"}})(
+		func(ctx context.Context, req *Request) ([]*Generation, error) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+			r.Header.Set("Authorization", "Bearer default")
+			SetRequestHeaders(r)
+			got = r.Header
+			return nil, nil
+		})
+	ctx := WithRequestHeaders(context.Background(), http.Header{"X-Tenant": {"a"}})
+	_, err := call(ctx, &Request{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer custom", got.Get("Authorization"))
+	assert.Equal(t, "1", got.Get("X-Trace"))
+	assert.Equal(t, "a", got.Get("X-Tenant"))
+	assert.Nil(t, RequestHeaders(context.Background()))
+}
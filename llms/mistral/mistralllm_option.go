@@ -0,0 +1,67 @@
+package mistral
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+const (
+	tokenEnvVarName = "MISTRAL_API_KEY" //nolint:gosec
+	defaultBaseURL  = "https://api.mistral.ai/v1"
+	defaultModel    = "mistral-small-latest"
+)
+
+type options struct {
+	token       string
+	model       string
+	baseURL     string
+	httpClient  openaicompat.Doer
+	rateLimiter *llms.RateLimiter
+	safePrompt  bool
+}
+
+type Option func(*options)
+
+// WithToken passes the Mistral API key to the client. If not set, the key is
+// read from the MISTRAL_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel passes the Mistral model to the client. Defaults to "mistral-small-latest".
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL passes the base url of the Mistral API to the client.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client openaicompat.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request. Share the
+// limiter between the clients of the same account.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
+
+// WithSafePrompt injects the Mistral safety prompt before the conversation.
+func WithSafePrompt(safePrompt bool) Option {
+	return func(opts *options) {
+		opts.safePrompt = safePrompt
+	}
+}
@@ -0,0 +1,109 @@
+package mistral
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "1",
+			"choices": [{"message": {"role": "assistant", "content": "Bonjour"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithSafePrompt(true))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.SystemChatMessage{Content: "Answer in French."},
+		schema.HumanChatMessage{Content: "Hello"},
+	}}, llms.WithSeed(42), llms.WithTemperature(0.2), llms.WithResponseFormat(llms.JSONMode))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Bonjour", generations[0].Message.Content)
+	assert.Equal(t, 6, generations[0].GenerationInfo["TotalTokens"])
+	assert.Equal(t, llms.Usage{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6}, generations[0].Usage)
+
+	assert.Equal(t, defaultModel, got["model"])
+	assert.Equal(t, true, got["safe_prompt"])
+	assert.Equal(t, 42.0, got["random_seed"])
+	assert.Equal(t, 0.2, got["temperature"])
+	assert.Equal(t, map[string]any{"type": "json_object"}, got["response_format"])
+	assert.Len(t, got["messages"], 2)
+}
+
+func TestChatToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "2",
+			"choices": [{"message": {"role": "assistant", "content": "", "tool_calls": [
+				{"id": "call2", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Lyon\"}"}}
+			]}, "finish_reason": "tool_calls"}]
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	tools := []llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{
+		Name:       "weather",
+		Parameters: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+	}}}
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Weather in Paris, then Lyon?"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+			ID:           "call1",
+			FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: map[string]any{"city": "Paris"}},
+		}}},
+		schema.ToolChatMessage{ID: "call1", Content: "Sunny"},
+	}}, llms.WithTools(tools), llms.WithToolChoice("any"))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, []schema.ToolCall{{
+		ID:           "call2",
+		Type:         "function",
+		FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Lyon"}`},
+	}}, generations[0].Message.ToolCalls)
+
+	assert.Equal(t, "any", got["tool_choice"])
+	require.Len(t, got["tools"], 1)
+	messages, ok := got["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 3)
+	assert.Equal(t, []any{map[string]any{
+		"id": "call1", "type": "function", "function": map[string]any{"name": "weather", "arguments": `{"city":"Paris"}`},
+	}}, messages[1].(map[string]any)["tool_calls"])
+	assert.Equal(t, "tool", messages[2].(map[string]any)["role"])
+	assert.Equal(t, "call1", messages[2].(map[string]any)["tool_call_id"])
+}
+
+func TestMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingToken)
+}
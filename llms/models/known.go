@@ -0,0 +1,166 @@
+package models
+
+// knownModels are the models known at release time, with the list prices of
+// the providers. Use Register to update them.
+//
+// nolint:gochecknoglobals,gomnd
+var knownModels = []Model{
+	// OpenAI, https://openai.com/api/pricing/.
+	{
+		Name: "gpt-4o", Provider: "openai", ContextWindow: 128000, MaxOutputTokens: 16384,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 2.5, Output: 10, CachedInput: 1.25},
+	},
+	{
+		Name: "gpt-4o-mini", Provider: "openai", ContextWindow: 128000, MaxOutputTokens: 16384,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 0.15, Output: 0.6, CachedInput: 0.075},
+	},
+	{
+		Name: "gpt-4.1", Provider: "openai", ContextWindow: 1047576, MaxOutputTokens: 32768,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 2, Output: 8, CachedInput: 0.5},
+	},
+	{
+		Name: "gpt-4.1-mini", Provider: "openai", ContextWindow: 1047576, MaxOutputTokens: 32768,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 0.4, Output: 1.6, CachedInput: 0.1},
+	},
+	{
+		Name: "gpt-4.1-nano", Provider: "openai", ContextWindow: 1047576, MaxOutputTokens: 32768,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 0.1, Output: 0.4, CachedInput: 0.025},
+	},
+	{
+		Name: "gpt-4-turbo", Provider: "openai", ContextWindow: 128000, MaxOutputTokens: 4096,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 10, Output: 30},
+	},
+	{
+		Name: "gpt-4", Provider: "openai", ContextWindow: 8192, MaxOutputTokens: 8192,
+		Tools:   true,
+		Pricing: Pricing{Input: 30, Output: 60},
+	},
+	{
+		Name: "gpt-4-32k", Provider: "openai", ContextWindow: 32768, MaxOutputTokens: 32768,
+		Tools:   true,
+		Pricing: Pricing{Input: 60, Output: 120},
+	},
+	{
+		Name: "gpt-3.5-turbo", Provider: "openai", ContextWindow: 16385, MaxOutputTokens: 4096,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.5, Output: 1.5},
+	},
+	{
+		Name: "o1", Provider: "openai", ContextWindow: 200000, MaxOutputTokens: 100000,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 15, Output: 60, CachedInput: 7.5},
+	},
+	{
+		Name: "o1-mini", Provider: "openai", ContextWindow: 128000, MaxOutputTokens: 65536,
+		Pricing: Pricing{Input: 1.1, Output: 4.4, CachedInput: 0.55},
+	},
+	{
+		Name: "o3-mini", Provider: "openai", ContextWindow: 200000, MaxOutputTokens: 100000,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 1.1, Output: 4.4, CachedInput: 0.55},
+	},
+
+	// Anthropic, https://www.anthropic.com/pricing#anthropic-api.
+	{
+		Name: "claude-3-7-sonnet", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 64000,
+		Tools: true, Vision: true,
+		Pricing: Pricing{Input: 3, Output: 15, CachedInput: 0.3, CacheWrite: 3.75},
+	},
+	{
+		Name: "claude-3-5-sonnet", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 8192,
+		Tools: true, Vision: true,
+		Pricing: Pricing{Input: 3, Output: 15, CachedInput: 0.3, CacheWrite: 3.75},
+	},
+	{
+		Name: "claude-3-5-haiku", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 8192,
+		Tools:   true,
+		Pricing: Pricing{Input: 0.8, Output: 4, CachedInput: 0.08, CacheWrite: 1},
+	},
+	{
+		Name: "claude-3-opus", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 4096,
+		Tools: true, Vision: true,
+		Pricing: Pricing{Input: 15, Output: 75, CachedInput: 1.5, CacheWrite: 18.75},
+	},
+	{
+		Name: "claude-3-sonnet", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 4096,
+		Tools: true, Vision: true,
+		Pricing: Pricing{Input: 3, Output: 15},
+	},
+	{
+		Name: "claude-3-haiku", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 4096,
+		Tools: true, Vision: true,
+		Pricing: Pricing{Input: 0.25, Output: 1.25, CachedInput: 0.03, CacheWrite: 0.3},
+	},
+	{
+		Name: "claude-2.1", Provider: "anthropic", ContextWindow: 200000, MaxOutputTokens: 4096,
+		Pricing: Pricing{Input: 8, Output: 24},
+	},
+	{
+		Name: "claude-2", Provider: "anthropic", ContextWindow: 100000, MaxOutputTokens: 4096,
+		Pricing: Pricing{Input: 8, Output: 24},
+	},
+
+	// Google, https://ai.google.dev/pricing.
+	{
+		Name: "gemini-1.5-pro", Provider: "vertexai", ContextWindow: 2097152, MaxOutputTokens: 8192,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 1.25, Output: 5},
+	},
+	{
+		Name: "gemini-1.5-flash", Provider: "vertexai", ContextWindow: 1048576, MaxOutputTokens: 8192,
+		Tools: true, Vision: true, JSON: true,
+		Pricing: Pricing{Input: 0.075, Output: 0.3},
+	},
+
+	// Mistral, https://mistral.ai/pricing#api-pricing.
+	{
+		Name: "mistral-large-latest", Provider: "mistral", ContextWindow: 128000,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 2, Output: 6},
+	},
+	{
+		Name: "mistral-small-latest", Provider: "mistral", ContextWindow: 32000,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.2, Output: 0.6},
+	},
+	{
+		Name: "open-mistral-nemo", Provider: "mistral", ContextWindow: 128000,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.15, Output: 0.15},
+	},
+
+	// Groq, https://groq.com/pricing/.
+	{
+		Name: "llama3-8b-8192", Provider: "groq", ContextWindow: 8192,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.05, Output: 0.08},
+	},
+	{
+		Name: "llama3-70b-8192", Provider: "groq", ContextWindow: 8192,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.59, Output: 0.79},
+	},
+	{
+		Name: "mixtral-8x7b-32768", Provider: "groq", ContextWindow: 32768,
+		Tools: true, JSON: true,
+		Pricing: Pricing{Input: 0.24, Output: 0.24},
+	},
+
+	// Cohere, https://cohere.com/pricing.
+	{
+		Name: "command-r-plus", Provider: "cohere", ContextWindow: 128000, MaxOutputTokens: 4096,
+		Tools:   true,
+		Pricing: Pricing{Input: 2.5, Output: 10},
+	},
+	{
+		Name: "command-r", Provider: "cohere", ContextWindow: 128000, MaxOutputTokens: 4096,
+		Tools:   true,
+		Pricing: Pricing{Input: 0.15, Output: 0.6},
+	},
+}
@@ -0,0 +1,207 @@
+// Package models provides a registry of the capabilities and the pricing of
+// the models of the providers, to validate the configuration of chains and to
+// compute the cost of the generations.
+//
+// The registry holds the models known at release time. Applications register
+// their own models, or override the known ones, with Register:
+//
+//	models.Register(models.Model{
+//		Name:          "my-fine-tuned-model",
+//		Provider:      "openai",
+//		ContextWindow: 16385,
+//		Pricing:       models.Pricing{Input: 3, Output: 6},
+//	})
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownModel is returned when a model is not in the registry.
+	ErrUnknownModel = errors.New("unknown model")
+	// ErrUnsupported is returned when a model does not support a required
+	// capability.
+	ErrUnsupported = errors.New("unsupported by the model")
+	// ErrMaxTokensExceeded is returned when the requested max tokens exceed
+	// the max output tokens of a model.
+	ErrMaxTokensExceeded = errors.New("max tokens exceed the max output tokens of the model")
+)
+
+// Model are the capabilities and the pricing of a model.
+type Model struct {
+	// Name is the name of the model sent to the provider. Versioned names,
+	// e.g. gpt-4o-2024-08-06, match the model without the version suffix.
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	// ContextWindow is the max number of tokens of the input and the output.
+	ContextWindow int `json:"context_window"`
+	// MaxOutputTokens is the max number of generated tokens, the context
+	// window if zero.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	// Tools reports whether the model supports tool calls.
+	Tools bool `json:"tools,omitempty"`
+	// Vision reports whether the model supports image inputs.
+	Vision bool `json:"vision,omitempty"`
+	// JSON reports whether the model supports a JSON response format.
+	JSON    bool    `json:"json,omitempty"`
+	Pricing Pricing `json:"pricing"`
+}
+
+// Pricing is the price of a model in US dollars per million tokens.
+type Pricing struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+	// CachedInput is the price of the input tokens read from the prompt
+	// cache, the input price if zero.
+	CachedInput float64 `json:"cached_input,omitempty"`
+	// CacheWrite is the price of the input tokens written to the prompt
+	// cache, the input price if zero.
+	CacheWrite float64 `json:"cache_write,omitempty"`
+}
+
+// Cost returns the cost in US dollars of the tokens. The input tokens
+// include the cached tokens and the tokens written to the cache.
+func (p Pricing) Cost(inputTokens, cachedTokens, cacheWriteTokens, outputTokens int) float64 {
+	cachedInput, cacheWrite := p.CachedInput, p.CacheWrite
+	if cachedInput == 0 {
+		cachedInput = p.Input
+	}
+	if cacheWrite == 0 {
+		cacheWrite = p.Input
+	}
+	uncached := inputTokens - cachedTokens - cacheWriteTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	return (float64(uncached)*p.Input +
+		float64(cachedTokens)*cachedInput +
+		float64(cacheWriteTokens)*cacheWrite +
+		float64(outputTokens)*p.Output) / 1e6
+}
+
+// Requirements are the capabilities a chain requires from a model.
+type Requirements struct {
+	// MaxTokens is the max number of generated tokens requested.
+	MaxTokens int
+	Tools     bool
+	Vision    bool
+	JSON      bool
+}
+
+// Validate returns an error if the model does not meet the requirements.
+func (m Model) Validate(req Requirements) error {
+	var errs []error
+	if req.Tools && !m.Tools {
+		errs = append(errs, fmt.Errorf("%s: tools %w", m.Name, ErrUnsupported))
+	}
+	if req.Vision && !m.Vision {
+		errs = append(errs, fmt.Errorf("%s: vision %w", m.Name, ErrUnsupported))
+	}
+	if req.JSON && !m.JSON {
+		errs = append(errs, fmt.Errorf("%s: json %w", m.Name, ErrUnsupported))
+	}
+	if maxOutput := m.MaxOutput(); req.MaxTokens > 0 && maxOutput > 0 && req.MaxTokens > maxOutput {
+		errs = append(errs, fmt.Errorf("%s: %w: %d > %d", m.Name, ErrMaxTokensExceeded, req.MaxTokens, maxOutput))
+	}
+	return errors.Join(errs...)
+}
+
+// MaxOutput returns the max number of generated tokens of the model.
+func (m Model) MaxOutput() int {
+	if m.MaxOutputTokens > 0 {
+		return m.MaxOutputTokens
+	}
+	return m.ContextWindow
+}
+
+// Registry is a registry of models, safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[string]Model
+}
+
+// NewRegistry returns a registry of the models.
+func NewRegistry(models ...Model) *Registry {
+	r := &Registry{models: make(map[string]Model, len(models))}
+	r.Register(models...)
+	return r
+}
+
+// Register adds the models to the registry, replacing the models of the
+// same name.
+func (r *Registry) Register(models ...Model) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range models {
+		r.models[m.Name] = m
+	}
+}
+
+// Lookup returns the model of the name. A name without an exact match
+// matches the longest registered name it starts with followed by a dash,
+// e.g. claude-3-5-sonnet-20241022 matches claude-3-5-sonnet.
+func (r *Registry) Lookup(name string) (Model, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if m, ok := r.models[name]; ok {
+		return m, true
+	}
+	var match Model
+	var found bool
+	for prefix, m := range r.models {
+		if strings.HasPrefix(name, prefix+"-") && len(prefix) > len(match.Name) {
+			match, found = m, true
+		}
+	}
+	if found {
+		// The model keeps the registered name.
+		return match, true
+	}
+	return Model{}, false
+}
+
+// Get returns the model of the name, or ErrUnknownModel.
+func (r *Registry) Get(name string) (Model, error) {
+	m, ok := r.Lookup(name)
+	if !ok {
+		return Model{}, fmt.Errorf("%w: %s", ErrUnknownModel, name)
+	}
+	return m, nil
+}
+
+// Models returns the registered models sorted by name.
+func (r *Registry) Models() []Model {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	models := make([]Model, 0, len(r.models))
+	for _, m := range r.models {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
+
+// Default is the registry of the known models used by the package functions.
+var Default = NewRegistry(knownModels...) //nolint:gochecknoglobals
+
+// Register adds the models to the default registry, replacing the models of
+// the same name.
+func Register(models ...Model) {
+	Default.Register(models...)
+}
+
+// Lookup returns the model of the name from the default registry.
+func Lookup(name string) (Model, bool) {
+	return Default.Lookup(name)
+}
+
+// Get returns the model of the name from the default registry, or
+// ErrUnknownModel.
+func Get(name string) (Model, error) {
+	return Default.Get(name)
+}
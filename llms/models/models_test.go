@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	m, ok := Lookup("gpt-4o")
+	require.True(t, ok)
+	assert.Equal(t, 128000, m.ContextWindow)
+
+	// Versioned names match the longest registered prefix.
+	m, ok = Lookup("gpt-4o-mini-2024-07-18")
+	require.True(t, ok)
+	assert.Equal(t, "gpt-4o-mini", m.Name)
+	m, ok = Lookup("claude-3-5-sonnet-20241022")
+	require.True(t, ok)
+	assert.Equal(t, "claude-3-5-sonnet", m.Name)
+
+	_, ok = Lookup("gpt-4.5-preview")
+	assert.False(t, ok)
+	_, err := Get("unknown")
+	require.ErrorIs(t, err, ErrUnknownModel)
+}
+
+func TestRegistryOverride(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(knownModels...)
+	r.Register(
+		Model{Name: "gpt-4o", Provider: "openai", ContextWindow: 128000, Pricing: Pricing{Input: 1, Output: 2}},
+		Model{Name: "my-model", Provider: "local", ContextWindow: 4096},
+	)
+	m, err := r.Get("gpt-4o-2024-08-06")
+	require.NoError(t, err)
+	assert.Equal(t, Pricing{Input: 1, Output: 2}, m.Pricing)
+	assert.Equal(t, 128000, m.MaxOutput())
+
+	m, err = r.Get("my-model")
+	require.NoError(t, err)
+	assert.Equal(t, 4096, m.MaxOutput())
+	assert.Len(t, r.Models(), len(knownModels)+1)
+
+	// The default registry is not changed.
+	m, _ = Lookup("gpt-4o")
+	assert.InDelta(t, 2.5, m.Pricing.Input, 1e-9)
+}
+
+func TestPricingCost(t *testing.T) {
+	t.Parallel()
+
+	p := Pricing{Input: 2, Output: 8, CachedInput: 0.5}
+	assert.InDelta(t, 0.8*2+0.2*0.5+0.5*8, p.Cost(1_000_000, 200_000, 0, 500_000), 1e-9)
+	// Cache writes are billed at the input price without a write price.
+	assert.InDelta(t, 2.0, p.Cost(1_000_000, 0, 1_000_000, 0), 1e-9)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	m, err := Get("o1-mini")
+	require.NoError(t, err)
+	require.NoError(t, m.Validate(Requirements{MaxTokens: 1000}))
+
+	err = m.Validate(Requirements{Tools: true, MaxTokens: 100000})
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.ErrorIs(t, err, ErrMaxTokensExceeded)
+}
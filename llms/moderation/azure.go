@@ -0,0 +1,152 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	azureTokenEnvVarName    = "AZURE_CONTENT_SAFETY_KEY"      //nolint:gosec
+	azureEndpointEnvVarName = "AZURE_CONTENT_SAFETY_ENDPOINT" //nolint:gosec
+	azureDefaultAPIVersion  = "2023-10-01"
+	// azureDefaultSeverityThreshold flags medium and high severities.
+	azureDefaultSeverityThreshold = 4
+	// azureMaxSeverity is the max severity of the four severity levels
+	// reported by default.
+	azureMaxSeverity = 6
+)
+
+// azureCategories maps the categories of Azure Content Safety to the
+// moderation categories.
+var azureCategories = map[string]string{ //nolint:gochecknoglobals
+	"Hate":     CategoryHate,
+	"SelfHarm": CategorySelfHarm,
+	"Sexual":   CategorySexual,
+	"Violence": CategoryViolence,
+}
+
+// AzureContentSafety is a moderator using the text analysis of Azure AI
+// Content Safety.
+type AzureContentSafety struct {
+	token             string
+	endpoint          string
+	apiVersion        string
+	severityThreshold int
+	httpClient        Doer
+}
+
+var _ Moderator = (*AzureContentSafety)(nil)
+
+// NewAzureContentSafety returns a moderator using Azure AI Content Safety.
+// The endpoint of the resource is set with WithBaseURL, or read from the
+// AZURE_CONTENT_SAFETY_ENDPOINT environment variable.
+func NewAzureContentSafety(opts ...Option) (*AzureContentSafety, error) {
+	options := newOptions(append([]Option{
+		WithToken(os.Getenv(azureTokenEnvVarName)),
+		WithBaseURL(os.Getenv(azureEndpointEnvVarName)),
+		WithAPIVersion(azureDefaultAPIVersion),
+		WithSeverityThreshold(azureDefaultSeverityThreshold),
+	}, opts...))
+	if options.token == "" {
+		return nil, fmt.Errorf("%w: set it in the AZURE_CONTENT_SAFETY_KEY environment variable", ErrMissingToken)
+	}
+	if options.baseURL == "" {
+		return nil, fmt.Errorf("missing the endpoint, set it in the %s environment variable", azureEndpointEnvVarName)
+	}
+	return &AzureContentSafety{
+		token:             options.token,
+		endpoint:          strings.TrimSuffix(options.baseURL, "/"),
+		apiVersion:        options.apiVersion,
+		severityThreshold: options.severityThreshold,
+		httpClient:        options.httpClient,
+	}, nil
+}
+
+type azureRequest struct {
+	Text string `json:"text"`
+}
+
+type azureResponse struct {
+	CategoriesAnalysis []struct {
+		Category string `json:"category"`
+		Severity int    `json:"severity"`
+	} `json:"categoriesAnalysis"`
+}
+
+type azureErrorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Moderate returns the result of each text. The API analyzes a text per
+// request.
+func (m *AzureContentSafety) Moderate(ctx context.Context, texts []string) ([]Result, error) {
+	results := make([]Result, 0, len(texts))
+	for _, text := range texts {
+		result, err := m.analyze(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (m *AzureContentSafety) analyze(ctx context.Context, text string) (Result, error) {
+	payload, err := json.Marshal(azureRequest{Text: text})
+	if err != nil {
+		return Result{}, fmt.Errorf("marshal payload: %w", err)
+	}
+	url := fmt.Sprintf("%s/contentsafety/text:analyze?api-version=%s", m.endpoint, m.apiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ocp-Apim-Subscription-Key", m.token)
+	llms.SetRequestHeaders(req)
+
+	r, err := m.httpClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp azureErrorResponse
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return Result{}, llms.NewStatusError(r, "", "")
+		}
+		return Result{}, llms.NewStatusError(r, errResp.Error.Code, errResp.Error.Message)
+	}
+
+	var response azureResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return Result{}, fmt.Errorf("decode response: %w", err)
+	}
+	result := Result{
+		Categories: make(map[string]bool, len(response.CategoriesAnalysis)),
+		Scores:     make(map[string]float64, len(response.CategoriesAnalysis)),
+	}
+	for _, analysis := range response.CategoriesAnalysis {
+		category, ok := azureCategories[analysis.Category]
+		if !ok {
+			category = strings.ToLower(analysis.Category)
+		}
+		flagged := analysis.Severity >= m.severityThreshold
+		result.Categories[category] = flagged
+		result.Scores[category] = math.Min(float64(analysis.Severity)/azureMaxSeverity, 1)
+		result.Flagged = result.Flagged || flagged
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureContentSafety(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/contentsafety/text:analyze", r.URL.Path)
+		assert.Equal(t, "2023-10-01", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "test-key", r.Header.Get("Ocp-Apim-Subscription-Key"))
+		var req azureRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		severity := 0
+		if req.Text == "violent" {
+			severity = 4
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"categoriesAnalysis": []map[string]any{
+				{"category": "Hate", "severity": 2},
+				{"category": "Violence", "severity": severity},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	moderator, err := NewAzureContentSafety(WithToken("test-key"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	results, err := moderator.Moderate(context.Background(), []string{"hello", "violent"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.False(t, results[0].Flagged)
+	assert.Equal(t, map[string]bool{CategoryHate: false, CategoryViolence: false}, results[0].Categories)
+	assert.True(t, results[1].Flagged)
+	assert.Equal(t, []string{CategoryViolence}, results[1].FlaggedCategories())
+	assert.InDelta(t, 4.0/6, results[1].Scores[CategoryViolence], 1e-9)
+
+	// A lower threshold flags low severities.
+	moderator, err = NewAzureContentSafety(WithToken("test-key"), WithBaseURL(server.URL), WithSeverityThreshold(2))
+	require.NoError(t, err)
+	results, err = moderator.Moderate(context.Background(), []string{"hello"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{CategoryHate}, results[0].FlaggedCategories())
+}
+
+func TestAzureContentSafetyMissingEndpoint(t *testing.T) {
+	t.Setenv(azureEndpointEnvVarName, "")
+
+	_, err := NewAzureContentSafety(WithToken("test-key"))
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Package moderation classifies texts as harmful with the moderation APIs of
+// the providers: the OpenAI moderation API and Azure AI Content Safety.
+//
+// The moderators are used by the chains.Moderation chain to reject, redact or
+// annotate flagged inputs before they reach the model, and flagged outputs
+// before they reach the user.
+package moderation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// ErrFlagged is returned when a text is flagged by a moderator.
+var ErrFlagged = errors.New("content flagged by moderation")
+
+// Categories of harmful content, as named by the OpenAI moderation API. The
+// moderators report the categories of other providers with these names.
+const (
+	CategoryHarassment      = "harassment"
+	CategoryHate            = "hate"
+	CategorySelfHarm        = "self-harm"
+	CategorySexual          = "sexual"
+	CategorySexualMinors    = "sexual/minors"
+	CategoryViolence        = "violence"
+	CategoryViolenceGraphic = "violence/graphic"
+	CategoryIllicit         = "illicit"
+)
+
+// Moderator classifies texts as harmful.
+type Moderator interface {
+	// Moderate returns the result of each text.
+	Moderate(ctx context.Context, texts []string) ([]Result, error)
+}
+
+// Result is the moderation of a text.
+type Result struct {
+	// Flagged reports whether the text is flagged in any category.
+	Flagged bool
+	// Categories reports whether the text is flagged, by category.
+	Categories map[string]bool
+	// Scores are the confidences, or severities, of the categories between
+	// 0 and 1.
+	Scores map[string]float64
+}
+
+// FlaggedCategories returns the sorted categories the text is flagged in.
+func (r Result) FlaggedCategories() []string {
+	var categories []string
+	for category, flagged := range r.Categories {
+		if flagged {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
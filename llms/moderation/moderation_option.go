@@ -0,0 +1,70 @@
+package moderation
+
+import "net/http"
+
+type options struct {
+	token             string
+	baseURL           string
+	model             string
+	apiVersion        string
+	severityThreshold int
+	httpClient        Doer
+}
+
+// Option is a function that configures a moderator.
+type Option func(*options)
+
+// WithToken sets the API key of the moderator. If not set, the key is read
+// from the OPENAI_API_KEY or the AZURE_CONTENT_SAFETY_KEY environment
+// variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithBaseURL sets the base URL of the API, the endpoint of the resource for
+// Azure Content Safety.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithModel sets the moderation model of OpenAI, omni-moderation-latest by
+// default.
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithAPIVersion sets the API version of Azure Content Safety.
+func WithAPIVersion(apiVersion string) Option {
+	return func(opts *options) {
+		opts.apiVersion = apiVersion
+	}
+}
+
+// WithSeverityThreshold sets the severity, from 0 to 7, from which Azure
+// Content Safety flags a category. The default is 4, medium severity.
+func WithSeverityThreshold(severity int) Option {
+	return func(opts *options) {
+		opts.severityThreshold = severity
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the moderator.
+func WithHTTPClient(client Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+func newOptions(opts []Option) *options {
+	options := &options{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
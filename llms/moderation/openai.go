@@ -0,0 +1,124 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	openAITokenEnvVarName   = "OPENAI_API_KEY"  //nolint:gosec
+	openAIBaseURLEnvVarName = "OPENAI_BASE_URL" //nolint:gosec
+	openAIDefaultBaseURL    = "https://api.openai.com/v1"
+	openAIDefaultModel      = "omni-moderation-latest"
+)
+
+// ErrMissingToken is returned when the API key of a moderator is not set.
+var ErrMissingToken = errors.New("missing the moderation API key")
+
+// OpenAI is a moderator using the OpenAI moderation API.
+type OpenAI struct {
+	token      string
+	baseURL    string
+	model      string
+	httpClient Doer
+}
+
+var _ Moderator = (*OpenAI)(nil)
+
+// NewOpenAI returns a moderator using the OpenAI moderation API.
+func NewOpenAI(opts ...Option) (*OpenAI, error) {
+	options := newOptions(append([]Option{
+		WithToken(os.Getenv(openAITokenEnvVarName)),
+		WithBaseURL(os.Getenv(openAIBaseURLEnvVarName)),
+		WithModel(openAIDefaultModel),
+	}, opts...))
+	if options.token == "" {
+		return nil, fmt.Errorf("%w: set it in the OPENAI_API_KEY environment variable", ErrMissingToken)
+	}
+	if options.baseURL == "" {
+		options.baseURL = openAIDefaultBaseURL
+	}
+	return &OpenAI{
+		token:      options.token,
+		baseURL:    strings.TrimSuffix(options.baseURL, "/"),
+		model:      options.model,
+		httpClient: options.httpClient,
+	}, nil
+}
+
+type openAIRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type openAIResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+type openAIErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Moderate returns the result of each text.
+func (m *OpenAI) Moderate(ctx context.Context, texts []string) ([]Result, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	payload, err := json.Marshal(openAIRequest{Model: m.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/moderations", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.token)
+	llms.SetRequestHeaders(req)
+
+	r, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp openAIErrorResponse
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, llms.NewStatusError(r, "", "")
+		}
+		return nil, llms.NewStatusError(r, errResp.Error.Type, errResp.Error.Message)
+	}
+
+	var response openAIResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(response.Results) != len(texts) {
+		return nil, fmt.Errorf("got %d results for %d texts", len(response.Results), len(texts))
+	}
+	results := make([]Result, len(response.Results))
+	for i, result := range response.Results {
+		results[i] = Result{
+			Flagged:    result.Flagged,
+			Categories: result.Categories,
+			Scores:     result.CategoryScores,
+		}
+	}
+	return results, nil
+}
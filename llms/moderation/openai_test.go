@@ -0,0 +1,68 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestOpenAI(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderations", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		var req openAIRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, openAIRequest{Model: "omni-moderation-latest", Input: []string{"hello", "hateful"}}, req)
+
+		_, err := w.Write([]byte(`{"results": [
+			{"flagged": false, "categories": {"hate": false}, "category_scores": {"hate": 0.01}},
+			{"flagged": true, "categories": {"hate": true, "violence": false}, "category_scores": {"hate": 0.9}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	moderator, err := NewOpenAI(WithToken("test-token"), WithBaseURL(server.URL+"/"))
+	require.NoError(t, err)
+	results, err := moderator.Moderate(context.Background(), []string{"hello", "hateful"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Flagged)
+	assert.True(t, results[1].Flagged)
+	assert.Equal(t, []string{CategoryHate}, results[1].FlaggedCategories())
+	assert.InDelta(t, 0.9, results[1].Scores[CategoryHate], 1e-9)
+}
+
+func TestOpenAIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, err := w.Write([]byte(`{"error": {"type": "invalid_request_error", "message": "bad key"}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	moderator, err := NewOpenAI(WithToken("test-token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, err = moderator.Moderate(context.Background(), []string{"hello"})
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+	assert.Equal(t, "bad key", statusErr.Message)
+}
+
+func TestOpenAIMissingToken(t *testing.T) {
+	t.Setenv(openAITokenEnvVarName, "")
+
+	_, err := NewOpenAI()
+	require.ErrorIs(t, err, ErrMissingToken)
+}
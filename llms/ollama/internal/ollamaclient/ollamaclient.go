@@ -0,0 +1,318 @@
+package ollamaclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrAPI is returned when the Ollama server responds with an error.
+var ErrAPI = errors.New("ollama api error")
+
+// Client is a client for the Ollama REST API.
+type Client struct {
+	baseURL    *url.URL
+	httpClient Doer
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// New returns a new Ollama client for the server at baseURL, e.g.
+// http://localhost:11434.
+func New(baseURL string, httpClient Doer) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse server url: %w", err)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    u,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Options are the model parameters of a request. See
+// https://github.com/jmorganca/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values.
+type Options struct {
+	NumCtx           int      `json:"num_ctx,omitempty"`
+	NumPredict       int      `json:"num_predict,omitempty"`
+	NumGPU           int      `json:"num_gpu,omitempty"`
+	NumThread        int      `json:"num_thread,omitempty"`
+	Temperature      float64  `json:"temperature,omitempty"`
+	TopK             int      `json:"top_k,omitempty"`
+	TopP             float64  `json:"top_p,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	RepeatPenalty    float64  `json:"repeat_penalty,omitempty"`
+	FrequencyPenalty float64  `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64  `json:"presence_penalty,omitempty"`
+}
+
+// Metrics are the timings and token counts of a response.
+type Metrics struct {
+	TotalDuration      time.Duration `json:"total_duration,omitempty"`
+	LoadDuration       time.Duration `json:"load_duration,omitempty"`
+	PromptEvalCount    int           `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration time.Duration `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int           `json:"eval_count,omitempty"`
+	EvalDuration       time.Duration `json:"eval_duration,omitempty"`
+}
+
+// GenerateRequest is a request to the generate endpoint.
+type GenerateRequest struct {
+	Model     string   `json:"model"`
+	Prompt    string   `json:"prompt"`
+	System    string   `json:"system,omitempty"`
+	Template  string   `json:"template,omitempty"`
+	Context   []int    `json:"context,omitempty"`
+	Images    [][]byte `json:"images,omitempty"`
+	Format    any      `json:"format,omitempty"`
+	Raw       bool     `json:"raw,omitempty"`
+	Stream    *bool    `json:"stream,omitempty"`
+	KeepAlive string   `json:"keep_alive,omitempty"`
+	Options   Options  `json:"options"`
+}
+
+// GenerateResponse is a response, or a chunk of a streaming response, of the
+// generate endpoint.
+type GenerateResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	Response  string    `json:"response"`
+	Done      bool      `json:"done"`
+	// DoneReason is the reason the generation stopped, e.g. "stop" or "length".
+	DoneReason string `json:"done_reason,omitempty"`
+	Context    []int  `json:"context,omitempty"`
+
+	Metrics
+}
+
+// Message is a message of a chat request.
+type Message struct {
+	// Role is one of "system", "user" or "assistant".
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  [][]byte `json:"images,omitempty"`
+}
+
+// ChatRequest is a request to the chat endpoint.
+type ChatRequest struct {
+	Model     string     `json:"model"`
+	Messages  []*Message `json:"messages"`
+	Format    any        `json:"format,omitempty"`
+	Stream    *bool      `json:"stream,omitempty"`
+	KeepAlive string     `json:"keep_alive,omitempty"`
+	Options   Options    `json:"options"`
+}
+
+// ChatResponse is a response, or a chunk of a streaming response, of the chat endpoint.
+type ChatResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	Message   *Message  `json:"message,omitempty"`
+	Done      bool      `json:"done"`
+	// DoneReason is the reason the generation stopped, e.g. "stop" or "length".
+	DoneReason string `json:"done_reason,omitempty"`
+
+	Metrics
+}
+
+// EmbeddingRequest is a request to the embeddings endpoint.
+type EmbeddingRequest struct {
+	Model     string  `json:"model"`
+	Prompt    string  `json:"prompt"`
+	KeepAlive string  `json:"keep_alive,omitempty"`
+	Options   Options `json:"options"`
+}
+
+// EmbeddingResponse is a response of the embeddings endpoint.
+type EmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// EmbedRequest is a request to the embed endpoint, which embeds several
+// inputs at once.
+type EmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+	// Truncate truncates the inputs longer than the context of the model
+	// instead of failing, true if nil.
+	Truncate  *bool   `json:"truncate,omitempty"`
+	KeepAlive string  `json:"keep_alive,omitempty"`
+	Options   Options `json:"options"`
+}
+
+// EmbedResponse is a response of the embed endpoint.
+type EmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float64 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count,omitempty"`
+}
+
+// GenerateResponseFunc is called for each chunk of a streaming generate response.
+type GenerateResponseFunc func(GenerateResponse) error
+
+// ChatResponseFunc is called for each chunk of a streaming chat response.
+type ChatResponseFunc func(ChatResponse) error
+
+// Generate calls the generate endpoint. The function is called for every
+// chunk if the request is streaming, otherwise once with the full response.
+func (c *Client) Generate(ctx context.Context, req *GenerateRequest, fn GenerateResponseFunc) error {
+	state := &streamState{}
+	return c.stream(ctx, "/api/generate", req, state, func(b []byte) error {
+		var resp GenerateResponse
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		state.partial.WriteString(resp.Response)
+		state.done = resp.Done
+		return fn(resp)
+	})
+}
+
+// Chat calls the chat endpoint. The function is called for every chunk if
+// the request is streaming, otherwise once with the full response.
+func (c *Client) Chat(ctx context.Context, req *ChatRequest, fn ChatResponseFunc) error {
+	state := &streamState{}
+	return c.stream(ctx, "/api/chat", req, state, func(b []byte) error {
+		var resp ChatResponse
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		if resp.Message != nil {
+			state.partial.WriteString(resp.Message.Content)
+		}
+		state.done = resp.Done
+		return fn(resp)
+	})
+}
+
+// Embed returns the embeddings of the inputs. The embed endpoint was added in
+// Ollama 0.3, older servers respond with a 404 status.
+func (c *Client) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	var resp EmbedResponse
+	err := c.stream(ctx, "/api/embed", req, nil, func(b []byte) error {
+		return json.Unmarshal(b, &resp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateEmbedding returns the embedding of the prompt with the legacy
+// embeddings endpoint.
+func (c *Client) CreateEmbedding(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	var resp EmbeddingResponse
+	err := c.stream(ctx, "/api/embeddings", req, nil, func(b []byte) error {
+		return json.Unmarshal(b, &resp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// streamState tracks the text streamed by a generation and whether its last
+// chunk was received.
+type streamState struct {
+	partial strings.Builder
+	done    bool
+}
+
+// stream posts the payload to the path and calls fn for each line of the
+// newline delimited json response. For the generations, which have a state,
+// an interruption of the stream, by the context, the connection or an error
+// event, returns a *llms.StreamError holding the text streamed so far.
+func (c *Client) stream(ctx context.Context, path string, payload any, state *streamState, fn func([]byte) error) error { //nolint:lll
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	reqURL := c.baseURL.JoinPath(path).String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+	llms.SetRequestHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode >= http.StatusBadRequest {
+		// The error message is optional, the status code is enough.
+		var errResp struct {
+			Error string `json:"error,omitempty"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
+		return fmt.Errorf("%w: %w", ErrAPI, llms.NewStatusError(r, "", errResp.Error))
+	}
+
+	stop := llms.CloseOnCancel(ctx, r.Body)
+	defer stop()
+	interrupted := func(err error) error {
+		if state == nil {
+			return err
+		}
+		return llms.NewStreamError(ctx, state.partial.String(), err)
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	// Embeddings and final chunks with the context can be large.
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 16<<20) //nolint:gomnd
+	for ctx.Err() == nil && scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var errResp struct {
+			Error string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(line, &errResp); err != nil {
+			return fmt.Errorf("unmarshal response: %w", err)
+		}
+		if errResp.Error != "" {
+			return interrupted(fmt.Errorf("%w: %w", ErrAPI, &llms.StatusError{Message: errResp.Error}))
+		}
+
+		if err := fn(line); err != nil {
+			return err
+		}
+		if state != nil && state.done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return interrupted(fmt.Errorf("read response: %w", err))
+	}
+	if ctx.Err() != nil {
+		return interrupted(ctx.Err())
+	}
+	if state != nil && !state.done {
+		return interrupted(io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
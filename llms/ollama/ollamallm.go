@@ -0,0 +1,260 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama/internal/ollamaclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	ErrEmptyResponse            = errors.New("no response")
+	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
+	ErrUnsupportedContentPart   = llms.ErrUnsupportedContentPart
+)
+
+// LLM is a client for a model served by Ollama using the generate endpoint.
+type LLM struct {
+	client  *ollamaclient.Client
+	options options
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+)
+
+// New returns a new Ollama LLM.
+func New(opts ...Option) (*LLM, error) {
+	o, c, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{
+		client:  c,
+		options: o,
+	}, nil
+}
+
+func newClient(opts ...Option) (options, *ollamaclient.Client, error) {
+	o := options{
+		serverURL: os.Getenv(serverURLEnvVarName),
+		model:     defaultModel,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.serverURL == "" {
+		o.serverURL = defaultServerURL
+	}
+
+	var doer ollamaclient.Doer
+	if o.httpClient != nil {
+		doer = o.httpClient
+	}
+	c, err := ollamaclient.New(o.serverURL, doer)
+	return o, c, err
+}
+
+// Call requests a completion for the given prompt.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	r, err := o.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(r) == 0 {
+		return "", ErrEmptyResponse
+	}
+	return r[0].Text, nil
+}
+
+// Generate requests a completion for each of the prompts.
+func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		stream := opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil
+		req := &ollamaclient.GenerateRequest{
+			Model:     o.model(opts),
+			Prompt:    prompt,
+			System:    o.options.system,
+			Format:    o.options.responseFormat(opts),
+			Stream:    &stream,
+			KeepAlive: o.options.keepAlive,
+			Options:   o.options.requestOptions(opts),
+		}
+
+		var text string
+		var last ollamaclient.GenerateResponse
+		err := o.client.Generate(ctx, req, func(resp ollamaclient.GenerateResponse) error {
+			text += resp.Response
+			last = resp
+			return llms.SendStreamChunk(ctx, opts, llms.StreamChunk{Content: resp.Response, FinishReason: resp.DoneReason})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		generations = append(generations, &llms.Generation{
+			Text:           text,
+			GenerationInfo: generationInfo(last.Metrics),
+			Usage:          usage(last.Metrics),
+		})
+	}
+
+	return generations, nil
+}
+
+func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, o, promptValues, options...)
+}
+
+func (o *LLM) GetNumTokens(text string) int {
+	return llms.CountTokens(o.options.model, text)
+}
+
+// CreateEmbedding creates embeddings for the given input texts.
+func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return createEmbedding(ctx, o.client, o.options, inputTexts)
+}
+
+func (o *LLM) model(opts llms.CallOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return o.options.model
+}
+
+// samplingLimits are the limits of Ollama on the sampling options, which does
+// not support logit biases.
+var samplingLimits = llms.SamplingLimits{} //nolint:gochecknoglobals
+
+// requestOptions merges the model options of the client with the call options.
+func (o options) requestOptions(opts llms.CallOptions) ollamaclient.Options {
+	r := o.options
+	if opts.Temperature != 0 {
+		r.Temperature = opts.Temperature
+	}
+	if opts.MaxTokens != 0 {
+		r.NumPredict = opts.MaxTokens
+	}
+	if opts.TopK != 0 {
+		r.TopK = opts.TopK
+	}
+	if opts.TopP != 0 {
+		r.TopP = opts.TopP
+	}
+	if opts.SeedSet {
+		r.Seed = &opts.Seed
+	}
+	if len(opts.StopWords) > 0 {
+		r.Stop = opts.StopWords
+	}
+	if opts.RepetitionPenalty != 0 {
+		r.RepeatPenalty = opts.RepetitionPenalty
+	}
+	if opts.FrequencyPenalty != 0 {
+		r.FrequencyPenalty = opts.FrequencyPenalty
+	}
+	if opts.PresencePenalty != 0 {
+		r.PresencePenalty = opts.PresencePenalty
+	}
+	return r
+}
+
+// responseFormat returns the format of the request. The response format of
+// the call options takes precedence over the format of the client, a JSON
+// schema is sent as is.
+func (o options) responseFormat(opts llms.CallOptions) any {
+	if opts.ResponseFormat.IsJSON() {
+		if opts.ResponseFormat.JSONSchema != nil && opts.ResponseFormat.JSONSchema.Schema != nil {
+			return opts.ResponseFormat.JSONSchema.Schema
+		}
+		return "json"
+	}
+	if o.format == "" {
+		return nil
+	}
+	return o.format
+}
+
+func usage(m ollamaclient.Metrics) llms.Usage {
+	return llms.Usage{
+		PromptTokens:     m.PromptEvalCount,
+		CompletionTokens: m.EvalCount,
+		TotalTokens:      m.PromptEvalCount + m.EvalCount,
+	}
+}
+
+func generationInfo(m ollamaclient.Metrics) map[string]any {
+	return map[string]any{
+		"PromptTokens":     m.PromptEvalCount,
+		"CompletionTokens": m.EvalCount,
+		"TotalTokens":      m.PromptEvalCount + m.EvalCount,
+		"TotalDuration":    m.TotalDuration,
+		"LoadDuration":     m.LoadDuration,
+	}
+}
+
+// createEmbedding embeds the texts with a single request to the embed
+// endpoint, or a request per text to the legacy endpoint of the servers
+// older than Ollama 0.3.
+func createEmbedding(ctx context.Context, client *ollamaclient.Client, o options, inputTexts []string) ([][]float64, error) { //nolint:lll
+	resp, err := client.Embed(ctx, &ollamaclient.EmbedRequest{
+		Model:     o.model,
+		Input:     inputTexts,
+		KeepAlive: o.keepAlive,
+		Options:   o.options,
+	})
+	var statusErr *llms.StatusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound && !isModelNotFound(statusErr):
+		return createLegacyEmbedding(ctx, client, o, inputTexts)
+	case err != nil:
+		return nil, err
+	case len(resp.Embeddings) != len(inputTexts):
+		return resp.Embeddings, ErrUnexpectedResponseLength
+	}
+	return resp.Embeddings, nil
+}
+
+// isModelNotFound reports whether the 404 error is about a model that is not
+// pulled rather than a missing endpoint.
+func isModelNotFound(err *llms.StatusError) bool {
+	return strings.Contains(err.Message, "model")
+}
+
+func createLegacyEmbedding(ctx context.Context, client *ollamaclient.Client, o options, inputTexts []string) ([][]float64, error) { //nolint:lll
+	embeddings := make([][]float64, 0, len(inputTexts))
+	for _, text := range inputTexts {
+		resp, err := client.CreateEmbedding(ctx, &ollamaclient.EmbeddingRequest{
+			Model:     o.model,
+			Prompt:    text,
+			KeepAlive: o.keepAlive,
+			Options:   o.options,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embedding) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		embeddings = append(embeddings, resp.Embedding)
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,153 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama/internal/ollamaclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Chat is a client for a model served by Ollama using the chat endpoint.
+type Chat struct {
+	client  *ollamaclient.Client
+	options options
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewChat returns a new Ollama chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	o, c, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Chat{
+		client:  c,
+		options: o,
+	}, nil
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// Generate requests a chat response for each of the message sets.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		msgs := make([]*ollamaclient.Message, 0, len(messageSet)+1)
+		if o.options.system != "" {
+			msgs = append(msgs, &ollamaclient.Message{Role: "system", Content: o.options.system})
+		}
+		for _, m := range messageSet {
+			msg := &ollamaclient.Message{
+				Content: m.GetContent(),
+			}
+			switch m.GetType() {
+			case schema.ChatMessageTypeSystem:
+				msg.Role = "system"
+			case schema.ChatMessageTypeAI:
+				msg.Role = "assistant"
+			default:
+				msg.Role = "user"
+			}
+			if humanChatMsg, ok := m.(schema.HumanChatMessage); ok {
+				images, err := imagesOfParts(humanChatMsg.Parts)
+				if err != nil {
+					return nil, err
+				}
+				msg.Images = images
+			}
+			msgs = append(msgs, msg)
+		}
+
+		model := opts.Model
+		if model == "" {
+			model = o.options.model
+		}
+		stream := opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil
+		req := &ollamaclient.ChatRequest{
+			Model:     model,
+			Messages:  msgs,
+			Format:    o.options.responseFormat(opts),
+			Stream:    &stream,
+			KeepAlive: o.options.keepAlive,
+			Options:   o.options.requestOptions(opts),
+		}
+
+		var content string
+		var last ollamaclient.ChatResponse
+		err := o.client.Chat(ctx, req, func(resp ollamaclient.ChatResponse) error {
+			last = resp
+			var text string
+			if resp.Message != nil {
+				text = resp.Message.Content
+			}
+			content += text
+			return llms.SendStreamChunk(ctx, opts, llms.StreamChunk{Content: text, FinishReason: resp.DoneReason})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		msg := &schema.AIChatMessage{Content: content}
+		generations = append(generations, &llms.Generation{
+			Message:        msg,
+			Text:           msg.Content,
+			GenerationInfo: generationInfo(last.Metrics),
+			Usage:          usage(last.Metrics),
+		})
+	}
+
+	return generations, nil
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
+
+func (o *Chat) GetNumTokens(text string) int {
+	return llms.CountTokens(o.options.model, text)
+}
+
+// CreateEmbedding creates embeddings for the given input texts.
+func (o *Chat) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return createEmbedding(ctx, o.client, o.options, inputTexts)
+}
+
+// imagesOfParts returns the images of the multimodal parts of a message. Ollama
+// only accepts inline images, the text parts are part of the message content.
+func imagesOfParts(parts []schema.ContentPart) ([][]byte, error) {
+	var images [][]byte
+	for _, part := range parts {
+		switch p := part.(type) {
+		case schema.TextPart:
+		case schema.BinaryPart:
+			images = append(images, p.Data)
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedContentPart, part)
+		}
+	}
+	return images, nil
+}
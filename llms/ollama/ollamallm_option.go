@@ -0,0 +1,100 @@
+package ollama
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tmc/langchaingo/llms/ollama/internal/ollamaclient"
+)
+
+const (
+	serverURLEnvVarName = "OLLAMA_HOST"
+	defaultServerURL    = "http://localhost:11434"
+	defaultModel        = "llama2"
+)
+
+type options struct {
+	serverURL  string
+	model      string
+	system     string
+	format     string
+	keepAlive  string
+	httpClient *http.Client
+	options    ollamaclient.Options
+}
+
+type Option func(*options)
+
+// WithServerURL sets the url of the Ollama server. If not set, the url is read
+// from the OLLAMA_HOST environment variable and defaults to http://localhost:11434.
+func WithServerURL(serverURL string) Option {
+	return func(opts *options) {
+		opts.serverURL = serverURL
+	}
+}
+
+// WithModel sets the model to use, e.g. "llama2" or "mistral". Defaults to "llama2".
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithSystemPrompt sets the system prompt, overriding the one of the modelfile.
+func WithSystemPrompt(system string) Option {
+	return func(opts *options) {
+		opts.system = system
+	}
+}
+
+// WithFormat sets the format of the response. The only supported value is "json".
+func WithFormat(format string) Option {
+	return func(opts *options) {
+		opts.format = format
+	}
+}
+
+// WithKeepAlive sets how long the model stays loaded in memory after a request.
+// A negative duration keeps the model loaded indefinitely, zero unloads it
+// immediately.
+func WithKeepAlive(keepAlive time.Duration) Option {
+	return func(opts *options) {
+		opts.keepAlive = keepAlive.String()
+	}
+}
+
+// WithHTTPClient sets the http client used to call the Ollama server.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithNumCtx sets the size of the context window of the model.
+func WithNumCtx(numCtx int) Option {
+	return func(opts *options) {
+		opts.options.NumCtx = numCtx
+	}
+}
+
+// WithNumGPU sets the number of layers offloaded to the GPU.
+func WithNumGPU(numGPU int) Option {
+	return func(opts *options) {
+		opts.options.NumGPU = numGPU
+	}
+}
+
+// WithNumThread sets the number of threads used for computation.
+func WithNumThread(numThread int) Option {
+	return func(opts *options) {
+		opts.options.NumThread = numThread
+	}
+}
+
+// WithTemperature sets the default temperature of the model. It is overridden
+// by llms.WithTemperature.
+func WithTemperature(temperature float64) Option {
+	return func(opts *options) {
+		opts.options.Temperature = temperature
+	}
+}
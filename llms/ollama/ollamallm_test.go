@@ -0,0 +1,245 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func newTestServer(t *testing.T, requests chan<- map[string]any) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+
+		switch r.URL.Path {
+		case "/api/generate":
+			if req["stream"] == true {
+				fmt.Fprintln(w, `{"response":"Hello","done":false}`)
+				switch req["prompt"] {
+				case "error event":
+					fmt.Fprintln(w, `{"error":"model unloaded"}`)
+					return
+				case "cut off":
+					return
+				}
+				fmt.Fprintln(w, `{"response":" world","done":false}`)
+				fmt.Fprintln(w, `{"response":"","done":true,"done_reason":"stop","prompt_eval_count":4,"eval_count":2}`)
+				return
+			}
+			fmt.Fprintln(w, `{"response":"Hello world","done":true,"prompt_eval_count":4,"eval_count":2}`)
+		case "/api/chat":
+			fmt.Fprintln(w, `{"message":{"role":"assistant","content":"Hi there"},"done":true}`)
+		case "/api/embeddings":
+			fmt.Fprintln(w, `{"embedding":[0.1,0.2]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":"not found"}`)
+		}
+	}))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("mistral"), WithNumCtx(4096), WithKeepAlive(time.Minute))
+	require.NoError(t, err)
+
+	text, err := llm.Call(context.Background(), "Say hello", llms.WithTemperature(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", text)
+
+	req := <-requests
+	assert.Equal(t, "mistral", req["model"])
+	assert.Equal(t, false, req["stream"])
+	assert.Equal(t, "1m0s", req["keep_alive"])
+	assert.Equal(t, map[string]any{"num_ctx": 4096.0, "temperature": 0.5}, req["options"])
+
+	var chunks []string
+	generations, err := llm.Generate(context.Background(), []string{"Say hello"},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}))
+	require.NoError(t, err)
+	<-requests
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, []string{"Hello", " world"}, chunks)
+	assert.Equal(t, 6, generations[0].GenerationInfo["TotalTokens"])
+	assert.Equal(t, 6, generations[0].Usage.TotalTokens)
+}
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithServerURL(server.URL), WithSystemPrompt("Be nice."))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Hello"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi there", msg.Content)
+
+	req := <-requests
+	assert.Equal(t, "llama2", req["model"])
+	assert.Equal(t, []any{
+		map[string]any{"role": "system", "content": "Be nice."},
+		map[string]any{"role": "user", "content": "Hello"},
+	}, req["messages"])
+
+	embeddings, err := chat.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1, 0.2}, {0.1, 0.2}}, embeddings)
+}
+
+func TestResponseFormat(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithServerURL(server.URL), WithFormat("json"))
+	require.NoError(t, err)
+
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hello"}}
+	_, err = chat.Call(context.Background(), messages)
+	require.NoError(t, err)
+	assert.Equal(t, "json", (<-requests)["format"])
+
+	_, err = chat.Call(context.Background(), messages, llms.WithResponseFormat(llms.JSONSchemaFormat(llms.JSONSchema{
+		Name:   "greeting",
+		Schema: map[string]any{"type": "object"},
+	})))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "object"}, (<-requests)["format"])
+}
+
+func TestChatImages(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "What is this?", Parts: []schema.ContentPart{
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")},
+		}},
+	})
+	require.NoError(t, err)
+	req := <-requests
+	assert.Equal(t, []any{
+		map[string]any{"role": "user", "content": "What is this?", "images": []any{"cG5n"}},
+	}, req["messages"])
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Parts: []schema.ContentPart{schema.ImageURLPart{URL: "https://example.com/cat.png"}}},
+	})
+	assert.ErrorIs(t, err, ErrUnsupportedContentPart)
+}
+
+func TestGenerateStreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	generations, err := llm.Generate(context.Background(), []string{"Hi"},
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, true, (<-requests)["stream"])
+	assert.Equal(t, "Hello world", generations[0].Text)
+	assert.Equal(t, []llms.StreamChunk{{Content: "Hello"}, {Content: " world"}, {FinishReason: "stop"}}, chunks)
+}
+
+func TestGenerateStreamInterrupted(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan map[string]any, 10)
+	server := newTestServer(t, requests)
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithServerURL(server.URL))
+	require.NoError(t, err)
+	stream := llms.WithStreamingFunc(func(context.Context, []byte) error { return nil })
+
+	_, err = llm.Call(context.Background(), "error event", stream)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+	assert.Contains(t, err.Error(), "model unloaded")
+
+	_, err = llm.Call(context.Background(), "cut off", stream)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hello", streamErr.Partial)
+}
+
+func TestCreateEmbedding(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req["model"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":"model \"missing\" not found, try pulling it first"}`)
+			return
+		}
+		assert.Equal(t, "nomic-embed-text", req["model"])
+		assert.Equal(t, []any{"a", "b"}, req["input"])
+		fmt.Fprintln(w, `{"model":"nomic-embed-text","embeddings":[[0.1,0.2],[0.3,0.4]]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("nomic-embed-text"))
+	require.NoError(t, err)
+
+	embeddings, err := llm.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1, 0.2}, {0.3, 0.4}}, embeddings)
+
+	llm, err = New(WithServerURL(server.URL), WithModel("missing"))
+	require.NoError(t, err)
+
+	_, err = llm.CreateEmbedding(context.Background(), []string{"a"})
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+}
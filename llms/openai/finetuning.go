@@ -0,0 +1,128 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms/finetuning"
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
+
+// fineTunedModelPrefix is the prefix of the names of the fine-tuned models.
+const fineTunedModelPrefix = "ft:"
+
+// FineTuning manages the fine-tuning jobs of the OpenAI API.
+type FineTuning struct {
+	client *openaiclient.Client
+}
+
+var _ finetuning.Provider = (*FineTuning)(nil)
+
+// NewFineTuning returns a client of the fine-tuning API configured with the
+// options of the LLMs.
+func NewFineTuning(opts ...Option) (*FineTuning, error) {
+	c, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &FineTuning{client: c}, nil
+}
+
+// UploadFile uploads a JSONL training or validation file and returns its id.
+func (f *FineTuning) UploadFile(ctx context.Context, name string, data io.Reader) (string, error) {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	return f.client.UploadFineTuningFile(ctx, name, content)
+}
+
+// CreateJob creates a fine-tuning job.
+func (f *FineTuning) CreateJob(ctx context.Context, req finetuning.JobRequest) (*finetuning.Job, error) {
+	r := &openaiclient.FineTuningJobRequest{
+		Model:          req.Model,
+		TrainingFile:   req.TrainingFile,
+		ValidationFile: req.ValidationFile,
+		Suffix:         req.Suffix,
+		Seed:           req.Seed,
+	}
+	if h := req.Hyperparameters; h != (finetuning.Hyperparameters{}) {
+		r.Hyperparameters = &openaiclient.FineTuningHyperparameters{
+			NEpochs:                h.Epochs,
+			BatchSize:              h.BatchSize,
+			LearningRateMultiplier: h.LearningRateMultiplier,
+		}
+	}
+	return fineTuningJob(f.client.CreateFineTuningJob(ctx, r))
+}
+
+// GetJob returns the job of the id.
+func (f *FineTuning) GetJob(ctx context.Context, id string) (*finetuning.Job, error) {
+	return fineTuningJob(f.client.GetFineTuningJob(ctx, id))
+}
+
+// CancelJob cancels the job of the id.
+func (f *FineTuning) CancelJob(ctx context.Context, id string) (*finetuning.Job, error) {
+	return fineTuningJob(f.client.CancelFineTuningJob(ctx, id))
+}
+
+// ListJobs lists the jobs, the most recent first.
+func (f *FineTuning) ListJobs(ctx context.Context) ([]finetuning.Job, error) {
+	result, err := f.client.ListFineTuningJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]finetuning.Job, 0, len(result))
+	for i := range result {
+		jobs = append(jobs, convertFineTuningJob(&result[i]))
+	}
+	return jobs, nil
+}
+
+// ListModels lists the fine-tuned models of the organization.
+func (f *FineTuning) ListModels(ctx context.Context) ([]string, error) {
+	result, err := f.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, m := range result {
+		if strings.HasPrefix(m.ID, fineTunedModelPrefix) {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+func fineTuningJob(job *openaiclient.FineTuningJob, err error) (*finetuning.Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	converted := convertFineTuningJob(job)
+	return &converted, nil
+}
+
+func convertFineTuningJob(job *openaiclient.FineTuningJob) finetuning.Job {
+	converted := finetuning.Job{
+		ID:             job.ID,
+		Model:          job.Model,
+		Status:         finetuning.Status(job.Status),
+		FineTunedModel: job.FineTunedModel,
+		TrainingFile:   job.TrainingFile,
+		ValidationFile: job.ValidationFile,
+		TrainedTokens:  job.TrainedTokens,
+	}
+	if job.CreatedAt > 0 {
+		converted.CreatedAt = time.Unix(job.CreatedAt, 0)
+	}
+	if job.FinishedAt > 0 {
+		converted.FinishedAt = time.Unix(job.FinishedAt, 0)
+	}
+	if job.Error != nil {
+		converted.Error = job.Error.Message
+	}
+	return converted
+}
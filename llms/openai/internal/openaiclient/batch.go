@@ -0,0 +1,300 @@
+package openaiclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	batchEndpoint            = "/v1/chat/completions"
+	batchCompletionWindow    = "24h"
+	defaultBatchPollInterval = 30 * time.Second
+)
+
+var (
+	// ErrBatchFailed is returned when a batch fails, expires or is cancelled.
+	ErrBatchFailed = errors.New("batch failed")
+	// ErrBatchUnsupported is returned when the Batch API is used with Azure.
+	ErrBatchUnsupported = errors.New("batch api is not supported with azure")
+	// ErrBatchResultMissing is returned for a request without result in the
+	// output of a batch.
+	ErrBatchResultMissing = errors.New("missing batch result")
+)
+
+// Batch is a batch of the Batch API.
+type Batch struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	InputFileID  string `json:"input_file_id"`
+	OutputFileID string `json:"output_file_id"`
+	ErrorFileID  string `json:"error_file_id"`
+	Errors       *struct {
+		Data []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"data"`
+	} `json:"errors"`
+}
+
+// BatchChatResult is the result of a chat request of a batch.
+type BatchChatResult struct {
+	Response *ChatResponse
+	Err      error
+}
+
+type batchRequestLine struct {
+	CustomID string       `json:"custom_id"`
+	Method   string       `json:"method"`
+	URL      string       `json:"url"`
+	Body     *ChatRequest `json:"body"`
+}
+
+type batchResponseLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateChatBatch sends the chat requests with the Batch API and waits for
+// the batch to complete, polling its status at the interval set with
+// WithBatchPollInterval. The results are in the order of the requests.
+func (c *Client) CreateChatBatch(ctx context.Context, requests []*ChatRequest) ([]BatchChatResult, error) {
+	if IsAzure(c.apiType) {
+		return nil, ErrBatchUnsupported
+	}
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	for i, r := range requests {
+		if r.Model == "" {
+			r.Model = c.Model
+		}
+		if r.Model == "" {
+			r.Model = defaultChatModel
+		}
+		if r.FunctionCallBehavior == "" && len(r.Functions) > 0 {
+			r.FunctionCallBehavior = defaultFunctionCallBehavior
+		}
+		r.setReasoningDefaults()
+		line := batchRequestLine{CustomID: strconv.Itoa(i), Method: http.MethodPost, URL: batchEndpoint, Body: r}
+		if err := encoder.Encode(line); err != nil {
+			return nil, fmt.Errorf("encode batch request: %w", err)
+		}
+	}
+
+	fileID, err := c.uploadFile(ctx, "batch", "batch.jsonl", input.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	var batch Batch
+	err = c.doJSON(ctx, http.MethodPost, "/batches", map[string]string{
+		"input_file_id":     fileID,
+		"endpoint":          batchEndpoint,
+		"completion_window": batchCompletionWindow,
+	}, &batch)
+	if err != nil {
+		return nil, err
+	}
+
+	for !batchDone(batch.Status) {
+		timer := time.NewTimer(c.batchPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if err := c.doJSON(ctx, http.MethodGet, "/batches/"+batch.ID, nil, &batch); err != nil {
+			return nil, err
+		}
+	}
+	if batch.Status != "completed" {
+		return nil, fmt.Errorf("%w: batch %s is %s%s", ErrBatchFailed, batch.ID, batch.Status, batch.errorMessage())
+	}
+
+	results := make([]BatchChatResult, len(requests))
+	for i := range results {
+		results[i].Err = ErrBatchResultMissing
+	}
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := c.readBatchResults(ctx, fileID, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func batchDone(status string) bool {
+	switch status {
+	case "completed", "failed", "expired", "cancelled":
+		return true
+	default:
+		return false
+	}
+}
+
+func (b Batch) errorMessage() string {
+	if b.Errors == nil || len(b.Errors.Data) == 0 {
+		return ""
+	}
+	messages := make([]string, 0, len(b.Errors.Data))
+	for _, e := range b.Errors.Data {
+		messages = append(messages, e.Message)
+	}
+	return ": " + strings.Join(messages, "; ")
+}
+
+// readBatchResults reads the output or error file of a batch into the results.
+func (c *Client) readBatchResults(ctx context.Context, fileID string, results []BatchChatResult) error {
+	body, err := c.do(ctx, http.MethodGet, "/files/"+fileID+"/content", "", nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 16<<20) //nolint:gomnd
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line batchResponseLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("decode batch result: %w", err)
+		}
+		i, err := strconv.Atoi(line.CustomID)
+		if err != nil || i < 0 || i >= len(results) {
+			continue
+		}
+		results[i] = batchResult(line)
+	}
+	return scanner.Err()
+}
+
+func batchResult(line batchResponseLine) BatchChatResult {
+	if line.Error != nil {
+		return BatchChatResult{Err: &llms.StatusError{Code: line.Error.Code, Message: line.Error.Message}}
+	}
+	if line.Response == nil {
+		return BatchChatResult{Err: ErrBatchResultMissing}
+	}
+	if line.Response.StatusCode != http.StatusOK {
+		var errResp errorMessage
+		_ = json.Unmarshal(line.Response.Body, &errResp)
+		return BatchChatResult{Err: &llms.StatusError{
+			StatusCode: line.Response.StatusCode,
+			Code:       errResp.code(),
+			Message:    errResp.Error.Message,
+		}}
+	}
+	var response ChatResponse
+	if err := json.Unmarshal(line.Response.Body, &response); err != nil {
+		return BatchChatResult{Err: fmt.Errorf("decode batch response: %w", err)}
+	}
+	if len(response.Choices) == 0 {
+		return BatchChatResult{Err: ErrEmptyResponse}
+	}
+	return BatchChatResult{Response: &response}
+}
+
+// uploadFile uploads the file for the purpose and returns its id.
+func (c *Client) uploadFile(ctx context.Context, purpose, name string, data []byte) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("purpose", purpose); err != nil {
+		return "", err
+	}
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	r, err := c.do(ctx, http.MethodPost, "/files", w.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return "", fmt.Errorf("decode file: %w", err)
+	}
+	return file.ID, nil
+}
+
+// doJSON sends the payload, if any, as json and decodes the json response into v.
+func (c *Client) doJSON(ctx context.Context, method, path string, payload, v any) error {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+	r, err := c.do(ctx, method, path, "application/json", body)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return json.NewDecoder(r).Decode(v)
+}
+
+// do sends the request and returns the body of a successful response.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader) (io.ReadCloser, error) {
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		defer r.Body.Close()
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, llms.NewStatusError(r, "", "")
+		}
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
+	}
+	return r.Body, nil
+}
@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -18,16 +20,26 @@ const (
 
 // ChatRequest is a request to create an embedding.
 type ChatRequest struct {
-	Model            string         `json:"model"`
-	Messages         []*ChatMessage `json:"messages"`
-	Temperature      float64        `json:"temperature,omitempty"`
-	TopP             float64        `json:"top_p,omitempty"`
-	MaxTokens        int            `json:"max_tokens,omitempty"`
+	Model       string         `json:"model"`
+	Messages    []*ChatMessage `json:"messages"`
+	Temperature float64        `json:"temperature,omitempty"`
+	TopP        float64        `json:"top_p,omitempty"`
+	MaxTokens   int            `json:"max_tokens,omitempty"`
+	// MaxCompletionTokens replaces MaxTokens for reasoning models, it
+	// includes the reasoning tokens.
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
+	// ReasoningEffort is "low", "medium" or "high" for reasoning models.
+	ReasoningEffort  string         `json:"reasoning_effort,omitempty"`
 	N                int            `json:"n,omitempty"`
 	StopWords        []string       `json:"stop,omitempty"`
 	Stream           bool           `json:"stream,omitempty"`
+	StreamOptions    *StreamOptions `json:"stream_options,omitempty"`
 	FrequencyPenalty float64        `json:"frequency_penalty,omitempty"`
 	PresencePenalty  float64        `json:"presence_penalty,omitempty"`
+	// Seed makes the sampling mostly deterministic.
+	Seed *int `json:"seed,omitempty"`
+	// LogitBias maps token ids to a bias between -100 and 100.
+	LogitBias map[string]int `json:"logit_bias,omitempty"`
 
 	// Function defitions to include in the request.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
@@ -37,9 +49,89 @@ type ChatRequest struct {
 	// `{"name": "my_function"}`
 	FunctionCallBehavior FunctionCallBehavior `json:"function_call,omitempty"`
 
+	// Tools is a list of tools the model may call.
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolChoice is either "none", "auto", "required" or a ToolChoice
+	// object forcing the use of a specific tool.
+	ToolChoice any `json:"tool_choice,omitempty"`
+
+	// ResponseFormat constrains the format of the response.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// LogProbs requests the log probabilities of the generated tokens.
+	LogProbs bool `json:"logprobs,omitempty"`
+	// TopLogProbs is the number of most likely tokens returned at each position.
+	TopLogProbs int `json:"top_logprobs,omitempty"`
+
+	// PromptCacheKey groups requests sharing a prompt prefix to improve cache hits.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
+
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each structured chunk
+	// of a streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
+}
+
+// ErrStream is returned when a streaming response reports an error. The
+// error of the event is a *llms.StatusError.
+var ErrStream = errors.New("stream error")
+
+// maxStreamLineSize is the maximum size of a line of a streaming response.
+const maxStreamLineSize = 1 << 20
+
+func (r *ChatRequest) streaming() bool {
+	return r.StreamingFunc != nil || r.StreamingChunkFunc != nil
+}
+
+// isReasoningModel reports whether the model is a reasoning model of the
+// o-series.
+func isReasoningModel(model string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4"} {
+		if model == prefix || strings.HasPrefix(model, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// setReasoningDefaults sends the max tokens as max completion tokens to
+// reasoning models, which reject max tokens.
+func (r *ChatRequest) setReasoningDefaults() {
+	if (isReasoningModel(r.Model) || r.ReasoningEffort != "") && r.MaxTokens > 0 {
+		r.MaxCompletionTokens = r.MaxTokens
+		r.MaxTokens = 0
+	}
+}
+
+// estimateTokens estimates the input tokens and the max tokens of the request
+// for rate limiting.
+func (r *ChatRequest) estimateTokens() int {
+	texts := make([]string, 0, len(r.Messages))
+	for _, m := range r.Messages {
+		texts = append(texts, m.Content)
+		for _, part := range m.MultiContent {
+			texts = append(texts, part.Text)
+		}
+	}
+	// Only one of the max tokens is set, see setReasoningDefaults.
+	return llms.EstimateTokens(texts...) + r.MaxTokens + r.MaxCompletionTokens
+}
+
+// ResponseFormat is the format of the response, "text", "json_object" or
+// "json_schema".
+type ResponseFormat struct {
+	Type       string                    `json:"type"`
+	JSONSchema *ResponseFormatJSONSchema `json:"json_schema,omitempty"`
+}
+
+// ResponseFormatJSONSchema is the schema of a "json_schema" response format.
+type ResponseFormatJSONSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      any    `json:"schema"`
+	Strict      bool   `json:"strict,omitempty"`
 }
 
 // ChatMessage is a message in a chat request.
@@ -48,12 +140,57 @@ type ChatMessage struct {
 	Role string `json:"role"`
 	// The content of the message.
 	Content string `json:"content"`
+	// MultiContent are the parts of a multimodal message, sent in place of
+	// Content when set.
+	MultiContent []ContentPart `json:"-"`
 	// The name of the author of this message. May contain a-z, A-Z, 0-9, and underscores,
 	// with a maximum length of 64 characters.
 	Name string `json:"name,omitempty"`
 
 	// FunctionCall represents a function call to be made in the message.
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// ToolCalls are the tool calls requested by the model.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is the id of the tool call a tool message is the result of.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// ReasoningContent is the reasoning of the response of reasoning models
+	// on OpenAI compatible servers, e.g. DeepSeek.
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+}
+
+// ContentPart is a part of the content of a multimodal message.
+type ContentPart struct {
+	// Type is one of "text", "image_url" or "file".
+	Type     string       `json:"type"`
+	Text     string       `json:"text,omitempty"`
+	ImageURL *ImageURL    `json:"image_url,omitempty"`
+	File     *FileContent `json:"file,omitempty"`
+}
+
+// ImageURL is an image of a multimodal message, referenced by url or as a data url.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// FileContent is a file of a multimodal message, given as a data url.
+type FileContent struct {
+	Filename string `json:"filename,omitempty"`
+	FileData string `json:"file_data"`
+}
+
+// MarshalJSON sends the multi content of the message in place of the content when set.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	type chatMessage ChatMessage
+	if len(m.MultiContent) == 0 {
+		return json.Marshal(chatMessage(m))
+	}
+	return json.Marshal(struct {
+		chatMessage
+		Content []ContentPart `json:"content"`
+	}{chatMessage: chatMessage(m), Content: m.MultiContent})
 }
 
 // ChatChoice is a choice in a chat response.
@@ -61,6 +198,12 @@ type ChatChoice struct {
 	Index        int         `json:"index"`
 	Message      ChatMessage `json:"message"`
 	FinishReason string      `json:"finish_reason"`
+	LogProbs     *LogProbs   `json:"logprobs,omitempty"`
+}
+
+// LogProbs are the log probabilities of the tokens of a choice.
+type LogProbs struct {
+	Content []llms.TokenLogProb `json:"content"`
 }
 
 // ChatUsage is the usage of a chat completion request.
@@ -77,11 +220,28 @@ type ChatResponse struct {
 	Choices []*ChatChoice `json:"choices,omitempty"`
 	Model   string        `json:"model,omitempty"`
 	Object  string        `json:"object,omitempty"`
-	Usage   struct {
-		CompletionTokens float64 `json:"completion_tokens,omitempty"`
-		PromptTokens     float64 `json:"prompt_tokens,omitempty"`
-		TotalTokens      float64 `json:"total_tokens,omitempty"`
-	} `json:"usage,omitempty"`
+	Usage   ResponseUsage `json:"usage,omitempty"`
+	// SystemFingerprint identifies the backend configuration of the response.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+}
+
+// ResponseUsage is the token usage of a chat response.
+type ResponseUsage struct {
+	CompletionTokens    float64 `json:"completion_tokens,omitempty"`
+	PromptTokens        float64 `json:"prompt_tokens,omitempty"`
+	TotalTokens         float64 `json:"total_tokens,omitempty"`
+	PromptTokensDetails struct {
+		CachedTokens float64 `json:"cached_tokens,omitempty"`
+	} `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails struct {
+		ReasoningTokens float64 `json:"reasoning_tokens,omitempty"`
+	} `json:"completion_tokens_details,omitempty"`
+}
+
+// StreamOptions are the options of a streaming request.
+type StreamOptions struct {
+	// IncludeUsage adds a last chunk holding the usage of the request.
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // StreamedChatResponsePayload is a chunk from the stream.
@@ -95,9 +255,16 @@ type StreamedChatResponsePayload struct {
 		Delta struct {
 			Role    string `json:"role,omitempty"`
 			Content string `json:"content,omitempty"`
+			// ReasoningContent is sent by OpenAI compatible servers of
+			// reasoning models, e.g. DeepSeek.
+			ReasoningContent string          `json:"reasoning_content,omitempty"`
+			ToolCalls        []ToolCallDelta `json:"tool_calls,omitempty"`
 		} `json:"delta,omitempty"`
-		FinishReason interface{} `json:"finish_reason,omitempty"`
+		LogProbs     *LogProbs `json:"logprobs,omitempty"`
+		FinishReason string    `json:"finish_reason,omitempty"`
 	} `json:"choices,omitempty"`
+	Usage             *ResponseUsage `json:"usage,omitempty"`
+	SystemFingerprint string         `json:"system_fingerprint,omitempty"`
 }
 
 // FunctionDefinition is a definition of a function that can be called by the model.
@@ -122,6 +289,40 @@ const (
 	FunctionCallBehaviorAuto FunctionCallBehavior = "auto"
 )
 
+// Tool is a tool the model may call.
+type Tool struct {
+	// Type is the type of the tool, "function" is the only supported type.
+	Type string `json:"type"`
+	// Function is the definition of the function.
+	Function FunctionDefinition `json:"function"`
+}
+
+// ToolChoice forces the model to use a specific tool.
+type ToolChoice struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// ToolCall is a call to a tool requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// ToolCallDelta is a part of a tool call of a streaming response.
+type ToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
 // FunctionCall is a call to a function.
 type FunctionCall struct {
 	// Name is the name of the function to call.
@@ -131,8 +332,9 @@ type FunctionCall struct {
 }
 
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatResponse, error) {
-	if payload.StreamingFunc != nil {
+	if payload.streaming() {
 		payload.Stream = true
+		payload.StreamOptions = &StreamOptions{IncludeUsage: true}
 	}
 	// Build request payload
 	payloadBytes, err := json.Marshal(payload)
@@ -145,12 +347,14 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	if c.baseURL == "" {
 		c.baseURL = defaultBaseURL
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/chat/completions", c.Model), body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/chat/completions", payload.Model), body)
 	if err != nil {
 		return nil, err
 	}
 
-	c.setHeaders(req)
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
 
 	// Send request
 	r, err := c.httpClient.Do(req)
@@ -160,18 +364,16 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, llms.NewStatusError(r, "", "")
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
 	}
-	if payload.StreamingFunc != nil {
+	if payload.streaming() {
 		return parseStreamingChatResponse(ctx, r, payload)
 	}
 	// Parse response
@@ -179,34 +381,15 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	return &response, json.NewDecoder(r.Body).Decode(&response)
 }
 
-func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *ChatRequest) (*ChatResponse, error) {
+// parseStreamingChatResponse reads the server sent events of a streaming
+// response and assembles them into a single response. If the stream is
+// interrupted, by the context, the connection or an error event, it returns a
+// *llms.StreamError holding the content streamed so far.
+func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *ChatRequest) (*ChatResponse, error) { //nolint:lll
+	stop := llms.CloseOnCancel(ctx, r.Body)
+	defer stop()
 	scanner := bufio.NewScanner(r.Body)
-	responseChan := make(chan StreamedChatResponsePayload)
-	go func() {
-		defer close(responseChan)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				continue
-			}
-			if !strings.HasPrefix(line, "data:") {
-				log.Fatalf("unexpected line: %v", line)
-			}
-			data := strings.TrimPrefix(line, "data: ")
-			if data == "[DONE]" {
-				return
-			}
-			var streamPayload StreamedChatResponsePayload
-			err := json.NewDecoder(bytes.NewReader([]byte(data))).Decode(&streamPayload)
-			if err != nil {
-				log.Fatalf("failed to decode stream payload: %v", err)
-			}
-			responseChan <- streamPayload
-		}
-		if err := scanner.Err(); err != nil {
-			log.Println("issue scanning response:", err)
-		}
-	}()
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxStreamLineSize)
 	// Parse response
 	response := ChatResponse{
 		Choices: []*ChatChoice{
@@ -214,15 +397,107 @@ func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *
 		},
 	}
 
-	for streamResponse := range responseChan {
-		if payload.StreamingFunc != nil {
-			response.Choices[0].Message.Content += streamResponse.Choices[0].Delta.Content
+	// positions maps the index of a tool call delta to its tool call.
+	positions := make(map[int]int)
+	var arguments []*strings.Builder
+	interrupted := func(err error) error {
+		return llms.NewStreamError(ctx, response.Choices[0].Message.Content, err)
+	}
+	done := false
+	for !done && ctx.Err() == nil && scanner.Scan() {
+		// Compatible servers send comments, e.g. the keep-alives of
+		// OpenRouter, and event lines besides the data lines.
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var errResp errorMessage
+		if err := json.Unmarshal([]byte(data), &errResp); err != nil {
+			return nil, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if errResp.Error.Message != "" {
+			return nil, interrupted(fmt.Errorf("%w: %w", ErrStream,
+				&llms.StatusError{Code: errResp.code(), Message: errResp.Error.Message}))
+		}
+		var streamResponse StreamedChatResponsePayload
+		if err := json.Unmarshal([]byte(data), &streamResponse); err != nil {
+			return nil, fmt.Errorf("decode stream chunk: %w", err)
+		}
+
+		if streamResponse.Usage != nil {
+			response.Usage = *streamResponse.Usage
+		}
+		if streamResponse.SystemFingerprint != "" {
+			response.SystemFingerprint = streamResponse.SystemFingerprint
+		}
+		// The chunk holding the usage has no choices.
+		if len(streamResponse.Choices) == 0 {
+			continue
+		}
+		choice := streamResponse.Choices[0]
+		if choice.LogProbs != nil {
+			if response.Choices[0].LogProbs == nil {
+				response.Choices[0].LogProbs = &LogProbs{}
+			}
+			response.Choices[0].LogProbs.Content = append(response.Choices[0].LogProbs.Content, choice.LogProbs.Content...)
+		}
+		if choice.FinishReason != "" {
+			response.Choices[0].FinishReason = choice.FinishReason
+		}
+		response.Choices[0].Message.Content += choice.Delta.Content
+		response.Choices[0].Message.ReasoningContent += choice.Delta.ReasoningContent
+		chunk := llms.StreamChunk{
+			Content:      choice.Delta.Content,
+			Reasoning:    choice.Delta.ReasoningContent,
+			FinishReason: choice.FinishReason,
+		}
+		for _, delta := range choice.Delta.ToolCalls {
+			position, ok := positions[delta.Index]
+			if !ok {
+				position = len(arguments)
+				positions[delta.Index] = position
+				arguments = append(arguments, &strings.Builder{})
+				response.Choices[0].Message.ToolCalls = append(response.Choices[0].Message.ToolCalls, ToolCall{
+					ID:       delta.ID,
+					Type:     delta.Type,
+					Function: FunctionCall{Name: delta.Function.Name},
+				})
+			}
+			arguments[position].WriteString(delta.Function.Arguments)
+			chunk.ToolCalls = append(chunk.ToolCalls, llms.ToolCallChunk{
+				Index:     delta.Index,
+				ID:        delta.ID,
+				Name:      delta.Function.Name,
+				Arguments: delta.Function.Arguments,
+			})
+		}
 
-			err := payload.StreamingFunc(ctx, []byte(streamResponse.Choices[0].Delta.Content))
-			if err != nil {
+		if payload.StreamingFunc != nil && choice.Delta.Content != "" {
+			if err := payload.StreamingFunc(ctx, []byte(choice.Delta.Content)); err != nil {
+				return nil, fmt.Errorf("streaming func returned an error: %w", err)
+			}
+		}
+		if payload.StreamingChunkFunc != nil && !chunk.IsEmpty() {
+			if err := payload.StreamingChunkFunc(ctx, chunk); err != nil {
 				return nil, fmt.Errorf("streaming func returned an error: %w", err)
 			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, interrupted(fmt.Errorf("read stream: %w", err))
+	}
+	// Compatible servers may omit the [DONE] event, the finish reason tells
+	// that the response is complete.
+	if ctx.Err() != nil || (!done && response.Choices[0].FinishReason == "") {
+		return nil, interrupted(io.ErrUnexpectedEOF)
+	}
+	for i := range arguments {
+		response.Choices[0].Message.ToolCalls[i].Function.Arguments = arguments[i].String()
+	}
 	return &response, nil
 }
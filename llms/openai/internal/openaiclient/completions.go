@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -14,42 +16,114 @@ const (
 )
 
 type completionPayload struct {
-	Model            string   `json:"model"`
-	Prompt           string   `json:"prompt"`
-	Temperature      float64  `json:"temperature,omitempty"`
-	MaxTokens        int      `json:"max_tokens,omitempty"`
-	N                int      `json:"n,omitempty"`
-	FrequencyPenalty float64  `json:"frequency_penalty,omitempty"`
-	PresencePenalty  float64  `json:"presence_penalty,omitempty"`
-	TopP             float64  `json:"top_p,omitempty"`
-	StopWords        []string `json:"stop,omitempty"`
+	Model            string         `json:"model"`
+	Prompt           string         `json:"prompt"`
+	Temperature      float64        `json:"temperature,omitempty"`
+	MaxTokens        int            `json:"max_tokens,omitempty"`
+	N                int            `json:"n,omitempty"`
+	FrequencyPenalty float64        `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64        `json:"presence_penalty,omitempty"`
+	TopP             float64        `json:"top_p,omitempty"`
+	StopWords        []string       `json:"stop,omitempty"`
+	Logprobs         *int           `json:"logprobs,omitempty"`
+	Seed             *int           `json:"seed,omitempty"`
+	LogitBias        map[string]int `json:"logit_bias,omitempty"`
 }
 
 type completionResponsePayload struct {
 	ID      string  `json:"id,omitempty"`
 	Created float64 `json:"created,omitempty"`
 	Choices []struct {
-		FinishReason string      `json:"finish_reason,omitempty"`
-		Index        float64     `json:"index,omitempty"`
-		Logprobs     interface{} `json:"logprobs,omitempty"`
-		Text         string      `json:"text,omitempty"`
+		FinishReason string              `json:"finish_reason,omitempty"`
+		Index        float64             `json:"index,omitempty"`
+		Logprobs     *completionLogprobs `json:"logprobs,omitempty"`
+		Text         string              `json:"text,omitempty"`
 	} `json:"choices,omitempty"`
-	Model  string `json:"model,omitempty"`
-	Object string `json:"object,omitempty"`
-	Usage  struct {
+	Model             string `json:"model,omitempty"`
+	Object            string `json:"object,omitempty"`
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	Usage             struct {
 		CompletionTokens float64 `json:"completion_tokens,omitempty"`
 		PromptTokens     float64 `json:"prompt_tokens,omitempty"`
 		TotalTokens      float64 `json:"total_tokens,omitempty"`
 	} `json:"usage,omitempty"`
 }
 
+// completionLogprobs are the log probabilities of the tokens of a completion
+// choice, in the format of the legacy completions endpoint.
+type completionLogprobs struct {
+	Tokens        []string             `json:"tokens"`
+	TokenLogprobs []float64            `json:"token_logprobs"`
+	TopLogprobs   []map[string]float64 `json:"top_logprobs"`
+}
+
+// tokenLogProbs converts the log probabilities, with the alternatives of each
+// token ordered from most to least likely.
+func (l *completionLogprobs) tokenLogProbs() []llms.TokenLogProb {
+	if l == nil {
+		return nil
+	}
+	tokens := make([]llms.TokenLogProb, len(l.Tokens))
+	for i, token := range l.Tokens {
+		tokens[i].Token = token
+		if i < len(l.TokenLogprobs) {
+			tokens[i].LogProb = l.TokenLogprobs[i]
+		}
+		if i >= len(l.TopLogprobs) {
+			continue
+		}
+		for top, logProb := range l.TopLogprobs[i] {
+			tokens[i].TopLogProbs = append(tokens[i].TopLogProbs, llms.TopLogProb{Token: top, LogProb: logProb})
+		}
+		sort.Slice(tokens[i].TopLogProbs, func(a, b int) bool {
+			return tokens[i].TopLogProbs[a].LogProb > tokens[i].TopLogProbs[b].LogProb
+		})
+	}
+	return tokens
+}
+
 type errorMessage struct {
 	Error struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
+		Code    any    `json:"code"`
 	} `json:"error"`
 }
 
+// UnmarshalJSON decodes the error of OpenAI and of compatible servers, which
+// send the error as a string, e.g. LM Studio, or the fields of the error at
+// the top level, e.g. vLLM.
+func (e *errorMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Type    string          `json:"type"`
+		Code    any             `json:"code"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var msg string
+	switch {
+	case len(raw.Error) > 0 && raw.Error[0] == '{':
+		return json.Unmarshal(raw.Error, &e.Error)
+	case len(raw.Error) > 0 && json.Unmarshal(raw.Error, &msg) == nil && msg != "":
+		e.Error.Message = msg
+	default:
+		e.Error.Message, e.Error.Type, e.Error.Code = raw.Message, raw.Type, raw.Code
+	}
+	return nil
+}
+
+// code returns the error code, or the error type if the code is not set.
+func (e errorMessage) code() string {
+	if code, ok := e.Error.Code.(string); ok && code != "" {
+		return code
+	}
+	return e.Error.Type
+}
+
 func (c *Client) setCompletionDefaults(payload *completionPayload) {
 	// Set defaults
 	if payload.MaxTokens == 0 {
@@ -88,12 +162,14 @@ func (c *Client) createCompletion(ctx context.Context, payload *completionPayloa
 	}
 
 	// Build request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/completions", c.Model), bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/completions", payload.Model), bytes.NewReader(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	c.setHeaders(req)
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
 
 	// Send request
 	r, err := c.httpClient.Do(req)
@@ -103,16 +179,14 @@ func (c *Client) createCompletion(ctx context.Context, payload *completionPayloa
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, llms.NewStatusError(r, "", "")
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
 	}
 
 	// Parse response
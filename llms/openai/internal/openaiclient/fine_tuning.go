@@ -0,0 +1,110 @@
+package openaiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+const fineTuningPurpose = "fine-tune"
+
+// ErrFineTuningUnsupported is returned when the fine-tuning API is used with
+// Azure.
+var ErrFineTuningUnsupported = errors.New("fine-tuning api is not supported with azure")
+
+// FineTuningJobRequest is a request to create a fine-tuning job.
+type FineTuningJobRequest struct {
+	Model           string                     `json:"model"`
+	TrainingFile    string                     `json:"training_file"`
+	ValidationFile  string                     `json:"validation_file,omitempty"`
+	Suffix          string                     `json:"suffix,omitempty"`
+	Seed            *int                       `json:"seed,omitempty"`
+	Hyperparameters *FineTuningHyperparameters `json:"hyperparameters,omitempty"`
+}
+
+// FineTuningHyperparameters are the hyperparameters of a fine-tuning job.
+type FineTuningHyperparameters struct {
+	NEpochs                int     `json:"n_epochs,omitempty"`
+	BatchSize              int     `json:"batch_size,omitempty"`
+	LearningRateMultiplier float64 `json:"learning_rate_multiplier,omitempty"`
+}
+
+// FineTuningJob is a job of the fine-tuning API.
+type FineTuningJob struct {
+	ID             string `json:"id"`
+	Model          string `json:"model"`
+	Status         string `json:"status"`
+	FineTunedModel string `json:"fine_tuned_model"`
+	TrainingFile   string `json:"training_file"`
+	ValidationFile string `json:"validation_file"`
+	TrainedTokens  int    `json:"trained_tokens"`
+	CreatedAt      int64  `json:"created_at"`
+	FinishedAt     int64  `json:"finished_at"`
+	Error          *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// UploadFineTuningFile uploads a training or validation file and returns its id.
+func (c *Client) UploadFineTuningFile(ctx context.Context, name string, data []byte) (string, error) {
+	if IsAzure(c.apiType) {
+		return "", ErrFineTuningUnsupported
+	}
+	return c.uploadFile(ctx, fineTuningPurpose, name, data)
+}
+
+// CreateFineTuningJob creates a fine-tuning job.
+func (c *Client) CreateFineTuningJob(ctx context.Context, r *FineTuningJobRequest) (*FineTuningJob, error) {
+	return c.fineTuningJob(ctx, http.MethodPost, "/fine_tuning/jobs", r)
+}
+
+// GetFineTuningJob returns the fine-tuning job of the id.
+func (c *Client) GetFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error) {
+	return c.fineTuningJob(ctx, http.MethodGet, "/fine_tuning/jobs/"+url.PathEscape(id), nil)
+}
+
+// CancelFineTuningJob cancels the fine-tuning job of the id.
+func (c *Client) CancelFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error) {
+	return c.fineTuningJob(ctx, http.MethodPost, "/fine_tuning/jobs/"+url.PathEscape(id)+"/cancel", nil)
+}
+
+// ListFineTuningJobs lists the fine-tuning jobs, the most recent first,
+// following the pages of the listing.
+func (c *Client) ListFineTuningJobs(ctx context.Context) ([]FineTuningJob, error) {
+	if IsAzure(c.apiType) {
+		return nil, ErrFineTuningUnsupported
+	}
+	var jobs []FineTuningJob
+	after := ""
+	for {
+		path := "/fine_tuning/jobs?limit=100"
+		if after != "" {
+			path += "&after=" + url.QueryEscape(after)
+		}
+		var page struct {
+			Data    []FineTuningJob `json:"data"`
+			HasMore bool            `json:"has_more"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Data...)
+		if !page.HasMore || len(page.Data) == 0 {
+			return jobs, nil
+		}
+		after = page.Data[len(page.Data)-1].ID
+	}
+}
+
+func (c *Client) fineTuningJob(ctx context.Context, method, path string, payload any) (*FineTuningJob, error) {
+	if IsAzure(c.apiType) {
+		return nil, ErrFineTuningUnsupported
+	}
+	var job FineTuningJob
+	if err := c.doJSON(ctx, method, path, payload, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
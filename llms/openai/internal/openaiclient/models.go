@@ -0,0 +1,109 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Model is a model served by the API.
+type Model struct {
+	ID      string
+	OwnedBy string
+	Created int64
+}
+
+// modelEntry is a model of a model listing. Compatible servers name the id
+// of the model differently, e.g. Ollama lists models by name.
+type modelEntry struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Model   string `json:"model"`
+	OwnedBy string `json:"owned_by"`
+	Created any    `json:"created"`
+}
+
+func (m modelEntry) model() Model {
+	model := Model{ID: m.ID, OwnedBy: m.OwnedBy}
+	if model.ID == "" {
+		model.ID = m.Name
+	}
+	if model.ID == "" {
+		model.ID = m.Model
+	}
+	// The creation time is a number, but some servers send a string.
+	if created, ok := m.Created.(float64); ok {
+		model.Created = int64(created)
+	}
+	return model
+}
+
+// ListModels lists the models served by the API.
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+	reqURL := c.baseURL + "/models"
+	if IsAzure(c.apiType) {
+		reqURL = fmt.Sprintf("%s/openai/models?api-version=%s", strings.TrimRight(c.baseURL, "/"), c.apiVersion)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, llms.NewStatusError(r, "", "")
+		}
+		return nil, llms.NewStatusError(r, errResp.code(), errResp.Error.Message)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return decodeModels(body)
+}
+
+// decodeModels decodes a model listing. OpenAI lists the models in the data
+// field, compatible servers may list them in a models field or as an array.
+func decodeModels(body []byte) ([]Model, error) {
+	var entries []modelEntry
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	} else {
+		var list struct {
+			Data   []modelEntry `json:"data"`
+			Models []modelEntry `json:"models"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		entries = append(list.Data, list.Models...)
+	}
+
+	models := make([]Model, 0, len(entries))
+	for _, entry := range entries {
+		models = append(models, entry.model())
+	}
+	return models, nil
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -36,8 +39,27 @@ type Client struct {
 	// required when APIType is APITypeAzure or APITypeAzureAD
 	apiVersion      string
 	embeddingsModel string
+
+	// tokenProvider returns the Azure AD token when APIType is APITypeAzureAD.
+	tokenProvider TokenProvider
+
+	// batchPollInterval is the interval the status of a batch is polled at.
+	batchPollInterval time.Duration
+
+	// headers are added to every request.
+	headers http.Header
+
+	// rateLimiter limits the requests of the client.
+	rateLimiter *llms.RateLimiter
+
+	// embeddingDimensions is the number of dimensions of the embeddings of
+	// the text-embedding-3 and later models, the model default if zero.
+	embeddingDimensions int
 }
 
+// TokenProvider returns a bearer token, e.g. a refreshed Azure AD access token.
+type TokenProvider func(ctx context.Context) (string, error)
+
 // Option is an option for the OpenAI client.
 type Option func(*Client) error
 
@@ -46,6 +68,53 @@ type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// WithTokenProvider sets the provider of the bearer token used instead of the
+// static token when APIType is APITypeAzureAD.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Client) error {
+		c.tokenProvider = provider
+
+		return nil
+	}
+}
+
+// WithBatchPollInterval sets the interval the status of a batch is polled at.
+func WithBatchPollInterval(interval time.Duration) Option {
+	return func(c *Client) error {
+		c.batchPollInterval = interval
+
+		return nil
+	}
+}
+
+// WithHeaders adds the headers to every request, e.g. the attribution
+// headers of OpenRouter.
+func WithHeaders(headers http.Header) Option {
+	return func(c *Client) error {
+		c.headers = headers
+
+		return nil
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(c *Client) error {
+		c.rateLimiter = limiter
+
+		return nil
+	}
+}
+
+// WithEmbeddingDimensions sets the number of dimensions of the embeddings.
+func WithEmbeddingDimensions(dimensions int) Option {
+	return func(c *Client) error {
+		c.embeddingDimensions = dimensions
+
+		return nil
+	}
+}
+
 // New returns a new OpenAI client.
 func New(token string, model string, baseURL string, organization string,
 	apiType APIType, apiVersion string, httpClient Doer, embeddingsModel string,
@@ -60,6 +129,8 @@ func New(token string, model string, baseURL string, organization string,
 		apiType:         apiType,
 		apiVersion:      apiVersion,
 		httpClient:      httpClient,
+
+		batchPollInterval: defaultBatchPollInterval,
 	}
 
 	for _, opt := range opts {
@@ -82,15 +153,37 @@ type CompletionRequest struct {
 	FrequencyPenalty float64  `json:"frequency_penalty,omitempty"`
 	PresencePenalty  float64  `json:"presence_penalty,omitempty"`
 	TopP             float64  `json:"top_p,omitempty"`
+	// Logprobs is the number of most likely tokens returned with the log
+	// probability of each generated token. Log probabilities are not
+	// requested if nil.
+	Logprobs *int `json:"logprobs,omitempty"`
+	// Seed makes the sampling mostly deterministic.
+	Seed *int `json:"seed,omitempty"`
+	// LogitBias maps token ids to a bias between -100 and 100.
+	LogitBias map[string]int `json:"logit_bias,omitempty"`
 }
 
 // Completion is a completion.
 type Completion struct {
-	Text string `json:"text"`
+	Text     string              `json:"text"`
+	Usage    Usage               `json:"usage"`
+	LogProbs []llms.TokenLogProb `json:"logprobs,omitempty"`
+	// SystemFingerprint identifies the backend configuration of the response.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+}
+
+// Usage is the token usage of a completion.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // CreateCompletion creates a completion.
 func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*Completion, error) {
+	if err := c.rateLimiter.Wait(ctx, llms.EstimateTokens(r.Prompt)+r.MaxTokens); err != nil {
+		return nil, err
+	}
 	resp, err := c.createCompletion(ctx, &completionPayload{
 		Model:            r.Model,
 		Prompt:           r.Prompt,
@@ -101,6 +194,9 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 		FrequencyPenalty: r.FrequencyPenalty,
 		PresencePenalty:  r.PresencePenalty,
 		TopP:             r.TopP,
+		Logprobs:         r.Logprobs,
+		Seed:             r.Seed,
+		LogitBias:        r.LogitBias,
 	})
 	if err != nil {
 		return nil, err
@@ -109,7 +205,15 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 		return nil, ErrEmptyResponse
 	}
 	return &Completion{
-		Text: resp.Choices[0].Text,
+		Text:     resp.Choices[0].Text,
+		LogProbs: resp.Choices[0].Logprobs.tokenLogProbs(),
+
+		SystemFingerprint: resp.SystemFingerprint,
+		Usage: Usage{
+			PromptTokens:     int(resp.Usage.PromptTokens),
+			CompletionTokens: int(resp.Usage.CompletionTokens),
+			TotalTokens:      int(resp.Usage.TotalTokens),
+		},
 	}, nil
 }
 
@@ -117,17 +221,30 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 type EmbeddingRequest struct {
 	Model string   `json:"model"`
 	Input []string `json:"input"`
+	// Dimensions is the number of dimensions of the embeddings, the
+	// dimensions of the client if zero.
+	Dimensions int `json:"dimensions,omitempty"`
 }
 
 // CreateEmbedding creates embeddings.
 func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]float64, error) {
+	if r.Model == "" {
+		r.Model = c.embeddingsModel
+	}
 	if r.Model == "" {
 		r.Model = defaultEmbeddingModel
 	}
+	if r.Dimensions == 0 {
+		r.Dimensions = c.embeddingDimensions
+	}
+	if err := c.rateLimiter.Wait(ctx, llms.EstimateTokens(r.Input...)); err != nil {
+		return nil, err
+	}
 
 	resp, err := c.createEmbedding(ctx, &embeddingPayload{
-		Model: r.Model,
-		Input: r.Input,
+		Model:      r.Model,
+		Input:      r.Input,
+		Dimensions: r.Dimensions,
 	})
 	if err != nil {
 		return nil, err
@@ -157,6 +274,10 @@ func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatResponse,
 	if r.FunctionCallBehavior == "" && len(r.Functions) > 0 {
 		r.FunctionCallBehavior = defaultFunctionCallBehavior
 	}
+	r.setReasoningDefaults()
+	if err := c.rateLimiter.Wait(ctx, r.estimateTokens()); err != nil {
+		return nil, err
+	}
 	resp, err := c.createChat(ctx, r)
 	if err != nil {
 		return nil, err
@@ -171,16 +292,31 @@ func IsAzure(apiType APIType) bool {
 	return apiType == APITypeAzure || apiType == APITypeAzureAD
 }
 
-func (c *Client) setHeaders(req *http.Request) {
+func (c *Client) setHeaders(req *http.Request) error {
 	req.Header.Set("Content-Type", "application/json")
-	if IsAzure(c.apiType) {
+	switch {
+	case c.apiType == APITypeAzureAD && c.tokenProvider != nil:
+		token, err := c.tokenProvider(req.Context())
+		if err != nil {
+			return fmt.Errorf("get azure ad token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.apiType == APITypeAzureAD:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.apiType == APITypeAzure:
 		req.Header.Set("api-key", c.token)
-	} else {
+	case c.token != "":
+		// Local OpenAI compatible servers do not require a token.
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 	if c.organization != "" {
 		req.Header.Set("OpenAI-Organization", c.organization)
 	}
+	for key, values := range c.headers {
+		req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	llms.SetRequestHeaders(req)
+	return nil
 }
 
 func (c *Client) buildURL(suffix string, model string) string {
@@ -192,6 +328,8 @@ func (c *Client) buildURL(suffix string, model string) string {
 	return fmt.Sprintf("%s%s", c.baseURL, suffix)
 }
 
+// buildAzureURL returns the url of the deployment, Azure uses the name of the
+// deployment instead of the model in the request body.
 func (c *Client) buildAzureURL(suffix string, model string) string {
 	baseURL := c.baseURL
 	baseURL = strings.TrimRight(baseURL, "/")
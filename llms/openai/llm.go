@@ -12,6 +12,7 @@ var (
 	ErrEmptyResponse              = errors.New("no response")
 	ErrMissingToken               = errors.New("missing the OpenAI API key, set it in the OPENAI_API_KEY environment variable") //nolint:lll
 	ErrMissingAzureEmbeddingModel = errors.New("embeddings model needs to be provided when using Azure API")
+	ErrMissingAzureEndpoint       = errors.New("missing the Azure OpenAI endpoint, set it in the AZURE_OPENAI_ENDPOINT environment variable") //nolint:lll
 
 	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
 )
@@ -21,7 +22,6 @@ func newClient(opts ...Option) (*openaiclient.Client, error) {
 	options := &options{
 		token:        os.Getenv(tokenEnvVarName),
 		model:        os.Getenv(modelEnvVarName),
-		baseURL:      os.Getenv(baseURLEnvVarName),
 		organization: os.Getenv(organizationEnvVarName),
 		apiType:      APIType(openaiclient.APITypeOpenAI),
 		httpClient:   http.DefaultClient,
@@ -31,18 +31,63 @@ func newClient(opts ...Option) (*openaiclient.Client, error) {
 		opt(options)
 	}
 
+	// WithAzure and WithAzureADTokenProvider select the Azure api type, AAD
+	// when authenticating with a token provider.
+	if options.azure && !openaiclient.IsAzure(openaiclient.APIType(options.apiType)) {
+		options.apiType = APITypeAzure
+	}
+	if options.tokenProvider != nil && options.apiType == APITypeAzure {
+		options.apiType = APITypeAzureAD
+	}
+
 	// set of options needed for Azure client
-	if openaiclient.IsAzure(openaiclient.APIType(options.apiType)) && options.apiVersion == "" {
-		options.apiVersion = DefaultAPIVersion
+	isAzure := openaiclient.IsAzure(openaiclient.APIType(options.apiType))
+	if !isAzure && options.baseURL == "" {
+		options.baseURL = os.Getenv(baseURLEnvVarName)
+	}
+	if isAzure {
+		if options.token == "" {
+			options.token = os.Getenv(azureTokenEnvVarName)
+		}
+		if options.baseURL == "" {
+			options.baseURL = os.Getenv(azureEndpointEnvVarName)
+		}
+		if options.apiVersion == "" {
+			options.apiVersion = os.Getenv(azureAPIVersionEnvVarName)
+		}
+		if options.apiVersion == "" {
+			options.apiVersion = DefaultAPIVersion
+		}
 		if options.embeddingModel == "" {
 			return nil, ErrMissingAzureEmbeddingModel
 		}
+		if options.baseURL == "" {
+			return nil, ErrMissingAzureEndpoint
+		}
 	}
 
-	if len(options.token) == 0 {
+	if len(options.token) == 0 && options.tokenProvider == nil && !options.compatible {
 		return nil, ErrMissingToken
 	}
 
+	var clientOpts []openaiclient.Option
+	if options.tokenProvider != nil {
+		clientOpts = append(clientOpts, openaiclient.WithTokenProvider(options.tokenProvider))
+	}
+	if len(options.headers) > 0 {
+		clientOpts = append(clientOpts, openaiclient.WithHeaders(options.headers))
+	}
+	if options.rateLimiter != nil {
+		clientOpts = append(clientOpts, openaiclient.WithRateLimiter(options.rateLimiter))
+	}
+	if options.embeddingDimensions > 0 {
+		clientOpts = append(clientOpts, openaiclient.WithEmbeddingDimensions(options.embeddingDimensions))
+	}
+	if options.batchPollInterval > 0 {
+		clientOpts = append(clientOpts, openaiclient.WithBatchPollInterval(options.batchPollInterval))
+	}
+
 	return openaiclient.New(options.token, options.model, options.baseURL, options.organization,
-		openaiclient.APIType(options.apiType), options.apiVersion, options.httpClient, options.embeddingModel)
+		openaiclient.APIType(options.apiType), options.apiVersion, options.httpClient, options.embeddingModel,
+		clientOpts...)
 }
@@ -0,0 +1,39 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
+
+// Model is a model served by the OpenAI API or an OpenAI compatible server.
+type Model struct {
+	// ID is the name of the model used with WithModel.
+	ID string
+	// OwnedBy is the organization owning the model, if reported.
+	OwnedBy string
+	// Created is the unix time the model was created at, if reported.
+	Created int64
+}
+
+// ListModels lists the models served by the API.
+func (o *LLM) ListModels(ctx context.Context) ([]Model, error) {
+	return listModels(ctx, o.client)
+}
+
+// ListModels lists the models served by the API.
+func (o *Chat) ListModels(ctx context.Context) ([]Model, error) {
+	return listModels(ctx, o.client)
+}
+
+func listModels(ctx context.Context, client *openaiclient.Client) ([]Model, error) {
+	result, err := client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]Model, 0, len(result))
+	for _, m := range result {
+		models = append(models, Model{ID: m.ID, OwnedBy: m.OwnedBy, Created: m.Created})
+	}
+	return models, nil
+}
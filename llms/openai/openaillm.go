@@ -43,7 +43,19 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 		opt(&opts)
 	}
 
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+
 	generations := make([]*llms.Generation, 0, len(prompts))
+	var logprobs *int
+	if opts.LogProbs {
+		logprobs = &opts.TopLogProbs
+	}
+	var seed *int
+	if opts.SeedSet {
+		seed = &opts.Seed
+	}
 	for _, prompt := range prompts {
 		result, err := o.client.CreateCompletion(ctx, &openaiclient.CompletionRequest{
 			Model:            opts.Model,
@@ -55,12 +67,28 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 			FrequencyPenalty: opts.FrequencyPenalty,
 			PresencePenalty:  opts.PresencePenalty,
 			TopP:             opts.TopP,
+			Logprobs:         logprobs,
+			Seed:             seed,
+			LogitBias:        opts.LogitBias,
 		})
 		if err != nil {
 			return nil, err
 		}
+		generationInfo := map[string]any{}
+		if result.LogProbs != nil {
+			generationInfo[llms.LogProbsKey] = result.LogProbs
+		}
+		if result.SystemFingerprint != "" {
+			generationInfo[llms.SystemFingerprintKey] = result.SystemFingerprint
+		}
 		generations = append(generations, &llms.Generation{
-			Text: result.Text,
+			Text:           result.Text,
+			GenerationInfo: generationInfo,
+			Usage: llms.Usage{
+				PromptTokens:     result.Usage.PromptTokens,
+				CompletionTokens: result.Usage.CompletionTokens,
+				TotalTokens:      result.Usage.TotalTokens,
+			},
 		})
 	}
 
@@ -0,0 +1,127 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func newAzureTestServer(t *testing.T, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`)
+	}))
+}
+
+func TestAzureChat(t *testing.T) {
+	t.Parallel()
+
+	server := newAzureTestServer(t, func(r *http.Request) {
+		assert.Equal(t, "/openai/deployments/my-gpt/chat/completions", r.URL.Path)
+		assert.Equal(t, "2023-12-01-preview", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "key", r.Header.Get("api-key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+	})
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(
+		WithAzure(server.URL),
+		WithToken("key"),
+		WithAzureDeployment("my-gpt"),
+		WithAzureEmbeddingDeployment("my-ada"),
+		WithAPIVersion("2023-12-01-preview"),
+	)
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", msg.Content)
+}
+
+func TestAzureADTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	server := newAzureTestServer(t, func(r *http.Request) {
+		assert.Equal(t, "/openai/deployments/my-gpt/chat/completions", r.URL.Path)
+		assert.Equal(t, DefaultAPIVersion, r.URL.Query().Get("api-version"))
+		assert.Equal(t, "Bearer aad-token", r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("api-key"))
+	})
+	t.Cleanup(server.Close)
+
+	calls := 0
+	chat, err := NewChat(
+		WithAzure(server.URL),
+		WithAzureADTokenProvider(func(context.Context) (string, error) {
+			calls++
+			return "aad-token", nil
+		}),
+		WithAzureDeployment("my-gpt"),
+		WithAzureEmbeddingDeployment("my-ada"),
+	)
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestAzureADTokenProviderBeforeAzure(t *testing.T) {
+	t.Parallel()
+
+	server := newAzureTestServer(t, func(r *http.Request) {
+		assert.Equal(t, "Bearer aad-token", r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("api-key"))
+	})
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(
+		WithAzureADTokenProvider(func(context.Context) (string, error) {
+			return "aad-token", nil
+		}),
+		WithAzure(server.URL),
+		WithAzureDeployment("my-gpt"),
+		WithAzureEmbeddingDeployment("my-ada"),
+	)
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+}
+
+func TestAzureEndpointEnv(t *testing.T) {
+	server := newAzureTestServer(t, func(r *http.Request) {
+		assert.Equal(t, "/openai/deployments/my-gpt/chat/completions", r.URL.Path)
+	})
+	t.Cleanup(server.Close)
+
+	t.Setenv(baseURLEnvVarName, "http://127.0.0.1:1")
+	t.Setenv(azureEndpointEnvVarName, server.URL)
+
+	chat, err := NewChat(
+		WithAzure(""),
+		WithToken("key"),
+		WithAzureDeployment("my-gpt"),
+		WithAzureEmbeddingDeployment("my-ada"),
+	)
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+}
+
+func TestAzureMissingEndpoint(t *testing.T) {
+	t.Setenv(azureEndpointEnvVarName, "")
+	t.Setenv(baseURLEnvVarName, "")
+
+	_, err := New(WithAPIType(APITypeAzure), WithToken("key"), WithAzureEmbeddingDeployment("my-ada"))
+	require.ErrorIs(t, err, ErrMissingAzureEndpoint)
+}
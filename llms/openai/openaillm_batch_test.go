@@ -0,0 +1,110 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatGenerateBatch(t *testing.T) {
+	t.Parallel()
+
+	var input string
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "batch", r.FormValue("purpose"))
+		f, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		input = string(data)
+		fmt.Fprint(w, `{"id":"file-in"}`)
+	})
+	mux.HandleFunc("/batches", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "file-in", body["input_file_id"])
+		assert.Equal(t, "/v1/chat/completions", body["endpoint"])
+		fmt.Fprint(w, `{"id":"batch_1","status":"validating"}`)
+	})
+	mux.HandleFunc("/batches/batch_1", func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) < 2 {
+			fmt.Fprint(w, `{"id":"batch_1","status":"in_progress"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"batch_1","status":"completed","output_file_id":"file-out","error_file_id":"file-err"}`)
+	})
+	mux.HandleFunc("/files/file-out/content", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"custom_id":"1","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"two"}}],"usage":{"total_tokens":7}}}}`) //nolint:lll
+		fmt.Fprintln(w, `{"custom_id":"0","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"one"}}]}}}`)                            //nolint:lll
+	})
+	mux.HandleFunc("/files/file-err/content", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"custom_id":"2","response":{"status_code":400,"body":{"error":{"message":"bad","type":"invalid_request_error"}}}}`) //nolint:lll
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithModel("gpt-4o-mini"),
+		WithBatchPollInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	results, err := llms.GenerateBatch(context.Background(), chat, [][]schema.ChatMessage{
+		{schema.HumanChatMessage{Content: "1"}},
+		{schema.HumanChatMessage{Content: "2"}},
+		{schema.HumanChatMessage{Content: "3"}},
+	}, llms.WithNativeBatch(), llms.WithTemperature(0.1))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "one", results[0].Generation.Text)
+	assert.Equal(t, "two", results[1].Generation.Text)
+	assert.Equal(t, 7, results[1].Generation.Usage.TotalTokens)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, results[2].Err, &statusErr)
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	require.Len(t, lines, 3)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "0", line["custom_id"])
+	assert.Equal(t, "/v1/chat/completions", line["url"])
+	body, ok := line["body"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "gpt-4o-mini", body["model"])
+	assert.Equal(t, 0.1, body["temperature"])
+}
+
+func TestChatGenerateBatchFailed(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"file-in"}`)
+	})
+	mux.HandleFunc("/batches", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"batch_1","status":"failed","errors":{"data":[{"code":"invalid","message":"invalid input"}]}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = chat.GenerateBatch(context.Background(), [][]schema.ChatMessage{{schema.HumanChatMessage{Content: "1"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid input")
+}
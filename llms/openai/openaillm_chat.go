@@ -16,10 +16,20 @@ type Chat struct {
 }
 
 var (
-	_ llms.ChatLLM       = (*Chat)(nil)
-	_ llms.LanguageModel = (*Chat)(nil)
+	_ llms.ChatLLM        = (*Chat)(nil)
+	_ llms.LanguageModel  = (*Chat)(nil)
+	_ llms.BatchGenerator = (*Chat)(nil)
 )
 
+// samplingLimits are the limits of the API on the sampling options. The
+// number of stop words is not limited, as compatible servers accept more than
+// OpenAI.
+var samplingLimits = llms.SamplingLimits{ //nolint:gochecknoglobals
+	MinPenalty:   -2,
+	MaxPenalty:   2,
+	MaxLogitBias: 100,
+}
+
 // NewChat returns a new OpenAI chat LLM.
 func NewChat(opts ...Option) (*Chat, error) {
 	c, err := newClient(opts...)
@@ -40,97 +50,286 @@ func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options
 	return r[0].Message, nil
 }
 
-//nolint:funlen
-func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll,cyclop
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
 	generations := make([]*llms.Generation, 0, len(messageSets))
 	for _, messageSet := range messageSets {
-		msgs := make([]*openaiclient.ChatMessage, len(messageSet))
-		for i, m := range messageSet {
-			msg := &openaiclient.ChatMessage{
-				Content: m.GetContent(),
-			}
-			typ := m.GetType()
-			switch typ {
-			case schema.ChatMessageTypeSystem:
-				msg.Role = "system"
-			case schema.ChatMessageTypeAI:
-				msg.Role = "assistant"
-				if aiChatMsg, ok := m.(schema.AIChatMessage); ok && aiChatMsg.FunctionCall != nil {
+		result, err := o.client.CreateChat(ctx, chatRequest(messageSet, opts))
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Choices) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		generations = append(generations, chatGeneration(result))
+	}
+
+	return generations, nil
+}
+
+// GenerateBatch generates a response for each of the message sets with the
+// OpenAI Batch API. It waits for the batch to complete, which takes up to 24
+// hours, polling its status at the interval set with WithBatchPollInterval.
+// Streaming is not supported.
+func (o *Chat) GenerateBatch(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]llms.BatchResult, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	opts.StreamingFunc = nil
+	opts.StreamingChunkFunc = nil
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	requests := make([]*openaiclient.ChatRequest, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		requests = append(requests, chatRequest(messageSet, opts))
+	}
+	results, err := o.client.CreateChatBatch(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	batchResults := make([]llms.BatchResult, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			batchResults = append(batchResults, llms.BatchResult{Err: result.Err})
+			continue
+		}
+		batchResults = append(batchResults, llms.BatchResult{Generation: chatGeneration(result.Response)})
+	}
+	return batchResults, nil
+}
+
+// chatRequest returns the request of the messages with the call options.
+func chatRequest(messageSet []schema.ChatMessage, opts llms.CallOptions) *openaiclient.ChatRequest {
+	msgs := make([]*openaiclient.ChatMessage, len(messageSet))
+	for i, m := range messageSet {
+		msg := &openaiclient.ChatMessage{
+			Content: m.GetContent(),
+		}
+		typ := m.GetType()
+		switch typ {
+		case schema.ChatMessageTypeSystem:
+			msg.Role = "system"
+		case schema.ChatMessageTypeAI:
+			msg.Role = "assistant"
+			if aiChatMsg, ok := m.(schema.AIChatMessage); ok {
+				if aiChatMsg.FunctionCall != nil {
 					msg.FunctionCall = &openaiclient.FunctionCall{
 						Name:      aiChatMsg.FunctionCall.Name,
 						Arguments: aiChatMsg.FunctionCall.Arguments,
 					}
 				}
-			case schema.ChatMessageTypeHuman:
-				msg.Role = "user"
-			case schema.ChatMessageTypeGeneric:
-				msg.Role = "user"
-			case schema.ChatMessageTypeFunction:
-				msg.Role = "function"
+				msg.ToolCalls = toClientToolCalls(aiChatMsg.ToolCalls)
+			}
+		case schema.ChatMessageTypeHuman:
+			msg.Role = "user"
+			if humanChatMsg, ok := m.(schema.HumanChatMessage); ok && len(humanChatMsg.Parts) > 0 {
+				msg.Content = ""
+				msg.MultiContent = toClientContentParts(humanChatMsg)
 			}
-			if n, ok := m.(schema.Named); ok {
-				msg.Name = n.GetName()
+		case schema.ChatMessageTypeGeneric:
+			msg.Role = "user"
+		case schema.ChatMessageTypeFunction:
+			msg.Role = "function"
+		case schema.ChatMessageTypeTool:
+			msg.Role = "tool"
+			if toolChatMsg, ok := m.(schema.ToolChatMessage); ok {
+				msg.ToolCallID = toolChatMsg.ID
 			}
-			msgs[i] = msg
 		}
-		req := &openaiclient.ChatRequest{
-			Model:            opts.Model,
-			StopWords:        opts.StopWords,
-			Messages:         msgs,
-			StreamingFunc:    opts.StreamingFunc,
-			Temperature:      opts.Temperature,
-			MaxTokens:        opts.MaxTokens,
-			N:                opts.N,
-			FrequencyPenalty: opts.FrequencyPenalty,
-			PresencePenalty:  opts.PresencePenalty,
-
-			FunctionCallBehavior: openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior),
+		if n, ok := m.(schema.Named); ok {
+			msg.Name = n.GetName()
 		}
-		for _, fn := range opts.Functions {
-			req.Functions = append(req.Functions, openaiclient.FunctionDefinition{
-				Name:        fn.Name,
-				Description: fn.Description,
-				Parameters:  fn.Parameters,
-			})
+		msgs[i] = msg
+	}
+	req := &openaiclient.ChatRequest{
+		Model:              opts.Model,
+		StopWords:          opts.StopWords,
+		Messages:           msgs,
+		StreamingFunc:      opts.StreamingFunc,
+		StreamingChunkFunc: opts.StreamingChunkFunc,
+		Temperature:        opts.Temperature,
+		MaxTokens:          opts.MaxTokens,
+		N:                  opts.N,
+		FrequencyPenalty:   opts.FrequencyPenalty,
+		PresencePenalty:    opts.PresencePenalty,
+		LogitBias:          opts.LogitBias,
+		ReasoningEffort:    string(opts.ReasoningEffort),
+		LogProbs:           opts.LogProbs,
+		TopLogProbs:        opts.TopLogProbs,
+
+		FunctionCallBehavior: openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior),
+	}
+	if opts.SeedSet {
+		req.Seed = &opts.Seed
+	}
+	for _, fn := range opts.Functions {
+		req.Functions = append(req.Functions, openaiclient.FunctionDefinition{
+			Name:        fn.Name,
+			Description: fn.Description,
+			Parameters:  fn.Parameters,
+		})
+	}
+	req.Tools, req.ToolChoice = toClientTools(opts.Tools, opts.ToolChoice)
+	req.ResponseFormat = toClientResponseFormat(opts.ResponseFormat)
+	if opts.PromptCaching != nil {
+		req.PromptCacheKey = opts.PromptCaching.Key
+	}
+	return req
+}
+
+// chatGeneration returns the generation of the first choice of the response.
+func chatGeneration(result *openaiclient.ChatResponse) *llms.Generation {
+	generationInfo := make(map[string]any, reflect.ValueOf(result.Usage).NumField())
+	generationInfo["CompletionTokens"] = result.Usage.CompletionTokens
+	generationInfo["PromptTokens"] = result.Usage.PromptTokens
+	generationInfo["TotalTokens"] = result.Usage.TotalTokens
+	if logProbs := result.Choices[0].LogProbs; logProbs != nil {
+		generationInfo[llms.LogProbsKey] = logProbs.Content
+	}
+	if result.SystemFingerprint != "" {
+		generationInfo[llms.SystemFingerprintKey] = result.SystemFingerprint
+	}
+	if reasoning := result.Choices[0].Message.ReasoningContent; reasoning != "" {
+		generationInfo[llms.ReasoningKey] = reasoning
+	}
+	generationInfo["ReasoningTokens"] = result.Usage.CompletionTokensDetails.ReasoningTokens
+	msg := &schema.AIChatMessage{
+		Content: result.Choices[0].Message.Content,
+	}
+	if result.Choices[0].FinishReason == "function_call" {
+		msg.FunctionCall = &schema.FunctionCall{
+			Name:      result.Choices[0].Message.FunctionCall.Name,
+			Arguments: result.Choices[0].Message.FunctionCall.Arguments,
 		}
-		result, err := o.client.CreateChat(ctx, req)
-		if err != nil {
-			return nil, err
+	}
+	for _, tc := range result.Choices[0].Message.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:   tc.ID,
+			Type: tc.Type,
+			FunctionCall: &schema.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+	return &llms.Generation{
+		Message:        msg,
+		Text:           msg.Content,
+		GenerationInfo: generationInfo,
+		Usage: llms.Usage{
+			PromptTokens:     int(result.Usage.PromptTokens),
+			CompletionTokens: int(result.Usage.CompletionTokens),
+			TotalTokens:      int(result.Usage.TotalTokens),
+			CachedTokens:     int(result.Usage.PromptTokensDetails.CachedTokens),
+			ReasoningTokens:  int(result.Usage.CompletionTokensDetails.ReasoningTokens),
+		},
+	}
+}
+
+// toClientTools converts the tools and the tool choice of the call options.
+func toClientTools(tools []llms.Tool, choice any) ([]openaiclient.Tool, any) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+	clientTools := make([]openaiclient.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
 		}
-		if len(result.Choices) == 0 {
-			return nil, ErrEmptyResponse
+		clientTools = append(clientTools, openaiclient.Tool{
+			Type: tool.Type,
+			Function: openaiclient.FunctionDefinition{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		})
+	}
+
+	switch c := choice.(type) {
+	case llms.ToolChoice:
+		clientChoice := openaiclient.ToolChoice{Type: c.Type}
+		if c.Function != nil {
+			clientChoice.Function.Name = c.Function.Name
 		}
-		generationInfo := make(map[string]any, reflect.ValueOf(result.Usage).NumField())
-		generationInfo["CompletionTokens"] = result.Usage.CompletionTokens
-		generationInfo["PromptTokens"] = result.Usage.PromptTokens
-		generationInfo["TotalTokens"] = result.Usage.TotalTokens
-		msg := &schema.AIChatMessage{
-			Content: result.Choices[0].Message.Content,
+		return clientTools, clientChoice
+	case *llms.ToolChoice:
+		if c == nil {
+			return clientTools, nil
 		}
-		if result.Choices[0].FinishReason == "function_call" {
-			msg.FunctionCall = &schema.FunctionCall{
-				Name:      result.Choices[0].Message.FunctionCall.Name,
-				Arguments: result.Choices[0].Message.FunctionCall.Arguments,
-			}
+		return toClientTools(tools, *c)
+	default:
+		return clientTools, choice
+	}
+}
+
+// toClientResponseFormat converts the response format of the call options.
+func toClientResponseFormat(format *llms.ResponseFormat) *openaiclient.ResponseFormat {
+	if format == nil {
+		return nil
+	}
+	clientFormat := &openaiclient.ResponseFormat{Type: string(format.Type)}
+	if format.JSONSchema != nil {
+		clientFormat.JSONSchema = &openaiclient.ResponseFormatJSONSchema{
+			Name:        format.JSONSchema.Name,
+			Description: format.JSONSchema.Description,
+			Schema:      format.JSONSchema.Schema,
+			Strict:      format.JSONSchema.Strict,
 		}
-		generations = append(generations, &llms.Generation{
-			Message:        msg,
-			Text:           msg.Content,
-			GenerationInfo: generationInfo,
-		})
 	}
+	return clientFormat
+}
 
-	return generations, nil
+func toClientToolCalls(toolCalls []schema.ToolCall) []openaiclient.ToolCall {
+	if len(toolCalls) == 0 {
+		return nil
+	}
+	clientToolCalls := make([]openaiclient.ToolCall, 0, len(toolCalls))
+	for _, tc := range toolCalls {
+		clientToolCall := openaiclient.ToolCall{
+			ID:   tc.ID,
+			Type: tc.Type,
+		}
+		if tc.FunctionCall != nil {
+			clientToolCall.Function = openaiclient.FunctionCall{
+				Name:      tc.FunctionCall.Name,
+				Arguments: tc.FunctionCall.Arguments,
+			}
+		}
+		clientToolCalls = append(clientToolCalls, clientToolCall)
+	}
+	return clientToolCalls
 }
 
 func (o *Chat) GetNumTokens(text string) int {
 	return llms.CountTokens(o.client.Model, text)
 }
 
+// CountTokens returns the number of input tokens of the messages, counted
+// locally with tiktoken. The tokens of function and tool definitions are not
+// included.
+func (o *Chat) CountTokens(_ context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (int, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	model := opts.Model
+	if model == "" {
+		model = o.client.Model
+	}
+	return llms.CountMessageTokens(model, messages)
+}
+
 func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
 	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
 }
@@ -151,3 +350,33 @@ func (o *Chat) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]fl
 	}
 	return embeddings, nil
 }
+
+// toClientContentParts returns the content and the parts of a multimodal message as client content parts.
+func toClientContentParts(m schema.HumanChatMessage) []openaiclient.ContentPart {
+	parts := make([]openaiclient.ContentPart, 0, len(m.Parts)+1)
+	if m.Content != "" {
+		parts = append(parts, openaiclient.ContentPart{Type: "text", Text: m.Content})
+	}
+	for _, part := range m.Parts {
+		switch p := part.(type) {
+		case schema.TextPart:
+			parts = append(parts, openaiclient.ContentPart{Type: "text", Text: p.Text})
+		case schema.ImageURLPart:
+			parts = append(parts, openaiclient.ContentPart{
+				Type:     "image_url",
+				ImageURL: &openaiclient.ImageURL{URL: p.URL, Detail: p.Detail},
+			})
+		case schema.BinaryPart:
+			parts = append(parts, openaiclient.ContentPart{
+				Type:     "image_url",
+				ImageURL: &openaiclient.ImageURL{URL: p.DataURL()},
+			})
+		case schema.FilePart:
+			parts = append(parts, openaiclient.ContentPart{
+				Type: "file",
+				File: &openaiclient.FileContent{Filename: p.Filename, FileData: p.DataURL()},
+			})
+		}
+	}
+	return parts
+}
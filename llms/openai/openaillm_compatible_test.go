@@ -0,0 +1,218 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestOpenAICompatible(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		// vLLM omits the fields it does not support.
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"Hi"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithOpenAICompatible(server.URL+"/v1"), WithModel("mistral-7b"), WithHeaders(http.Header{
+		"http-referer": {"https://example.com"},
+		"X-Title":      {"Example"},
+	}))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", msg.Content)
+
+	assert.Empty(t, got.Get("Authorization"))
+	assert.Equal(t, "https://example.com", got.Get("HTTP-Referer"))
+	assert.Equal(t, "Example", got.Get("X-Title"))
+
+	_, err = NewChat(WithBaseURL(server.URL + "/v1"))
+	require.ErrorIs(t, err, ErrMissingToken)
+}
+
+func TestOpenAICompatibleStreaming(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		// OpenRouter sends keep-alive comments, and servers may omit the
+		// space after data: and the final [DONE].
+		fmt.Fprint(w, ": OPENROUTER PROCESSING\n\n")
+		fmt.Fprint(w, "data:{\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+		fmt.Fprint(w, "event: message\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\" world\"},\"finish_reason\":\"stop\"}]}\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithOpenAICompatible(server.URL), WithToken("token"))
+	require.NoError(t, err)
+
+	var streamed string
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			streamed += string(chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", msg.Content)
+	assert.Equal(t, "Hello world", streamed)
+}
+
+func TestOpenAICompatibleErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Case") {
+		case "string":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"model not loaded"}`)
+		case "top-level":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"object":"error","message":"max_tokens is too large","type":"BadRequestError","code":400}`)
+		default:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"error\":{\"message\":\"provider disconnected\",\"code\":502}}\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithOpenAICompatible(server.URL), WithToken("token"))
+	require.NoError(t, err)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}}
+
+	ctx := llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"string"}})
+	_, err = chat.Call(ctx, messages)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "model not loaded", statusErr.Message)
+
+	ctx = llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"top-level"}})
+	_, err = chat.Call(ctx, messages)
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "max_tokens is too large", statusErr.Message)
+	assert.Equal(t, "BadRequestError", statusErr.Code)
+
+	_, err = chat.Call(context.Background(), messages, llms.WithStreamingFunc(func(context.Context, []byte) error {
+		return nil
+	}))
+	require.ErrorContains(t, err, "provider disconnected")
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hel", streamErr.Partial)
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want []Model
+	}{
+		{
+			name: "openai",
+			body: `{"object":"list","data":[{"id":"gpt-4o","object":"model","created":1715367049,"owned_by":"system"}]}`,
+			want: []Model{{ID: "gpt-4o", OwnedBy: "system", Created: 1715367049}},
+		},
+		{
+			name: "models field",
+			body: `{"models":[{"name":"llama3:8b","model":"llama3:8b"}]}`,
+			want: []Model{{ID: "llama3:8b"}},
+		},
+		{
+			name: "array",
+			body: `[{"id":"local-model","created":"2024-05-01"}]`,
+			want: []Model{{ID: "local-model"}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/v1/models", r.URL.Path)
+				fmt.Fprint(w, tc.body)
+			}))
+			t.Cleanup(server.Close)
+
+			llm, err := New(WithOpenAICompatible(server.URL+"/v1"), WithToken("token"))
+			require.NoError(t, err)
+			models, err := llm.ListModels(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, models)
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"Hi"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	// A single request per hour, shared by the clients.
+	limiter := llms.NewRateLimiter(1, 0)
+	limiter.Requests.SetLimit(1.0 / 3600)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}}
+	for i := 0; i < 2; i++ {
+		chat, err := NewChat(WithOpenAICompatible(server.URL), WithRateLimiter(limiter))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		_, err = chat.Call(ctx, messages)
+		cancel()
+		if i == 0 {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+		}
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestRateLimiterReasoningMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"Hi"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	// The max tokens of reasoning models, sent as max completion tokens, use
+	// up the tokens of the hour.
+	limiter := llms.NewRateLimiter(0, 1000)
+	limiter.Tokens.SetLimit(1.0 / 3600)
+	chat, err := NewChat(WithOpenAICompatible(server.URL), WithRateLimiter(limiter), WithModel("o3-mini"))
+	require.NoError(t, err)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}}
+
+	_, err = chat.Call(context.Background(), messages, llms.WithMaxTokens(1000))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = chat.Call(ctx, messages, llms.WithMaxTokens(10))
+	require.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEmbeddingDimensions(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"data":[{"index":0,"embedding":[0.6,0.8]}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL),
+		WithEmbeddingModel("text-embedding-3-small"), WithEmbeddingDimensions(2))
+	require.NoError(t, err)
+
+	embeddings, err := llm.CreateEmbedding(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.6, 0.8}}, embeddings)
+	assert.Equal(t, map[string]any{
+		"model":      "text-embedding-3-small",
+		"input":      []any{"a"},
+		"dimensions": float64(2),
+	}, got)
+
+	// The dimensions are not sent unless set.
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	got = nil
+	_, err = chat.CreateEmbedding(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-ada-002", got["model"])
+	assert.NotContains(t, got, "dimensions")
+}
@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/finetuning"
+)
+
+func TestFineTuning(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "fine-tune", r.FormValue("purpose"))
+		f, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "train.jsonl", header.Filename)
+		assert.Equal(t, "{}\n", string(data))
+		fmt.Fprint(w, `{"id":"file-train"}`)
+	})
+	mux.HandleFunc("/fine_tuning/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("after") == "" {
+				fmt.Fprint(w, `{"data":[{"id":"ftjob-2","status":"running"}],"has_more":true}`)
+				return
+			}
+			assert.Equal(t, "ftjob-2", r.URL.Query().Get("after"))
+			fmt.Fprint(w, `{"data":[{"id":"ftjob-1","status":"succeeded"}],"has_more":false}`)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"model":           "gpt-4o-mini",
+			"training_file":   "file-train",
+			"suffix":          "support",
+			"hyperparameters": map[string]any{"n_epochs": float64(3)},
+		}, body)
+		fmt.Fprint(w, `{"id":"ftjob-1","model":"gpt-4o-mini","status":"validating_files","created_at":1700000000}`)
+	})
+	mux.HandleFunc("/fine_tuning/jobs/ftjob-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"ftjob-1","status":"succeeded","fine_tuned_model":"ft:gpt-4o-mini:org:support:abc","trained_tokens":1200,"finished_at":1700000600}`) //nolint:lll
+	})
+	mux.HandleFunc("/fine_tuning/jobs/ftjob-1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		fmt.Fprint(w, `{"id":"ftjob-1","status":"cancelled"}`)
+	})
+	mux.HandleFunc("/models", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":"gpt-4o-mini"},{"id":"ft:gpt-4o-mini:org:support:abc"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	ft, err := NewFineTuning(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	fileID, err := ft.UploadFile(ctx, "train.jsonl", strings.NewReader("{}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "file-train", fileID)
+
+	job, err := ft.CreateJob(ctx, finetuning.JobRequest{
+		Model:           "gpt-4o-mini",
+		TrainingFile:    fileID,
+		Suffix:          "support",
+		Hyperparameters: finetuning.Hyperparameters{Epochs: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, finetuning.StatusValidatingFiles, job.Status)
+	assert.Equal(t, time.Unix(1700000000, 0), job.CreatedAt)
+
+	job, err = finetuning.Wait(ctx, ft, job.ID, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "ft:gpt-4o-mini:org:support:abc", job.FineTunedModel)
+	assert.Equal(t, 1200, job.TrainedTokens)
+	assert.Equal(t, time.Unix(1700000600, 0), job.FinishedAt)
+
+	job, err = ft.CancelJob(ctx, "ftjob-1")
+	require.NoError(t, err)
+	assert.Equal(t, finetuning.StatusCancelled, job.Status)
+
+	jobs, err := ft.ListJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "ftjob-2", jobs[0].ID)
+	assert.Equal(t, "ftjob-1", jobs[1].ID)
+
+	models, err := ft.ListModels(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ft:gpt-4o-mini:org:support:abc"}, models)
+}
+
+func TestFineTuningAzureUnsupported(t *testing.T) {
+	t.Parallel()
+
+	ft, err := NewFineTuning(WithToken("token"), WithAzure("https://example.openai.azure.com"),
+		WithAzureEmbeddingDeployment("embeddings"))
+	require.NoError(t, err)
+	_, err = ft.GetJob(context.Background(), "ftjob-1")
+	require.Error(t, err)
+}
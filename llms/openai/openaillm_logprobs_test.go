@@ -0,0 +1,106 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatLogProbs(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Yes"},"logprobs":{"content":[
+			{"token":"Yes","logprob":-0.1,"bytes":[89,101,115],"top_logprobs":[
+				{"token":"Yes","logprob":-0.1},{"token":"No","logprob":-2.4}]}]}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Is the sky blue?"},
+	}}, llms.WithLogProbs(2))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, got["logprobs"])
+	assert.Equal(t, float64(2), got["top_logprobs"])
+	assert.Equal(t, []llms.TokenLogProb{{
+		Token:   "Yes",
+		LogProb: -0.1,
+		Bytes:   []int{89, 101, 115},
+		TopLogProbs: []llms.TopLogProb{
+			{Token: "Yes", LogProb: -0.1},
+			{Token: "No", LogProb: -2.4},
+		},
+	}}, generations[0].LogProbs())
+}
+
+func TestChatStreamingLogProbs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"content":"Hello"},"logprobs":{"content":[{"token":"Hello","logprob":-0.5}]}}]}`+"\n\n")
+		fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"content":"!"},"logprobs":{"content":[{"token":"!","logprob":-1.5}]}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Hi"},
+	}}, llms.WithLogProbs(0), llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error { return nil }))
+	require.NoError(t, err)
+
+	logProbs := generations[0].LogProbs()
+	require.Len(t, logProbs, 2)
+	assert.Equal(t, "!", logProbs[1].Token)
+	assert.InDelta(t, -1.0, llms.MeanLogProb(logProbs), 1e-9)
+}
+
+func TestCompletionLogProbs(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"text":" blue","logprobs":{
+			"tokens":[" blue"],"token_logprobs":[-0.2],"top_logprobs":[{" grey":-3.1," blue":-0.2}]}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"The sky is"}, llms.WithLogProbs(2))
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), got["logprobs"])
+	assert.Equal(t, []llms.TokenLogProb{{
+		Token:   " blue",
+		LogProb: -0.2,
+		TopLogProbs: []llms.TopLogProb{
+			{Token: " blue", LogProb: -0.2},
+			{Token: " grey", LogProb: -3.1},
+		},
+	}}, generations[0].LogProbs())
+
+	_, err = llm.Generate(context.Background(), []string{"The sky is"})
+	require.NoError(t, err)
+	_, ok := got["logprobs"]
+	assert.False(t, ok)
+}
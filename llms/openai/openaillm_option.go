@@ -1,12 +1,23 @@
 package openai
 
-import "github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
 
 const (
 	tokenEnvVarName        = "OPENAI_API_KEY"      //nolint:gosec
 	modelEnvVarName        = "OPENAI_MODEL"        //nolint:gosec
 	baseURLEnvVarName      = "OPENAI_BASE_URL"     //nolint:gosec
 	organizationEnvVarName = "OPENAI_ORGANIZATION" //nolint:gosec
+
+	azureTokenEnvVarName      = "AZURE_OPENAI_API_KEY"  //nolint:gosec
+	azureEndpointEnvVarName   = "AZURE_OPENAI_ENDPOINT" //nolint:gosec
+	azureAPIVersionEnvVarName = "OPENAI_API_VERSION"    //nolint:gosec
 )
 
 type APIType openaiclient.APIType
@@ -27,11 +38,24 @@ type options struct {
 	baseURL      string
 	organization string
 	apiType      APIType
+	azure        bool
 	httpClient   openaiclient.Doer
 
 	// required when APIType is APITypeAzure or APITypeAzureAD
 	apiVersion     string
 	embeddingModel string
+
+	embeddingDimensions int
+
+	// used instead of the token when APIType is APITypeAzureAD
+	tokenProvider openaiclient.TokenProvider
+
+	batchPollInterval time.Duration
+
+	// compatible is set for OpenAI compatible servers, which may not require a token.
+	compatible  bool
+	headers     http.Header
+	rateLimiter *llms.RateLimiter
 }
 
 type Option func(*options)
@@ -52,13 +76,24 @@ func WithModel(model string) Option {
 	}
 }
 
-// WithEmbeddingModel passes the OpenAI model to the client. Required when ApiType is Azure.
+// WithEmbeddingModel passes the OpenAI embedding model to the client, e.g.
+// text-embedding-3-small, text-embedding-ada-002 if not set. Required when
+// ApiType is Azure.
 func WithEmbeddingModel(embeddingModel string) Option {
 	return func(opts *options) {
 		opts.embeddingModel = embeddingModel
 	}
 }
 
+// WithEmbeddingDimensions shortens the embeddings of the text-embedding-3 and
+// later models to the number of dimensions. The shortened embeddings keep most
+// of their quality and take less space in the vector store.
+func WithEmbeddingDimensions(dimensions int) Option {
+	return func(opts *options) {
+		opts.embeddingDimensions = dimensions
+	}
+}
+
 // WithBaseURL passes the OpenAI base url to the client. If not set, the base url
 // is read from the OPENAI_BASE_URL environment variable. If still not set in ENV
 // VAR OPENAI_BASE_URL, then the default value is https://api.openai.com/v1 is used.
@@ -99,3 +134,80 @@ func WithHTTPClient(client openaiclient.Doer) Option {
 		opts.httpClient = client
 	}
 }
+
+// WithAzure configures the client for the Azure OpenAI resource at endpoint,
+// e.g. https://my-resource.openai.azure.com. If endpoint is empty, it is read
+// from the AZURE_OPENAI_ENDPOINT environment variable. The client uses API key
+// authentication, unless WithAzureADTokenProvider is set. If not set with
+// WithToken, the key is read from the AZURE_OPENAI_API_KEY environment
+// variable.
+func WithAzure(endpoint string) Option {
+	return func(opts *options) {
+		opts.azure = true
+		opts.baseURL = endpoint
+	}
+}
+
+// WithAzureDeployment sets the name of the Azure deployment used for
+// completions and chat. Azure deployments take the place of model names.
+func WithAzureDeployment(deployment string) Option {
+	return func(opts *options) {
+		opts.model = deployment
+	}
+}
+
+// WithAzureEmbeddingDeployment sets the name of the Azure deployment used for embeddings.
+func WithAzureEmbeddingDeployment(deployment string) Option {
+	return func(opts *options) {
+		opts.embeddingModel = deployment
+	}
+}
+
+// WithAzureADTokenProvider authenticates to Azure OpenAI with Azure Active
+// Directory tokens. The provider is called before each request, so it can
+// return cached tokens and refresh them when they expire. The client uses the
+// api type APITypeAzureAD, whatever the order of this option and WithAzure.
+func WithAzureADTokenProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(opts *options) {
+		opts.azure = true
+		opts.tokenProvider = provider
+	}
+}
+
+// WithBatchPollInterval sets the interval the status of a batch of the Batch
+// API is polled at. Defaults to 30 seconds.
+func WithBatchPollInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.batchPollInterval = interval
+	}
+}
+
+// WithOpenAICompatible configures the client for an OpenAI compatible server
+// at baseURL, e.g. vLLM at http://localhost:8000/v1, LM Studio at
+// http://localhost:1234/v1 or OpenRouter at https://openrouter.ai/api/v1.
+// The token is optional, local servers usually do not require one. Use the
+// model names of the server with WithModel and ListModels to find them.
+func WithOpenAICompatible(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+		opts.compatible = true
+	}
+}
+
+// WithHeaders adds the headers to every request, e.g. the HTTP-Referer and
+// X-Title attribution headers of OpenRouter. Per-request headers set with
+// llms.WithRequestHeaders take precedence.
+func WithHeaders(headers http.Header) Option {
+	return func(opts *options) {
+		opts.headers = headers
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request, e.g.
+// llms.NewRateLimiter(500, 200000) for 500 requests and 200k tokens per minute.
+// The limiter may be shared by several clients using the same quota.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatReasoningEffort(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Yes"}}],
+			"usage":{"prompt_tokens":10,"completion_tokens":200,"total_tokens":210,
+				"completion_tokens_details":{"reasoning_tokens":192}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithModel("o3-mini"))
+	require.NoError(t, err)
+	messages := [][]schema.ChatMessage{{schema.HumanChatMessage{Content: "Is 17 prime?"}}}
+
+	generations, err := chat.Generate(context.Background(), messages,
+		llms.WithReasoningEffort(llms.ReasoningEffortHigh), llms.WithMaxTokens(1000))
+	require.NoError(t, err)
+	assert.Equal(t, "high", got["reasoning_effort"])
+	// Reasoning models take max completion tokens in place of max tokens.
+	assert.Equal(t, float64(1000), got["max_completion_tokens"])
+	assert.NotContains(t, got, "max_tokens")
+	assert.Equal(t, 192, generations[0].Usage.ReasoningTokens)
+	assert.Equal(t, 200, generations[0].Usage.CompletionTokens)
+
+	// Other models keep max tokens.
+	_, err = chat.Generate(context.Background(), messages, llms.WithModel("gpt-4o"), llms.WithMaxTokens(1000))
+	require.NoError(t, err)
+	assert.Equal(t, float64(1000), got["max_tokens"])
+	assert.NotContains(t, got, "reasoning_effort")
+}
+
+func TestChatReasoningContent(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req["stream"] == true {
+			fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"reasoning_content":"17 has no "}}]}`+"\n\n")
+			fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"reasoning_content":"divisors."}}]}`+"\n\n")
+			fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"content":"Yes"},"finish_reason":"stop"}]}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Yes",
+			"reasoning_content":"17 has no divisors."}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithOpenAICompatible(server.URL), WithModel("deepseek-reasoner"))
+	require.NoError(t, err)
+	messages := [][]schema.ChatMessage{{schema.HumanChatMessage{Content: "Is 17 prime?"}}}
+
+	generations, err := chat.Generate(context.Background(), messages)
+	require.NoError(t, err)
+	assert.Equal(t, "Yes", generations[0].Text)
+	assert.Equal(t, "17 has no divisors.", generations[0].Reasoning())
+
+	var reasoning string
+	generations, err = chat.Generate(context.Background(), messages,
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			reasoning += chunk.Reasoning
+			return nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "17 has no divisors.", reasoning)
+	assert.Equal(t, "17 has no divisors.", generations[0].Reasoning())
+}
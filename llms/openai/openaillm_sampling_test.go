@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatSamplingOptions(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Yes"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Yes or no?"}}
+	_, err = chat.Call(context.Background(), messages,
+		llms.WithStopWords([]string{"\n"}),
+		llms.WithFrequencyPenalty(0.5),
+		llms.WithPresencePenalty(-0.5),
+		llms.WithLogitBias(map[string]int{"9642": 100, "2822": -100}))
+	require.NoError(t, err)
+	assert.Equal(t, []any{"\n"}, got["stop"])
+	assert.Equal(t, 0.5, got["frequency_penalty"])
+	assert.Equal(t, -0.5, got["presence_penalty"])
+	assert.Equal(t, map[string]any{"9642": float64(100), "2822": float64(-100)}, got["logit_bias"])
+
+	got = nil
+	_, err = chat.Call(context.Background(), messages, llms.WithPresencePenalty(3))
+	require.ErrorIs(t, err, llms.ErrInvalidCallOption)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatSeed(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"4"}}],"system_fingerprint":"fp_44709d6fcb"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "2+2?"},
+	}}, llms.WithSeed(42))
+	require.NoError(t, err)
+	assert.Equal(t, float64(42), got["seed"])
+	assert.Equal(t, "fp_44709d6fcb", generations[0].SystemFingerprint())
+
+	// The seed is not sent unless set.
+	got = nil
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "2+2?"}})
+	require.NoError(t, err)
+	assert.NotContains(t, got, "seed")
+
+	// A seed of zero is sent.
+	got = nil
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "2+2?"},
+	}, llms.WithSeed(0))
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), got["seed"])
+}
+
+func TestChatStreamingSystemFingerprint(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: "+`{"choices":[{"delta":{"content":"4"}}],"system_fingerprint":"fp_44709d6fcb"}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "2+2?"},
+	}}, llms.WithSeed(42), llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.NoError(t, err)
+	assert.Equal(t, "fp_44709d6fcb", generations[0].SystemFingerprint())
+}
+
+func TestCompletionSeed(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"text":"4"}],"system_fingerprint":"fp_44709d6fcb"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"2+2="}, llms.WithSeed(7))
+	require.NoError(t, err)
+	assert.Equal(t, float64(7), got["seed"])
+	assert.Equal(t, "fp_44709d6fcb", generations[0].SystemFingerprint())
+}
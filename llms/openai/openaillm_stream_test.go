@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatStreamInterrupted(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+		switch r.Header.Get("X-Case") {
+		case "error":
+			fmt.Fprint(w, "data: {\"error\":{\"message\":\"Overloaded\",\"type\":\"server_error\",\"code\":\"overloaded_error\"}}\n\n") //nolint:lll
+		case "hang":
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+		// Otherwise the stream ends without a finish reason.
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}}
+	streaming := llms.WithStreamingFunc(func(context.Context, []byte) error { return nil })
+
+	ctx := llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"error"}})
+	_, err = chat.Call(ctx, messages, streaming)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	require.ErrorIs(t, err, openaiclient.ErrStream)
+	var streamErr *llms.StreamError
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hel", streamErr.Partial)
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "overloaded_error", statusErr.Code)
+	assert.Equal(t, "Overloaded", statusErr.Message)
+	assert.True(t, llms.IsRetryableError(err))
+
+	_, err = chat.Call(context.Background(), messages, streaming)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hel", streamErr.Partial)
+
+	ctx, cancel := context.WithCancel(llms.WithRequestHeaders(context.Background(), http.Header{"X-Case": {"hang"}}))
+	defer cancel()
+	start := time.Now()
+	_, err = chat.Call(ctx, messages, llms.WithStreamingFunc(func(context.Context, []byte) error {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return nil
+	}))
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, llms.ErrStreamInterrupted)
+	require.ErrorAs(t, err, &streamErr)
+	assert.Equal(t, "Hel", streamErr.Partial)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.False(t, llms.IsRetryableError(err))
+}
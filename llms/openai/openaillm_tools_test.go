@@ -0,0 +1,291 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type weatherParams struct {
+	Location string `json:"location" description:"The city"`
+}
+
+func TestChatTools(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"finish_reason":"tool_calls","message":{"role":"assistant","content":"",
+			"tool_calls":[{"id":"call_1","type":"function","function":{"name":"weather","arguments":"{\"location\":\"Paris\"}"}}]}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	tool, err := llms.NewFunctionTool("weather", "Get the weather", weatherParams{})
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Weather in Paris and Rome?"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+			ID:           "call_0",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"location":"Rome"}`},
+		}}},
+		schema.ToolChatMessage{ID: "call_0", Content: "sunny"},
+	}, llms.WithTools([]llms.Tool{tool}), llms.WithToolChoice(llms.ToolChoiceFunction("weather")))
+	require.NoError(t, err)
+
+	require.Len(t, msg.ToolCalls, 1)
+	assert.Equal(t, "call_1", msg.ToolCalls[0].ID)
+	assert.Equal(t, "weather", msg.ToolCalls[0].FunctionCall.Name)
+	assert.Equal(t, `{"location":"Paris"}`, msg.ToolCalls[0].FunctionCall.Arguments)
+
+	expected := `{
+		"model": "gpt-3.5-turbo",
+		"messages": [
+			{"role": "user", "content": "Weather in Paris and Rome?"},
+			{"role": "assistant", "content": "", "tool_calls": [
+				{"id": "call_0", "type": "function", "function": {"name": "weather", "arguments": "{\"location\":\"Rome\"}"}}
+			]},
+			{"role": "tool", "content": "sunny", "tool_call_id": "call_0"}
+		],
+		"tools": [{"type": "function", "function": {
+			"name": "weather",
+			"description": "Get the weather",
+			"parameters": {
+				"type": "object",
+				"properties": {"location": {"type": "string", "description": "The city"}},
+				"required": ["location"],
+				"additionalProperties": false
+			}
+		}}],
+		"tool_choice": {"type": "function", "function": {"name": "weather"}}
+	}`
+	b, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(b))
+}
+
+func TestChatResponseFormat(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"{\"location\":\"Paris\"}"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	format, err := llms.JSONSchemaFor("weather", weatherParams{})
+	require.NoError(t, err)
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Where is the Eiffel tower?"},
+	}, llms.WithResponseFormat(format))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"location":"Paris"}`, msg.Content)
+
+	responseFormat, err := json.Marshal(got["response_format"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "json_schema",
+		"json_schema": {
+			"name": "weather",
+			"schema": {
+				"type": "object",
+				"properties": {"location": {"type": "string", "description": "The city"}},
+				"required": ["location"],
+				"additionalProperties": false
+			}
+		}
+	}`, string(responseFormat))
+}
+
+func TestChatStreamingUsage(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"role":"assistant","content":"Hel"}}]}
+
+data: {"choices":[{"delta":{"content":"lo"},"finish_reason":"stop"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":9,"completion_tokens":2,"total_tokens":11,"prompt_tokens_details":{"cached_tokens":4}}}
+
+data: [DONE]
+`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []string
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Hi"},
+	}}, llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hello", generations[0].Text)
+	assert.Equal(t, []string{"Hel", "lo"}, chunks)
+	assert.Equal(t, llms.Usage{PromptTokens: 9, CompletionTokens: 2, TotalTokens: 11, CachedTokens: 4}, generations[0].Usage)
+	assert.Equal(t, map[string]any{"include_usage": true}, got["stream_options"])
+}
+
+func TestChatStatusError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}})
+	var statusErr *llms.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Equal(t, "rate_limit_exceeded", statusErr.Code)
+	assert.Equal(t, 2*time.Second, statusErr.RetryAfter)
+	assert.True(t, llms.IsRetryableError(err))
+}
+
+func TestChatRequestContentParts(t *testing.T) {
+	t.Parallel()
+
+	req := chatRequest([]schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Hi"},
+		schema.HumanChatMessage{Content: "Compare", Parts: []schema.ContentPart{
+			schema.ImageURLPart{URL: "https://example.com/cat.png", Detail: "low"},
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")},
+			schema.FilePart{Filename: "doc.pdf", MIMEType: "application/pdf", Data: []byte("pdf")},
+		}},
+	}, llms.CallOptions{})
+	b, err := json.Marshal(req.Messages)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"role":"user","content":"Hi"},
+		{"role":"user","content":[
+			{"type":"text","text":"Compare"},
+			{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"low"}},
+			{"type":"image_url","image_url":{"url":"data:image/png;base64,cG5n"}},
+			{"type":"file","file":{"filename":"doc.pdf","file_data":"data:application/pdf;base64,cGRm"}}
+		]}
+	]`, string(b))
+}
+
+func TestChatStreamingToolCalls(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"weather","arguments":""}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"location\":"}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}
+
+data: {"choices":[{"delta":{},"finish_reason":"tool_calls"}]}
+
+data: [DONE]
+`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Weather in Paris?"},
+	}, llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []llms.StreamChunk{
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, ID: "call_1", Name: "weather"}}},
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, Arguments: `{"location":`}}},
+		{ToolCalls: []llms.ToolCallChunk{{Index: 0, Arguments: `"Paris"}`}}},
+		{FinishReason: "tool_calls"},
+	}, chunks)
+	require.Len(t, msg.ToolCalls, 1)
+	assert.Equal(t, "call_1", msg.ToolCalls[0].ID)
+	assert.Equal(t, "weather", msg.ToolCalls[0].FunctionCall.Name)
+	assert.Equal(t, `{"location":"Paris"}`, msg.ToolCalls[0].FunctionCall.Arguments)
+}
+
+func TestChatRequestHeaders(t *testing.T) {
+	t.Parallel()
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Hi"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat := llms.WithChatMiddleware(mustNewChat(t, server.URL), llms.HeaderMiddleware(http.Header{
+		"Authorization": {"Bearer gateway"},
+		"X-Team":        {"search"},
+	}))
+	_, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "Hi"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer gateway", got.Get("Authorization"))
+	assert.Equal(t, "search", got.Get("X-Team"))
+}
+
+func mustNewChat(t *testing.T, baseURL string) *Chat {
+	t.Helper()
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(baseURL))
+	require.NoError(t, err)
+	return chat
+}
+
+func TestChatPromptCacheKey(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"choices":[{"message":{"role":"assistant","content":"Hi"}}],
+			"usage":{"prompt_tokens":2048,"completion_tokens":1,"total_tokens":2049,
+				"prompt_tokens_details":{"cached_tokens":1024}}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	generations, err := mustNewChat(t, server.URL).Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "Hi"},
+	}}, llms.WithPromptCaching(llms.PromptCaching{Key: "support-bot"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "support-bot", got["prompt_cache_key"])
+	assert.Equal(t, 1024, generations[0].Usage.CachedTokens)
+}
@@ -13,17 +13,24 @@ type CallOptions struct {
 	MaxTokens int `json:"max_tokens"`
 	// Temperature is the temperature for sampling, between 0 and 1.
 	Temperature float64 `json:"temperature"`
-	// StopWords is a list of words to stop on.
+	// StopWords is a list of words to stop on. The generated text does not
+	// include the stop word.
 	StopWords []string `json:"stop_words"`
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error
+	// StreamingChunkFunc is a function to be called for each structured chunk
+	// of a streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk StreamChunk) error
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
 	TopP float64 `json:"top_p"`
 	// Seed is a seed for deterministic sampling.
 	Seed int `json:"seed"`
+	// SeedSet is true if Seed is set with WithSeed, so that a seed of zero is
+	// sent.
+	SeedSet bool `json:"seed_set,omitempty"`
 	// MinLength is the minimum length of the generated text.
 	MinLength int `json:"min_length"`
 	// MaxLength is the maximum length of the generated text.
@@ -32,10 +39,16 @@ type CallOptions struct {
 	N int `json:"n"`
 	// RepetitionPenalty is the repetition penalty for sampling.
 	RepetitionPenalty float64 `json:"repetition_penalty"`
-	// FrequencyPenalty is the frequency penalty for sampling.
+	// FrequencyPenalty is the frequency penalty for sampling, penalizing the
+	// tokens in proportion to their count in the text so far.
 	FrequencyPenalty float64 `json:"frequency_penalty"`
-	// PresencePenalty is the presence penalty for sampling.
+	// PresencePenalty is the presence penalty for sampling, penalizing the
+	// tokens present in the text so far.
 	PresencePenalty float64 `json:"presence_penalty"`
+	// LogitBias maps the ids of tokens of the tokenizer of the model to a
+	// bias added to their logits, between -100 to ban and 100 to force a
+	// token.
+	LogitBias map[string]int `json:"logit_bias,omitempty"`
 
 	// Function defitions to include in the request.
 	Functions []FunctionDefinition `json:"functions"`
@@ -44,6 +57,62 @@ type CallOptions struct {
 	// If a specific function should be invoked, use the format:
 	// `{"name": "my_function"}`
 	FunctionCallBehavior FunctionCallBehavior `json:"function_call"`
+
+	// Tools is a list of tools the model may call.
+	Tools []Tool `json:"tools"`
+	// ToolChoice controls which tool is called by the model. It is either one
+	// of the strings "none", "auto" and "required", or a ToolChoice.
+	ToolChoice any `json:"tool_choice"`
+
+	// ResponseFormat constrains the format of the response, e.g. JSONMode.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// PromptCaching marks a prefix of the request as cacheable, e.g. with
+	// Anthropic cache control breakpoints.
+	PromptCaching *PromptCaching `json:"prompt_caching,omitempty"`
+
+	// ReasoningEffort is the reasoning effort of reasoning models.
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty"`
+	// ThinkingBudget is the number of tokens of the extended thinking of
+	// models, disabled if zero.
+	ThinkingBudget int `json:"thinking_budget,omitempty"`
+
+	// LogProbs requests the log probabilities of the generated tokens.
+	LogProbs bool `json:"logprobs"`
+	// TopLogProbs is the number of most likely alternatives returned for each
+	// generated token when LogProbs is set.
+	TopLogProbs int `json:"top_logprobs"`
+
+	// BatchConcurrency is the number of requests GenerateBatch sends at once.
+	BatchConcurrency int `json:"batch_concurrency"`
+	// NativeBatch makes GenerateBatch use the batch endpoint of the provider.
+	NativeBatch bool `json:"native_batch"`
+
+	// StructuredRetries is the number of times GenerateStructured retries
+	// when the output of the model does not match the schema.
+	StructuredRetries int `json:"structured_retries"`
+}
+
+// Tool is a tool the model may call.
+type Tool struct {
+	// Type is the type of the tool, "function" is the only supported type.
+	Type string `json:"type"`
+	// Function is the definition of the function.
+	Function *FunctionDefinition `json:"function,omitempty"`
+}
+
+// ToolChoice forces the model to call a specific tool.
+type ToolChoice struct {
+	// Type is the type of the tool, "function" is the only supported type.
+	Type string `json:"type"`
+	// Function is the function to call.
+	Function *FunctionReference `json:"function,omitempty"`
+}
+
+// FunctionReference is a reference to a function by name.
+type FunctionReference struct {
+	// Name is the name of the function.
+	Name string `json:"name"`
 }
 
 // FunctionDefinition is a definition of a function that can be called by the model.
@@ -108,6 +177,15 @@ func WithStreamingFunc(streamingFunc func(ctx context.Context, chunk []byte) err
 	}
 }
 
+// WithStreamingChunkFunc is an option that streams the response as structured
+// chunks, including partial tool call arguments, reasoning tokens and the
+// finish reason. It can be combined with WithStreamingFunc.
+func WithStreamingChunkFunc(streamingChunkFunc func(ctx context.Context, chunk StreamChunk) error) CallOption {
+	return func(o *CallOptions) {
+		o.StreamingChunkFunc = streamingChunkFunc
+	}
+}
+
 // WithTopK will add an option to use top-k sampling.
 func WithTopK(topK int) CallOption {
 	return func(o *CallOptions) {
@@ -122,10 +200,14 @@ func WithTopP(topP float64) CallOption {
 	}
 }
 
-// WithSeed will add an option to use deterministic sampling.
+// WithSeed will add an option to use deterministic sampling. Providers
+// reporting a system fingerprint return it with Generation.SystemFingerprint,
+// repeated requests with the same seed are mostly identical while the
+// fingerprint does not change.
 func WithSeed(seed int) CallOption {
 	return func(o *CallOptions) {
 		o.Seed = seed
+		o.SeedSet = true
 	}
 }
 
@@ -171,6 +253,14 @@ func WithPresencePenalty(presencePenalty float64) CallOption {
 	}
 }
 
+// WithLogitBias will add an option to bias the sampling of the tokens,
+// mapping the token ids to a bias between -100 and 100.
+func WithLogitBias(logitBias map[string]int) CallOption {
+	return func(o *CallOptions) {
+		o.LogitBias = logitBias
+	}
+}
+
 // WithFunctionCallBehavior will add an option to set the behavior to use when calling functions.
 func WithFunctionCallBehavior(behavior FunctionCallBehavior) CallOption {
 	return func(o *CallOptions) {
@@ -184,3 +274,53 @@ func WithFunctions(functions []FunctionDefinition) CallOption {
 		o.Functions = functions
 	}
 }
+
+// WithTools will add an option to set the tools the model may call.
+func WithTools(tools []Tool) CallOption {
+	return func(o *CallOptions) {
+		o.Tools = tools
+	}
+}
+
+// WithToolChoice will add an option to set which tool is called by the model.
+// The choice is either one of the strings "none", "auto" and "required", or a
+// ToolChoice.
+func WithToolChoice(choice any) CallOption {
+	return func(o *CallOptions) {
+		o.ToolChoice = choice
+	}
+}
+
+// WithStructuredRetries will add an option to set the number of times
+// GenerateStructured retries when the output does not match the schema.
+func WithStructuredRetries(retries int) CallOption {
+	return func(o *CallOptions) {
+		o.StructuredRetries = retries
+	}
+}
+
+// WithLogProbs requests the log probabilities of the generated tokens, along
+// with the n most likely alternatives for each token. They are available from
+// Generation.LogProbs for providers that support them.
+func WithLogProbs(n int) CallOption {
+	return func(o *CallOptions) {
+		o.LogProbs = true
+		o.TopLogProbs = n
+	}
+}
+
+// WithBatchConcurrency sets the number of requests GenerateBatch sends at once. Defaults to 5.
+func WithBatchConcurrency(concurrency int) CallOption {
+	return func(o *CallOptions) {
+		o.BatchConcurrency = concurrency
+	}
+}
+
+// WithNativeBatch makes GenerateBatch use the batch endpoint of the provider
+// if it has one. Batch endpoints are cheaper but complete asynchronously,
+// e.g. within 24 hours for the OpenAI Batch API.
+func WithNativeBatch() CallOption {
+	return func(o *CallOptions) {
+		o.NativeBatch = true
+	}
+}
@@ -0,0 +1,26 @@
+package llms
+
+// PromptCaching marks a prefix of a request as cacheable. The prefix holds
+// the tools, the system prompt and the leading messages of the request.
+// Providers caching prompts automatically, such as OpenAI, ignore the prefix;
+// the cache hits of all providers are reported in Usage.CachedTokens.
+type PromptCaching struct {
+	// Messages is the number of leading messages of the prefix, including
+	// system messages. Zero caches all the messages of the request, e.g. for
+	// multi-turn conversations.
+	Messages int `json:"messages"`
+	// TTL is the lifetime of the cache entries, "5m" or "1h" on Anthropic.
+	// Defaults to the lifetime of the provider.
+	TTL string `json:"ttl,omitempty"`
+	// Key groups requests sharing a prefix to improve the cache hit rate of
+	// OpenAI, sent as the prompt_cache_key.
+	Key string `json:"key,omitempty"`
+}
+
+// WithPromptCaching marks a prefix of the request as cacheable, e.g. a large
+// system prompt or retrieved context shared by many requests.
+func WithPromptCaching(caching PromptCaching) CallOption {
+	return func(o *CallOptions) {
+		o.PromptCaching = &caching
+	}
+}
@@ -0,0 +1,100 @@
+package llms
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the requests and the tokens sent to a provider, so that
+// bulk workloads stay within the quotas of the provider instead of being
+// rejected with 429 errors. A nil limiter does not limit anything.
+type RateLimiter struct {
+	// Requests limits the number of requests, unlimited if nil.
+	Requests *rate.Limiter
+	// Tokens limits the number of tokens of the requests, the input tokens
+	// and the max tokens of the response, unlimited if nil.
+	Tokens *rate.Limiter
+}
+
+// NewRateLimiter returns a rate limiter allowing requestsPerMinute requests
+// and tokensPerMinute tokens per minute, up to a minute worth of each at
+// once. Zero does not limit the requests or the tokens.
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	l := &RateLimiter{}
+	if requestsPerMinute > 0 {
+		l.Requests = rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), requestsPerMinute)
+	}
+	if tokensPerMinute > 0 {
+		l.Tokens = rate.NewLimiter(rate.Limit(float64(tokensPerMinute)/time.Minute.Seconds()), tokensPerMinute)
+	}
+	return l
+}
+
+// Wait blocks until a request of the given number of tokens is allowed or
+// the context is done. Requests larger than the burst of the tokens limiter
+// wait for the whole burst.
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	if l == nil {
+		return nil
+	}
+	if l.Requests != nil {
+		if err := l.Requests.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.Tokens != nil && tokens > 0 {
+		if burst := l.Tokens.Burst(); tokens > burst && l.Tokens.Limit() != rate.Inf {
+			tokens = burst
+		}
+		return l.Tokens.WaitN(ctx, tokens)
+	}
+	return nil
+}
+
+// EstimateTokens returns a fast estimate of the number of tokens of the
+// texts, about four characters per token, for rate limiting.
+func EstimateTokens(texts ...string) int {
+	var chars int
+	for _, text := range texts {
+		chars += len([]rune(text))
+	}
+	return int(math.Ceil(float64(chars) / _tokenApproximation))
+}
+
+// EstimateMessageTokens returns a fast estimate of the number of tokens of
+// the messages, for rate limiting.
+func EstimateMessageTokens(messages []schema.ChatMessage) int {
+	tokens := _tokensPerReply
+	for _, m := range messages {
+		tokens += _tokensPerMessage + EstimateTokens(m.GetContent())
+	}
+	return tokens
+}
+
+// RateLimitMiddleware returns a middleware waiting for the rate limiter
+// before each request, for models without a rate limiter option.
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, req *Request) ([]*Generation, error) {
+			opts := CallOptions{}
+			for _, opt := range req.Options {
+				opt(&opts)
+			}
+			for _, prompt := range req.Prompts {
+				if err := limiter.Wait(ctx, EstimateTokens(prompt)+opts.MaxTokens); err != nil {
+					return nil, err
+				}
+			}
+			for _, messages := range req.MessageSets {
+				if err := limiter.Wait(ctx, EstimateMessageTokens(messages)+opts.MaxTokens); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var nilLimiter *RateLimiter
+	require.NoError(t, nilLimiter.Wait(ctx, 1000))
+
+	limiter := NewRateLimiter(60, 1000)
+	assert.Equal(t, 60, limiter.Requests.Burst())
+	assert.InDelta(t, 1.0, float64(limiter.Requests.Limit()), 1e-9)
+	assert.Equal(t, 1000, limiter.Tokens.Burst())
+
+	// Requests larger than the burst wait for the whole burst.
+	require.NoError(t, limiter.Wait(ctx, 5000))
+	assert.InDelta(t, 0, limiter.Tokens.Tokens(), 1)
+
+	unlimited := NewRateLimiter(0, 0)
+	assert.Nil(t, unlimited.Requests)
+	assert.Nil(t, unlimited.Tokens)
+	require.NoError(t, unlimited.Wait(ctx, 1000))
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	t.Parallel()
+
+	limiter := &RateLimiter{Requests: rate.NewLimiter(rate.Limit(0.001), 1)}
+	require.NoError(t, limiter.Wait(context.Background(), 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, limiter.Wait(ctx, 0))
+}
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, EstimateTokens())
+	assert.Equal(t, 1, EstimateTokens("Hi"))
+	assert.Equal(t, 3, EstimateTokens("Hello", "world!"))
+	assert.Equal(t, _tokensPerReply+_tokensPerMessage+2, EstimateMessageTokens([]schema.ChatMessage{
+		schema.HumanChatMessage{Content: "Hello!"},
+	}))
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	limiter := &RateLimiter{Tokens: rate.NewLimiter(rate.Limit(0.001), 100)}
+	fake := &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "ok"}}}
+	chat := WithChatMiddleware(usageChatLLM{fakeChatLLM: fake}, RateLimitMiddleware(limiter))
+
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "Hello!"}}
+	_, err := chat.Call(context.Background(), messages, WithMaxTokens(50))
+	require.NoError(t, err)
+	assert.InDelta(t, 100-50-EstimateMessageTokens(messages), limiter.Tokens.Tokens(), 1)
+
+	// The next request exceeds the remaining tokens.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = chat.Call(ctx, messages, WithMaxTokens(50))
+	require.Error(t, err)
+	assert.Len(t, fake.calls, 1)
+}
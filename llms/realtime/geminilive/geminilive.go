@@ -0,0 +1,354 @@
+// Package geminilive implements realtime sessions with the Gemini Live API.
+// The audio of the user is 16-bit little endian mono PCM at 16kHz, the audio
+// of the responses at 24kHz.
+package geminilive
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/realtime"
+	"github.com/tmc/langchaingo/llms/realtime/internal/wsconn"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrMissingToken is returned when the Gemini API key is not set.
+	ErrMissingToken = errors.New("missing the Gemini API key, set it in the GOOGLE_API_KEY environment variable")
+	// ErrSetup is returned when the server does not complete the setup of
+	// the session.
+	ErrSetup = errors.New("session setup failed")
+)
+
+const inputAudioMIMEType = "audio/pcm;rate=16000"
+
+// Session is a session with the Gemini Live API.
+type Session struct {
+	*wsconn.Session
+	turnDetection bool
+
+	mu sync.Mutex
+	// calls are the names of the pending function calls by id, the
+	// responses require the name.
+	calls map[string]string
+	// activity reports whether the user activity was started, with turn
+	// detection disabled.
+	activity bool
+	// usage is the usage of the current turn.
+	usage *llms.Usage
+}
+
+var _ realtime.Session = (*Session)(nil)
+
+// Dial opens a session configured with the config. It returns once the
+// server completed the setup of the session.
+func Dial(ctx context.Context, config realtime.Config, opts ...Option) (*Session, error) {
+	options := &options{
+		token:   os.Getenv(tokenEnvVarName),
+		model:   defaultModel,
+		baseURL: defaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.token == "" {
+		return nil, ErrMissingToken
+	}
+
+	u, err := url.Parse(options.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	query := u.Query()
+	query.Set("key", options.token)
+	u.RawQuery = query.Encode()
+
+	header := http.Header{}
+	for key, values := range llms.RequestHeaders(ctx) {
+		header[key] = values
+	}
+	conn, err := wsconn.Dial(ctx, u.String(), header)
+	if err != nil {
+		return nil, err
+	}
+	if err := setup(ctx, conn, options.model, config); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	s := &Session{
+		turnDetection: !config.DisableTurnDetection,
+		calls:         make(map[string]string),
+	}
+	s.Session = wsconn.NewSession(conn, s.readEvents)
+	return s, nil
+}
+
+// setup sends the setup message of the config and waits for its completion.
+func setup(ctx context.Context, conn *wsconn.Conn, model string, config realtime.Config) error {
+	if !strings.HasPrefix(model, "models/") {
+		model = "models/" + model
+	}
+	if err := conn.Send(ctx, map[string]any{"setup": setupMessage(model, config)}); err != nil {
+		return fmt.Errorf("send setup: %w", err)
+	}
+	var msg serverMessage
+	if err := conn.Receive(&msg); err != nil {
+		return fmt.Errorf("%w: %w", ErrSetup, err)
+	}
+	if msg.SetupComplete == nil {
+		return fmt.Errorf("%w: unexpected message", ErrSetup)
+	}
+	return nil
+}
+
+// setupMessage returns the setup of the session of the config.
+func setupMessage(model string, config realtime.Config) map[string]any {
+	generationConfig := map[string]any{}
+	// The API supports a single response modality.
+	modality := "TEXT"
+	for _, m := range config.Modalities {
+		if m == realtime.ModalityAudio {
+			modality = "AUDIO"
+		}
+	}
+	generationConfig["responseModalities"] = []string{modality}
+	if config.Voice != "" {
+		generationConfig["speechConfig"] = map[string]any{
+			"voiceConfig": map[string]any{
+				"prebuiltVoiceConfig": map[string]any{"voiceName": config.Voice},
+			},
+		}
+	}
+	if config.Temperature > 0 {
+		generationConfig["temperature"] = config.Temperature
+	}
+
+	setup := map[string]any{
+		"model":            model,
+		"generationConfig": generationConfig,
+	}
+	if config.Instructions != "" {
+		setup["systemInstruction"] = map[string]any{
+			"parts": []map[string]any{{"text": config.Instructions}},
+		}
+	}
+	if len(config.Tools) > 0 {
+		declarations := make([]map[string]any, 0, len(config.Tools))
+		for _, tool := range config.Tools {
+			if tool.Function == nil {
+				continue
+			}
+			declarations = append(declarations, map[string]any{
+				"name":        tool.Function.Name,
+				"description": tool.Function.Description,
+				"parameters":  tool.Function.Parameters,
+			})
+		}
+		setup["tools"] = []map[string]any{{"functionDeclarations": declarations}}
+	}
+	if config.InputTranscription {
+		setup["inputAudioTranscription"] = map[string]any{}
+	}
+	if modality == "AUDIO" {
+		setup["outputAudioTranscription"] = map[string]any{}
+	}
+	if config.DisableTurnDetection {
+		setup["realtimeInputConfig"] = map[string]any{
+			"automaticActivityDetection": map[string]any{"disabled": true},
+		}
+	}
+	return setup
+}
+
+// SendText sends a text message of the user, ending the turn.
+func (s *Session) SendText(ctx context.Context, text string) error {
+	return s.Send(ctx, map[string]any{
+		"clientContent": map[string]any{
+			"turns": []map[string]any{
+				{"role": "user", "parts": []map[string]any{{"text": text}}},
+			},
+			"turnComplete": true,
+		},
+	})
+}
+
+// SendAudio streams the audio to the model. With turn detection disabled,
+// the first chunk of a turn starts the activity of the user.
+func (s *Session) SendAudio(ctx context.Context, audio []byte) error {
+	if !s.turnDetection {
+		s.mu.Lock()
+		start := !s.activity
+		s.activity = true
+		s.mu.Unlock()
+		if start {
+			if err := s.sendRealtimeInput(ctx, "activityStart", map[string]any{}); err != nil {
+				return err
+			}
+		}
+	}
+	return s.sendRealtimeInput(ctx, "audio", map[string]any{
+		"mimeType": inputAudioMIMEType,
+		"data":     base64.StdEncoding.EncodeToString(audio),
+	})
+}
+
+// CommitAudio ends the activity of the user with turn detection disabled, or
+// the audio stream otherwise.
+func (s *Session) CommitAudio(ctx context.Context) error {
+	if s.turnDetection {
+		return s.sendRealtimeInput(ctx, "audioStreamEnd", true)
+	}
+	s.mu.Lock()
+	s.activity = false
+	s.mu.Unlock()
+	return s.sendRealtimeInput(ctx, "activityEnd", map[string]any{})
+}
+
+func (s *Session) sendRealtimeInput(ctx context.Context, key string, value any) error {
+	return s.Send(ctx, map[string]any{"realtimeInput": map[string]any{key: value}})
+}
+
+// SendToolResult sends the response of the function call. The model
+// continues the turn with the response.
+func (s *Session) SendToolResult(ctx context.Context, callID, result string) error {
+	s.mu.Lock()
+	name, ok := s.calls[callID]
+	delete(s.calls, callID)
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown function call %q", callID)
+	}
+	return s.Send(ctx, map[string]any{
+		"toolResponse": map[string]any{
+			"functionResponses": []map[string]any{{
+				"id":       callID,
+				"name":     name,
+				"response": map[string]any{"result": result},
+			}},
+		},
+	})
+}
+
+// serverMessage is a message sent by the server.
+type serverMessage struct {
+	SetupComplete *struct{} `json:"setupComplete"`
+	ServerContent *struct {
+		ModelTurn *struct {
+			Parts []struct {
+				Text       string `json:"text"`
+				InlineData *struct {
+					MIMEType string `json:"mimeType"`
+					Data     string `json:"data"`
+				} `json:"inlineData"`
+			} `json:"parts"`
+		} `json:"modelTurn"`
+		InputTranscription *struct {
+			Text string `json:"text"`
+		} `json:"inputTranscription"`
+		OutputTranscription *struct {
+			Text string `json:"text"`
+		} `json:"outputTranscription"`
+		Interrupted  bool `json:"interrupted"`
+		TurnComplete bool `json:"turnComplete"`
+	} `json:"serverContent"`
+	ToolCall *struct {
+		FunctionCalls []struct {
+			ID   string          `json:"id"`
+			Name string          `json:"name"`
+			Args json.RawMessage `json:"args"`
+		} `json:"functionCalls"`
+	} `json:"toolCall"`
+	UsageMetadata *struct {
+		PromptTokenCount        int `json:"promptTokenCount"`
+		ResponseTokenCount      int `json:"responseTokenCount"`
+		TotalTokenCount         int `json:"totalTokenCount"`
+		CachedContentTokenCount int `json:"cachedContentTokenCount"`
+		ThoughtsTokenCount      int `json:"thoughtsTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// readEvents reads the next server message and returns its session events.
+func (s *Session) readEvents(conn *wsconn.Conn) ([]realtime.Event, error) {
+	var msg serverMessage
+	if err := conn.Receive(&msg); err != nil {
+		return nil, err
+	}
+	if msg.UsageMetadata != nil {
+		s.mu.Lock()
+		s.usage = &llms.Usage{
+			PromptTokens:     msg.UsageMetadata.PromptTokenCount,
+			CompletionTokens: msg.UsageMetadata.ResponseTokenCount,
+			TotalTokens:      msg.UsageMetadata.TotalTokenCount,
+			CachedTokens:     msg.UsageMetadata.CachedContentTokenCount,
+			ReasoningTokens:  msg.UsageMetadata.ThoughtsTokenCount,
+		}
+		s.mu.Unlock()
+	}
+
+	var events []realtime.Event
+	if msg.ToolCall != nil {
+		for _, call := range msg.ToolCall.FunctionCalls {
+			s.mu.Lock()
+			s.calls[call.ID] = call.Name
+			s.mu.Unlock()
+			args := string(call.Args)
+			if args == "" {
+				args = "{}"
+			}
+			events = append(events, realtime.Event{
+				Type: realtime.EventToolCall,
+				ToolCall: &schema.ToolCall{
+					ID:           call.ID,
+					Type:         "function",
+					FunctionCall: &schema.FunctionCall{Name: call.Name, Arguments: args},
+				},
+			})
+		}
+	}
+	content := msg.ServerContent
+	if content == nil {
+		return events, nil
+	}
+	if content.Interrupted {
+		events = append(events, realtime.Event{Type: realtime.EventSpeechStarted})
+	}
+	if content.InputTranscription != nil && content.InputTranscription.Text != "" {
+		events = append(events, realtime.Event{Type: realtime.EventInputTranscript, Text: content.InputTranscription.Text})
+	}
+	if content.ModelTurn != nil {
+		for _, part := range content.ModelTurn.Parts {
+			if part.Text != "" {
+				events = append(events, realtime.Event{Type: realtime.EventText, Text: part.Text})
+			}
+			if part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "audio/") {
+				audio, err := base64.StdEncoding.DecodeString(part.InlineData.Data)
+				if err != nil {
+					events = append(events, realtime.Event{Type: realtime.EventError, Err: fmt.Errorf("decode audio: %w", err)})
+					continue
+				}
+				events = append(events, realtime.Event{Type: realtime.EventAudio, Audio: audio})
+			}
+		}
+	}
+	if content.OutputTranscription != nil && content.OutputTranscription.Text != "" {
+		events = append(events, realtime.Event{Type: realtime.EventTranscript, Text: content.OutputTranscription.Text})
+	}
+	if content.TurnComplete {
+		s.mu.Lock()
+		usage := s.usage
+		s.usage = nil
+		s.mu.Unlock()
+		events = append(events, realtime.Event{Type: realtime.EventResponseDone, Usage: usage})
+	}
+	return events, nil
+}
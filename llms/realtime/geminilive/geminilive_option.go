@@ -0,0 +1,41 @@
+package geminilive
+
+const (
+	tokenEnvVarName = "GOOGLE_API_KEY" //nolint:gosec
+	defaultModel    = "models/gemini-2.0-flash-live-001"
+)
+
+//nolint:lll
+const defaultBaseURL = "wss://generativelanguage.googleapis.com/ws/google.ai.generativelanguage.v1beta.GenerativeService.BidiGenerateContent"
+
+type options struct {
+	token   string
+	model   string
+	baseURL string
+}
+
+// Option is a function that configures a session.
+type Option func(*options)
+
+// WithToken passes the Gemini API key to the client. If not set, the key is
+// read from the GOOGLE_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel sets the Live API model, models/gemini-2.0-flash-live-001 by
+// default.
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL sets the websocket URL of the Live API.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
@@ -0,0 +1,145 @@
+package geminilive
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/realtime"
+	"golang.org/x/net/websocket"
+)
+
+func newTestServer(t *testing.T, handler func(ws *websocket.Conn)) string {
+	t.Helper()
+	server := httptest.NewServer(websocket.Handler(handler))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	var msg map[string]any
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	return msg
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan map[string]any, 10)
+	url := newTestServer(t, func(ws *websocket.Conn) {
+		assert.Equal(t, "test-key", ws.Request().URL.Query().Get("key"))
+
+		received <- receive(t, ws)
+		assert.NoError(t, websocket.JSON.Send(ws, map[string]any{"setupComplete": map[string]any{}}))
+		for i := 0; i < 3; i++ {
+			received <- receive(t, ws)
+		}
+
+		audio := base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4})
+		for _, msg := range []map[string]any{
+			{"toolCall": map[string]any{"functionCalls": []any{
+				map[string]any{"id": "call_1", "name": "search", "args": map[string]any{"input": "weather"}},
+			}}},
+			{"serverContent": map[string]any{"modelTurn": map[string]any{"parts": []any{
+				map[string]any{"inlineData": map[string]any{"mimeType": "audio/pcm;rate=24000", "data": audio}},
+			}}}},
+			{"serverContent": map[string]any{"outputTranscription": map[string]any{"text": "Sunny"}}},
+			{"usageMetadata": map[string]any{"promptTokenCount": 10, "responseTokenCount": 5, "totalTokenCount": 15}},
+			{"serverContent": map[string]any{"turnComplete": true}},
+		} {
+			assert.NoError(t, websocket.JSON.Send(ws, msg))
+		}
+		received <- receive(t, ws)
+		// Wait for the client to close the session.
+		var msg map[string]any
+		_ = websocket.JSON.Receive(ws, &msg)
+	})
+
+	ctx := context.Background()
+	session, err := Dial(ctx, realtime.Config{
+		Instructions: "Be brief.",
+		Voice:        "Puck",
+		Modalities:   []string{realtime.ModalityAudio},
+		Tools: []llms.Tool{{
+			Type:     "function",
+			Function: &llms.FunctionDefinition{Name: "search", Description: "Search the web."},
+		}},
+		DisableTurnDetection: true,
+	}, WithToken("test-key"), WithModel("gemini-live"), WithBaseURL(url))
+	require.NoError(t, err)
+	defer session.Close()
+
+	setup := (<-received)["setup"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, "models/gemini-live", setup["model"])
+	assert.Equal(t, map[string]any{"parts": []any{map[string]any{"text": "Be brief."}}}, setup["systemInstruction"])
+	assert.Equal(t, map[string]any{
+		"responseModalities": []any{"AUDIO"},
+		"speechConfig": map[string]any{
+			"voiceConfig": map[string]any{"prebuiltVoiceConfig": map[string]any{"voiceName": "Puck"}},
+		},
+	}, setup["generationConfig"])
+	assert.Equal(t, []any{map[string]any{"functionDeclarations": []any{map[string]any{
+		"name": "search", "description": "Search the web.", "parameters": nil,
+	}}}}, setup["tools"])
+	assert.Equal(t, map[string]any{
+		"automaticActivityDetection": map[string]any{"disabled": true},
+	}, setup["realtimeInputConfig"])
+
+	require.NoError(t, session.SendAudio(ctx, []byte{5, 6}))
+	require.NoError(t, session.CommitAudio(ctx))
+	assert.Equal(t, map[string]any{"realtimeInput": map[string]any{"activityStart": map[string]any{}}}, <-received)
+	assert.Equal(t, map[string]any{"realtimeInput": map[string]any{"audio": map[string]any{
+		"mimeType": "audio/pcm;rate=16000", "data": base64.StdEncoding.EncodeToString([]byte{5, 6}),
+	}}}, <-received)
+	assert.Equal(t, map[string]any{"realtimeInput": map[string]any{"activityEnd": map[string]any{}}}, <-received)
+
+	var events []realtime.Event
+	for event := range session.Events() {
+		events = append(events, event)
+		if event.Type == realtime.EventResponseDone {
+			break
+		}
+	}
+	require.Len(t, events, 4)
+	assert.Equal(t, realtime.EventToolCall, events[0].Type)
+	assert.Equal(t, "call_1", events[0].ToolCall.ID)
+	assert.Equal(t, "search", events[0].ToolCall.FunctionCall.Name)
+	assert.JSONEq(t, `{"input":"weather"}`, events[0].ToolCall.FunctionCall.Arguments.(string)) //nolint:forcetypeassert
+	assert.Equal(t, realtime.Event{Type: realtime.EventAudio, Audio: []byte{1, 2, 3, 4}}, events[1])
+	assert.Equal(t, realtime.Event{Type: realtime.EventTranscript, Text: "Sunny"}, events[2])
+	assert.Equal(t, &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, events[3].Usage)
+
+	require.NoError(t, session.SendToolResult(ctx, "call_1", "sunny"))
+	assert.Equal(t, map[string]any{"toolResponse": map[string]any{"functionResponses": []any{map[string]any{
+		"id": "call_1", "name": "search", "response": map[string]any{"result": "sunny"},
+	}}}}, <-received)
+	require.Error(t, session.SendToolResult(ctx, "call_1", "sunny"))
+
+	require.NoError(t, session.Close())
+	for range session.Events() { //nolint:revive
+	}
+	require.NoError(t, session.Err())
+}
+
+func TestDialSetupFailed(t *testing.T) {
+	t.Parallel()
+
+	url := newTestServer(t, func(ws *websocket.Conn) {
+		receive(t, ws)
+	})
+	_, err := Dial(context.Background(), realtime.Config{}, WithToken("test-key"), WithBaseURL(url))
+	require.ErrorIs(t, err, ErrSetup)
+}
+
+func TestDialMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := Dial(context.Background(), realtime.Config{})
+	require.ErrorIs(t, err, ErrMissingToken)
+}
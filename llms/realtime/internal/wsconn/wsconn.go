@@ -0,0 +1,199 @@
+// Package wsconn implements the websocket sessions of the realtime providers.
+package wsconn
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms/realtime"
+	"golang.org/x/net/websocket"
+)
+
+// eventBufferSize is the number of events buffered before the reading of the
+// connection blocks.
+const eventBufferSize = 64
+
+// Conn is a websocket connection exchanging JSON messages.
+type Conn struct {
+	ws *websocket.Conn
+}
+
+// Dial opens a websocket connection to the ws or wss URL with the headers.
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	origin := "http://" + u.Host
+	port := "80"
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		origin, port = "https://"+u.Host, "443"
+	default:
+		return nil, fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	config, err := websocket.NewConfig(rawURL, origin)
+	if err != nil {
+		return nil, fmt.Errorf("websocket config: %w", err)
+	}
+	for k, v := range header {
+		config.Header[k] = v
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+
+	// The handshake is canceled with the context.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now()) //nolint:errcheck
+		case <-stop:
+		}
+	}()
+
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	return &Conn{ws: ws}, nil
+}
+
+// Send sends the value as a JSON text message. The write fails at the
+// deadline of the context.
+func (c *Conn) Send(ctx context.Context, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := c.ws.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(c.ws, v)
+}
+
+// Receive reads the next message into the value.
+func (c *Conn) Receive(v any) error {
+	return websocket.JSON.Receive(c.ws, v)
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+// Session reads the messages of a connection in the background, converting
+// them to the events of a realtime session.
+type Session struct {
+	conn   *Conn
+	events chan realtime.Event
+	done   chan struct{}
+	once   sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewSession starts reading the messages of the connection. The read
+// function reads the next message and returns its events.
+func NewSession(conn *Conn, read func(conn *Conn) ([]realtime.Event, error)) *Session {
+	s := &Session{
+		conn:   conn,
+		events: make(chan realtime.Event, eventBufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.loop(read)
+	return s
+}
+
+func (s *Session) loop(read func(conn *Conn) ([]realtime.Event, error)) {
+	defer close(s.events)
+	for {
+		events, err := read(s.conn)
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.mu.Lock()
+				s.err = err
+				s.mu.Unlock()
+			}
+			return
+		}
+		for _, event := range events {
+			select {
+			case s.events <- event:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+// Send sends the message, or returns realtime.ErrSessionClosed.
+func (s *Session) Send(ctx context.Context, v any) error {
+	select {
+	case <-s.done:
+		return realtime.ErrSessionClosed
+	default:
+	}
+	if err := s.conn.Send(ctx, v); err != nil {
+		select {
+		case <-s.done:
+			return realtime.ErrSessionClosed
+		default:
+		}
+		return fmt.Errorf("send: %w", err)
+	}
+	return nil
+}
+
+// Events returns the events of the session.
+func (s *Session) Events() <-chan realtime.Event {
+	return s.events
+}
+
+// Err returns the error ending the session.
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the session.
+func (s *Session) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+	})
+	return err
+}
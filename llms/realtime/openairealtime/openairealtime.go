@@ -0,0 +1,261 @@
+// Package openairealtime implements realtime sessions with the OpenAI
+// Realtime API. The audio is 16-bit little endian mono PCM at 24kHz.
+package openairealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/realtime"
+	"github.com/tmc/langchaingo/llms/realtime/internal/wsconn"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrMissingToken is returned when the OpenAI API key is not set.
+var ErrMissingToken = errors.New("missing the OpenAI API key, set it in the OPENAI_API_KEY environment variable")
+
+// Session is a session with the OpenAI Realtime API.
+type Session struct {
+	*wsconn.Session
+	turnDetection bool
+}
+
+var _ realtime.Session = (*Session)(nil)
+
+// Dial opens a session configured with the config.
+func Dial(ctx context.Context, config realtime.Config, opts ...Option) (*Session, error) {
+	options := &options{
+		token:   os.Getenv(tokenEnvVarName),
+		model:   defaultModel,
+		baseURL: defaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.token == "" {
+		return nil, ErrMissingToken
+	}
+
+	u, err := url.Parse(options.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	query := u.Query()
+	query.Set("model", options.model)
+	u.RawQuery = query.Encode()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+options.token)
+	header.Set("OpenAI-Beta", "realtime=v1")
+	for key, values := range llms.RequestHeaders(ctx) {
+		header[key] = values
+	}
+	conn, err := wsconn.Dial(ctx, u.String(), header)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Send(ctx, sessionUpdate(config)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("update session: %w", err)
+	}
+	return &Session{
+		Session:       wsconn.NewSession(conn, readEvents),
+		turnDetection: !config.DisableTurnDetection,
+	}, nil
+}
+
+// SendText sends a text message of the user and requests a response.
+func (s *Session) SendText(ctx context.Context, text string) error {
+	err := s.Send(ctx, map[string]any{
+		"type": "conversation.item.create",
+		"item": map[string]any{
+			"type": "message",
+			"role": "user",
+			"content": []map[string]any{
+				{"type": "input_text", "text": text},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.createResponse(ctx)
+}
+
+// SendAudio appends the audio to the input audio buffer.
+func (s *Session) SendAudio(ctx context.Context, audio []byte) error {
+	return s.Send(ctx, map[string]any{
+		"type":  "input_audio_buffer.append",
+		"audio": base64.StdEncoding.EncodeToString(audio),
+	})
+}
+
+// CommitAudio commits the input audio buffer and requests a response. With
+// turn detection the server commits the buffer and responds on its own.
+func (s *Session) CommitAudio(ctx context.Context) error {
+	if err := s.Send(ctx, map[string]any{"type": "input_audio_buffer.commit"}); err != nil {
+		return err
+	}
+	if s.turnDetection {
+		return nil
+	}
+	return s.createResponse(ctx)
+}
+
+// SendToolResult sends the output of the function call and requests a
+// response.
+func (s *Session) SendToolResult(ctx context.Context, callID, result string) error {
+	err := s.Send(ctx, map[string]any{
+		"type": "conversation.item.create",
+		"item": map[string]any{
+			"type":    "function_call_output",
+			"call_id": callID,
+			"output":  result,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.createResponse(ctx)
+}
+
+func (s *Session) createResponse(ctx context.Context) error {
+	return s.Send(ctx, map[string]any{"type": "response.create"})
+}
+
+// sessionUpdate returns the session.update event of the config.
+func sessionUpdate(config realtime.Config) map[string]any {
+	session := map[string]any{
+		"input_audio_format":  "pcm16",
+		"output_audio_format": "pcm16",
+	}
+	if config.Instructions != "" {
+		session["instructions"] = config.Instructions
+	}
+	if config.Voice != "" {
+		session["voice"] = config.Voice
+	}
+	// The API requires text with audio.
+	modalities := []string{realtime.ModalityText}
+	for _, modality := range config.Modalities {
+		if modality == realtime.ModalityAudio {
+			modalities = append(modalities, realtime.ModalityAudio)
+		}
+	}
+	session["modalities"] = modalities
+	if config.Temperature > 0 {
+		session["temperature"] = config.Temperature
+	}
+	if config.InputTranscription {
+		session["input_audio_transcription"] = map[string]any{"model": "whisper-1"}
+	}
+	if config.DisableTurnDetection {
+		session["turn_detection"] = nil
+	} else {
+		session["turn_detection"] = map[string]any{"type": "server_vad"}
+	}
+	if len(config.Tools) > 0 {
+		tools := make([]map[string]any, 0, len(config.Tools))
+		for _, tool := range config.Tools {
+			if tool.Function == nil {
+				continue
+			}
+			tools = append(tools, map[string]any{
+				"type":        "function",
+				"name":        tool.Function.Name,
+				"description": tool.Function.Description,
+				"parameters":  tool.Function.Parameters,
+			})
+		}
+		session["tools"] = tools
+		session["tool_choice"] = "auto"
+	}
+	return map[string]any{"type": "session.update", "session": session}
+}
+
+// serverEvent is an event sent by the server.
+type serverEvent struct {
+	Type       string `json:"type"`
+	Delta      string `json:"delta"`
+	Transcript string `json:"transcript"`
+	CallID     string `json:"call_id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Response   *struct {
+		Usage *struct {
+			InputTokens       int `json:"input_tokens"`
+			OutputTokens      int `json:"output_tokens"`
+			TotalTokens       int `json:"total_tokens"`
+			InputTokenDetails struct {
+				CachedTokens int `json:"cached_tokens"`
+			} `json:"input_token_details"`
+		} `json:"usage"`
+	} `json:"response"`
+	Error *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readEvents reads the next server event and returns its session events.
+// The events of the beta and the GA versions of the API are supported.
+func readEvents(conn *wsconn.Conn) ([]realtime.Event, error) {
+	var event serverEvent
+	if err := conn.Receive(&event); err != nil {
+		return nil, err
+	}
+	switch event.Type {
+	case "response.text.delta", "response.output_text.delta":
+		return []realtime.Event{{Type: realtime.EventText, Text: event.Delta}}, nil
+	case "response.audio.delta", "response.output_audio.delta":
+		audio, err := base64.StdEncoding.DecodeString(event.Delta)
+		if err != nil {
+			return []realtime.Event{{Type: realtime.EventError, Err: fmt.Errorf("decode audio: %w", err)}}, nil
+		}
+		return []realtime.Event{{Type: realtime.EventAudio, Audio: audio}}, nil
+	case "response.audio_transcript.delta", "response.output_audio_transcript.delta":
+		return []realtime.Event{{Type: realtime.EventTranscript, Text: event.Delta}}, nil
+	case "conversation.item.input_audio_transcription.completed":
+		return []realtime.Event{{Type: realtime.EventInputTranscript, Text: event.Transcript}}, nil
+	case "input_audio_buffer.speech_started":
+		return []realtime.Event{{Type: realtime.EventSpeechStarted}}, nil
+	case "response.function_call_arguments.done":
+		return []realtime.Event{{
+			Type: realtime.EventToolCall,
+			ToolCall: &schema.ToolCall{
+				ID:   event.CallID,
+				Type: "function",
+				FunctionCall: &schema.FunctionCall{
+					Name:      event.Name,
+					Arguments: event.Arguments,
+				},
+			},
+		}}, nil
+	case "response.done":
+		done := realtime.Event{Type: realtime.EventResponseDone}
+		if event.Response != nil && event.Response.Usage != nil {
+			usage := event.Response.Usage
+			done.Usage = &llms.Usage{
+				PromptTokens:     usage.InputTokens,
+				CompletionTokens: usage.OutputTokens,
+				TotalTokens:      usage.TotalTokens,
+				CachedTokens:     usage.InputTokenDetails.CachedTokens,
+			}
+		}
+		return []realtime.Event{done}, nil
+	case "error":
+		err := errors.New("unknown error")
+		if event.Error != nil {
+			err = fmt.Errorf("%s: %s", event.Error.Type, event.Error.Message)
+		}
+		return []realtime.Event{{Type: realtime.EventError, Err: err}}, nil
+	}
+	return nil, nil
+}
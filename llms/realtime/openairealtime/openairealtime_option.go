@@ -0,0 +1,38 @@
+package openairealtime
+
+const (
+	tokenEnvVarName = "OPENAI_API_KEY" //nolint:gosec
+	defaultBaseURL  = "wss://api.openai.com/v1/realtime"
+	defaultModel    = "gpt-4o-realtime-preview"
+)
+
+type options struct {
+	token   string
+	model   string
+	baseURL string
+}
+
+// Option is a function that configures a session.
+type Option func(*options)
+
+// WithToken passes the OpenAI API token to the client. If not set, the token
+// is read from the OPENAI_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel sets the realtime model, gpt-4o-realtime-preview by default.
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL sets the websocket URL of the realtime API.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
@@ -0,0 +1,147 @@
+package openairealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/realtime"
+	"golang.org/x/net/websocket"
+)
+
+func newTestServer(t *testing.T, handler func(ws *websocket.Conn)) string {
+	t.Helper()
+	server := httptest.NewServer(websocket.Handler(handler))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	var msg map[string]any
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	return msg
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan map[string]any, 10)
+	url := newTestServer(t, func(ws *websocket.Conn) {
+		assert.Equal(t, "Bearer test-token", ws.Request().Header.Get("Authorization"))
+		assert.Equal(t, "realtime=v1", ws.Request().Header.Get("OpenAI-Beta"))
+		assert.Equal(t, "test-model", ws.Request().URL.Query().Get("model"))
+
+		for i := 0; i < 3; i++ {
+			received <- receive(t, ws)
+		}
+		audio := base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4})
+		for _, event := range []map[string]any{
+			{"type": "session.created"},
+			{"type": "response.text.delta", "delta": "Hello"},
+			{"type": "response.audio.delta", "delta": audio},
+			{"type": "response.audio_transcript.delta", "delta": "Hi"},
+			{
+				"type":      "response.function_call_arguments.done",
+				"call_id":   "call_1",
+				"name":      "search",
+				"arguments": `{"input":"weather"}`,
+			},
+			{"type": "response.done", "response": map[string]any{
+				"usage": map[string]any{"input_tokens": 10, "output_tokens": 5, "total_tokens": 15},
+			}},
+		} {
+			assert.NoError(t, websocket.JSON.Send(ws, event))
+		}
+		for i := 0; i < 2; i++ {
+			received <- receive(t, ws)
+		}
+		assert.NoError(t, websocket.JSON.Send(ws, map[string]any{
+			"type":  "error",
+			"error": map[string]any{"type": "invalid_request_error", "message": "bad"},
+		}))
+		// Wait for the client to close the session.
+		var msg map[string]any
+		_ = websocket.JSON.Receive(ws, &msg)
+	})
+
+	ctx := context.Background()
+	session, err := Dial(ctx, realtime.Config{
+		Instructions: "Be brief.",
+		Voice:        "alloy",
+		Modalities:   []string{realtime.ModalityAudio},
+		Tools: []llms.Tool{{
+			Type:     "function",
+			Function: &llms.FunctionDefinition{Name: "search", Description: "Search the web."},
+		}},
+	}, WithToken("test-token"), WithModel("test-model"), WithBaseURL(url))
+	require.NoError(t, err)
+	defer session.Close()
+
+	require.NoError(t, session.SendText(ctx, "Hello!"))
+
+	update := (<-received)["session"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, "Be brief.", update["instructions"])
+	assert.Equal(t, "alloy", update["voice"])
+	assert.Equal(t, []any{"text", "audio"}, update["modalities"])
+	assert.Equal(t, map[string]any{"type": "server_vad"}, update["turn_detection"])
+	assert.Equal(t, []any{map[string]any{
+		"type": "function", "name": "search", "description": "Search the web.", "parameters": nil,
+	}}, update["tools"])
+	assert.Equal(t, "conversation.item.create", (<-received)["type"])
+	assert.Equal(t, "response.create", (<-received)["type"])
+
+	var events []realtime.Event
+	for event := range session.Events() {
+		events = append(events, event)
+		if event.Type == realtime.EventResponseDone {
+			break
+		}
+	}
+	require.Len(t, events, 5)
+	assert.Equal(t, realtime.Event{Type: realtime.EventText, Text: "Hello"}, events[0])
+	assert.Equal(t, realtime.Event{Type: realtime.EventAudio, Audio: []byte{1, 2, 3, 4}}, events[1])
+	assert.Equal(t, realtime.Event{Type: realtime.EventTranscript, Text: "Hi"}, events[2])
+	assert.Equal(t, "call_1", events[3].ToolCall.ID)
+	assert.Equal(t, "search", events[3].ToolCall.FunctionCall.Name)
+	assert.Equal(t, `{"input":"weather"}`, events[3].ToolCall.FunctionCall.Arguments)
+	assert.Equal(t, &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, events[4].Usage)
+
+	require.NoError(t, session.SendToolResult(ctx, "call_1", "sunny"))
+	output := (<-received)["item"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, map[string]any{"type": "function_call_output", "call_id": "call_1", "output": "sunny"}, output)
+	assert.Equal(t, "response.create", (<-received)["type"])
+
+	event := <-session.Events()
+	assert.Equal(t, realtime.EventError, event.Type)
+	require.EqualError(t, event.Err, "invalid_request_error: bad")
+
+	require.NoError(t, session.Close())
+	for range session.Events() { //nolint:revive
+	}
+	require.NoError(t, session.Err())
+	require.ErrorIs(t, session.SendText(ctx, "Hello?"), realtime.ErrSessionClosed)
+}
+
+func TestDialMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := Dial(context.Background(), realtime.Config{})
+	require.ErrorIs(t, err, ErrMissingToken)
+}
+
+func TestSessionUpdateWithoutTurnDetection(t *testing.T) {
+	t.Parallel()
+
+	update := sessionUpdate(realtime.Config{DisableTurnDetection: true, InputTranscription: true})
+	session := update["session"].(map[string]any) //nolint:forcetypeassert
+	assert.Nil(t, session["turn_detection"])
+	assert.Contains(t, session, "turn_detection")
+	assert.Equal(t, []string{"text"}, session["modalities"])
+	assert.Equal(t, map[string]any{"model": "whisper-1"}, session["input_audio_transcription"])
+}
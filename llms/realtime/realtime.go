@@ -0,0 +1,134 @@
+// Package realtime provides a channel based API for the realtime, speech to
+// speech, APIs of the providers, e.g. OpenAI Realtime and Gemini Live.
+//
+// A session is opened with the Dial function of a provider package. The
+// audio and text of the user are sent with the methods of the session, and
+// the responses of the model are received as events:
+//
+//	session, err := openairealtime.Dial(ctx, realtime.Config{
+//		Instructions: "You are a helpful voice assistant.",
+//		Modalities:   []string{realtime.ModalityAudio},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer session.Close()
+//
+//	go streamMicrophone(ctx, session)
+//	for event := range session.Events() {
+//		switch event.Type {
+//		case realtime.EventAudio:
+//			play(event.Audio)
+//		case realtime.EventSpeechStarted:
+//			stopPlayback()
+//		}
+//	}
+//	return session.Err()
+//
+// The tool calls of the model are answered with SendToolResult, or by the
+// agent tools with HandleToolCalls.
+package realtime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrSessionClosed is returned when sending to a closed session.
+var ErrSessionClosed = errors.New("realtime session closed")
+
+// Modalities of the responses.
+const (
+	ModalityText  = "text"
+	ModalityAudio = "audio"
+)
+
+// Config is the configuration of a session.
+type Config struct {
+	// Instructions are the system instructions of the model.
+	Instructions string
+	// Voice is the name of the voice of the audio responses, specific to
+	// the provider.
+	Voice string
+	// Modalities are the modalities of the responses, text by default.
+	Modalities []string
+	// Tools are the tools the model may call.
+	Tools []llms.Tool
+	// Temperature is the temperature for sampling, the default of the
+	// provider if zero.
+	Temperature float64
+	// InputTranscription transcribes the audio of the user, delivered as
+	// EventInputTranscript events.
+	InputTranscription bool
+	// DisableTurnDetection disables the voice activity detection of the
+	// provider, the turns of the user are ended with CommitAudio.
+	DisableTurnDetection bool
+}
+
+// EventType is the type of an event.
+type EventType string
+
+const (
+	// EventText is a delta of the text of a response.
+	EventText EventType = "text"
+	// EventAudio is a chunk of the audio of a response, 16-bit little
+	// endian mono PCM.
+	EventAudio EventType = "audio"
+	// EventTranscript is a delta of the transcript of the audio of a response.
+	EventTranscript EventType = "transcript"
+	// EventInputTranscript is the transcript of the audio of the user.
+	EventInputTranscript EventType = "input_transcript"
+	// EventSpeechStarted is sent when the user starts speaking, the playback
+	// of the response should stop.
+	EventSpeechStarted EventType = "speech_started"
+	// EventToolCall is a call of a tool, answered with SendToolResult.
+	EventToolCall EventType = "tool_call"
+	// EventResponseDone ends a response, with the usage of the response.
+	EventResponseDone EventType = "response_done"
+	// EventError is an error reported by the provider that does not end
+	// the session, e.g. an invalid message.
+	EventError EventType = "error"
+)
+
+// Event is an event of a session.
+type Event struct {
+	Type EventType
+	// Text is the text or the transcript of text, transcript and input
+	// transcript events.
+	Text string
+	// Audio is the audio of audio events.
+	Audio []byte
+	// ToolCall is the call of tool call events.
+	ToolCall *schema.ToolCall
+	// Usage is the usage of response done events, if reported.
+	Usage *llms.Usage
+	// Err is the error of error events.
+	Err error
+}
+
+// Session is a realtime session with a model. The methods may be called
+// concurrently with the reading of the events.
+type Session interface {
+	// SendText sends a text message of the user and requests a response.
+	SendText(ctx context.Context, text string) error
+	// SendAudio sends a chunk of the audio of the user, 16-bit little endian
+	// mono PCM at the sample rate of the provider.
+	SendAudio(ctx context.Context, audio []byte) error
+	// CommitAudio ends the turn of the user, with turn detection disabled,
+	// and requests a response.
+	CommitAudio(ctx context.Context) error
+	// SendToolResult sends the result of the tool call of the id and
+	// requests a response.
+	SendToolResult(ctx context.Context, callID, result string) error
+	// Events returns the events of the session. The channel is closed when
+	// the session ends.
+	Events() <-chan Event
+	// Err returns the error ending the session once the events are closed,
+	// nil if the session was closed with Close.
+	Err() error
+	// Close ends the session.
+	Close() error
+}
@@ -0,0 +1,86 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// AgentTools returns the function tools of the agent tools, for the tools of
+// the config. The tools take their input as the input string parameter.
+func AgentTools(agentTools []tools.Tool) []llms.Tool {
+	functionTools := make([]llms.Tool, 0, len(agentTools))
+	for _, tool := range agentTools {
+		functionTools = append(functionTools, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"input": map[string]any{"type": "string"},
+					},
+					"required": []string{"input"},
+				},
+			},
+		})
+	}
+	return functionTools
+}
+
+// HandleToolCalls calls the agent tools for the tool call events of the
+// session and sends their results, or errors, to the model. The other events
+// are forwarded to the returned channel, closed with the events of the
+// session.
+func HandleToolCalls(ctx context.Context, session Session, agentTools []tools.Tool) <-chan Event {
+	byName := make(map[string]tools.Tool, len(agentTools))
+	for _, tool := range agentTools {
+		byName[tool.Name()] = tool
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for event := range session.Events() {
+			if event.Type == EventToolCall && event.ToolCall != nil && event.ToolCall.FunctionCall != nil {
+				result := callTool(ctx, byName, event.ToolCall.FunctionCall)
+				if err := session.SendToolResult(ctx, event.ToolCall.ID, result); err != nil {
+					event = Event{Type: EventError, Err: fmt.Errorf("send tool result: %w", err)}
+				} else {
+					continue
+				}
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// callTool returns the result of the tool call, or the error for the model.
+func callTool(ctx context.Context, byName map[string]tools.Tool, call *schema.FunctionCall) string {
+	tool, ok := byName[call.Name]
+	if !ok {
+		return fmt.Sprintf("error: unknown tool %q", call.Name)
+	}
+	input, _ := call.Arguments.(string)
+	var args struct {
+		Input string `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(input), &args); err == nil {
+		input = args.Input
+	}
+	result, err := tool.Call(ctx, input)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return result
+}
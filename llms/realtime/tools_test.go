@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type fakeSession struct {
+	events  chan Event
+	results map[string]string
+}
+
+func (s *fakeSession) SendText(context.Context, string) error  { return nil }
+func (s *fakeSession) SendAudio(context.Context, []byte) error { return nil }
+func (s *fakeSession) CommitAudio(context.Context) error       { return nil }
+func (s *fakeSession) Events() <-chan Event                    { return s.events }
+func (s *fakeSession) Err() error                              { return nil }
+func (s *fakeSession) Close() error                            { return nil }
+func (s *fakeSession) SendToolResult(_ context.Context, callID, result string) error {
+	s.results[callID] = result
+	return nil
+}
+
+type fakeTool struct {
+	name string
+	err  error
+}
+
+func (t fakeTool) Name() string        { return t.name }
+func (t fakeTool) Description() string { return "A fake tool." }
+func (t fakeTool) Call(_ context.Context, input string) (string, error) {
+	return t.name + "(" + input + ")", t.err
+}
+
+func toolCall(id, name, args string) Event {
+	return Event{Type: EventToolCall, ToolCall: &schema.ToolCall{
+		ID: id, Type: "function", FunctionCall: &schema.FunctionCall{Name: name, Arguments: args},
+	}}
+}
+
+func TestAgentTools(t *testing.T) {
+	t.Parallel()
+
+	functionTools := AgentTools([]tools.Tool{fakeTool{name: "search"}})
+	require.Len(t, functionTools, 1)
+	assert.Equal(t, "function", functionTools[0].Type)
+	assert.Equal(t, "search", functionTools[0].Function.Name)
+	assert.Equal(t, "A fake tool.", functionTools[0].Function.Description)
+}
+
+func TestHandleToolCalls(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeSession{events: make(chan Event, 5), results: map[string]string{}}
+	session.events <- Event{Type: EventText, Text: "Let me check."}
+	session.events <- toolCall("1", "search", `{"input":"weather"}`)
+	session.events <- toolCall("2", "broken", `{"input":"x"}`)
+	session.events <- toolCall("3", "unknown", `{}`)
+	session.events <- Event{Type: EventResponseDone}
+	close(session.events)
+
+	agentTools := []tools.Tool{fakeTool{name: "search"}, fakeTool{name: "broken", err: errors.New("boom")}}
+	var forwarded []EventType
+	for event := range HandleToolCalls(context.Background(), session, agentTools) {
+		forwarded = append(forwarded, event.Type)
+	}
+
+	assert.Equal(t, []EventType{EventText, EventResponseDone}, forwarded)
+	assert.Equal(t, map[string]string{
+		"1": "search(weather)",
+		"2": "error: boom",
+		"3": `error: unknown tool "unknown"`,
+	}, session.results)
+}
@@ -0,0 +1,43 @@
+package llms
+
+// ReasoningEffort is how much reasoning a reasoning model does before it
+// responds, trading latency and reasoning tokens for quality.
+type ReasoningEffort string
+
+const (
+	ReasoningEffortLow    ReasoningEffort = "low"
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	ReasoningEffortHigh   ReasoningEffort = "high"
+)
+
+// ReasoningKey is the key of the reasoning text in the generation info.
+const ReasoningKey = "Reasoning"
+
+// WithReasoningEffort sets the reasoning effort of reasoning models, e.g. the
+// OpenAI o-series. Providers with a thinking budget instead map the effort to
+// a budget.
+func WithReasoningEffort(effort ReasoningEffort) CallOption {
+	return func(o *CallOptions) {
+		o.ReasoningEffort = effort
+	}
+}
+
+// WithThinkingBudget enables the extended thinking of models, e.g. Claude,
+// with a budget of tokens for the thinking. The max tokens of the response
+// include the budget.
+func WithThinkingBudget(tokens int) CallOption {
+	return func(o *CallOptions) {
+		o.ThinkingBudget = tokens
+	}
+}
+
+// Reasoning returns the reasoning text of the generation, the thinking of
+// the model or its summary, or an empty string if the provider does not
+// return it. The reasoning is streamed in StreamChunk.Reasoning.
+func (g *Generation) Reasoning() string {
+	if g == nil {
+		return ""
+	}
+	reasoning, _ := g.GenerationInfo[ReasoningKey].(string)
+	return reasoning
+}
@@ -0,0 +1,37 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+)
+
+type requestHeadersKey struct{}
+
+// WithRequestHeaders returns a context carrying headers that the providers
+// set on the HTTP requests sent with the context. The headers are added to
+// the headers of the context, and replace the headers of the providers, e.g.
+// the Authorization header.
+func WithRequestHeaders(ctx context.Context, header http.Header) context.Context {
+	merged := RequestHeaders(ctx).Clone()
+	if merged == nil {
+		merged = make(http.Header, len(header))
+	}
+	for key, values := range header {
+		merged[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	return context.WithValue(ctx, requestHeadersKey{}, merged)
+}
+
+// RequestHeaders returns the headers of the context set with WithRequestHeaders.
+func RequestHeaders(ctx context.Context) http.Header {
+	header, _ := ctx.Value(requestHeadersKey{}).(http.Header)
+	return header
+}
+
+// SetRequestHeaders sets the headers of the context of the request on the
+// request. It is called by the providers after setting their own headers.
+func SetRequestHeaders(req *http.Request) {
+	for key, values := range RequestHeaders(req.Context()) {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
@@ -0,0 +1,75 @@
+package llms
+
+import "github.com/tmc/langchaingo/llms/jsonschema"
+
+// ResponseFormatType is the type of a response format.
+type ResponseFormatType string
+
+const (
+	// ResponseFormatText is plain text, the default.
+	ResponseFormatText ResponseFormatType = "text"
+	// ResponseFormatJSONObject is any valid JSON object.
+	ResponseFormatJSONObject ResponseFormatType = "json_object"
+	// ResponseFormatJSONSchema is a JSON object matching a schema.
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema"
+)
+
+// ResponseFormat constrains the format of the response of the model. Each
+// provider maps it to its own mechanism: OpenAI uses response_format, Ollama
+// the format field and Anthropic a forced tool call whose input is returned
+// as the content of the response. There is no mapping to the responseSchema
+// of Gemini, as the vertexai package only has PaLM models.
+type ResponseFormat struct {
+	// Type is the type of the response format.
+	Type ResponseFormatType `json:"type"`
+	// JSONSchema is the schema of the response when the type is
+	// ResponseFormatJSONSchema.
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema describes the JSON object the model must respond with.
+type JSONSchema struct {
+	// Name is the name of the schema. It must match ^[a-zA-Z0-9_-]{1,64}$.
+	Name string `json:"name"`
+	// Description describes what the response is for.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON schema, e.g. a jsonschema.Definition.
+	Schema any `json:"schema"`
+	// Strict enables strict schema adherence where the provider supports it.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// JSONMode makes the model respond with a valid JSON object.
+//
+//nolint:gochecknoglobals
+var JSONMode = ResponseFormat{Type: ResponseFormatJSONObject}
+
+// JSONSchemaFormat returns a response format making the model respond with a
+// JSON object matching the schema.
+func JSONSchemaFormat(schema JSONSchema) ResponseFormat {
+	return ResponseFormat{Type: ResponseFormatJSONSchema, JSONSchema: &schema}
+}
+
+// JSONSchemaFor returns a response format making the model respond with a
+// JSON object matching the schema of v, typically a struct. See
+// jsonschema.Reflect for the supported struct tags.
+func JSONSchemaFor(name string, v any) (ResponseFormat, error) {
+	definition, err := jsonschema.Reflect(v)
+	if err != nil {
+		return ResponseFormat{}, err
+	}
+	return JSONSchemaFormat(JSONSchema{Name: name, Schema: definition}), nil
+}
+
+// IsJSON reports whether the response format requires a JSON response.
+func (f *ResponseFormat) IsJSON() bool {
+	return f != nil && (f.Type == ResponseFormatJSONObject || f.Type == ResponseFormatJSONSchema)
+}
+
+// WithResponseFormat constrains the format of the response, e.g.
+// WithResponseFormat(JSONMode) or WithResponseFormat(JSONSchemaFormat(...)).
+func WithResponseFormat(format ResponseFormat) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseFormat = &format
+	}
+}
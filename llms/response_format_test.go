@@ -0,0 +1,33 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/jsonschema"
+)
+
+func TestResponseFormat(t *testing.T) {
+	t.Parallel()
+
+	opts := CallOptions{}
+	assert.False(t, opts.ResponseFormat.IsJSON())
+
+	WithResponseFormat(JSONMode)(&opts)
+	require.NotNil(t, opts.ResponseFormat)
+	assert.True(t, opts.ResponseFormat.IsJSON())
+	assert.Nil(t, opts.ResponseFormat.JSONSchema)
+
+	WithResponseFormat(ResponseFormat{Type: ResponseFormatText})(&opts)
+	assert.False(t, opts.ResponseFormat.IsJSON())
+
+	format, err := JSONSchemaFor("person", person{})
+	require.NoError(t, err)
+	assert.Equal(t, ResponseFormatJSONSchema, format.Type)
+	require.NotNil(t, format.JSONSchema)
+	assert.Equal(t, "person", format.JSONSchema.Name)
+	definition, ok := format.JSONSchema.Schema.(*jsonschema.Definition)
+	require.True(t, ok)
+	assert.Equal(t, []string{"name", "age"}, definition.Required)
+}
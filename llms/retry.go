@@ -0,0 +1,286 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultMaxRetries     = 3
+	_defaultInitialBackoff = 500 * time.Millisecond
+	_defaultMaxBackoff     = 30 * time.Second
+	_defaultBackoffFactor  = 2
+	_defaultJitter         = 0.2
+)
+
+// RetryPolicy configures how the retry wrappers retry failed calls.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. A Retry-After delay
+	// requested by the provider is honored even if it is longer.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each retry.
+	Multiplier float64
+	// Jitter is the fraction, between 0 and 1, of the delay that is
+	// randomized to avoid synchronized retries of concurrent clients.
+	Jitter float64
+	// IsRetryable reports whether an error is transient. Defaults to IsRetryableError.
+	IsRetryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy retrying 3 times with an exponential
+// backoff starting at 500ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     _defaultMaxRetries,
+		InitialBackoff: _defaultInitialBackoff,
+		MaxBackoff:     _defaultMaxBackoff,
+		Multiplier:     _defaultBackoffFactor,
+		Jitter:         _defaultJitter,
+	}
+}
+
+// nolint:gochecknoglobals
+var (
+	_retryableStatusCodes = map[int]bool{
+		http.StatusRequestTimeout:      true,
+		http.StatusConflict:            true,
+		http.StatusTooEarly:            true,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+		_statusOverloaded:              true,
+	}
+	_retryableErrorCodes = map[string]bool{
+		"rate_limit_exceeded": true,
+		"rate_limit_error":    true,
+		"overloaded_error":    true,
+		"server_error":        true,
+		"api_error":           true,
+		"timeout":             true,
+	}
+	// Errors with these codes are not transient, even with a retryable status code.
+	_permanentErrorCodes = map[string]bool{
+		"insufficient_quota":   true,
+		"billing_hard_limit":   true,
+		"invalid_api_key":      true,
+		"permission_error":     true,
+		"authentication_error": true,
+	}
+)
+
+// _statusOverloaded is returned by Anthropic when the API is overloaded.
+const _statusOverloaded = 529
+
+// IsRetryableError reports whether the error is transient: rate limits,
+// server errors, timeouts and dropped connections. Cancellation of the
+// context is never retried.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if _permanentErrorCodes[statusErr.Code] {
+			return false
+		}
+		return _retryableStatusCodes[statusErr.StatusCode] || _retryableErrorCodes[statusErr.Code]
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// RetryLLM is an LLM retrying transient failures of the wrapped LLM.
+type RetryLLM struct {
+	llm    LLM
+	policy RetryPolicy
+}
+
+var (
+	_ LLM           = (*RetryLLM)(nil)
+	_ LanguageModel = (*RetryLLM)(nil)
+)
+
+// WithRetries wraps the LLM to retry transient failures with exponential
+// backoff and jitter, honoring the Retry-After delay of rate limited
+// responses.
+func WithRetries(llm LLM, policy RetryPolicy) *RetryLLM {
+	return &RetryLLM{llm: llm, policy: policy}
+}
+
+// Call calls the wrapped LLM, retrying transient failures.
+func (r *RetryLLM) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	return withRetries(ctx, r.policy, options, func(options []CallOption) (string, error) {
+		return r.llm.Call(ctx, prompt, options...)
+	})
+}
+
+// Generate calls the wrapped LLM, retrying transient failures.
+func (r *RetryLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	return withRetries(ctx, r.policy, options, func(options []CallOption) ([]*Generation, error) {
+		return r.llm.Generate(ctx, prompts, options...)
+	})
+}
+
+func (r *RetryLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GeneratePrompt(ctx, r, promptValues, options...)
+}
+
+func (r *RetryLLM) GetNumTokens(text string) int {
+	return numTokens(r.llm, text)
+}
+
+// RetryChatLLM is a chat LLM retrying transient failures of the wrapped chat LLM.
+type RetryChatLLM struct {
+	chat   ChatLLM
+	policy RetryPolicy
+}
+
+var (
+	_ ChatLLM       = (*RetryChatLLM)(nil)
+	_ LanguageModel = (*RetryChatLLM)(nil)
+)
+
+// WithChatRetries wraps the chat LLM to retry transient failures with
+// exponential backoff and jitter, honoring the Retry-After delay of rate
+// limited responses.
+func WithChatRetries(chat ChatLLM, policy RetryPolicy) *RetryChatLLM {
+	return &RetryChatLLM{chat: chat, policy: policy}
+}
+
+// Call calls the wrapped chat LLM, retrying transient failures.
+func (r *RetryChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	return withRetries(ctx, r.policy, options, func(options []CallOption) (*schema.AIChatMessage, error) {
+		return r.chat.Call(ctx, messages, options...)
+	})
+}
+
+// Generate calls the wrapped chat LLM, retrying transient failures.
+func (r *RetryChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	return withRetries(ctx, r.policy, options, func(options []CallOption) ([]*Generation, error) {
+		return r.chat.Generate(ctx, messageSets, options...)
+	})
+}
+
+func (r *RetryChatLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, r, promptValues, options...)
+}
+
+func (r *RetryChatLLM) GetNumTokens(text string) int {
+	return numTokens(r.chat, text)
+}
+
+// CountTokens counts the input tokens of the messages with the wrapped chat
+// LLM, retrying transient failures of counting endpoints.
+func (r *RetryChatLLM) CountTokens(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (int, error) { //nolint:lll
+	return withRetries(ctx, r.policy, options, func(options []CallOption) (int, error) {
+		return CountChatTokens(ctx, r.chat, messages, options...)
+	})
+}
+
+// Retry calls fn, retrying its transient failures with the policy. It is
+// used to retry the calls of other components than LLMs, e.g. the requests of
+// the embedders.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	return withRetries(ctx, policy, nil, func([]CallOption) (T, error) { return fn() })
+}
+
+// withRetries calls fn until it succeeds, fails with an error that is not
+// retryable or the retries of the policy are exhausted. A streamed call is
+// not retried once chunks were sent to the streaming func, as they cannot
+// be taken back.
+func withRetries[T any](ctx context.Context, policy RetryPolicy, options []CallOption, fn func([]CallOption) (T, error)) (T, error) { //nolint:lll
+	isRetryable := policy.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+
+	options, streamed := trackStreaming(options)
+
+	for attempt := 0; ; attempt++ {
+		result, err := fn(options)
+		if err == nil || attempt >= policy.MaxRetries || *streamed || !isRetryable(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// trackStreaming wraps the streaming funcs of the options to report whether
+// chunks were streamed.
+func trackStreaming(options []CallOption) ([]CallOption, *bool) {
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	streamed := new(bool)
+	if streamingFunc := opts.StreamingFunc; streamingFunc != nil {
+		options = append(options, WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			*streamed = true
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+	if streamingChunkFunc := opts.StreamingChunkFunc; streamingChunkFunc != nil {
+		options = append(options, WithStreamingChunkFunc(func(ctx context.Context, chunk StreamChunk) error {
+			*streamed = true
+			return streamingChunkFunc(ctx, chunk)
+		}))
+	}
+	return options, streamed
+}
+
+// backoff returns the delay before the retry following the given attempt.
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * math.Min(p.Jitter, 1) * rand.Float64() //nolint:gosec
+	}
+	return time.Duration(delay)
+}
+
+// numTokens returns the number of tokens of the text using the model if it
+// is a LanguageModel, or an approximation otherwise.
+func numTokens(model any, text string) int {
+	if lm, ok := model.(LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return CountTokens("", text)
+}
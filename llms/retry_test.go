@@ -0,0 +1,183 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// flakyLLM fails with the given errors before succeeding.
+type flakyLLM struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyLLM) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	generations, err := f.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	return generations[0].Text, nil
+}
+
+func (f *flakyLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	f.calls++
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte("chunk")); err != nil {
+			return nil, err
+		}
+	}
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return []*Generation{{Text: "ok"}}, nil
+}
+
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	return policy
+}
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("bad request"), false},
+		{context.Canceled, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), false},
+		{&StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{&StatusError{StatusCode: http.StatusTooManyRequests, Code: "insufficient_quota"}, false},
+		{&StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&StatusError{StatusCode: 529, Code: "overloaded_error"}, true},
+		{&StatusError{StatusCode: http.StatusBadRequest}, false},
+		{&StatusError{StatusCode: http.StatusBadRequest, Code: "server_error"}, true},
+		{fmt.Errorf("send request: %w", io.ErrUnexpectedEOF), true},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, IsRetryableError(tc.err), "%v", tc.err)
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	t.Parallel()
+
+	llm := &flakyLLM{errs: []error{
+		&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond},
+		&StatusError{StatusCode: http.StatusBadGateway},
+	}}
+	text, err := WithRetries(llm, fastRetryPolicy()).Call(context.Background(), "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", text)
+	assert.Equal(t, 3, llm.calls)
+
+	permanent := &StatusError{StatusCode: http.StatusUnauthorized}
+	llm = &flakyLLM{errs: []error{permanent}}
+	_, err = WithRetries(llm, fastRetryPolicy()).Call(context.Background(), "hi")
+	require.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, llm.calls)
+
+	transient := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	llm = &flakyLLM{errs: []error{transient, transient, transient}}
+	policy := fastRetryPolicy()
+	policy.MaxRetries = 2
+	_, err = WithRetries(llm, policy).Call(context.Background(), "hi")
+	require.ErrorIs(t, err, transient)
+	assert.Equal(t, 3, llm.calls)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	n, err := Retry(context.Background(), fastRetryPolicy(), func() (int, error) {
+		calls++
+		if calls < 2 {
+			return 0, &StatusError{StatusCode: http.StatusTooManyRequests}
+		}
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+	assert.Equal(t, 2, calls)
+}
+
+func TestWithRetriesStreaming(t *testing.T) {
+	t.Parallel()
+
+	llm := &flakyLLM{errs: []error{&StatusError{StatusCode: http.StatusInternalServerError}}}
+	var chunks int
+	_, err := WithRetries(llm, fastRetryPolicy()).Generate(context.Background(), []string{"hi"},
+		WithStreamingFunc(func(context.Context, []byte) error {
+			chunks++
+			return nil
+		}))
+	require.Error(t, err)
+	assert.Equal(t, 1, llm.calls)
+	assert.Equal(t, 1, chunks)
+}
+
+func TestWithRetriesContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	llm := &flakyLLM{errs: []error{&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}}}
+	_, err := WithRetries(llm, DefaultRetryPolicy()).Call(ctx, "hi")
+	require.Error(t, err)
+	assert.Equal(t, 1, llm.calls)
+}
+
+func TestWithChatRetries(t *testing.T) {
+	t.Parallel()
+
+	chat := &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "ok"}}}
+	calls := 0
+	policy := fastRetryPolicy()
+	policy.IsRetryable = func(err error) bool {
+		calls++
+		return false
+	}
+	msg, err := WithChatRetries(chat, policy).Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "hi"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", msg.Content)
+	assert.Equal(t, 0, calls)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	err := errors.New("transient")
+	assert.Equal(t, time.Second, policy.backoff(0, err))
+	assert.Equal(t, 4*time.Second, policy.backoff(2, err))
+	assert.Equal(t, 5*time.Second, policy.backoff(5, err))
+	assert.Equal(t, time.Minute, policy.backoff(0, &StatusError{RetryAfter: time.Minute}))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := policy.backoff(0, err)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
@@ -0,0 +1,351 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultEjectionFailures = 3
+	_defaultEjectionCooldown = 30 * time.Second
+)
+
+// RouterEndpointKey is the key of the generation info holding the name of
+// the endpoint that served a request of a router.
+const RouterEndpointKey = "RouterEndpoint"
+
+// ErrNoEndpoints is returned when a router has no endpoints.
+var ErrNoEndpoints = errors.New("router has no endpoints")
+
+// RoutingStrategy selects the endpoint a router sends a request to.
+type RoutingStrategy int
+
+const (
+	// RoundRobin sends requests to the endpoints in turn.
+	RoundRobin RoutingStrategy = iota
+	// LeastInFlight sends requests to the endpoint with the fewest requests in flight.
+	LeastInFlight
+	// Weighted sends requests to the endpoints in proportion to their weights.
+	Weighted
+)
+
+// RouterEndpoint is an endpoint of a router: a model with its own API key,
+// base URL or deployment.
+type RouterEndpoint[M any] struct {
+	// Name identifies the endpoint in the generation info and the health report.
+	Name  string
+	Model M
+	// Weight is the share of requests of the endpoint with the Weighted
+	// strategy. Defaults to 1.
+	Weight int
+}
+
+// EndpointHealth is the health of an endpoint of a router.
+type EndpointHealth struct {
+	Name     string
+	InFlight int
+	// Failures is the number of consecutive failures of the endpoint.
+	Failures int
+	// EjectedUntil is the time until the endpoint receives no requests, zero
+	// if the endpoint is healthy.
+	EjectedUntil time.Time
+}
+
+// RouterOption is an option of a router.
+type RouterOption func(*routerOptions)
+
+type routerOptions struct {
+	strategy         RoutingStrategy
+	ejectionFailures int
+	ejectionCooldown time.Duration
+	isFailure        func(error) bool
+}
+
+// WithRoutingStrategy sets the strategy selecting the endpoint of a request. Defaults to RoundRobin.
+func WithRoutingStrategy(strategy RoutingStrategy) RouterOption {
+	return func(o *routerOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithEjection ejects an endpoint for the cooldown after the given number of
+// consecutive failures. Defaults to 3 failures and 30 seconds.
+func WithEjection(failures int, cooldown time.Duration) RouterOption {
+	return func(o *routerOptions) {
+		o.ejectionFailures = failures
+		o.ejectionCooldown = cooldown
+	}
+}
+
+// WithEndpointFailure sets the function reporting whether an error is a
+// failure of the endpoint, counting towards its ejection and sending the
+// request to another endpoint. Defaults to IsRetryableError.
+func WithEndpointFailure(isFailure func(err error) bool) RouterOption {
+	return func(o *routerOptions) {
+		o.isFailure = isFailure
+	}
+}
+
+// RouterLLM is an LLM distributing requests across endpoints.
+type RouterLLM struct {
+	router *router[LLM]
+}
+
+var (
+	_ LLM           = (*RouterLLM)(nil)
+	_ LanguageModel = (*RouterLLM)(nil)
+)
+
+// NewRouter returns an LLM distributing requests across the endpoints. A
+// request failing on an endpoint is sent to the next endpoint, and endpoints
+// failing repeatedly are ejected temporarily. If all endpoints are ejected,
+// requests are sent to the ejected endpoints.
+func NewRouter(endpoints []RouterEndpoint[LLM], opts ...RouterOption) *RouterLLM {
+	return &RouterLLM{router: newRouter(endpoints, opts)}
+}
+
+// Call calls the LLM of an endpoint selected by the routing strategy.
+func (r *RouterLLM) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	text, _, err := route(ctx, r.router, options, func(llm LLM, options []CallOption) (string, error) {
+		return llm.Call(ctx, prompt, options...)
+	})
+	return text, err
+}
+
+// Generate calls the LLM of an endpoint selected by the routing strategy.
+func (r *RouterLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	generations, name, err := route(ctx, r.router, options, func(llm LLM, options []CallOption) ([]*Generation, error) {
+		return llm.Generate(ctx, prompts, options...)
+	})
+	recordGenerationInfo(generations, RouterEndpointKey, name)
+	return generations, err
+}
+
+func (r *RouterLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GeneratePrompt(ctx, r, promptValues, options...)
+}
+
+func (r *RouterLLM) GetNumTokens(text string) int {
+	return numTokens(r.router.first(), text)
+}
+
+// Health returns the health of the endpoints.
+func (r *RouterLLM) Health() []EndpointHealth {
+	return r.router.health()
+}
+
+// RouterChatLLM is a chat LLM distributing requests across endpoints.
+type RouterChatLLM struct {
+	router *router[ChatLLM]
+}
+
+var (
+	_ ChatLLM       = (*RouterChatLLM)(nil)
+	_ LanguageModel = (*RouterChatLLM)(nil)
+)
+
+// NewChatRouter returns a chat LLM distributing requests across the
+// endpoints. A request failing on an endpoint is sent to the next endpoint,
+// and endpoints failing repeatedly are ejected temporarily. If all endpoints
+// are ejected, requests are sent to the ejected endpoints.
+func NewChatRouter(endpoints []RouterEndpoint[ChatLLM], opts ...RouterOption) *RouterChatLLM {
+	return &RouterChatLLM{router: newRouter(endpoints, opts)}
+}
+
+// Call calls the chat LLM of an endpoint selected by the routing strategy.
+func (r *RouterChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	msg, _, err := route(ctx, r.router, options, func(chat ChatLLM, options []CallOption) (*schema.AIChatMessage, error) {
+		return chat.Call(ctx, messages, options...)
+	})
+	return msg, err
+}
+
+// Generate calls the chat LLM of an endpoint selected by the routing strategy.
+func (r *RouterChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	generations, name, err := route(ctx, r.router, options, func(chat ChatLLM, options []CallOption) ([]*Generation, error) {
+		return chat.Generate(ctx, messageSets, options...)
+	})
+	recordGenerationInfo(generations, RouterEndpointKey, name)
+	return generations, err
+}
+
+func (r *RouterChatLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, r, promptValues, options...)
+}
+
+func (r *RouterChatLLM) GetNumTokens(text string) int {
+	return numTokens(r.router.first(), text)
+}
+
+// Health returns the health of the endpoints.
+func (r *RouterChatLLM) Health() []EndpointHealth {
+	return r.router.health()
+}
+
+type endpointState[M any] struct {
+	RouterEndpoint[M]
+	inFlight      int
+	failures      int
+	ejectedUntil  time.Time
+	currentWeight int
+}
+
+// router holds the endpoints and their health.
+type router[M any] struct {
+	opts routerOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	endpoints []*endpointState[M]
+	next      int
+}
+
+func newRouter[M any](endpoints []RouterEndpoint[M], opts []RouterOption) *router[M] {
+	r := &router[M]{
+		opts: routerOptions{
+			ejectionFailures: _defaultEjectionFailures,
+			ejectionCooldown: _defaultEjectionCooldown,
+			isFailure:        IsRetryableError,
+		},
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Weight <= 0 {
+			endpoint.Weight = 1
+		}
+		r.endpoints = append(r.endpoints, &endpointState[M]{RouterEndpoint: endpoint})
+	}
+	return r
+}
+
+func (r *router[M]) first() any {
+	if len(r.endpoints) == 0 {
+		return nil
+	}
+	return r.endpoints[0].Model
+}
+
+// route calls fn with the model of an endpoint selected by the strategy
+// until a call succeeds, fails with an error that is not an endpoint failure
+// or all endpoints were tried. It returns the name of the endpoint of the
+// last call. A streamed call is not sent to another endpoint once chunks were
+// streamed.
+func route[M, T any](ctx context.Context, r *router[M], options []CallOption, fn func(M, []CallOption) (T, error)) (T, string, error) { //nolint:lll
+	var result T
+	if len(r.endpoints) == 0 {
+		return result, "", ErrNoEndpoints
+	}
+	options, streamed := trackStreaming(options)
+
+	tried := make(map[*endpointState[M]]bool, len(r.endpoints))
+	for {
+		endpoint := r.acquire(tried)
+		tried[endpoint] = true
+
+		var err error
+		result, err = fn(endpoint.Model, options)
+		failed := err != nil && r.opts.isFailure(err)
+		r.release(endpoint, failed)
+		if !failed || *streamed || ctx.Err() != nil || len(tried) == len(r.endpoints) {
+			return result, endpoint.Name, err
+		}
+	}
+}
+
+// acquire selects an endpoint that was not tried yet and counts the request
+// as in flight. Ejected endpoints are only selected if all untried endpoints
+// are ejected.
+func (r *router[M]) acquire(tried map[*endpointState[M]]bool) *endpointState[M] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	candidates := make([]*endpointState[M], 0, len(r.endpoints))
+	ejected := make([]*endpointState[M], 0)
+	for _, endpoint := range r.endpoints {
+		switch {
+		case tried[endpoint]:
+		case now.Before(endpoint.ejectedUntil):
+			ejected = append(ejected, endpoint)
+		default:
+			candidates = append(candidates, endpoint)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = ejected
+	}
+
+	var selected *endpointState[M]
+	switch r.opts.strategy {
+	case LeastInFlight:
+		for _, endpoint := range r.rotate(candidates) {
+			if selected == nil || endpoint.inFlight < selected.inFlight {
+				selected = endpoint
+			}
+		}
+		r.next++
+	case Weighted:
+		// Smooth weighted round robin spreads the requests of an endpoint
+		// evenly instead of sending them in bursts.
+		total := 0
+		for _, endpoint := range candidates {
+			endpoint.currentWeight += endpoint.Weight
+			total += endpoint.Weight
+			if selected == nil || endpoint.currentWeight > selected.currentWeight {
+				selected = endpoint
+			}
+		}
+		selected.currentWeight -= total
+	default:
+		selected = r.rotate(candidates)[0]
+		r.next++
+	}
+	selected.inFlight++
+	return selected
+}
+
+// rotate returns the candidates starting at the round robin position.
+func (r *router[M]) rotate(candidates []*endpointState[M]) []*endpointState[M] {
+	start := r.next % len(candidates)
+	return append(candidates[start:len(candidates):len(candidates)], candidates[:start]...)
+}
+
+// release ends the request of the endpoint and updates its health.
+func (r *router[M]) release(endpoint *endpointState[M], failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint.inFlight--
+	if !failed {
+		endpoint.failures = 0
+		return
+	}
+	endpoint.failures++
+	if r.opts.ejectionFailures > 0 && endpoint.failures >= r.opts.ejectionFailures {
+		endpoint.ejectedUntil = r.now().Add(r.opts.ejectionCooldown)
+		endpoint.failures = 0
+	}
+}
+
+func (r *router[M]) health() []EndpointHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	health := make([]EndpointHealth, 0, len(r.endpoints))
+	for _, endpoint := range r.endpoints {
+		h := EndpointHealth{Name: endpoint.Name, InFlight: endpoint.inFlight, Failures: endpoint.failures}
+		if now.Before(endpoint.ejectedUntil) {
+			h.EjectedUntil = endpoint.ejectedUntil
+		}
+		health = append(health, h)
+	}
+	return health
+}
@@ -0,0 +1,120 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingLLM blocks calls until released.
+type blockingLLM struct {
+	flakyLLM
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLLM) Generate(ctx context.Context, prompts []string, options ...CallOption) ([]*Generation, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.flakyLLM.Generate(ctx, prompts, options...)
+}
+
+func servedBy(t *testing.T, llm LLM, calls int) []any {
+	t.Helper()
+
+	names := make([]any, 0, calls)
+	for i := 0; i < calls; i++ {
+		generations, err := llm.Generate(context.Background(), []string{"hi"})
+		require.NoError(t, err)
+		names = append(names, generations[0].GenerationInfo[RouterEndpointKey])
+	}
+	return names
+}
+
+func TestRouterRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	router := NewRouter([]RouterEndpoint[LLM]{
+		{Name: "a", Model: &flakyLLM{}},
+		{Name: "b", Model: &flakyLLM{}},
+		{Name: "c", Model: &flakyLLM{}},
+	})
+	assert.Equal(t, []any{"a", "b", "c", "a", "b", "c"}, servedBy(t, router, 6))
+
+	_, err := NewRouter(nil).Call(context.Background(), "hi")
+	assert.ErrorIs(t, err, ErrNoEndpoints)
+}
+
+func TestRouterWeighted(t *testing.T) {
+	t.Parallel()
+
+	router := NewRouter([]RouterEndpoint[LLM]{
+		{Name: "a", Model: &flakyLLM{}, Weight: 2},
+		{Name: "b", Model: &flakyLLM{}},
+	}, WithRoutingStrategy(Weighted))
+	assert.Equal(t, []any{"a", "b", "a", "a", "b", "a"}, servedBy(t, router, 6))
+}
+
+func TestRouterLeastInFlight(t *testing.T) {
+	t.Parallel()
+
+	busy := &blockingLLM{started: make(chan struct{}), release: make(chan struct{})}
+	router := NewRouter([]RouterEndpoint[LLM]{
+		{Name: "busy", Model: busy},
+		{Name: "idle", Model: &flakyLLM{}},
+	}, WithRoutingStrategy(LeastInFlight))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := router.Generate(context.Background(), []string{"hi"})
+		assert.NoError(t, err)
+	}()
+	<-busy.started
+
+	assert.Equal(t, []any{"idle", "idle", "idle"}, servedBy(t, router, 3))
+	assert.Equal(t, 1, router.Health()[0].InFlight)
+	close(busy.release)
+	<-done
+	assert.Equal(t, 0, router.Health()[0].InFlight)
+}
+
+func TestRouterEjection(t *testing.T) {
+	t.Parallel()
+
+	rateLimited := &StatusError{StatusCode: http.StatusTooManyRequests}
+	failing := &flakyLLM{errs: []error{rateLimited, rateLimited, rateLimited}}
+	healthy := &flakyLLM{}
+	router := NewRouter([]RouterEndpoint[LLM]{
+		{Name: "failing", Model: failing},
+		{Name: "healthy", Model: healthy},
+	}, WithEjection(2, time.Minute))
+	now := time.Now()
+	router.router.now = func() time.Time { return now }
+
+	// Failed requests are sent to the next endpoint.
+	assert.Equal(t, []any{"healthy"}, servedBy(t, router, 1))
+	assert.Equal(t, 1, failing.calls)
+	assert.Equal(t, 1, router.Health()[0].Failures)
+	// The second failure ejects the endpoint.
+	assert.Equal(t, []any{"healthy", "healthy", "healthy"}, servedBy(t, router, 3))
+	assert.Equal(t, 2, failing.calls)
+	assert.Equal(t, now.Add(time.Minute), router.Health()[0].EjectedUntil)
+
+	// The endpoint receives requests again after the cooldown.
+	failing.errs = nil
+	now = now.Add(time.Minute)
+	assert.Contains(t, servedBy(t, router, 2), "failing")
+	assert.True(t, router.Health()[0].EjectedUntil.IsZero())
+
+	// Errors that are not endpoint failures are returned.
+	invalid := &StatusError{StatusCode: http.StatusBadRequest}
+	healthy.errs = []error{invalid}
+	failing.errs = []error{invalid}
+	_, err := router.Call(context.Background(), "hi")
+	assert.ErrorIs(t, err, invalid)
+}
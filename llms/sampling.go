@@ -0,0 +1,60 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCallOption is returned when a call option is out of the limits of
+// the provider.
+var ErrInvalidCallOption = errors.New("invalid call option")
+
+// SamplingLimits are the limits of a provider on the common sampling
+// options, used by the providers to validate the call options before
+// sending a request.
+type SamplingLimits struct {
+	// MaxStopWords is the max number of stop words, unlimited if zero.
+	MaxStopWords int
+	// MinPenalty and MaxPenalty bound the frequency and the presence
+	// penalties, unbounded if both are zero.
+	MinPenalty float64
+	MaxPenalty float64
+	// MaxLogitBias bounds the absolute value of the logit biases. Logit
+	// biases are not supported if zero.
+	MaxLogitBias int
+}
+
+// Validate returns an error wrapping ErrInvalidCallOption for each option out
+// of the limits.
+func (l SamplingLimits) Validate(opts CallOptions) error {
+	var errs []error
+	if l.MaxStopWords > 0 && len(opts.StopWords) > l.MaxStopWords {
+		errs = append(errs, fmt.Errorf("%w: %d stop words, at most %d supported",
+			ErrInvalidCallOption, len(opts.StopWords), l.MaxStopWords))
+	}
+	if l.MinPenalty != 0 || l.MaxPenalty != 0 {
+		penalties := []struct {
+			name  string
+			value float64
+		}{
+			{"frequency penalty", opts.FrequencyPenalty},
+			{"presence penalty", opts.PresencePenalty},
+		}
+		for _, p := range penalties {
+			if p.value < l.MinPenalty || p.value > l.MaxPenalty {
+				errs = append(errs, fmt.Errorf("%w: %s %v not between %v and %v",
+					ErrInvalidCallOption, p.name, p.value, l.MinPenalty, l.MaxPenalty))
+			}
+		}
+	}
+	if len(opts.LogitBias) > 0 && l.MaxLogitBias == 0 {
+		errs = append(errs, fmt.Errorf("%w: logit bias not supported", ErrInvalidCallOption))
+	}
+	for token, bias := range opts.LogitBias {
+		if l.MaxLogitBias > 0 && (bias < -l.MaxLogitBias || bias > l.MaxLogitBias) {
+			errs = append(errs, fmt.Errorf("%w: logit bias %d of token %s not between %d and %d",
+				ErrInvalidCallOption, bias, token, -l.MaxLogitBias, l.MaxLogitBias))
+		}
+	}
+	return errors.Join(errs...)
+}
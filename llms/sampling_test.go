@@ -0,0 +1,40 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := SamplingLimits{MaxStopWords: 2, MinPenalty: -2, MaxPenalty: 2, MaxLogitBias: 100}
+	opts := CallOptions{}
+	for _, opt := range []CallOption{
+		WithStopWords([]string{"\n", "END"}),
+		WithFrequencyPenalty(1.5),
+		WithPresencePenalty(-2),
+		WithLogitBias(map[string]int{"50256": -100}),
+	} {
+		opt(&opts)
+	}
+	require.NoError(t, limits.Validate(opts))
+
+	opts.StopWords = append(opts.StopWords, "STOP")
+	opts.PresencePenalty = 2.5
+	opts.LogitBias["1234"] = 101
+	err := limits.Validate(opts)
+	require.ErrorIs(t, err, ErrInvalidCallOption)
+	assert.Contains(t, err.Error(), "3 stop words")
+	assert.Contains(t, err.Error(), "presence penalty 2.5")
+	assert.Contains(t, err.Error(), "token 1234")
+	assert.NotContains(t, err.Error(), "frequency penalty")
+
+	// Without bounds only the logit biases are rejected.
+	err = SamplingLimits{}.Validate(opts)
+	require.ErrorIs(t, err, ErrInvalidCallOption)
+	assert.Contains(t, err.Error(), "logit bias not supported")
+	require.NoError(t, SamplingLimits{}.Validate(CallOptions{StopWords: opts.StopWords, PresencePenalty: 5}))
+}
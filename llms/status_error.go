@@ -0,0 +1,72 @@
+package llms
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusError is returned by the providers when the API responds with an
+// error status code. It lets callers, such as the retry wrappers, classify
+// errors without inspecting provider specific responses.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response, zero for the
+	// errors sent as events of a streaming response.
+	StatusCode int
+	// Code is the provider specific error code or type, e.g.
+	// "rate_limit_exceeded" or "overloaded_error".
+	Code string
+	// Message is the error message of the response.
+	Message string
+	// RetryAfter is the delay requested by the Retry-After header, zero if
+	// the header is not set.
+	RetryAfter time.Duration
+}
+
+// NewStatusError returns a StatusError for the response. The retry delay is
+// read from its headers.
+func NewStatusError(r *http.Response, code, message string) *StatusError {
+	return &StatusError{
+		StatusCode: r.StatusCode,
+		Code:       code,
+		Message:    message,
+		RetryAfter: RetryAfter(r.Header),
+	}
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", e.StatusCode)
+	if e.StatusCode == 0 {
+		msg = "API returned an error"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// RetryAfter returns the delay requested by the retry-after-ms or the
+// Retry-After header, which holds either a number of seconds or a date.
+// It returns zero if neither header is set.
+func RetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
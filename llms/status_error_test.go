@@ -0,0 +1,48 @@
+package llms
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second},
+		{"milliseconds", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"150"}}, 150 * time.Millisecond},
+		{"negative", http.Header{"Retry-After": {"-1"}}, 0},
+		{"past date", http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}}, 0},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, RetryAfter(tc.header))
+		})
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	d := RetryAfter(http.Header{"Retry-After": {date}})
+	assert.Greater(t, d, 50*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
+
+func TestStatusError(t *testing.T) {
+	t.Parallel()
+
+	r := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}}
+	err := NewStatusError(r, "rate_limit_exceeded", "slow down")
+	assert.Equal(t, 3*time.Second, err.RetryAfter)
+	assert.Equal(t, "API returned unexpected status code: 429: slow down", err.Error())
+	assert.Equal(t, "API returned unexpected status code: 500", (&StatusError{StatusCode: 500}).Error())
+}
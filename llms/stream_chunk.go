@@ -0,0 +1,48 @@
+package llms
+
+import "context"
+
+// StreamChunk is a chunk of a streaming response, delivered to the function
+// set with WithStreamingChunkFunc.
+type StreamChunk struct {
+	// Content is the generated text of the chunk.
+	Content string `json:"content,omitempty"`
+	// Reasoning is the reasoning text of the chunk, for providers that stream
+	// the reasoning of the model.
+	Reasoning string `json:"reasoning,omitempty"`
+	// ToolCalls are the partial tool calls of the chunk.
+	ToolCalls []ToolCallChunk `json:"tool_calls,omitempty"`
+	// FinishReason is set on the last chunk of a choice, e.g. "stop" or "tool_calls".
+	FinishReason string `json:"finish_reason,omitempty"`
+}
+
+// ToolCallChunk is a part of a tool call. The ID and name are sent with the
+// first chunk of a tool call; the arguments are the next fragment of the
+// json arguments, to be concatenated over the chunks of the same index.
+type ToolCallChunk struct {
+	// Index is the index of the tool call in the response.
+	Index     int    `json:"index"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// IsEmpty reports whether the chunk carries no data.
+func (c StreamChunk) IsEmpty() bool {
+	return c.Content == "" && c.Reasoning == "" && len(c.ToolCalls) == 0 && c.FinishReason == ""
+}
+
+// SendStreamChunk passes a chunk of a streaming response to the streaming
+// funcs of the options: its content to the StreamingFunc and the chunk, if it
+// is not empty, to the StreamingChunkFunc.
+func SendStreamChunk(ctx context.Context, opts CallOptions, chunk StreamChunk) error {
+	if opts.StreamingFunc != nil && chunk.Content != "" {
+		if err := opts.StreamingFunc(ctx, []byte(chunk.Content)); err != nil {
+			return err
+		}
+	}
+	if opts.StreamingChunkFunc != nil && !chunk.IsEmpty() {
+		return opts.StreamingChunkFunc(ctx, chunk)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrStreamInterrupted is returned when a streaming response ends before its
+// completion: the context is canceled, the connection is lost or the provider
+// sends an error event in the stream.
+var ErrStreamInterrupted = errors.New("stream interrupted")
+
+// StreamError is returned by the providers when a streaming response is
+// interrupted. It holds the output streamed before the interruption:
+//
+//	_, err := llm.Call(ctx, prompt, llms.WithStreamingFunc(fn))
+//	var streamErr *llms.StreamError
+//	if errors.As(err, &streamErr) {
+//		log.Printf("interrupted after %q: %v", streamErr.Partial, streamErr.Err)
+//	}
+//
+// It matches ErrStreamInterrupted and its cause with errors.Is, e.g.
+// context.Canceled, io.ErrUnexpectedEOF or a *StatusError for the error
+// events of the providers.
+type StreamError struct {
+	// Partial is the text streamed before the interruption.
+	Partial string
+	// Err is the cause of the interruption.
+	Err error
+}
+
+// NewStreamError returns a StreamError with the partial output and the cause
+// of the interruption. If the context is done, its error is the cause.
+func NewStreamError(ctx context.Context, partial string, err error) *StreamError {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	return &StreamError{Partial: partial, Err: err}
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrStreamInterrupted, e.Err)
+}
+
+// Unwrap returns ErrStreamInterrupted and the cause of the interruption.
+func (e *StreamError) Unwrap() []error {
+	return []error{ErrStreamInterrupted, e.Err}
+}
+
+// CloseOnCancel closes the body of a streaming response as soon as the
+// context is done, so that a blocked read of the stream returns immediately
+// even if the HTTP client does not watch the context. The returned func stops
+// watching the context and must be called once the stream is read.
+func CloseOnCancel(ctx context.Context, body io.Closer) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case <-done:
+			default:
+				body.Close()
+			}
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,64 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamError(t *testing.T) {
+	t.Parallel()
+
+	err := error(NewStreamError(context.Background(), "Hel", &StatusError{Code: "overloaded_error", Message: "Overloaded"}))
+	require.ErrorIs(t, err, ErrStreamInterrupted)
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "stream interrupted: API returned an error: Overloaded", err.Error())
+	assert.True(t, IsRetryableError(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = NewStreamError(ctx, "Hel", io.ErrUnexpectedEOF)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.False(t, IsRetryableError(err))
+}
+
+type closer chan struct{}
+
+func (c closer) Close() error {
+	close(c)
+	return nil
+}
+
+func TestCloseOnCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	body := make(closer)
+	stop := CloseOnCancel(ctx, body)
+	defer stop()
+	cancel()
+	select {
+	case <-body:
+	case <-time.After(time.Second):
+		t.Fatal("body not closed")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	body = make(closer)
+	stop = CloseOnCancel(ctx, body)
+	stop()
+	stop()
+	cancel()
+	select {
+	case <-body:
+		t.Fatal("body closed after stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,131 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms/jsonschema"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_structuredToolName        = "respond"
+	_defaultStructuredRetries  = 2
+	_structuredToolDescription = "Respond to the user with the requested data."
+)
+
+// ErrStructuredOutput is returned when the model did not return data matching
+// the schema of the target type after all retries.
+var ErrStructuredOutput = errors.New("model did not return valid structured output")
+
+// GenerateStructured asks the model to respond to the prompt with data
+// matching the JSON schema of T, which is typically a struct, and returns the
+// decoded value. See jsonschema.Reflect for the struct tags used to describe
+// the fields.
+//
+// The output is constrained by forcing the model to call a tool whose
+// parameters are the schema of T. Models without tool calling are asked to
+// respond with a JSON object instead. If the output can not be decoded or does
+// not match the schema, the error is sent back to the model and the request
+// is retried, see WithStructuredRetries.
+func GenerateStructured[T any](ctx context.Context, model ChatLLM, prompt string, options ...CallOption) (T, error) {
+	var result T
+
+	opts := CallOptions{StructuredRetries: _defaultStructuredRetries}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	definition, err := jsonschema.Reflect(result)
+	if err != nil {
+		return result, err
+	}
+	schemaJSON, err := json.Marshal(definition)
+	if err != nil {
+		return result, err
+	}
+
+	options = append(options,
+		WithTools([]Tool{{
+			Type: "function",
+			Function: &FunctionDefinition{
+				Name:        _structuredToolName,
+				Description: _structuredToolDescription,
+				Parameters:  definition,
+			},
+		}}),
+		WithToolChoice(ToolChoiceFunction(_structuredToolName)),
+	)
+
+	messages := []schema.ChatMessage{
+		schema.HumanChatMessage{Content: fmt.Sprintf(
+			"%s\n\nRespond with a JSON object matching this JSON schema:\n%s", prompt, schemaJSON,
+		)},
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= opts.StructuredRetries; attempt++ {
+		msg, err := model.Call(ctx, messages, options...)
+		if err != nil {
+			return result, err
+		}
+
+		data := structuredOutput(msg)
+		lastErr = definition.Validate(data)
+		if lastErr == nil {
+			var value T
+			if lastErr = json.Unmarshal(data, &value); lastErr == nil {
+				return value, nil
+			}
+		}
+
+		messages = append(messages,
+			schema.AIChatMessage{Content: string(data)},
+			schema.HumanChatMessage{Content: fmt.Sprintf(
+				"The response is invalid: %s. Respond again with a JSON object matching the schema.", lastErr,
+			)},
+		)
+	}
+
+	return result, fmt.Errorf("%w: %s", ErrStructuredOutput, lastErr)
+}
+
+// structuredOutput returns the arguments of the tool call of the message, or
+// the JSON in the content if the model did not call the tool.
+func structuredOutput(msg *schema.AIChatMessage) []byte {
+	for _, tc := range msg.ToolCalls {
+		if tc.FunctionCall != nil && tc.FunctionCall.Name == _structuredToolName {
+			return argumentsJSON(tc.FunctionCall.Arguments)
+		}
+	}
+	if msg.FunctionCall != nil && msg.FunctionCall.Name == _structuredToolName {
+		return argumentsJSON(msg.FunctionCall.Arguments)
+	}
+
+	content := strings.TrimSpace(msg.Content)
+	// Strip markdown code fences.
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		content = content[start : end+1]
+	}
+	return []byte(strings.TrimSpace(content))
+}
+
+func argumentsJSON(arguments any) []byte {
+	switch arguments := arguments.(type) {
+	case string:
+		return []byte(arguments)
+	case []byte:
+		return arguments
+	default:
+		b, _ := json.Marshal(arguments)
+		return b
+	}
+}
@@ -0,0 +1,83 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type fakeChatLLM struct {
+	responses []*schema.AIChatMessage
+	calls     [][]schema.ChatMessage
+	options   []CallOptions
+}
+
+func (f *fakeChatLLM) Call(_ context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	f.calls = append(f.calls, messages)
+	f.options = append(f.options, opts)
+	msg := f.responses[0]
+	f.responses = f.responses[1:]
+	return msg, nil
+}
+
+func (f *fakeChatLLM) Generate(context.Context, [][]schema.ChatMessage, ...CallOption) ([]*Generation, error) {
+	return nil, nil
+}
+
+type person struct {
+	Name string `json:"name" description:"The full name"`
+	Age  int    `json:"age"`
+}
+
+func TestGenerateStructured(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{responses: []*schema.AIChatMessage{
+		{ToolCalls: []schema.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "respond", Arguments: `{"name":"Ada Lovelace","age":36}`},
+		}}},
+	}}
+
+	p, err := GenerateStructured[person](context.Background(), model, "Who wrote the first program?")
+	require.NoError(t, err)
+	assert.Equal(t, person{Name: "Ada Lovelace", Age: 36}, p)
+
+	require.Len(t, model.options, 1)
+	require.Len(t, model.options[0].Tools, 1)
+	assert.Equal(t, "respond", model.options[0].Tools[0].Function.Name)
+	assert.Equal(t, ToolChoiceFunction("respond"), model.options[0].ToolChoice)
+}
+
+func TestGenerateStructuredRetry(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{responses: []*schema.AIChatMessage{
+		{Content: `{"name": "Ada Lovelace"}`},
+		{Content: "```json\n{\"name\": \"Ada Lovelace\", \"age\": 36}\n```"},
+	}}
+
+	p, err := GenerateStructured[person](context.Background(), model, "Who wrote the first program?")
+	require.NoError(t, err)
+	assert.Equal(t, person{Name: "Ada Lovelace", Age: 36}, p)
+
+	require.Len(t, model.calls, 2)
+	require.Len(t, model.calls[1], 3)
+	assert.Contains(t, model.calls[1][2].GetContent(), "$.age is required")
+
+	model = &fakeChatLLM{responses: []*schema.AIChatMessage{
+		{Content: "no"},
+		{Content: "still no"},
+	}}
+	_, err = GenerateStructured[person](context.Background(), model, "Who?", WithStructuredRetries(1))
+	require.ErrorIs(t, err, ErrStructuredOutput)
+	assert.Len(t, model.calls, 2)
+}
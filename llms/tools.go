@@ -0,0 +1,30 @@
+package llms
+
+import "github.com/tmc/langchaingo/llms/jsonschema"
+
+// NewFunctionTool returns a function tool whose parameters are described by
+// the JSON schema of params, typically a struct. See jsonschema.Reflect for
+// the supported struct tags.
+func NewFunctionTool(name, description string, params any) (Tool, error) {
+	parameters, err := jsonschema.Reflect(params)
+	if err != nil {
+		return Tool{}, err
+	}
+	return Tool{
+		Type: "function",
+		Function: &FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}, nil
+}
+
+// ToolChoiceFunction returns a tool choice forcing the model to call the
+// function with the given name.
+func ToolChoiceFunction(name string) ToolChoice {
+	return ToolChoice{
+		Type:     "function",
+		Function: &FunctionReference{Name: name},
+	}
+}
@@ -0,0 +1,266 @@
+package llms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestIDHeader     = "X-Request-Id"
+	defaultRetryBackoff        = 500 * time.Millisecond
+	defaultMaxRecordedBodySize = 64 << 10
+)
+
+// HTTPTrace is the trace of a HTTP request sent through an
+// InstrumentedTransport.
+type HTTPTrace struct {
+	// RequestID is the id set on the request, in the request id header.
+	RequestID string
+	// ProviderRequestID is the id of the request reported by the provider in
+	// the response headers, if any.
+	ProviderRequestID string
+	Method            string
+	// URL is the URL of the request without the query, which may hold keys.
+	URL        string
+	StatusCode int
+	// Attempts is the number of attempts, more than one if retried.
+	Attempts int
+	// Latency is the time until the headers of the response, including the
+	// retries.
+	Latency time.Duration
+	// Duration is the time until the body of the response is closed, e.g.
+	// the end of a stream.
+	Duration time.Duration
+	// RequestBody and ResponseBody are the bodies of the request and the
+	// response, truncated, when recorded.
+	RequestBody  []byte
+	ResponseBody []byte
+	// Err is the error of the request, if any.
+	Err error
+}
+
+// InstrumentedTransport is a http.RoundTripper tracing the requests to the
+// providers and retrying the failed ones. It is used with the WithHTTPClient
+// options of the providers:
+//
+//	transport := &llms.InstrumentedTransport{
+//		MaxRetries: 2,
+//		OnTrace: func(trace llms.HTTPTrace) {
+//			log.Printf("%s %s: %d in %s", trace.Method, trace.URL, trace.StatusCode, trace.Latency)
+//		},
+//	}
+//	llm, err := openai.New(openai.WithHTTPClient(transport.Client()))
+type InstrumentedTransport struct {
+	// Base is the transport sending the requests, http.DefaultTransport if
+	// nil.
+	Base http.RoundTripper
+	// MaxRetries is the number of retries of the requests failing with a
+	// network error or a rate limit, timeout or server error status. The
+	// requests are retried regardless of their method.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// retry, 500ms if zero. The Retry-After header of the response takes
+	// precedence.
+	RetryBackoff time.Duration
+	// RequestIDHeader is the header of the request ids, X-Request-Id if
+	// empty. An id is generated for the requests without the header.
+	RequestIDHeader string
+	// RecordBodies records the bodies of the requests and the responses in
+	// the traces, up to MaxRecordedBodySize bytes, 64KiB if zero. The bodies
+	// may hold sensitive data.
+	RecordBodies        bool
+	MaxRecordedBodySize int
+	// OnTrace is called with the trace of each request, once the body of
+	// the response is closed or the request failed.
+	OnTrace func(HTTPTrace)
+}
+
+var _ http.RoundTripper = (*InstrumentedTransport)(nil)
+
+// Client returns a HTTP client using the transport.
+func (t *InstrumentedTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip sends the request, retrying it on failure.
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	req = req.Clone(req.Context())
+	header := t.RequestIDHeader
+	if header == "" {
+		header = defaultRequestIDHeader
+	}
+	if req.Header.Get(header) == "" {
+		req.Header.Set(header, newRequestID())
+	}
+	trace := HTTPTrace{
+		RequestID: req.Header.Get(header),
+		Method:    req.Method,
+		URL:       urlWithoutQuery(req.URL),
+	}
+
+	// The body is buffered to be sent again or recorded.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil && (t.MaxRetries > 0 || t.RecordBodies) {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, t.fail(trace, start, fmt.Errorf("read request body: %w", err))
+		}
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.Body, _ = req.GetBody()
+	}
+	if t.RecordBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			trace.RequestBody, _ = io.ReadAll(io.LimitReader(body, int64(t.maxRecordedBodySize())))
+			body.Close()
+		}
+	}
+
+	resp, err := t.send(req, &trace)
+	trace.Latency = time.Since(start)
+	if err != nil {
+		return nil, t.fail(trace, start, err)
+	}
+	trace.StatusCode = resp.StatusCode
+	for _, key := range []string{"X-Request-Id", "Request-Id", "Apim-Request-Id"} {
+		if id := resp.Header.Get(key); id != "" {
+			trace.ProviderRequestID = id
+			break
+		}
+	}
+	if t.OnTrace != nil {
+		resp.Body = &tracedBody{ReadCloser: resp.Body, transport: t, trace: trace, start: start}
+	}
+	return resp, nil
+}
+
+// send sends the request, retrying it on failure.
+func (t *InstrumentedTransport) send(req *http.Request, trace *HTTPTrace) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	backoff := t.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("reset request body: %w", err)
+			}
+			req.Body = body
+		}
+		trace.Attempts = attempt + 1
+		resp, err := base.RoundTrip(req)
+		if attempt >= t.MaxRetries || !retryable(resp, err) || req.Context().Err() != nil ||
+			(req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := backoff << attempt
+		if resp != nil {
+			if retryAfter := RetryAfter(resp.Header); retryAfter > 0 {
+				delay = retryAfter
+			}
+			io.Copy(io.Discard, resp.Body) //nolint:errcheck
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether the response or the error of a request is
+// transient.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		529: // Anthropic overloaded.
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *InstrumentedTransport) fail(trace HTTPTrace, start time.Time, err error) error {
+	if t.OnTrace != nil {
+		trace.Err = err
+		trace.Duration = time.Since(start)
+		t.OnTrace(trace)
+	}
+	return err
+}
+
+func (t *InstrumentedTransport) maxRecordedBodySize() int {
+	if t.MaxRecordedBodySize > 0 {
+		return t.MaxRecordedBodySize
+	}
+	return defaultMaxRecordedBodySize
+}
+
+// tracedBody records the body of a response and reports the trace of the
+// request when closed.
+type tracedBody struct {
+	io.ReadCloser
+	transport *InstrumentedTransport
+	trace     HTTPTrace
+	start     time.Time
+	once      sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.transport.RecordBodies {
+		if remaining := b.transport.maxRecordedBodySize() - len(b.trace.ResponseBody); remaining > 0 {
+			if n < remaining {
+				remaining = n
+			}
+			b.trace.ResponseBody = append(b.trace.ResponseBody, p[:remaining]...)
+		}
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.trace.Duration = time.Since(b.start)
+		b.transport.OnTrace(b.trace)
+	})
+	return err
+}
+
+// urlWithoutQuery formats the URL without its query and user info.
+func urlWithoutQuery(u *url.URL) string {
+	stripped := *u
+	stripped.RawQuery = ""
+	stripped.User = nil
+	return stripped.String()
+}
+
+// newRequestID returns a random request id.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
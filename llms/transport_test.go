@@ -0,0 +1,131 @@
+package llms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedTransport(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"prompt":"hi"}`, string(body))
+		assert.Len(t, r.Header.Get("X-Request-Id"), 32)
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0.001")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Request-Id", "req_123")
+		_, err = w.Write([]byte(`{"text":"hello"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	traces := make(chan HTTPTrace, 1)
+	transport := &InstrumentedTransport{
+		MaxRetries:   2,
+		RetryBackoff: time.Hour,
+		RecordBodies: true,
+		OnTrace:      func(trace HTTPTrace) { traces <- trace },
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/generate?key=secret", io.NopCloser(strings.NewReader(`{"prompt":"hi"}`))) //nolint:lll
+	require.NoError(t, err)
+	resp, err := transport.Client().Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, `{"text":"hello"}`, string(body))
+
+	trace := <-traces
+	assert.Equal(t, http.MethodPost, trace.Method)
+	assert.Equal(t, server.URL+"/v1/generate", trace.URL)
+	assert.Equal(t, http.StatusOK, trace.StatusCode)
+	assert.Equal(t, 2, trace.Attempts)
+	assert.Len(t, trace.RequestID, 32)
+	assert.Equal(t, "req_123", trace.ProviderRequestID)
+	assert.Equal(t, `{"prompt":"hi"}`, string(trace.RequestBody))
+	assert.Equal(t, `{"text":"hello"}`, string(trace.ResponseBody))
+	assert.GreaterOrEqual(t, trace.Duration, trace.Latency)
+	require.NoError(t, trace.Err)
+}
+
+func TestInstrumentedTransportMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var trace HTTPTrace
+	transport := &InstrumentedTransport{
+		MaxRetries:          1,
+		RetryBackoff:        time.Millisecond,
+		RequestIDHeader:     "X-Client-Request-Id",
+		MaxRecordedBodySize: 1,
+		OnTrace:             func(t HTTPTrace) { trace = t },
+	}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Client-Request-Id", "my-id")
+	resp, err := transport.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Equal(t, 2, trace.Attempts)
+	assert.Equal(t, "my-id", trace.RequestID)
+	assert.Empty(t, trace.ResponseBody)
+
+	// Client errors are not retried.
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	attempts.Store(0)
+	resp, err = transport.Client().Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestInstrumentedTransportError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var trace HTTPTrace
+	transport := &InstrumentedTransport{
+		MaxRetries:   3,
+		RetryBackoff: time.Hour,
+		OnTrace:      func(t HTTPTrace) { trace = t },
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = transport.Client().Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, trace.Err, context.DeadlineExceeded)
+	assert.Equal(t, 1, trace.Attempts)
+}
@@ -0,0 +1,249 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrContextWindowExceeded is returned when the messages do not fit in the
+// context window after trimming all the messages that can be trimmed.
+var ErrContextWindowExceeded = errors.New("messages exceed the context window")
+
+// Summarizer summarizes the messages trimmed from a conversation into a
+// message sent in their place.
+type Summarizer func(ctx context.Context, messages []schema.ChatMessage) (schema.ChatMessage, error)
+
+// TokenCounter counts the input tokens of messages.
+type TokenCounter func(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (int, error)
+
+// TruncationOption is an option of a truncating chat LLM.
+type TruncationOption func(*truncationOptions)
+
+type truncationOptions struct {
+	summarizer     Summarizer
+	counter        TokenCounter
+	reservedTokens int
+}
+
+// WithSummarizer summarizes the trimmed messages with the summarizer, e.g.
+// ChatSummarizer, instead of dropping them.
+func WithSummarizer(summarizer Summarizer) TruncationOption {
+	return func(o *truncationOptions) {
+		o.summarizer = summarizer
+	}
+}
+
+// WithTokenCounter sets the counter of the input tokens of the messages.
+// Defaults to CountChatTokens for the wrapped chat LLM.
+func WithTokenCounter(counter TokenCounter) TruncationOption {
+	return func(o *truncationOptions) {
+		o.counter = counter
+	}
+}
+
+// WithReservedTokens reserves tokens of the context window for the response.
+// Defaults to the max tokens of the call options.
+func WithReservedTokens(tokens int) TruncationOption {
+	return func(o *truncationOptions) {
+		o.reservedTokens = tokens
+	}
+}
+
+// TruncatingChatLLM is a chat LLM trimming the oldest messages of the
+// conversations exceeding the context window of the wrapped chat LLM. System
+// messages and the last message are never trimmed.
+type TruncatingChatLLM struct {
+	chat        ChatLLM
+	contextSize int
+	options     truncationOptions
+}
+
+var (
+	_ ChatLLM       = (*TruncatingChatLLM)(nil)
+	_ LanguageModel = (*TruncatingChatLLM)(nil)
+)
+
+// NewTruncatingChat wraps the chat LLM to fit the messages in its context
+// window of contextSize tokens.
+func NewTruncatingChat(chat ChatLLM, contextSize int, opts ...TruncationOption) *TruncatingChatLLM {
+	t := &TruncatingChatLLM{chat: chat, contextSize: contextSize}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	if t.options.counter == nil {
+		t.options.counter = func(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (int, error) {
+			return CountChatTokens(ctx, chat, messages, options...)
+		}
+	}
+	return t
+}
+
+// Call trims the messages to the context window and calls the wrapped chat LLM.
+func (t *TruncatingChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := t.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0].Message == nil {
+		return &schema.AIChatMessage{}, nil
+	}
+	return generations[0].Message, nil
+}
+
+// Generate trims each message set to the context window and calls the
+// wrapped chat LLM.
+func (t *TruncatingChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	truncated := make([][]schema.ChatMessage, 0, len(messageSets))
+	for _, messages := range messageSets {
+		fitted, err := t.Truncate(ctx, messages, options...)
+		if err != nil {
+			return nil, err
+		}
+		truncated = append(truncated, fitted)
+	}
+	return t.chat.Generate(ctx, truncated, options...)
+}
+
+func (t *TruncatingChatLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, t, promptValues, options...)
+}
+
+func (t *TruncatingChatLLM) GetNumTokens(text string) int {
+	return numTokens(t.chat, text)
+}
+
+// Truncate returns the messages fitting in the context window. The oldest
+// messages besides the system messages are trimmed first, with the results
+// of the tool calls they hold, and replaced by their summary if a summarizer
+// is set.
+func (t *TruncatingChatLLM) Truncate(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) ([]schema.ChatMessage, error) { //nolint:lll
+	opts := CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	budget := t.contextSize - opts.MaxTokens
+	if t.options.reservedTokens > 0 {
+		budget = t.contextSize - t.options.reservedTokens
+	}
+
+	fits := func(messages []schema.ChatMessage) (bool, error) {
+		tokens, err := t.options.counter(ctx, messages, options...)
+		if err != nil {
+			return false, fmt.Errorf("count tokens: %w", err)
+		}
+		return tokens <= budget, nil
+	}
+	if ok, err := fits(messages); ok || err != nil || len(messages) == 0 {
+		return messages, err
+	}
+
+	// trimmable are the positions of the messages that may be trimmed, all
+	// the messages but the system messages and the last message.
+	var trimmable []int
+	for i, m := range messages[:len(messages)-1] {
+		if m.GetType() != schema.ChatMessageTypeSystem {
+			trimmable = append(trimmable, i)
+		}
+	}
+
+	// Find the fewest trimmed messages fitting in the context window, the
+	// number of tokens decreases with the number of trimmed messages.
+	var fitErr error
+	n := sort.Search(len(trimmable), func(n int) bool {
+		if fitErr != nil {
+			return true
+		}
+		ok, err := fits(trim(messages, trimmable, n, nil))
+		fitErr = err
+		return ok
+	})
+	if fitErr != nil {
+		return nil, fitErr
+	}
+	if n == len(trimmable) {
+		if ok, err := fits(trim(messages, trimmable, n, nil)); !ok || err != nil {
+			return nil, errors.Join(ErrContextWindowExceeded, err)
+		}
+	}
+	if t.options.summarizer == nil {
+		return trim(messages, trimmable, n, nil), nil
+	}
+
+	// The summary takes the place of the trimmed messages, trim more
+	// messages until the summary fits as well.
+	for ; n <= len(trimmable); n++ {
+		trimmed := make([]schema.ChatMessage, 0, n)
+		for _, i := range trimmable[:n] {
+			trimmed = append(trimmed, messages[i])
+		}
+		summary, err := t.options.summarizer(ctx, trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("summarize messages: %w", err)
+		}
+		fitted := trim(messages, trimmable, n, summary)
+		ok, err := fits(fitted)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return fitted, nil
+		}
+	}
+	// Even the summary of all the trimmable messages does not fit.
+	return trim(messages, trimmable, len(trimmable), nil), nil
+}
+
+// trim returns the messages without the first n trimmable messages and the
+// tool results following them, with the summary in their place if not nil.
+func trim(messages []schema.ChatMessage, trimmable []int, n int, summary schema.ChatMessage) []schema.ChatMessage {
+	trimmed := make(map[int]bool, n)
+	for _, i := range trimmable[:n] {
+		trimmed[i] = true
+	}
+	// The results of the trimmed tool calls are trimmed as well, the
+	// providers reject results without calls.
+	for _, i := range trimmable[n:] {
+		if t := messages[i].GetType(); n == 0 || t != schema.ChatMessageTypeTool && t != schema.ChatMessageTypeFunction {
+			break
+		}
+		trimmed[i] = true
+	}
+
+	fitted := make([]schema.ChatMessage, 0, len(messages)-len(trimmed)+1)
+	for i, m := range messages {
+		if trimmed[i] {
+			continue
+		}
+		if summary != nil && len(trimmable) > 0 && i > trimmable[0] {
+			fitted = append(fitted, summary)
+			summary = nil
+		}
+		fitted = append(fitted, m)
+	}
+	return fitted
+}
+
+// ChatSummarizer returns a summarizer asking the chat LLM to summarize the
+// trimmed messages, sent as a system message.
+func ChatSummarizer(chat ChatLLM, options ...CallOption) Summarizer {
+	return func(ctx context.Context, messages []schema.ChatMessage) (schema.ChatMessage, error) {
+		conversation, err := schema.GetBufferString(messages, "Human", "AI")
+		if err != nil {
+			return nil, err
+		}
+		msg, err := chat.Call(ctx, []schema.ChatMessage{
+			schema.SystemChatMessage{
+				Content: "Summarize the conversation concisely, keeping the facts, decisions and open questions.",
+			},
+			schema.HumanChatMessage{Content: conversation},
+		}, options...)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SystemChatMessage{Content: "Summary of the earlier conversation:\n" + msg.Content}, nil
+	}
+}
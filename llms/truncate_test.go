@@ -0,0 +1,141 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// echoChatLLM records the message sets it is called with.
+type echoChatLLM struct {
+	messageSets [][]schema.ChatMessage
+}
+
+func (e *echoChatLLM) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := e.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (e *echoChatLLM) Generate(_ context.Context, messageSets [][]schema.ChatMessage, _ ...CallOption) ([]*Generation, error) { //nolint:lll
+	e.messageSets = append(e.messageSets, messageSets...)
+	generations := make([]*Generation, 0, len(messageSets))
+	for range messageSets {
+		generations = append(generations, &Generation{Message: &schema.AIChatMessage{Content: "ok"}, Text: "ok"})
+	}
+	return generations, nil
+}
+
+// countWords counts a token per word of the messages.
+func countWords(_ context.Context, messages []schema.ChatMessage, _ ...CallOption) (int, error) {
+	tokens := 0
+	for _, m := range messages {
+		tokens += len(strings.Fields(m.GetContent()))
+	}
+	return tokens, nil
+}
+
+func contents(messages []schema.ChatMessage) []string {
+	result := make([]string, 0, len(messages))
+	for _, m := range messages {
+		result = append(result, m.GetContent())
+	}
+	return result
+}
+
+func TestTruncatingChatLLM(t *testing.T) {
+	t.Parallel()
+
+	conversation := []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.HumanChatMessage{Content: "one two three"},
+		schema.AIChatMessage{Content: "four five", ToolCalls: []schema.ToolCall{{ID: "call_1"}}},
+		schema.ToolChatMessage{ID: "call_1", Content: "six"},
+		schema.AIChatMessage{Content: "seven"},
+		schema.HumanChatMessage{Content: "eight nine"},
+	}
+
+	tests := []struct {
+		name        string
+		contextSize int
+		options     []CallOption
+		want        []string
+	}{
+		{"fits", 20, nil, contents(conversation)},
+		{"oldest trimmed", 10, nil, []string{"be brief", "four five", "six", "seven", "eight nine"}},
+		{"tool results trimmed with calls", 7, nil, []string{"be brief", "seven", "eight nine"}},
+		{"max tokens reserved", 20, []CallOption{WithMaxTokens(13)}, []string{"be brief", "seven", "eight nine"}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			chat := &echoChatLLM{}
+			truncating := NewTruncatingChat(chat, tc.contextSize, WithTokenCounter(countWords))
+			_, err := truncating.Call(context.Background(), conversation, tc.options...)
+			require.NoError(t, err)
+			require.Len(t, chat.messageSets, 1)
+			assert.Equal(t, tc.want, contents(chat.messageSets[0]))
+		})
+	}
+}
+
+func TestTruncatingChatLLMExceeded(t *testing.T) {
+	t.Parallel()
+
+	truncating := NewTruncatingChat(&echoChatLLM{}, 3, WithTokenCounter(countWords))
+	_, err := truncating.Call(context.Background(), []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.HumanChatMessage{Content: "one two"},
+	})
+	require.ErrorIs(t, err, ErrContextWindowExceeded)
+}
+
+func TestTruncatingChatLLMSummarizer(t *testing.T) {
+	t.Parallel()
+
+	var summarized [][]string
+	summarizer := func(_ context.Context, messages []schema.ChatMessage) (schema.ChatMessage, error) {
+		summarized = append(summarized, contents(messages))
+		return schema.SystemChatMessage{Content: "summary"}, nil
+	}
+
+	chat := &echoChatLLM{}
+	truncating := NewTruncatingChat(chat, 7, WithTokenCounter(countWords), WithSummarizer(summarizer))
+	_, err := truncating.Call(context.Background(), []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.HumanChatMessage{Content: "one two"},
+		schema.AIChatMessage{Content: "three"},
+		schema.HumanChatMessage{Content: "four five"},
+		schema.AIChatMessage{Content: "six"},
+		schema.HumanChatMessage{Content: "seven"},
+	})
+	require.NoError(t, err)
+
+	// Trimming the first message fits without a summary, the summary needs
+	// the second message trimmed as well.
+	assert.Equal(t, [][]string{{"one two"}, {"one two", "three"}}, summarized)
+	assert.Equal(t, []string{"be brief", "summary", "four five", "six", "seven"}, contents(chat.messageSets[0]))
+}
+
+func TestChatSummarizer(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "They said hello."}}}
+	summary, err := ChatSummarizer(model)(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "hello"},
+		schema.AIChatMessage{Content: "hi"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, schema.SystemChatMessage{Content: "Summary of the earlier conversation:\nThey said hello."}, summary)
+	require.Len(t, model.calls, 1)
+	assert.Equal(t, "Human: hello\nAI: hi", model.calls[0][1].GetContent())
+}
@@ -0,0 +1,55 @@
+package llms
+
+import "github.com/tmc/langchaingo/llms/models"
+
+// Usage is the token usage of a generation, as reported by the provider.
+type Usage struct {
+	// PromptTokens is the number of tokens of the input, including cached tokens.
+	PromptTokens int `json:"prompt_tokens"`
+	// CompletionTokens is the number of generated tokens.
+	CompletionTokens int `json:"completion_tokens"`
+	// TotalTokens is the sum of the prompt and completion tokens.
+	TotalTokens int `json:"total_tokens"`
+	// CachedTokens is the number of prompt tokens read from the prompt cache
+	// of the provider.
+	CachedTokens int `json:"cached_tokens"`
+	// CacheWriteTokens is the number of prompt tokens written to the prompt
+	// cache of the provider, for providers billing cache writes.
+	CacheWriteTokens int `json:"cache_write_tokens"`
+	// ReasoningTokens is the number of completion tokens of the reasoning of
+	// reasoning models, for providers reporting them.
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// Add returns the sum of the usages.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		CachedTokens:     u.CachedTokens + other.CachedTokens,
+		CacheWriteTokens: u.CacheWriteTokens + other.CacheWriteTokens,
+		ReasoningTokens:  u.ReasoningTokens + other.ReasoningTokens,
+	}
+}
+
+// TotalUsage returns the sum of the usage of the generations.
+func TotalUsage(generations []*Generation) Usage {
+	var usage Usage
+	for _, generation := range generations {
+		if generation != nil {
+			usage = usage.Add(generation.Usage)
+		}
+	}
+	return usage
+}
+
+// Cost returns the cost in US dollars of the usage with the pricing of the
+// model in the models registry, or models.ErrUnknownModel.
+func (u Usage) Cost(model string) (float64, error) {
+	m, err := models.Get(model)
+	if err != nil {
+		return 0, err
+	}
+	return m.Pricing.Cost(u.PromptTokens, u.CachedTokens, u.CacheWriteTokens, u.CompletionTokens), nil
+}
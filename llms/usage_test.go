@@ -0,0 +1,76 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/models"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestTotalUsage(t *testing.T) {
+	t.Parallel()
+
+	generations := []*Generation{
+		{Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, CachedTokens: 8}},
+		nil,
+		{Usage: Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5, CacheWriteTokens: 4, ReasoningTokens: 1}},
+	}
+	assert.Equal(t, Usage{
+		PromptTokens: 13, CompletionTokens: 7, TotalTokens: 20, CachedTokens: 8, CacheWriteTokens: 4, ReasoningTokens: 1,
+	}, TotalUsage(generations))
+}
+
+func TestGenerateChatPromptUsage(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{responses: []*schema.AIChatMessage{{Content: "hi"}}}
+	usage := Usage{PromptTokens: 2, CompletionTokens: 1, TotalTokens: 3}
+	result, err := GenerateChatPrompt(context.Background(), usageChatLLM{model, usage}, []schema.PromptValue{
+		humanPromptValue("hello"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, usage, result.Usage)
+}
+
+// usageChatLLM reports a fixed usage for every generation.
+type usageChatLLM struct {
+	*fakeChatLLM
+	usage Usage
+}
+
+func (u usageChatLLM) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	generations := make([]*Generation, 0, len(messageSets))
+	for _, messages := range messageSets {
+		msg, err := u.Call(ctx, messages, options...)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, &Generation{Message: msg, Text: msg.Content, Usage: u.usage})
+	}
+	return generations, nil
+}
+
+type humanPromptValue string
+
+func (v humanPromptValue) String() string { return string(v) }
+func (v humanPromptValue) Messages() []schema.ChatMessage {
+	return []schema.ChatMessage{schema.HumanChatMessage{Content: string(v)}}
+}
+
+func TestUsageCost(t *testing.T) {
+	t.Parallel()
+
+	usage := Usage{PromptTokens: 1_000_000, CompletionTokens: 100_000, CachedTokens: 200_000, CacheWriteTokens: 300_000}
+	cost, err := usage.Cost("claude-3-5-sonnet-20241022")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5*3+0.2*0.3+0.3*3.75+0.1*15, cost, 1e-9)
+
+	_, err = usage.Cost("unknown-model")
+	require.ErrorIs(t, err, models.ErrUnknownModel)
+
+	assert.Equal(t, 128000, GetModelContextSize("gpt-4o-2024-08-06"))
+	assert.Equal(t, 8192, GetModelContextSize("gpt-4"))
+}
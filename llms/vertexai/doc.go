@@ -0,0 +1,11 @@
+// Package vertexai provides the PaLM text, chat and embedding models of
+// Vertex AI.
+//
+// The package has no Gemini models. The features mapped to the Gemini API by
+// other requests are not available with PaLM: PaLM has no JSON mode, so the
+// response formats of llms.WithResponseFormat are not sent to the API, and
+// the PaLM chat models only accept text, so the human messages with image or
+// file parts return llms.ErrUnsupportedContentPart. PaLM has no countTokens
+// endpoint either, so llms.CountChatTokens estimates the tokens of the
+// messages with llms.CountMessageTokens.
+package vertexai
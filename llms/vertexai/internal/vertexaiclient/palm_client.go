@@ -2,6 +2,7 @@ package vertexaiclient
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"runtime"
@@ -34,9 +35,10 @@ var defaultParameters = map[string]interface{}{ //nolint:gochecknoglobals
 }
 
 const (
-	embeddingModelName = "textembedding-gecko"
-	TextModelName      = "text-bison"
-	ChatModelName      = "chat-bison"
+	embeddingModelName           = "text-embedding-005"
+	multimodalEmbeddingModelName = "multimodalembedding@001"
+	TextModelName                = "text-bison"
+	ChatModelName                = "chat-bison"
 
 	defaultMaxConns = 4
 )
@@ -80,6 +82,8 @@ type CompletionRequest struct {
 	Temperature float64  `json:"temperature,omitempty"`
 	TopP        int      `json:"top_p,omitempty"`
 	TopK        int      `json:"top_k,omitempty"`
+	// StopSequences stop the generation of the text.
+	StopSequences []string `json:"stop_sequences,omitempty"`
 }
 
 // Completion is a completion.
@@ -87,20 +91,28 @@ type Completion struct {
 	Text string `json:"text"`
 }
 
+// CompletionResponse is the response to a completion request.
+type CompletionResponse struct {
+	Completions []*Completion
+}
+
 // CreateCompletion creates a completion.
-func (c *PaLMClient) CreateCompletion(ctx context.Context, r *CompletionRequest) ([]*Completion, error) {
+func (c *PaLMClient) CreateCompletion(ctx context.Context, r *CompletionRequest) (*CompletionResponse, error) {
 	params := map[string]interface{}{
 		"maxOutputTokens": r.MaxTokens,
 		"temperature":     r.Temperature,
 		"top_p":           r.TopP,
 		"top_k":           r.TopK,
 	}
-	predictions, err := c.batchPredict(ctx, TextModelName, r.Prompts, params)
+	if len(r.StopSequences) > 0 {
+		params["stopSequences"] = r.StopSequences
+	}
+	resp, err := c.batchPredict(ctx, TextModelName, r.Prompts, params)
 	if err != nil {
 		return nil, err
 	}
 	completions := []*Completion{}
-	for _, p := range predictions {
+	for _, p := range resp.Predictions {
 		value := p.GetStructValue().AsMap()
 		text, ok := value["content"].(string)
 		if !ok {
@@ -110,24 +122,61 @@ func (c *PaLMClient) CreateCompletion(ctx context.Context, r *CompletionRequest)
 			Text: text,
 		})
 	}
-	return completions, nil
+	return &CompletionResponse{Completions: completions}, nil
 }
 
 // EmbeddingRequest is a request to create an embedding.
 type EmbeddingRequest struct {
 	Input []string `json:"input"`
+	// Model is the embedding model, text-embedding-005 if not set.
+	Model string `json:"model,omitempty"`
+	// TaskType is the use of the embeddings, e.g. RETRIEVAL_DOCUMENT or
+	// RETRIEVAL_QUERY.
+	TaskType string `json:"task_type,omitempty"`
+	// OutputDimensionality shortens the embeddings, the model default if zero.
+	OutputDimensionality int `json:"output_dimensionality,omitempty"`
 }
 
 // CreateEmbedding creates embeddings.
 func (c *PaLMClient) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]float64, error) {
+	model := r.Model
+	if model == "" {
+		model = embeddingModelName
+	}
+	instances := make([]*structpb.Value, 0, len(r.Input))
+	for _, text := range r.Input {
+		instance := map[string]interface{}{"content": text}
+		if r.TaskType != "" {
+			instance["task_type"] = r.TaskType
+		}
+		value, err := structpb.NewStruct(instance)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, structpb.NewStructValue(value))
+	}
 	params := map[string]interface{}{}
-	responses, err := c.batchPredict(ctx, embeddingModelName, r.Input, params)
+	if r.OutputDimensionality > 0 {
+		params["outputDimensionality"] = r.OutputDimensionality
+	}
+	parameters, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Predict(ctx, &aiplatformpb.PredictRequest{
+		Endpoint:   c.projectLocationPublisherModelPath(c.projectID, defaultLocation, defaultPublisher, model),
+		Instances:  instances,
+		Parameters: structpb.NewStructValue(parameters),
+	})
 	if err != nil {
 		return nil, err
 	}
+	if len(resp.Predictions) == 0 {
+		return nil, ErrEmptyResponse
+	}
 
 	embeddings := [][]float64{}
-	for _, res := range responses {
+	for _, res := range resp.Predictions {
 		value := res.GetStructValue().AsMap()
 		embedding, ok := value["embeddings"].(map[string]interface{})
 		if !ok {
@@ -150,6 +199,96 @@ func (c *PaLMClient) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) (
 	return embeddings, nil
 }
 
+// MultimodalEmbeddingRequest is a request to embed a text, an image or both
+// in the same space.
+type MultimodalEmbeddingRequest struct {
+	Text string `json:"text,omitempty"`
+	// Image is the encoded image, e.g. in JPEG or PNG.
+	Image []byte `json:"image,omitempty"`
+	// Model is the embedding model, multimodalembedding@001 if not set.
+	Model string `json:"model,omitempty"`
+	// Dimension is the dimension of the embeddings, 128, 256, 512 or 1408,
+	// the model default if zero.
+	Dimension int `json:"dimension,omitempty"`
+}
+
+// MultimodalEmbedding is the embedding of the text and the image of a
+// request, nil if not in the request.
+type MultimodalEmbedding struct {
+	Text  []float64
+	Image []float64
+}
+
+// CreateMultimodalEmbedding embeds a text, an image or both.
+func (c *PaLMClient) CreateMultimodalEmbedding(ctx context.Context, r *MultimodalEmbeddingRequest) (*MultimodalEmbedding, error) { //nolint:lll
+	model := r.Model
+	if model == "" {
+		model = multimodalEmbeddingModelName
+	}
+	instance := map[string]interface{}{}
+	if r.Text != "" {
+		instance["text"] = r.Text
+	}
+	if len(r.Image) > 0 {
+		instance["image"] = map[string]interface{}{
+			"bytesBase64Encoded": base64.StdEncoding.EncodeToString(r.Image),
+		}
+	}
+	value, err := structpb.NewStruct(instance)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	if r.Dimension > 0 {
+		params["dimension"] = r.Dimension
+	}
+	parameters, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Predict(ctx, &aiplatformpb.PredictRequest{
+		Endpoint:   c.projectLocationPublisherModelPath(c.projectID, defaultLocation, defaultPublisher, model),
+		Instances:  []*structpb.Value{structpb.NewStructValue(value)},
+		Parameters: structpb.NewStructValue(parameters),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Predictions) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	prediction := resp.Predictions[0].GetStructValue().AsMap()
+	embedding := &MultimodalEmbedding{}
+	if r.Text != "" {
+		if embedding.Text, err = floatValues(prediction, "textEmbedding"); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Image) > 0 {
+		if embedding.Image, err = floatValues(prediction, "imageEmbedding"); err != nil {
+			return nil, err
+		}
+	}
+	return embedding, nil
+}
+
+func floatValues(prediction map[string]interface{}, key string) ([]float64, error) {
+	values, ok := prediction[key].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrMissingValue, key)
+	}
+	floatValues := make([]float64, 0, len(values))
+	for _, v := range values {
+		val, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a float64", ErrInvalidValue, key)
+		}
+		floatValues = append(floatValues, val)
+	}
+	return floatValues, nil
+}
+
 // ChatRequest is a request to create an embedding.
 type ChatRequest struct {
 	Context        string         `json:"context"`
@@ -158,6 +297,8 @@ type ChatRequest struct {
 	TopP           int            `json:"top_p,omitempty"`
 	TopK           int            `json:"top_k,omitempty"`
 	CandidateCount int            `json:"candidate_count,omitempty"`
+	// StopSequences stop the generation of the response.
+	StopSequences []string `json:"stop_sequences,omitempty"`
 }
 
 // ChatMessage is a message in a chat.
@@ -193,12 +334,12 @@ type ChatResponse struct {
 
 // CreateChat creates chat request.
 func (c *PaLMClient) CreateChat(ctx context.Context, r *ChatRequest) (*ChatResponse, error) {
-	responses, err := c.chat(ctx, r)
+	resp, err := c.chat(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 	chatResponse := &ChatResponse{}
-	res := responses[0]
+	res := resp.Predictions[0]
 	value := res.GetStructValue().AsMap()
 	candidates, ok := value["candidates"].([]interface{})
 	if !ok {
@@ -252,7 +393,7 @@ func mergeParams(defaultParams, params map[string]interface{}) *structpb.Struct
 	return smergedParams
 }
 
-func (c *PaLMClient) batchPredict(ctx context.Context, model string, prompts []string, params map[string]interface{}) ([]*structpb.Value, error) { //nolint:lll
+func (c *PaLMClient) batchPredict(ctx context.Context, model string, prompts []string, params map[string]interface{}) (*aiplatformpb.PredictResponse, error) { //nolint:lll
 	mergedParams := mergeParams(defaultParameters, params)
 	instances := []*structpb.Value{}
 	for _, prompt := range prompts {
@@ -272,15 +413,18 @@ func (c *PaLMClient) batchPredict(ctx context.Context, model string, prompts []s
 	if len(resp.Predictions) == 0 {
 		return nil, ErrEmptyResponse
 	}
-	return resp.Predictions, nil
+	return resp, nil
 }
 
-func (c *PaLMClient) chat(ctx context.Context, r *ChatRequest) ([]*structpb.Value, error) {
+func (c *PaLMClient) chat(ctx context.Context, r *ChatRequest) (*aiplatformpb.PredictResponse, error) {
 	params := map[string]interface{}{
 		"temperature": r.Temperature,
 		"top_p":       r.TopP,
 		"top_k":       r.TopK,
 	}
+	if len(r.StopSequences) > 0 {
+		params["stopSequences"] = r.StopSequences
+	}
 	mergedParams := mergeParams(defaultParameters, params)
 	messages := []interface{}{}
 	for _, msg := range r.Messages {
@@ -311,7 +455,7 @@ func (c *PaLMClient) chat(ctx context.Context, r *ChatRequest) ([]*structpb.Valu
 	if len(resp.Predictions) == 0 {
 		return nil, ErrEmptyResponse
 	}
-	return resp.Predictions, nil
+	return resp, nil
 }
 
 func (c *PaLMClient) projectLocationPublisherModelPath(projectID, location, publisher, model string) string {
@@ -14,10 +14,12 @@ var (
 	ErrMissingProjectID         = errors.New("missing the GCP Project ID, set it in the GOOGLE_CLOUD_PROJECT environment variable") //nolint:lll
 	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
 	ErrNotImplemented           = errors.New("not implemented")
+	ErrEmptyInput               = errors.New("missing the text and the image to embed")
 )
 
 type LLM struct {
-	client *vertexaiclient.PaLMClient
+	client    *vertexaiclient.PaLMClient
+	embedding embeddingOptions
 }
 
 var (
@@ -37,46 +39,63 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return r[0].Text, nil
 }
 
+// samplingLimits are the limits of PaLM on the sampling options, which does
+// not support logit biases.
+var samplingLimits = llms.SamplingLimits{} //nolint:gochecknoglobals
+
 func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
 	}
-	results, err := o.client.CreateCompletion(ctx, &vertexaiclient.CompletionRequest{
-		Prompts:     prompts,
-		MaxTokens:   opts.MaxTokens,
-		Temperature: opts.Temperature,
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
+	resp, err := o.client.CreateCompletion(ctx, &vertexaiclient.CompletionRequest{
+		Prompts:       prompts,
+		MaxTokens:     opts.MaxTokens,
+		Temperature:   opts.Temperature,
+		StopSequences: opts.StopWords,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	generations := []*llms.Generation{}
-	for _, r := range results {
+	for i, r := range resp.Completions {
+		var prompt string
+		if i < len(prompts) {
+			prompt = prompts[i]
+		}
 		generations = append(generations, &llms.Generation{
-			Text: r.Text,
+			Text:  r.Text,
+			Usage: estimateUsage(o.GetNumTokens, prompt, r.Text),
 		})
 	}
 	return generations, nil
 }
 
-// CreateEmbedding creates embeddings for the given input texts.
-func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
-	embeddings, err := o.client.CreateEmbedding(ctx, &vertexaiclient.EmbeddingRequest{
-		Input: inputTexts,
-	})
-	if err != nil {
-		return [][]float64{}, err
+// estimateUsage estimates the token usage of a generation with the tokenizer
+// of GetNumTokens, as the client does not report the token usage of PaLM.
+func estimateUsage(numTokens func(string) int, prompt, completion string) llms.Usage {
+	promptTokens, completionTokens := numTokens(prompt), numTokens(completion)
+	return llms.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
 	}
+}
 
-	if len(embeddings) == 0 {
-		return [][]float64{}, ErrEmptyResponse
-	}
-	if len(inputTexts) != len(embeddings) {
-		return embeddings, ErrUnexpectedResponseLength
-	}
+// CreateEmbedding creates embeddings for the given input texts, embedded as
+// documents of a search.
+func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return o.CreateEmbeddingWithTaskType(ctx, inputTexts, TaskTypeRetrievalDocument)
+}
 
-	return embeddings, nil
+// CreateEmbeddingWithTaskType creates embeddings for the given input texts,
+// embedded for the task type.
+func (o *LLM) CreateEmbeddingWithTaskType(ctx context.Context, inputTexts []string, taskType TaskType) ([][]float64, error) { //nolint:lll
+	return o.embedding.createEmbedding(ctx, o.client, inputTexts, taskType)
 }
 
 func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
@@ -89,11 +108,14 @@ func (o *LLM) GetNumTokens(text string) int {
 
 // New returns a new VertexAI PaLM LLM.
 func New(opts ...Option) (*LLM, error) {
-	client, err := newClient(opts...)
-	return &LLM{client: client}, err
+	client, options, err := newClient(opts...)
+	if err != nil {
+		return &LLM{}, err
+	}
+	return &LLM{client: client, embedding: options.embeddingOptions()}, nil
 }
 
-func newClient(opts ...Option) (*vertexaiclient.PaLMClient, error) {
+func newClient(opts ...Option) (*vertexaiclient.PaLMClient, *options, error) {
 	// Ensure options are initialized only once.
 	initOptions.Do(initOpts)
 	options := &options{}
@@ -103,8 +125,9 @@ func newClient(opts ...Option) (*vertexaiclient.PaLMClient, error) {
 		opt(options)
 	}
 	if len(options.projectID) == 0 {
-		return nil, ErrMissingProjectID
+		return nil, nil, ErrMissingProjectID
 	}
 
-	return vertexaiclient.New(options.projectID, options.clientOptions...)
+	client, err := vertexaiclient.New(options.projectID, options.clientOptions...)
+	return client, options, err
 }
@@ -2,6 +2,7 @@ package vertexai
 
 import (
 	"context"
+	"strings"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/vertexai/internal/vertexaiclient"
@@ -16,7 +17,8 @@ const (
 type ChatMessage = vertexaiclient.ChatMessage
 
 type Chat struct {
-	client *vertexaiclient.PaLMClient
+	client    *vertexaiclient.PaLMClient
+	embedding embeddingOptions
 }
 
 var (
@@ -45,13 +47,21 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 	if opts.StreamingFunc != nil {
 		return nil, ErrNotImplemented
 	}
+	if err := samplingLimits.Validate(opts); err != nil {
+		return nil, err
+	}
 
 	generations := make([]*llms.Generation, 0, len(messageSets))
 	for _, messages := range messageSets {
+		// The PaLM chat models only accept text.
+		if err := llms.RequireTextParts(messages); err != nil {
+			return nil, err
+		}
 		msgs := toClientChatMessage(messages)
 		result, err := o.client.CreateChat(ctx, &vertexaiclient.ChatRequest{
-			Temperature: opts.Temperature,
-			Messages:    msgs,
+			Temperature:   opts.Temperature,
+			Messages:      msgs,
+			StopSequences: opts.StopWords,
 		})
 		if err != nil {
 			return nil, err
@@ -63,7 +73,8 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 			Message: &schema.AIChatMessage{
 				Content: result.Candidates[0].Content,
 			},
-			Text: result.Candidates[0].Content,
+			Text:  result.Candidates[0].Content,
+			Usage: estimateUsage(o.GetNumTokens, chatPrompt(messages), result.Candidates[0].Content),
 		})
 	}
 
@@ -78,6 +89,16 @@ func (o *Chat) GetNumTokens(text string) int {
 	return llms.CountTokens(vertexaiclient.TextModelName, text)
 }
 
+// chatPrompt returns the contents of the messages, one per line, to estimate
+// their token count.
+func chatPrompt(messages []schema.ChatMessage) string {
+	contents := make([]string, 0, len(messages))
+	for _, m := range messages {
+		contents = append(contents, m.GetContent())
+	}
+	return strings.Join(contents, "\n")
+}
+
 func toClientChatMessage(messages []schema.ChatMessage) []*vertexaiclient.ChatMessage {
 	msgs := make([]*vertexaiclient.ChatMessage, len(messages))
 	for i, m := range messages {
@@ -107,23 +128,21 @@ func toClientChatMessage(messages []schema.ChatMessage) []*vertexaiclient.ChatMe
 
 // NewChat returns a new VertexAI PaLM Chat LLM.
 func NewChat(opts ...Option) (*Chat, error) {
-	client, err := newClient(opts...)
-	return &Chat{client: client}, err
+	client, options, err := newClient(opts...)
+	if err != nil {
+		return &Chat{}, err
+	}
+	return &Chat{client: client, embedding: options.embeddingOptions()}, nil
 }
 
-// CreateEmbedding creates embeddings for the given input texts.
+// CreateEmbedding creates embeddings for the given input texts, embedded as
+// documents of a search.
 func (o *Chat) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
-	embeddings, err := o.client.CreateEmbedding(ctx, &vertexaiclient.EmbeddingRequest{
-		Input: inputTexts,
-	})
-	if err != nil {
-		return nil, err
-	}
-	if len(embeddings) == 0 {
-		return nil, ErrEmptyResponse
-	}
-	if len(inputTexts) != len(embeddings) {
-		return embeddings, ErrUnexpectedResponseLength
-	}
-	return embeddings, nil
+	return o.CreateEmbeddingWithTaskType(ctx, inputTexts, TaskTypeRetrievalDocument)
+}
+
+// CreateEmbeddingWithTaskType creates embeddings for the given input texts,
+// embedded for the task type.
+func (o *Chat) CreateEmbeddingWithTaskType(ctx context.Context, inputTexts []string, taskType TaskType) ([][]float64, error) { //nolint:lll
+	return o.embedding.createEmbedding(ctx, o.client, inputTexts, taskType)
 }
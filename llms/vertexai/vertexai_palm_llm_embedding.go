@@ -0,0 +1,92 @@
+package vertexai
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms/vertexai/internal/vertexaiclient"
+)
+
+// TaskType is the use of the embedded texts. The text-embedding models embed
+// the documents and the queries of a search differently.
+type TaskType string
+
+const (
+	TaskTypeRetrievalDocument  TaskType = "RETRIEVAL_DOCUMENT"
+	TaskTypeRetrievalQuery     TaskType = "RETRIEVAL_QUERY"
+	TaskTypeSemanticSimilarity TaskType = "SEMANTIC_SIMILARITY"
+	TaskTypeClassification     TaskType = "CLASSIFICATION"
+	TaskTypeClustering         TaskType = "CLUSTERING"
+)
+
+// embeddingOptions are the options of the embeddings shared by the LLM and
+// the Chat.
+type embeddingOptions struct {
+	model      string
+	dimensions int
+}
+
+func (o *options) embeddingOptions() embeddingOptions {
+	return embeddingOptions{model: o.embeddingModel, dimensions: o.embeddingDimensions}
+}
+
+func (o embeddingOptions) createEmbedding(ctx context.Context, client *vertexaiclient.PaLMClient, inputTexts []string, taskType TaskType) ([][]float64, error) { //nolint:lll
+	embeddings, err := client.CreateEmbedding(ctx, &vertexaiclient.EmbeddingRequest{
+		Input:                inputTexts,
+		Model:                o.model,
+		TaskType:             string(taskType),
+		OutputDimensionality: o.dimensions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
+
+// MultimodalEmbeddingOption is an option of CreateMultimodalEmbedding.
+type MultimodalEmbeddingOption func(*vertexaiclient.MultimodalEmbeddingRequest)
+
+// WithMultimodalEmbeddingModel sets the model of the multimodal embeddings,
+// multimodalembedding@001 if not set.
+func WithMultimodalEmbeddingModel(model string) MultimodalEmbeddingOption {
+	return func(r *vertexaiclient.MultimodalEmbeddingRequest) {
+		r.Model = model
+	}
+}
+
+// WithMultimodalEmbeddingDimension sets the dimension of the multimodal
+// embeddings, 128, 256, 512 or 1408 by default.
+func WithMultimodalEmbeddingDimension(dimension int) MultimodalEmbeddingOption {
+	return func(r *vertexaiclient.MultimodalEmbeddingRequest) {
+		r.Dimension = dimension
+	}
+}
+
+// CreateMultimodalEmbedding embeds a text, an encoded image, e.g. in JPEG or
+// PNG, or both in the same space with the multimodal embedding model, and
+// returns the embedding of the text and the embedding of the image, nil if
+// empty.
+func (o *LLM) CreateMultimodalEmbedding(
+	ctx context.Context,
+	text string,
+	image []byte,
+	opts ...MultimodalEmbeddingOption,
+) ([]float64, []float64, error) {
+	if text == "" && len(image) == 0 {
+		return nil, nil, ErrEmptyInput
+	}
+	r := &vertexaiclient.MultimodalEmbeddingRequest{Text: text, Image: image}
+	for _, opt := range opts {
+		opt(r)
+	}
+	embedding, err := o.client.CreateMultimodalEmbedding(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return embedding.Text, embedding.Image, nil
+}
@@ -0,0 +1,131 @@
+package vertexai
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type fakePredictionServer struct {
+	aiplatformpb.UnimplementedPredictionServiceServer
+	requests chan *aiplatformpb.PredictRequest
+}
+
+func (s *fakePredictionServer) Predict(_ context.Context, req *aiplatformpb.PredictRequest) (*aiplatformpb.PredictResponse, error) { //nolint:lll
+	s.requests <- req
+	resp := &aiplatformpb.PredictResponse{}
+	for _, instance := range req.Instances {
+		value := map[string]any{
+			"embeddings": map[string]any{"values": []any{0.6, 0.8}},
+		}
+		// The multimodal instances have a text or an image.
+		fields := instance.GetStructValue().AsMap()
+		if _, ok := fields["text"]; ok {
+			value = map[string]any{"textEmbedding": []any{0.6, 0.8}}
+		}
+		if _, ok := fields["image"]; ok {
+			value["imageEmbedding"] = []any{0.8, 0.6}
+		}
+		prediction, err := structpb.NewValue(value)
+		if err != nil {
+			return nil, err
+		}
+		resp.Predictions = append(resp.Predictions, prediction)
+	}
+	return resp, nil
+}
+
+func newFakeServer(t *testing.T) (*fakePredictionServer, []Option) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fake := &fakePredictionServer{requests: make(chan *aiplatformpb.PredictRequest, 10)}
+	server := grpc.NewServer()
+	aiplatformpb.RegisterPredictionServiceServer(server, fake)
+	go server.Serve(lis) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	return fake, []Option{
+		WithProjectID("project"),
+		WithClientOptions(
+			option.WithEndpoint(lis.Addr().String()),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		),
+	}
+}
+
+func TestCreateEmbedding(t *testing.T) {
+	t.Parallel()
+
+	fake, opts := newFakeServer(t)
+	llm, err := New(opts...)
+	require.NoError(t, err)
+
+	embeddings, err := llm.CreateEmbedding(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.6, 0.8}, {0.6, 0.8}}, embeddings)
+
+	req := <-fake.requests
+	assert.Equal(t, "projects/project/locations/us-central1/publishers/google/models/text-embedding-005", req.Endpoint)
+	require.Len(t, req.Instances, 2)
+	assert.Equal(t, map[string]any{"content": "a", "task_type": "RETRIEVAL_DOCUMENT"},
+		req.Instances[0].GetStructValue().AsMap())
+	assert.Empty(t, req.Parameters.GetStructValue().AsMap())
+}
+
+func TestCreateEmbeddingWithOptions(t *testing.T) {
+	t.Parallel()
+
+	fake, opts := newFakeServer(t)
+	chat, err := NewChat(append(opts,
+		WithEmbeddingModel("text-multilingual-embedding-002"), WithEmbeddingDimensions(256))...)
+	require.NoError(t, err)
+
+	_, err = chat.CreateEmbeddingWithTaskType(context.Background(), []string{"a"}, TaskTypeRetrievalQuery)
+	require.NoError(t, err)
+
+	req := <-fake.requests
+	assert.Contains(t, req.Endpoint, "/models/text-multilingual-embedding-002")
+	assert.Equal(t, "RETRIEVAL_QUERY", req.Instances[0].GetStructValue().AsMap()["task_type"])
+	assert.Equal(t, map[string]any{"outputDimensionality": 256.0}, req.Parameters.GetStructValue().AsMap())
+}
+
+func TestCreateMultimodalEmbedding(t *testing.T) {
+	t.Parallel()
+
+	fake, opts := newFakeServer(t)
+	llm, err := New(opts...)
+	require.NoError(t, err)
+
+	text, image, err := llm.CreateMultimodalEmbedding(context.Background(), "a cat", []byte("image"),
+		WithMultimodalEmbeddingDimension(128))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.6, 0.8}, text)
+	assert.Equal(t, []float64{0.8, 0.6}, image)
+
+	req := <-fake.requests
+	assert.Equal(t, "projects/project/locations/us-central1/publishers/google/models/multimodalembedding@001", req.Endpoint)
+	assert.Equal(t, map[string]any{
+		"text":  "a cat",
+		"image": map[string]any{"bytesBase64Encoded": "aW1hZ2U="},
+	}, req.Instances[0].GetStructValue().AsMap())
+	assert.Equal(t, map[string]any{"dimension": 128.0}, req.Parameters.GetStructValue().AsMap())
+
+	_, image, err = llm.CreateMultimodalEmbedding(context.Background(), "", []byte("image"))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.8, 0.6}, image)
+	<-fake.requests
+
+	_, _, err = llm.CreateMultimodalEmbedding(context.Background(), "", nil)
+	require.ErrorIs(t, err, ErrEmptyInput)
+}
@@ -1,6 +1,7 @@
 package vertexai
 
 import (
+	"net/http"
 	"os"
 	"sync"
 
@@ -22,6 +23,9 @@ var (
 type options struct {
 	projectID     string
 	clientOptions []option.ClientOption
+
+	embeddingModel      string
+	embeddingDimensions int
 }
 
 // Option is a function that can be passed to NewClient to configure options.
@@ -62,6 +66,39 @@ func WithCredentialsJSON(json []byte) Option {
 	return convertByteArrayOption(option.WithCredentialsJSON)(json)
 }
 
+// WithHTTPClient allows setting a custom HTTP client. The client is used as
+// is, it must authenticate the requests, e.g. with a transport of
+// golang.org/x/oauth2/google wrapping an instrumented transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *options) {
+		opts.clientOptions = append(opts.clientOptions, option.WithHTTPClient(client))
+	}
+}
+
+// WithClientOptions passes the options to the underlying Vertex AI client,
+// e.g. option.WithEndpoint for a regional endpoint.
+func WithClientOptions(clientOptions ...option.ClientOption) Option {
+	return func(opts *options) {
+		opts.clientOptions = append(opts.clientOptions, clientOptions...)
+	}
+}
+
+// WithEmbeddingModel sets the model of the embeddings, e.g.
+// text-multilingual-embedding-002, text-embedding-005 if not set.
+func WithEmbeddingModel(model string) Option {
+	return func(opts *options) {
+		opts.embeddingModel = model
+	}
+}
+
+// WithEmbeddingDimensions shortens the embeddings to the number of
+// dimensions, which take less space in the vector store.
+func WithEmbeddingDimensions(dimensions int) Option {
+	return func(opts *options) {
+		opts.embeddingDimensions = dimensions
+	}
+}
+
 func convertStringOption(fopt func(string) option.ClientOption) func(string) Option {
 	return func(param string) Option {
 		return func(opts *options) {
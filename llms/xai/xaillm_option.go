@@ -0,0 +1,59 @@
+package xai
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/internal/openaicompat"
+)
+
+const (
+	tokenEnvVarName = "XAI_API_KEY" //nolint:gosec
+	defaultBaseURL  = "https://api.x.ai/v1"
+	defaultModel    = "grok-3"
+)
+
+type options struct {
+	token       string
+	model       string
+	baseURL     string
+	httpClient  openaicompat.Doer
+	rateLimiter *llms.RateLimiter
+}
+
+type Option func(*options)
+
+// WithToken passes the xAI API key to the client. If not set, the key is
+// read from the XAI_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel passes the Grok model to the client. Defaults to "grok-3".
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL passes the base url of the xAI API to the client.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client.
+func WithHTTPClient(client openaicompat.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before each request. Share the
+// limiter between the clients of the same account.
+func WithRateLimiter(limiter *llms.RateLimiter) Option {
+	return func(opts *options) {
+		opts.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,97 @@
+package xai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatReasoning(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{
+			"id": "1",
+			"model": "grok-3-mini",
+			"choices": [{"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."}, "finish_reason": "stop"}],
+			"usage": {
+				"prompt_tokens": 10, "completion_tokens": 12, "total_tokens": 22,
+				"prompt_tokens_details": {"cached_tokens": 6},
+				"completion_tokens_details": {"reasoning_tokens": 11}
+			}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	chat, err := NewChat(WithToken("token"), WithBaseURL(server.URL), WithModel("grok-3-mini"))
+	require.NoError(t, err)
+
+	generations, err := chat.Generate(context.Background(), [][]schema.ChatMessage{{
+		schema.HumanChatMessage{Content: "2+2?"},
+	}}, llms.WithReasoningEffort(llms.ReasoningEffortLow), llms.WithSeed(7))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "4", generations[0].Text)
+	assert.Equal(t, "2 plus 2 is 4.", generations[0].GenerationInfo[llms.ReasoningKey])
+	assert.Equal(t, llms.Usage{
+		PromptTokens: 10, CompletionTokens: 12, TotalTokens: 22, CachedTokens: 6, ReasoningTokens: 11,
+	}, generations[0].Usage)
+
+	assert.Equal(t, "grok-3-mini", got["model"])
+	assert.Equal(t, "low", got["reasoning_effort"])
+	assert.Equal(t, float64(7), got["seed"])
+}
+
+func TestLLMStreaming(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"role":"assistant","reasoning_content":"Thinking."}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{"content":"Hi"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"delta":{},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithToken("token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	generations, err := llm.Generate(context.Background(), []string{"Hi"},
+		llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "Hi", generations[0].Text)
+	assert.Equal(t, "Thinking.", generations[0].GenerationInfo[llms.ReasoningKey])
+	assert.Equal(t, []llms.StreamChunk{{Reasoning: "Thinking."}, {Content: "Hi"}, {FinishReason: "stop"}}, chunks)
+	assert.Equal(t, 5, generations[0].Usage.TotalTokens)
+
+	assert.Equal(t, "grok-3", got["model"])
+	assert.Equal(t, map[string]any{"include_usage": true}, got["stream_options"])
+}
+
+func TestMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingToken)
+}
@@ -0,0 +1,139 @@
+package runnable
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Prompt returns a runnable formatting the prompt with the input values.
+func Prompt(prompt prompts.FormatPrompter) Runnable[map[string]any, schema.PromptValue] { //nolint:ireturn
+	return Lambda(func(_ context.Context, values map[string]any) (schema.PromptValue, error) {
+		return prompt.FormatPrompt(values)
+	})
+}
+
+// Parser returns a runnable parsing the text with the output parser.
+func Parser[T any](parser schema.OutputParser[T]) Runnable[string, T] { //nolint:ireturn
+	return Lambda(func(_ context.Context, text string) (T, error) {
+		return parser.Parse(text)
+	})
+}
+
+// Retriever returns a runnable returning the documents relevant to the query.
+func Retriever(retriever schema.Retriever) Runnable[string, []schema.Document] { //nolint:ireturn
+	return Lambda(retriever.GetRelevantDocuments)
+}
+
+type llm struct {
+	model   llms.LanguageModel
+	options []llms.CallOption
+}
+
+// LLM returns a runnable generating the text of the prompt with the model and
+// the options. The batches are generated by one call of the model, and the
+// streams stream the chunks of the text generated.
+func LLM(model llms.LanguageModel, options ...llms.CallOption) Runnable[schema.PromptValue, string] { //nolint:ireturn
+	return llm{model: model, options: options}
+}
+
+func (l llm) Invoke(ctx context.Context, prompt schema.PromptValue) (string, error) {
+	texts, err := l.generate(ctx, []schema.PromptValue{prompt}, l.options)
+	if err != nil {
+		return "", err
+	}
+	return texts[0], nil
+}
+
+func (l llm) Batch(ctx context.Context, prompts []schema.PromptValue) ([]string, error) {
+	if len(prompts) == 0 {
+		return []string{}, nil
+	}
+	return l.generate(ctx, prompts, l.options)
+}
+
+func (l llm) Stream(ctx context.Context, prompt schema.PromptValue, fn StreamFunc[string]) error {
+	streamed := false
+	options := append(l.options[:len(l.options):len(l.options)], llms.WithStreamingFunc(
+		func(ctx context.Context, chunk []byte) error {
+			streamed = true
+			return fn(ctx, string(chunk))
+		},
+	))
+	texts, err := l.generate(ctx, []schema.PromptValue{prompt}, options)
+	if err != nil {
+		return err
+	}
+	// The models without streaming produce the text as one chunk.
+	if !streamed {
+		return fn(ctx, texts[0])
+	}
+	return nil
+}
+
+// generate returns the texts generated for the prompts.
+func (l llm) generate(ctx context.Context, prompts []schema.PromptValue, options []llms.CallOption) ([]string, error) { //nolint:lll
+	result, err := l.model.GeneratePrompt(ctx, prompts, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Generations) != len(prompts) {
+		return nil, fmt.Errorf("%w: %d generations for %d prompts", ErrInvalidBatch, len(result.Generations), len(prompts))
+	}
+	texts := make([]string, 0, len(prompts))
+	for i, generations := range result.Generations {
+		if len(generations) == 0 || generations[0] == nil {
+			return nil, fmt.Errorf("%w: prompt %d", llms.ErrEmptyBatchResult, i)
+		}
+		texts = append(texts, generations[0].Text)
+	}
+	return texts, nil
+}
+
+type chain struct {
+	chain   chains.Chain
+	options []chains.ChainCallOption
+}
+
+// Chain returns a runnable calling the chain with the input values and the
+// options. The batches are applied concurrently, and the streams of the chains
+// with one output key stream the chunks of the text generated as maps of the
+// key.
+func Chain(c chains.Chain, options ...chains.ChainCallOption) Runnable[map[string]any, map[string]any] { //nolint:ireturn,lll
+	return chain{chain: c, options: options}
+}
+
+func (c chain) Invoke(ctx context.Context, values map[string]any) (map[string]any, error) {
+	return chains.Call(ctx, c.chain, values, c.options...)
+}
+
+func (c chain) Batch(ctx context.Context, inputs []map[string]any) ([]map[string]any, error) {
+	return chains.Apply(ctx, c.chain, inputs, _defaultBatchConcurrency, c.options...)
+}
+
+func (c chain) Stream(ctx context.Context, values map[string]any, fn StreamFunc[map[string]any]) error {
+	outputKeys := c.chain.GetOutputKeys()
+	if len(outputKeys) != 1 {
+		return stream(ctx, values, c.Invoke, fn)
+	}
+
+	streamed := false
+	options := append(c.options[:len(c.options):len(c.options)], chains.WithStreamingFunc(
+		func(ctx context.Context, chunk []byte) error {
+			streamed = true
+			return fn(ctx, map[string]any{outputKeys[0]: string(chunk)})
+		},
+	))
+	outputs, err := chains.Call(ctx, c.chain, values, options...)
+	if err != nil {
+		return err
+	}
+	if !streamed {
+		return fn(ctx, outputs)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package runnable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type pipe[In, Mid, Out any] struct {
+	first  Runnable[In, Mid]
+	second Runnable[Mid, Out]
+}
+
+// Pipe returns a runnable giving the output of the first runnable to the
+// second. The batches are batched by both runnables, and the streams stream
+// the chunks of the second runnable.
+func Pipe[In, Mid, Out any](first Runnable[In, Mid], second Runnable[Mid, Out]) Runnable[In, Out] { //nolint:ireturn
+	return pipe[In, Mid, Out]{first: first, second: second}
+}
+
+func (p pipe[In, Mid, Out]) Invoke(ctx context.Context, input In) (Out, error) {
+	mid, err := p.first.Invoke(ctx, input)
+	if err != nil {
+		var zero Out
+		return zero, err
+	}
+	return p.second.Invoke(ctx, mid)
+}
+
+func (p pipe[In, Mid, Out]) Batch(ctx context.Context, inputs []In) ([]Out, error) {
+	mids, err := p.first.Batch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return p.second.Batch(ctx, mids)
+}
+
+func (p pipe[In, Mid, Out]) Stream(ctx context.Context, input In, fn StreamFunc[Out]) error {
+	mid, err := p.first.Invoke(ctx, input)
+	if err != nil {
+		return err
+	}
+	return p.second.Stream(ctx, mid, fn)
+}
+
+type parallel[In any] struct {
+	keys      []string
+	runnables map[string]Runnable[In, any]
+}
+
+// Parallel returns a runnable invoking the runnables concurrently with the
+// same input and returning their outputs by key. The streams stream the
+// chunks of the runnables as maps of one key. The runnables of other output
+// types are adapted by Any.
+func Parallel[In any](runnables map[string]Runnable[In, any]) Runnable[In, map[string]any] { //nolint:ireturn
+	keys := make([]string, 0, len(runnables))
+	for key := range runnables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return parallel[In]{keys: keys, runnables: runnables}
+}
+
+func (p parallel[In]) Invoke(ctx context.Context, input In) (map[string]any, error) {
+	outputs := make(map[string]any, len(p.keys))
+	var mu sync.Mutex
+	err := p.each(ctx, func(ctx context.Context, key string, r Runnable[In, any]) error {
+		output, err := r.Invoke(ctx, input)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		outputs[key] = output
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+func (p parallel[In]) Batch(ctx context.Context, inputs []In) ([]map[string]any, error) {
+	outputs := make([]map[string]any, len(inputs))
+	for i := range outputs {
+		outputs[i] = make(map[string]any, len(p.keys))
+	}
+	var mu sync.Mutex
+	err := p.each(ctx, func(ctx context.Context, key string, r Runnable[In, any]) error {
+		keyOutputs, err := r.Batch(ctx, inputs)
+		if err != nil {
+			return err
+		}
+		if len(keyOutputs) != len(inputs) {
+			return fmt.Errorf("%w: %d outputs for %d inputs", ErrInvalidBatch, len(keyOutputs), len(inputs))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for i, output := range keyOutputs {
+			outputs[i][key] = output
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+func (p parallel[In]) Stream(ctx context.Context, input In, fn StreamFunc[map[string]any]) error {
+	// The chunks are given to the func one at a time.
+	var mu sync.Mutex
+	return p.each(ctx, func(ctx context.Context, key string, r Runnable[In, any]) error {
+		return r.Stream(ctx, input, func(ctx context.Context, chunk any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(ctx, map[string]any{key: chunk})
+		})
+	})
+}
+
+// each calls the function with the runnables concurrently. The first error
+// cancels the other calls.
+func (p parallel[In]) each(ctx context.Context, call func(context.Context, string, Runnable[In, any]) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, key := range p.keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if err := call(ctx, key, p.runnables[key]); err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("%s: %w", key, err)
+					cancel()
+				})
+			}
+		}(key)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+type anyOutput[In, Out any] struct {
+	runnable Runnable[In, Out]
+}
+
+// Any adapts the runnable to a runnable of any output, e.g. for Parallel.
+func Any[In, Out any](r Runnable[In, Out]) Runnable[In, any] { //nolint:ireturn
+	return anyOutput[In, Out]{runnable: r}
+}
+
+func (a anyOutput[In, Out]) Invoke(ctx context.Context, input In) (any, error) {
+	return a.runnable.Invoke(ctx, input)
+}
+
+func (a anyOutput[In, Out]) Batch(ctx context.Context, inputs []In) ([]any, error) {
+	outputs, err := a.runnable.Batch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	anyOutputs := make([]any, len(outputs))
+	for i, output := range outputs {
+		anyOutputs[i] = output
+	}
+	return anyOutputs, nil
+}
+
+func (a anyOutput[In, Out]) Stream(ctx context.Context, input In, fn StreamFunc[any]) error {
+	return a.runnable.Stream(ctx, input, func(ctx context.Context, chunk Out) error {
+		return fn(ctx, chunk)
+	})
+}
+
+// Case is a branch of a Branch, taken when its condition is true.
+type Case[In, Out any] struct {
+	Condition func(ctx context.Context, input In) (bool, error)
+	Runnable  Runnable[In, Out]
+}
+
+type branch[In, Out any] struct {
+	defaultRunnable Runnable[In, Out]
+	cases           []Case[In, Out]
+}
+
+// Branch returns a runnable invoking the runnable of the first case whose
+// condition is true for the input, or the default runnable.
+func Branch[In, Out any](defaultRunnable Runnable[In, Out], cases ...Case[In, Out]) Runnable[In, Out] { //nolint:ireturn
+	return branch[In, Out]{defaultRunnable: defaultRunnable, cases: cases}
+}
+
+func (b branch[In, Out]) Invoke(ctx context.Context, input In) (Out, error) {
+	r, err := b.selectRunnable(ctx, input)
+	if err != nil {
+		var zero Out
+		return zero, err
+	}
+	return r.Invoke(ctx, input)
+}
+
+// Batch invokes the inputs concurrently, as they may take different branches.
+func (b branch[In, Out]) Batch(ctx context.Context, inputs []In) ([]Out, error) {
+	return batch(ctx, inputs, b.Invoke)
+}
+
+func (b branch[In, Out]) Stream(ctx context.Context, input In, fn StreamFunc[Out]) error {
+	r, err := b.selectRunnable(ctx, input)
+	if err != nil {
+		return err
+	}
+	return r.Stream(ctx, input, fn)
+}
+
+func (b branch[In, Out]) selectRunnable(ctx context.Context, input In) (Runnable[In, Out], error) { //nolint:ireturn
+	for _, c := range b.cases {
+		ok, err := c.Condition(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return c.Runnable, nil
+		}
+	}
+	return b.defaultRunnable, nil
+}
@@ -0,0 +1,17 @@
+// Package runnable contains a generic interface for the units of work of the
+// LLM applications, the runnables, and combinators composing them
+// declaratively, in the manner of the LangChain Expression Language.
+//
+// The prompts, the models, the output parsers, the retrievers and the chains
+// are adapted as runnables by Prompt, LLM, Parser, Retriever and Chain, and
+// composed with Pipe, Parallel, Branch, WithFallbacks and WithRetry. The
+// composed runnables propagate the batches and the streams through the graph:
+// a batch is batched by each step, and a stream streams the chunks of the last
+// step, e.g. the tokens of a model.
+//
+//	chain := runnable.Pipe(
+//		runnable.Pipe(runnable.Prompt(prompt), runnable.LLM(llm)),
+//		runnable.Parser[[]string](outputparser.NewCommaSeparatedList()),
+//	)
+//	colors, err := chain.Invoke(ctx, map[string]any{"subject": "the colors of the rainbow"})
+package runnable
@@ -0,0 +1,116 @@
+package runnable
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+type fallbacks[In, Out any] struct {
+	runnables []Runnable[In, Out]
+}
+
+// WithFallbacks returns a runnable invoking the fallbacks in order when the
+// runnable fails, returning the output of the first one succeeding, or the
+// errors of all of them. A stream falls back only until its first chunk, as
+// the chunks streamed cannot be taken back.
+func WithFallbacks[In, Out any](r Runnable[In, Out], fallbackRunnables ...Runnable[In, Out]) Runnable[In, Out] { //nolint:ireturn,lll
+	return fallbacks[In, Out]{runnables: append([]Runnable[In, Out]{r}, fallbackRunnables...)}
+}
+
+func (f fallbacks[In, Out]) Invoke(ctx context.Context, input In) (Out, error) {
+	var errs []error
+	for _, r := range f.runnables {
+		output, err := r.Invoke(ctx, input)
+		if err == nil {
+			return output, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	var zero Out
+	return zero, errors.Join(errs...)
+}
+
+// Batch falls back for the whole batch, so that the fallbacks batch the
+// inputs too.
+func (f fallbacks[In, Out]) Batch(ctx context.Context, inputs []In) ([]Out, error) {
+	var errs []error
+	for _, r := range f.runnables {
+		outputs, err := r.Batch(ctx, inputs)
+		if err == nil {
+			return outputs, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (f fallbacks[In, Out]) Stream(ctx context.Context, input In, fn StreamFunc[Out]) error {
+	var errs []error
+	for _, r := range f.runnables {
+		streamed := false
+		err := r.Stream(ctx, input, func(ctx context.Context, chunk Out) error {
+			streamed = true
+			return fn(ctx, chunk)
+		})
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if streamed || ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type retry[In, Out any] struct {
+	runnable Runnable[In, Out]
+	policy   llms.RetryPolicy
+}
+
+// WithRetry returns a runnable retrying the failures of the runnable with the
+// policy, e.g. llms.DefaultRetryPolicy(), whose IsRetryable reports the
+// errors retried. A stream is retried only until its first chunk.
+func WithRetry[In, Out any](r Runnable[In, Out], policy llms.RetryPolicy) Runnable[In, Out] { //nolint:ireturn
+	return retry[In, Out]{runnable: r, policy: policy}
+}
+
+func (r retry[In, Out]) Invoke(ctx context.Context, input In) (Out, error) {
+	return llms.Retry(ctx, r.policy, func() (Out, error) {
+		return r.runnable.Invoke(ctx, input)
+	})
+}
+
+func (r retry[In, Out]) Batch(ctx context.Context, inputs []In) ([]Out, error) {
+	return llms.Retry(ctx, r.policy, func() ([]Out, error) {
+		return r.runnable.Batch(ctx, inputs)
+	})
+}
+
+func (r retry[In, Out]) Stream(ctx context.Context, input In, fn StreamFunc[Out]) error {
+	streamed := false
+	policy := r.policy
+	isRetryable := policy.IsRetryable
+	if isRetryable == nil {
+		isRetryable = llms.IsRetryableError
+	}
+	policy.IsRetryable = func(err error) bool {
+		return !streamed && isRetryable(err)
+	}
+
+	_, err := llms.Retry(ctx, policy, func() (struct{}, error) {
+		return struct{}{}, r.runnable.Stream(ctx, input, func(ctx context.Context, chunk Out) error {
+			streamed = true
+			return fn(ctx, chunk)
+		})
+	})
+	return err
+}
@@ -0,0 +1,129 @@
+package runnable
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// _defaultBatchConcurrency is the number of inputs of a batch invoked at once
+// by the runnables without a batch of their own.
+const _defaultBatchConcurrency = 5
+
+// ErrInvalidBatch is returned when a batch of a runnable does not return an
+// output per input.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// StreamFunc is called with the chunks of the output of a runnable streamed.
+// An error stops the stream.
+type StreamFunc[Out any] func(ctx context.Context, chunk Out) error
+
+// Runnable is a unit of work transforming an input into an output, composed
+// with the other runnables by the combinators of the package.
+type Runnable[In, Out any] interface {
+	// Invoke transforms the input into the output.
+	Invoke(ctx context.Context, input In) (Out, error)
+	// Batch transforms the inputs into the outputs, in the order of the
+	// inputs. The first error fails the batch.
+	Batch(ctx context.Context, inputs []In) ([]Out, error)
+	// Stream transforms the input, calling the func with the chunks of the
+	// output as they are produced. The runnables that do not stream produce
+	// their output as one chunk.
+	Stream(ctx context.Context, input In, fn StreamFunc[Out]) error
+}
+
+type lambda[In, Out any] struct {
+	fn func(ctx context.Context, input In) (Out, error)
+}
+
+// Lambda returns a runnable calling the function, batching the inputs
+// concurrently and streaming the output as one chunk.
+func Lambda[In, Out any](fn func(ctx context.Context, input In) (Out, error)) Runnable[In, Out] { //nolint:ireturn
+	return lambda[In, Out]{fn: fn}
+}
+
+func (l lambda[In, Out]) Invoke(ctx context.Context, input In) (Out, error) {
+	return l.fn(ctx, input)
+}
+
+func (l lambda[In, Out]) Batch(ctx context.Context, inputs []In) ([]Out, error) {
+	return batch(ctx, inputs, l.fn)
+}
+
+func (l lambda[In, Out]) Stream(ctx context.Context, input In, fn StreamFunc[Out]) error {
+	return stream(ctx, input, l.fn, fn)
+}
+
+// Passthrough returns a runnable returning its input, e.g. to pass the input
+// of a Parallel to one of its keys.
+func Passthrough[T any]() Runnable[T, T] { //nolint:ireturn
+	return Lambda(func(_ context.Context, input T) (T, error) {
+		return input, nil
+	})
+}
+
+// batch invokes the function with the inputs, up to _defaultBatchConcurrency
+// at once, and returns the outputs in the order of the inputs. The first error
+// cancels the other invocations.
+func batch[In, Out any](
+	ctx context.Context,
+	inputs []In,
+	invoke func(ctx context.Context, input In) (Out, error),
+) ([]Out, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outputs := make([]Out, len(inputs))
+	sem := make(chan struct{}, _defaultBatchConcurrency)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, input := range inputs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, input In) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			output, err := invoke(ctx, input)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			outputs[i] = output
+		}(i, input)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// stream invokes the function and streams its output as one chunk.
+func stream[In, Out any](
+	ctx context.Context,
+	input In,
+	invoke func(ctx context.Context, input In) (Out, error),
+	fn StreamFunc[Out],
+) error {
+	output, err := invoke(ctx, input)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, output)
+}
@@ -0,0 +1,225 @@
+package runnable
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var errTest = errors.New("boom")
+
+// streamingModel generates the prompts in upper case, streaming their words.
+type streamingModel struct {
+	calls atomic.Int32
+}
+
+func (m *streamingModel) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	m.calls.Add(1)
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	result := llms.LLMResult{}
+	for _, promptValue := range promptValues {
+		text := strings.ToUpper(promptValue.String())
+		if opts.StreamingFunc != nil {
+			for i, word := range strings.Fields(text) {
+				if i > 0 {
+					word = " " + word
+				}
+				if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+					return llms.LLMResult{}, err
+				}
+			}
+		}
+		result.Generations = append(result.Generations, []*llms.Generation{{Text: text}})
+	}
+	return result, nil
+}
+
+func (m *streamingModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func collect[In, Out any](t *testing.T, r Runnable[In, Out], input In) []Out {
+	t.Helper()
+
+	var chunks []Out
+	err := r.Stream(context.Background(), input, func(_ context.Context, chunk Out) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	return chunks
+}
+
+func TestPipe(t *testing.T) {
+	t.Parallel()
+
+	model := &streamingModel{}
+	chain := Pipe(
+		Pipe(
+			Prompt(prompts.NewPromptTemplate("list {{.subject}}", []string{"subject"})),
+			LLM(model),
+		),
+		Parser[[]string](outputparser.NewCommaSeparatedList()),
+	)
+
+	output, err := chain.Invoke(context.Background(), map[string]any{"subject": "red, green"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LIST RED", "GREEN"}, output)
+
+	// The batches are generated by one call of the model.
+	outputs, err := chain.Batch(context.Background(), []map[string]any{{"subject": "a, b"}, {"subject": "c"}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"LIST A", "B"}, {"LIST C"}}, outputs)
+	assert.Equal(t, int32(2), model.calls.Load())
+
+	// The streams stream the chunks of the model.
+	generate := Pipe(Prompt(prompts.NewPromptTemplate("say {{.text}}", []string{"text"})), LLM(model))
+	assert.Equal(t, []string{"SAY", " HELLO", " WORLD"}, collect(t, generate, map[string]any{"text": "hello world"}))
+}
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	upper := Lambda(func(_ context.Context, s string) (string, error) { return strings.ToUpper(s), nil })
+	length := Lambda(func(_ context.Context, s string) (int, error) { return len(s), nil })
+	r := Parallel(map[string]Runnable[string, any]{
+		"upper":  Any(upper),
+		"length": Any(length),
+		"input":  Any(Passthrough[string]()),
+	})
+
+	output, err := r.Invoke(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"upper": "FOO", "length": 3, "input": "foo"}, output)
+
+	outputs, err := r.Batch(context.Background(), []string{"a", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"upper": "A", "length": 1, "input": "a"},
+		{"upper": "BB", "length": 2, "input": "bb"},
+	}, outputs)
+
+	assert.ElementsMatch(t, []map[string]any{
+		{"upper": "FOO"}, {"length": 3}, {"input": "foo"},
+	}, collect(t, r, "foo"))
+
+	failing := Parallel(map[string]Runnable[string, any]{
+		"upper": Any(upper),
+		"fail":  Any(Lambda(func(context.Context, string) (string, error) { return "", errTest })),
+	})
+	_, err = failing.Invoke(context.Background(), "foo")
+	require.ErrorIs(t, err, errTest)
+}
+
+func TestBranch(t *testing.T) {
+	t.Parallel()
+
+	r := Branch(
+		Lambda(func(_ context.Context, n int) (string, error) { return "other", nil }),
+		Case[int, string]{
+			Condition: func(_ context.Context, n int) (bool, error) { return n < 0, nil },
+			Runnable:  Lambda(func(_ context.Context, n int) (string, error) { return "negative", nil }),
+		},
+		Case[int, string]{
+			Condition: func(_ context.Context, n int) (bool, error) { return n == 0, nil },
+			Runnable:  Lambda(func(_ context.Context, n int) (string, error) { return "zero", nil }),
+		},
+	)
+
+	outputs, err := r.Batch(context.Background(), []int{-1, 0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"negative", "zero", "other"}, outputs)
+	assert.Equal(t, []string{"zero"}, collect(t, r, 0))
+}
+
+func TestWithFallbacks(t *testing.T) {
+	t.Parallel()
+
+	failing := Lambda(func(context.Context, string) (string, error) { return "", errTest })
+	r := WithFallbacks(failing, failing, Lambda(func(_ context.Context, s string) (string, error) {
+		return "fallback " + s, nil
+	}))
+
+	output, err := r.Invoke(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "fallback foo", output)
+	assert.Equal(t, []string{"fallback foo"}, collect(t, r, "foo"))
+
+	_, err = WithFallbacks(failing, failing).Batch(context.Background(), []string{"foo"})
+	require.ErrorIs(t, err, errTest)
+
+	// The streams do not fall back once chunks were streamed.
+	var fallbackCalls atomic.Int32
+	fallback := Lambda(func(context.Context, schema.PromptValue) (string, error) {
+		fallbackCalls.Add(1)
+		return "fallback", nil
+	})
+	streaming := WithFallbacks(LLM(&failingStreamModel{}), fallback)
+	err = streaming.Stream(context.Background(), prompts.StringPromptValue("foo"), func(context.Context, string) error {
+		return nil
+	})
+	require.ErrorIs(t, err, errTest)
+	assert.Zero(t, fallbackCalls.Load())
+}
+
+// failingStreamModel streams a chunk, then fails.
+type failingStreamModel struct{}
+
+func (m *failingStreamModel) GeneratePrompt(ctx context.Context, _ []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte("partial")); err != nil {
+			return llms.LLMResult{}, err
+		}
+	}
+	return llms.LLMResult{}, errTest
+}
+
+func (m *failingStreamModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	flaky := Lambda(func(_ context.Context, s string) (string, error) {
+		if attempts.Add(1) < 3 {
+			return "", errTest
+		}
+		return s, nil
+	})
+	policy := llms.RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		IsRetryable:    func(err error) bool { return errors.Is(err, errTest) },
+	}
+
+	output, err := WithRetry(flaky, policy).Invoke(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", output)
+	assert.Equal(t, int32(3), attempts.Load())
+
+	policy.MaxRetries = 1
+	attempts.Store(0)
+	_, err = WithRetry(flaky, policy).Invoke(context.Background(), "foo")
+	require.ErrorIs(t, err, errTest)
+	assert.Equal(t, int32(2), attempts.Load())
+}
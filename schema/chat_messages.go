@@ -24,6 +24,8 @@ const (
 	ChatMessageTypeGeneric ChatMessageType = "generic"
 	// ChatMessageTypeFunction is a message sent by a function.
 	ChatMessageTypeFunction ChatMessageType = "function"
+	// ChatMessageTypeTool is a message sent by a tool.
+	ChatMessageTypeTool ChatMessageType = "tool"
 )
 
 // ChatMessage represents a message in a chat.
@@ -46,6 +48,7 @@ var (
 	_ ChatMessage = SystemChatMessage{}
 	_ ChatMessage = GenericChatMessage{}
 	_ ChatMessage = FunctionChatMessage{}
+	_ ChatMessage = ToolChatMessage{}
 )
 
 // AIChatMessage is a message sent by an AI.
@@ -55,6 +58,9 @@ type AIChatMessage struct {
 
 	// FunctionCall represents the model choosing to call a function.
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// ToolCalls represents the model choosing to call tools.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 func (m AIChatMessage) GetType() ChatMessageType { return ChatMessageTypeAI }
@@ -63,10 +69,68 @@ func (m AIChatMessage) GetContent() string       { return m.Content }
 // HumanChatMessage is a message sent by a human.
 type HumanChatMessage struct {
 	Content string
+
+	// Parts are multimodal parts of the message, e.g. images and files, sent
+	// after the text of the content.
+	Parts []ContentPart `json:"parts,omitempty"`
 }
 
 func (m HumanChatMessage) GetType() ChatMessageType { return ChatMessageTypeHuman }
-func (m HumanChatMessage) GetContent() string       { return m.Content }
+
+// GetContent returns the content followed by the text parts of the message.
+func (m HumanChatMessage) GetContent() string {
+	text := TextOfParts(m.Parts)
+	switch {
+	case text == "":
+		return m.Content
+	case m.Content == "":
+		return text
+	default:
+		return m.Content + "\n" + text
+	}
+}
+
+// humanChatMessageJSON is the JSON encoding of a HumanChatMessage, its parts
+// encoded with their type.
+type humanChatMessageJSON struct {
+	Content string
+	Parts   []json.RawMessage `json:"parts,omitempty"`
+}
+
+// MarshalJSON encodes the message with the type of each of its parts, e.g.
+// {"type": "image_url", "url": "https://example.com/cat.png"}.
+func (m HumanChatMessage) MarshalJSON() ([]byte, error) {
+	v := humanChatMessageJSON{Content: m.Content}
+	for _, part := range m.Parts {
+		data, err := marshalPart(part)
+		if err != nil {
+			return nil, err
+		}
+		v.Parts = append(v.Parts, data)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a message encoded by MarshalJSON.
+func (m *HumanChatMessage) UnmarshalJSON(data []byte) error {
+	var v humanChatMessageJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parts := make([]ContentPart, 0, len(v.Parts))
+	for _, data := range v.Parts {
+		part, err := unmarshalPart(data)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		parts = nil
+	}
+	*m = HumanChatMessage{Content: v.Content, Parts: parts}
+	return nil
+}
 
 // SystemChatMessage is a chat message representing information that should be instructions to the AI system.
 type SystemChatMessage struct {
@@ -103,6 +167,26 @@ func (m FunctionChatMessage) GetType() ChatMessageType { return ChatMessageTypeF
 func (m FunctionChatMessage) GetContent() string       { return m.Content }
 func (m FunctionChatMessage) GetName() string          { return m.Name }
 
+// ToolCall is a call to a tool requested by the model.
+type ToolCall struct {
+	// ID is the id of the tool call, used to match the result of the call.
+	ID string `json:"id"`
+	// Type is the type of the tool, "function" is the only supported type.
+	Type string `json:"type"`
+	// FunctionCall is the function to call.
+	FunctionCall *FunctionCall `json:"function,omitempty"`
+}
+
+// ToolChatMessage is a chat message representing the result of a tool call.
+type ToolChatMessage struct {
+	// ID is the id of the tool call this message is the result of.
+	ID      string `json:"tool_call_id"`
+	Content string `json:"content"`
+}
+
+func (m ToolChatMessage) GetType() ChatMessageType { return ChatMessageTypeTool }
+func (m ToolChatMessage) GetContent() string       { return m.Content }
+
 // ChatGeneration is the output of a single chat generation.
 type ChatGeneration struct {
 	Generation
@@ -131,6 +215,13 @@ func GetBufferString(messages []ChatMessage, humanPrefix string, aiPrefix string
 			}
 			msg = fmt.Sprintf("%s %s", msg, string(j))
 		}
+		if m, ok := m.(AIChatMessage); ok && len(m.ToolCalls) > 0 {
+			j, err := json.Marshal(m.ToolCalls)
+			if err != nil {
+				return "", err
+			}
+			msg = fmt.Sprintf("%s %s", msg, string(j))
+		}
 		result = append(result, msg)
 	}
 	return strings.Join(result, "\n"), nil
@@ -153,6 +244,8 @@ func getMessageRole(m ChatMessage, humanPrefix, aiPrefix string) (string, error)
 		role = cgm.Role
 	case ChatMessageTypeFunction:
 		role = "Function"
+	case ChatMessageTypeTool:
+		role = "Tool"
 	default:
 		return "", ErrUnexpectedChatMessageType
 	}
@@ -0,0 +1,151 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownContentPart is returned when a content part of a message can not
+// be encoded or decoded.
+var ErrUnknownContentPart = errors.New("unknown content part")
+
+// The types of the content parts in the JSON encoding of the messages.
+const (
+	contentPartText     = "text"
+	contentPartImageURL = "image_url"
+	contentPartBinary   = "binary"
+	contentPartFile     = "file"
+)
+
+// ContentPart is a part of the content of a multimodal message: a TextPart,
+// an ImageURLPart, a BinaryPart or a FilePart. The OpenAI, Anthropic and
+// Ollama models map the parts to their APIs. There is no mapping to the parts
+// of Gemini, as the vertexai package only has PaLM models.
+type ContentPart interface {
+	isContentPart()
+}
+
+// TextPart is a part of text.
+type TextPart struct {
+	Text string `json:"text"`
+}
+
+// ImageURLPart is an image referenced by its url.
+type ImageURLPart struct {
+	URL string `json:"url"`
+	// Detail is the resolution the model processes the image at, "low",
+	// "high" or "auto". Only supported by OpenAI.
+	Detail string `json:"detail,omitempty"`
+}
+
+// BinaryPart is inline binary data, typically an image, e.g. of the MIME type "image/png".
+type BinaryPart struct {
+	MIMEType string `json:"mime_type"`
+	Data     []byte `json:"data"`
+}
+
+// FilePart is a file, e.g. a PDF document.
+type FilePart struct {
+	Filename string `json:"filename"`
+	MIMEType string `json:"mime_type"`
+	Data     []byte `json:"data"`
+}
+
+func (TextPart) isContentPart()     {}
+func (ImageURLPart) isContentPart() {}
+func (BinaryPart) isContentPart()   {}
+func (FilePart) isContentPart()     {}
+
+// DataURL returns the data as a base64 encoded data url.
+func (p BinaryPart) DataURL() string {
+	return dataURL(p.MIMEType, p.Data)
+}
+
+// DataURL returns the file as a base64 encoded data url.
+func (p FilePart) DataURL() string {
+	return dataURL(p.MIMEType, p.Data)
+}
+
+func dataURL(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// TextOfParts returns the text parts joined by newlines.
+func TextOfParts(parts []ContentPart) string {
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if p, ok := part.(TextPart); ok && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// marshalPart encodes the part with its type, e.g. {"type": "text", "text":
+// "hello"}, so that it can be decoded by unmarshalPart.
+func marshalPart(part ContentPart) (json.RawMessage, error) {
+	switch p := part.(type) {
+	case TextPart:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			TextPart
+		}{contentPartText, p})
+	case ImageURLPart:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			ImageURLPart
+		}{contentPartImageURL, p})
+	case BinaryPart:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			BinaryPart
+		}{contentPartBinary, p})
+	case FilePart:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			FilePart
+		}{contentPartFile, p})
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownContentPart, part)
+	}
+}
+
+// unmarshalPart decodes a part encoded by marshalPart.
+func unmarshalPart(data []byte) (ContentPart, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var part ContentPart
+	var err error
+	switch header.Type {
+	case contentPartText:
+		var p TextPart
+		err = json.Unmarshal(data, &p)
+		part = p
+	case contentPartImageURL:
+		var p ImageURLPart
+		err = json.Unmarshal(data, &p)
+		part = p
+	case contentPartBinary:
+		var p BinaryPart
+		err = json.Unmarshal(data, &p)
+		part = p
+	case contentPartFile:
+		var p FilePart
+		err = json.Unmarshal(data, &p)
+		part = p
+	default:
+		return nil, fmt.Errorf("%w: type %q", ErrUnknownContentPart, header.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return part, nil
+}
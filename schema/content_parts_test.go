@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestHumanChatMessageParts(t *testing.T) {
+	t.Parallel()
+
+	msg := schema.HumanChatMessage{
+		Content: "Describe the images.",
+		Parts: []schema.ContentPart{
+			schema.ImageURLPart{URL: "https://example.com/cat.png"},
+			schema.TextPart{Text: "Be brief."},
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")},
+		},
+	}
+	assert.Equal(t, "Describe the images.\nBe brief.", msg.GetContent())
+	assert.Equal(t, "Be brief.", schema.HumanChatMessage{Parts: msg.Parts}.GetContent())
+	assert.Equal(t, "data:image/png;base64,cG5n", schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")}.DataURL())
+}
+
+func TestHumanChatMessageJSON(t *testing.T) {
+	t.Parallel()
+
+	msg := schema.HumanChatMessage{
+		Content: "Describe the images.",
+		Parts: []schema.ContentPart{
+			schema.TextPart{Text: "Be brief."},
+			schema.ImageURLPart{URL: "https://example.com/cat.png", Detail: "low"},
+			schema.BinaryPart{MIMEType: "image/png", Data: []byte("png")},
+			schema.FilePart{Filename: "cat.pdf", MIMEType: "application/pdf", Data: []byte("pdf")},
+		},
+	}
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Content": "Describe the images.",
+		"parts": [
+			{"type": "text", "text": "Be brief."},
+			{"type": "image_url", "url": "https://example.com/cat.png", "detail": "low"},
+			{"type": "binary", "mime_type": "image/png", "data": "cG5n"},
+			{"type": "file", "filename": "cat.pdf", "mime_type": "application/pdf", "data": "cGRm"}
+		]
+	}`, string(data))
+
+	var decoded schema.HumanChatMessage
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, msg, decoded)
+
+	data, err = json.Marshal(schema.HumanChatMessage{Content: "hello"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Content": "hello"}`, string(data))
+	decoded = schema.HumanChatMessage{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, schema.HumanChatMessage{Content: "hello"}, decoded)
+
+	err = json.Unmarshal([]byte(`{"parts": [{"type": "video"}]}`), &decoded)
+	require.ErrorIs(t, err, schema.ErrUnknownContentPart)
+	_, err = json.Marshal(schema.HumanChatMessage{Parts: []schema.ContentPart{&schema.TextPart{}}})
+	require.ErrorIs(t, err, schema.ErrUnknownContentPart)
+}
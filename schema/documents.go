@@ -4,4 +4,8 @@ package schema
 type Document struct {
 	PageContent string
 	Metadata    map[string]any
+	// Score is the similarity score of the document to the query of the
+	// vector store search returning it, the score compared to the score
+	// threshold of the search, 0 if not set.
+	Score float64
 }
@@ -5,6 +5,11 @@ type AgentAction struct {
 	Tool      string
 	ToolInput string
 	Log       string
+	// ToolID is the id of the tool call of models with native tool calling.
+	ToolID string
+	// Turn is the index of the model turn that planned the action, set by
+	// the executor. The tool calls of a turn share the same index.
+	Turn int
 }
 
 // AgentStep is a step of the agent.
@@ -8,3 +8,13 @@ type Tool interface {
 	Description() string
 	Call(context.Context, string) (string, error)
 }
+
+// Parameterized is a tool called with a JSON object of parameters by the
+// agents of models with native tool calling, instead of a text input.
+type Parameterized interface {
+	Tool
+	// Parameters returns the JSON schema of the parameters, e.g. a
+	// *jsonschema.Definition of the llms/jsonschema package. The tool is
+	// called with the JSON arguments of the model.
+	Parameters() any
+}
@@ -0,0 +1,273 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultDocumentsPerBatch = 100
+	_defaultMaxParallel       = 1
+)
+
+// BatchError is the error of a batch of documents failing to be added after
+// its retries.
+type BatchError struct {
+	// Start and End are the indices of the first document of the batch and
+	// of the document following its last document.
+	Start int
+	End   int
+	// IDs are the IDs of the documents of the batch.
+	IDs []string
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("add documents %d to %d: %v", e.Start, e.End, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned with the IDs of the documents when batches fail
+// with WithContinueOnError. The documents of the other batches are added.
+type PartialError struct {
+	// Errors are the errors of the failed batches, in the order of the
+	// documents.
+	Errors []*BatchError
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d batches failed, first: %v", len(e.Errors), e.Errors[0])
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Failed returns the indices of the documents of the failed batches, to add
+// them again.
+func (e *PartialError) Failed() []int {
+	var failed []int
+	for _, err := range e.Errors {
+		for i := err.Start; i < err.End; i++ {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// ProgressFunc reports the progress of AddDocumentsInBatches after each
+// batch: the number of documents added, the total number of documents and
+// the errors of the batches failed so far.
+type ProgressFunc func(done, total int, errs []error)
+
+type batchOptions struct {
+	documentsPerBatch int
+	maxParallel       int
+	retryPolicy       llms.RetryPolicy
+	progress          ProgressFunc
+	continueOnError   bool
+	options           []Option
+}
+
+// BatchOption is an option of AddDocumentsInBatches.
+type BatchOption func(*batchOptions)
+
+// WithDocumentsPerBatch sets the number of documents added per call of
+// AddDocuments, 100 by default.
+func WithDocumentsPerBatch(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.documentsPerBatch = n
+	}
+}
+
+// WithMaxParallel sets the maximum number of batches added concurrently, 1
+// by default.
+func WithMaxParallel(maxParallel int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxParallel = maxParallel
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the failed batches, the default
+// policy of the llms package by default. Unless the policy sets IsRetryable,
+// all the errors are retried but the cancellation of the context and the
+// errors of invalid options of this package, as the stores return errors of
+// their own.
+func WithRetryPolicy(policy llms.RetryPolicy) BatchOption {
+	return func(o *batchOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithProgress sets the function reporting the progress after each batch.
+// The function is not called concurrently, but blocks the workers while it
+// runs.
+func WithProgress(progress ProgressFunc) BatchOption {
+	return func(o *batchOptions) {
+		o.progress = progress
+	}
+}
+
+// WithContinueOnError adds all the batches even if some fail after their
+// retries, returning a *PartialError listing the failed batches, instead of
+// cancelling the others at the first failure.
+func WithContinueOnError() BatchOption {
+	return func(o *batchOptions) {
+		o.continueOnError = true
+	}
+}
+
+// WithAddOptions sets the options of the calls of AddDocuments, e.g. the name
+// space or WithDeterministicIDs.
+func WithAddOptions(options ...Option) BatchOption {
+	return func(o *batchOptions) {
+		o.options = options
+	}
+}
+
+// AddDocumentsInBatches splits the documents into batches, adds each batch
+// with a call of AddDocuments by a pool of workers, retrying the failed
+// calls, and returns the IDs of the documents. The IDs are set before the
+// first call, with WithIDs, so that the retries of a batch partially added
+// replace its documents rather than duplicate them. The first batch failing
+// after its retries cancels the others, unless WithContinueOnError is set.
+func AddDocumentsInBatches(ctx context.Context, store VectorStore, docs []schema.Document, opts ...BatchOption) ([]string, error) { //nolint:lll
+	o := batchOptions{
+		documentsPerBatch: _defaultDocumentsPerBatch,
+		maxParallel:       _defaultMaxParallel,
+		retryPolicy:       llms.DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.retryPolicy.IsRetryable == nil {
+		o.retryPolicy.IsRetryable = isRetryableBatchError
+	}
+	if o.documentsPerBatch <= 0 {
+		o.documentsPerBatch = _defaultDocumentsPerBatch
+	}
+	if o.maxParallel <= 0 {
+		o.maxParallel = _defaultMaxParallel
+	}
+
+	addOpts := Options{}
+	for _, opt := range o.options {
+		opt(&addOpts)
+	}
+	ids, err := DocumentIDs(addOpts, docs)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+		errs []*BatchError
+	)
+	// report records the result of a batch, cancelling the other batches if
+	// it failed, and reports the progress.
+	report := func(start, end int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, &BatchError{Start: start, End: end, IDs: ids[start:end], Err: err})
+			if !o.continueOnError {
+				cancel()
+			}
+		} else {
+			done += end - start
+		}
+		if o.progress != nil {
+			progressErrs := make([]error, 0, len(errs))
+			for _, err := range errs {
+				progressErrs = append(progressErrs, err)
+			}
+			o.progress(done, len(docs), progressErrs)
+		}
+	}
+
+	type batch struct{ start, end int }
+	batches := make(chan batch)
+	workers := (len(docs) + o.documentsPerBatch - 1) / o.documentsPerBatch
+	if workers > o.maxParallel {
+		workers = o.maxParallel
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				// The batches sent before a failure cancelled the others are
+				// skipped.
+				if ctx.Err() != nil {
+					continue
+				}
+				options := append(o.options[:len(o.options):len(o.options)], WithIDs(ids[b.start:b.end]...))
+				_, err := llms.Retry(ctx, o.retryPolicy, func() ([]string, error) {
+					return store.AddDocuments(ctx, docs[b.start:b.end], options...)
+				})
+				report(b.start, b.end, err)
+			}
+		}()
+	}
+
+	for start := 0; start < len(docs) && ctx.Err() == nil; start += o.documentsPerBatch {
+		end := start + o.documentsPerBatch
+		if end > len(docs) {
+			end = len(docs)
+		}
+		select {
+		case batches <- batch{start: start, end: end}:
+		case <-ctx.Done():
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	switch {
+	case len(errs) > 0 && !o.continueOnError:
+		return nil, errs[0]
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case len(errs) > 0:
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Start < errs[j].Start })
+		return ids, &PartialError{Errors: errs}
+	}
+	return ids, nil
+}
+
+// isRetryableBatchError reports whether the error of a batch is worth
+// retrying: all the errors but the cancellation of the context and the
+// errors of invalid options.
+func isRetryableBatchError(err error) bool {
+	for _, permanent := range []error{
+		context.Canceled,
+		context.DeadlineExceeded,
+		ErrWrongNumberIDs,
+		ErrSparseNotSupported,
+	} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return err != nil
+}
@@ -0,0 +1,106 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var errBatch = errors.New("batch failed")
+
+// batchStore keeps the documents added by ID, failing to add the batches
+// with a document whose content has failures left.
+type batchStore struct {
+	fakeStore
+	mu       sync.Mutex
+	docs     map[string]schema.Document
+	failures map[string]int
+	calls    int
+}
+
+func (s *batchStore) AddDocuments(_ context.Context, docs []schema.Document, options ...Option) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	ids, err := DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	for i, doc := range docs {
+		// The documents preceding the failure are added.
+		if s.failures[doc.PageContent] > 0 {
+			s.failures[doc.PageContent]--
+			return nil, errBatch
+		}
+		s.docs[ids[i]] = doc
+	}
+	return ids, nil
+}
+
+func newBatchStore(failures map[string]int) *batchStore {
+	return &batchStore{docs: map[string]schema.Document{}, failures: failures}
+}
+
+func TestAddDocumentsInBatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	docs := []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"}, {PageContent: "d"}, {PageContent: "e"},
+	}
+	retries := WithRetryPolicy(llms.RetryPolicy{MaxRetries: 1})
+
+	// The batch failing once is retried without duplicating its documents.
+	store := newBatchStore(map[string]int{"d": 1})
+	var progress []int
+	ids, err := AddDocumentsInBatches(ctx, store, docs, WithDocumentsPerBatch(2), retries,
+		WithProgress(func(done, total int, errs []error) {
+			assert.Equal(t, 5, total)
+			assert.Empty(t, errs)
+			progress = append(progress, done)
+		}))
+	require.NoError(t, err)
+	require.Len(t, ids, 5)
+	assert.Len(t, store.docs, 5)
+	assert.Equal(t, 4, store.calls)
+	assert.Equal(t, []int{2, 4, 5}, progress)
+
+	// The other batches are added despite the failure.
+	store = newBatchStore(map[string]int{"c": 2})
+	ids, err = AddDocumentsInBatches(ctx, store, docs, WithDocumentsPerBatch(2), WithMaxParallel(3), retries,
+		WithContinueOnError(), WithAddOptions(WithDeterministicIDs(ContentHash)))
+	var partial *PartialError
+	require.ErrorAs(t, err, &partial)
+	require.ErrorIs(t, err, errBatch)
+	require.Len(t, ids, 5)
+	require.Len(t, partial.Errors, 1)
+	assert.Equal(t, 2, partial.Errors[0].Start)
+	assert.Equal(t, 4, partial.Errors[0].End)
+	assert.Equal(t, ids[2:4], partial.Errors[0].IDs)
+	assert.Equal(t, []int{2, 3}, partial.Failed())
+	assert.Len(t, store.docs, 3)
+	assert.NotContains(t, store.docs, ids[2])
+
+	// The first failure fails the ingestion.
+	store = newBatchStore(map[string]int{"a": 2})
+	_, err = AddDocumentsInBatches(ctx, store, docs, WithDocumentsPerBatch(2), retries)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 0, batchErr.Start)
+
+	// The invalid options are not retried.
+	store = newBatchStore(nil)
+	_, err = AddDocumentsInBatches(ctx, store, docs, WithAddOptions(WithIDs("a")))
+	require.ErrorIs(t, err, ErrWrongNumberIDs)
+	assert.Zero(t, store.calls)
+}
@@ -4,9 +4,28 @@ and querying documents as vector embeddings.
 
 The main components of this package are:
 
-- VectorStore interface: a common interface for saving and querying vector embeddings of documents.
-- Options: a set of options for similarity search and document addition.
-- Retriever: a retriever for vector stores that implements the schema.Retriever interface.
+- VectorStore interface: a common interface for saving, querying and deleting vector embeddings of
+  documents, identified by the IDs returned by AddDocuments or set with WithIDs.
+- Options: a set of options for similarity search and document addition, such as
+  WithSparseEmbedder and WithAlpha for hybrid dense and sparse searches, and
+  WithFilters for the metadata filters built with the filters package, WithNameSpace
+  for keeping several corpora in one store, and WithTenant for keeping the documents of
+  several customers apart.
+- HybridSearch: hybrid searches fusing dense and keyword or sparse searches, natively with the
+  stores implementing HybridSearcher and client-side with the other stores.
+- AddDocumentsInBatches: the ingestion of many documents in concurrent batches, retried on failure,
+  reporting the batches failing with a PartialError.
+- WithTTL and ExpiringStore: the expiry of the documents, native in Redis and lazy in the other
+  stores.
+- VectorSearcher and SearchPage: the searches by the vectors of the queries already embedded, and
+  the pages of results of the stores supporting WithOffset.
+- Export and Import: the backups and migrations of the documents of the stores implementing Exporter
+  as JSON lines of records with their vectors, imported in any store without embedding them again.
+- IndexManager: an optional interface of the stores creating, deleting, describing and counting
+  the documents of their index.
+- Retriever: a retriever for vector stores that implements the schema.Retriever interface, and
+  the self-query retriever of the selfquery package, generating the metadata filters of the queries
+  with a chat model.
 
 The package provides a flexible way to handle different types of vector stores
 by using the VectorStore interface as an abstraction.
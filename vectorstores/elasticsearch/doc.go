@@ -0,0 +1,3 @@
+// Package elasticsearch contains an implementation of the vectorStore
+// interface using the kNN search of Elasticsearch or OpenSearch.
+package elasticsearch
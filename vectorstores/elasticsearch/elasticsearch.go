@@ -0,0 +1,528 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	ErrInvalidAlpha = errors.New("alpha must be between 0 and 1")
+)
+
+// _openSearchSpaceTypes are the space types of OpenSearch of the
+// similarities.
+var _openSearchSpaceTypes = map[Similarity]string{ //nolint:gochecknoglobals
+	SimilarityCosine:     "cosinesimil",
+	SimilarityDotProduct: "innerproduct",
+	SimilarityL2:         "l2",
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Store is a wrapper around an index of Elasticsearch or OpenSearch holding
+// the documents with their content, their metadata and their vector.
+type Store struct {
+	embedder   embeddings.Embedder
+	httpClient Doer
+
+	url               string
+	engine            Engine
+	apiKey            string
+	username          string
+	password          string
+	indexName         string
+	nameSpace         string
+	vectorDimensions  int
+	similarity        Similarity
+	rankConstant      int
+	skipIndexCreation bool
+}
+
+var (
+	_ vectorstores.VectorStore    = Store{}
+	_ vectorstores.HybridSearcher = Store{}
+	_ vectorstores.IndexManager   = Store{}
+)
+
+// New creates a new Store with options. The index name, the embedder and,
+// unless the index creation is skipped, the dimensions of its vectors must be
+// set. The index is created with the kNN mappings of the engine if it does
+// not exist.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if !s.skipIndexCreation {
+		if err := s.CreateIndex(ctx); err != nil {
+			return Store{}, err
+		}
+	}
+
+	return s, nil
+}
+
+// CreateIndex creates the index with the kNN mappings of the engine if it
+// does not exist.
+func (s Store) CreateIndex(ctx context.Context) error {
+	exists, err := s.indexExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+	return s.createIndex(ctx)
+}
+
+// DeleteIndex deletes the index with its documents.
+func (s Store) DeleteIndex(ctx context.Context) error {
+	_, err := s.doRequest(ctx, "deleting index", http.MethodDelete, "/"+s.indexName, "", nil, nil)
+	return err
+}
+
+// DescribeIndex returns the name of the index with the dimensions and the
+// similarity of the mapping of its vectors, the similarities of OpenSearch
+// being named as those of Elasticsearch.
+func (s Store) DescribeIndex(ctx context.Context) (vectorstores.IndexInfo, error) {
+	var res mappingResponse
+	status, err := s.doRequest(ctx, "describing index", http.MethodGet, "/"+s.indexName+"/_mapping", "", nil, &res)
+	if status == http.StatusNotFound {
+		return vectorstores.IndexInfo{}, fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.indexName)
+	}
+	if err != nil {
+		return vectorstores.IndexInfo{}, err
+	}
+	info := vectorstores.IndexInfo{Name: s.indexName}
+	// The mapping is keyed by the name of the index, which may differ from
+	// an alias.
+	for _, index := range res {
+		vector := index.Mappings.Properties.Vector
+		info.Dimensions = vector.Dims
+		info.Metric = vector.Similarity
+		if s.engine == EngineOpenSearch {
+			info.Dimensions = vector.Dimension
+			info.Metric = vector.Method.SpaceType
+			for similarity, spaceType := range _openSearchSpaceTypes {
+				if spaceType == vector.Method.SpaceType {
+					info.Metric = string(similarity)
+				}
+			}
+		}
+	}
+	return info, nil
+}
+
+// Stats returns the number of documents of the index.
+func (s Store) Stats(ctx context.Context) (vectorstores.IndexStats, error) {
+	var res struct {
+		Count int `json:"count"`
+	}
+	status, err := s.doRequest(ctx, "counting documents", http.MethodGet, "/"+s.indexName+"/_count", "", nil, &res)
+	if status == http.StatusNotFound {
+		return vectorstores.IndexStats{}, fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.indexName)
+	}
+	if err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	return vectorstores.IndexStats{NumDocuments: res.Count}, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and indexes them in the name space with a bulk request, replacing the
+// documents with the same IDs.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	sources := make([]source, 0, len(docs))
+	for i, doc := range docs {
+		metadata := doc.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		sources = append(sources, source{
+			Content:   texts[i],
+			Metadata:  metadata,
+			NameSpace: nameSpace,
+			Vector:    vectors[i],
+		})
+	}
+	if err := s.bulkIndex(ctx, ids, sources); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete deletes the documents of the name space with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+	return s.deleteByQuery(ctx, s.scope(nameSpace, map[string]any{"ids": map[string]any{"values": ids}}))
+}
+
+// DeleteByFilter deletes the documents of the name space matching the
+// filter, a query of the query DSL or a filters.Filter as in
+// SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	filterQuery, err := getFilters(filter)
+	if err != nil {
+		return err
+	}
+	return s.deleteByQuery(ctx, s.scope(s.getNameSpace(s.getOptions(options...)), filterQuery))
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a query of
+// the query DSL filtering the documents, e.g.
+// map[string]any{"term": map[string]any{"metadata.country": "France"}}, or a
+// filters.Filter on the metadata, translated to the query DSL. The
+// scores are normalized between 0 and 1 for the score threshold, e.g. to
+// (1 + cosine similarity) / 2 for the cosine similarity. Only the documents
+// of the name space are searched.
+//
+// With vectorstores.WithAlpha, the search is a hybrid search fusing the
+// rankings of the kNN search and of a BM25 search on the content with
+// reciprocal rank fusion, alpha weighting the kNN ranking and 1 - alpha the
+// BM25 ranking. The score threshold filters the hits of the kNN search before
+// the fusion, the BM25 hits are not filtered. The scores of the documents are
+// then their fused scores rather than normalized scores.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Alpha != nil && (*opts.Alpha < 0 || *opts.Alpha > 1) {
+		return nil, ErrInvalidAlpha
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	filterQuery, err := getFilters(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := s.scope(s.getNameSpace(opts), filterQuery)
+	var docs []schema.Document
+	if opts.Alpha != nil {
+		docs, err = s.hybridSearch(ctx, query, vector, fetchK, filter, *opts.Alpha, vectorstores.FusionRRF,
+			scoreThreshold)
+		if err != nil {
+			return nil, err
+		}
+		return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+	}
+
+	hits, err := s.search(ctx, s.knnBody(vector, fetchK, filter))
+	if err != nil {
+		return nil, err
+	}
+
+	docs = make([]schema.Document, 0, len(hits))
+	for _, h := range hits {
+		score := s.normalizeScore(h.Score)
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && score < scoreThreshold {
+			continue
+		}
+		docs = append(docs, newDocument(h, score))
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// HybridSearch fuses the kNN search of the vector of the query, embedded from
+// its text if not set, and the BM25 search of its text on the content, on
+// twice as many documents as requested. The fusion is reciprocal rank fusion
+// by default, or the weighted sum of the normalized kNN scores and the BM25
+// scores normalized by their minimum and maximum. The score threshold
+// filters the hits of the kNN search before the fusion, and the scores of the
+// documents are their fused scores. The filters are those of
+// SimilaritySearch, the sparse vectors are not supported.
+func (s Store) HybridSearch(ctx context.Context, query vectorstores.HybridQuery, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil || query.Sparse != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	filterQuery, err := getFilters(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := query.Vector
+	if vector == nil {
+		vector, err = s.getEmbedder(opts).EmbedQuery(ctx, query.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filter := s.scope(s.getNameSpace(opts), filterQuery)
+	docs, err := s.hybridSearch(ctx, query.Text, vector, fetchK, filter, query.Alpha, query.Fusion,
+		scoreThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// hybridSearch runs the kNN and BM25 searches, on twice as many documents as
+// requested, and fuses their results, with reciprocal rank fusion unless the
+// fusion is weighted. The kNN hits below the score threshold are left out of
+// the fusion.
+func (s Store) hybridSearch(
+	ctx context.Context,
+	query string,
+	vector []float64,
+	numDocuments int,
+	filter any,
+	alpha float64,
+	fusion vectorstores.Fusion,
+	scoreThreshold float64,
+) ([]schema.Document, error) {
+	window := 2 * numDocuments
+	knnHits, err := s.search(ctx, s.knnBody(vector, window, filter))
+	if err != nil {
+		return nil, err
+	}
+	if scoreThreshold != 0 {
+		matches := knnHits[:0]
+		for _, h := range knnHits {
+			if s.normalizeScore(h.Score) >= scoreThreshold {
+				matches = append(matches, h)
+			}
+		}
+		knnHits = matches
+	}
+	bm25Hits, err := s.search(ctx, s.bm25Body(query, window, filter))
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make(map[string]hit)
+	rank := func(ranking []hit, normalize func(float64) float64) []vectorstores.Ranked {
+		ranked := make([]vectorstores.Ranked, 0, len(ranking))
+		for _, h := range ranking {
+			ranked = append(ranked, vectorstores.Ranked{Key: h.ID, Score: normalize(h.Score)})
+			hits[h.ID] = h
+		}
+		return ranked
+	}
+	knn := rank(knnHits, s.normalizeScore)
+	bm25 := rank(bm25Hits, func(score float64) float64 { return score })
+
+	var fused []vectorstores.Ranked
+	if fusion == vectorstores.FusionWeighted {
+		fused = vectorstores.FuseWeighted(alpha, knn, bm25)
+	} else {
+		fused = vectorstores.FuseRRF(alpha, s.rankConstant, knn, bm25)
+	}
+	if len(fused) > numDocuments {
+		fused = fused[:numDocuments]
+	}
+
+	docs := make([]schema.Document, 0, len(fused))
+	for _, r := range fused {
+		docs = append(docs, newDocument(hits[r.Key], r.Score))
+	}
+	return docs, nil
+}
+
+// scope returns the query of the documents of the name space matching the
+// filter, if not nil. The documents of the default name space have no name
+// space field.
+func (s Store) scope(nameSpace string, filter any) any {
+	clause := map[string]any{"term": map[string]any{"namespace": nameSpace}}
+	if nameSpace == "" {
+		clause = map[string]any{
+			"bool": map[string]any{"must_not": map[string]any{"exists": map[string]any{"field": "namespace"}}},
+		}
+	}
+	if filter == nil {
+		return clause
+	}
+	return map[string]any{"bool": map[string]any{"filter": []any{clause, filter}}}
+}
+
+// knnBody returns the kNN search of the k nearest documents to the vector
+// matching the filter.
+func (s Store) knnBody(vector []float64, k int, filter any) map[string]any {
+	if s.engine == EngineOpenSearch {
+		knn := map[string]any{"vector": vector, "k": k}
+		if filter != nil {
+			knn["filter"] = filter
+		}
+		return map[string]any{
+			"size":    k,
+			"query":   map[string]any{"knn": map[string]any{"vector": knn}},
+			"_source": []string{"content", "metadata"},
+		}
+	}
+
+	// The number of candidates per shard trades speed for recall.
+	numCandidates := 10 * k
+	if numCandidates < 100 { //nolint:gomnd
+		numCandidates = 100
+	}
+	knn := map[string]any{
+		"field":          "vector",
+		"query_vector":   vector,
+		"k":              k,
+		"num_candidates": numCandidates,
+	}
+	if filter != nil {
+		knn["filter"] = filter
+	}
+	return map[string]any{
+		"size":    k,
+		"knn":     knn,
+		"_source": []string{"content", "metadata"},
+	}
+}
+
+// bm25Body returns the full-text search of the k documents whose content
+// best matches the query and which match the filter.
+func (s Store) bm25Body(query string, k int, filter any) map[string]any {
+	boolQuery := map[string]any{"must": map[string]any{"match": map[string]any{"content": query}}}
+	if filter != nil {
+		boolQuery["filter"] = filter
+	}
+	return map[string]any{
+		"size":    k,
+		"query":   map[string]any{"bool": boolQuery},
+		"_source": []string{"content", "metadata"},
+	}
+}
+
+// normalizeScore returns the score of a kNN hit on the scale of
+// Elasticsearch, between 0 and 1: (1 + similarity) / 2 for the cosine
+// similarity and the dot product, and 1 / (1 + squared distance) for the
+// Euclidean distance. OpenSearch scores the inner product differently.
+func (s Store) normalizeScore(score float64) float64 {
+	if s.engine != EngineOpenSearch || s.similarity != SimilarityDotProduct {
+		return score
+	}
+	// OpenSearch scores a dot product d as d + 1 if d >= 0 and 1 / (1 - d)
+	// otherwise.
+	dot := score - 1
+	if score < 1 {
+		dot = 1 - 1/score
+	}
+	normalized := (1 + dot) / 2 //nolint:gomnd
+	switch {
+	case normalized < 0:
+		return 0
+	case normalized > 1:
+		return 1
+	}
+	return normalized
+}
+
+func newDocument(h hit, score float64) schema.Document {
+	return schema.Document{PageContent: h.Source.Content, Metadata: h.Source.Metadata, Score: score}
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getNameSpace returns the name space of the call, scoped by its tenant.
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	nameSpace := s.nameSpace
+	if opts.NameSpace != "" {
+		nameSpace = opts.NameSpace
+	}
+	return vectorstores.TenantNameSpace(opts.Tenant, nameSpace)
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
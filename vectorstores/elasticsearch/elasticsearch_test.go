@@ -0,0 +1,680 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// fakeCluster is an Elasticsearch or OpenSearch cluster holding an index in
+// memory. The kNN searches score the documents with the cosine similarity on
+// the scale of the engine, the full-text searches count the words of the
+// query in the content. The filters only filter the IDs and the name spaces
+// of the documents, the other queries match all the documents.
+type fakeCluster struct {
+	mu       sync.Mutex
+	engine   Engine
+	mapping  map[string]any
+	docs     []hit
+	searches []map[string]any
+	deletes  []map[string]any
+	auth     []string
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	switch {
+	case r.Method == http.MethodHead && r.URL.Path == "/test":
+		if f.mapping == nil {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == http.MethodPut && r.URL.Path == "/test":
+		f.mapping = map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&f.mapping)
+		fmt.Fprint(w, `{"acknowledged":true}`)
+	case r.Method == http.MethodDelete && r.URL.Path == "/test":
+		f.mapping = nil
+		f.docs = nil
+		fmt.Fprint(w, `{"acknowledged":true}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/test/_mapping" && f.mapping != nil:
+		_ = json.NewEncoder(w).Encode(map[string]any{"test": map[string]any{"mappings": f.mapping["mappings"]}})
+	case r.Method == http.MethodGet && r.URL.Path == "/test/_count" && f.mapping != nil:
+		fmt.Fprintf(w, `{"count":%d}`, len(f.docs))
+	case r.Method == http.MethodGet && f.mapping == nil:
+		http.Error(w, `{"error":{"type":"index_not_found_exception"}}`, http.StatusNotFound)
+	case r.Method == http.MethodPost && r.URL.Path == "/_bulk":
+		f.bulk(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/test/_search":
+		f.search(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/test/_delete_by_query":
+		f.deleteByQuery(w, r)
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+	}
+}
+
+func (f *fakeCluster) bulk(w http.ResponseWriter, r *http.Request) {
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var action struct {
+			Index struct {
+				ID string `json:"_id"`
+			} `json:"index"`
+		}
+		_ = json.Unmarshal(scanner.Bytes(), &action)
+		if !scanner.Scan() {
+			break
+		}
+		var src source
+		_ = json.Unmarshal(scanner.Bytes(), &src)
+		f.delete(action.Index.ID)
+		f.docs = append(f.docs, hit{ID: action.Index.ID, Source: src})
+	}
+	fmt.Fprint(w, `{"errors":false,"items":[]}`)
+}
+
+func (f *fakeCluster) deleteByQuery(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.deletes = append(f.deletes, body)
+
+	docs := f.docs[:0]
+	for _, doc := range f.docs {
+		if !matches(body["query"], doc) {
+			docs = append(docs, doc)
+		}
+	}
+	deleted := len(f.docs) - len(docs)
+	f.docs = docs
+	fmt.Fprintf(w, `{"deleted":%d}`, deleted)
+}
+
+// matches reports whether the document matches the ids, term namespace and
+// exists namespace queries of the query, combined by bool queries.
+func matches(query any, doc hit) bool {
+	q, _ := query.(map[string]any)
+	if b, ok := q["bool"].(map[string]any); ok {
+		filters, ok := b["filter"].([]any)
+		if !ok && b["filter"] != nil {
+			filters = []any{b["filter"]}
+		}
+		for _, filter := range filters {
+			if !matches(filter, doc) {
+				return false
+			}
+		}
+		return b["must_not"] == nil || !matches(b["must_not"], doc)
+	}
+	if ids, ok := q["ids"].(map[string]any); ok {
+		values, _ := ids["values"].([]any)
+		for _, id := range values {
+			if id == doc.ID {
+				return true
+			}
+		}
+		return false
+	}
+	if term, ok := q["term"].(map[string]any); ok && term["namespace"] != nil {
+		return term["namespace"] == doc.Source.NameSpace
+	}
+	if exists, ok := q["exists"].(map[string]any); ok && exists["field"] == "namespace" {
+		return doc.Source.NameSpace != ""
+	}
+	return true
+}
+
+// delete deletes the document with the ID, if any.
+func (f *fakeCluster) delete(id any) {
+	for i, doc := range f.docs {
+		if doc.ID == id {
+			f.docs = append(f.docs[:i], f.docs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *fakeCluster) search(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.searches = append(f.searches, body)
+
+	var (
+		k      int
+		scored []hit
+	)
+	vector, k, isKNN := f.knnQuery(body)
+	filter := f.filter(body, isKNN)
+	for _, doc := range f.docs {
+		if !matches(filter, doc) {
+			continue
+		}
+		h := hit{ID: doc.ID, Source: source{Content: doc.Source.Content, Metadata: doc.Source.Metadata}}
+		if isKNN {
+			h.Score = f.knnScore(cosine(vector, doc.Source.Vector))
+		} else {
+			query := body["query"].(map[string]any)["bool"].(map[string]any)["must"].(map[string]any)["match"].(map[string]any)["content"].(string) //nolint:lll,forcetypeassert
+			h.Score = bm25(query, doc.Source.Content)
+			if h.Score == 0 {
+				continue
+			}
+		}
+		scored = append(scored, h)
+	}
+	if !isKNN {
+		k = int(body["size"].(float64)) //nolint:forcetypeassert
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	if len(scored) > k {
+		scored = scored[:k]
+	}
+
+	var res searchResponse
+	res.Hits.Hits = scored
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// knnQuery returns the vector and k of the kNN search of the body.
+func (f *fakeCluster) knnQuery(body map[string]any) ([]float64, int, bool) {
+	var knn map[string]any
+	if f.engine == EngineOpenSearch {
+		query, _ := body["query"].(map[string]any)
+		outer, ok := query["knn"].(map[string]any)
+		if !ok {
+			return nil, 0, false
+		}
+		knn, _ = outer["vector"].(map[string]any)
+		return toVector(knn["vector"]), int(knn["k"].(float64)), true //nolint:forcetypeassert
+	}
+	knn, ok := body["knn"].(map[string]any)
+	if !ok {
+		return nil, 0, false
+	}
+	return toVector(knn["query_vector"]), int(knn["k"].(float64)), true //nolint:forcetypeassert
+}
+
+// filter returns the filter of the kNN or full-text search of the body.
+func (f *fakeCluster) filter(body map[string]any, isKNN bool) any {
+	query, _ := body["query"].(map[string]any)
+	if !isKNN {
+		b, _ := query["bool"].(map[string]any)
+		return b["filter"]
+	}
+	knn, _ := body["knn"].(map[string]any)
+	if f.engine == EngineOpenSearch {
+		outer, _ := query["knn"].(map[string]any)
+		knn, _ = outer["vector"].(map[string]any)
+	}
+	return knn["filter"]
+}
+
+// knnScore returns the score of the engine for the cosine similarity, with
+// the cosine space of Elasticsearch and the inner product space of
+// OpenSearch.
+func (f *fakeCluster) knnScore(similarity float64) float64 {
+	if f.engine == EngineOpenSearch {
+		if similarity >= 0 {
+			return similarity + 1
+		}
+		return 1 / (1 - similarity)
+	}
+	return (1 + similarity) / 2
+}
+
+func toVector(v any) []float64 {
+	values, _ := v.([]any)
+	vector := make([]float64, 0, len(values))
+	for _, value := range values {
+		f, _ := value.(float64)
+		vector = append(vector, f)
+	}
+	return vector
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+func bm25(query, content string) float64 {
+	words := strings.Fields(strings.ToLower(content))
+	var score float64
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		for _, word := range words {
+			if strings.Trim(word, ".,!?") == term {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+func newTestStore(t *testing.T, engine Engine, opts ...Option) (Store, *fakeCluster) {
+	t.Helper()
+
+	cluster := &fakeCluster{engine: engine}
+	server := httptest.NewServer(cluster)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{
+		WithURL(server.URL),
+		WithEngine(engine),
+		WithIndexName("test"),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithVectorDimensions(3),
+	}, opts...)
+	store, err := New(context.Background(), opts...)
+	require.NoError(t, err)
+	return store, cluster
+}
+
+var testDocs = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "The cat sleeps.", Metadata: map[string]any{"animal": true}},
+	{PageContent: "The dog barks at the cat.", Metadata: map[string]any{"animal": true}},
+	{PageContent: "The car is red.", Metadata: map[string]any{"animal": false}},
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(context.Background(), WithEmbedder(testutil.WordCountEmbedder{}))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(context.Background(), WithIndexName("test"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(context.Background(), WithIndexName("test"), WithEmbedder(testutil.WordCountEmbedder{}))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(context.Background(), WithIndexName("test"), WithEmbedder(testutil.WordCountEmbedder{}),
+		WithSkipIndexCreation(), WithSimilarity("hamming"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(context.Background(), WithIndexName("test"), WithEmbedder(testutil.WordCountEmbedder{}),
+		WithSkipIndexCreation(), WithEngine("solr"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestIndexMappings(t *testing.T) {
+	t.Parallel()
+
+	_, es := newTestStore(t, EngineElasticsearch, WithSimilarity(SimilarityDotProduct))
+	properties := es.mapping["mappings"].(map[string]any)["properties"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, map[string]any{
+		"type":       "dense_vector",
+		"dims":       float64(3),
+		"index":      true,
+		"similarity": "dot_product",
+	}, properties["vector"])
+	assert.Nil(t, es.mapping["settings"])
+
+	_, opensearch := newTestStore(t, EngineOpenSearch)
+	properties = opensearch.mapping["mappings"].(map[string]any)["properties"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, map[string]any{
+		"type":      "knn_vector",
+		"dimension": float64(3),
+		"method": map[string]any{
+			"name":       "hnsw",
+			"engine":     "lucene",
+			"space_type": "cosinesimil",
+		},
+	}, properties["vector"])
+	assert.Equal(t, map[string]any{"index": map[string]any{"knn": true}}, opensearch.mapping["settings"])
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range []Engine{EngineElasticsearch, EngineOpenSearch} {
+		engine := engine
+		t.Run(string(engine), func(t *testing.T) {
+			t.Parallel()
+
+			// The fake OpenSearch cluster scores with the inner product space.
+			store, cluster := newTestStore(t, engine, WithSimilarity(SimilarityDotProduct),
+				WithAPIKey("key"))
+			_, err := store.AddDocuments(context.Background(), testDocs)
+			require.NoError(t, err)
+			require.Len(t, cluster.docs, 3)
+
+			docs, err := store.SimilaritySearch(context.Background(), "cat", 2)
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.Equal(t, map[string]any{"animal": true}, docs[0].Metadata)
+			assert.Equal(t, "The dog barks at the cat.", docs[1].PageContent)
+
+			// The normalized score of the second document is 0.87 with both
+			// engines.
+			assert.InDelta(t, 0.87, docs[1].Score, 0.01)
+			docs, err = store.SimilaritySearch(context.Background(), "cat", 2,
+				vectorstores.WithScoreThreshold(0.9))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+			assert.Equal(t, "ApiKey key", cluster.auth[len(cluster.auth)-1])
+		})
+	}
+}
+
+func TestSimilaritySearchFilter(t *testing.T) {
+	t.Parallel()
+
+	store, cluster := newTestStore(t, EngineElasticsearch)
+	filter := map[string]any{"term": map[string]any{"metadata.animal": true}}
+	_, err := store.SimilaritySearch(context.Background(), "cat", 2, vectorstores.WithFilters(filter))
+	require.NoError(t, err)
+
+	knn := cluster.searches[0]["knn"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, map[string]any{"bool": map[string]any{"filter": []any{
+		map[string]any{"bool": map[string]any{"must_not": map[string]any{"exists": map[string]any{"field": "namespace"}}}},
+		map[string]any{"term": map[string]any{"metadata.animal": true}},
+	}}}, knn["filter"])
+	assert.Equal(t, "vector", knn["field"])
+	assert.Equal(t, float64(100), knn["num_candidates"])
+}
+
+func TestSimilaritySearchTypedFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, cluster := newTestStore(t, EngineElasticsearch)
+	filter := filters.Eq("animal", true).
+		And(filters.Or(filters.Gt("legs", 2), filters.In("name", "cat", "dog")), filters.Not(filters.Ne("color", "red")))
+	_, err := store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithFilters(filter))
+	require.NoError(t, err)
+
+	knn := cluster.searches[0]["knn"].(map[string]any) //nolint:forcetypeassert
+	assert.Equal(t, map[string]any{"bool": map[string]any{"filter": []any{
+		map[string]any{"bool": map[string]any{"must_not": map[string]any{"exists": map[string]any{"field": "namespace"}}}},
+		map[string]any{"bool": map[string]any{"filter": []any{
+			map[string]any{"term": map[string]any{"metadata.animal": true}},
+			map[string]any{"bool": map[string]any{
+				"should": []any{
+					map[string]any{"range": map[string]any{"metadata.legs": map[string]any{"gt": float64(2)}}},
+					map[string]any{"terms": map[string]any{"metadata.name": []any{"cat", "dog"}}},
+				},
+				"minimum_should_match": float64(1),
+			}},
+			map[string]any{"bool": map[string]any{"must_not": []any{
+				map[string]any{"bool": map[string]any{"must_not": []any{
+					map[string]any{"term": map[string]any{"metadata.color": "red"}},
+				}}},
+			}}},
+		}}},
+	}}}, knn["filter"])
+
+	_, err = store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithFilters(filters.In("name")))
+	require.ErrorIs(t, err, filters.ErrInvalidFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, filters.Gt("", 1)), filters.ErrInvalidFilter)
+	require.Len(t, cluster.searches, 1)
+	require.Empty(t, cluster.deletes)
+}
+
+func TestHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	store, cluster := newTestStore(t, EngineElasticsearch, WithBasicAuth("elastic", "secret"))
+	_, err := store.AddDocuments(context.Background(), testDocs)
+	require.NoError(t, err)
+
+	// The kNN search ranks the car last, the BM25 search ranks it first.
+	docs, err := store.SimilaritySearch(context.Background(), "red car cat", 3, vectorstores.WithAlpha(0))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 1, vectorstores.WithAlpha(1))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+	// The kNN and BM25 searches are over twice as many documents.
+	last := cluster.searches[len(cluster.searches)-1]
+	assert.Equal(t, float64(2), last["size"])
+	assert.NotNil(t, last["query"].(map[string]any)["bool"]) //nolint:forcetypeassert
+
+	// The score threshold leaves the car out of the kNN ranking, the BM25
+	// search only matches the documents with a cat.
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 3, vectorstores.WithAlpha(0.5),
+		vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+	_, err = store.SimilaritySearch(context.Background(), "cat", 1, vectorstores.WithAlpha(2))
+	require.ErrorIs(t, err, ErrInvalidAlpha)
+
+	assert.True(t, strings.HasPrefix(cluster.auth[len(cluster.auth)-1], "Basic "))
+}
+
+func TestHybridSearchAPI(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, cluster := newTestStore(t, EngineOpenSearch)
+	_, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+
+	// The weighted fusion of the BM25 search ranks the car first.
+	docs, err := vectorstores.HybridSearch(ctx, store, vectorstores.HybridQuery{
+		Text:   "red car cat",
+		Alpha:  0,
+		Fusion: vectorstores.FusionWeighted,
+	}, 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+	assert.InDelta(t, 1, docs[0].Score, 1e-9)
+
+	// The vector of the query is searched as is.
+	docs, err = store.HybridSearch(ctx, vectorstores.HybridQuery{Vector: []float64{1, 0, 0}, Alpha: 1}, 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	knn, _, _ := cluster.knnQuery(cluster.searches[len(cluster.searches)-2])
+	assert.Equal(t, []float64{1, 0, 0}, knn)
+
+	_, err = store.HybridSearch(ctx, vectorstores.HybridQuery{Text: "cat", Alpha: 2}, 1)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridQuery)
+	_, err = store.HybridSearch(ctx, vectorstores.HybridQuery{Text: "cat", Sparse: &embeddings.SparseVector{}}, 1)
+	require.ErrorIs(t, err, vectorstores.ErrSparseNotSupported)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, cluster := newTestStore(t, EngineElasticsearch)
+	ids, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	assert.Equal(t, ids[0], cluster.docs[0].ID)
+
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat eats."}}, vectorstores.WithIDs(ids[0]))
+	require.NoError(t, err)
+	require.Len(t, cluster.docs, 3)
+	assert.Equal(t, "The cat eats.", cluster.docs[2].Source.Content)
+
+	require.NoError(t, store.Delete(ctx, ids[:2]))
+	require.Len(t, cluster.docs, 1)
+	assert.Equal(t, "The car is red.", cluster.docs[0].Source.Content)
+
+	filter := map[string]any{"term": map[string]any{"metadata.animal": false}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter, vectorstores.WithNameSpace("pets")))
+	assert.Equal(t, map[string]any{"query": map[string]any{"bool": map[string]any{"filter": []any{
+		map[string]any{"term": map[string]any{"namespace": "pets"}},
+		map[string]any{"term": map[string]any{"metadata.animal": false}},
+	}}}}, cluster.deletes[1])
+	require.Len(t, cluster.docs, 1)
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	_, err = store.AddDocuments(ctx, testDocs, vectorstores.WithIDs("a"))
+	require.ErrorIs(t, err, vectorstores.ErrWrongNumberIDs)
+}
+
+func TestNameSpace(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range []Engine{EngineElasticsearch, EngineOpenSearch} {
+		engine := engine
+		t.Run(string(engine), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store, cluster := newTestStore(t, engine, WithNameSpace("pets"))
+			_, err := store.AddDocuments(ctx, testDocs[:2])
+			require.NoError(t, err)
+			ids, err := store.AddDocuments(ctx, testDocs[2:], vectorstores.WithNameSpace("cars"))
+			require.NoError(t, err)
+			assert.Equal(t, "pets", cluster.docs[0].Source.NameSpace)
+			assert.Equal(t, "cars", cluster.docs[2].Source.NameSpace)
+
+			docs, err := store.SimilaritySearch(ctx, "car", 3)
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			docs, err = store.SimilaritySearch(ctx, "cat", 3, vectorstores.WithNameSpace("cars"),
+				vectorstores.WithAlpha(0.5))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+			// The documents of other name spaces are not deleted.
+			require.NoError(t, store.Delete(ctx, ids))
+			require.Len(t, cluster.docs, 3)
+			require.NoError(t, store.Delete(ctx, ids, vectorstores.WithNameSpace("cars")))
+			require.Len(t, cluster.docs, 2)
+		})
+	}
+}
+
+func TestIndexManager(t *testing.T) {
+	t.Parallel()
+
+	for engine, similarity := range map[Engine]Similarity{
+		EngineElasticsearch: SimilarityDotProduct,
+		EngineOpenSearch:    SimilarityL2,
+	} {
+		engine, similarity := engine, similarity
+		t.Run(string(engine), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store, cluster := newTestStore(t, engine, WithSimilarity(similarity), WithSkipIndexCreation())
+			_, err := store.DescribeIndex(ctx)
+			require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+			_, err = store.Stats(ctx)
+			require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+
+			require.NoError(t, store.CreateIndex(ctx))
+			require.NotNil(t, cluster.mapping)
+			// The index is not created again.
+			require.NoError(t, store.CreateIndex(ctx))
+
+			info, err := store.DescribeIndex(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, vectorstores.IndexInfo{Name: "test", Dimensions: 3, Metric: string(similarity)}, info)
+
+			_, err = store.AddDocuments(ctx, testDocs)
+			require.NoError(t, err)
+			stats, err := store.Stats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 3, stats.NumDocuments)
+
+			require.NoError(t, store.DeleteIndex(ctx))
+			_, err = store.Stats(ctx)
+			require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+		})
+	}
+}
+
+func TestNormalizeScore(t *testing.T) {
+	t.Parallel()
+
+	es := Store{engine: EngineElasticsearch, similarity: SimilarityDotProduct}
+	assert.InDelta(t, 0.75, es.normalizeScore(0.75), 1e-9)
+
+	opensearch := Store{engine: EngineOpenSearch, similarity: SimilarityDotProduct}
+	assert.InDelta(t, 0.75, opensearch.normalizeScore(1.5), 1e-9)
+	assert.InDelta(t, 0.25, opensearch.normalizeScore(2.0/3), 1e-9)
+
+	opensearch.similarity = SimilarityCosine
+	assert.InDelta(t, 0.75, opensearch.normalizeScore(0.75), 1e-9)
+}
+
+func TestBulkErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`) //nolint:lll
+	}))
+	t.Cleanup(server.Close)
+
+	store, err := New(context.Background(), WithURL(server.URL), WithIndexName("test"),
+		WithEmbedder(testutil.WordCountEmbedder{}), WithSkipIndexCreation())
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), testDocs)
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Message, "mapper_parsing_exception")
+}
+
+func TestElasticsearchIntegration(t *testing.T) {
+	t.Parallel()
+
+	url := os.Getenv(_urlEnvVarName)
+	if url == "" {
+		t.Skip("ELASTICSEARCH_URL not set")
+	}
+
+	store, err := New(context.Background(),
+		WithURL(url),
+		WithIndexName("langchaingo-"+uuid.NewString()),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, store.DeleteIndex(context.Background())) }()
+
+	_, err = store.AddDocuments(context.Background(), testDocs)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(context.Background(), "red car", 1, vectorstores.WithAlpha(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+}
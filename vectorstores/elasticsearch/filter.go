@@ -0,0 +1,61 @@
+package elasticsearch
+
+import (
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// queryFilter returns the filter as a query of the query DSL on the metadata
+// of the documents, e.g. {"bool": {"filter": [{"term": {"metadata.author":
+// "bob"}}, {"range": {"metadata.year": {"gt": 2020}}}]}}.
+func queryFilter(f filters.Filter) (map[string]any, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return translate(f), nil
+}
+
+func translate(f filters.Filter) map[string]any {
+	field := "metadata." + f.Field
+	switch f.Op {
+	case filters.OpAnd:
+		return boolQuery("filter", translateAll(f.Filters))
+	case filters.OpOr:
+		return map[string]any{"bool": map[string]any{
+			"should":               translateAll(f.Filters),
+			"minimum_should_match": 1,
+		}}
+	case filters.OpNot:
+		return boolQuery("must_not", translateAll(f.Filters))
+	case filters.OpEq:
+		return map[string]any{"term": map[string]any{field: f.Value}}
+	case filters.OpNe:
+		return boolQuery("must_not", []any{map[string]any{"term": map[string]any{field: f.Value}}})
+	case filters.OpIn:
+		return map[string]any{"terms": map[string]any{field: f.Value}}
+	case filters.OpNin:
+		return boolQuery("must_not", []any{map[string]any{"terms": map[string]any{field: f.Value}}})
+	default:
+		return map[string]any{"range": map[string]any{field: map[string]any{string(f.Op): f.Value}}}
+	}
+}
+
+func translateAll(fs []filters.Filter) []any {
+	queries := make([]any, 0, len(fs))
+	for _, f := range fs {
+		queries = append(queries, translate(f))
+	}
+	return queries
+}
+
+func boolQuery(occur string, queries []any) map[string]any {
+	return map[string]any{"bool": map[string]any{occur: queries}}
+}
+
+// getFilters returns the filters as a query of the query DSL, passing the
+// filters other than a filters.Filter as they are.
+func getFilters(filter any) (any, error) {
+	if f, ok := filter.(filters.Filter); ok {
+		return queryFilter(f)
+	}
+	return filter, nil
+}
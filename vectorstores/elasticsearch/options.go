@@ -0,0 +1,193 @@
+package elasticsearch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_urlEnvVarName       = "ELASTICSEARCH_URL"
+	_defaultURL          = "http://localhost:9200"
+	_defaultRankConstant = 60
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Engine is the search engine of the cluster, whose kNN mappings and queries
+// differ.
+type Engine string
+
+const (
+	// EngineElasticsearch is Elasticsearch 8, with dense_vector fields.
+	EngineElasticsearch Engine = "elasticsearch"
+	// EngineOpenSearch is OpenSearch 2.4 or later, with knn_vector fields
+	// of the Lucene engine.
+	EngineOpenSearch Engine = "opensearch"
+)
+
+// Similarity is the similarity of the vectors of the index.
+type Similarity string
+
+const (
+	// SimilarityCosine is the cosine similarity.
+	SimilarityCosine Similarity = "cosine"
+	// SimilarityDotProduct is the dot product, for normalized vectors.
+	SimilarityDotProduct Similarity = "dot_product"
+	// SimilarityL2 is the Euclidean distance.
+	SimilarityL2 Similarity = "l2_norm"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithURL is an option for setting the url of the cluster. If not set, the
+// url is read from the ELASTICSEARCH_URL environment variable, or is
+// http://localhost:9200.
+func WithURL(url string) Option {
+	return func(p *Store) {
+		p.url = url
+	}
+}
+
+// WithEngine is an option for setting the search engine of the cluster,
+// Elasticsearch by default.
+func WithEngine(engine Engine) Option {
+	return func(p *Store) {
+		p.engine = engine
+	}
+}
+
+// WithAPIKey is an option for authenticating with an encoded API key of
+// Elasticsearch.
+func WithAPIKey(apiKey string) Option {
+	return func(p *Store) {
+		p.apiKey = apiKey
+	}
+}
+
+// WithBasicAuth is an option for authenticating with a user and a password.
+func WithBasicAuth(username, password string) Option {
+	return func(p *Store) {
+		p.username = username
+		p.password = password
+	}
+}
+
+// WithIndexName is an option for specifying the name of the index. Must be
+// set.
+func WithIndexName(name string) Option {
+	return func(p *Store) {
+		p.indexName = name
+	}
+}
+
+// WithNameSpace is an option for setting the default name space of the
+// documents, overridden by vectorstores.WithNameSpace. The name spaces are
+// stored in the keyword field namespace, mapped by the indexes created by
+// the store.
+func WithNameSpace(nameSpace string) Option {
+	return func(p *Store) {
+		p.nameSpace = nameSpace
+	}
+}
+
+// WithVectorDimensions is an option for specifying the number of dimensions
+// of the vectors of the embedder. Must be set to create the index.
+func WithVectorDimensions(dimensions int) Option {
+	return func(p *Store) {
+		p.vectorDimensions = dimensions
+	}
+}
+
+// WithSimilarity is an option for specifying the similarity of the vectors,
+// the cosine similarity by default.
+func WithSimilarity(similarity Similarity) Option {
+	return func(p *Store) {
+		p.similarity = similarity
+	}
+}
+
+// WithRankConstant is an option for specifying the rank constant of the
+// reciprocal rank fusion of the hybrid searches, 60 by default. Higher
+// constants give more weight to the documents ranked low by a search.
+func WithRankConstant(rankConstant int) Option {
+	return func(p *Store) {
+		p.rankConstant = rankConstant
+	}
+}
+
+// WithSkipIndexCreation is an option for using an existing index, with the
+// content, metadata and vector fields, without creating it.
+func WithSkipIndexCreation() Option {
+	return func(p *Store) {
+		p.skipIndexCreation = true
+	}
+}
+
+// WithHTTPClient is an option for setting the HTTP client of the rest api.
+// If not set, http.DefaultClient is used.
+func WithHTTPClient(client Doer) Option {
+	return func(p *Store) {
+		p.httpClient = client
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	o := &Store{
+		url:          os.Getenv(_urlEnvVarName),
+		engine:       EngineElasticsearch,
+		similarity:   SimilarityCosine,
+		rankConstant: _defaultRankConstant,
+		httpClient:   http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.url == "" {
+		o.url = _defaultURL
+	}
+
+	if o.indexName == "" {
+		return Store{}, fmt.Errorf("%w: missing index name", ErrInvalidOptions)
+	}
+
+	if o.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if !o.skipIndexCreation && o.vectorDimensions <= 0 {
+		return Store{}, fmt.Errorf("%w: missing vector dimensions", ErrInvalidOptions)
+	}
+
+	switch o.engine {
+	case EngineElasticsearch, EngineOpenSearch:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown engine %q", ErrInvalidOptions, o.engine)
+	}
+
+	switch o.similarity {
+	case SimilarityCosine, SimilarityDotProduct, SimilarityL2:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown similarity %q", ErrInvalidOptions, o.similarity)
+	}
+
+	if o.rankConstant <= 0 {
+		return Store{}, fmt.Errorf("%w: rank constant must be positive", ErrInvalidOptions)
+	}
+
+	return *o, nil
+}
@@ -0,0 +1,224 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error type returned if the status code from the rest
+// api is not 2xx, or if a document of a bulk request fails.
+type APIError struct {
+	Task       string
+	StatusCode int
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s: status %d: %s", e.Task, e.StatusCode, e.Message)
+}
+
+type source struct {
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata"`
+	NameSpace string         `json:"namespace,omitempty"`
+	Vector    []float64      `json:"vector,omitempty"`
+}
+
+type hit struct {
+	ID     string  `json:"_id"`
+	Score  float64 `json:"_score"`
+	Source source  `json:"_source"`
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []hit `json:"hits"`
+	} `json:"hits"`
+}
+
+// mappingResponse is the response of the mapping of an index, keyed by the
+// name of the index, with the vector field of both engines.
+type mappingResponse map[string]struct {
+	Mappings struct {
+		Properties struct {
+			Vector struct {
+				Dims       int    `json:"dims"`
+				Similarity string `json:"similarity"`
+				Dimension  int    `json:"dimension"`
+				Method     struct {
+					SpaceType string `json:"space_type"`
+				} `json:"method"`
+			} `json:"vector"`
+		} `json:"properties"`
+	} `json:"mappings"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// indexExists reports whether the index exists.
+func (s Store) indexExists(ctx context.Context) (bool, error) {
+	status, err := s.doRequest(ctx, "checking index", http.MethodHead, "/"+s.indexName, "", nil, nil)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// createIndex creates the index with the mappings of the engine.
+func (s Store) createIndex(ctx context.Context) error {
+	_, err := s.doRequest(ctx, "creating index", http.MethodPut, "/"+s.indexName, "application/json",
+		s.indexBody(), nil)
+	return err
+}
+
+// indexBody returns the settings and the mappings of the index.
+func (s Store) indexBody() map[string]any {
+	properties := map[string]any{
+		"content":   map[string]any{"type": "text"},
+		"metadata":  map[string]any{"type": "object"},
+		"namespace": map[string]any{"type": "keyword"},
+	}
+	body := map[string]any{"mappings": map[string]any{"properties": properties}}
+
+	if s.engine == EngineOpenSearch {
+		body["settings"] = map[string]any{"index": map[string]any{"knn": true}}
+		properties["vector"] = map[string]any{
+			"type":      "knn_vector",
+			"dimension": s.vectorDimensions,
+			"method": map[string]any{
+				"name":       "hnsw",
+				"engine":     "lucene",
+				"space_type": _openSearchSpaceTypes[s.similarity],
+			},
+		}
+		return body
+	}
+	properties["vector"] = map[string]any{
+		"type":       "dense_vector",
+		"dims":       s.vectorDimensions,
+		"index":      true,
+		"similarity": string(s.similarity),
+	}
+	return body
+}
+
+// bulkIndex indexes the documents with their IDs with a bulk request,
+// waiting for them to be searchable.
+func (s Store) bulkIndex(ctx context.Context, ids []string, sources []source) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i, src := range sources {
+		action := map[string]any{"index": map[string]any{"_index": s.indexName, "_id": ids[i]}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(src); err != nil {
+			return err
+		}
+	}
+
+	var res bulkResponse
+	if _, err := s.doRequest(ctx, "indexing documents", http.MethodPost, "/_bulk?refresh=wait_for",
+		"application/x-ndjson", body.Bytes(), &res); err != nil {
+		return err
+	}
+	if !res.Errors {
+		return nil
+	}
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Error.Type != "" {
+				return APIError{
+					Task:       "indexing documents",
+					StatusCode: result.Status,
+					Message:    result.Error.Type + ": " + result.Error.Reason,
+				}
+			}
+		}
+	}
+	return APIError{Task: "indexing documents", StatusCode: http.StatusOK, Message: "unknown error"}
+}
+
+// deleteByQuery deletes the documents matching the query, waiting for the
+// deletion to be visible to the searches.
+func (s Store) deleteByQuery(ctx context.Context, query any) error {
+	_, err := s.doRequest(ctx, "deleting documents", http.MethodPost,
+		"/"+s.indexName+"/_delete_by_query?refresh=true", "application/json",
+		map[string]any{"query": query}, nil)
+	return err
+}
+
+// search runs the search request and returns its hits.
+func (s Store) search(ctx context.Context, body map[string]any) ([]hit, error) {
+	var res searchResponse
+	if _, err := s.doRequest(ctx, "searching documents", http.MethodPost, "/"+s.indexName+"/_search",
+		"application/json", body, &res); err != nil {
+		return nil, err
+	}
+	return res.Hits.Hits, nil
+}
+
+// doRequest sends the request, whose payload is encoded as JSON unless it is
+// a []byte, and decodes the response into result, if not nil. The status code
+// is returned with the error.
+func (s Store) doRequest(
+	ctx context.Context,
+	task, method, path, contentType string,
+	payload, result any,
+) (int, error) {
+	var body io.Reader
+	switch payload := payload.(type) {
+	case nil:
+	case []byte:
+		body = bytes.NewReader(payload)
+	default:
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(s.url, "/")+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case s.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+s.apiKey)
+	case s.username != "":
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < http.StatusOK || r.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(r.Body)
+		return r.StatusCode, APIError{Task: task, StatusCode: r.StatusCode, Message: string(message)}
+	}
+	if result == nil {
+		return r.StatusCode, nil
+	}
+	return r.StatusCode, json.NewDecoder(r.Body).Decode(result)
+}
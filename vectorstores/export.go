@@ -0,0 +1,151 @@
+package vectorstores
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _importBatchSize is the number of records added at once by Import.
+const _importBatchSize = 100
+
+var (
+	// ErrExportNotSupported is returned by Export for the vector stores not
+	// implementing Exporter.
+	ErrExportNotSupported = errors.New("export not supported by the vector store")
+	// ErrInvalidRecord is returned by Import when a line is not a record with
+	// a vector.
+	ErrInvalidRecord = errors.New("invalid record")
+	// ErrMissingVector is returned by the embedder of the records imported
+	// for a text not in the records.
+	ErrMissingVector = errors.New("missing vector")
+)
+
+// Record is a document of a vector store with its ID and its vector, one JSON
+// object per line of the exports of the stores. The format is the same for
+// all the stores, so that the documents of a store are imported in another
+// store without embedding them again, with the same embedding model.
+type Record struct {
+	ID       string         `json:"id,omitempty"`
+	Vector   []float64      `json:"vector"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Exporter is the interface of the vector stores writing their documents as
+// records, e.g. for backups or migrations.
+type Exporter interface {
+	// Export writes the records of the documents of the name space and the
+	// tenant of the options to the writer, one JSON object per line.
+	Export(ctx context.Context, w io.Writer, options ...Option) error
+}
+
+// Export writes the records of the documents of the store to the writer, one
+// JSON object per line. The store must implement Exporter.
+func Export(ctx context.Context, store VectorStore, w io.Writer, options ...Option) error {
+	exporter, ok := store.(Exporter)
+	if !ok {
+		return ErrExportNotSupported
+	}
+	return exporter.Export(ctx, w, options...)
+}
+
+// Import adds the documents of the records of the reader, one JSON object per
+// line as written by Export, to the store, in batches, and returns the number
+// of documents added. The documents are added with the vectors of their
+// records rather than embedded, and with the IDs of their records when all the
+// records of a batch have one. The options are the options of AddDocuments,
+// e.g. the name space of the documents.
+func Import(ctx context.Context, store VectorStore, r io.Reader, options ...Option) (int, error) {
+	scanner := bufio.NewScanner(r)
+	// The vectors of the records make long lines.
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	imported := 0
+	records := make([]Record, 0, _importBatchSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return imported, fmt.Errorf("%w: line %d: %w", ErrInvalidRecord, line, err)
+		}
+		if len(record.Vector) == 0 {
+			return imported, fmt.Errorf("%w: line %d: no vector", ErrInvalidRecord, line)
+		}
+		records = append(records, record)
+		if len(records) == _importBatchSize {
+			if err := importRecords(ctx, store, records, options); err != nil {
+				return imported, err
+			}
+			imported += len(records)
+			records = records[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, err
+	}
+	if len(records) > 0 {
+		if err := importRecords(ctx, store, records, options); err != nil {
+			return imported, err
+		}
+		imported += len(records)
+	}
+	return imported, nil
+}
+
+// importRecords adds the documents of the records to the store with the
+// vectors of the records.
+func importRecords(ctx context.Context, store VectorStore, records []Record, options []Option) error {
+	docs := make([]schema.Document, 0, len(records))
+	ids := make([]string, 0, len(records))
+	vectors := make(recordEmbedder, len(records))
+	for _, record := range records {
+		docs = append(docs, schema.Document{PageContent: record.Content, Metadata: record.Metadata})
+		if record.ID != "" {
+			ids = append(ids, record.ID)
+		}
+		vectors[record.Content] = record.Vector
+	}
+
+	options = append(options[:len(options):len(options)], WithEmbedder(vectors))
+	if len(ids) == len(records) {
+		options = append(options, WithIDs(ids...))
+	}
+	_, err := store.AddDocuments(ctx, docs, options...)
+	return err
+}
+
+// recordEmbedder is the embedder of the records imported, embedding the
+// contents of the records as their vectors. The records of a batch with the
+// same content share the vector of the last one.
+type recordEmbedder map[string][]float64
+
+var _ embeddings.Embedder = recordEmbedder{}
+
+func (e recordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		vector, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+func (e recordEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	vector, ok := e[text]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingVector, text)
+	}
+	return vector, nil
+}
@@ -0,0 +1,94 @@
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// importStore records the documents added, with the vectors of the embedder
+// of the options.
+type importStore struct {
+	fakeStore
+	added   []schema.Document
+	ids     [][]string
+	vectors [][]float64
+}
+
+func (s *importStore) AddDocuments(ctx context.Context, docs []schema.Document, options ...Option) ([]string, error) { //nolint:lll
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	s.options = append(s.options, opts)
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := opts.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	s.added = append(s.added, docs...)
+	s.ids = append(s.ids, opts.IDs)
+	s.vectors = append(s.vectors, vectors...)
+	return opts.IDs, nil
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	for i := 0; i < _importBatchSize+1; i++ {
+		lines = append(lines, fmt.Sprintf(`{"id": "%d", "vector": [%d, 1], "content": "doc %d", "metadata": {"n": %d}}`,
+			i, i, i, i))
+	}
+	store := &importStore{}
+	n, err := Import(context.Background(), store, strings.NewReader(strings.Join(lines, "\n")+"\n\n"),
+		WithNameSpace("docs"))
+	require.NoError(t, err)
+	assert.Equal(t, _importBatchSize+1, n)
+
+	require.Len(t, store.options, 2)
+	assert.Equal(t, "docs", store.options[1].NameSpace)
+	require.Len(t, store.added, _importBatchSize+1)
+	assert.Equal(t, schema.Document{PageContent: "doc 100", Metadata: map[string]any{"n": 100.0}}, store.added[100])
+	assert.Equal(t, []float64{100, 1}, store.vectors[100])
+	assert.Len(t, store.ids[0], _importBatchSize)
+	assert.Equal(t, []string{"100"}, store.ids[1])
+
+	// The documents of the records without IDs are given new ones.
+	store = &importStore{}
+	_, err = Import(context.Background(), store, strings.NewReader(`{"id": "a", "vector": [1], "content": "a"}
+{"vector": [2], "content": "b"}`))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{nil}, store.ids)
+	assert.Equal(t, [][]float64{{1}, {2}}, store.vectors)
+}
+
+func TestImportInvalidRecord(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		`{"vector": [1], "content": "a"}` + "\nnot json",
+		`{"content": "a"}`,
+	} {
+		store := &importStore{}
+		n, err := Import(context.Background(), store, strings.NewReader(input))
+		require.ErrorIs(t, err, ErrInvalidRecord, input)
+		assert.Equal(t, 0, n)
+		assert.Empty(t, store.added)
+	}
+}
+
+func TestExportNotSupported(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	require.ErrorIs(t, Export(context.Background(), &fakeStore{}, &b), ErrExportNotSupported)
+}
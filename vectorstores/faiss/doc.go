@@ -0,0 +1,4 @@
+// Package faiss contains an implementation of the vectorStore interface
+// holding the documents in a flat FAISS index, read from and written to the
+// index files of FAISS in pure Go, without cgo.
+package faiss
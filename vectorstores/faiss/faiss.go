@@ -0,0 +1,415 @@
+package faiss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are neither a
+	// map[string]any of the metadata of the documents nor a valid
+	// filters.Filter.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrWrongVectorDimensions is returned if the vectors of the embedder
+	// have a number of dimensions different from the vectors of the index.
+	ErrWrongVectorDimensions = errors.New("vectors of different dimensions")
+)
+
+// document is a document of the store, the vector of which is the vector of
+// the index at the same position.
+type document struct {
+	ID        string         `json:"id"`
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	NameSpace string         `json:"nameSpace,omitempty"`
+}
+
+// Store is a vector store holding the vectors of the documents in a flat
+// FAISS index, read from and written to the index files of FAISS, so that
+// the indexes built by the Python pipelines are served from Go. The searches
+// are exact, as the searches of the flat indexes of FAISS, by the metric of
+// the index. It is safe for concurrent use.
+type Store struct {
+	embedder embeddings.Embedder
+	// optional, the directory of the index and the documents
+	dir string
+
+	mu        sync.RWMutex
+	index     *Index
+	documents []document
+	// positions are the positions of the documents by ID.
+	positions map[string]int
+	// nextID is the ID of the next vector of an index with IDs.
+	nextID int64
+}
+
+var (
+	_ vectorstores.VectorStore    = &Store{}
+	_ vectorstores.VectorSearcher = &Store{}
+	_ vectorstores.Exporter       = &Store{}
+)
+
+// New creates a new Store with options. With WithDirectory, the index and
+// the documents of the directory are loaded if it exists.
+func New(opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.dir != "" {
+		if err := s.loadDir(s.dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Len returns the number of documents of the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.documents)
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and adds them to the index, in the name space of the options. The
+// documents with the ID of a document of the store replace it.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	docs = vectorstores.ApplyTTL(opts, docs, time.Now())
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.documents) == 0 && len(vectors) > 0 {
+		s.index.Dimensions = len(vectors[0])
+	}
+	for _, vector := range vectors {
+		if len(vector) != s.index.Dimensions {
+			return nil, ErrWrongVectorDimensions
+		}
+	}
+
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata))
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		d := document{ID: ids[i], Content: texts[i], Metadata: metadata, NameSpace: opts.NameSpace}
+		vector := toFloat32(vectors[i])
+		if position, ok := s.positions[d.ID]; ok {
+			s.documents[position] = d
+			s.index.Vectors[position] = vector
+			continue
+		}
+		s.add(d, vector)
+	}
+	return ids, nil
+}
+
+// Delete deletes the documents of the name space of the options with the
+// IDs.
+func (s *Store) Delete(_ context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(doc document) bool {
+		return doc.NameSpace == opts.NameSpace && deleted[doc.ID]
+	})
+	return nil
+}
+
+// DeleteByFilter deletes the documents of the name space of the options
+// matching the filter, with the filters of SimilaritySearch.
+func (s *Store) DeleteByFilter(_ context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	match, err := s.getFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(doc document) bool {
+		return doc.NameSpace == opts.NameSpace && match(doc.Metadata)
+	})
+	return nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and returns the most similar documents of the name space of the options.
+// The scores of the documents are their inner products with the query for
+// the indexes of MetricInnerProduct, and 1 / (1 + their Euclidean distance)
+// for the indexes of MetricL2. The filters are either a map[string]any of
+// the values of the metadata of the documents or a filters.Filter. The
+// documents expired, see vectorstores.WithTTL, are not returned.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SimilaritySearchByVector(ctx, vector, numDocuments, options...)
+}
+
+// SimilaritySearchByVector returns the most similar documents to the vector,
+// as SimilaritySearch. The documents of the offset of the options are
+// skipped.
+func (s *Store) SimilaritySearchByVector(ctx context.Context, vector []float64, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.getFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	docs, err := s.search(vector, func(doc document, score float64) bool {
+		// If scoreThreshold is 0, we return all matches.
+		return doc.NameSpace == opts.NameSpace &&
+			(scoreThreshold == 0 || score >= scoreThreshold) &&
+			match(doc.Metadata)
+	}, offset, fetchK)
+	if err != nil {
+		return nil, err
+	}
+
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// search returns the n documents most similar to the vector accepted by the
+// function, after the first offset documents.
+func (s *Store) search(
+	vector []float64,
+	accept func(doc document, score float64) bool,
+	offset, n int,
+) ([]schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.documents) > 0 && len(vector) != s.index.Dimensions {
+		return nil, ErrWrongVectorDimensions
+	}
+
+	type result struct {
+		position int
+		score    float64
+	}
+	now := time.Now()
+	var results []result
+	for i, doc := range s.documents {
+		score := s.score(vector, s.index.Vectors[i])
+		if accept(doc, score) && !vectorstores.Expired(schema.Document{Metadata: doc.Metadata}, now) {
+			results = append(results, result{position: i, score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	if offset > len(results) {
+		offset = len(results)
+	}
+	results = results[offset:]
+	if len(results) > n {
+		results = results[:n]
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	for _, r := range results {
+		doc := s.documents[r.position]
+		metadata := make(map[string]any, len(doc.Metadata))
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		docs = append(docs, schema.Document{PageContent: doc.Content, Metadata: metadata, Score: r.score})
+	}
+	return docs, nil
+}
+
+// score returns the similarity of the vectors by the metric of the index.
+func (s *Store) score(query []float64, vector []float32) float64 {
+	if s.index.Metric == MetricInnerProduct {
+		var dot float64
+		for i, v := range vector {
+			dot += query[i] * float64(v)
+		}
+		return dot
+	}
+	var squared float64
+	for i, v := range vector {
+		d := query[i] - float64(v)
+		squared += d * d
+	}
+	return 1 / (1 + math.Sqrt(squared))
+}
+
+// add adds the document and its vector to the store. The lock must be held.
+func (s *Store) add(doc document, vector []float32) {
+	if s.positions == nil {
+		s.positions = map[string]int{}
+	}
+	s.positions[doc.ID] = len(s.documents)
+	s.documents = append(s.documents, doc)
+	s.index.Vectors = append(s.index.Vectors, vector)
+	if s.index.IDs != nil {
+		s.index.IDs = append(s.index.IDs, s.nextID)
+		s.nextID++
+	}
+}
+
+// deleteWhere deletes the documents for which deleted returns true with their
+// vectors. The lock must be held.
+func (s *Store) deleteWhere(deleted func(document) bool) {
+	documents := s.documents[:0]
+	vectors := s.index.Vectors[:0]
+	var ids []int64
+	if s.index.IDs != nil {
+		ids = s.index.IDs[:0]
+	}
+	s.positions = map[string]int{}
+	for i, doc := range s.documents {
+		if deleted(doc) {
+			continue
+		}
+		s.positions[doc.ID] = len(documents)
+		documents = append(documents, doc)
+		vectors = append(vectors, s.index.Vectors[i])
+		if ids != nil {
+			ids = append(ids, s.index.IDs[i])
+		}
+	}
+	s.documents = documents
+	s.index.Vectors = vectors
+	s.index.IDs = ids
+}
+
+func (s *Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+func (s *Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+// getFilter returns the filter of the metadata of the documents.
+func (s *Store) getFilter(opts vectorstores.Options) (func(map[string]any) bool, error) {
+	var filter filters.Filter
+	switch f := opts.Filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case filters.Filter:
+		filter = f
+	case map[string]any:
+		if len(f) == 0 {
+			return func(map[string]any) bool { return true }, nil
+		}
+		conditions := make([]filters.Filter, 0, len(f))
+		for key, value := range f {
+			conditions = append(conditions, filters.Eq(key, value))
+		}
+		filter = filters.And(conditions...)
+	default:
+		return nil, fmt.Errorf("%w: map[string]any or filters.Filter required, got %T", ErrInvalidFilter, opts.Filters)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	return func(metadata map[string]any) bool {
+		ok, _ := filters.Match(filter, metadata)
+		return ok
+	}, nil
+}
+
+// getOptions returns the options of the call, with the name space scoped by
+// the tenant.
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	opts.NameSpace = vectorstores.TenantNameSpace(opts.Tenant, opts.NameSpace)
+	return opts
+}
+
+func toFloat32(vector []float64) []float32 {
+	converted := make([]float32, len(vector))
+	for i, v := range vector {
+		converted[i] = float32(v)
+	}
+	return converted
+}
@@ -0,0 +1,191 @@
+package faiss
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+var testDocs = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "The cat sleeps.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The dog barks at the cat.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The car is red.", Metadata: map[string]any{"animal": false}},
+}
+
+func newTestStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+
+	store, err := New(append([]Option{WithEmbedder(testutil.WordCountEmbedder{})}, opts...)...)
+	require.NoError(t, err)
+	_, err = store.AddDocuments(context.Background(), testDocs)
+	require.NoError(t, err)
+	return store
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(WithEmbedder(testutil.WordCountEmbedder{}), WithMetric(3))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+
+	for name, metric := range map[string]Metric{"l2": MetricL2, "ip": MetricInnerProduct} {
+		metric := metric
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newTestStore(t, WithMetric(metric))
+			assert.Equal(t, 3, store.Len())
+
+			docs, err := store.SimilaritySearch(context.Background(), "cat", 2)
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.Equal(t, map[string]any{"animal": true, "legs": 4}, docs[0].Metadata)
+			assert.Equal(t, "The dog barks at the cat.", docs[1].PageContent)
+			// The inner products of the documents with the query are equal.
+			assert.GreaterOrEqual(t, docs[0].Score, docs[1].Score)
+
+			docs, err = store.SimilaritySearch(context.Background(), "cat", 2, vectorstores.WithOffset(1),
+				vectorstores.WithFilters(map[string]any{"animal": true}))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The dog barks at the cat.", docs[0].PageContent)
+		})
+	}
+}
+
+func TestSimilaritySearchL2Score(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	// The distance of the first document to the query is 0.
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 1, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.InDelta(t, 1, docs[0].Score, 1e-6)
+
+	_, err = store.SimilaritySearch(context.Background(), "cat", 1, vectorstores.WithScoreThreshold(2))
+	require.ErrorIs(t, err, ErrInvalidScoreThreshold)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStore(t)
+	ids, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "The other cat."}},
+		vectorstores.WithNameSpace("other"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteByFilter(ctx, filters.Eq("animal", true)))
+	assert.Equal(t, 2, store.Len())
+	docs, err := store.SimilaritySearch(ctx, "cat", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	// The documents of the other name spaces are not deleted.
+	require.NoError(t, store.Delete(ctx, ids))
+	assert.Equal(t, 2, store.Len())
+	require.NoError(t, store.Delete(ctx, ids, vectorstores.WithNameSpace("other")))
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestPersist(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "index")
+	store := newTestStore(t, WithDirectory(dir), WithMetric(MetricInnerProduct))
+	require.NoError(t, store.Persist())
+
+	loaded, err := New(WithEmbedder(testutil.WordCountEmbedder{}), WithDirectory(dir))
+	require.NoError(t, err)
+	assert.Equal(t, 3, loaded.Len())
+	assert.Equal(t, MetricInnerProduct, loaded.index.Metric)
+
+	docs, err := loaded.SimilaritySearch(context.Background(), "car", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	// The documents are imported in another store with their vectors.
+	var export bytes.Buffer
+	require.NoError(t, loaded.Export(context.Background(), &export))
+	store, err = New(WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	n, err := vectorstores.Import(context.Background(), store, &export)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, loaded.index.Vectors, store.index.Vectors)
+
+	require.ErrorIs(t, store.Persist(), ErrMissingDirectory)
+}
+
+func TestLoadPythonIndex(t *testing.T) {
+	t.Parallel()
+
+	// The index and the documents of a Python pipeline, with the IDs of an
+	// IndexIDMap.
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	_, err = f.Write(faissFile(t))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DocumentsFile), []byte(`{"version": 1, "documents": [
+		{"content": "The cat sleeps.", "metadata": {"source": "a.txt"}},
+		{"id": "b", "content": "The cat and the dog."}
+	]}`), 0o600))
+
+	store, err := New(WithEmbedder(testutil.WordCountEmbedder{}), WithDirectory(dir))
+	require.NoError(t, err)
+	docs, err := store.SimilaritySearchByVector(context.Background(), []float64{1, 0}, 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"source": "a.txt"}, docs[0].Metadata)
+	assert.InDelta(t, 1, docs[0].Score, 1e-6)
+
+	// The vectors added to an IndexIDMap are given the next IDs.
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "dog"}},
+		vectorstores.WithEmbedder(fixedEmbedder{0, 1}))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10, 42, 43}, store.index.IDs)
+	require.NoError(t, store.Delete(context.Background(), []string{"b"}))
+	assert.Equal(t, []int64{10, 43}, store.index.IDs)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DocumentsFile), []byte(`{"version": 1, "documents": []}`), 0o600))
+	_, err = New(WithEmbedder(testutil.WordCountEmbedder{}), WithDirectory(dir))
+	require.ErrorIs(t, err, ErrInvalidDocuments)
+}
+
+// fixedEmbedder embeds all the texts as its vector.
+type fixedEmbedder []float64
+
+func (e fixedEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = e
+	}
+	return vectors, nil
+}
+
+func (e fixedEmbedder) EmbedQuery(context.Context, string) ([]float64, error) {
+	return e, nil
+}
@@ -0,0 +1,225 @@
+package faiss
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	_fourccFlatL2 = "IxF2"
+	_fourccFlatIP = "IxFI"
+	_fourccFlat   = "IxFl"
+	_fourccIDMap  = "IxMp"
+	// _headerDummy is the value of the deprecated fields of the headers.
+	_headerDummy = 1 << 20
+	// _maxValues bounds the number of values of the vectors read, against
+	// the corrupted files.
+	_maxValues = 1 << 31
+)
+
+var (
+	// ErrInvalidIndex is returned when an index file is not a valid FAISS
+	// index.
+	ErrInvalidIndex = errors.New("invalid index file")
+	// ErrUnsupportedIndex is returned when an index file is a FAISS index
+	// of another type than the flat indexes and their ID maps.
+	ErrUnsupportedIndex = errors.New("unsupported index")
+)
+
+// Metric is the metric type of a FAISS index.
+type Metric int32
+
+const (
+	// MetricInnerProduct is the inner product of the vectors, of the
+	// IndexFlatIP indexes.
+	MetricInnerProduct Metric = 0
+	// MetricL2 is the squared Euclidean distance of the vectors, of the
+	// IndexFlatL2 indexes.
+	MetricL2 Metric = 1
+)
+
+// Index is a flat FAISS index, an IndexFlatL2 or an IndexFlatIP, possibly
+// wrapped in an IndexIDMap, as written by faiss.write_index.
+type Index struct {
+	Dimensions int
+	Metric     Metric
+	Vectors    [][]float32
+	// IDs are the IDs of the vectors of an IndexIDMap, nil for a flat index
+	// identifying its vectors by position.
+	IDs []int64
+}
+
+// ReadIndex reads the FAISS index of the reader.
+func ReadIndex(r io.Reader) (*Index, error) {
+	return readIndex(bufio.NewReader(r), true)
+}
+
+// Write writes the index to the writer in the format of faiss.write_index,
+// as an IndexIDMap of the flat index if it has IDs.
+func (idx *Index) Write(w io.Writer) error {
+	if idx.Metric != MetricL2 && idx.Metric != MetricInnerProduct {
+		return fmt.Errorf("%w: metric %d", ErrUnsupportedIndex, idx.Metric)
+	}
+	for _, vector := range idx.Vectors {
+		if len(vector) != idx.Dimensions {
+			return fmt.Errorf("%w: vector of %d dimensions in an index of %d dimensions",
+				ErrInvalidIndex, len(vector), idx.Dimensions)
+		}
+	}
+	if idx.IDs != nil && len(idx.IDs) != len(idx.Vectors) {
+		return fmt.Errorf("%w: %d IDs for %d vectors", ErrInvalidIndex, len(idx.IDs), len(idx.Vectors))
+	}
+
+	bw := bufio.NewWriter(w)
+	if idx.IDs != nil {
+		if err := idx.writeHeader(bw, _fourccIDMap); err != nil {
+			return err
+		}
+	}
+	fourcc := _fourccFlatL2
+	if idx.Metric == MetricInnerProduct {
+		fourcc = _fourccFlatIP
+	}
+	if err := idx.writeHeader(bw, fourcc); err != nil {
+		return err
+	}
+	// The codes of the vectors are written as a vector of float32.
+	values := make([]float32, 0, len(idx.Vectors)*idx.Dimensions)
+	for _, vector := range idx.Vectors {
+		values = append(values, vector...)
+	}
+	if err := writeVector(bw, values); err != nil {
+		return err
+	}
+	if idx.IDs != nil {
+		if err := writeVector(bw, idx.IDs); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// writeHeader writes the four character code of the type of the index and
+// the header of the index.
+func (idx *Index) writeHeader(w io.Writer, fourcc string) error {
+	header := []any{
+		[]byte(fourcc),
+		int32(idx.Dimensions),
+		int64(len(idx.Vectors)),
+		int64(_headerDummy),
+		int64(_headerDummy),
+		true,
+		int32(idx.Metric),
+	}
+	for _, v := range header {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// header is the header of a FAISS index.
+type header struct {
+	dimensions int
+	total      int64
+	metric     Metric
+}
+
+// readIndex reads an index, an ID map only at the top level.
+func readIndex(r io.Reader, top bool) (*Index, error) {
+	var fourcc [4]byte
+	if _, err := io.ReadFull(r, fourcc[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch string(fourcc[:]) {
+	case _fourccFlatL2, _fourccFlatIP, _fourccFlat:
+		values, err := readVector[float32](r)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(values)) != h.total*int64(h.dimensions) {
+			return nil, fmt.Errorf("%w: %d values for %d vectors of %d dimensions",
+				ErrInvalidIndex, len(values), h.total, h.dimensions)
+		}
+		idx := &Index{Dimensions: h.dimensions, Metric: h.metric, Vectors: make([][]float32, 0, h.total)}
+		for start := 0; start < len(values); start += h.dimensions {
+			idx.Vectors = append(idx.Vectors, values[start:start+h.dimensions:start+h.dimensions])
+		}
+		return idx, nil
+	case _fourccIDMap:
+		if !top {
+			return nil, fmt.Errorf("%w: nested ID map", ErrUnsupportedIndex)
+		}
+		idx, err := readIndex(r, false)
+		if err != nil {
+			return nil, err
+		}
+		idx.IDs, err = readVector[int64](r)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(idx.IDs)) != h.total || len(idx.Vectors) != len(idx.IDs) {
+			return nil, fmt.Errorf("%w: %d IDs for %d vectors", ErrInvalidIndex, len(idx.IDs), len(idx.Vectors))
+		}
+		return idx, nil
+	default:
+		return nil, fmt.Errorf("%w: index type %q", ErrUnsupportedIndex, fourcc[:])
+	}
+}
+
+// readHeader reads the header of an index following its four character
+// code.
+func readHeader(r io.Reader) (header, error) {
+	var fields struct {
+		Dimensions int32
+		Total      int64
+		_          [2]int64
+		Trained    bool
+		Metric     int32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &fields); err != nil {
+		return header{}, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	if fields.Dimensions <= 0 || fields.Total < 0 {
+		return header{}, fmt.Errorf("%w: %d vectors of %d dimensions", ErrInvalidIndex, fields.Total, fields.Dimensions)
+	}
+	metric := Metric(fields.Metric)
+	if metric != MetricL2 && metric != MetricInnerProduct {
+		return header{}, fmt.Errorf("%w: metric %d", ErrUnsupportedIndex, metric)
+	}
+	return header{dimensions: int(fields.Dimensions), total: fields.Total, metric: metric}, nil
+}
+
+// readVector reads a std::vector written by FAISS, its size followed by its
+// values.
+func readVector[T float32 | int64](r io.Reader) ([]T, error) {
+	var size uint64
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	if size > _maxValues {
+		return nil, fmt.Errorf("%w: vector of %d values", ErrInvalidIndex, size)
+	}
+	values := make([]T, size)
+	if err := binary.Read(r, binary.LittleEndian, values); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	return values, nil
+}
+
+// writeVector writes a std::vector as FAISS, its size followed by its values.
+func writeVector[T float32 | int64](w io.Writer, values []T) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(values))); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, values)
+}
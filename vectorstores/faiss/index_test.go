@@ -0,0 +1,81 @@
+package faiss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// faissFile returns an index file as written by faiss.write_index for an
+// IndexIDMap of an IndexFlatIP of two vectors of two dimensions.
+func faissFile(t *testing.T) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	for _, v := range []any{
+		[]byte("IxMp"), int32(2), int64(2), int64(1 << 20), int64(1 << 20), true, int32(0),
+		[]byte("IxFI"), int32(2), int64(2), int64(1 << 20), int64(1 << 20), true, int32(0),
+		uint64(4), []float32{1, 0, 0.5, 0.5},
+		uint64(2), []int64{10, 42},
+	} {
+		require.NoError(t, binary.Write(&b, binary.LittleEndian, v))
+	}
+	return b.Bytes()
+}
+
+func TestReadIndex(t *testing.T) {
+	t.Parallel()
+
+	idx, err := ReadIndex(bytes.NewReader(faissFile(t)))
+	require.NoError(t, err)
+	assert.Equal(t, &Index{
+		Dimensions: 2,
+		Metric:     MetricInnerProduct,
+		Vectors:    [][]float32{{1, 0}, {0.5, 0.5}},
+		IDs:        []int64{10, 42},
+	}, idx)
+
+	// The index is written as read.
+	var b bytes.Buffer
+	require.NoError(t, idx.Write(&b))
+	assert.Equal(t, faissFile(t), b.Bytes())
+}
+
+func TestWriteReadFlatIndex(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Dimensions: 3, Metric: MetricL2, Vectors: [][]float32{{1, 2, 3}, {4, 5, 6}}}
+	var b bytes.Buffer
+	require.NoError(t, idx.Write(&b))
+	assert.Equal(t, "IxF2", b.String()[:4])
+
+	read, err := ReadIndex(&b)
+	require.NoError(t, err)
+	assert.Equal(t, idx, read)
+
+	require.ErrorIs(t, (&Index{Dimensions: 2, Vectors: [][]float32{{1}}, Metric: MetricL2}).Write(&b),
+		ErrInvalidIndex)
+	require.ErrorIs(t, (&Index{Dimensions: 2, Metric: 3}).Write(&b), ErrUnsupportedIndex)
+}
+
+func TestReadInvalidIndex(t *testing.T) {
+	t.Parallel()
+
+	file := faissFile(t)
+	_, err := ReadIndex(bytes.NewReader(file[:len(file)-3]))
+	require.ErrorIs(t, err, ErrInvalidIndex)
+
+	hnsw := append([]byte("IHNf"), file[4:]...)
+	_, err = ReadIndex(bytes.NewReader(hnsw))
+	require.ErrorIs(t, err, ErrUnsupportedIndex)
+
+	nested := append(append([]byte{}, file[:37]...), file...)
+	_, err = ReadIndex(bytes.NewReader(nested))
+	require.ErrorIs(t, err, ErrUnsupportedIndex)
+
+	_, err = ReadIndex(bytes.NewReader(nil))
+	require.ErrorIs(t, err, ErrInvalidIndex)
+}
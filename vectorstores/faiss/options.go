@@ -0,0 +1,57 @@
+package faiss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithMetric is an option for setting the metric of a new index, MetricL2
+// by default as the IndexFlatL2 of the vector stores of LangChain. The
+// metric of an index loaded is the metric of its file.
+func WithMetric(metric Metric) Option {
+	return func(p *Store) {
+		p.index.Metric = metric
+	}
+}
+
+// WithDirectory is an option for loading the index and the documents of the
+// directory, if it exists, when creating the store, see Store.Load.
+// Store.Persist saves the index and the documents to the directory.
+func WithDirectory(dir string) Option {
+	return func(p *Store) {
+		p.dir = dir
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	o := &Store{index: &Index{Metric: MetricL2}}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if o.index.Metric != MetricL2 && o.index.Metric != MetricInnerProduct {
+		return nil, fmt.Errorf("%w: unknown metric %d", ErrInvalidOptions, o.index.Metric)
+	}
+
+	return o, nil
+}
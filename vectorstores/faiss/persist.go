@@ -0,0 +1,186 @@
+package faiss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// IndexFile is the name of the FAISS index file of the directories of
+	// the stores.
+	IndexFile = "index.faiss"
+	// DocumentsFile is the name of the file of the documents of the
+	// directories of the stores.
+	DocumentsFile = "index.json"
+
+	_documentsVersion = 1
+)
+
+var (
+	// ErrMissingDirectory is returned by Persist if the store has no
+	// directory.
+	ErrMissingDirectory = errors.New("missing directory, see WithDirectory")
+	// ErrInvalidDocuments is returned if the documents file of a directory
+	// can not be loaded.
+	ErrInvalidDocuments = errors.New("invalid documents file")
+)
+
+// documentsFile is the JSON file of the documents of the vectors of an index,
+// in the order of the vectors.
+type documentsFile struct {
+	Version   int        `json:"version"`
+	Documents []document `json:"documents"`
+}
+
+// Save writes the index of the store to the IndexFile of the directory, in
+// the format of faiss.write_index, and its documents to the DocumentsFile of
+// the directory. The files are replaced atomically, with temporary files in
+// the directory.
+func (s *Store) Save(dir string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, IndexFile), s.index.Write); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, DocumentsFile), func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(documentsFile{Version: _documentsVersion, Documents: s.documents})
+	})
+}
+
+// Load replaces the index and the documents of the store by the index and
+// the documents of the directory. The DocumentsFile of the directory is a
+// JSON object of the documents of the vectors of the index, in their order,
+// e.g. {"version": 1, "documents": [{"id": "1", "content": "The cat sleeps.",
+// "metadata": {"animal": true}}]}, written by the Python pipelines from the
+// docstore of their index.
+func (s *Store) Load(dir string) error {
+	f, err := os.Open(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	index, err := ReadIndex(f)
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, DocumentsFile))
+	if err != nil {
+		return err
+	}
+	var docs documentsFile
+	if err := json.Unmarshal(b, &docs); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDocuments, err)
+	}
+	if docs.Version != _documentsVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidDocuments, docs.Version)
+	}
+	if len(docs.Documents) != len(index.Vectors) {
+		return fmt.Errorf("%w: %d documents for %d vectors", ErrInvalidDocuments, len(docs.Documents), len(index.Vectors))
+	}
+
+	positions := make(map[string]int, len(docs.Documents))
+	for i, doc := range docs.Documents {
+		// The documents without IDs are given new ones.
+		if doc.ID == "" {
+			docs.Documents[i].ID = uuid.New().String()
+		}
+		positions[docs.Documents[i].ID] = i
+	}
+	var nextID int64
+	for _, id := range index.IDs {
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index = index
+	s.documents = docs.Documents
+	s.positions = positions
+	s.nextID = nextID
+	return nil
+}
+
+// Export writes the records of the documents of the name space of the
+// options, with their vectors, to the writer, one JSON object per line, see
+// vectorstores.Import.
+func (s *Store) Export(ctx context.Context, w io.Writer, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	enc := json.NewEncoder(w)
+	for i, doc := range s.documents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if doc.NameSpace != opts.NameSpace || vectorstores.Expired(schema.Document{Metadata: doc.Metadata}, now) {
+			continue
+		}
+		vector := make([]float64, len(s.index.Vectors[i]))
+		for j, v := range s.index.Vectors[i] {
+			vector[j] = float64(v)
+		}
+		record := vectorstores.Record{ID: doc.ID, Vector: vector, Content: doc.Content, Metadata: doc.Metadata}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Persist saves the index and the documents of the store to the directory of
+// WithDirectory.
+func (s *Store) Persist() error {
+	if s.dir == "" {
+		return ErrMissingDirectory
+	}
+	return s.Save(s.dir)
+}
+
+// loadDir loads the index and the documents of the directory, if its index
+// file exists.
+func (s *Store) loadDir(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, IndexFile)); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return s.Load(dir)
+}
+
+// writeFile replaces the file by the output of write atomically, with a
+// temporary file in the same directory.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,4 @@
+// Package filters contains a typed builder of the metadata filters of the
+// vector stores, e.g. filters.Eq("author", "bob").And(filters.Gt("year",
+// 2020)), which the stores translate to the filters of their databases.
+package filters
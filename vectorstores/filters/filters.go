@@ -0,0 +1,269 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidFilter is returned when a filter cannot be evaluated or
+// translated, e.g. a comparison without a field or an unsupported value.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Op is the operator of a filter.
+type Op string
+
+const (
+	// OpEq matches the documents whose field is equal to the value.
+	OpEq Op = "eq"
+	// OpNe matches the documents whose field is not equal to the value.
+	OpNe Op = "ne"
+	// OpGt matches the documents whose field is greater than the value.
+	OpGt Op = "gt"
+	// OpGte matches the documents whose field is greater than or equal to
+	// the value.
+	OpGte Op = "gte"
+	// OpLt matches the documents whose field is less than the value.
+	OpLt Op = "lt"
+	// OpLte matches the documents whose field is less than or equal to the
+	// value.
+	OpLte Op = "lte"
+	// OpIn matches the documents whose field is equal to one of the values.
+	OpIn Op = "in"
+	// OpNin matches the documents whose field is equal to none of the
+	// values.
+	OpNin Op = "nin"
+	// OpAnd matches the documents matching all the filters.
+	OpAnd Op = "and"
+	// OpOr matches the documents matching any of the filters.
+	OpOr Op = "or"
+	// OpNot matches the documents not matching the filter.
+	OpNot Op = "not"
+)
+
+// Filter is a filter of the documents by their metadata, built with Eq, Gt,
+// And, etc. and passed to vectorstores.WithFilters. The vector stores
+// translate it to the filters of their databases.
+//
+// The values compared are strings, numbers and booleans.
+type Filter struct {
+	Op Op
+	// Field is the metadata key compared by the comparison operators.
+	Field string
+	// Value is the value of the comparison operators, a slice for OpIn and
+	// OpNin.
+	Value any
+	// Filters are the operands of OpAnd, OpOr and OpNot.
+	Filters []Filter
+}
+
+// Eq returns the filter of the documents whose field is equal to the value.
+func Eq(field string, value any) Filter {
+	return Filter{Op: OpEq, Field: field, Value: value}
+}
+
+// Ne returns the filter of the documents whose field is not equal to the
+// value.
+func Ne(field string, value any) Filter {
+	return Filter{Op: OpNe, Field: field, Value: value}
+}
+
+// Gt returns the filter of the documents whose field is greater than the
+// value.
+func Gt(field string, value any) Filter {
+	return Filter{Op: OpGt, Field: field, Value: value}
+}
+
+// Gte returns the filter of the documents whose field is greater than or
+// equal to the value.
+func Gte(field string, value any) Filter {
+	return Filter{Op: OpGte, Field: field, Value: value}
+}
+
+// Lt returns the filter of the documents whose field is less than the value.
+func Lt(field string, value any) Filter {
+	return Filter{Op: OpLt, Field: field, Value: value}
+}
+
+// Lte returns the filter of the documents whose field is less than or equal
+// to the value.
+func Lte(field string, value any) Filter {
+	return Filter{Op: OpLte, Field: field, Value: value}
+}
+
+// In returns the filter of the documents whose field is equal to one of the
+// values.
+func In(field string, values ...any) Filter {
+	return Filter{Op: OpIn, Field: field, Value: values}
+}
+
+// Nin returns the filter of the documents whose field is equal to none of
+// the values.
+func Nin(field string, values ...any) Filter {
+	return Filter{Op: OpNin, Field: field, Value: values}
+}
+
+// And returns the filter of the documents matching all the filters.
+func And(filters ...Filter) Filter {
+	return Filter{Op: OpAnd, Filters: filters}
+}
+
+// Or returns the filter of the documents matching any of the filters.
+func Or(filters ...Filter) Filter {
+	return Filter{Op: OpOr, Filters: filters}
+}
+
+// Not returns the filter of the documents not matching the filter.
+func Not(filter Filter) Filter {
+	return Filter{Op: OpNot, Filters: []Filter{filter}}
+}
+
+// And returns the filter of the documents matching the filter and all the
+// others.
+func (f Filter) And(others ...Filter) Filter {
+	return And(append([]Filter{f}, others...)...)
+}
+
+// Or returns the filter of the documents matching the filter or any of the
+// others.
+func (f Filter) Or(others ...Filter) Filter {
+	return Or(append([]Filter{f}, others...)...)
+}
+
+// Values returns the values of the OpIn and OpNin filters.
+func (f Filter) Values() ([]any, error) {
+	values, ok := f.Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s requires a slice of values, got %T", ErrInvalidFilter, f.Op, f.Value)
+	}
+	return values, nil
+}
+
+// Validate returns an error wrapping ErrInvalidFilter if the filter or one
+// of its operands is malformed.
+func (f Filter) Validate() error {
+	switch f.Op {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
+		if f.Field == "" {
+			return fmt.Errorf("%w: %s requires a field", ErrInvalidFilter, f.Op)
+		}
+		return validateValue(f.Value)
+	case OpIn, OpNin:
+		if f.Field == "" {
+			return fmt.Errorf("%w: %s requires a field", ErrInvalidFilter, f.Op)
+		}
+		values, err := f.Values()
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("%w: %s requires values", ErrInvalidFilter, f.Op)
+		}
+		for _, value := range values {
+			if err := validateValue(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OpAnd, OpOr:
+		if len(f.Filters) == 0 {
+			return fmt.Errorf("%w: %s requires filters", ErrInvalidFilter, f.Op)
+		}
+	case OpNot:
+		if len(f.Filters) != 1 {
+			return fmt.Errorf("%w: not requires one filter, got %d", ErrInvalidFilter, len(f.Filters))
+		}
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Op)
+	}
+	for _, filter := range f.Filters {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Negate returns the filter with its negations pushed down to the
+// comparisons, e.g. Ne("a", 1) for Not(Eq("a", 1)), for the databases
+// without a not operator.
+func (f Filter) Negate() Filter {
+	switch f.Op {
+	case OpEq:
+		return Filter{Op: OpNe, Field: f.Field, Value: f.Value}
+	case OpNe:
+		return Filter{Op: OpEq, Field: f.Field, Value: f.Value}
+	case OpGt:
+		return Filter{Op: OpLte, Field: f.Field, Value: f.Value}
+	case OpGte:
+		return Filter{Op: OpLt, Field: f.Field, Value: f.Value}
+	case OpLt:
+		return Filter{Op: OpGte, Field: f.Field, Value: f.Value}
+	case OpLte:
+		return Filter{Op: OpGt, Field: f.Field, Value: f.Value}
+	case OpIn:
+		return Filter{Op: OpNin, Field: f.Field, Value: f.Value}
+	case OpNin:
+		return Filter{Op: OpIn, Field: f.Field, Value: f.Value}
+	case OpAnd, OpOr:
+		op := OpOr
+		if f.Op == OpOr {
+			op = OpAnd
+		}
+		negated := make([]Filter, 0, len(f.Filters))
+		for _, filter := range f.Filters {
+			negated = append(negated, filter.Negate())
+		}
+		return Filter{Op: op, Filters: negated}
+	case OpNot:
+		if len(f.Filters) == 1 {
+			return f.Filters[0]
+		}
+	}
+	return Not(f)
+}
+
+func validateValue(value any) error {
+	switch value.(type) {
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return nil
+	default:
+		return fmt.Errorf("%w: string, number or boolean value required, got %T", ErrInvalidFilter, value)
+	}
+}
+
+// String returns the filter as an expression, e.g. (author = "bob" AND
+// year > 2020), for the logs and the errors.
+func (f Filter) String() string {
+	switch f.Op {
+	case OpAnd, OpOr:
+		parts := make([]string, 0, len(f.Filters))
+		for _, filter := range f.Filters {
+			parts = append(parts, filter.String())
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(string(f.Op))+" ") + ")"
+	case OpNot:
+		if len(f.Filters) == 1 {
+			return "NOT " + f.Filters[0].String()
+		}
+	case OpIn, OpNin:
+		values, _ := f.Values()
+		parts := make([]string, 0, len(values))
+		for _, value := range values {
+			parts = append(parts, fmt.Sprintf("%#v", value))
+		}
+		return fmt.Sprintf("%s %s (%s)", f.Field, strings.ToUpper(string(f.Op)), strings.Join(parts, ", "))
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
+		return fmt.Sprintf("%s %s %#v", f.Field, _symbols[f.Op], f.Value)
+	}
+	return fmt.Sprintf("%s(%v)", f.Op, f.Filters)
+}
+
+var _symbols = map[Op]string{ //nolint:gochecknoglobals
+	OpEq:  "=",
+	OpNe:  "!=",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
@@ -0,0 +1,72 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	metadata := map[string]any{"author": "bob", "year": float64(2021), "draft": false}
+	cases := []struct {
+		filter Filter
+		want   bool
+	}{
+		{Eq("author", "bob"), true},
+		{Eq("author", "alice"), false},
+		{Eq("author", "bob").And(Gt("year", 2020)), true},
+		{Eq("author", "bob").And(Gt("year", 2021)), false},
+		{Gte("year", 2021).Or(Eq("author", "alice")), true},
+		{Lt("year", 2021), false},
+		{Lte("year", int64(2021)), true},
+		{Ne("author", "alice"), true},
+		{In("author", "alice", "bob"), true},
+		{Nin("author", "alice", "bob"), false},
+		{Nin("author", "alice"), true},
+		{Eq("draft", false), true},
+		{Not(Eq("draft", true)), true},
+		{Not(Eq("author", "bob").Or(Eq("year", 2020))), false},
+		// The comparisons of missing fields and of values of different
+		// types are false, and so are their negations.
+		{Eq("title", "x"), false},
+		{Ne("title", "x"), false},
+		{Not(Eq("title", "x")), false},
+		{Gt("author", 1), false},
+		{Nin("year", "2021"), false},
+	}
+	for _, c := range cases {
+		got, err := Match(c.filter, metadata)
+		require.NoError(t, err)
+		assert.Equal(t, c.want, got, c.filter.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Eq("a", 1).And(Not(In("b", "x", "y"))).Validate())
+	require.ErrorIs(t, Eq("", 1).Validate(), ErrInvalidFilter)
+	require.ErrorIs(t, Eq("a", []int{1}).Validate(), ErrInvalidFilter)
+	require.ErrorIs(t, And().Validate(), ErrInvalidFilter)
+	require.ErrorIs(t, In("a").Validate(), ErrInvalidFilter)
+	require.ErrorIs(t, Filter{Op: "like", Field: "a"}.Validate(), ErrInvalidFilter)
+	require.ErrorIs(t, Eq("a", 1).Or(Gt("b", map[string]any{})).Validate(), ErrInvalidFilter)
+}
+
+func TestNegate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Ne("a", 1), Eq("a", 1).Negate())
+	assert.Equal(t, Or(Lte("a", 1), Nin("b", "x")), And(Gt("a", 1), In("b", "x")).Negate())
+	assert.Equal(t, Eq("a", 1), Not(Eq("a", 1)).Negate())
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `(author = "bob" AND year > 2020)`, Eq("author", "bob").And(Gt("year", 2020)).String())
+	assert.Equal(t, `NOT a IN ("x", 1)`, Not(In("a", "x", 1)).String())
+}
@@ -0,0 +1,139 @@
+package filters
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Match reports whether the metadata matches the filter, for the vector
+// stores filtering the documents in memory. The comparisons of a missing
+// field or of values of different types are false, as in the databases,
+// and so are their negations with Ne, Nin and Not.
+func Match(filter Filter, metadata map[string]any) (bool, error) {
+	if err := filter.Validate(); err != nil {
+		return false, err
+	}
+	return match(filter, metadata), nil
+}
+
+func match(f Filter, metadata map[string]any) bool {
+	switch f.Op {
+	case OpAnd:
+		for _, filter := range f.Filters {
+			if !match(filter, metadata) {
+				return false
+			}
+		}
+		return true
+	case OpOr:
+		for _, filter := range f.Filters {
+			if match(filter, metadata) {
+				return true
+			}
+		}
+		return false
+	case OpNot:
+		return match(f.Filters[0].Negate(), metadata)
+	case OpIn, OpNin:
+		field, ok := metadata[f.Field]
+		if !ok {
+			return false
+		}
+		values, _ := f.Values()
+		typed := true
+		for _, value := range values {
+			c, ok := compare(field, value)
+			if ok && c == 0 {
+				return f.Op == OpIn
+			}
+			typed = typed && ok
+		}
+		return f.Op == OpNin && typed
+	default:
+		return compareField(f, metadata)
+	}
+}
+
+// compareField reports whether the field of the metadata matches the
+// comparison of the filter.
+func compareField(f Filter, metadata map[string]any) bool {
+	field, ok := metadata[f.Field]
+	if !ok {
+		return false
+	}
+	c, ok := compare(field, f.Value)
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case OpEq:
+		return c == 0
+	case OpNe:
+		return c != 0
+	case OpGt:
+		return c > 0
+	case OpGte:
+		return c >= 0
+	case OpLt:
+		return c < 0
+	case OpLte:
+		return c <= 0
+	default:
+		return false
+	}
+}
+
+// compare returns the order of the values, false if they are not both
+// strings, numbers or booleans. Numbers of different types are compared as
+// float64, as the numbers of metadata decoded from JSON are.
+func compare(a, b any) (int, bool) {
+	if aNumber, ok := toFloat(a); ok {
+		bNumber, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case aNumber < bNumber:
+			return -1, true
+		case aNumber > bNumber:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(a, b), true
+	case bool:
+		b, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case a == b:
+			return 0, true
+		case b:
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func toFloat(v any) (float64, bool) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
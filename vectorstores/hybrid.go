@@ -0,0 +1,284 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/embeddings/bm25"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrInvalidHybridQuery is returned when a hybrid query is invalid.
+	ErrInvalidHybridQuery = errors.New("invalid hybrid query")
+	// ErrFusionNotSupported is returned by the vector stores not supporting
+	// the fusion of a hybrid query.
+	ErrFusionNotSupported = errors.New("fusion not supported by the vector store")
+)
+
+// _defaultRankConstant is the rank constant of reciprocal rank fusion.
+const _defaultRankConstant = 60
+
+// _hybridWindow is the number of candidates of the client-side hybrid
+// searches per document requested.
+const _hybridWindow = 4
+
+// Fusion is the strategy fusing the results of the dense and the keyword or
+// sparse searches of a hybrid search.
+type Fusion string
+
+const (
+	// FusionDefault is the fusion of the vector store, reciprocal rank
+	// fusion for the client-side hybrid searches.
+	FusionDefault Fusion = ""
+	// FusionRRF is reciprocal rank fusion: the score of a document is the
+	// sum of the weight / (rank constant + rank) of its ranks.
+	FusionRRF Fusion = "rrf"
+	// FusionWeighted is the weighted sum of the scores of a document,
+	// normalized between 0 and 1.
+	FusionWeighted Fusion = "weighted"
+)
+
+// HybridQuery is the query of a hybrid search, fusing a dense vector search
+// and a keyword or sparse vector search.
+type HybridQuery struct {
+	// Text is the text of the query, searched by keywords and embedded with
+	// the embedders of the store unless the vectors are set.
+	Text string
+	// Vector is the dense vector of the query. Nil to embed the text.
+	Vector []float64
+	// Sparse is the sparse vector of the query. Nil to embed the text with
+	// the sparse embedder of the options, if any.
+	Sparse *embeddings.SparseVector
+	// Alpha is the weight of the dense search, from 0 for a keyword or
+	// sparse search only to 1 for a dense search only, the weight of the
+	// other search being 1 - Alpha.
+	Alpha float64
+	// Fusion is the strategy fusing the results of the searches.
+	Fusion Fusion
+}
+
+// Validate returns an error if the query has neither text nor vector, if the
+// alpha is not between 0 and 1, or if the fusion is unknown.
+func (q HybridQuery) Validate() error {
+	if q.Text == "" && q.Vector == nil {
+		return fmt.Errorf("%w: text or vector required", ErrInvalidHybridQuery)
+	}
+	if q.Alpha < 0 || q.Alpha > 1 {
+		return fmt.Errorf("%w: alpha must be between 0 and 1", ErrInvalidHybridQuery)
+	}
+	switch q.Fusion {
+	case FusionDefault, FusionRRF, FusionWeighted:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown fusion %q", ErrInvalidHybridQuery, q.Fusion)
+	}
+}
+
+// HybridSearcher is the interface of the vector stores supporting hybrid
+// searches natively. The options are those of SimilaritySearch.
+type HybridSearcher interface {
+	HybridSearch(ctx context.Context, query HybridQuery, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
+}
+
+// HybridSearch returns the documents of the hybrid search of the query in the
+// store, with their fused scores. The stores implementing HybridSearcher
+// search natively. With the other stores, the documents found by
+// SimilaritySearch for the text of the query, four times as many as
+// requested, are reranked by fusing their ranking with their ranking by the
+// sparse embedder of the options or, without one, by BM25 over these
+// documents, so that only the documents found by the dense search are
+// returned.
+func HybridSearch(
+	ctx context.Context,
+	store VectorStore,
+	query HybridQuery,
+	numDocuments int,
+	options ...Option,
+) ([]schema.Document, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if searcher, ok := store.(HybridSearcher); ok {
+		return searcher.HybridSearch(ctx, query, numDocuments, options...)
+	}
+
+	if query.Vector != nil || query.Text == "" {
+		return nil, fmt.Errorf("%w: text without vector required by the store", ErrInvalidHybridQuery)
+	}
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if query.Sparse != nil && opts.SparseEmbedder == nil {
+		return nil, fmt.Errorf("%w: sparse vector without sparse embedder", ErrInvalidHybridQuery)
+	}
+
+	// The dense search is a plain similarity search.
+	denseOptions := append(options[:len(options):len(options)], func(o *Options) {
+		o.SparseEmbedder = nil
+		o.Alpha = nil
+		o.MMR = nil
+	})
+	candidates, err := store.SimilaritySearch(ctx, query.Text, _hybridWindow*numDocuments, denseOptions...)
+	if err != nil {
+		return nil, err
+	}
+	keywordScores, err := sparseScores(ctx, query, candidates, opts.SparseEmbedder)
+	if err != nil {
+		return nil, err
+	}
+
+	dense := make([]Ranked, 0, len(candidates))
+	sparse := make([]Ranked, 0, len(candidates))
+	for i, doc := range candidates {
+		dense = append(dense, Ranked{Key: strconv.Itoa(i), Score: doc.Score})
+		// The documents without the keywords are not ranked.
+		if keywordScores[i] > 0 {
+			sparse = append(sparse, Ranked{Key: strconv.Itoa(i), Score: keywordScores[i]})
+		}
+	}
+	sort.SliceStable(sparse, func(i, j int) bool { return sparse[i].Score > sparse[j].Score })
+
+	var fused []Ranked
+	if query.Fusion == FusionWeighted {
+		fused = FuseWeighted(query.Alpha, dense, sparse)
+	} else {
+		fused = FuseRRF(query.Alpha, _defaultRankConstant, dense, sparse)
+	}
+	if len(fused) > numDocuments {
+		fused = fused[:numDocuments]
+	}
+	docs := make([]schema.Document, 0, len(fused))
+	for _, r := range fused {
+		i, _ := strconv.Atoi(r.Key)
+		doc := candidates[i]
+		doc.Score = r.Score
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// sparseScores returns the dot products of the sparse vectors of the query
+// and of the documents, embedded with the sparse embedder or, if nil, with
+// BM25 fitted on the documents.
+func sparseScores(
+	ctx context.Context,
+	query HybridQuery,
+	docs []schema.Document,
+	embedder embeddings.SparseEmbedder,
+) ([]float64, error) {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	if embedder == nil {
+		b := bm25.New()
+		b.Fit(texts)
+		embedder = b
+	}
+
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	queryVector := query.Sparse
+	if queryVector == nil {
+		v, err := embedder.EmbedQuery(ctx, query.Text)
+		if err != nil {
+			return nil, err
+		}
+		queryVector = &v
+	}
+
+	weights := make(map[int]float64, len(queryVector.Indices))
+	for i, index := range queryVector.Indices {
+		weights[index] = queryVector.Values[i]
+	}
+	scores := make([]float64, 0, len(vectors))
+	for _, vector := range vectors {
+		var score float64
+		for i, index := range vector.Indices {
+			score += weights[index] * vector.Values[i]
+		}
+		scores = append(scores, score)
+	}
+	return scores, nil
+}
+
+// Ranked is a document of a ranking, identified by a key, with its score.
+type Ranked struct {
+	Key   string
+	Score float64
+}
+
+// FuseRRF returns the documents of the rankings by their scores of
+// reciprocal rank fusion, the sum of the weight / (rank constant + rank) of
+// their ranks, alpha weighting the dense ranking and 1 - alpha the sparse
+// ranking. The documents with equal scores are ordered by key.
+func FuseRRF(alpha float64, rankConstant int, dense, sparse []Ranked) []Ranked {
+	scores := make(map[string]float64, len(dense)+len(sparse))
+	for rank, r := range dense {
+		scores[r.Key] += alpha / float64(rankConstant+rank+1)
+	}
+	for rank, r := range sparse {
+		scores[r.Key] += (1 - alpha) / float64(rankConstant+rank+1)
+	}
+	return sortScores(scores)
+}
+
+// FuseWeighted returns the documents of the rankings by the weighted sum of
+// their scores, normalized between 0 and 1 by the minimum and maximum scores
+// of each ranking, alpha weighting the dense scores and 1 - alpha the sparse
+// scores. The documents missing from a ranking score 0 in it, the scores of
+// a ranking of equal scores are normalized to 1.
+func FuseWeighted(alpha float64, dense, sparse []Ranked) []Ranked {
+	scores := make(map[string]float64, len(dense)+len(sparse))
+	for _, ranking := range []struct {
+		ranked []Ranked
+		weight float64
+	}{{dense, alpha}, {sparse, 1 - alpha}} {
+		if len(ranking.ranked) == 0 {
+			continue
+		}
+		low, high := ranking.ranked[0].Score, ranking.ranked[0].Score
+		for _, r := range ranking.ranked {
+			if r.Score < low {
+				low = r.Score
+			}
+			if r.Score > high {
+				high = r.Score
+			}
+		}
+		for _, r := range ranking.ranked {
+			normalized := 1.0
+			if high > low {
+				normalized = (r.Score - low) / (high - low)
+			}
+			scores[r.Key] += ranking.weight * normalized
+		}
+	}
+	return sortScores(scores)
+}
+
+// sortScores returns the scores sorted in descending order, then by key.
+func sortScores(scores map[string]float64) []Ranked {
+	fused := make([]Ranked, 0, len(scores))
+	for key, score := range scores {
+		fused = append(fused, Ranked{Key: key, Score: score})
+	}
+	sort.Slice(fused, func(i, j int) bool {
+		if fused[i].Score != fused[j].Score {
+			return fused[i].Score > fused[j].Score
+		}
+		return fused[i].Key < fused[j].Key
+	})
+	return fused
+}
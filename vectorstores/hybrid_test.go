@@ -0,0 +1,114 @@
+package vectorstores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// fakeStore returns its documents, in order, to the similarity searches.
+type fakeStore struct {
+	docs    []schema.Document
+	options []Options
+}
+
+func (s *fakeStore) AddDocuments(context.Context, []schema.Document, ...Option) ([]string, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) SimilaritySearch(_ context.Context, _ string, numDocuments int, options ...Option) ([]schema.Document, error) { //nolint:lll
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	s.options = append(s.options, opts)
+	if len(s.docs) > numDocuments {
+		return s.docs[:numDocuments], nil
+	}
+	return s.docs, nil
+}
+
+func (s *fakeStore) Delete(context.Context, []string, ...Option) error { return nil }
+
+func (s *fakeStore) DeleteByFilter(context.Context, any, ...Option) error { return nil }
+
+type fakeSparseEmbedder struct{}
+
+func (fakeSparseEmbedder) EmbedDocuments(_ context.Context, texts []string) ([]embeddings.SparseVector, error) {
+	vectors := make([]embeddings.SparseVector, len(texts))
+	// The last text is the only one with the term.
+	vectors[len(texts)-1] = embeddings.SparseVector{Indices: []int{1}, Values: []float64{1}}
+	return vectors, nil
+}
+
+func (fakeSparseEmbedder) EmbedQuery(context.Context, string) (embeddings.SparseVector, error) {
+	return embeddings.SparseVector{Indices: []int{1}, Values: []float64{1}}, nil
+}
+
+func TestHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &fakeStore{docs: []schema.Document{
+		{PageContent: "The cat sleeps.", Score: 0.9},
+		{PageContent: "The dog barks.", Score: 0.8},
+		{PageContent: "The red car.", Score: 0.7},
+	}}
+
+	// The keywords rerank the documents of the dense search.
+	docs, err := HybridSearch(ctx, store, HybridQuery{Text: "red car"}, 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The red car.", docs[0].PageContent)
+	assert.InDelta(t, 1.0/61, docs[0].Score, 1e-9)
+
+	docs, err = HybridSearch(ctx, store, HybridQuery{Text: "red car", Alpha: 1}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, "The dog barks.", docs[1].PageContent)
+
+	docs, err = HybridSearch(ctx, store, HybridQuery{Text: "red car", Alpha: 0.4, Fusion: FusionWeighted}, 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "The red car.", docs[0].PageContent)
+	assert.InDelta(t, 0.6, docs[0].Score, 1e-9)
+	assert.Equal(t, "The cat sleeps.", docs[1].PageContent)
+
+	// The sparse embedder of the options scores the documents, and is not
+	// passed to the dense search.
+	docs, err = HybridSearch(ctx, store, HybridQuery{Text: "pets"}, 1, WithSparseEmbedder(fakeSparseEmbedder{}))
+	require.NoError(t, err)
+	assert.Equal(t, "The red car.", docs[0].PageContent)
+	last := store.options[len(store.options)-1]
+	assert.Nil(t, last.SparseEmbedder)
+
+	_, err = HybridSearch(ctx, store, HybridQuery{Vector: []float64{1}}, 1)
+	require.ErrorIs(t, err, ErrInvalidHybridQuery)
+	_, err = HybridSearch(ctx, store, HybridQuery{}, 1)
+	require.ErrorIs(t, err, ErrInvalidHybridQuery)
+	_, err = HybridSearch(ctx, store, HybridQuery{Text: "cat", Fusion: "max"}, 1)
+	require.ErrorIs(t, err, ErrInvalidHybridQuery)
+}
+
+func TestFuse(t *testing.T) {
+	t.Parallel()
+
+	dense := []Ranked{{Key: "a", Score: 0.9}, {Key: "b", Score: 0.5}}
+	sparse := []Ranked{{Key: "b", Score: 12}, {Key: "c", Score: 2}}
+
+	assert.Equal(t, []Ranked{
+		{Key: "b", Score: 0.5/2 + 0.5/1},
+		{Key: "a", Score: 0.5 / 1},
+		{Key: "c", Score: 0.5 / 2},
+	}, FuseRRF(0.5, 0, dense, sparse))
+
+	assert.Equal(t, []Ranked{
+		{Key: "b", Score: 0.75},
+		{Key: "a", Score: 0.25},
+		{Key: "c", Score: 0},
+	}, FuseWeighted(0.25, dense, sparse))
+}
@@ -0,0 +1,79 @@
+package vectorstores
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrWrongNumberIDs is returned when the number of IDs of WithIDs is not
+	// equal to the number of documents added.
+	ErrWrongNumberIDs = errors.New("number of IDs does not match number of documents")
+	// ErrMissingFilter is returned by DeleteByFilter when the filter is nil,
+	// rather than deleting all the documents.
+	ErrMissingFilter = errors.New("missing filter")
+)
+
+// _idNameSpace is the name space of the UUIDs derived from the keys of the
+// documents.
+var _idNameSpace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/tmc/langchaingo/vectorstores")) //nolint:gochecknoglobals,lll
+
+// WithIDs returns an Option for setting the IDs of the documents added, one
+// per document. The documents with the ID of a document of the store replace
+// it.
+func WithIDs(ids ...string) Option {
+	return func(o *Options) {
+		o.IDs = ids
+	}
+}
+
+// WithDeterministicIDs returns an Option for deriving the IDs of the
+// documents added from their keys, e.g. ContentHash or the source and the
+// position of a chunk. As the documents with the same key get the same ID,
+// adding a corpus again does not duplicate its documents, and with keys
+// identifying the chunks rather than their content, the changed chunks
+// replace their previous version. The IDs are name-based UUIDs, supported by
+// all the stores, derived from the tenant of WithTenant too, so that the
+// documents of two tenants never share an ID. WithIDs takes precedence.
+func WithDeterministicIDs(key func(doc schema.Document) string) Option {
+	return func(o *Options) {
+		o.IDKey = key
+	}
+}
+
+// ContentHash returns the hex-encoded SHA-256 hash of the content of the
+// document, a key of WithDeterministicIDs deduplicating the documents with
+// the same content.
+func ContentHash(doc schema.Document) string {
+	hash := sha256.Sum256([]byte(doc.PageContent))
+	return hex.EncodeToString(hash[:])
+}
+
+// DocumentIDs returns the IDs of the documents added with the options, the
+// IDs of WithIDs, the IDs derived from the keys of WithDeterministicIDs or
+// new random UUIDs.
+func DocumentIDs(opts Options, docs []schema.Document) ([]string, error) {
+	if opts.IDs != nil {
+		if len(opts.IDs) != len(docs) {
+			return nil, ErrWrongNumberIDs
+		}
+		return opts.IDs, nil
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if opts.IDKey != nil {
+			key := opts.IDKey(doc)
+			if opts.Tenant != "" {
+				key = opts.Tenant + "\x00" + key
+			}
+			ids = append(ids, uuid.NewSHA1(_idNameSpace, []byte(key)).String())
+			continue
+		}
+		ids = append(ids, uuid.New().String())
+	}
+	return ids, nil
+}
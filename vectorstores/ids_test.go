@@ -0,0 +1,66 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestDocumentIDs(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "a"}, {PageContent: "a"}}
+	ids, err := DocumentIDs(Options{}, docs)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+	_, err = uuid.Parse(ids[0])
+	require.NoError(t, err)
+
+	opts := Options{}
+	WithIDs("a", "b")(&opts)
+	ids, err = DocumentIDs(opts, docs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	_, err = DocumentIDs(opts, docs[:1])
+	require.ErrorIs(t, err, ErrWrongNumberIDs)
+}
+
+func TestDeterministicIDs(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{}
+	WithDeterministicIDs(ContentHash)(&opts)
+	docs := []schema.Document{{PageContent: "a"}, {PageContent: "b"}, {PageContent: "a"}}
+	ids, err := DocumentIDs(opts, docs)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	assert.Equal(t, ids[0], ids[2])
+	assert.NotEqual(t, ids[0], ids[1])
+	id, err := uuid.Parse(ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(5), id.Version())
+
+	// The IDs do not change across calls.
+	again, err := DocumentIDs(opts, docs[:1])
+	require.NoError(t, err)
+	assert.Equal(t, ids[:1], again)
+
+	// The keys identify the chunks rather than their content.
+	WithDeterministicIDs(func(doc schema.Document) string {
+		return doc.Metadata["source"].(string) //nolint:forcetypeassert
+	})(&opts)
+	ids, err = DocumentIDs(opts, []schema.Document{
+		{PageContent: "old", Metadata: map[string]any{"source": "a.txt"}},
+		{PageContent: "new", Metadata: map[string]any{"source": "a.txt"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], ids[1])
+
+	assert.Equal(t, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+		ContentHash(schema.Document{PageContent: "a"}))
+}
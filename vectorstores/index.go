@@ -0,0 +1,45 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrIndexNotFound is returned when the index of a vector store does not
+// exist.
+var ErrIndexNotFound = errors.New("index not found")
+
+// IndexInfo describes the index of a vector store.
+type IndexInfo struct {
+	// Name is the name of the index, or of the collection holding it.
+	Name string
+	// Dimensions is the number of dimensions of the vectors of the index.
+	Dimensions int
+	// Metric is the similarity or distance metric of the vectors, in the
+	// terms of the store, e.g. cosine or COSINE.
+	Metric string
+}
+
+// IndexStats are the statistics of the index of a vector store.
+type IndexStats struct {
+	// NumDocuments is the number of documents of the index.
+	NumDocuments int
+}
+
+// IndexManager is the interface of the vector stores managing the lifecycle
+// of their index, so that deployment code provisions the indexes
+// programmatically. The stores create their index by default when created,
+// unless told to skip it.
+type IndexManager interface {
+	// CreateIndex creates the index with the options of the store if it does
+	// not exist.
+	CreateIndex(ctx context.Context) error
+	// DeleteIndex deletes the index with its documents.
+	DeleteIndex(ctx context.Context) error
+	// DescribeIndex returns the description of the index, or
+	// ErrIndexNotFound if it does not exist.
+	DescribeIndex(ctx context.Context) (IndexInfo, error)
+	// Stats returns the statistics of the index, or ErrIndexNotFound if it
+	// does not exist.
+	Stats(ctx context.Context) (IndexStats, error)
+}
@@ -0,0 +1,3 @@
+// Package inmemory contains an implementation of the vectorStore
+// interface holding the documents in memory, with snapshots to files.
+package inmemory
@@ -0,0 +1,221 @@
+package inmemory
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// hnsw is a hierarchical navigable small world graph of the vectors of the
+// documents, for approximate nearest neighbor searches. The nodes are the
+// indices of the documents of the store, the distance between the
+// normalized vectors is 1 - their cosine similarity.
+//
+// See https://arxiv.org/abs/1603.09320.
+type hnsw struct {
+	m              int
+	efConstruction int
+	efSearch       int
+	levelFactor    float64
+	rand           *rand.Rand
+
+	// neighbors are the neighbors of the nodes at each of their levels.
+	neighbors  [][][]int
+	entryPoint int
+	maxLevel   int
+}
+
+func newHNSW(m, efConstruction, efSearch int) *hnsw {
+	h := &hnsw{
+		m:              m,
+		efConstruction: efConstruction,
+		efSearch:       efSearch,
+		// The graph is deterministic, the same documents giving the same
+		// results.
+		rand:       rand.New(rand.NewSource(1)), //nolint:gosec
+		entryPoint: -1,
+	}
+	if m > 1 {
+		h.levelFactor = 1 / math.Log(float64(m))
+	}
+	return h
+}
+
+// reset returns an empty graph with the same parameters.
+func (h *hnsw) reset() *hnsw {
+	return newHNSW(h.m, h.efConstruction, h.efSearch)
+}
+
+// candidate is a node at a distance from the query.
+type candidate struct {
+	node     int
+	distance float64
+}
+
+// candidates is a heap of candidates, the nearest first or, if far, the
+// farthest first.
+type candidates struct {
+	items []candidate
+	far   bool
+}
+
+func (c *candidates) Len() int { return len(c.items) }
+
+func (c *candidates) Less(i, j int) bool {
+	if c.far {
+		return c.items[i].distance > c.items[j].distance
+	}
+	return c.items[i].distance < c.items[j].distance
+}
+
+func (c *candidates) Swap(i, j int) { c.items[i], c.items[j] = c.items[j], c.items[i] }
+
+func (c *candidates) Push(x any) { c.items = append(c.items, x.(candidate)) } //nolint:forcetypeassert
+
+func (c *candidates) Pop() any {
+	last := c.items[len(c.items)-1]
+	c.items = c.items[:len(c.items)-1]
+	return last
+}
+
+// insert adds the node to the graph, vectors being the normalized vectors
+// of all the nodes.
+func (h *hnsw) insert(node int, vectors [][]float64) {
+	level := int(-math.Log(1-h.rand.Float64()) * h.levelFactor)
+	h.neighbors = append(h.neighbors, make([][]int, level+1))
+
+	if h.entryPoint < 0 {
+		h.entryPoint = node
+		h.maxLevel = level
+		return
+	}
+
+	query := vectors[node]
+	entryPoint := h.entryPoint
+	for l := h.maxLevel; l > level; l-- {
+		entryPoint = h.searchLayer(query, entryPoint, 1, l, vectors)[0].node
+	}
+
+	for l := minInt(level, h.maxLevel); l >= 0; l-- {
+		nearest := h.searchLayer(query, entryPoint, h.efConstruction, l, vectors)
+		maxNeighbors := h.maxNeighbors(l)
+		for _, c := range nearest[:minInt(h.m, len(nearest))] {
+			h.neighbors[node][l] = append(h.neighbors[node][l], c.node)
+			h.neighbors[c.node][l] = append(h.neighbors[c.node][l], node)
+			if len(h.neighbors[c.node][l]) > maxNeighbors {
+				h.neighbors[c.node][l] = nearestNodes(vectors[c.node], h.neighbors[c.node][l], maxNeighbors, vectors)
+			}
+		}
+		entryPoint = nearest[0].node
+	}
+
+	if level > h.maxLevel {
+		h.entryPoint = node
+		h.maxLevel = level
+	}
+}
+
+// search returns the ef nodes nearest to the normalized query, the nearest
+// first.
+func (h *hnsw) search(query []float64, ef int, vectors [][]float64) []candidate {
+	if h.entryPoint < 0 {
+		return nil
+	}
+	entryPoint := h.entryPoint
+	for l := h.maxLevel; l > 0; l-- {
+		entryPoint = h.searchLayer(query, entryPoint, 1, l, vectors)[0].node
+	}
+	return h.searchLayer(query, entryPoint, ef, 0, vectors)
+}
+
+// searchLayer returns the ef nodes of the level nearest to the query, the
+// nearest first, with a greedy search from the entry point.
+func (h *hnsw) searchLayer(query []float64, entryPoint, ef, level int, vectors [][]float64) []candidate {
+	visited := map[int]bool{entryPoint: true}
+	start := candidate{node: entryPoint, distance: distance(query, vectors[entryPoint])}
+	toVisit := &candidates{items: []candidate{start}}
+	nearest := &candidates{items: []candidate{start}, far: true}
+
+	for toVisit.Len() > 0 {
+		current := heap.Pop(toVisit).(candidate) //nolint:forcetypeassert
+		if nearest.Len() >= ef && current.distance > nearest.items[0].distance {
+			break
+		}
+		for _, neighbor := range h.neighbors[current.node][level] {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+			c := candidate{node: neighbor, distance: distance(query, vectors[neighbor])}
+			if nearest.Len() < ef || c.distance < nearest.items[0].distance {
+				heap.Push(toVisit, c)
+				heap.Push(nearest, c)
+				if nearest.Len() > ef {
+					heap.Pop(nearest)
+				}
+			}
+		}
+	}
+
+	result := make([]candidate, nearest.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(nearest).(candidate) //nolint:forcetypeassert
+	}
+	return result
+}
+
+// maxNeighbors returns the maximum number of neighbors of the nodes at the
+// level, twice as many at the bottom level.
+func (h *hnsw) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * h.m
+	}
+	return h.m
+}
+
+// nearestNodes returns the n nodes nearest to the vector.
+func nearestNodes(vector []float64, nodes []int, n int, vectors [][]float64) []int {
+	c := &candidates{items: make([]candidate, 0, len(nodes))}
+	for _, node := range nodes {
+		c.items = append(c.items, candidate{node: node, distance: distance(vector, vectors[node])})
+	}
+	heap.Init(c)
+	result := make([]int, 0, n)
+	for c.Len() > 0 && len(result) < n {
+		result = append(result, heap.Pop(c).(candidate).node) //nolint:forcetypeassert
+	}
+	return result
+}
+
+// distance returns the cosine distance between normalized vectors.
+func distance(a, b []float64) float64 {
+	return 1 - dot(a, b)
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// normalize returns the vector divided by its norm.
+func normalize(v []float64) []float64 {
+	normalized := make([]float64, len(v))
+	norm := math.Sqrt(dot(v, v))
+	if norm == 0 {
+		return normalized
+	}
+	for i, f := range v {
+		normalized[i] = f / norm
+	}
+	return normalized
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
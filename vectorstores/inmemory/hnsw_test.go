@@ -0,0 +1,66 @@
+package inmemory
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHNSWRecall(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(42)) //nolint:gosec
+	randomVector := func() []float64 {
+		v := make([]float64, 16)
+		for i := range v {
+			v[i] = r.NormFloat64()
+		}
+		return normalize(v)
+	}
+
+	index := newHNSW(8, 64, 32)
+	vectors := make([][]float64, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vectors = append(vectors, randomVector())
+		index.insert(i, vectors)
+	}
+
+	const k = 10
+	var found, total int
+	for q := 0; q < 50; q++ {
+		query := randomVector()
+
+		exact := make([]candidate, 0, len(vectors))
+		for i, v := range vectors {
+			exact = append(exact, candidate{node: i, distance: distance(query, v)})
+		}
+		sort.Slice(exact, func(i, j int) bool { return exact[i].distance < exact[j].distance })
+
+		approximate := index.search(query, 32, vectors)
+		require.Len(t, approximate, 32)
+		for i := 1; i < len(approximate); i++ {
+			assert.LessOrEqual(t, approximate[i-1].distance, approximate[i].distance)
+		}
+
+		nodes := make(map[int]bool)
+		for _, c := range approximate[:k] {
+			nodes[c.node] = true
+		}
+		for _, c := range exact[:k] {
+			if nodes[c.node] {
+				found++
+			}
+			total++
+		}
+	}
+	assert.Greater(t, float64(found)/float64(total), 0.9)
+}
+
+func TestHNSWEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, newHNSW(16, 200, 50).search([]float64{1, 0}, 10, nil))
+}
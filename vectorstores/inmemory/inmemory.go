@@ -0,0 +1,456 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrWrongVectorDimensions is returned if the vectors of the embedder
+	// have a number of dimensions different from the vectors of the store.
+	ErrWrongVectorDimensions = errors.New("vectors of different dimensions")
+)
+
+// document is a document of the store with its vector.
+type document struct {
+	ID        string         `json:"id,omitempty"`
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata"`
+	NameSpace string         `json:"nameSpace,omitempty"`
+	Vector    []float64      `json:"vector"`
+}
+
+// Store is a vector store holding the documents in memory, searched by
+// cosine similarity. It is safe for concurrent use.
+type Store struct {
+	embedder embeddings.Embedder
+
+	mu        sync.RWMutex
+	documents []document
+	// normalized are the normalized vectors of the documents.
+	normalized [][]float64
+	// positions are the positions of the documents by ID.
+	positions map[string]int
+
+	// optional, the HNSW graph of the approximate searches
+	index *hnsw
+	// optional, the file of the snapshots
+	path string
+}
+
+var (
+	_ vectorstores.VectorStore    = &Store{}
+	_ vectorstores.VectorSearcher = &Store{}
+	_ vectorstores.Exporter       = &Store{}
+)
+
+// New creates a new Store with options. With WithFile, the documents of the
+// snapshot of the file are loaded if it exists.
+func New(opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.path != "" {
+		if err := s.loadFile(s.path); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Len returns the number of documents of the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.documents)
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and adds them to the store, in the name space of the options. The
+// documents with the ID of a document of the store replace it, and the HNSW
+// index is then rebuilt.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	docs = vectorstores.ApplyTTL(opts, docs, time.Now())
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, vector := range vectors {
+		if err := s.checkDimensions(vector); err != nil {
+			return nil, err
+		}
+	}
+
+	replaced := false
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata))
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		d := document{
+			ID:        ids[i],
+			Content:   texts[i],
+			Metadata:  metadata,
+			NameSpace: opts.NameSpace,
+			Vector:    vectors[i],
+		}
+		if position, ok := s.positions[d.ID]; ok {
+			s.documents[position] = d
+			replaced = true
+			continue
+		}
+		s.add(d)
+	}
+	if replaced {
+		s.rebuild(s.documents)
+	}
+	return ids, nil
+}
+
+// Delete deletes the documents of the name space of the options with the
+// IDs.
+func (s *Store) Delete(_ context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(doc document) bool {
+		return doc.NameSpace == opts.NameSpace && deleted[doc.ID]
+	})
+	return nil
+}
+
+// DeleteByFilter deletes the documents of the name space of the options
+// matching the filter, with the filters of SimilaritySearch.
+func (s *Store) DeleteByFilter(_ context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	match, err := s.getFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWhere(func(doc document) bool {
+		return doc.NameSpace == opts.NameSpace && match(doc.Metadata)
+	})
+	return nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and returns the most similar documents of the name space of the options,
+// by cosine similarity, their score. The filters are either a map[string]any of the values
+// of the metadata of the documents, compared as JSON, a filters.Filter, or a
+// func(metadata map[string]any) bool. The documents expired, see
+// vectorstores.WithTTL, are not returned.
+//
+// With an HNSW index, the filtered searches fall back to the exact search of
+// all the documents if the nearest candidates of the graph give too few
+// documents.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SimilaritySearchByVector(ctx, vector, numDocuments, options...)
+}
+
+// SimilaritySearchByVector returns the most similar documents to the vector,
+// as SimilaritySearch. The documents of the offset of the options are
+// skipped.
+func (s *Store) SimilaritySearchByVector(_ context.Context, vector []float64, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.getFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.checkDimensions(vector); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	match := func(i int, score float64) bool {
+		doc := s.documents[i]
+		// If scoreThreshold is 0, we return all matches.
+		return doc.NameSpace == opts.NameSpace &&
+			(scoreThreshold == 0 || score >= scoreThreshold) &&
+			filter(doc.Metadata) &&
+			!vectorstores.Expired(schema.Document{Metadata: doc.Metadata}, now)
+	}
+
+	normalized := normalize(vector)
+	var results []candidate
+	if s.index != nil {
+		ef := s.index.efSearch
+		if ef < offset+fetchK {
+			ef = offset + fetchK
+		}
+		nearest := s.index.search(normalized, ef, s.normalized)
+		for _, c := range nearest {
+			if match(c.node, 1-c.distance) {
+				results = append(results, c)
+			}
+		}
+		if len(results) >= offset+fetchK || len(nearest) == len(s.documents) {
+			results = skip(results, offset)
+			return s.newDocuments(s.selectMMR(normalized, results, fetchK, numDocuments, opts), numDocuments), nil
+		}
+		results = nil
+	}
+
+	for i := range s.documents {
+		c := candidate{node: i, distance: distance(normalized, s.normalized[i])}
+		if match(i, 1-c.distance) {
+			results = append(results, c)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].distance < results[j].distance })
+	results = skip(results, offset)
+	return s.newDocuments(s.selectMMR(normalized, results, fetchK, numDocuments, opts), numDocuments), nil
+}
+
+// selectMMR returns the n candidates selected by maximal marginal relevance
+// to the query among the first fetchK candidates, with the vectors of the
+// store. The candidates are returned as is without MMR options. The lock
+// must be held.
+func (s *Store) selectMMR(query []float64, results []candidate, fetchK, n int, opts vectorstores.Options) []candidate {
+	if opts.MMR == nil {
+		return results
+	}
+	if len(results) > fetchK {
+		results = results[:fetchK]
+	}
+	vectors := make([][]float64, 0, len(results))
+	for _, c := range results {
+		vectors = append(vectors, s.normalized[c.node])
+	}
+	selected := make([]candidate, 0, n)
+	for _, i := range vectorstores.MaximalMarginalRelevance(query, vectors, n, opts.MMR.Lambda) {
+		selected = append(selected, results[i])
+	}
+	return selected
+}
+
+// skip returns the candidates following the first offset candidates.
+func skip(results []candidate, offset int) []candidate {
+	if offset > len(results) {
+		offset = len(results)
+	}
+	return results[offset:]
+}
+
+// newDocuments returns the documents of the first n candidates.
+func (s *Store) newDocuments(results []candidate, n int) []schema.Document {
+	if len(results) > n {
+		results = results[:n]
+	}
+	docs := make([]schema.Document, 0, len(results))
+	for _, c := range results {
+		doc := s.documents[c.node]
+		metadata := make(map[string]any, len(doc.Metadata))
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		docs = append(docs, schema.Document{PageContent: doc.Content, Metadata: metadata, Score: 1 - c.distance})
+	}
+	return docs
+}
+
+// deleteWhere deletes the documents for which deleted returns true and
+// rebuilds the index if any. The lock must be held.
+func (s *Store) deleteWhere(deleted func(document) bool) {
+	kept := make([]document, 0, len(s.documents))
+	for _, doc := range s.documents {
+		if !deleted(doc) {
+			kept = append(kept, doc)
+		}
+	}
+	if len(kept) < len(s.documents) {
+		s.rebuild(kept)
+	}
+}
+
+// rebuild replaces the documents of the store by the documents and rebuilds
+// their index. The lock must be held.
+func (s *Store) rebuild(docs []document) {
+	s.documents = nil
+	s.normalized = nil
+	s.positions = nil
+	if s.index != nil {
+		s.index = s.index.reset()
+	}
+	for _, doc := range docs {
+		s.add(doc)
+	}
+}
+
+// add adds the document to the store and to its index. The lock must be
+// held.
+func (s *Store) add(doc document) {
+	if s.positions == nil {
+		s.positions = map[string]int{}
+	}
+	s.positions[doc.ID] = len(s.documents)
+	s.documents = append(s.documents, doc)
+	s.normalized = append(s.normalized, normalize(doc.Vector))
+	if s.index != nil {
+		s.index.insert(len(s.documents)-1, s.normalized)
+	}
+}
+
+// checkDimensions returns an error if the vector has a number of dimensions
+// different from the vectors of the store. The lock must be held.
+func (s *Store) checkDimensions(vector []float64) error {
+	if len(s.documents) > 0 && len(vector) != len(s.documents[0].Vector) {
+		return ErrWrongVectorDimensions
+	}
+	return nil
+}
+
+func (s *Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+func (s *Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+// getFilter returns the filter of the metadata of the documents.
+func (s *Store) getFilter(opts vectorstores.Options) (func(map[string]any) bool, error) {
+	switch filter := opts.Filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case func(map[string]any) bool:
+		return filter, nil
+	case filters.Filter:
+		if err := filter.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+		return func(metadata map[string]any) bool {
+			ok, _ := filters.Match(filter, metadata)
+			return ok
+		}, nil
+	case map[string]any:
+		return func(metadata map[string]any) bool {
+			for key, value := range filter {
+				if !jsonEqual(metadata[key], value) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, ErrInvalidFilter
+	}
+}
+
+// jsonEqual reports whether the values are equal as JSON, as the values of
+// the metadata loaded from a snapshot are JSON values.
+func jsonEqual(a, b any) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// getOptions returns the options of the call, with the name space scoped by
+// the tenant.
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	opts.NameSpace = vectorstores.TenantNameSpace(opts.Tenant, opts.NameSpace)
+	return opts
+}
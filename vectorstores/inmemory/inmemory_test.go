@@ -0,0 +1,299 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+var testDocs = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "The cat sleeps.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The dog barks at the cat.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The car is red.", Metadata: map[string]any{"animal": false}},
+}
+
+func newTestStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+
+	store, err := New(append([]Option{WithEmbedder(testutil.WordCountEmbedder{})}, opts...)...)
+	require.NoError(t, err)
+	_, err = store.AddDocuments(context.Background(), testDocs)
+	require.NoError(t, err)
+	return store
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(WithEmbedder(testutil.WordCountEmbedder{}), WithHNSWIndex(1, 0, 0))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]Option{
+		"exact": nil,
+		"hnsw":  {WithHNSWIndex(0, 0, 0)},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newTestStore(t, opts...)
+			assert.Equal(t, 3, store.Len())
+
+			docs, err := store.SimilaritySearch(context.Background(), "cat", 2)
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.Equal(t, map[string]any{"animal": true, "legs": 4}, docs[0].Metadata)
+			assert.Equal(t, "The dog barks at the cat.", docs[1].PageContent)
+			assert.Greater(t, docs[0].Score, docs[1].Score)
+			assert.InDelta(t, 0.74, docs[1].Score, 0.01)
+
+			// The cosine similarity of the second document is 0.74.
+			docs, err = store.SimilaritySearch(context.Background(), "cat", 2,
+				vectorstores.WithScoreThreshold(0.8))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+		})
+	}
+}
+
+func TestSimilaritySearchByVector(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]Option{
+		"exact": nil,
+		"hnsw":  {WithHNSWIndex(0, 0, 0)},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newTestStore(t, opts...)
+			vector, err := testutil.WordCountEmbedder{}.EmbedQuery(context.Background(), "cat")
+			require.NoError(t, err)
+
+			docs, err := store.SimilaritySearchByVector(context.Background(), vector, 1)
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+			docs, err = store.SimilaritySearchByVector(context.Background(), vector, 1, vectorstores.WithOffset(1))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The dog barks at the cat.", docs[0].PageContent)
+
+			docs, err = store.SimilaritySearchByVector(context.Background(), vector, 2, vectorstores.WithOffset(3))
+			require.NoError(t, err)
+			assert.Empty(t, docs)
+
+			_, err = store.SimilaritySearchByVector(context.Background(), []float64{1}, 1)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSimilaritySearchMMR(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]Option{
+		"exact": nil,
+		"hnsw":  {WithHNSWIndex(0, 0, 0)},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newTestStore(t, opts...)
+
+			// The document about the car is selected over the near duplicate
+			// about the dog.
+			docs, err := store.SimilaritySearch(context.Background(), "cat", 2, vectorstores.WithMMR(0.25, 3))
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.Equal(t, "The car is red.", docs[1].PageContent)
+
+			_, err = store.SimilaritySearch(context.Background(), "cat", 2, vectorstores.WithMMR(-1, 3))
+			require.ErrorIs(t, err, vectorstores.ErrInvalidMMR)
+		})
+	}
+}
+
+func TestSimilaritySearchFilters(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 3,
+		vectorstores.WithFilters(map[string]any{"animal": false}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	// The values are compared as JSON.
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 3,
+		vectorstores.WithFilters(map[string]any{"legs": 4.0}))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 3,
+		vectorstores.WithFilters(func(metadata map[string]any) bool {
+			return metadata["legs"] == nil
+		}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 3,
+		vectorstores.WithFilters(filters.Eq("animal", true).And(filters.Lt("legs", 4))))
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 3,
+		vectorstores.WithFilters(filters.Gte("legs", 4).Or(filters.Eq("animal", false))))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	_, err = store.SimilaritySearch(context.Background(), "cat", 3, vectorstores.WithFilters("animal"))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = store.SimilaritySearch(context.Background(), "cat", 3, vectorstores.WithFilters(filters.And()))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = store.SimilaritySearch(context.Background(), "cat", 3, vectorstores.WithScoreThreshold(2))
+	require.ErrorIs(t, err, ErrInvalidScoreThreshold)
+}
+
+func TestSimilaritySearchNameSpace(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	_, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Cats and dogs."},
+	}, vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 5, vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Cats and dogs.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+}
+
+func TestSimilaritySearchTenant(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	_, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The cat of acme."},
+	}, vectorstores.WithTenant("acme"))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 5, vectorstores.WithTenant("acme"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat of acme.", docs[0].PageContent)
+
+	// The documents of a tenant are not found by the other tenants or
+	// without a tenant.
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 5, vectorstores.WithTenant("globex"))
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+	docs, err = store.SimilaritySearch(context.Background(), "cat", 5)
+	require.NoError(t, err)
+	assert.Len(t, docs, 3)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	for _, opts := range [][]Option{nil, {WithHNSWIndex(4, 16, 16)}} {
+		store, err := New(append([]Option{WithEmbedder(testutil.WordCountEmbedder{})}, opts...)...)
+		require.NoError(t, err)
+		ids, err := store.AddDocuments(context.Background(), testDocs)
+		require.NoError(t, err)
+		require.Len(t, ids, 3)
+
+		// The documents are updated by adding them with their IDs.
+		_, err = store.AddDocuments(context.Background(), []schema.Document{
+			{PageContent: "The car is blue.", Metadata: map[string]any{"animal": false}},
+		}, vectorstores.WithIDs(ids[2]))
+		require.NoError(t, err)
+		assert.Equal(t, 3, store.Len())
+		docs, err := store.SimilaritySearch(context.Background(), "car", 1)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "The car is blue.", docs[0].PageContent)
+
+		// The documents of other name spaces are not deleted.
+		require.NoError(t, store.Delete(context.Background(), ids[:1], vectorstores.WithNameSpace("pets")))
+		assert.Equal(t, 3, store.Len())
+		require.NoError(t, store.Delete(context.Background(), ids[:1]))
+		assert.Equal(t, 2, store.Len())
+		require.NoError(t, store.DeleteByFilter(context.Background(), filters.Eq("animal", false)))
+		assert.Equal(t, 1, store.Len())
+
+		docs, err = store.SimilaritySearch(context.Background(), "cat", 3)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "The dog barks at the cat.", docs[0].PageContent)
+
+		require.ErrorIs(t, store.DeleteByFilter(context.Background(), nil), vectorstores.ErrMissingFilter)
+		_, err = store.AddDocuments(context.Background(), testDocs, vectorstores.WithIDs("1"))
+		require.ErrorIs(t, err, vectorstores.ErrWrongNumberIDs)
+	}
+}
+
+func TestDeterministicIDs(t *testing.T) {
+	t.Parallel()
+
+	store, err := New(WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+
+	// Adding the documents again does not duplicate them.
+	for i := 0; i < 2; i++ {
+		_, err = store.AddDocuments(context.Background(), testDocs,
+			vectorstores.WithDeterministicIDs(vectorstores.ContentHash))
+		require.NoError(t, err)
+		assert.Equal(t, 3, store.Len())
+	}
+}
+
+func TestAddDocumentsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	store, err := New(WithEmbedder(testutil.WordCountEmbedder{}), WithHNSWIndex(4, 16, 16))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.AddDocuments(context.Background(), testDocs)
+			assert.NoError(t, err)
+			_, err = store.SimilaritySearch(context.Background(), "dog", 3)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 30, store.Len())
+}
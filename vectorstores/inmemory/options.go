@@ -0,0 +1,74 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_defaultM              = 16
+	_defaultEfConstruction = 200
+	_defaultEfSearch       = 50
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithHNSWIndex is an option for searching the documents with an HNSW
+// graph, approximate but faster than the exact search of all the documents
+// for large stores. m is the number of neighbors of the nodes of the graph,
+// efConstruction and efSearch the number of candidates when adding and
+// searching the documents. Zero values use the defaults of 16, 200 and 50.
+func WithHNSWIndex(m, efConstruction, efSearch int) Option {
+	return func(p *Store) {
+		if m == 0 {
+			m = _defaultM
+		}
+		if efConstruction == 0 {
+			efConstruction = _defaultEfConstruction
+		}
+		if efSearch == 0 {
+			efSearch = _defaultEfSearch
+		}
+		p.index = newHNSW(m, efConstruction, efSearch)
+	}
+}
+
+// WithFile is an option for loading the documents of the snapshot of the
+// file, if it exists, when creating the store. Store.Persist saves the
+// documents to the file.
+func WithFile(path string) Option {
+	return func(p *Store) {
+		p.path = path
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	o := &Store{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if o.index != nil && (o.index.m < 2 || o.index.efConstruction < 1 || o.index.efSearch < 1) {
+		return nil, fmt.Errorf("%w: invalid HNSW parameters", ErrInvalidOptions)
+	}
+
+	return o, nil
+}
@@ -0,0 +1,141 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const _snapshotVersion = 1
+
+var (
+	// ErrMissingFile is returned by Persist if the store has no file.
+	ErrMissingFile = errors.New("missing file, see WithFile")
+	// ErrInvalidSnapshot is returned if a snapshot can not be loaded.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+// snapshot is the JSON snapshot of the documents of a store. The HNSW
+// graph is rebuilt when loading the snapshot.
+type snapshot struct {
+	Version   int        `json:"version"`
+	Documents []document `json:"documents"`
+}
+
+// Save writes a snapshot of the documents of the store, with their vectors,
+// to the writer.
+func (s *Store) Save(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return json.NewEncoder(w).Encode(snapshot{Version: _snapshotVersion, Documents: s.documents})
+}
+
+// Load replaces the documents of the store by the documents of the snapshot
+// read from the reader.
+func (s *Store) Load(r io.Reader) error {
+	var snap snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if snap.Version != _snapshotVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidSnapshot, snap.Version)
+	}
+	for _, doc := range snap.Documents {
+		if len(doc.Vector) != len(snap.Documents[0].Vector) {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, ErrWrongVectorDimensions)
+		}
+	}
+
+	for i, doc := range snap.Documents {
+		if doc.Metadata == nil {
+			snap.Documents[i].Metadata = map[string]any{}
+		}
+		// The documents of the snapshots saved without IDs are given new
+		// ones.
+		if doc.ID == "" {
+			snap.Documents[i].ID = uuid.New().String()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rebuild(snap.Documents)
+	return nil
+}
+
+// Export writes the records of the documents of the name space of the
+// options, with their vectors, to the writer, one JSON object per line, see
+// vectorstores.Import. Unlike Save, only the documents of the name space are
+// written, and the expired documents are not.
+func (s *Store) Export(ctx context.Context, w io.Writer, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	enc := json.NewEncoder(w)
+	for _, doc := range s.documents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if doc.NameSpace != opts.NameSpace ||
+			vectorstores.Expired(schema.Document{Metadata: doc.Metadata}, now) {
+			continue
+		}
+		record := vectorstores.Record{ID: doc.ID, Vector: doc.Vector, Content: doc.Content, Metadata: doc.Metadata}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Persist saves a snapshot of the documents of the store to the file of
+// WithFile. The file is replaced atomically, with a temporary file in the
+// same directory.
+func (s *Store) Persist() error {
+	if s.path == "" {
+		return ErrMissingFile
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// loadFile loads the snapshot of the file, if it exists.
+func (s *Store) loadFile(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Load(f)
+}
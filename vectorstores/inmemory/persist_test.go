@@ -0,0 +1,121 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestPersist(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	store := newTestStore(t, WithFile(path))
+	require.NoError(t, store.Persist())
+
+	loaded, err := New(WithEmbedder(testutil.WordCountEmbedder{}), WithFile(path), WithHNSWIndex(0, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 3, loaded.Len())
+
+	docs, err := loaded.SimilaritySearch(context.Background(), "cat", 1,
+		vectorstores.WithFilters(map[string]any{"legs": 4}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	// The numbers of the metadata are loaded as JSON numbers.
+	assert.Equal(t, map[string]any{"animal": true, "legs": 4.0}, docs[0].Metadata)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestNewMissingFile(t *testing.T) {
+	t.Parallel()
+
+	store, err := New(WithEmbedder(testutil.WordCountEmbedder{}), WithFile(filepath.Join(t.TempDir(), "store.json")))
+	require.NoError(t, err)
+	assert.Equal(t, 0, store.Len())
+
+	store, err = New(WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	require.ErrorIs(t, store.Persist(), ErrMissingFile)
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, newTestStore(t).Save(&buf))
+
+	// Loading replaces the documents of the store.
+	store := newTestStore(t)
+	require.NoError(t, store.Load(&buf))
+	assert.Equal(t, 3, store.Len())
+
+	err := store.Load(strings.NewReader(`{"version":2,"documents":[]}`))
+	require.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	err = store.Load(strings.NewReader(`{"version":1,"documents":[{"vector":[1]},{"vector":[1,2]}]}`))
+	require.ErrorIs(t, err, ErrInvalidSnapshot)
+	assert.Equal(t, 3, store.Len())
+}
+
+// failingEmbedder fails to embed, for the documents imported with their
+// vectors.
+type failingEmbedder struct{}
+
+func (failingEmbedder) EmbedDocuments(context.Context, []string) ([][]float64, error) {
+	return nil, errors.New("unexpected embedding")
+}
+
+func (failingEmbedder) EmbedQuery(context.Context, string) ([]float64, error) {
+	return nil, errors.New("unexpected embedding")
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStore(t)
+	_, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "The other cat."}},
+		vectorstores.WithNameSpace("other"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, vectorstores.Export(ctx, store, &buf))
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+	imported, err := New(WithEmbedder(failingEmbedder{}))
+	require.NoError(t, err)
+	n, err := vectorstores.Import(ctx, imported, &buf, vectorstores.WithNameSpace("copy"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 3, imported.Len())
+
+	vector, err := testutil.WordCountEmbedder{}.EmbedQuery(ctx, "cat")
+	require.NoError(t, err)
+	docs, err := imported.SimilaritySearchByVector(ctx, vector, 1, vectorstores.WithNameSpace("copy"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"animal": true, "legs": 4.0}, docs[0].Metadata)
+
+	// The documents keep their IDs.
+	var exported bytes.Buffer
+	require.NoError(t, imported.Export(ctx, &exported, vectorstores.WithNameSpace("copy")))
+	var original bytes.Buffer
+	require.NoError(t, store.Export(ctx, &original))
+	assert.JSONEq(t, "["+strings.ReplaceAll(strings.TrimSpace(original.String()), "\n", ",")+"]",
+		"["+strings.ReplaceAll(strings.TrimSpace(exported.String()), "\n", ",")+"]")
+}
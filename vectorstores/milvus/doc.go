@@ -0,0 +1,3 @@
+// Package milvus contains an implementation of the vectorStore
+// interface using the RESTful API of Milvus and Zilliz Cloud.
+package milvus
@@ -0,0 +1,393 @@
+package milvus
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrMissingTextKey is returned in SimilaritySearch if a vector
+	// from the query is missing the text field.
+	ErrMissingTextKey = errors.New("missing text field in search result")
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are not a boolean
+	// expression string of Milvus.
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Store is a wrapper around a collection of the Milvus rest api. The
+// documents are stored with their text, their metadata as a JSON field and
+// their vector.
+type Store struct {
+	embedder   embeddings.Embedder
+	httpClient Doer
+
+	url                    string
+	token                  string
+	collectionName         string
+	partition              string
+	vectorDimensions       int
+	metricType             MetricType
+	indexType              string
+	indexParams            map[string]any
+	searchParams           map[string]any
+	skipCollectionCreation bool
+	textField              string
+	metadataField          string
+	vectorField            string
+	batchSize              int
+}
+
+var (
+	_ vectorstores.VectorStore  = Store{}
+	_ vectorstores.IndexManager = Store{}
+)
+
+// New creates a new Store with options. The collection name, the embedder
+// and, unless the collection creation is skipped, the dimensions of its
+// vectors must be set. The collection is created with its index if it does
+// not exist.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if !s.skipCollectionCreation {
+		if err := s.CreateIndex(ctx); err != nil {
+			return Store{}, err
+		}
+	}
+
+	return s, nil
+}
+
+// CreateIndex creates the collection with the index of its vectors if it
+// does not exist.
+func (s Store) CreateIndex(ctx context.Context) error {
+	return s.ensureCollection(ctx)
+}
+
+// DeleteIndex drops the collection with its documents.
+func (s Store) DeleteIndex(ctx context.Context) error {
+	return s.doRequest(ctx, "dropping collection", "/v2/vectordb/collections/drop",
+		hasPayload{CollectionName: s.collectionName}, nil)
+}
+
+// DescribeIndex returns the name of the collection with the dimensions and
+// the metric type of the index of its vectors.
+func (s Store) DescribeIndex(ctx context.Context) (vectorstores.IndexInfo, error) {
+	if err := s.checkCollection(ctx); err != nil {
+		return vectorstores.IndexInfo{}, err
+	}
+	var description collectionDescription
+	if err := s.doRequest(ctx, "describing collection", "/v2/vectordb/collections/describe",
+		hasPayload{CollectionName: s.collectionName}, &description); err != nil {
+		return vectorstores.IndexInfo{}, err
+	}
+
+	info := vectorstores.IndexInfo{Name: s.collectionName}
+	for _, f := range description.Fields {
+		if f.Name != s.vectorField {
+			continue
+		}
+		for _, param := range f.Params {
+			if param.Key == "dim" {
+				info.Dimensions, _ = strconv.Atoi(fmt.Sprint(param.Value))
+			}
+		}
+	}
+	for _, index := range description.Indexes {
+		if index.FieldName == s.vectorField {
+			info.Metric = string(index.MetricType)
+		}
+	}
+	return info, nil
+}
+
+// Stats returns the number of entities of the collection.
+func (s Store) Stats(ctx context.Context) (vectorstores.IndexStats, error) {
+	if err := s.checkCollection(ctx); err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	var stats struct {
+		RowCount int `json:"rowCount"`
+	}
+	if err := s.doRequest(ctx, "getting collection statistics", "/v2/vectordb/collections/get_stats",
+		hasPayload{CollectionName: s.collectionName}, &stats); err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	return vectorstores.IndexStats{NumDocuments: stats.RowCount}, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and upserts them into the partition of the collection in batches.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	partition := s.getPartition(opts)
+	if partition != "" {
+		if err := s.ensurePartition(ctx, partition); err != nil {
+			return nil, err
+		}
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	for start := 0; start < len(docs); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		data := make([]map[string]any, 0, end-start)
+		for i := start; i < end; i++ {
+			metadata := docs[i].Metadata
+			if metadata == nil {
+				metadata = map[string]any{}
+			}
+			data = append(data, map[string]any{
+				_idField:        ids[i],
+				s.textField:     texts[i],
+				s.metadataField: metadata,
+				s.vectorField:   vectors[i],
+			})
+		}
+		payload := insertPayload{CollectionName: s.collectionName, PartitionName: partition, Data: data}
+		if err := s.doRequest(ctx, "upserting vectors", "/v2/vectordb/entities/upsert", payload, nil); err != nil {
+			return nil, fmt.Errorf("upsert documents %d to %d: %w", start, end, err)
+		}
+	}
+
+	return ids, nil
+}
+
+// Delete deletes the documents of the partition with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	quoted, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, fmt.Sprintf("%s in %s", _idField, quoted), s.getOptions(options...))
+}
+
+// DeleteByFilter deletes the documents of the partition matching the filter,
+// a boolean expression of Milvus as in SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	expression, err := s.getFilter(opts)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, expression, opts)
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a boolean
+// expression of Milvus, e.g. `metadata["country"] == "France"`. The scores of
+// the documents are the cosine similarity, the inner product, or
+// 1 / (1 + the squared Euclidean distance).
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.getFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := searchPayload{
+		CollectionName: s.collectionName,
+		Data:           [][]float64{vector},
+		AnnsField:      s.vectorField,
+		Filter:         filter,
+		Limit:          fetchK,
+		OutputFields:   []string{s.textField, s.metadataField},
+		SearchParams:   map[string]any{"metricType": s.metricType},
+	}
+	if partition := s.getPartition(opts); partition != "" {
+		payload.PartitionNames = []string{partition}
+	}
+	if s.searchParams != nil {
+		payload.SearchParams["params"] = s.searchParams
+	}
+
+	hits, err := s.search(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(hits))
+	for _, h := range hits {
+		var distance float64
+		if err := json.Unmarshal(h["distance"], &distance); err != nil {
+			return nil, fmt.Errorf("decoding distance: %w", err)
+		}
+		score := s.score(distance)
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && score < scoreThreshold {
+			continue
+		}
+
+		doc, err := s.newDocument(h)
+		if err != nil {
+			return nil, err
+		}
+		doc.Score = score
+		docs = append(docs, doc)
+	}
+
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// newDocument returns the document of a search result.
+func (s Store) newDocument(h hit) (schema.Document, error) {
+	var doc schema.Document
+	if err := json.Unmarshal(h[s.textField], &doc.PageContent); err != nil {
+		return schema.Document{}, ErrMissingTextKey
+	}
+
+	metadata := h[s.metadataField]
+	// Some versions of Milvus return the JSON fields as strings.
+	var encoded string
+	if err := json.Unmarshal(metadata, &encoded); err == nil {
+		metadata = json.RawMessage(encoded)
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &doc.Metadata); err != nil {
+			return schema.Document{}, fmt.Errorf("decoding metadata: %w", err)
+		}
+	}
+	return doc, nil
+}
+
+// score returns the similarity of a distance of the search results, the
+// cosine similarity or the inner product, or 1 / (1 + the squared Euclidean
+// distance).
+func (s Store) score(distance float64) float64 {
+	if s.metricType == MetricL2 {
+		return 1 / (1 + distance)
+	}
+	return distance
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getPartition returns the partition of the call, scoped by its tenant. As
+// the partition names are made of letters, digits and underscores, the
+// partitions of a tenant are prefixed with its hex-encoded name rather than
+// with vectorstores.TenantNameSpace.
+func (s Store) getPartition(opts vectorstores.Options) string {
+	partition := s.partition
+	if opts.NameSpace != "" {
+		partition = opts.NameSpace
+	}
+	if opts.Tenant == "" {
+		return partition
+	}
+	return "tenant_" + hex.EncodeToString([]byte(opts.Tenant)) + "_" + partition
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+func (s Store) delete(ctx context.Context, filter string, opts vectorstores.Options) error {
+	payload := deletePayload{CollectionName: s.collectionName, PartitionName: s.getPartition(opts), Filter: filter}
+	return s.doRequest(ctx, "deleting vectors", "/v2/vectordb/entities/delete", payload, nil)
+}
+
+func (s Store) getFilter(opts vectorstores.Options) (string, error) {
+	if opts.Filters == nil {
+		return "", nil
+	}
+	filter, ok := opts.Filters.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: string expression required, got %T", ErrInvalidFilter, opts.Filters)
+	}
+	return filter, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
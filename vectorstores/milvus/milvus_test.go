@@ -0,0 +1,373 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeMilvus is a Milvus server holding a collection in memory, searched by
+// cosine similarity. The filters are ignored.
+type fakeMilvus struct {
+	mu         sync.Mutex
+	collection *createCollectionPayload
+	partitions map[string][]map[string]any
+	paths      []string
+	searches   []searchPayload
+	deletes    []deletePayload
+	inserts    int
+}
+
+func (f *fakeMilvus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.Path)
+
+	var data any
+	switch r.URL.Path {
+	case "/v2/vectordb/collections/has":
+		data = map[string]bool{"has": f.collection != nil}
+	case "/v2/vectordb/collections/create":
+		f.collection = &createCollectionPayload{}
+		_ = json.NewDecoder(r.Body).Decode(f.collection)
+		f.partitions = map[string][]map[string]any{"_default": nil}
+	case "/v2/vectordb/collections/drop":
+		f.collection = nil
+		f.partitions = nil
+	case "/v2/vectordb/collections/describe":
+		data = f.describe()
+	case "/v2/vectordb/collections/get_stats":
+		rowCount := 0
+		for _, entities := range f.partitions {
+			rowCount += len(entities)
+		}
+		data = map[string]int{"rowCount": rowCount}
+	case "/v2/vectordb/partitions/has":
+		var payload hasPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_, ok := f.partitions[payload.PartitionName]
+		data = map[string]bool{"has": ok}
+	case "/v2/vectordb/partitions/create":
+		var payload hasPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		f.partitions[payload.PartitionName] = nil
+	case "/v2/vectordb/entities/upsert":
+		var payload insertPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		partition := payload.PartitionName
+		if partition == "" {
+			partition = "_default"
+		}
+		if _, ok := f.partitions[partition]; !ok {
+			_ = json.NewEncoder(w).Encode(response{Code: 200, Message: "partition not found"})
+			return
+		}
+		f.partitions[partition] = upsert(f.partitions[partition], payload.Data)
+		f.inserts++
+	case "/v2/vectordb/entities/delete":
+		var payload deletePayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		f.deletes = append(f.deletes, payload)
+	case "/v2/vectordb/entities/search":
+		var payload searchPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		f.searches = append(f.searches, payload)
+		data = f.search(payload)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	encoded, _ := json.Marshal(data)
+	_ = json.NewEncoder(w).Encode(response{Data: encoded})
+}
+
+// describe returns the description of the collection, with the parameters of
+// the fields as strings as Milvus does.
+func (f *fakeMilvus) describe() map[string]any {
+	fields := make([]map[string]any, 0, len(f.collection.Schema.Fields))
+	for _, schemaField := range f.collection.Schema.Fields {
+		params := []map[string]any{}
+		for key, value := range schemaField.ElementTypeParams {
+			params = append(params, map[string]any{"key": key, "value": fmt.Sprint(value)})
+		}
+		fields = append(fields, map[string]any{
+			"name":   schemaField.FieldName,
+			"type":   schemaField.DataType,
+			"params": params,
+		})
+	}
+	return map[string]any{
+		"collectionName": f.collection.CollectionName,
+		"fields":         fields,
+		"indexes":        f.collection.IndexParams,
+	}
+}
+
+// upsert returns the entities with the data, replacing the entities with the
+// same IDs.
+func upsert(entities, data []map[string]any) []map[string]any {
+	for _, d := range data {
+		replaced := false
+		for i, entity := range entities {
+			if entity["id"] == d["id"] {
+				entities[i] = d
+				replaced = true
+			}
+		}
+		if !replaced {
+			entities = append(entities, d)
+		}
+	}
+	return entities
+}
+
+func (f *fakeMilvus) search(payload searchPayload) []map[string]any {
+	partitions := payload.PartitionNames
+	if len(partitions) == 0 {
+		partitions = []string{"_default"}
+	}
+	var hits []map[string]any
+	for _, partition := range partitions {
+		for _, entity := range f.partitions[partition] {
+			vector, _ := entity["vector"].([]any)
+			// The metadata is returned encoded, as some versions do.
+			metadata, _ := json.Marshal(entity["metadata"])
+			hits = append(hits, map[string]any{
+				"distance": cosine(payload.Data[0], vector),
+				"text":     entity["text"],
+				"metadata": string(metadata),
+			})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i]["distance"].(float64) > hits[j]["distance"].(float64) //nolint:forcetypeassert
+	})
+	if len(hits) > payload.Limit {
+		hits = hits[:payload.Limit]
+	}
+	return hits
+}
+
+func cosine(a []float64, b []any) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		v, _ := b[i].(float64)
+		dot += a[i] * v
+		na += a[i] * a[i]
+		nb += v * v
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func TestMilvusStore(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeMilvus{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithURL(server.URL),
+		WithToken("root:Milvus"),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithCollectionName("docs"),
+		WithVectorDimensions(3),
+		WithIndex("HNSW", map[string]any{"M": 16}),
+		WithSearchParams(map[string]any{"ef": 64}),
+		WithBatchSize(2),
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, fake.collection)
+	assert.Equal(t, "docs", fake.collection.CollectionName)
+	assert.Len(t, fake.collection.Schema.Fields, 4)
+	assert.Equal(t, []indexParam{{
+		FieldName:  "vector",
+		IndexName:  "vector",
+		MetricType: MetricCosine,
+		Params:     map[string]any{"index_type": "HNSW", "M": 16.0},
+	}}, fake.collection.IndexParams)
+
+	// The collection is not created again.
+	_, err = New(ctx, WithURL(server.URL), WithEmbedder(testutil.WordCountEmbedder{}), WithCollectionName("docs"),
+		WithVectorDimensions(3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/v2/vectordb/collections/has",
+		"/v2/vectordb/collections/create",
+		"/v2/vectordb/collections/has",
+	}, fake.paths)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The dog barks.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The car is red."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.inserts)
+
+	docs, err := store.SimilaritySearch(ctx, "cats", 1, vectorstores.WithFilters(`metadata["kind"] == "animal"`))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"kind": "animal"}, docs[0].Metadata)
+	search := fake.searches[0]
+	assert.Equal(t, `metadata["kind"] == "animal"`, search.Filter)
+	assert.Equal(t, []string{"text", "metadata"}, search.OutputFields)
+	assert.Equal(t, map[string]any{"metricType": "COSINE", "params": map[string]any{"ef": 64.0}}, search.SearchParams)
+
+	docs, err = store.SimilaritySearch(ctx, "dogs", 3, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+	assert.GreaterOrEqual(t, docs[0].Score, 0.9)
+
+	_, err = store.SimilaritySearch(ctx, "dogs", 3, vectorstores.WithFilters(map[string]any{"kind": "animal"}))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestMilvusStorePartitions(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeMilvus{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithURL(server.URL),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithCollectionName("docs"),
+		WithVectorDimensions(3),
+		WithPartition("pets"),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat sleeps."}})
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The car is red."}},
+		vectorstores.WithNameSpace("vehicles"))
+	require.NoError(t, err)
+	assert.Len(t, fake.partitions["pets"], 1)
+	assert.Len(t, fake.partitions["vehicles"], 1)
+
+	docs, err := store.SimilaritySearch(ctx, "cars", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, []string{"pets"}, fake.searches[0].PartitionNames)
+
+	docs, err = store.SimilaritySearch(ctx, "cars", 2, vectorstores.WithNameSpace("vehicles"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+}
+
+func TestMilvusStoreDelete(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeMilvus{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithURL(server.URL),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithCollectionName("docs"),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, field{
+		FieldName: "id", DataType: "VarChar", IsPrimary: true, ElementTypeParams: map[string]any{"max_length": 512.0},
+	}, fake.collection.Schema.Fields[0])
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat sleeps."}})
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat eats."}}, vectorstores.WithIDs(ids[0]))
+	require.NoError(t, err)
+	require.Len(t, fake.partitions["_default"], 1)
+	assert.Equal(t, "The cat eats.", fake.partitions["_default"][0]["text"])
+
+	require.NoError(t, store.Delete(ctx, []string{"a", `b"c`}, vectorstores.WithNameSpace("pets")))
+	require.NoError(t, store.DeleteByFilter(ctx, `metadata["kind"] == "animal"`))
+	assert.Equal(t, []deletePayload{
+		{CollectionName: "docs", PartitionName: "pets", Filter: `id in ["a","b\"c"]`},
+		{CollectionName: "docs", Filter: `metadata["kind"] == "animal"`},
+	}, fake.deletes)
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, map[string]any{"kind": "animal"}), ErrInvalidFilter)
+}
+
+func TestMilvusStoreIndexManager(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeMilvus{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithURL(server.URL),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithCollectionName("docs"),
+		WithVectorDimensions(3),
+		WithMetricType(MetricL2),
+		WithSkipCollectionCreation(),
+	)
+	require.NoError(t, err)
+	_, err = store.DescribeIndex(ctx)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+
+	require.NoError(t, store.CreateIndex(ctx))
+	info, err := store.DescribeIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, vectorstores.IndexInfo{Name: "docs", Dimensions: 3, Metric: "L2"}, info)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat sleeps."}, {PageContent: "The car."}})
+	require.NoError(t, err)
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.NumDocuments)
+
+	require.NoError(t, store.DeleteIndex(ctx))
+	_, err = store.Stats(ctx)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+}
+
+func TestMilvusStoreErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(response{Code: 1800, Message: "user hasn't authenticated"})
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := New(context.Background(), WithURL(server.URL), WithEmbedder(testutil.WordCountEmbedder{}),
+		WithCollectionName("docs"), WithVectorDimensions(3))
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1800, apiErr.Code)
+
+	_, err = New(context.Background(), WithEmbedder(testutil.WordCountEmbedder{}), WithCollectionName("docs"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), WithEmbedder(testutil.WordCountEmbedder{}), WithCollectionName("docs"),
+		WithVectorDimensions(3), WithMetricType("HAMMING"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -0,0 +1,189 @@
+package milvus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_milvusTokenEnvVarName = "MILVUS_TOKEN" //nolint:gosec
+	_defaultURL            = "http://localhost:19530"
+	_defaultTextField      = "text"
+	_defaultMetadataField  = "metadata"
+	_defaultVectorField    = "vector"
+	_defaultBatchSize      = 1000
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// MetricType is the metric of the similarity of the vectors.
+type MetricType string
+
+const (
+	// MetricCosine is the cosine similarity.
+	MetricCosine MetricType = "COSINE"
+	// MetricL2 is the Euclidean distance.
+	MetricL2 MetricType = "L2"
+	// MetricIP is the inner product.
+	MetricIP MetricType = "IP"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithURL is an option for setting the url of the Milvus server or of the
+// Zilliz Cloud cluster, http://localhost:19530 by default.
+func WithURL(url string) Option {
+	return func(p *Store) {
+		p.url = url
+	}
+}
+
+// WithToken is an option for setting the token, "user:password" or the API
+// key of Zilliz Cloud. If the option is not set the token is read from the
+// MILVUS_TOKEN environment variable, and may be empty for a local server
+// without authentication.
+func WithToken(token string) Option {
+	return func(p *Store) {
+		p.token = token
+	}
+}
+
+// WithCollectionName is an option for specifying the name of the collection.
+// Must be set.
+func WithCollectionName(name string) Option {
+	return func(p *Store) {
+		p.collectionName = name
+	}
+}
+
+// WithVectorDimensions is an option for specifying the number of dimensions
+// of the vectors of the embedder. Must be set to create the collection.
+func WithVectorDimensions(dimensions int) Option {
+	return func(p *Store) {
+		p.vectorDimensions = dimensions
+	}
+}
+
+// WithMetricType is an option for specifying the metric of the index, the
+// cosine similarity by default.
+func WithMetricType(metricType MetricType) Option {
+	return func(p *Store) {
+		p.metricType = metricType
+	}
+}
+
+// WithIndex is an option for specifying the type of the index of the
+// vectors, e.g. HNSW or IVF_FLAT, and its build parameters, e.g.
+// {"M": 16, "efConstruction": 200} or {"nlist": 1024}. AUTOINDEX by default.
+func WithIndex(indexType string, params map[string]any) Option {
+	return func(p *Store) {
+		p.indexType = indexType
+		p.indexParams = params
+	}
+}
+
+// WithSearchParams is an option for specifying the search parameters of the
+// index, e.g. {"ef": 64} for HNSW or {"nprobe": 16} for IVF_FLAT.
+func WithSearchParams(params map[string]any) Option {
+	return func(p *Store) {
+		p.searchParams = params
+	}
+}
+
+// WithPartition is an option for setting the partition to insert and search
+// the documents, created if it does not exist. The default partition if not
+// set. The partition can also be set per call with vectorstores.WithNameSpace.
+func WithPartition(partition string) Option {
+	return func(p *Store) {
+		p.partition = partition
+	}
+}
+
+// WithSkipCollectionCreation is an option for using an existing collection,
+// with the text, metadata and vector fields, without creating it.
+func WithSkipCollectionCreation() Option {
+	return func(p *Store) {
+		p.skipCollectionCreation = true
+	}
+}
+
+// WithFields is an option for setting the names of the text, metadata and
+// vector fields of the collection, text, metadata and vector by default.
+func WithFields(textField, metadataField, vectorField string) Option {
+	return func(p *Store) {
+		p.textField = textField
+		p.metadataField = metadataField
+		p.vectorField = vectorField
+	}
+}
+
+// WithBatchSize is an option for specifying the number of documents inserted
+// per request, 1000 by default.
+func WithBatchSize(batchSize int) Option {
+	return func(p *Store) {
+		p.batchSize = batchSize
+	}
+}
+
+// WithHTTPClient is an option for setting the HTTP client of the rest api.
+// If not set, http.DefaultClient is used.
+func WithHTTPClient(client Doer) Option {
+	return func(p *Store) {
+		p.httpClient = client
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	o := &Store{
+		url:           _defaultURL,
+		token:         os.Getenv(_milvusTokenEnvVarName),
+		metricType:    MetricCosine,
+		indexType:     "AUTOINDEX",
+		textField:     _defaultTextField,
+		metadataField: _defaultMetadataField,
+		vectorField:   _defaultVectorField,
+		batchSize:     _defaultBatchSize,
+		httpClient:    http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.collectionName == "" {
+		return Store{}, fmt.Errorf("%w: missing collection name", ErrInvalidOptions)
+	}
+
+	if o.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if !o.skipCollectionCreation && o.vectorDimensions <= 0 {
+		return Store{}, fmt.Errorf("%w: missing vector dimensions", ErrInvalidOptions)
+	}
+
+	switch o.metricType {
+	case MetricCosine, MetricL2, MetricIP:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown metric type %q", ErrInvalidOptions, o.metricType)
+	}
+
+	if o.batchSize <= 0 {
+		return Store{}, fmt.Errorf("%w: batch size must be positive", ErrInvalidOptions)
+	}
+
+	return *o, nil
+}
@@ -0,0 +1,210 @@
+package milvus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _idField is the primary field of the collections, holding the IDs of the
+// documents.
+const _idField = "id"
+
+// APIError is an error type returned if the rest api returns an error code.
+type APIError struct {
+	Task    string
+	Code    int
+	Message string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s: code %d: %s", e.Task, e.Code, e.Message)
+}
+
+// response is the envelope of the responses of the rest api, whose code is
+// not 0 on errors.
+type response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type field struct {
+	FieldName         string         `json:"fieldName"`
+	DataType          string         `json:"dataType"`
+	IsPrimary         bool           `json:"isPrimary,omitempty"`
+	ElementTypeParams map[string]any `json:"elementTypeParams,omitempty"`
+}
+
+type indexParam struct {
+	FieldName  string         `json:"fieldName"`
+	IndexName  string         `json:"indexName"`
+	MetricType MetricType     `json:"metricType"`
+	Params     map[string]any `json:"params"`
+}
+
+type createCollectionPayload struct {
+	CollectionName string `json:"collectionName"`
+	Schema         struct {
+		AutoID bool    `json:"autoId"`
+		Fields []field `json:"fields"`
+	} `json:"schema"`
+	IndexParams []indexParam `json:"indexParams"`
+}
+
+type hasPayload struct {
+	CollectionName string `json:"collectionName"`
+	PartitionName  string `json:"partitionName,omitempty"`
+}
+
+type insertPayload struct {
+	CollectionName string           `json:"collectionName"`
+	PartitionName  string           `json:"partitionName,omitempty"`
+	Data           []map[string]any `json:"data"`
+}
+
+type deletePayload struct {
+	CollectionName string `json:"collectionName"`
+	PartitionName  string `json:"partitionName,omitempty"`
+	Filter         string `json:"filter"`
+}
+
+type searchPayload struct {
+	CollectionName string         `json:"collectionName"`
+	PartitionNames []string       `json:"partitionNames,omitempty"`
+	Data           [][]float64    `json:"data"`
+	AnnsField      string         `json:"annsField"`
+	Filter         string         `json:"filter,omitempty"`
+	Limit          int            `json:"limit"`
+	OutputFields   []string       `json:"outputFields"`
+	SearchParams   map[string]any `json:"searchParams"`
+}
+
+// collectionDescription is the description of a collection, with the
+// parameters of its fields and its indexes.
+type collectionDescription struct {
+	Fields []struct {
+		Name   string `json:"name"`
+		Params []struct {
+			Key   string `json:"key"`
+			Value any    `json:"value"`
+		} `json:"params"`
+	} `json:"fields"`
+	Indexes []struct {
+		FieldName  string     `json:"fieldName"`
+		MetricType MetricType `json:"metricType"`
+	} `json:"indexes"`
+}
+
+// ensureCollection creates the collection with its index if it does not
+// exist. The collection is loaded on creation.
+func (s Store) ensureCollection(ctx context.Context) error {
+	has, err := s.has(ctx, "/v2/vectordb/collections/has", hasPayload{CollectionName: s.collectionName})
+	if err != nil || has {
+		return err
+	}
+
+	payload := createCollectionPayload{CollectionName: s.collectionName}
+	payload.Schema.Fields = []field{
+		{FieldName: _idField, DataType: "VarChar", IsPrimary: true, ElementTypeParams: map[string]any{"max_length": 512}},
+		{FieldName: s.textField, DataType: "VarChar", ElementTypeParams: map[string]any{"max_length": 65535}},
+		{FieldName: s.metadataField, DataType: "JSON"},
+		{FieldName: s.vectorField, DataType: "FloatVector", ElementTypeParams: map[string]any{"dim": s.vectorDimensions}},
+	}
+	params := map[string]any{"index_type": s.indexType}
+	for key, value := range s.indexParams {
+		params[key] = value
+	}
+	payload.IndexParams = []indexParam{{
+		FieldName:  s.vectorField,
+		IndexName:  s.vectorField,
+		MetricType: s.metricType,
+		Params:     params,
+	}}
+	return s.doRequest(ctx, "creating collection", "/v2/vectordb/collections/create", payload, nil)
+}
+
+// ensurePartition creates the partition of the collection if it does not
+// exist.
+func (s Store) ensurePartition(ctx context.Context, partition string) error {
+	payload := hasPayload{CollectionName: s.collectionName, PartitionName: partition}
+	has, err := s.has(ctx, "/v2/vectordb/partitions/has", payload)
+	if err != nil || has {
+		return err
+	}
+	return s.doRequest(ctx, "creating partition", "/v2/vectordb/partitions/create", payload, nil)
+}
+
+// checkCollection returns vectorstores.ErrIndexNotFound if the collection does
+// not exist.
+func (s Store) checkCollection(ctx context.Context) error {
+	has, err := s.has(ctx, "/v2/vectordb/collections/has", hasPayload{CollectionName: s.collectionName})
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.collectionName)
+	}
+	return nil
+}
+
+func (s Store) has(ctx context.Context, path string, payload hasPayload) (bool, error) {
+	var data struct {
+		Has bool `json:"has"`
+	}
+	err := s.doRequest(ctx, "checking existence", path, payload, &data)
+	return data.Has, err
+}
+
+// hit is a result of a search, holding the output fields.
+type hit map[string]json.RawMessage
+
+func (s Store) search(ctx context.Context, payload searchPayload) ([]hit, error) {
+	var hits []hit
+	if err := s.doRequest(ctx, "searching vectors", "/v2/vectordb/entities/search", payload, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// doRequest posts the payload to the path and decodes the data of the
+// response into result, if not nil.
+func (s Store) doRequest(ctx context.Context, task, path string, payload, result any) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(s.url, "/")+path, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	var res response
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return APIError{Task: task, Code: r.StatusCode, Message: fmt.Sprintf("decoding response: %v", err)}
+	}
+	if r.StatusCode != http.StatusOK || res.Code != 0 {
+		return APIError{Task: task, Code: res.Code, Message: res.Message}
+	}
+	if result == nil || len(res.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Data, result)
+}
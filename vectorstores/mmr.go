@@ -0,0 +1,143 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidMMR is returned when the options of a maximal marginal relevance
+// search are invalid.
+var ErrInvalidMMR = errors.New("invalid maximal marginal relevance options")
+
+// MMR are the options of a maximal marginal relevance search, see WithMMR.
+type MMR struct {
+	// Lambda is the weight of the similarity to the query, 1 - Lambda being
+	// the weight of the dissimilarity to the documents already selected.
+	Lambda float64
+	// FetchK is the number of nearest documents the documents are selected
+	// from.
+	FetchK int
+}
+
+// WithMMR returns an Option for selecting the documents of a similarity
+// search by maximal marginal relevance among the fetchK nearest documents,
+// diversifying the results instead of returning near duplicates. Lambda is
+// between 0 for the most diverse documents and 1 for the most similar ones,
+// 0.5 being a common choice. The stores not keeping the vectors of the
+// documents at hand embed the fetched documents again.
+func WithMMR(lambda float64, fetchK int) Option {
+	return func(o *Options) {
+		o.MMR = &MMR{Lambda: lambda, FetchK: fetchK}
+	}
+}
+
+// FetchK returns the number of documents to fetch for a search of
+// numDocuments documents with the options, fetchK with maximal marginal
+// relevance, numDocuments otherwise.
+func FetchK(opts Options, numDocuments int) (int, error) {
+	if opts.MMR == nil {
+		return numDocuments, nil
+	}
+	if opts.MMR.Lambda < 0 || opts.MMR.Lambda > 1 {
+		return 0, fmt.Errorf("%w: lambda must be between 0 and 1", ErrInvalidMMR)
+	}
+	if opts.MMR.FetchK < numDocuments {
+		return numDocuments, nil
+	}
+	return opts.MMR.FetchK, nil
+}
+
+// MaximalMarginalRelevance returns the indexes of the k vectors selected by
+// maximal marginal relevance to the query, in their order of selection. Each
+// step selects the vector maximizing lambda times its cosine similarity to
+// the query minus 1 - lambda times its highest cosine similarity to the
+// vectors already selected.
+func MaximalMarginalRelevance(query []float64, vectors [][]float64, k int, lambda float64) []int {
+	if k > len(vectors) {
+		k = len(vectors)
+	}
+	relevance := make([]float64, len(vectors))
+	for i, v := range vectors {
+		relevance[i] = cosineSimilarity(query, v)
+	}
+	// redundancy is the highest similarity of each vector to the selected
+	// vectors.
+	redundancy := make([]float64, len(vectors))
+	for i := range redundancy {
+		redundancy[i] = math.Inf(-1)
+	}
+	selected := make([]int, 0, k)
+	chosen := make([]bool, len(vectors))
+	for len(selected) < k {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range vectors {
+			if chosen[i] {
+				continue
+			}
+			score := lambda * relevance[i]
+			if len(selected) > 0 {
+				score -= (1 - lambda) * redundancy[i]
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		selected = append(selected, best)
+		chosen[best] = true
+		for i, v := range vectors {
+			if !chosen[i] {
+				redundancy[i] = math.Max(redundancy[i], cosineSimilarity(vectors[best], v))
+			}
+		}
+	}
+	return selected
+}
+
+// RerankMMR embeds the documents fetched by a search with the embedder and
+// returns the numDocuments documents selected by maximal marginal relevance
+// to the vector of the query, for the stores not keeping the vectors of the
+// documents at hand. The documents are returned as is without MMR options.
+func RerankMMR(
+	ctx context.Context,
+	embedder embeddings.Embedder,
+	query []float64,
+	docs []schema.Document,
+	numDocuments int,
+	opts Options,
+) ([]schema.Document, error) {
+	if opts.MMR == nil || len(docs) == 0 {
+		return docs, nil
+	}
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	selected := MaximalMarginalRelevance(query, vectors, numDocuments, opts.MMR.Lambda)
+	reranked := make([]schema.Document, 0, len(selected))
+	for _, i := range selected {
+		reranked = append(reranked, docs[i])
+	}
+	return reranked, nil
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,66 @@
+package vectorstores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestMaximalMarginalRelevance(t *testing.T) {
+	t.Parallel()
+
+	query := []float64{1, 0}
+	vectors := [][]float64{{1, 0}, {1, 0.01}, {0.7, 0.7}}
+
+	// The most similar vectors without diversity.
+	assert.Equal(t, []int{0, 1}, MaximalMarginalRelevance(query, vectors, 2, 1))
+	// The near duplicate of the first vector is skipped.
+	assert.Equal(t, []int{0, 2}, MaximalMarginalRelevance(query, vectors, 2, 0.3))
+	assert.Equal(t, []int{0, 2, 1}, MaximalMarginalRelevance(query, vectors, 5, 0.3))
+}
+
+func TestFetchK(t *testing.T) {
+	t.Parallel()
+
+	k, err := FetchK(Options{}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, k)
+
+	k, err = FetchK(Options{MMR: &MMR{Lambda: 0.5, FetchK: 20}}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 20, k)
+
+	k, err = FetchK(Options{MMR: &MMR{Lambda: 0.5, FetchK: 2}}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, k)
+
+	_, err = FetchK(Options{MMR: &MMR{Lambda: 2, FetchK: 20}}, 4)
+	require.ErrorIs(t, err, ErrInvalidMMR)
+}
+
+func TestRerankMMR(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	docs := []schema.Document{
+		{PageContent: "The cat sleeps."},
+		{PageContent: "The dog barks at the cat."},
+		{PageContent: "The car is red."},
+	}
+	query, err := testutil.WordCountEmbedder{}.EmbedQuery(ctx, "cat")
+	require.NoError(t, err)
+
+	reranked, err := RerankMMR(ctx, testutil.WordCountEmbedder{}, query, docs, 2, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, docs, reranked)
+
+	opts := Options{}
+	WithMMR(0.25, 3)(&opts)
+	reranked, err = RerankMMR(ctx, testutil.WordCountEmbedder{}, query, docs, 2, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{docs[0], docs[2]}, reranked)
+}
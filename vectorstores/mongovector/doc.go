@@ -0,0 +1,3 @@
+// Package mongovector contains an implementation of the vectorStore
+// interface using MongoDB Atlas Vector Search.
+package mongovector
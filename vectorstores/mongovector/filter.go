@@ -0,0 +1,46 @@
+package mongovector
+
+import (
+	"github.com/tmc/langchaingo/vectorstores/filters"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// mqlFilter returns the filter as a query of the MQL operators on the fields
+// of the metadata of the documents, e.g. {"$and": [{"metadata.author":
+// {"$eq": "bob"}}, {"metadata.year": {"$gt": 2020}}]}. The MQL $not operator
+// only negates the operators of a field, so the negations are pushed down to
+// the comparisons.
+func (s Store) mqlFilter(f filters.Filter) (bson.D, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return s.translate(f), nil
+}
+
+func (s Store) translate(f filters.Filter) bson.D {
+	field := s.metadataKey + "." + f.Field
+	switch f.Op {
+	case filters.OpAnd, filters.OpOr:
+		operands := make(bson.A, 0, len(f.Filters))
+		for _, filter := range f.Filters {
+			operands = append(operands, s.translate(filter))
+		}
+		return bson.D{{Key: "$" + string(f.Op), Value: operands}}
+	case filters.OpNot:
+		return s.translate(f.Filters[0].Negate())
+	case filters.OpIn, filters.OpNin:
+		values, _ := f.Values()
+		return bson.D{{Key: field, Value: bson.D{{Key: "$" + string(f.Op), Value: bson.A(values)}}}}
+	default:
+		return bson.D{{Key: field, Value: bson.D{{Key: "$" + string(f.Op), Value: f.Value}}}}
+	}
+}
+
+// getFilters returns the filters as a query of the MQL operators, passing
+// the filters other than a filters.Filter as they are.
+func (s Store) getFilters(filter any) (any, error) {
+	if f, ok := filter.(filters.Filter); ok {
+		return s.mqlFilter(f)
+	}
+	return filter, nil
+}
@@ -0,0 +1,359 @@
+package mongovector
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrMissingTextKey is returned when a document of the results has no
+	// content.
+	ErrMissingTextKey = errors.New("missing text field in search result")
+)
+
+// _nameSpaceKey is the field of the name space of the documents not in the
+// default name space.
+const _nameSpaceKey = "namespace"
+
+// Similarity is the similarity function of the vectors of an index.
+type Similarity string
+
+const (
+	// SimilarityCosine is the cosine similarity.
+	SimilarityCosine Similarity = "cosine"
+	// SimilarityEuclidean is the Euclidean distance.
+	SimilarityEuclidean Similarity = "euclidean"
+	// SimilarityDotProduct is the dot product, for normalized vectors the
+	// fastest equivalent of the cosine similarity.
+	SimilarityDotProduct Similarity = "dotProduct"
+)
+
+// Collection is the collection of the documents, implemented by
+// *mongo.Collection.
+type Collection interface {
+	BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) //nolint:lll
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+}
+
+// Store is a wrapper around a MongoDB Atlas collection of documents with
+// their vectors, searched with the $vectorSearch aggregation stage.
+type Store struct {
+	embedder   embeddings.Embedder
+	collection Collection
+
+	index         string
+	path          string
+	textKey       string
+	metadataKey   string
+	nameSpace     string
+	numCandidates int
+}
+
+var _ vectorstores.VectorStore = Store{}
+
+// New creates a new Store of the collection with options. The embedder must
+// be set. The collection must have an Atlas Vector Search index of the
+// vectors, see VectorSearchIndex and CreateVectorSearchIndex.
+func New(collection Collection, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+	s.collection = collection
+	return s, nil
+}
+
+// VectorSearchIndex returns the definition of an Atlas Vector Search index of
+// the vectors of the path, e.g. embedding, with the number of dimensions of
+// the embedder and the similarity. The filter paths are the fields the
+// filters of the searches may refer to, e.g. metadata.country, and must
+// include namespace to search the name spaces other than the default.
+func VectorSearchIndex(path string, dimensions int, similarity Similarity, filterPaths ...string) bson.D {
+	fields := bson.A{bson.D{
+		{Key: "type", Value: "vector"},
+		{Key: "path", Value: path},
+		{Key: "numDimensions", Value: dimensions},
+		{Key: "similarity", Value: similarity},
+	}}
+	for _, filterPath := range filterPaths {
+		fields = append(fields, bson.D{
+			{Key: "type", Value: "filter"},
+			{Key: "path", Value: filterPath},
+		})
+	}
+	return bson.D{{Key: "fields", Value: fields}}
+}
+
+// CreateVectorSearchIndex creates the Atlas Vector Search index of the
+// collection with the name and the definition, e.g. of VectorSearchIndex.
+// The index is built asynchronously, the searches return no documents until
+// it is ready.
+func CreateVectorSearchIndex(ctx context.Context, collection *mongo.Collection, name string, definition bson.D) error {
+	return collection.Database().RunCommand(ctx, bson.D{
+		{Key: "createSearchIndexes", Value: collection.Name()},
+		{Key: "indexes", Value: bson.A{bson.D{
+			{Key: "name", Value: name},
+			{Key: "type", Value: "vectorSearch"},
+			{Key: "definition", Value: definition},
+		}}},
+	}).Err()
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and writes them to the collection with their IDs as _id, replacing the
+// documents with the same IDs. The documents of the name spaces other than
+// the default have their name space in the namespace field.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for i, doc := range docs {
+		metadata := doc.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		replacement := bson.D{
+			{Key: "_id", Value: ids[i]},
+			{Key: s.textKey, Value: texts[i]},
+			{Key: s.path, Value: vectors[i]},
+			{Key: s.metadataKey, Value: metadata},
+		}
+		if nameSpace != "" {
+			replacement = append(replacement, bson.E{Key: _nameSpaceKey, Value: nameSpace})
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: ids[i]}}).
+			SetReplacement(replacement).
+			SetUpsert(true))
+	}
+	if _, err := s.collection.BulkWrite(ctx, models); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete deletes the documents with the IDs, of the name space unless it is
+// the default name space.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	filter := s.scope(s.getNameSpace(s.getOptions(options...)),
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	_, err := s.collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// DeleteByFilter deletes the documents matching the filter, a query of the
+// MQL operators or a filters.Filter as in SimilaritySearch, of the name space
+// unless it is the default name space.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	query, err := s.getFilters(filter)
+	if err != nil {
+		return err
+	}
+	_, err = s.collection.DeleteMany(ctx, s.scope(s.getNameSpace(s.getOptions(options...)), query))
+	return err
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a query of
+// the MQL operators supported by Atlas Vector Search on the filter fields of
+// the index, e.g. bson.D{{Key: "metadata.country", Value: "France"}}, or a
+// filters.Filter on the metadata, translated to the MQL operators, which
+// prefilter the documents before the search. The scores are the normalized
+// scores of Atlas, between 0 and 1.
+//
+// Only the documents of the name space are searched, filtered on the
+// namespace field, unless it is the default name space: the searches of the
+// default name space search all the documents, so that the indexes without
+// the namespace filter path keep working.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.getFilters(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.collection.Aggregate(ctx,
+		s.pipeline(vector, fetchK, s.scope(s.getNameSpace(opts), filter)))
+	if err != nil {
+		return nil, err
+	}
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	for _, result := range results {
+		score, _ := result["score"].(float64)
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && score < scoreThreshold {
+			continue
+		}
+		doc, err := s.newDocument(result)
+		if err != nil {
+			return nil, err
+		}
+		doc.Score = score
+		docs = append(docs, doc)
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// pipeline returns the aggregation pipeline of the search of the k nearest
+// documents to the vector matching the filter, with their content, metadata
+// and score.
+func (s Store) pipeline(vector []float64, k int, filter any) mongo.Pipeline {
+	numCandidates := s.numCandidates
+	if numCandidates == 0 {
+		numCandidates = 10 * k
+		if numCandidates < 100 { //nolint:gomnd
+			numCandidates = 100
+		}
+	}
+	search := bson.D{
+		{Key: "index", Value: s.index},
+		{Key: "path", Value: s.path},
+		{Key: "queryVector", Value: vector},
+		{Key: "numCandidates", Value: numCandidates},
+		{Key: "limit", Value: k},
+	}
+	if filter != nil {
+		search = append(search, bson.E{Key: "filter", Value: filter})
+	}
+	return mongo.Pipeline{
+		{{Key: "$vectorSearch", Value: search}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: s.textKey, Value: 1},
+			{Key: s.metadataKey, Value: 1},
+			{Key: "score", Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}},
+		}}},
+	}
+}
+
+// scope returns the filter of the documents of the name space matching the
+// filter, if not nil, or the filter for the default name space.
+func (s Store) scope(nameSpace string, filter any) any {
+	if nameSpace == "" {
+		return filter
+	}
+	clause := bson.D{{Key: _nameSpaceKey, Value: bson.D{{Key: "$eq", Value: nameSpace}}}}
+	if filter == nil {
+		return clause
+	}
+	return bson.D{{Key: "$and", Value: bson.A{clause, filter}}}
+}
+
+// newDocument returns the document of a search result.
+func (s Store) newDocument(result bson.M) (schema.Document, error) {
+	text, ok := result[s.textKey].(string)
+	if !ok {
+		return schema.Document{}, ErrMissingTextKey
+	}
+	doc := schema.Document{PageContent: text, Metadata: map[string]any{}}
+	if metadata, ok := result[s.metadataKey].(bson.M); ok {
+		for k, v := range metadata {
+			doc.Metadata[k] = v
+		}
+	}
+	return doc, nil
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getNameSpace returns the name space of the call, scoped by its tenant.
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	nameSpace := s.nameSpace
+	if opts.NameSpace != "" {
+		nameSpace = opts.NameSpace
+	}
+	return vectorstores.TenantNameSpace(opts.Tenant, nameSpace)
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
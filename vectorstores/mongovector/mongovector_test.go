@@ -0,0 +1,283 @@
+package mongovector
+
+import (
+	"context"
+	"math"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fakeCollection holds the documents in memory. The searches score the
+// documents with the cosine similarity normalized by Atlas, (1 + cosine) / 2,
+// the filters are ignored. The deletions only delete the documents of $in
+// queries on _id.
+type fakeCollection struct {
+	docs      []bson.D
+	pipelines []mongo.Pipeline
+	deletes   []interface{}
+}
+
+func (f *fakeCollection) BulkWrite(_ context.Context, models []mongo.WriteModel, _ ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) { //nolint:lll
+	for _, model := range models {
+		d := model.(*mongo.ReplaceOneModel).Replacement.(bson.D) //nolint:forcetypeassert
+		f.delete(d.Map()["_id"])                                 //nolint:staticcheck
+		f.docs = append(f.docs, d)
+	}
+	return &mongo.BulkWriteResult{}, nil
+}
+
+func (f *fakeCollection) DeleteMany(_ context.Context, filter interface{}, _ ...*options.DeleteOptions) (*mongo.DeleteResult, error) { //nolint:lll
+	f.deletes = append(f.deletes, filter)
+	query, _ := filter.(bson.D)
+	if len(query) == 1 && query[0].Key == "_id" {
+		ids, _ := query[0].Value.(bson.D).Map()["$in"].([]string) //nolint:forcetypeassert,staticcheck
+		for _, id := range ids {
+			f.delete(id)
+		}
+	}
+	return &mongo.DeleteResult{}, nil
+}
+
+// delete deletes the document with the ID, if any.
+func (f *fakeCollection) delete(id interface{}) {
+	for i, d := range f.docs {
+		if d.Map()["_id"] == id { //nolint:staticcheck
+			f.docs = append(f.docs[:i], f.docs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *fakeCollection) Aggregate(_ context.Context, pipeline interface{}, _ ...*options.AggregateOptions) (*mongo.Cursor, error) { //nolint:lll
+	p := pipeline.(mongo.Pipeline) //nolint:forcetypeassert
+	f.pipelines = append(f.pipelines, p)
+	search := p[0][0].Value.(bson.D).Map()     //nolint:forcetypeassert,staticcheck
+	query := search["queryVector"].([]float64) //nolint:forcetypeassert
+
+	results := make([]interface{}, 0, len(f.docs))
+	for _, d := range f.docs {
+		m := d.Map() //nolint:staticcheck
+		results = append(results, bson.M{
+			"text":     m["text"],
+			"metadata": m["metadata"],
+			"score":    (1 + cosine(query, m[search["path"].(string)].([]float64))) / 2, //nolint:forcetypeassert
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].(bson.M)["score"].(float64) > results[j].(bson.M)["score"].(float64) //nolint:forcetypeassert
+	})
+	if limit := search["limit"].(int); len(results) > limit { //nolint:forcetypeassert
+		results = results[:limit]
+	}
+	return mongo.NewCursorFromDocuments(results, nil, nil)
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+var testDocs = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "The cat sleeps.", Metadata: map[string]any{"animal": true}},
+	{PageContent: "The dog barks at the cat.", Metadata: map[string]any{"animal": true}},
+	{PageContent: "The car is red.", Metadata: map[string]any{"animal": false}},
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	collection := &fakeCollection{}
+	store, err := New(collection, WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+	require.Len(t, collection.docs, 3)
+
+	docs, err := store.SimilaritySearch(ctx, "cat", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"animal": true}, docs[0].Metadata)
+	assert.Equal(t, "The dog barks at the cat.", docs[1].PageContent)
+
+	// The normalized score of the second document is 0.87.
+	assert.InDelta(t, 0.87, docs[1].Score, 0.01)
+	docs, err = store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	_, err = store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithScoreThreshold(2))
+	require.ErrorIs(t, err, ErrInvalidScoreThreshold)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	collection := &fakeCollection{}
+	store, err := New(collection, WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	ids, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat eats."}}, vectorstores.WithIDs(ids[0]))
+	require.NoError(t, err)
+	require.Len(t, collection.docs, 3)
+	assert.Equal(t, "The cat eats.", collection.docs[2].Map()["text"]) //nolint:staticcheck
+
+	require.NoError(t, store.Delete(ctx, ids[:2]))
+	require.Len(t, collection.docs, 1)
+	assert.Equal(t, "The car is red.", collection.docs[0].Map()["text"]) //nolint:staticcheck
+
+	filter := bson.D{{Key: "metadata.animal", Value: false}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter))
+	assert.Equal(t, filter, collection.deletes[1])
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+}
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	collection := &fakeCollection{}
+	store, err := New(collection, WithEmbedder(testutil.WordCountEmbedder{}), WithIndex("docs"))
+	require.NoError(t, err)
+
+	filter := bson.D{{Key: "metadata.animal", Value: true}}
+	_, err = store.SimilaritySearch(context.Background(), "cat", 2, vectorstores.WithFilters(filter))
+	require.NoError(t, err)
+
+	search := collection.pipelines[0][0][0].Value.(bson.D).Map() //nolint:forcetypeassert,staticcheck
+	assert.Equal(t, "docs", search["index"])
+	assert.Equal(t, "embedding", search["path"])
+	assert.Equal(t, 100, search["numCandidates"])
+	assert.Equal(t, 2, search["limit"])
+	assert.Equal(t, filter, search["filter"])
+}
+
+func TestTypedFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	collection := &fakeCollection{}
+	store, err := New(collection, WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+
+	filter := filters.Eq("animal", true).
+		And(filters.Or(filters.Gte("legs", 2), filters.In("name", "cat", "dog")), filters.Not(filters.Lt("age", 3)))
+	_, err = store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithFilters(filter))
+	require.NoError(t, err)
+
+	search := collection.pipelines[0][0][0].Value.(bson.D).Map() //nolint:forcetypeassert,staticcheck
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "metadata.animal", Value: bson.D{{Key: "$eq", Value: true}}}},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "metadata.legs", Value: bson.D{{Key: "$gte", Value: 2}}}},
+			bson.D{{Key: "metadata.name", Value: bson.D{{Key: "$in", Value: bson.A{"cat", "dog"}}}}},
+		}}},
+		bson.D{{Key: "metadata.age", Value: bson.D{{Key: "$gte", Value: 3}}}},
+	}}}, search["filter"])
+
+	require.NoError(t, store.DeleteByFilter(ctx, filters.Nin("name", "car"), vectorstores.WithNameSpace("pets")))
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "namespace", Value: bson.D{{Key: "$eq", Value: "pets"}}}},
+		bson.D{{Key: "metadata.name", Value: bson.D{{Key: "$nin", Value: bson.A{"car"}}}}},
+	}}}, collection.deletes[0])
+
+	_, err = store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithFilters(filters.And()))
+	require.ErrorIs(t, err, filters.ErrInvalidFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, filters.Eq("", 1)), filters.ErrInvalidFilter)
+	require.Len(t, collection.pipelines, 1)
+	require.Len(t, collection.deletes, 1)
+}
+
+func TestNameSpace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	collection := &fakeCollection{}
+	store, err := New(collection, WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, testDocs[:1], vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, testDocs[2:])
+	require.NoError(t, err)
+	assert.Equal(t, "pets", collection.docs[0].Map()["namespace"]) //nolint:staticcheck
+	assert.NotContains(t, collection.docs[1].Map(), "namespace")   //nolint:staticcheck
+
+	nameSpace := bson.D{{Key: "namespace", Value: bson.D{{Key: "$eq", Value: "cars"}}}}
+	filter := bson.D{{Key: "metadata.animal", Value: false}}
+	_, err = store.SimilaritySearch(ctx, "car", 1, vectorstores.WithNameSpace("cars"))
+	require.NoError(t, err)
+	search := collection.pipelines[0][0][0].Value.(bson.D).Map() //nolint:forcetypeassert,staticcheck
+	assert.Equal(t, nameSpace, search["filter"])
+
+	require.NoError(t, store.DeleteByFilter(ctx, filter, vectorstores.WithNameSpace("cars")))
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{nameSpace, filter}}}, collection.deletes[0])
+}
+
+func TestVectorSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	index := VectorSearchIndex("embedding", 1536, SimilarityCosine, "metadata.country")
+	b, err := bson.MarshalExtJSON(index, false, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fields":[
+		{"type":"vector","path":"embedding","numDimensions":1536,"similarity":"cosine"},
+		{"type":"filter","path":"metadata.country"}
+	]}`, string(b))
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&fakeCollection{})
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(&fakeCollection{}, WithEmbedder(testutil.WordCountEmbedder{}), WithNumCandidates(-1))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestMongoVectorStore(t *testing.T) {
+	t.Parallel()
+
+	uri := os.Getenv("MONGODB_ATLAS_URI")
+	if uri == "" {
+		t.Skip("Must set MONGODB_ATLAS_URI to run test")
+	}
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Disconnect(ctx) })
+
+	// The collection must have a vector search index of 3 dimensions named
+	// vector_index.
+	store, err := New(client.Database("langchaingo").Collection("documents"),
+		WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "cat", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
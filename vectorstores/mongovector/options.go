@@ -0,0 +1,102 @@
+package mongovector
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_defaultIndex       = "vector_index"
+	_defaultPath        = "embedding"
+	_defaultTextKey     = "text"
+	_defaultMetadataKey = "metadata"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithIndex is an option for specifying the name of the Atlas Vector Search
+// index of the vectors, vector_index by default.
+func WithIndex(index string) Option {
+	return func(p *Store) {
+		p.index = index
+	}
+}
+
+// WithPath is an option for specifying the field of the vectors of the
+// documents, embedding by default.
+func WithPath(path string) Option {
+	return func(p *Store) {
+		p.path = path
+	}
+}
+
+// WithTextKey is an option for specifying the field of the content of the
+// documents, text by default.
+func WithTextKey(key string) Option {
+	return func(p *Store) {
+		p.textKey = key
+	}
+}
+
+// WithMetadataKey is an option for specifying the field of the metadata of
+// the documents, metadata by default. The filters of the searches refer to
+// the fields of the metadata with this prefix, e.g. metadata.country.
+func WithMetadataKey(key string) Option {
+	return func(p *Store) {
+		p.metadataKey = key
+	}
+}
+
+// WithNameSpace is an option for setting the default name space of the
+// documents, overridden by vectorstores.WithNameSpace. The index must have the
+// namespace filter path, see VectorSearchIndex.
+func WithNameSpace(nameSpace string) Option {
+	return func(p *Store) {
+		p.nameSpace = nameSpace
+	}
+}
+
+// WithNumCandidates is an option for specifying the number of nearest
+// neighbors considered by the searches, trading speed for recall. By default
+// ten times the number of documents searched, at least 100.
+func WithNumCandidates(numCandidates int) Option {
+	return func(p *Store) {
+		p.numCandidates = numCandidates
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	o := &Store{
+		index:       _defaultIndex,
+		path:        _defaultPath,
+		textKey:     _defaultTextKey,
+		metadataKey: _defaultMetadataKey,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if o.numCandidates < 0 {
+		return Store{}, fmt.Errorf("%w: number of candidates must not be negative", ErrInvalidOptions)
+	}
+
+	return *o, nil
+}
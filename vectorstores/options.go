@@ -1,19 +1,56 @@
 package vectorstores
 
-import "github.com/tmc/langchaingo/embeddings"
+import (
+	"errors"
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrSparseNotSupported is returned by the vector stores not supporting the
+// sparse vectors of a sparse embedder.
+var ErrSparseNotSupported = errors.New("sparse vectors not supported by the vector store")
 
 // Option is a function that configures an Options.
 type Option func(*Options)
 
 // Options is a set of options for similarity search and add documents.
 type Options struct {
-	NameSpace      string
+	NameSpace string
+	// Tenant is the tenant of the documents, see WithTenant. Empty if not
+	// set.
+	Tenant         string
 	ScoreThreshold float64
 	Filters        any
 	Embedder       embeddings.Embedder
+	SparseEmbedder embeddings.SparseEmbedder
+	// Alpha is the weight of the dense vectors in hybrid searches, the
+	// weight of the sparse vectors being 1 - Alpha. Nil if not set.
+	Alpha *float64
+	// MMR selects the documents of the searches by maximal marginal
+	// relevance. Nil if not set.
+	MMR *MMR
+	// IDs are the IDs of the documents added, one per document. Nil if not
+	// set.
+	IDs []string
+	// IDKey returns the key of a document added, from which its ID is
+	// derived. Nil if not set.
+	IDKey func(doc schema.Document) string
+	// TTL is the time to live of the documents added, see WithTTL. Zero if
+	// not set.
+	TTL time.Duration
+	// Offset is the number of documents of the results of the searches
+	// skipped, see WithOffset. Zero if not set.
+	Offset int
 }
 
-// WithNameSpace returns an Option for setting the name space.
+// WithNameSpace returns an Option for setting the name space of the documents
+// added, searched and deleted, so that one store holds several corpora. The
+// stores map the name spaces to their own partitioning, e.g. the namespaces
+// of Pinecone, the partitions of Milvus, or a property of Weaviate or a column
+// of pgvector, and default to the name space set with their own
+// WithNameSpace option, if any.
 func WithNameSpace(nameSpace string) Option {
 	return func(o *Options) {
 		o.NameSpace = nameSpace
@@ -30,6 +67,10 @@ func WithScoreThreshold(scoreThreshold float64) Option {
 // filters retrieve exactly the number of nearest-neighbors results that match the filters. In
 // most cases the search latency will be lower than unfiltered searches
 // See https://docs.pinecone.io/docs/metadata-filtering
+//
+// The filters are either a filters.Filter, which the pgvector, pinecone and
+// inmemory stores translate to their own filters, or the native filters of
+// the store.
 func WithFilters(filters any) Option {
 	return func(o *Options) {
 		o.Filters = filters
@@ -44,3 +85,24 @@ func WithEmbedder(embedder embeddings.Embedder) Option {
 		o.Embedder = embedder
 	}
 }
+
+// WithSparseEmbedder returns an Option for setting the sparse embedder of the
+// sparse vectors added with the dense vectors of the documents and searched
+// with the dense vector of the query, for hybrid searches combining keyword
+// and semantic similarity. Stores not supporting sparse vectors return
+// ErrSparseNotSupported.
+func WithSparseEmbedder(embedder embeddings.SparseEmbedder) Option {
+	return func(o *Options) {
+		o.SparseEmbedder = embedder
+	}
+}
+
+// WithAlpha returns an Option for setting the weight of the dense vector of
+// the query in hybrid searches, from 0 for a sparse search to 1 for a dense
+// search, the weight of the sparse vector being 1 - alpha. If not set, both
+// vectors are unweighted.
+func WithAlpha(alpha float64) Option {
+	return func(o *Options) {
+		o.Alpha = &alpha
+	}
+}
@@ -0,0 +1,91 @@
+package vectorstores
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _cursorPrefix = "offset:"
+
+var (
+	// ErrOffsetNotSupported is returned by the vector stores not supporting
+	// the offset of WithOffset.
+	ErrOffsetNotSupported = errors.New("offset not supported by the vector store")
+	// ErrInvalidCursor is returned by SearchPage when the cursor is not a
+	// cursor of a previous page.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// VectorSearcher is the interface of the vector stores searching the
+// documents most similar to a vector, for the callers which already computed
+// the embedding of the query. The options are those of SimilaritySearch, the
+// embedders being used by maximal marginal relevance only.
+type VectorSearcher interface {
+	SimilaritySearchByVector(ctx context.Context, vector []float64, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
+}
+
+// WithOffset returns an Option for skipping the first documents of the
+// results of the searches, e.g. the documents of the previous pages, without
+// returning them. With maximal marginal relevance, the documents are
+// selected among the documents following the offset. The stores not
+// supporting offsets return ErrOffsetNotSupported.
+func WithOffset(offset int) Option {
+	return func(o *Options) {
+		o.Offset = offset
+	}
+}
+
+// Page is a page of the results of a search.
+type Page struct {
+	Documents []schema.Document
+	// NextCursor is the cursor of the next page, empty for the last page.
+	NextCursor string
+}
+
+// SearchPage returns the page of pageSize documents of the results of the
+// similarity search of the query starting at the cursor, the first page with
+// an empty cursor and the next pages with the cursors of the previous pages.
+// The store must support WithOffset. One more document is fetched to know
+// whether there is a next page.
+func SearchPage(ctx context.Context, store VectorStore, query string, pageSize int, cursor string, options ...Option) (Page, error) { //nolint:lll
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+	options = append(options[:len(options):len(options)], WithOffset(offset))
+	docs, err := store.SimilaritySearch(ctx, query, pageSize+1, options...)
+	if err != nil {
+		return Page{}, err
+	}
+	if len(docs) <= pageSize {
+		return Page{Documents: docs}, nil
+	}
+	return Page{Documents: docs[:pageSize], NextCursor: encodeCursor(offset + pageSize)}, nil
+}
+
+// encodeCursor returns the opaque cursor of the offset.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(_cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset of the cursor, 0 for the empty cursor.
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), _cursorPrefix) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), _cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return offset, nil
+}
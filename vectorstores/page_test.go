@@ -0,0 +1,83 @@
+package vectorstores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// offsetStore returns its documents following the offset of the options.
+type offsetStore struct {
+	fakeStore
+}
+
+func (s *offsetStore) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) { //nolint:lll
+	all := s.docs
+	defer func() { s.docs = all }()
+
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Offset < len(s.docs) {
+		s.docs = s.docs[opts.Offset:]
+	} else {
+		s.docs = nil
+	}
+	return s.fakeStore.SimilaritySearch(ctx, query, numDocuments, options...)
+}
+
+func TestSearchPage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &offsetStore{fakeStore{docs: []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"}, {PageContent: "d"}, {PageContent: "e"},
+	}}}
+
+	var contents []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := SearchPage(ctx, store, "query", 2, cursor, WithNameSpace("docs"))
+		require.NoError(t, err)
+		for _, doc := range page.Documents {
+			contents = append(contents, doc.PageContent)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, contents)
+
+	require.Len(t, store.options, 3)
+	for i, opts := range store.options {
+		assert.Equal(t, "docs", opts.NameSpace)
+		assert.Equal(t, 2*i, opts.Offset)
+	}
+
+	// There is no next page when the documents fit in the page.
+	page, err := SearchPage(ctx, store, "query", 5, "", WithNameSpace("docs"))
+	require.NoError(t, err)
+	assert.Len(t, page.Documents, 5)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestSearchPageInvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	store := &offsetStore{}
+	for _, cursor := range []string{"not a cursor", encodeCursor(-1), "b2Zmc2V0Ong"} {
+		_, err := SearchPage(context.Background(), store, "query", 2, cursor)
+		require.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+	assert.Empty(t, store.options)
+
+	offset, err := decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, 42, offset)
+}
@@ -0,0 +1,3 @@
+// Package pgvector contains an implementation of the vectorStore
+// interface using PostgreSQL with the pgvector extension.
+package pgvector
@@ -0,0 +1,81 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// _pathOperators are the comparison operators of the SQL/JSON path
+// language.
+var _pathOperators = map[filters.Op]string{ //nolint:gochecknoglobals
+	filters.OpEq:  "==",
+	filters.OpNe:  "!=",
+	filters.OpGt:  ">",
+	filters.OpGte: ">=",
+	filters.OpLt:  "<",
+	filters.OpLte: "<=",
+}
+
+// pathPredicate returns the filter as an SQL/JSON path predicate on the
+// metadata, e.g. ($."author" == "bob" && $."year" > 2020), matched with the
+// @@ operator, which the GIN index of the metadata supports. The comparisons
+// of missing fields and of values of different types are unknown, so that
+// neither they nor their negations match.
+func pathPredicate(f filters.Filter) (string, error) {
+	if err := f.Validate(); err != nil {
+		return "", err
+	}
+	return predicate(f)
+}
+
+func predicate(f filters.Filter) (string, error) {
+	switch f.Op {
+	case filters.OpAnd, filters.OpOr:
+		sep := " && "
+		if f.Op == filters.OpOr {
+			sep = " || "
+		}
+		parts := make([]string, 0, len(f.Filters))
+		for _, filter := range f.Filters {
+			part, err := predicate(filter)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, sep) + ")", nil
+	case filters.OpNot:
+		part, err := predicate(f.Filters[0])
+		if err != nil {
+			return "", err
+		}
+		return "!(" + part + ")", nil
+	case filters.OpIn, filters.OpNin:
+		values, err := f.Values()
+		if err != nil {
+			return "", err
+		}
+		in := make([]filters.Filter, 0, len(values))
+		for _, value := range values {
+			in = append(in, filters.Eq(f.Field, value))
+		}
+		part, err := predicate(filters.Or(in...))
+		if err != nil || f.Op == filters.OpIn {
+			return part, err
+		}
+		return "!" + part, nil
+	default:
+		key, err := json.Marshal(f.Field)
+		if err != nil {
+			return "", err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return "", fmt.Errorf("marshal %s value: %w", f.Field, err)
+		}
+		return fmt.Sprintf("$.%s %s %s", key, _pathOperators[f.Op], value), nil
+	}
+}
@@ -0,0 +1,546 @@
+package pgvector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are neither a
+	// map[string]any of the metadata of the documents nor a valid
+	// filters.Filter.
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// _operators are the operators of the distances, returning the distance of
+// two vectors, and the operator classes of their indexes.
+var _operators = map[Distance]struct{ operator, opclass string }{ //nolint:gochecknoglobals
+	DistanceCosine:       {"<=>", "vector_cosine_ops"},
+	DistanceL2:           {"<->", "vector_l2_ops"},
+	DistanceInnerProduct: {"<#>", "vector_ip_ops"},
+}
+
+// Conn is a connection to the database, implemented by *pgxpool.Pool and
+// *pgx.Conn.
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Store is a wrapper around a PostgreSQL table of documents with their
+// vectors, searched with the operators of the pgvector extension.
+type Store struct {
+	embedder embeddings.Embedder
+	conn     Conn
+	// pool is the pool created from the connection URL, closed by Close.
+	pool *pgxpool.Pool
+
+	connectionURL        string
+	tableName            string
+	vectorDimensions     int
+	distance             Distance
+	indexType            IndexType
+	hnswM                int
+	hnswEfConstruction   int
+	ivfflatLists         int
+	skipSchemaManagement bool
+	preDeleteTable       bool
+	nameSpace            string
+	tenant               string
+	requireTenant        bool
+	batchSize            int
+}
+
+var (
+	_ vectorstores.VectorStore    = Store{}
+	_ vectorstores.VectorSearcher = Store{}
+	_ vectorstores.Exporter       = Store{}
+)
+
+// New creates a new Store with options. The embedder and, unless the schema
+// management is skipped, the dimensions of its vectors must be set. The
+// vector extension, the table and its indexes are created if they do not
+// exist.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if s.conn == nil {
+		s.pool, err = pgxpool.New(ctx, s.connectionURL)
+		if err != nil {
+			return Store{}, fmt.Errorf("connect: %w", err)
+		}
+		s.conn = s.pool
+	}
+
+	if !s.skipSchemaManagement {
+		for _, statement := range s.schemaStatements() {
+			if _, err := s.conn.Exec(ctx, statement); err != nil {
+				s.Close()
+				return Store{}, fmt.Errorf("create schema: %w", err)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Close closes the pool created from the connection URL. A connection set
+// with WithConn is left open.
+func (s Store) Close() {
+	if s.pool != nil {
+		s.pool.Close()
+	}
+}
+
+// DropTable drops the table of the documents.
+func (s Store) DropTable(ctx context.Context) error {
+	_, err := s.conn.Exec(ctx, "DROP TABLE IF EXISTS "+s.table())
+	return err
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and upserts them into the table in batches, one round trip per batch. The
+// IDs of the documents are UUIDs.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	// The documents of another tenant with the same IDs are left unchanged.
+	upsert := fmt.Sprintf(`INSERT INTO %s (id, tenant, namespace, content, metadata, embedding)
+VALUES ($1, $2, $3, $4, $5::jsonb, $6::vector)
+ON CONFLICT (id) DO UPDATE SET namespace = EXCLUDED.namespace, content = EXCLUDED.content,
+	metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding
+	WHERE %s.tenant = EXCLUDED.tenant`,
+		s.table(), s.table(),
+	)
+	for start := 0; start < len(docs); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		batch := &pgx.Batch{}
+		for i := start; i < end; i++ {
+			metadata, err := marshalMetadata(docs[i].Metadata)
+			if err != nil {
+				return nil, err
+			}
+			batch.Queue(upsert, ids[i], tenant, nameSpace, texts[i], metadata, vectorLiteral(vectors[i]))
+		}
+		if err := s.sendBatch(ctx, batch); err != nil {
+			return nil, fmt.Errorf("insert documents %d to %d: %w", start, end, err)
+		}
+	}
+
+	return ids, nil
+}
+
+// Delete deletes the documents of the name space and the tenant with the
+// IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE namespace = $1 AND tenant = $2 AND id = ANY($3::uuid[])", s.table()),
+		s.getNameSpace(opts), tenant, ids)
+	return err
+}
+
+// DeleteByFilter deletes the documents of the name space and the tenant
+// matching the filter, a map[string]any or a filters.Filter as in
+// SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return err
+	}
+	condition, arg, err := s.getFilter(opts, 3)
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE namespace = $1 AND tenant = $2 AND %s", s.table(), condition),
+		s.getNameSpace(opts), tenant, arg)
+	return err
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a
+// map[string]any matched against the metadata of the documents with the
+// containment operator @> of JSONB, e.g. map[string]any{"country": "France"},
+// or a filters.Filter matched as an SQL/JSON path predicate with @@. The
+// scores of the documents are 1 - the cosine distance, the inner product or
+// 1 / (1 + the Euclidean distance).
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SimilaritySearchByVector(ctx, vector, numDocuments, options...)
+}
+
+// SimilaritySearchByVector returns the most similar documents to the vector,
+// as SimilaritySearch. The documents of the offset of the options are
+// skipped with OFFSET.
+func (s Store) SimilaritySearchByVector(ctx context.Context, vector []float64, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	nameSpace := s.getNameSpace(opts)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, filter, err := s.getFilter(opts, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{nameSpace, tenant, vectorLiteral(vector), fetchK}
+	if condition != "" {
+		args = append(args, filter)
+	}
+	rows, err := s.conn.Query(ctx, s.searchSQL(condition, opts.Offset), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, fetchK)
+	for rows.Next() {
+		var doc schema.Document
+		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &doc.Score); err != nil {
+			return nil, err
+		}
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && doc.Score < scoreThreshold {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// schemaStatements returns the statements creating the vector extension,
+// the table and its indexes.
+func (s Store) schemaStatements() []string {
+	statements := []string{"CREATE EXTENSION IF NOT EXISTS vector"}
+	if s.preDeleteTable {
+		statements = append(statements, "DROP TABLE IF EXISTS "+s.table())
+	}
+	statements = append(statements,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id uuid PRIMARY KEY,
+	tenant text NOT NULL DEFAULT '',
+	namespace text NOT NULL DEFAULT '',
+	content text NOT NULL,
+	metadata jsonb NOT NULL DEFAULT '{}',
+	embedding vector(%d) NOT NULL
+)`, s.table(), s.vectorDimensions),
+		// The tables created without tenants get the column of the tenants.
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT ''", s.table()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (namespace)", s.index("namespace"), s.table()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (tenant, namespace)", s.index("tenant"), s.table()),
+		// The jsonb_path_ops operator class indexes the containment queries
+		// of the filters.
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (metadata jsonb_path_ops)",
+			s.index("metadata"), s.table()),
+	)
+
+	var parameters []string
+	switch s.indexType {
+	case IndexNone:
+		return statements
+	case IndexHNSW:
+		if s.hnswM > 0 {
+			parameters = append(parameters, "m = "+strconv.Itoa(s.hnswM))
+		}
+		if s.hnswEfConstruction > 0 {
+			parameters = append(parameters, "ef_construction = "+strconv.Itoa(s.hnswEfConstruction))
+		}
+	case IndexIVFFlat:
+		if s.ivfflatLists > 0 {
+			parameters = append(parameters, "lists = "+strconv.Itoa(s.ivfflatLists))
+		}
+	}
+	index := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING %s (embedding %s)",
+		s.index("embedding"), s.table(), s.indexType, _operators[s.distance].opclass)
+	if len(parameters) > 0 {
+		index += " WITH (" + strings.Join(parameters, ", ") + ")"
+	}
+	return append(statements, index)
+}
+
+// searchSQL returns the query of the nearest documents of the name space $1
+// and the tenant $2 to the vector $3, at most $4, matching the condition on
+// the filter $5 if any, after the first offset documents. The documents are
+// ordered by distance, so that the index is used, and their scores are the
+// similarities of the vectors: the cosine similarity, the inner product, or
+// 1 / (1 + the Euclidean distance).
+func (s Store) searchSQL(condition string, offset int) string {
+	distance := fmt.Sprintf("embedding %s $3::vector", _operators[s.distance].operator)
+	var score string
+	switch s.distance {
+	case DistanceCosine:
+		score = fmt.Sprintf("1 - (%s)", distance)
+	case DistanceInnerProduct:
+		score = fmt.Sprintf("(%s) * -1", distance)
+	case DistanceL2:
+		score = fmt.Sprintf("1 / (1 + (%s))", distance)
+	}
+
+	where := "namespace = $1 AND tenant = $2"
+	if condition != "" {
+		where += " AND " + condition
+	}
+	sql := fmt.Sprintf("SELECT content, metadata, %s AS score FROM %s WHERE %s ORDER BY %s LIMIT $4",
+		score, s.table(), where, distance)
+	if offset > 0 {
+		sql += " OFFSET " + strconv.Itoa(offset)
+	}
+	return sql
+}
+
+func (s Store) sendBatch(ctx context.Context, batch *pgx.Batch) error {
+	results := s.conn.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return err
+		}
+	}
+	return results.Close()
+}
+
+func (s Store) table() string {
+	return pgx.Identifier{s.tableName}.Sanitize()
+}
+
+func (s Store) index(column string) string {
+	return pgx.Identifier{s.tableName + "_" + column + "_idx"}.Sanitize()
+}
+
+// Export writes the records of the documents of the name space and the tenant
+// of the options, with their vectors, to the writer, one JSON object per
+// line, see vectorstores.Import.
+func (s Store) Export(ctx context.Context, w io.Writer, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.conn.Query(ctx,
+		fmt.Sprintf("SELECT id::text, content, metadata, embedding::text FROM %s "+
+			"WHERE namespace = $1 AND tenant = $2 ORDER BY id", s.table()),
+		s.getNameSpace(opts), tenant)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	enc := json.NewEncoder(w)
+	for rows.Next() {
+		var record vectorstores.Record
+		var vector string
+		if err := rows.Scan(&record.ID, &record.Content, &record.Metadata, &vector); err != nil {
+			return err
+		}
+		record.Vector, err = parseVectorLiteral(vector)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	if opts.NameSpace != "" {
+		return opts.NameSpace
+	}
+	return s.nameSpace
+}
+
+// getTenant returns the tenant of the call, the tenant of the store by
+// default, the documents without a tenant having the empty tenant.
+func (s Store) getTenant(opts vectorstores.Options) (string, error) {
+	return vectorstores.ResolveTenant(opts, s.tenant, s.requireTenant)
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+// getFilter returns the condition of the filters on the metadata and the
+// argument of its parameter, a JSON object or an SQL/JSON path predicate,
+// empty if not set.
+func (s Store) getFilter(opts vectorstores.Options, param int) (string, string, error) {
+	switch filter := opts.Filters.(type) {
+	case nil:
+		return "", "", nil
+	case map[string]any:
+		object, err := marshalMetadata(filter)
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("metadata @> $%d::jsonb", param), object, nil
+	case filters.Filter:
+		predicate, err := pathPredicate(filter)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+		return fmt.Sprintf("metadata @@ $%d::jsonpath", param), predicate, nil
+	default:
+		return "", "", fmt.Errorf("%w: map[string]any or filters.Filter required, got %T",
+			ErrInvalidFilter, opts.Filters)
+	}
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+// marshalMetadata returns the metadata as a JSON object.
+func marshalMetadata(metadata map[string]any) (string, error) {
+	if metadata == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("marshal metadata: %w", err)
+	}
+	return string(b), nil
+}
+
+// vectorLiteral returns the vector in the text format of pgvector, e.g.
+// [1,2.5,3].
+func vectorLiteral(vector []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// parseVectorLiteral returns the vector of the text format of pgvector.
+func parseVectorLiteral(literal string) ([]float64, error) {
+	trimmed := strings.TrimSpace(literal)
+	if !strings.HasPrefix(trimmed, "[") || !strings.HasSuffix(trimmed, "]") {
+		return nil, fmt.Errorf("parse vector %q", literal)
+	}
+	trimmed = trimmed[1 : len(trimmed)-1]
+	if trimmed == "" {
+		return []float64{}, nil
+	}
+	fields := strings.Split(trimmed, ",")
+	vector := make([]float64, 0, len(fields))
+	for _, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse vector %q: %w", literal, err)
+		}
+		vector = append(vector, v)
+	}
+	return vector, nil
+}
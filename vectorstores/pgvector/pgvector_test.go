@@ -0,0 +1,320 @@
+package pgvector
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings/bm25"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// recordingConn records the statements and the batches sent to the database.
+type recordingConn struct {
+	statements []string
+	batches    []*pgx.Batch
+}
+
+func (c *recordingConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.statements = append(c.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *recordingConn) Query(_ context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (c *recordingConn) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	c.batches = append(c.batches, b)
+	return batchResults{}
+}
+
+type batchResults struct{ pgx.BatchResults }
+
+func (batchResults) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, nil }
+func (batchResults) Close() error                     { return nil }
+
+func TestPgvectorStoreSchema(t *testing.T) {
+	t.Parallel()
+
+	conn := &recordingConn{}
+	_, err := New(context.Background(),
+		WithConn(conn),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithTableName("docs"),
+		WithVectorDimensions(3),
+		WithDistance(DistanceInnerProduct),
+		WithHNSWIndex(32, 0),
+		WithPreDeleteTable(),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, conn.statements, 8)
+	assert.Equal(t, "CREATE EXTENSION IF NOT EXISTS vector", conn.statements[0])
+	assert.Equal(t, `DROP TABLE IF EXISTS "docs"`, conn.statements[1])
+	assert.Contains(t, conn.statements[2], `CREATE TABLE IF NOT EXISTS "docs"`)
+	assert.Contains(t, conn.statements[2], "embedding vector(3) NOT NULL")
+	assert.Equal(t, `ALTER TABLE "docs" ADD COLUMN IF NOT EXISTS tenant text NOT NULL DEFAULT ''`, conn.statements[3])
+	assert.Equal(t, `CREATE INDEX IF NOT EXISTS "docs_tenant_idx" ON "docs" (tenant, namespace)`, conn.statements[5])
+	assert.Equal(t, `CREATE INDEX IF NOT EXISTS "docs_metadata_idx" ON "docs" USING gin (metadata jsonb_path_ops)`,
+		conn.statements[6])
+	assert.Equal(t, `CREATE INDEX IF NOT EXISTS "docs_embedding_idx" ON "docs" USING hnsw (embedding vector_ip_ops) WITH (m = 32)`, //nolint:lll
+		conn.statements[7])
+
+	// The schema management can be skipped, without the dimensions.
+	conn = &recordingConn{}
+	_, err = New(context.Background(), WithConn(conn), WithEmbedder(testutil.WordCountEmbedder{}), WithSkipSchemaManagement())
+	require.NoError(t, err)
+	assert.Empty(t, conn.statements)
+
+	_, err = New(context.Background(), WithConn(conn), WithEmbedder(testutil.WordCountEmbedder{}))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), WithConn(conn), WithEmbedder(testutil.WordCountEmbedder{}), WithVectorDimensions(3),
+		WithDistance("hamming"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestPgvectorStoreBatches(t *testing.T) {
+	t.Parallel()
+
+	conn := &recordingConn{}
+	store, err := New(context.Background(),
+		WithConn(conn),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithSkipSchemaManagement(),
+		WithBatchSize(2),
+	)
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "a cat", Metadata: map[string]any{"animal": true}},
+		{PageContent: "a dog"},
+		{PageContent: "a car"},
+	}, vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	assert.Len(t, ids, 3)
+
+	require.Len(t, conn.batches, 2)
+	assert.Equal(t, 2, conn.batches[0].Len())
+	assert.Equal(t, 1, conn.batches[1].Len())
+
+	_, err = store.AddDocuments(context.Background(), nil, vectorstores.WithSparseEmbedder(bm25.New()))
+	require.ErrorIs(t, err, vectorstores.ErrSparseNotSupported)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "a cat"}},
+		vectorstores.WithIDs("1", "2"))
+	require.ErrorIs(t, err, vectorstores.ErrWrongNumberIDs)
+
+	require.NoError(t, store.Delete(context.Background(), ids[:1]))
+	require.NoError(t, store.DeleteByFilter(context.Background(), filters.Eq("animal", true)))
+	assert.Equal(t, []string{
+		`DELETE FROM "langchain_documents" WHERE namespace = $1 AND tenant = $2 AND id = ANY($3::uuid[])`,
+		`DELETE FROM "langchain_documents" WHERE namespace = $1 AND tenant = $2 AND metadata @@ $3::jsonpath`,
+	}, conn.statements)
+	require.ErrorIs(t, store.DeleteByFilter(context.Background(), nil), vectorstores.ErrMissingFilter)
+}
+
+func TestPgvectorStoreTenant(t *testing.T) {
+	t.Parallel()
+
+	conn := &recordingConn{}
+	store, err := New(context.Background(),
+		WithConn(conn),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithSkipSchemaManagement(),
+		WithRequireTenant(),
+	)
+	require.NoError(t, err)
+
+	// The calls without a tenant fail rather than reaching the documents
+	// without a tenant.
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "a cat"}})
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	_, err = store.SimilaritySearch(context.Background(), "cat", 1)
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	require.ErrorIs(t, store.Delete(context.Background(), []string{uuid.NewString()}), vectorstores.ErrMissingTenant)
+	require.ErrorIs(t, store.DeleteByFilter(context.Background(), filters.Eq("animal", true)),
+		vectorstores.ErrMissingTenant)
+	assert.Empty(t, conn.batches)
+	assert.Empty(t, conn.statements)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "a cat"}},
+		vectorstores.WithTenant("acme"))
+	require.NoError(t, err)
+	require.Len(t, conn.batches, 1)
+	require.NoError(t, store.Delete(context.Background(), []string{uuid.NewString()}, vectorstores.WithTenant("acme")))
+	assert.Len(t, conn.statements, 1)
+}
+
+func TestPgvectorEncoding(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "[1.1,0,-2.5e-07]", vectorLiteral([]float64{1.1, 0, -0.00000025}))
+	assert.Equal(t, "[]", vectorLiteral(nil))
+
+	vector, err := parseVectorLiteral("[1.1,0,-2.5e-07]")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.1, 0, -2.5e-07}, vector)
+	vector, err = parseVectorLiteral("[]")
+	require.NoError(t, err)
+	assert.Empty(t, vector)
+	_, err = parseVectorLiteral("1,2")
+	require.Error(t, err)
+	_, err = parseVectorLiteral("[1,a]")
+	require.Error(t, err)
+
+	metadata, err := marshalMetadata(map[string]any{"animal": true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"animal":true}`, metadata)
+	metadata, err = marshalMetadata(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", metadata)
+}
+
+func TestPgvectorSearchSQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		distance  Distance
+		condition string
+		offset    int
+		want      string
+	}{
+		{
+			distance: DistanceCosine,
+			want: `SELECT content, metadata, 1 - (embedding <=> $3::vector) AS score FROM "docs" ` +
+				`WHERE namespace = $1 AND tenant = $2 ORDER BY embedding <=> $3::vector LIMIT $4`,
+		},
+		{
+			distance:  DistanceL2,
+			condition: "metadata @> $5::jsonb",
+			want: `SELECT content, metadata, 1 / (1 + (embedding <-> $3::vector)) AS score FROM "docs" ` +
+				`WHERE namespace = $1 AND tenant = $2 AND metadata @> $5::jsonb ORDER BY embedding <-> $3::vector LIMIT $4`,
+		},
+		{
+			distance: DistanceInnerProduct,
+			want: `SELECT content, metadata, (embedding <#> $3::vector) * -1 AS score FROM "docs" ` +
+				`WHERE namespace = $1 AND tenant = $2 ORDER BY embedding <#> $3::vector LIMIT $4`,
+		},
+		{
+			distance: DistanceCosine,
+			offset:   20,
+			want: `SELECT content, metadata, 1 - (embedding <=> $3::vector) AS score FROM "docs" ` +
+				`WHERE namespace = $1 AND tenant = $2 ORDER BY embedding <=> $3::vector LIMIT $4 OFFSET 20`,
+		},
+	}
+	for _, tt := range tests {
+		s := Store{tableName: "docs", distance: tt.distance}
+		assert.Equal(t, tt.want, s.searchSQL(tt.condition, tt.offset))
+	}
+
+	_, _, err := Store{}.getFilter(vectorstores.Options{Filters: "country = 'France'"}, 4)
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestPgvectorFilter(t *testing.T) {
+	t.Parallel()
+
+	condition, filter, err := Store{}.getFilter(vectorstores.Options{
+		Filters: map[string]any{"kind": "animal"},
+	}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "metadata @> $4::jsonb", condition)
+	assert.Equal(t, `{"kind":"animal"}`, filter)
+
+	condition, filter, err = Store{}.getFilter(vectorstores.Options{
+		Filters: filters.Eq("author", "bob").And(
+			filters.Gt("year", 2020),
+			filters.Not(filters.In("tag", "a", `b"c`)),
+		),
+	}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "metadata @@ $4::jsonpath", condition)
+	assert.Equal(t, `($."author" == "bob" && $."year" > 2020 && !(($."tag" == "a" || $."tag" == "b\"c")))`,
+		filter)
+
+	_, _, err = Store{}.getFilter(vectorstores.Options{Filters: filters.Eq("", 1)}, 4)
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestPgvectorStore(t *testing.T) {
+	t.Parallel()
+
+	connectionURL := os.Getenv(_pgvectorEnvVarName)
+	if connectionURL == "" {
+		t.Skip("Must set PGVECTOR_CONNECTION_STRING to run test")
+	}
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithConnectionURL(connectionURL),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithTableName("test_"+strings.ReplaceAll(uuid.New().String(), "-", "")),
+		WithVectorDimensions(3),
+		WithHNSWIndex(0, 0),
+	)
+	require.NoError(t, err)
+	defer store.Close()
+	defer func() {
+		require.NoError(t, store.DropTable(ctx))
+	}()
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The dog barks.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The car is red.", Metadata: map[string]any{"kind": "vehicle"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	docs, err := store.SimilaritySearch(ctx, "cats", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"kind": "animal"}, docs[0].Metadata)
+
+	docs, err = store.SimilaritySearch(ctx, "cars", 3,
+		vectorstores.WithFilters(map[string]any{"kind": "animal"}))
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	docs, err = store.SimilaritySearch(ctx, "dogs", 3, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(ctx, "cats", 3, vectorstores.WithNameSpace("other"))
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The car is blue.", Metadata: map[string]any{"kind": "vehicle"}},
+	}, vectorstores.WithIDs(ids[2]))
+	require.NoError(t, err)
+	docs, err = store.SimilaritySearch(ctx, "cars", 3, vectorstores.WithFilters(filters.Eq("kind", "vehicle")))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is blue.", docs[0].PageContent)
+
+	var export bytes.Buffer
+	require.NoError(t, store.Export(ctx, &export))
+	assert.Equal(t, 3, strings.Count(export.String(), "\n"))
+
+	require.NoError(t, store.Delete(ctx, ids[:1]))
+	require.NoError(t, store.DeleteByFilter(ctx, filters.Eq("kind", "vehicle")))
+	docs, err = store.SimilaritySearch(ctx, "cats", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+}
@@ -0,0 +1,203 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	_controlPlaneURL = "https://api.pinecone.io"
+	// _apiVersion is the version of the API of the requests.
+	_apiVersion = "2024-07"
+	// _readyPollInterval is the interval of the descriptions of an index
+	// created until it is ready.
+	_readyPollInterval = time.Second
+)
+
+// indexDescription is the description of an index by the control plane.
+type indexDescription struct {
+	Name      string `json:"name"`
+	Dimension int    `json:"dimension"`
+	Metric    string `json:"metric"`
+	Host      string `json:"host"`
+	Status    struct {
+		Ready bool   `json:"ready"`
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+type serverlessSpec struct {
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+}
+
+type createIndexPayload struct {
+	Name      string `json:"name"`
+	Dimension int    `json:"dimension"`
+	Metric    string `json:"metric"`
+	Spec      struct {
+		Serverless serverlessSpec `json:"serverless"`
+	} `json:"spec"`
+}
+
+// CreateIndex creates the serverless index in the cloud and the region of
+// WithServerless if it does not exist, with the dimensions of WithDimensions
+// and the metric of WithMetric, and waits until it is ready.
+func (s Store) CreateIndex(ctx context.Context) error {
+	_, err := s.describeIndex(ctx)
+	if err == nil {
+		return s.waitReady(ctx)
+	}
+	if !errors.Is(err, vectorstores.ErrIndexNotFound) {
+		return err
+	}
+	if s.cloud == "" || s.region == "" || s.dimensions <= 0 {
+		return fmt.Errorf("%w: the cloud, the region and the dimensions of the index are required to create it",
+			ErrInvalidOptions)
+	}
+
+	payload := createIndexPayload{Name: s.indexName, Dimension: s.dimensions, Metric: s.metric}
+	payload.Spec.Serverless = serverlessSpec{Cloud: s.cloud, Region: s.region}
+	body, status, err := s.doRequest(ctx, payload, _controlPlaneURL+"/indexes", s.apiKey, http.MethodPost)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	// The index may be created concurrently.
+	if status != http.StatusCreated && status != http.StatusConflict {
+		return newAPIError("creating index", body)
+	}
+	return s.waitReady(ctx)
+}
+
+// DeleteIndex deletes the index with its vectors.
+func (s Store) DeleteIndex(ctx context.Context) error {
+	body, status, err := s.doRequest(ctx, nil, s.indexURL(), s.apiKey, http.MethodDelete)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	switch status {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.indexName)
+	default:
+		return newAPIError("deleting index", body)
+	}
+}
+
+// DescribeIndex returns the name of the index with its dimensions and its
+// metric.
+func (s Store) DescribeIndex(ctx context.Context) (vectorstores.IndexInfo, error) {
+	description, err := s.describeIndex(ctx)
+	if err != nil {
+		return vectorstores.IndexInfo{}, err
+	}
+	return vectorstores.IndexInfo{
+		Name:       description.Name,
+		Dimensions: description.Dimension,
+		Metric:     description.Metric,
+	}, nil
+}
+
+// Stats returns the number of vectors of the index, in all its namespaces.
+func (s Store) Stats(ctx context.Context) (vectorstores.IndexStats, error) {
+	body, status, err := s.doRequest(ctx, struct{}{}, s.endpoint()+"/describe_index_stats", s.apiKey, http.MethodPost)
+	if err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	defer body.Close()
+	if status == http.StatusNotFound {
+		return vectorstores.IndexStats{}, fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.indexName)
+	}
+	if status != http.StatusOK {
+		return vectorstores.IndexStats{}, newAPIError("describing index stats", body)
+	}
+	var res struct {
+		TotalVectorCount int `json:"totalVectorCount"`
+	}
+	if err := json.NewDecoder(body).Decode(&res); err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	return vectorstores.IndexStats{NumDocuments: res.TotalVectorCount}, nil
+}
+
+// describeIndex returns the description of the index by the control plane,
+// or vectorstores.ErrIndexNotFound if it does not exist.
+func (s Store) describeIndex(ctx context.Context) (indexDescription, error) {
+	body, status, err := s.doRequest(ctx, nil, s.indexURL(), s.apiKey, http.MethodGet)
+	if err != nil {
+		return indexDescription{}, err
+	}
+	defer body.Close()
+	if status == http.StatusNotFound {
+		return indexDescription{}, fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, s.indexName)
+	}
+	if status != http.StatusOK {
+		return indexDescription{}, newAPIError("describing index", body)
+	}
+	var description indexDescription
+	if err := json.NewDecoder(body).Decode(&description); err != nil {
+		return indexDescription{}, err
+	}
+	return description, nil
+}
+
+// waitReady describes the index until it is ready to be queried.
+func (s Store) waitReady(ctx context.Context) error {
+	for {
+		description, err := s.describeIndex(ctx)
+		if err != nil {
+			return err
+		}
+		if description.Status.Ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(_readyPollInterval):
+		}
+	}
+}
+
+// resolveHost returns the data plane host of the index described by the
+// control plane, unless the host or the environment and the project name of
+// the legacy pod-based indexes are set.
+func (s Store) resolveHost(ctx context.Context) (string, error) {
+	if s.host != "" || (s.environment != "" && s.projectName != "") {
+		return s.host, nil
+	}
+	description, err := s.describeIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+	return description.Host, nil
+}
+
+// indexURL returns the URL of the index in the control plane.
+func (s Store) indexURL() string {
+	return _controlPlaneURL + "/indexes/" + url.PathEscape(s.indexName)
+}
+
+// endpoint returns the URL of the data plane of the index, the URL of its
+// host, or the URL of the environment and the project of the legacy
+// pod-based indexes.
+func (s Store) endpoint() string {
+	if s.host != "" {
+		if strings.Contains(s.host, "://") {
+			return strings.TrimSuffix(s.host, "/")
+		}
+		return "https://" + s.host
+	}
+	return getEndpoint(s.indexName, s.projectName, s.environment)
+}
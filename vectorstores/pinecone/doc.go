@@ -1,3 +1,6 @@
 // Package pinecone contains an implementation of the vectorStore
-// interface using pinecone.
+// interface using pinecone. The store uses the serverless indexes of the
+// current API, reached by the host of the index and created with
+// WithServerless, and the legacy pod-based indexes reached by their
+// environment and project name.
 package pinecone
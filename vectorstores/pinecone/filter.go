@@ -0,0 +1,32 @@
+package pinecone
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// metadataFilter returns the filter as a metadata filter of Pinecone, e.g.
+// {"$and": [{"author": {"$eq": "bob"}}, {"year": {"$gt": 2020}}]}. Pinecone
+// has no $not operator, so the negations are pushed down to the comparisons.
+func metadataFilter(f filters.Filter) (map[string]any, error) {
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	return translate(f), nil
+}
+
+func translate(f filters.Filter) map[string]any {
+	switch f.Op {
+	case filters.OpAnd, filters.OpOr:
+		operands := make([]any, 0, len(f.Filters))
+		for _, filter := range f.Filters {
+			operands = append(operands, translate(filter))
+		}
+		return map[string]any{"$" + string(f.Op): operands}
+	case filters.OpNot:
+		return translate(f.Filters[0].Negate())
+	default:
+		return map[string]any{f.Field: map[string]any{"$" + string(f.Op): f.Value}}
+	}
+}
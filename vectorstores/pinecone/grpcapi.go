@@ -5,7 +5,6 @@ import (
 	"crypto/tls"
 	"fmt"
 
-	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/pinecone_grpc"
 	"github.com/tmc/langchaingo/schema"
 	"google.golang.org/grpc"
@@ -25,6 +24,9 @@ func (s Store) getGRPCConn(ctx context.Context) (*grpc.ClientConn, error) {
 		s.projectName,
 		s.environment,
 	)
+	if s.host != "" {
+		target = s.host + ":443"
+	}
 
 	ctx = metadata.AppendToOutgoingContext(ctx, "api-key", s.apiKey)
 
@@ -44,6 +46,7 @@ func (s Store) getGRPCConn(ctx context.Context) (*grpc.ClientConn, error) {
 
 func (s Store) grpcUpsert(
 	ctx context.Context,
+	ids []string,
 	vectors [][]float64,
 	metadatas []map[string]any,
 	nameSpace string,
@@ -58,7 +61,7 @@ func (s Store) grpcUpsert(
 		pineconeVectors = append(
 			pineconeVectors,
 			&pinecone_grpc.Vector{
-				Id:       uuid.New().String(),
+				Id:       ids[i],
 				Values:   float64ToFloat32(vectors[i]),
 				Metadata: metadataStruct,
 			},
@@ -78,6 +81,7 @@ func (s Store) grpcQuery(
 	vector []float64,
 	numDocs int,
 	nameSpace string,
+	scoreThreshold float64,
 ) ([]schema.Document, error) {
 	queryResult, err := s.client.Query(
 		ctx,
@@ -108,9 +112,15 @@ func (s Store) grpcQuery(
 		}
 		delete(metadata, s.textKey)
 
+		score := float64(match.Score)
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && score < scoreThreshold {
+			continue
+		}
 		resultDocuments = append(resultDocuments, schema.Document{
 			PageContent: pageContent,
 			Metadata:    metadata,
+			Score:       score,
 		})
 	}
 
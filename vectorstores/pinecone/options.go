@@ -3,14 +3,17 @@ package pinecone
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/tmc/langchaingo/embeddings"
 )
 
 const (
-	_pineconeEnvVrName = "PINECONE_API_KEY"
-	_defaultTextKey    = "text"
+	_pineconeEnvVrName      = "PINECONE_API_KEY"
+	_defaultTextKey         = "text"
+	_defaultMetric          = "cosine"
+	_defaultUpsertBatchSize = 100
 )
 
 // ErrInvalidOptions is returned when the options given are invalid.
@@ -26,22 +29,69 @@ func WithIndexName(name string) Option {
 	}
 }
 
-// WithEnvironment is an option for specifying the environment. Must be set.
+// WithEnvironment is an option for specifying the environment of a legacy
+// pod-based index, with WithProjectName.
 func WithEnvironment(environment string) Option {
 	return func(p *Store) {
 		p.environment = environment
 	}
 }
 
-// WithProjectName is an option for specifying the project name. Must be set. The
-// project name associated with the api key can be obtained using the whoami
-// operation.
+// WithProjectName is an option for specifying the project name of a legacy
+// pod-based index, with WithEnvironment. The project name associated with the
+// api key can be obtained using the whoami operation.
 func WithProjectName(name string) Option {
 	return func(p *Store) {
 		p.projectName = name
 	}
 }
 
+// WithHost is an option for setting the data plane host of the index, e.g.
+// my-index-abc123.svc.aped-4627-b74a.pinecone.io, shown in the console. If
+// not set, the host is described by the control plane when the store is
+// created.
+func WithHost(host string) Option {
+	return func(p *Store) {
+		p.host = host
+	}
+}
+
+// WithServerless is an option for creating the serverless index in the cloud
+// and the region, e.g. aws and us-east-1, when the store is created if it
+// does not exist. The dimensions of the vectors must be set with
+// WithDimensions.
+func WithServerless(cloud, region string) Option {
+	return func(p *Store) {
+		p.cloud = cloud
+		p.region = region
+	}
+}
+
+// WithDimensions is an option for setting the dimensions of the vectors of the
+// index created with WithServerless.
+func WithDimensions(dimensions int) Option {
+	return func(p *Store) {
+		p.dimensions = dimensions
+	}
+}
+
+// WithMetric is an option for setting the metric of the index created with
+// WithServerless: cosine, the default, euclidean or dotproduct, required by
+// the hybrid searches.
+func WithMetric(metric string) Option {
+	return func(p *Store) {
+		p.metric = metric
+	}
+}
+
+// WithUpsertBatchSize is an option for setting the number of vectors upserted
+// per request, 100 by default, within the limits of the size of the requests.
+func WithUpsertBatchSize(size int) Option {
+	return func(p *Store) {
+		p.upsertBatchSize = size
+	}
+}
+
 // WithEmbedder is an option for setting the embedder to use. Must be set.
 func WithEmbedder(e embeddings.Embedder) Option {
 	return func(p *Store) {
@@ -49,6 +99,16 @@ func WithEmbedder(e embeddings.Embedder) Option {
 	}
 }
 
+// WithSparseEmbedder is an option for setting the sparse embedder of the
+// sparse vectors upserted and queried with the dense vectors, for hybrid
+// searches. The index must use the dotproduct metric. The sparse embedder can
+// also be set per call with vectorstores.WithSparseEmbedder.
+func WithSparseEmbedder(e embeddings.SparseEmbedder) Option {
+	return func(p *Store) {
+		p.sparseEmbedder = e
+	}
+}
+
 // WithAPIKey is an option for setting the api key. If the option is not set
 // the api key is read from the PINECONE_API_KEY environment variable. If the
 // variable is not present, an error will be returned.
@@ -74,6 +134,32 @@ func WithNameSpace(nameSpace string) Option {
 	}
 }
 
+// WithTenant is an option for setting the tenant of the vectors upserted
+// and queried, mapped to the Pinecone namespaces of the tenant. The tenant of
+// a call can also be set with vectorstores.WithTenant.
+func WithTenant(tenant string) Option {
+	return func(p *Store) {
+		p.tenant = tenant
+	}
+}
+
+// WithRequireTenant is an option for failing the calls without a tenant with
+// vectorstores.ErrMissingTenant, rather than reaching the namespaces shared by
+// the tenants.
+func WithRequireTenant() Option {
+	return func(p *Store) {
+		p.requireTenant = true
+	}
+}
+
+// WithHTTPClient is an option for setting the HTTP client of the rest api.
+// If not set, http.DefaultClient is used.
+func WithHTTPClient(client Doer) Option {
+	return func(p *Store) {
+		p.httpClient = client
+	}
+}
+
 // withGrpc is an option for using the grpc api instead of the rest api.
 func withGrpc() Option { // nolint: unused
 	return func(p *Store) {
@@ -83,7 +169,10 @@ func withGrpc() Option { // nolint: unused
 
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
-		textKey: _defaultTextKey,
+		textKey:         _defaultTextKey,
+		httpClient:      http.DefaultClient,
+		metric:          _defaultMetric,
+		upsertBatchSize: _defaultUpsertBatchSize,
 	}
 
 	for _, opt := range opts {
@@ -94,12 +183,13 @@ func applyClientOptions(opts ...Option) (Store, error) {
 		return Store{}, fmt.Errorf("%w: missing index name", ErrInvalidOptions)
 	}
 
-	if o.environment == "" {
-		return Store{}, fmt.Errorf("%w: missing environment", ErrInvalidOptions)
+	if (o.environment == "") != (o.projectName == "") {
+		return Store{}, fmt.Errorf("%w: the environment and the project name must be set together",
+			ErrInvalidOptions)
 	}
 
-	if o.projectName == "" {
-		return Store{}, fmt.Errorf("%w: missing project name", ErrInvalidOptions)
+	if o.upsertBatchSize <= 0 {
+		return Store{}, fmt.Errorf("%w: upsert batch size must be positive", ErrInvalidOptions)
 	}
 
 	if o.embedder == nil {
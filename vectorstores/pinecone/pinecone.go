@@ -3,11 +3,14 @@ package pinecone
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/pinecone-io/go-pinecone/pinecone_grpc"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
 	"google.golang.org/grpc"
 )
 
@@ -24,13 +27,18 @@ var (
 	ErrEmptyResponse         = errors.New("empty response")
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
+	ErrInvalidAlpha = errors.New("alpha must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are a filters.Filter
+	// that is not valid.
+	ErrInvalidFilter = errors.New("invalid filter")
 )
 
 // Store is a wrapper around the pinecone rest API and grpc client.
 type Store struct {
-	embedder embeddings.Embedder
-	grpcConn *grpc.ClientConn
-	client   pinecone_grpc.VectorServiceClient
+	embedder       embeddings.Embedder
+	sparseEmbedder embeddings.SparseEmbedder
+	grpcConn       *grpc.ClientConn
+	client         pinecone_grpc.VectorServiceClient
 
 	indexName   string
 	projectName string
@@ -38,19 +46,55 @@ type Store struct {
 	apiKey      string
 	textKey     string
 	nameSpace   string
+	tenant      string
 	useGRPC     bool
+	httpClient  Doer
+	// requireTenant fails the calls without a tenant.
+	requireTenant bool
+
+	// host is the data plane host of the index, described by the control
+	// plane unless set or the index is a legacy pod-based index.
+	host string
+	// cloud and region are the location of the serverless index created.
+	cloud           string
+	region          string
+	metric          string
+	dimensions      int
+	upsertBatchSize int
 }
 
-var _ vectorstores.VectorStore = Store{}
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+var (
+	_ vectorstores.VectorStore    = Store{}
+	_ vectorstores.HybridSearcher = Store{}
+	_ vectorstores.IndexManager   = Store{}
+)
 
-// New creates a new Store with options. Options for index name, environment, project name
-// and embedder must be set.
+// New creates a new Store with options. The index name and the embedder must
+// be set. The data plane host of a serverless index is described by the
+// control plane unless set with WithHost, and the index is created if it does
+// not exist with WithServerless. The legacy pod-based indexes are reached with
+// WithEnvironment and WithProjectName.
 func New(ctx context.Context, opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
 	if err != nil {
 		return Store{}, err
 	}
 
+	if s.cloud != "" {
+		if err := s.CreateIndex(ctx); err != nil {
+			return Store{}, err
+		}
+	}
+	s.host, err = s.resolveHost(ctx)
+	if err != nil {
+		return Store{}, err
+	}
+
 	if s.useGRPC {
 		conn, err := s.getGRPCConn(ctx)
 		if err != nil {
@@ -66,24 +110,47 @@ func New(ctx context.Context, opts ...Option) (Store, error) {
 }
 
 // AddDocuments creates vector embeddings from the documents using the embedder
-// and upsert the vectors to the pinecone index.
-func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+// and upsert the vectors to the pinecone index, in batches of the size of
+// WithUpsertBatchSize.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
 	opts := s.getOptions(options...)
 
-	nameSpace := s.getNameSpace(opts)
+	nameSpace, err := s.getNameSpace(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
 
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
 	}
 
-	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(vectors) != len(docs) {
-		return ErrEmbedderWrongNumberVectors
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	var sparseVectors []embeddings.SparseVector
+	if sparseEmbedder := s.getSparseEmbedder(opts); sparseEmbedder != nil {
+		if s.useGRPC {
+			return nil, fmt.Errorf("%w: grpc api", vectorstores.ErrSparseNotSupported)
+		}
+		sparseVectors, err = sparseEmbedder.EmbedDocuments(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(sparseVectors) != len(docs) {
+			return nil, ErrEmbedderWrongNumberVectors
+		}
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -97,38 +164,194 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 		metadatas = append(metadatas, metadata)
 	}
 
+	for start := 0; start < len(docs); start += s.upsertBatchSize {
+		end := start + s.upsertBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		if s.useGRPC {
+			err = s.grpcUpsert(ctx, ids[start:end], vectors[start:end], metadatas[start:end], nameSpace)
+		} else {
+			var batchSparseVectors []embeddings.SparseVector
+			if sparseVectors != nil {
+				batchSparseVectors = sparseVectors[start:end]
+			}
+			err = s.restUpsert(ctx, ids[start:end], vectors[start:end], batchSparseVectors,
+				metadatas[start:end], nameSpace)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// Delete deletes the vectors of the name space with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	nameSpace, err := s.getNameSpace(s.getOptions(options...))
+	if err != nil {
+		return err
+	}
 	if s.useGRPC {
-		return s.grpcUpsert(ctx, vectors, metadatas, nameSpace)
+		_, err := s.client.Delete(ctx, &pinecone_grpc.DeleteRequest{Ids: ids, Namespace: nameSpace})
+		return err
 	}
+	return s.restDelete(ctx, deletePayload{IDs: ids, Namespace: nameSpace})
+}
+
+// DeleteByFilter deletes the vectors of the name space matching the filter,
+// a metadata filter of Pinecone or a filters.Filter as in SimilaritySearch.
+// The delete requests of the grpc api have no metadata filter, the vectors are
+// deleted through the rest api whichever api the store uses.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
 
-	return s.restUpsert(ctx, vectors, metadatas, nameSpace)
+	nameSpace, err := s.getNameSpace(opts)
+	if err != nil {
+		return err
+	}
+	metadataFilter, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+	return s.restDelete(ctx, deletePayload{Filter: metadataFilter, Namespace: nameSpace})
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents. With a sparse embedder, the
+// query is a hybrid query of the dense and sparse vectors, weighted with
+// vectorstores.WithAlpha. Without a sparse embedder, the alpha is ignored.
+// The scores of the documents are the scores of the metric of the index.
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
 	opts := s.getOptions(options...)
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
 
-	nameSpace := s.getNameSpace(opts)
+	nameSpace, err := s.getNameSpace(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	filters := s.getFilters(opts)
+	filter, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	vector, err := s.embedder.EmbedQuery(ctx, query)
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
+	var sparseVector *embeddings.SparseVector
+	if sparseEmbedder := s.getSparseEmbedder(opts); sparseEmbedder != nil {
+		if s.useGRPC {
+			return nil, fmt.Errorf("%w: grpc api", vectorstores.ErrSparseNotSupported)
+		}
+		v, err := sparseEmbedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		sparseVector = &v
+	}
+
+	if opts.Alpha != nil {
+		if *opts.Alpha < 0 || *opts.Alpha > 1 {
+			return nil, ErrInvalidAlpha
+		}
+		if sparseVector != nil {
+			vector, sparseVector = weightHybrid(vector, *sparseVector, *opts.Alpha)
+		}
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []schema.Document
 	if s.useGRPC {
-		return s.grpcQuery(ctx, vector, numDocuments, nameSpace)
+		docs, err = s.grpcQuery(ctx, vector, fetchK, nameSpace, scoreThreshold)
+	} else {
+		docs, err = s.restQuery(ctx, vector, sparseVector, fetchK, nameSpace, scoreThreshold,
+			filter)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
 
-	return s.restQuery(ctx, vector, numDocuments, nameSpace, scoreThreshold,
-		filters)
+// HybridSearch runs a hybrid query of the dense vector of the query and of
+// its sparse vector, embedded from its text with the embedders of the store
+// unless set. The scores of the documents are the convex combination of the
+// dense and sparse scores weighted by alpha, so that the fusion is weighted by
+// default and reciprocal rank fusion is not supported. The options are those
+// of SimilaritySearch, with the REST API only.
+func (s Store) HybridSearch(ctx context.Context, query vectorstores.HybridQuery, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if query.Fusion == vectorstores.FusionRRF {
+		return nil, fmt.Errorf("%w: %s", vectorstores.ErrFusionNotSupported, query.Fusion)
+	}
+	if s.useGRPC {
+		return nil, fmt.Errorf("%w: grpc api", vectorstores.ErrSparseNotSupported)
+	}
+	opts := s.getOptions(options...)
+
+	nameSpace, err := s.getNameSpace(opts)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := query.Vector
+	if vector == nil {
+		vector, err = s.getEmbedder(opts).EmbedQuery(ctx, query.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sparseVector := query.Sparse
+	if sparseVector == nil {
+		sparseEmbedder := s.getSparseEmbedder(opts)
+		if sparseEmbedder == nil {
+			return nil, fmt.Errorf("%w: sparse vector or sparse embedder required", vectorstores.ErrInvalidHybridQuery)
+		}
+		v, err := sparseEmbedder.EmbedQuery(ctx, query.Text)
+		if err != nil {
+			return nil, err
+		}
+		sparseVector = &v
+	}
+	weighted, weightedSparse := weightHybrid(vector, *sparseVector, query.Alpha)
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.restQuery(ctx, weighted, weightedSparse, fetchK, nameSpace, scoreThreshold, filter)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
 }
 
 // Close closes the grpc connection.
@@ -136,11 +359,18 @@ func (s Store) Close() error {
 	return s.grpcConn.Close()
 }
 
-func (s Store) getNameSpace(opts vectorstores.Options) string {
+// getNameSpace returns the Pinecone namespace of the call, the name space of
+// the tenant if any, see vectorstores.TenantNameSpace.
+func (s Store) getNameSpace(opts vectorstores.Options) (string, error) {
+	tenant, err := vectorstores.ResolveTenant(opts, s.tenant, s.requireTenant)
+	if err != nil {
+		return "", err
+	}
+	nameSpace := s.nameSpace
 	if opts.NameSpace != "" {
-		return opts.NameSpace
+		nameSpace = opts.NameSpace
 	}
-	return s.nameSpace
+	return vectorstores.TenantNameSpace(tenant, nameSpace), nil
 }
 
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float64,
@@ -152,12 +382,49 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float64,
 	return opts.ScoreThreshold, nil
 }
 
-func (s Store) getFilters(opts vectorstores.Options) any {
-	if opts.Filters != nil {
-		return opts.Filters
+// getFilters returns the filters as a metadata filter of Pinecone, passing
+// the filters other than a filters.Filter as they are.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if filter, ok := opts.Filters.(filters.Filter); ok {
+		return metadataFilter(filter)
+	}
+	return opts.Filters, nil
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
 	}
+	return s.embedder
+}
+
+func (s Store) getSparseEmbedder(opts vectorstores.Options) embeddings.SparseEmbedder {
+	if opts.SparseEmbedder != nil {
+		return opts.SparseEmbedder
+	}
+	return s.sparseEmbedder
+}
 
-	return nil
+// weightHybrid scales the dense vector by alpha and the sparse vector by
+// 1 - alpha, so that the scores of the hybrid query are the convex
+// combination of the dense and sparse scores.
+func weightHybrid(
+	vector []float64,
+	sparseVector embeddings.SparseVector,
+	alpha float64,
+) ([]float64, *embeddings.SparseVector) {
+	weighted := make([]float64, len(vector))
+	for i, v := range vector {
+		weighted[i] = v * alpha
+	}
+	weightedSparse := &embeddings.SparseVector{
+		Indices: sparseVector.Indices,
+		Values:  make([]float64, len(sparseVector.Values)),
+	}
+	for i, v := range sparseVector.Values {
+		weightedSparse.Values[i] = v * (1 - alpha)
+	}
+	return weighted, weightedSparse
 }
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
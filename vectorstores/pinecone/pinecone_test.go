@@ -1,18 +1,26 @@
 package pinecone_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/embeddings"
 	openaiEmbeddings "github.com/tmc/langchaingo/embeddings/openai"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
 	"github.com/tmc/langchaingo/vectorstores/pinecone"
 )
 
@@ -65,7 +73,7 @@ func getValues(t *testing.T) (string, string, string, string) {
 	)
 	require.NoError(t, err)
 
-	err = storer.AddDocuments(context.Background(), []schema.Document{
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "yes"},
 		{PageContent: "no"},
 	})
@@ -95,7 +103,7 @@ func TestPineconeStoreRest(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	err = storer.AddDocuments(context.Background(), []schema.Document{
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "tokyo"},
 		{PageContent: "potato"},
 	})
@@ -125,7 +133,7 @@ func TestPineconeStoreRestWithScoreThreshold(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	err = storer.AddDocuments(context.Background(), []schema.Document{
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "Tokyo"},
 		{PageContent: "Yokohama"},
 		{PageContent: "Osaka"},
@@ -172,7 +180,7 @@ func TestSimilaritySearchWithInvalidScoreThreshold(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	err = storer.AddDocuments(context.Background(), []schema.Document{
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "Tokyo"},
 		{PageContent: "Yokohama"},
 		{PageContent: "Osaka"},
@@ -216,7 +224,7 @@ func TestPineconeAsRetriever(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{PageContent: "The color of the house is blue."},
@@ -261,7 +269,7 @@ func TestPineconeAsRetrieverWithScoreThreshold(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{PageContent: "The color of the house is blue."},
@@ -312,7 +320,7 @@ func TestPineconeAsRetrieverWithMetadataFilterEqualsClause(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -391,7 +399,7 @@ func TestPineconeAsRetrieverWithMetadataFilterInClause(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -471,7 +479,7 @@ func TestPineconeAsRetrieverWithMetadataFilterNotSelected(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -549,7 +557,7 @@ func TestPineconeAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	id := uuid.New().String()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -609,3 +617,357 @@ func TestPineconeAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	require.Contains(t, result, "purple", "expected black in purple")
 }
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = []float64{1, 0}
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(_ context.Context, _ string) ([]float64, error) {
+	return []float64{0.5, 0.5}, nil
+}
+
+type fakeSparseEmbedder struct{}
+
+func (fakeSparseEmbedder) EmbedDocuments(_ context.Context, texts []string) ([]embeddings.SparseVector, error) {
+	vectors := make([]embeddings.SparseVector, len(texts))
+	for i := range texts {
+		vectors[i] = embeddings.SparseVector{Indices: []int{7}, Values: []float64{2}}
+	}
+	// An empty text has no terms.
+	vectors[len(texts)-1] = embeddings.SparseVector{}
+	return vectors, nil
+}
+
+func (fakeSparseEmbedder) EmbedQuery(_ context.Context, _ string) (embeddings.SparseVector, error) {
+	return embeddings.SparseVector{Indices: []int{7}, Values: []float64{1}}, nil
+}
+
+// recordingDoer records the payloads of the requests and replies to the
+// queries with a match.
+type recordingDoer struct {
+	payloads map[string]map[string]any
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	var payload map[string]any
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	d.payloads[req.URL.Path] = payload
+	body := `{"matches":[{"id":"1","score":0.9,"metadata":{"text":"foo"}}]}`
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func TestPineconeStoreRestHybrid(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{payloads: map[string]map[string]any{}}
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey("key"),
+		pinecone.WithEnvironment("env"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithProjectName("project"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithSparseEmbedder(fakeSparseEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+	)
+	require.NoError(t, err)
+
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "foo"},
+		{PageContent: ""},
+	})
+	require.NoError(t, err)
+	vectors, ok := doer.payloads["/vectors/upsert"]["vectors"].([]any)
+	require.True(t, ok)
+	require.Len(t, vectors, 2)
+	assert.Equal(t, map[string]any{"indices": []any{7.0}, "values": []any{2.0}},
+		vectors[0].(map[string]any)["sparseValues"])
+	assert.NotContains(t, vectors[1], "sparseValues")
+
+	docs, err := storer.SimilaritySearch(context.Background(), "foo", 1, vectorstores.WithAlpha(0.8))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "foo", docs[0].PageContent)
+	assert.InDelta(t, 0.9, docs[0].Score, 1e-9)
+	query := doer.payloads["/query"]
+	assert.InDeltaSlice(t, []any{0.4, 0.4}, query["vector"], 1e-9)
+	sparse, ok := query["sparseVector"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{7.0}, sparse["indices"])
+	assert.InDeltaSlice(t, []any{0.2}, sparse["values"], 1e-9)
+
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1, vectorstores.WithAlpha(2))
+	require.ErrorIs(t, err, pinecone.ErrInvalidAlpha)
+}
+
+func TestPineconeStoreRestAlphaWithoutSparse(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{payloads: map[string]map[string]any{}}
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey("key"),
+		pinecone.WithEnvironment("env"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithProjectName("project"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+	)
+	require.NoError(t, err)
+
+	// Without a sparse vector the dense vector is not scaled by alpha.
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1, vectorstores.WithAlpha(0))
+	require.NoError(t, err)
+	query := doer.payloads["/query"]
+	assert.InDeltaSlice(t, []any{0.5, 0.5}, query["vector"], 1e-9)
+	assert.NotContains(t, query, "sparseVector")
+}
+
+func TestPineconeStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{payloads: map[string]map[string]any{}}
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey("key"),
+		pinecone.WithEnvironment("env"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithProjectName("project"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+	)
+	require.NoError(t, err)
+
+	// The vectors of the query are weighted by alpha.
+	docs, err := vectorstores.HybridSearch(context.Background(), storer, vectorstores.HybridQuery{
+		Vector: []float64{1, 0},
+		Sparse: &embeddings.SparseVector{Indices: []int{3}, Values: []float64{1}},
+		Alpha:  0.25,
+	}, 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	query := doer.payloads["/query"]
+	assert.InDeltaSlice(t, []any{0.25, 0.0}, query["vector"], 1e-9)
+	sparse, ok := query["sparseVector"].(map[string]any)
+	require.True(t, ok)
+	assert.InDeltaSlice(t, []any{0.75}, sparse["values"], 1e-9)
+
+	_, err = storer.HybridSearch(context.Background(), vectorstores.HybridQuery{Text: "foo"}, 1)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridQuery)
+	_, err = storer.HybridSearch(context.Background(), vectorstores.HybridQuery{
+		Text:   "foo",
+		Fusion: vectorstores.FusionRRF,
+	}, 1, vectorstores.WithSparseEmbedder(fakeSparseEmbedder{}))
+	require.ErrorIs(t, err, vectorstores.ErrFusionNotSupported)
+}
+
+func TestPineconeStoreRestFilter(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{payloads: map[string]map[string]any{}}
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey("key"),
+		pinecone.WithEnvironment("env"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithProjectName("project"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+	)
+	require.NoError(t, err)
+
+	filter := filters.Eq("author", "bob").And(filters.Not(filters.Gt("year", 2020).Or(filters.In("tag", "a"))))
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1, vectorstores.WithFilters(filter))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$and": []any{
+		map[string]any{"author": map[string]any{"$eq": "bob"}},
+		map[string]any{"$and": []any{
+			map[string]any{"year": map[string]any{"$lte": 2020.0}},
+			map[string]any{"tag": map[string]any{"$nin": []any{"a"}}},
+		}},
+	}}, doer.payloads["/query"]["filter"])
+
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1,
+		vectorstores.WithFilters(filters.Eq("author", []string{"bob"})))
+	require.ErrorIs(t, err, pinecone.ErrInvalidFilter)
+}
+
+func TestPineconeStoreTenant(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{payloads: map[string]map[string]any{}}
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey("key"),
+		pinecone.WithEnvironment("env"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithProjectName("project"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+		pinecone.WithNameSpace("docs"),
+		pinecone.WithRequireTenant(),
+	)
+	require.NoError(t, err)
+
+	// The calls without a tenant fail rather than reaching the shared
+	// namespaces.
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{{PageContent: "foo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1)
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	require.ErrorIs(t, storer.Delete(context.Background(), []string{"1"}), vectorstores.ErrMissingTenant)
+	assert.Empty(t, doer.payloads)
+
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{{PageContent: "foo"}},
+		vectorstores.WithTenant("acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme/docs", doer.payloads["/vectors/upsert"]["namespace"])
+
+	_, err = storer.SimilaritySearch(context.Background(), "foo", 1,
+		vectorstores.WithTenant("acme"), vectorstores.WithNameSpace("notes"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme/notes", doer.payloads["/query"]["namespace"])
+}
+
+// serverlessDoer replies to the requests of the control plane and of the data
+// plane of a serverless index.
+type serverlessDoer struct {
+	index   map[string]any
+	creates []map[string]any
+	upserts []int
+	hosts   []string
+	headers []string
+}
+
+func (d *serverlessDoer) Do(req *http.Request) (*http.Response, error) {
+	d.headers = append(d.headers, req.Header.Get("X-Pinecone-API-Version"))
+	var payload map[string]any
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+	}
+
+	status, body := http.StatusNotFound, `{"error":"not found"}`
+	switch {
+	case req.URL.Host == "api.pinecone.io" && req.URL.Path == "/indexes" && req.Method == http.MethodPost:
+		d.creates = append(d.creates, payload)
+		d.index = map[string]any{
+			"name":      payload["name"],
+			"dimension": payload["dimension"],
+			"metric":    payload["metric"],
+			"host":      "index-abc.svc.pinecone.io",
+			"status":    map[string]any{"ready": true, "state": "Ready"},
+		}
+		status, body = http.StatusCreated, "{}"
+	case req.URL.Host == "api.pinecone.io" && req.URL.Path == "/indexes/index" && d.index != nil:
+		if req.Method == http.MethodDelete {
+			d.index = nil
+			status, body = http.StatusAccepted, ""
+			break
+		}
+		b, err := json.Marshal(d.index)
+		if err != nil {
+			return nil, err
+		}
+		status, body = http.StatusOK, string(b)
+	case req.URL.Path == "/vectors/upsert":
+		d.hosts = append(d.hosts, req.URL.Host)
+		vectors, _ := payload["vectors"].([]any)
+		d.upserts = append(d.upserts, len(vectors))
+		status, body = http.StatusOK, "{}"
+	case req.URL.Path == "/describe_index_stats":
+		total := 0
+		for _, n := range d.upserts {
+			total += n
+		}
+		status, body = http.StatusOK, fmt.Sprintf(`{"totalVectorCount":%d}`, total)
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestPineconeStoreServerless(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	doer := &serverlessDoer{}
+	storer, err := pinecone.New(ctx,
+		pinecone.WithAPIKey("key"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+		pinecone.WithServerless("aws", "us-east-1"),
+		pinecone.WithDimensions(2),
+		pinecone.WithMetric("dotproduct"),
+		pinecone.WithUpsertBatchSize(2),
+	)
+	require.NoError(t, err)
+	require.Len(t, doer.creates, 1)
+	assert.Equal(t, map[string]any{
+		"name":      "index",
+		"dimension": 2.0,
+		"metric":    "dotproduct",
+		"spec":      map[string]any{"serverless": map[string]any{"cloud": "aws", "region": "us-east-1"}},
+	}, doer.creates[0])
+
+	// The vectors are upserted in batches to the host of the index.
+	_, err = storer.AddDocuments(ctx, []schema.Document{
+		{PageContent: "foo"}, {PageContent: "bar"}, {PageContent: "baz"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, doer.upserts)
+	assert.Equal(t, []string{"index-abc.svc.pinecone.io", "index-abc.svc.pinecone.io"}, doer.hosts)
+
+	info, err := storer.DescribeIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, vectorstores.IndexInfo{Name: "index", Dimensions: 2, Metric: "dotproduct"}, info)
+	stats, err := storer.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.NumDocuments)
+
+	// The index is not created again.
+	_, err = pinecone.New(ctx,
+		pinecone.WithAPIKey("key"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+		pinecone.WithServerless("aws", "us-east-1"),
+		pinecone.WithDimensions(2),
+	)
+	require.NoError(t, err)
+	assert.Len(t, doer.creates, 1)
+	for _, header := range doer.headers {
+		assert.NotEmpty(t, header)
+	}
+
+	require.NoError(t, storer.DeleteIndex(ctx))
+	_, err = storer.DescribeIndex(ctx)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+
+	// Without the host, the index must exist.
+	_, err = pinecone.New(ctx,
+		pinecone.WithAPIKey("key"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+	)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+
+	// The index is not created without its dimensions.
+	_, err = pinecone.New(ctx,
+		pinecone.WithAPIKey("key"),
+		pinecone.WithIndexName("index"),
+		pinecone.WithEmbedder(fakeEmbedder{}),
+		pinecone.WithHTTPClient(doer),
+		pinecone.WithServerless("aws", "us-east-1"),
+	)
+	require.ErrorIs(t, err, pinecone.ErrInvalidOptions)
+}
@@ -9,7 +9,7 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -35,9 +35,10 @@ func (e APIError) Error() string {
 }
 
 type vector struct {
-	Values   []float64      `json:"values"`
-	Metadata map[string]any `json:"metadata"`
-	ID       string         `json:"id"`
+	Values       []float64      `json:"values"`
+	SparseValues *sparseValues  `json:"sparseValues,omitempty"`
+	Metadata     map[string]any `json:"metadata"`
+	ID           string         `json:"id"`
 }
 
 type upsertPayload struct {
@@ -47,7 +48,9 @@ type upsertPayload struct {
 
 func (s Store) restUpsert(
 	ctx context.Context,
+	ids []string,
 	vectors [][]float64,
+	sparseVectors []embeddings.SparseVector,
 	metadatas []map[string]any,
 	nameSpace string,
 ) error {
@@ -56,8 +59,11 @@ func (s Store) restUpsert(
 		v = append(v, vector{
 			Values:   vectors[i],
 			Metadata: metadatas[i],
-			ID:       uuid.New().String(),
+			ID:       ids[i],
 		})
+		if sparseVectors != nil {
+			v[i].SparseValues = newSparseValues(sparseVectors[i])
+		}
 	}
 
 	payload := upsertPayload{
@@ -65,10 +71,10 @@ func (s Store) restUpsert(
 		Namespace: nameSpace,
 	}
 
-	body, status, err := doRequest(
+	body, status, err := s.doRequest(
 		ctx,
 		payload,
-		getEndpoint(s.indexName, s.projectName, s.environment)+"/vectors/upsert",
+		s.endpoint()+"/vectors/upsert",
 		s.apiKey,
 		http.MethodPost,
 	)
@@ -84,11 +90,46 @@ func (s Store) restUpsert(
 	return newAPIError("upserting vectors", body)
 }
 
+type deletePayload struct {
+	IDs       []string `json:"ids,omitempty"`
+	Filter    any      `json:"filter,omitempty"`
+	Namespace string   `json:"namespace"`
+}
+
+func (s Store) restDelete(ctx context.Context, payload deletePayload) error {
+	body, status, err := s.doRequest(
+		ctx,
+		payload,
+		s.endpoint()+"/vectors/delete",
+		s.apiKey,
+		http.MethodPost,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if status == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError("deleting vectors", body)
+}
+
 type sparseValues struct {
 	Indices []int     `json:"indices"`
 	Values  []float64 `json:"values"`
 }
 
+// newSparseValues returns the sparse values of the vector, nil if the vector
+// is empty as the index rejects empty sparse values.
+func newSparseValues(v embeddings.SparseVector) *sparseValues {
+	if len(v.Indices) == 0 {
+		return nil
+	}
+	return &sparseValues{Indices: v.Indices, Values: v.Values}
+}
+
 type match struct {
 	ID           string         `json:"id"`
 	Score        float64        `json:"score"`
@@ -103,17 +144,19 @@ type queriesResponse struct {
 }
 
 type queryPayload struct {
-	IncludeValues   bool      `json:"includeValues"`
-	IncludeMetadata bool      `json:"includeMetadata"`
-	Vector          []float64 `json:"vector"`
-	TopK            int       `json:"topK"`
-	Namespace       string    `json:"namespace"`
-	Filter          any       `json:"filter"`
+	IncludeValues   bool          `json:"includeValues"`
+	IncludeMetadata bool          `json:"includeMetadata"`
+	Vector          []float64     `json:"vector"`
+	SparseVector    *sparseValues `json:"sparseVector,omitempty"`
+	TopK            int           `json:"topK"`
+	Namespace       string        `json:"namespace"`
+	Filter          any           `json:"filter,omitempty"`
 }
 
 func (s Store) restQuery(
 	ctx context.Context,
 	vector []float64,
+	sparseVector *embeddings.SparseVector,
 	numVectors int,
 	nameSpace string,
 	scoreThreshold float64,
@@ -127,11 +170,14 @@ func (s Store) restQuery(
 		Namespace:       nameSpace,
 		Filter:          filter,
 	}
+	if sparseVector != nil {
+		payload.SparseVector = newSparseValues(*sparseVector)
+	}
 
-	body, statusCode, err := doRequest(
+	body, statusCode, err := s.doRequest(
 		ctx,
 		payload,
-		getEndpoint(s.indexName, s.projectName, s.environment)+"/query",
+		s.endpoint()+"/query",
 		s.apiKey,
 		http.MethodPost,
 	)
@@ -167,6 +213,7 @@ func (s Store) restQuery(
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    match.Metadata,
+			Score:       match.Score,
 		}
 
 		// If scoreThreshold is not 0, we only return matches with a score above the threshold.
@@ -180,23 +227,31 @@ func (s Store) restQuery(
 	return docs, nil
 }
 
-func doRequest(ctx context.Context, payload any, url, apiKey, method string) (io.ReadCloser, int, error) {
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, 0, err
+// doRequest sends the request with the payload as JSON, if any, and returns
+// the body and the status code of the response.
+func (s Store) doRequest(ctx context.Context, payload any, url, apiKey, method string) (io.ReadCloser, int, error) {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, 0, err
+		}
+		body = bytes.NewReader(payloadBytes)
 	}
-	body := bytes.NewReader(payloadBytes)
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("accept", "text/plain")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("accept", "application/json")
 	req.Header.Set("Api-Key", apiKey)
+	req.Header.Set("X-Pinecone-API-Version", _apiVersion)
 
-	r, err := http.DefaultClient.Do(req)
+	r, err := s.httpClient.Do(req)
 	if err != nil {
 		return nil, 0, err
 	}
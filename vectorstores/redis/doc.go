@@ -0,0 +1,3 @@
+// Package redis contains an implementation of the vectorStore
+// interface using the vector similarity search of Redis Stack.
+package redis
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Filter is a RediSearch query expression filtering the documents by their
+// tag and numeric metadata fields, e.g. Tag("country", "France"). Pass it to
+// vectorstores.WithFilters, as well as a raw expression string.
+type Filter string
+
+// Tag returns the filter of the documents whose tag field holds one of the
+// values.
+func Tag(field string, values ...string) Filter {
+	escaped := make([]string, 0, len(values))
+	for _, value := range values {
+		escaped = append(escaped, escapeTag(value))
+	}
+	return Filter("@" + field + ":{" + strings.Join(escaped, " | ") + "}")
+}
+
+// Numeric returns the filter of the documents whose numeric field is between
+// min and max, inclusive. Use math.Inf for an open range.
+func Numeric(field string, min, max float64) Filter {
+	return Filter("@" + field + ":[" + formatNumber(min) + " " + formatNumber(max) + "]")
+}
+
+// And returns the filter of the documents matching all the filters.
+func And(filters ...Filter) Filter {
+	return join(filters, " ")
+}
+
+// Or returns the filter of the documents matching any of the filters.
+func Or(filters ...Filter) Filter {
+	return join(filters, " | ")
+}
+
+// Not returns the filter of the documents not matching the filter.
+func Not(filter Filter) Filter {
+	return "-(" + filter + ")"
+}
+
+func join(filters []Filter, sep string) Filter {
+	parts := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		parts = append(parts, string(filter))
+	}
+	return Filter("(" + strings.Join(parts, sep) + ")")
+}
+
+// escapeTag escapes the punctuation and the spaces of a tag value, which
+// RediSearch otherwise reads as separators.
+func escapeTag(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func formatNumber(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_redisURLEnvVarName = "REDIS_URL"
+	_defaultBatchSize   = 1000
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Algorithm is the algorithm of the vector index.
+type Algorithm string
+
+const (
+	// AlgorithmFlat is the exact search, for small data sets.
+	AlgorithmFlat Algorithm = "FLAT"
+	// AlgorithmHNSW is the approximate search of an HNSW graph.
+	AlgorithmHNSW Algorithm = "HNSW"
+)
+
+// DistanceMetric is the metric of the distance of the vectors.
+type DistanceMetric string
+
+const (
+	// DistanceCosine is the cosine distance.
+	DistanceCosine DistanceMetric = "COSINE"
+	// DistanceL2 is the squared Euclidean distance.
+	DistanceL2 DistanceMetric = "L2"
+	// DistanceIP is the inner product distance.
+	DistanceIP DistanceMetric = "IP"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithClient is an option for setting the Redis client. If not set, a client
+// is created from the Redis URL.
+func WithClient(client redis.UniversalClient) Option {
+	return func(p *Store) {
+		p.client = client
+	}
+}
+
+// WithURL is an option for setting the Redis URL, e.g.
+// redis://localhost:6379. If not set, the URL is read from the REDIS_URL
+// environment variable.
+func WithURL(url string) Option {
+	return func(p *Store) {
+		p.url = url
+	}
+}
+
+// WithIndexName is an option for specifying the name of the index. Must be
+// set.
+func WithIndexName(name string) Option {
+	return func(p *Store) {
+		p.indexName = name
+	}
+}
+
+// WithPrefix is an option for setting the prefix of the keys of the hashes
+// of the documents, indexed by the index. "doc:<index name>:" by default.
+func WithPrefix(prefix string) Option {
+	return func(p *Store) {
+		p.prefix = prefix
+	}
+}
+
+// WithNameSpace is an option for setting the default name space of the
+// documents, overridden by vectorstores.WithNameSpace. The documents of a name
+// space are indexed by the index <index name>:<name space>, the keys of their
+// hashes prefixed with <name space>:<prefix>.
+func WithNameSpace(nameSpace string) Option {
+	return func(p *Store) {
+		p.nameSpace = nameSpace
+	}
+}
+
+// WithVectorDimensions is an option for specifying the number of dimensions
+// of the vectors of the embedder. Must be set to create the index.
+func WithVectorDimensions(dimensions int) Option {
+	return func(p *Store) {
+		p.vectorDimensions = dimensions
+	}
+}
+
+// WithFlatIndex is an option for creating a FLAT index, searched
+// exhaustively.
+func WithFlatIndex() Option {
+	return func(p *Store) {
+		p.algorithm = AlgorithmFlat
+	}
+}
+
+// WithHNSWIndex is an option for creating an HNSW index with the maximum
+// number of edges per node m and the size of the candidate list
+// efConstruction, the defaults of Redis, 16 and 200, if zero. The default.
+func WithHNSWIndex(m, efConstruction int) Option {
+	return func(p *Store) {
+		p.algorithm = AlgorithmHNSW
+		p.hnswM = m
+		p.hnswEfConstruction = efConstruction
+	}
+}
+
+// WithDistanceMetric is an option for specifying the distance metric of the
+// index, the cosine distance by default.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(p *Store) {
+		p.distanceMetric = metric
+	}
+}
+
+// WithTagFields is an option for indexing the metadata fields as tags,
+// filtered with Tag. The values of the fields are strings or slices of
+// strings.
+func WithTagFields(fields ...string) Option {
+	return func(p *Store) {
+		p.tagFields = fields
+	}
+}
+
+// WithNumericFields is an option for indexing the metadata fields as
+// numbers, filtered with Numeric.
+func WithNumericFields(fields ...string) Option {
+	return func(p *Store) {
+		p.numericFields = fields
+	}
+}
+
+// WithSkipIndexCreation is an option for using an existing index without
+// creating it.
+func WithSkipIndexCreation() Option {
+	return func(p *Store) {
+		p.skipIndexCreation = true
+	}
+}
+
+// WithBatchSize is an option for specifying the number of documents written
+// per pipeline, 1000 by default.
+func WithBatchSize(batchSize int) Option {
+	return func(p *Store) {
+		p.batchSize = batchSize
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	o := &Store{
+		algorithm:      AlgorithmHNSW,
+		distanceMetric: DistanceCosine,
+		batchSize:      _defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.indexName == "" {
+		return Store{}, fmt.Errorf("%w: missing index name", ErrInvalidOptions)
+	}
+
+	if o.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if !o.skipIndexCreation && o.vectorDimensions <= 0 {
+		return Store{}, fmt.Errorf("%w: missing vector dimensions", ErrInvalidOptions)
+	}
+
+	switch o.distanceMetric {
+	case DistanceCosine, DistanceL2, DistanceIP:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown distance metric %q", ErrInvalidOptions, o.distanceMetric)
+	}
+
+	if o.batchSize <= 0 {
+		return Store{}, fmt.Errorf("%w: batch size must be positive", ErrInvalidOptions)
+	}
+
+	if o.prefix == "" {
+		o.prefix = "doc:" + o.indexName + ":"
+	}
+
+	if o.client == nil {
+		if o.url == "" {
+			o.url = os.Getenv(_redisURLEnvVarName)
+		}
+		if o.url == "" {
+			return Store{}, fmt.Errorf(
+				"%w: missing redis url. Pass it as an option or set the %s environment variable",
+				ErrInvalidOptions,
+				_redisURLEnvVarName,
+			)
+		}
+		redisOpts, err := redis.ParseURL(o.url)
+		if err != nil {
+			return Store{}, fmt.Errorf("%w: %w", ErrInvalidOptions, err)
+		}
+		o.client = redis.NewClient(redisOpts)
+		o.ownClient = true
+	}
+
+	return *o, nil
+}
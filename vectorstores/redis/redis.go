@@ -0,0 +1,656 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	_contentField  = "content"
+	_metadataField = "metadata"
+	_vectorField   = "content_vector"
+	_scoreField    = "vector_score"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are not a Filter or a
+	// query expression string.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrInvalidResponse is returned when the reply of a search cannot be
+	// parsed.
+	ErrInvalidResponse = errors.New("invalid response")
+)
+
+// Store is a wrapper around a RediSearch index of the hashes of the
+// documents, holding their text, their metadata as JSON, their vector and
+// their indexed metadata fields.
+type Store struct {
+	embedder embeddings.Embedder
+	client   redis.UniversalClient
+	// ownClient is set when the client is created from the URL, closed by
+	// Close.
+	ownClient bool
+
+	url                string
+	indexName          string
+	prefix             string
+	nameSpace          string
+	vectorDimensions   int
+	algorithm          Algorithm
+	hnswM              int
+	hnswEfConstruction int
+	distanceMetric     DistanceMetric
+	tagFields          []string
+	numericFields      []string
+	skipIndexCreation  bool
+	batchSize          int
+}
+
+var (
+	_ vectorstores.VectorStore    = Store{}
+	_ vectorstores.VectorSearcher = Store{}
+	_ vectorstores.IndexManager   = Store{}
+)
+
+// New creates a new Store with options. The index name, the embedder and,
+// unless the index creation is skipped, the dimensions of its vectors must be
+// set. The index is created if it does not exist.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if !s.skipIndexCreation {
+		if err := s.createIndexIfNotExists(ctx, s.nameSpace); err != nil {
+			s.Close()
+			return Store{}, err
+		}
+	}
+
+	return s, nil
+}
+
+// Close closes the client created from the Redis URL. A client set with
+// WithClient is left open.
+func (s Store) Close() error {
+	if s.ownClient {
+		return s.client.Close()
+	}
+	return nil
+}
+
+// DropIndex drops the index of the name space of the options, and the hashes
+// of its documents if deleteDocuments is set.
+func (s Store) DropIndex(ctx context.Context, deleteDocuments bool, options ...vectorstores.Option) error {
+	indexName, _ := s.index(s.getNameSpace(s.getOptions(options...)))
+	args := []any{"FT.DROPINDEX", indexName}
+	if deleteDocuments {
+		args = append(args, "DD")
+	}
+	return s.client.Do(ctx, args...).Err()
+}
+
+// CreateIndex creates the index of the default name space if it does not
+// exist.
+func (s Store) CreateIndex(ctx context.Context) error {
+	return s.createIndexIfNotExists(ctx, s.nameSpace)
+}
+
+// DeleteIndex drops the index of the default name space and the hashes of
+// its documents.
+func (s Store) DeleteIndex(ctx context.Context) error {
+	return s.DropIndex(ctx, true)
+}
+
+// DescribeIndex returns the name of the index of the default name space with
+// the dimensions and the distance metric of its vectors, those of the
+// options if the version of RediSearch does not report them.
+func (s Store) DescribeIndex(ctx context.Context) (vectorstores.IndexInfo, error) {
+	indexName, _ := s.index(s.nameSpace)
+	fields, err := s.indexInfo(ctx, indexName)
+	if err != nil {
+		return vectorstores.IndexInfo{}, err
+	}
+	info := vectorstores.IndexInfo{Name: indexName, Dimensions: s.vectorDimensions, Metric: string(s.distanceMetric)}
+	attributes, _ := fields["attributes"].([]any)
+	for _, attribute := range attributes {
+		attributeFields := infoFields(attribute)
+		if fmt.Sprint(attributeFields["identifier"]) != _vectorField {
+			continue
+		}
+		if dim, err := strconv.Atoi(fmt.Sprint(attributeFields["dim"])); err == nil {
+			info.Dimensions = dim
+		}
+		if metric, ok := attributeFields["distance_metric"]; ok {
+			info.Metric = fmt.Sprint(metric)
+		}
+	}
+	return info, nil
+}
+
+// Stats returns the number of documents of the index of the default name
+// space.
+func (s Store) Stats(ctx context.Context) (vectorstores.IndexStats, error) {
+	indexName, _ := s.index(s.nameSpace)
+	fields, err := s.indexInfo(ctx, indexName)
+	if err != nil {
+		return vectorstores.IndexStats{}, err
+	}
+	numDocs, err := strconv.ParseFloat(fmt.Sprint(fields["num_docs"]), 64)
+	if err != nil {
+		return vectorstores.IndexStats{}, fmt.Errorf("%w: number of documents %v", ErrInvalidResponse,
+			fields["num_docs"])
+	}
+	return vectorstores.IndexStats{NumDocuments: int(numDocs)}, nil
+}
+
+// indexInfo returns the fields of the reply of FT.INFO for the index.
+func (s Store) indexInfo(ctx context.Context, indexName string) (map[string]any, error) {
+	res, err := s.client.Do(ctx, "FT.INFO", indexName).Result()
+	if isUnknownIndex(err) {
+		return nil, fmt.Errorf("%w: %s", vectorstores.ErrIndexNotFound, indexName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return infoFields(res), nil
+}
+
+// infoFields returns the fields of a reply of FT.INFO, or of one of its
+// attributes: an array of names and values with RESP2, a map with RESP3.
+func infoFields(reply any) map[string]any {
+	fields := map[string]any{}
+	switch reply := reply.(type) {
+	case []any:
+		for i := 0; i+1 < len(reply); i += 2 {
+			fields[strings.ToLower(fmt.Sprint(reply[i]))] = reply[i+1]
+		}
+	case map[any]any:
+		for name, value := range reply {
+			fields[strings.ToLower(fmt.Sprint(name))] = value
+		}
+	}
+	return fields
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and writes their hashes with pipelines of batches of documents. The hashes
+// of the documents with the same IDs are replaced. The index of the name
+// space is created if it does not exist. The hashes of the documents with an
+// expiry, see vectorstores.WithTTL, expire with EXPIREAT.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	nameSpace := s.getNameSpace(opts)
+	_, prefix := s.index(nameSpace)
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	docs = vectorstores.ApplyTTL(opts, docs, time.Now())
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	if !s.skipIndexCreation && nameSpace != s.nameSpace {
+		if err := s.createIndexIfNotExists(ctx, nameSpace); err != nil {
+			return nil, err
+		}
+	}
+
+	for start := 0; start < len(docs); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		pipe := s.client.Pipeline()
+		for i := start; i < end; i++ {
+			fields, err := s.hashFields(docs[i], vectors[i])
+			if err != nil {
+				return nil, err
+			}
+			// The fields of the replaced hash are not kept.
+			pipe.Del(ctx, prefix+ids[i])
+			pipe.HSet(ctx, prefix+ids[i], fields...)
+			if expiresAt, ok := vectorstores.ExpiresAt(docs[i]); ok {
+				pipe.ExpireAt(ctx, prefix+ids[i], expiresAt)
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("add documents %d to %d: %w", start, end, err)
+		}
+	}
+
+	return ids, nil
+}
+
+// Delete deletes the hashes of the documents of the name space with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, prefix := s.index(s.getNameSpace(s.getOptions(options...)))
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, prefix+id)
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// DeleteByFilter deletes the hashes of the documents of the name space
+// matching the filter, a Filter or a RediSearch query expression string as in
+// SimilaritySearch, searching them in batches.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	expression, err := s.getFilter(opts)
+	if err != nil {
+		return err
+	}
+	indexName, _ := s.index(s.getNameSpace(opts))
+	for {
+		res, err := s.client.Do(ctx,
+			"FT.SEARCH", indexName, expression, "NOCONTENT", "LIMIT", 0, s.batchSize, "DIALECT", 2).Result()
+		if isUnknownIndex(err) {
+			// The name space has no documents.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		keys, err := parseKeys(res)
+		if err != nil || len(keys) == 0 {
+			return err
+		}
+		if err := s.client.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a Filter of
+// the tag and numeric fields, e.g. And(Tag("country", "France"),
+// Numeric("year", 2000, 2020)), or a RediSearch query expression string. The
+// scores of the documents are the similarities of their distances, see score.
+// The documents are searched in the index of the name space of the options.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SimilaritySearchByVector(ctx, vector, numDocuments, options...)
+}
+
+// SimilaritySearchByVector returns the most similar documents to the vector,
+// as SimilaritySearch. The documents of the offset of the options are
+// skipped with the LIMIT of the search.
+func (s Store) SimilaritySearchByVector(ctx context.Context, vector []float64, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.getFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	indexName, _ := s.index(s.getNameSpace(opts))
+	res, err := s.client.Do(ctx, s.searchArgs(indexName, filter, vector, offset, fetchK)...).Result()
+	if isUnknownIndex(err) {
+		// The name space has no documents.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseSearch(res)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	for _, fields := range results {
+		distance, err := strconv.ParseFloat(fields[_scoreField], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: score %q", ErrInvalidResponse, fields[_scoreField])
+		}
+		doc := schema.Document{PageContent: fields[_contentField], Score: s.score(distance)}
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && doc.Score < scoreThreshold {
+			continue
+		}
+		if metadata := fields[_metadataField]; metadata != "" {
+			if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+				return nil, fmt.Errorf("decoding metadata: %w", err)
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// createIndexIfNotExists creates the index on the hashes of the prefix if it
+// does not exist.
+func (s Store) createIndexIfNotExists(ctx context.Context, nameSpace string) error {
+	indexName, prefix := s.index(nameSpace)
+	err := s.client.Do(ctx, "FT.INFO", indexName).Err()
+	if err == nil {
+		return nil
+	}
+	if !isUnknownIndex(err) {
+		return err
+	}
+	if err := s.client.Do(ctx, s.createIndexArgs(indexName, prefix)...).Err(); err != nil {
+		return fmt.Errorf("create index: %w", err)
+	}
+	return nil
+}
+
+// isUnknownIndex reports whether the error is the error of a command on an
+// index that does not exist.
+func isUnknownIndex(err error) bool {
+	if err == nil {
+		return false
+	}
+	// The message depends on the version of RediSearch.
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "unknown index name") || strings.Contains(message, "no such index")
+}
+
+// index returns the name of the index of the name space and the prefix of
+// the keys of its hashes: those of the options for the empty name space,
+// <index name>:<name space> and <name space>:<prefix> for the others, so that
+// the indexes of the name spaces do not index the hashes of the others.
+func (s Store) index(nameSpace string) (string, string) {
+	if nameSpace == "" {
+		return s.indexName, s.prefix
+	}
+	return s.indexName + ":" + nameSpace, nameSpace + ":" + s.prefix
+}
+
+// createIndexArgs returns the FT.CREATE command of the index of the hashes
+// of the prefix.
+func (s Store) createIndexArgs(indexName, prefix string) []any {
+	vectorParams := []any{
+		"TYPE", "FLOAT32",
+		"DIM", s.vectorDimensions,
+		"DISTANCE_METRIC", string(s.distanceMetric),
+	}
+	if s.algorithm == AlgorithmHNSW {
+		if s.hnswM > 0 {
+			vectorParams = append(vectorParams, "M", s.hnswM)
+		}
+		if s.hnswEfConstruction > 0 {
+			vectorParams = append(vectorParams, "EF_CONSTRUCTION", s.hnswEfConstruction)
+		}
+	}
+
+	args := []any{
+		"FT.CREATE", indexName, "ON", "HASH", "PREFIX", 1, prefix,
+		"SCHEMA",
+		_contentField, "TEXT",
+		_vectorField, "VECTOR", string(s.algorithm), len(vectorParams),
+	}
+	args = append(args, vectorParams...)
+	for _, field := range s.tagFields {
+		args = append(args, field, "TAG")
+	}
+	for _, field := range s.numericFields {
+		args = append(args, field, "NUMERIC")
+	}
+	return args
+}
+
+// searchArgs returns the FT.SEARCH command of the nearest documents of the
+// index to the vector matching the filter, sorted by distance, after the
+// first offset documents.
+func (s Store) searchArgs(indexName, filter string, vector []float64, offset, numDocuments int) []any {
+	if filter == "" {
+		filter = "*"
+	}
+	return []any{
+		"FT.SEARCH", indexName,
+		fmt.Sprintf("(%s)=>[KNN $K @%s $VECTOR AS %s]", filter, _vectorField, _scoreField),
+		"PARAMS", 4, "K", offset + numDocuments, "VECTOR", vectorBytes(vector),
+		"SORTBY", _scoreField,
+		"RETURN", 3, _contentField, _metadataField, _scoreField,
+		"LIMIT", offset, numDocuments,
+		"DIALECT", 2,
+	}
+}
+
+// hashFields returns the fields of the hash of the document: its text, its
+// metadata, its vector and its indexed metadata fields.
+func (s Store) hashFields(doc schema.Document, vector []float64) ([]any, error) {
+	metadata := doc.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	fields := []any{
+		_contentField, doc.PageContent,
+		_metadataField, string(encoded),
+		_vectorField, vectorBytes(vector),
+	}
+	for _, field := range s.tagFields {
+		switch value := metadata[field].(type) {
+		case nil:
+		case []string:
+			fields = append(fields, field, strings.Join(value, ","))
+		case []any:
+			values := make([]string, 0, len(value))
+			for _, v := range value {
+				values = append(values, fmt.Sprint(v))
+			}
+			fields = append(fields, field, strings.Join(values, ","))
+		default:
+			fields = append(fields, field, fmt.Sprint(value))
+		}
+	}
+	for _, field := range s.numericFields {
+		if value, ok := metadata[field]; ok {
+			fields = append(fields, field, fmt.Sprint(value))
+		}
+	}
+	return fields, nil
+}
+
+// score returns the similarity of a distance: 1 - the cosine or inner product
+// distance, or 1 / (1 + the squared Euclidean distance).
+func (s Store) score(distance float64) float64 {
+	if s.distanceMetric == DistanceL2 {
+		return 1 / (1 + distance)
+	}
+	return 1 - distance
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+func (s Store) getFilter(opts vectorstores.Options) (string, error) {
+	switch filter := opts.Filters.(type) {
+	case nil:
+		return "", nil
+	case Filter:
+		return string(filter), nil
+	case string:
+		return filter, nil
+	default:
+		return "", fmt.Errorf("%w: Filter or string required, got %T", ErrInvalidFilter, opts.Filters)
+	}
+}
+
+// getNameSpace returns the name space of the call, scoped by its tenant.
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	nameSpace := s.nameSpace
+	if opts.NameSpace != "" {
+		nameSpace = opts.NameSpace
+	}
+	return vectorstores.TenantNameSpace(opts.Tenant, nameSpace)
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+// parseSearch returns the fields of the documents of the reply of FT.SEARCH,
+// an array with RESP2 and a map with RESP3.
+func parseSearch(res any) ([]map[string]string, error) {
+	switch res := res.(type) {
+	case []any:
+		// The total is followed by the key and the fields of each document.
+		if len(res) == 0 || len(res)%2 != 1 {
+			return nil, fmt.Errorf("%w: %d elements", ErrInvalidResponse, len(res))
+		}
+		results := make([]map[string]string, 0, len(res)/2)
+		for i := 2; i < len(res); i += 2 {
+			fields, ok := res[i].([]any)
+			if !ok || len(fields)%2 != 0 {
+				return nil, fmt.Errorf("%w: fields %v", ErrInvalidResponse, res[i])
+			}
+			result := make(map[string]string, len(fields)/2)
+			for j := 0; j < len(fields); j += 2 {
+				result[fmt.Sprint(fields[j])] = fmt.Sprint(fields[j+1])
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	case map[any]any:
+		docs, ok := res["results"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: missing results", ErrInvalidResponse)
+		}
+		results := make([]map[string]string, 0, len(docs))
+		for _, doc := range docs {
+			doc, _ := doc.(map[any]any)
+			attributes, ok := doc["extra_attributes"].(map[any]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: missing attributes", ErrInvalidResponse)
+			}
+			result := make(map[string]string, len(attributes))
+			for key, value := range attributes {
+				result[fmt.Sprint(key)] = fmt.Sprint(value)
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidResponse, res)
+	}
+}
+
+// parseKeys returns the keys of the documents of the reply of FT.SEARCH with
+// NOCONTENT, an array with RESP2 and a map with RESP3.
+func parseKeys(res any) ([]string, error) {
+	switch res := res.(type) {
+	case []any:
+		// The total is followed by the keys.
+		if len(res) == 0 {
+			return nil, fmt.Errorf("%w: no total", ErrInvalidResponse)
+		}
+		keys := make([]string, 0, len(res)-1)
+		for _, key := range res[1:] {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		return keys, nil
+	case map[any]any:
+		docs, ok := res["results"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: missing results", ErrInvalidResponse)
+		}
+		keys := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			doc, _ := doc.(map[any]any)
+			keys = append(keys, fmt.Sprint(doc["id"]))
+		}
+		return keys, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidResponse, res)
+	}
+}
+
+// vectorBytes returns the vector as little-endian float32 values.
+func vectorBytes(vector []float64) []byte {
+	b := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(float32(v)))
+	}
+	return b
+}
@@ -0,0 +1,552 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeRediSearch is a hook replying to the commands of the store without a
+// server: the hashes are kept in memory and searched by cosine distance in
+// the hashes of the prefix of the index, ignoring the filters.
+type fakeRediSearch struct {
+	mu        sync.Mutex
+	indexes   map[string][]any
+	hashes    map[string]map[string]any
+	expiries  map[string]any
+	searches  [][]any
+	pipelines int
+}
+
+func newFakeClient(t *testing.T) (*redis.Client, *fakeRediSearch) {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { client.Close() })
+	fake := &fakeRediSearch{
+		indexes:  map[string][]any{},
+		hashes:   map[string]map[string]any{},
+		expiries: map[string]any{},
+	}
+	client.AddHook(fake)
+	return client, fake
+}
+
+func (f *fakeRediSearch) DialHook(next redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("no server")
+	}
+}
+
+func (f *fakeRediSearch) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.process(cmd)
+	}
+}
+
+func (f *fakeRediSearch) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(_ context.Context, cmds []redis.Cmder) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.pipelines++
+		for _, cmd := range cmds {
+			if err := f.process(cmd); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (f *fakeRediSearch) process(cmd redis.Cmder) error {
+	args := cmd.Args()
+	switch strings.ToUpper(fmt.Sprint(args[0])) {
+	case "FT.INFO":
+		index := f.indexes[fmt.Sprint(args[1])]
+		if index == nil {
+			cmd.SetErr(errors.New("Unknown index name"))
+			return cmd.Err()
+		}
+		cmd.(*redis.Cmd).SetVal(f.info(index)) //nolint:forcetypeassert
+	case "FT.CREATE":
+		f.indexes[fmt.Sprint(args[1])] = args
+		cmd.(*redis.Cmd).SetVal("OK") //nolint:forcetypeassert
+	case "HSET":
+		fields := map[string]any{}
+		for i := 2; i < len(args); i += 2 {
+			fields[fmt.Sprint(args[i])] = args[i+1]
+		}
+		f.hashes[fmt.Sprint(args[1])] = fields
+		cmd.(*redis.IntCmd).SetVal(int64(len(fields))) //nolint:forcetypeassert
+	case "EXPIREAT":
+		f.expiries[fmt.Sprint(args[1])] = args[2]
+		cmd.(*redis.BoolCmd).SetVal(true) //nolint:forcetypeassert
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := f.hashes[fmt.Sprint(key)]; ok {
+				delete(f.hashes, fmt.Sprint(key))
+				deleted++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(int64(deleted)) //nolint:forcetypeassert
+	case "FT.SEARCH":
+		f.searches = append(f.searches, args)
+		index := f.indexes[fmt.Sprint(args[1])]
+		if index == nil {
+			cmd.SetErr(errors.New("Unknown index name"))
+			return cmd.Err()
+		}
+		prefix := fmt.Sprint(index[6])
+		if args[3] == "NOCONTENT" {
+			cmd.(*redis.Cmd).SetVal(f.keys(prefix)) //nolint:forcetypeassert
+			break
+		}
+		cmd.(*redis.Cmd).SetVal(f.search(prefix, args)) //nolint:forcetypeassert
+	case "FT.DROPINDEX":
+		delete(f.indexes, fmt.Sprint(args[1]))
+		cmd.(*redis.Cmd).SetVal("OK") //nolint:forcetypeassert
+	default:
+		cmd.SetErr(fmt.Errorf("unknown command %v", args[0]))
+	}
+	return cmd.Err()
+}
+
+// info replies to FT.INFO in the RESP2 format, with the vector attribute and
+// the number of documents of the index.
+func (f *fakeRediSearch) info(index []any) []any {
+	var vector []any
+	for i, arg := range index {
+		if arg == _vectorField {
+			vector = []any{"identifier", _vectorField, "attribute", _vectorField, "type", "VECTOR"}
+			// The parameters of the vector follow their number.
+			params := index[i+4 : i+4+index[i+3].(int)] //nolint:forcetypeassert
+			for j := 0; j < len(params); j += 2 {
+				vector = append(vector, strings.ToLower(fmt.Sprint(params[j])), params[j+1])
+			}
+		}
+	}
+	numDocs := len(f.keys(fmt.Sprint(index[6]))) - 1
+	return []any{
+		"index_name", index[1],
+		"attributes", []any{[]any{"identifier", _contentField, "type", "TEXT"}, vector},
+		"num_docs", strconv.Itoa(numDocs),
+	}
+}
+
+// keys replies to FT.SEARCH with NOCONTENT in the RESP2 format, with the
+// keys of all the hashes of the prefix.
+func (f *fakeRediSearch) keys(prefix string) []any {
+	keys := []any{}
+	for key := range f.hashes {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return append([]any{int64(len(keys))}, keys...)
+}
+
+// search replies to FT.SEARCH in the RESP2 format.
+func (f *fakeRediSearch) search(prefix string, args []any) []any {
+	var query []byte
+	k, offset := 0, 0
+	for i := range args {
+		switch args[i] {
+		case "VECTOR":
+			query, _ = args[i+1].([]byte)
+		case "K":
+			k, _ = args[i+1].(int)
+		case "LIMIT":
+			offset, _ = args[i+1].(int)
+		}
+	}
+
+	type hit struct {
+		key      string
+		distance float64
+	}
+	hits := make([]hit, 0, len(f.hashes))
+	for key, fields := range f.hashes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		vector, _ := fields[_vectorField].([]byte)
+		hits = append(hits, hit{key: key, distance: 1 - cosine(decode(query), decode(vector))})
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].distance < hits[j].distance })
+	if len(hits) > k {
+		hits = hits[:k]
+	}
+	if len(hits) > offset {
+		hits = hits[offset:]
+	} else {
+		hits = nil
+	}
+
+	res := []any{int64(len(hits))}
+	for _, h := range hits {
+		res = append(res, h.key, []any{
+			_contentField, f.hashes[h.key][_contentField],
+			_metadataField, f.hashes[h.key][_metadataField],
+			_scoreField, fmt.Sprint(h.distance),
+		})
+	}
+	return res
+}
+
+func decode(b []byte) []float64 {
+	vector := make([]float64, len(b)/4)
+	for i := range vector {
+		vector[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:])))
+	}
+	return vector
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+		WithHNSWIndex(32, 0),
+		WithTagFields("kind"),
+		WithNumericFields("legs"),
+		WithBatchSize(2),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		"FT.CREATE", "docs", "ON", "HASH", "PREFIX", 1, "doc:docs:",
+		"SCHEMA",
+		"content", "TEXT",
+		"content_vector", "VECTOR", "HNSW", 8, "TYPE", "FLOAT32", "DIM", 3, "DISTANCE_METRIC", "COSINE", "M", 32,
+		"kind", "TAG",
+		"legs", "NUMERIC",
+	}, fake.indexes["docs"])
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps.", Metadata: map[string]any{"kind": "animal", "legs": 4}},
+		{PageContent: "The dog barks.", Metadata: map[string]any{"kind": []string{"animal", "pet"}}},
+		{PageContent: "The car is red."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.pipelines)
+	require.Len(t, fake.hashes, 3)
+	for key, fields := range fake.hashes {
+		assert.True(t, strings.HasPrefix(key, "doc:docs:"))
+		switch fields[_contentField] {
+		case "The cat sleeps.":
+			assert.Equal(t, "animal", fields["kind"])
+			assert.Equal(t, "4", fields["legs"])
+		case "The dog barks.":
+			assert.Equal(t, "animal,pet", fields["kind"])
+		case "The car is red.":
+			assert.Equal(t, "{}", fields[_metadataField])
+		}
+	}
+
+	docs, err := store.SimilaritySearch(ctx, "cats", 1, vectorstores.WithFilters(Tag("kind", "animal")))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"kind": "animal", "legs": 4.0}, docs[0].Metadata)
+	assert.Equal(t, "(@kind:{animal})=>[KNN $K @content_vector $VECTOR AS vector_score]", fake.searches[0][2])
+
+	docs, err = store.SimilaritySearch(ctx, "dogs", 3, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+	assert.GreaterOrEqual(t, docs[0].Score, 0.9)
+	assert.Equal(t, "(*)=>[KNN $K @content_vector $VECTOR AS vector_score]", fake.searches[1][2])
+
+	_, err = store.SimilaritySearch(ctx, "dogs", 3, vectorstores.WithFilters(map[string]any{"kind": "animal"}))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	// The index is not created again.
+	_, err = New(ctx, WithClient(client), WithEmbedder(testutil.WordCountEmbedder{}), WithIndexName("docs"), WithVectorDimensions(3))
+	require.NoError(t, err)
+
+	require.NoError(t, store.DropIndex(ctx, true))
+	assert.Empty(t, fake.indexes)
+}
+
+func TestRedisStoreDelete(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+		WithTagFields("kind"),
+	)
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The car is red."},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.Contains(t, fake.hashes, "doc:docs:"+ids[0])
+
+	// The documents are updated by adding them with their IDs, without the
+	// fields of the replaced hashes.
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat eats."}}, vectorstores.WithIDs(ids[0]))
+	require.NoError(t, err)
+	require.Len(t, fake.hashes, 2)
+	assert.Equal(t, "The cat eats.", fake.hashes["doc:docs:"+ids[0]][_contentField])
+	assert.NotContains(t, fake.hashes["doc:docs:"+ids[0]], "kind")
+
+	require.NoError(t, store.Delete(ctx, ids[:1]))
+	assert.Len(t, fake.hashes, 1)
+
+	// The fake deletes all the documents, ignoring the filter.
+	require.NoError(t, store.DeleteByFilter(ctx, Tag("kind", "animal")))
+	assert.Empty(t, fake.hashes)
+	assert.Equal(t, []any{"FT.SEARCH", "docs", "@kind:{animal}", "NOCONTENT", "LIMIT", 0, 1000, "DIALECT", 2},
+		fake.searches[len(fake.searches)-1])
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+}
+
+func TestRedisStoreNameSpace(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat sleeps."}})
+	require.NoError(t, err)
+	// The index of the name space is created with the first documents.
+	docs, err := store.SimilaritySearch(ctx, "cats", 2, vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+	ids, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "The dog barks."}},
+		vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	assert.Equal(t, "pets:doc:docs:", fake.indexes["docs:pets"][6])
+	assert.Contains(t, fake.hashes, "pets:doc:docs:"+ids[0])
+
+	docs, err = store.SimilaritySearch(ctx, "cats", 2, vectorstores.WithNameSpace("pets"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+	docs, err = store.SimilaritySearch(ctx, "dogs", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+
+	// The documents of other name spaces are not deleted.
+	require.NoError(t, store.Delete(ctx, ids))
+	assert.Len(t, fake.hashes, 2)
+	require.NoError(t, store.DeleteByFilter(ctx, Filter("*"), vectorstores.WithNameSpace("pets")))
+	assert.Len(t, fake.hashes, 1)
+	require.NoError(t, store.DropIndex(ctx, false, vectorstores.WithNameSpace("pets")))
+	assert.NotContains(t, fake.indexes, "docs:pets")
+	assert.Contains(t, fake.indexes, "docs")
+}
+
+func TestRedisStoreTTL(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps."},
+		{PageContent: "The dog barks.", Metadata: map[string]any{vectorstores.ExpiresAtKey: 1700000000}},
+	}, vectorstores.WithTTL(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, fake.expiries, 2)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), fake.expiries["doc:docs:"+ids[0]], 5)
+	assert.Equal(t, int64(1700000000), fake.expiries["doc:docs:"+ids[1]])
+
+	// The documents without a time to live do not expire.
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The car is red."}})
+	require.NoError(t, err)
+	assert.Len(t, fake.expiries, 2)
+}
+
+func TestRedisStoreSearchByVector(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The dog barks."},
+		{PageContent: "The cat and the dog."},
+		{PageContent: "The car is red."},
+	})
+	require.NoError(t, err)
+
+	vector := []float64{0.1, 1, 0}
+	docs, err := store.SimilaritySearchByVector(ctx, vector, 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearchByVector(ctx, vector, 1, vectorstores.WithOffset(1))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat and the dog.", docs[0].PageContent)
+	search := fake.searches[len(fake.searches)-1]
+	assert.Equal(t, []any{"LIMIT", 1, 1}, search[len(search)-5:len(search)-2])
+	assert.Equal(t, []any{"K", 2}, search[5:7])
+}
+
+func TestRedisStoreIndexManager(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithClient(client),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("docs"),
+		WithVectorDimensions(3),
+		WithDistanceMetric(DistanceL2),
+		WithSkipIndexCreation(),
+	)
+	require.NoError(t, err)
+	_, err = store.DescribeIndex(ctx)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+
+	require.NoError(t, store.CreateIndex(ctx))
+	info, err := store.DescribeIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, vectorstores.IndexInfo{Name: "docs", Dimensions: 3, Metric: "L2"}, info)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "The cat sleeps."}, {PageContent: "The car."}})
+	require.NoError(t, err)
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.NumDocuments)
+
+	require.NoError(t, store.DeleteIndex(ctx))
+	_, err = store.Stats(ctx)
+	require.ErrorIs(t, err, vectorstores.ErrIndexNotFound)
+}
+
+func TestFilters(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Filter(`@city:{New\ York | Paris}`), Tag("city", "New York", "Paris"))
+	assert.Equal(t, Filter(`@email:{a\@b\.com}`), Tag("email", "a@b.com"))
+	assert.Equal(t, Filter("@year:[2000 +inf]"), Numeric("year", 2000, math.Inf(1)))
+	assert.Equal(t, Filter("(@kind:{animal} -(@legs:[-inf 2.5]))"),
+		And(Tag("kind", "animal"), Not(Numeric("legs", math.Inf(-1), 2.5))))
+	assert.Equal(t, Filter("(@kind:{animal} | @kind:{car})"), Or(Tag("kind", "animal"), Tag("kind", "car")))
+}
+
+func TestParseSearchRESP3(t *testing.T) {
+	t.Parallel()
+
+	results, err := parseSearch(map[any]any{
+		"total_results": int64(1),
+		"results": []any{
+			map[any]any{
+				"id": "doc:docs:1",
+				"extra_attributes": map[any]any{
+					"content":      "The cat sleeps.",
+					"vector_score": "0.25",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"content": "The cat sleeps.", "vector_score": "0.25"}}, results)
+
+	_, err = parseSearch("OK")
+	require.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestRedisStoreServer(t *testing.T) {
+	t.Parallel()
+
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		t.Skip("Must set REDIS_URL to run test")
+	}
+
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithURL(redisURL),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("test_"+uuid.New().String()),
+		WithVectorDimensions(3),
+		WithTagFields("kind"),
+	)
+	require.NoError(t, err)
+	defer store.Close()
+	defer func() {
+		require.NoError(t, store.DropIndex(ctx, true))
+	}()
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The cat sleeps.", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "The car is red.", Metadata: map[string]any{"kind": "vehicle"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "cars", 2, vectorstores.WithFilters(Tag("kind", "animal")))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+}
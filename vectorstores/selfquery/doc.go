@@ -0,0 +1,5 @@
+// Package selfquery contains a retriever translating natural-language
+// queries, e.g. "papers after 2021 about transformers", with a chat model
+// into a semantic query and a metadata filter of the filters package, searched
+// in a vector store.
+package selfquery
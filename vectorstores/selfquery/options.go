@@ -0,0 +1,33 @@
+package selfquery
+
+import "github.com/tmc/langchaingo/vectorstores"
+
+const _defaultNumDocuments = 4
+
+// Option is a function type that can be used to modify the retriever.
+type Option func(r *Retriever)
+
+// WithNumDocuments is an option for setting the number of documents
+// retrieved, 4 by default.
+func WithNumDocuments(n int) Option {
+	return func(r *Retriever) {
+		r.numDocuments = n
+	}
+}
+
+// WithSearchOptions is an option for setting the options of the similarity
+// searches, e.g. the name space or the score threshold. A filter of the
+// options is combined with the filters generated.
+func WithSearchOptions(options ...vectorstores.Option) Option {
+	return func(r *Retriever) {
+		r.options = options
+	}
+}
+
+// WithExamples is an option for giving the model examples of queries with
+// their structured queries, improving the filters of smaller models.
+func WithExamples(examples ...Example) Option {
+	return func(r *Retriever) {
+		r.examples = examples
+	}
+}
@@ -0,0 +1,189 @@
+package selfquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// ParseFilter parses a filter expression of the comparisons eq, ne, gt, gte,
+// lt and lte of a field and a value, e.g. gt("year", 2021), in and nin of a
+// field and values, e.g. in("genre", "drama", "comedy"), combined with and,
+// or and not, e.g. and(gt("year", 2021), not(eq("genre", "drama"))). The
+// values are strings in double or single quotes, numbers and booleans.
+func ParseFilter(expression string) (filters.Filter, error) {
+	p := &parser{input: expression}
+	filter, err := p.parseFilter()
+	if err != nil {
+		return filters.Filter{}, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return filters.Filter{}, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return filter, filter.Validate()
+}
+
+// parser is a recursive descent parser of the filter expressions.
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) parseFilter() (filters.Filter, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	op := filters.Op(strings.ToLower(p.input[start:p.pos]))
+	if err := p.expect('('); err != nil {
+		return filters.Filter{}, err
+	}
+
+	var filter filters.Filter
+	switch op {
+	case filters.OpAnd, filters.OpOr, filters.OpNot:
+		filter.Op = op
+		for {
+			operand, err := p.parseFilter()
+			if err != nil {
+				return filters.Filter{}, err
+			}
+			filter.Filters = append(filter.Filters, operand)
+			if !p.accept(',') {
+				break
+			}
+		}
+	case filters.OpEq, filters.OpNe, filters.OpGt, filters.OpGte, filters.OpLt, filters.OpLte,
+		filters.OpIn, filters.OpNin:
+		field, err := p.parseValue()
+		if err != nil {
+			return filters.Filter{}, err
+		}
+		name, ok := field.(string)
+		if !ok {
+			return filters.Filter{}, p.errorf("%s requires a field name, got %v", op, field)
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return filters.Filter{}, err
+		}
+		filter = filters.Filter{Op: op, Field: name, Value: values}
+		if op != filters.OpIn && op != filters.OpNin {
+			if len(values) != 1 {
+				return filters.Filter{}, p.errorf("%s requires one value, got %d", op, len(values))
+			}
+			filter.Value = values[0]
+		}
+	default:
+		return filters.Filter{}, p.errorf("unknown operator %q", op)
+	}
+
+	return filter, p.expect(')')
+}
+
+// parseValues parses the values following a field, separated by commas,
+// possibly in a list.
+func (p *parser) parseValues() ([]any, error) {
+	if !p.accept(',') {
+		return nil, nil
+	}
+	list := p.accept('[')
+	var values []any
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if !p.accept(',') {
+			break
+		}
+	}
+	if list {
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// parseValue parses a string, a number or a boolean.
+func (p *parser) parseValue() (any, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("missing value")
+	}
+	start := p.pos
+	switch quote := p.input[p.pos]; quote {
+	case '"', '\'':
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] != quote {
+			if p.input[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("unterminated string")
+		}
+		p.pos++
+		if quote == '\'' {
+			return p.input[start+1 : p.pos-1], nil
+		}
+		s, err := strconv.Unquote(p.input[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid string %s", p.input[start:p.pos])
+		}
+		return s, nil
+	}
+
+	for p.pos < len(p.input) && !strings.ContainsRune(",)] \t\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	token := p.input[start:p.pos]
+	switch strings.ToLower(token) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if i, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("invalid value %q", token)
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// accept consumes the character if it is next.
+func (p *parser) accept(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(c byte) error {
+	if !p.accept(c) {
+		return p.errorf("expected %q", c)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d in %q", filters.ErrInvalidFilter, fmt.Sprintf(format, args...), p.pos, p.input)
+}
@@ -0,0 +1,310 @@
+package selfquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// _noFilter is the filter of the queries without conditions on the
+// attributes, also accepted empty.
+const _noFilter = "NO_FILTER"
+
+// _prompt is the prompt structuring a query, formatted with the description
+// of the contents, the attributes, the examples and the query.
+const _prompt = `Your goal is to structure the query of a user to match the request schema of a search of documents.
+
+The documents are: %s
+
+The metadata attributes of the documents are:
+%s
+Respond with the query, the text compared to the contents of the documents, without the conditions on the attributes, and the filter, a logical condition on the attributes, or ` + _noFilter + ` if the user did not ask for conditions on the attributes.
+
+The filter is an expression of the comparisons eq, ne, gt, gte, lt and lte of an attribute name and a value, e.g. gt("year", 2021), of the comparisons in and nin of an attribute name and values, e.g. in("genre", "drama", "comedy"), and of the operators and, or and not combining them, e.g. and(gt("year", 2021), not(eq("genre", "drama"))). Only use the attributes listed, with values of their type, strings in double quotes.
+%s
+Query: %s`
+
+// AttributeType is the type of the values of an attribute.
+type AttributeType string
+
+const (
+	TypeString  AttributeType = "string"
+	TypeInteger AttributeType = "integer"
+	TypeNumber  AttributeType = "number"
+	TypeBoolean AttributeType = "boolean"
+)
+
+// AttributeInfo describes a metadata attribute of the documents, which the
+// filters generated may refer to.
+type AttributeInfo struct {
+	// Name is the metadata key of the attribute.
+	Name string
+	// Description is the description of the attribute given to the model,
+	// e.g. "The year the paper was published".
+	Description string
+	// Type is the type of the values of the attribute.
+	Type AttributeType
+}
+
+// StructuredQuery is a query translated into a semantic query and a filter.
+type StructuredQuery struct {
+	// Query is the text compared to the contents of the documents, without
+	// the conditions on the attributes.
+	Query string
+	// Filter is the filter of the metadata of the documents, nil if the
+	// query has no conditions on the attributes.
+	Filter *filters.Filter
+}
+
+// Example is an example of query with its structured query, given to the
+// model.
+type Example struct {
+	Query      string
+	Structured StructuredQuery
+}
+
+// structuredOutput is the structured output of the model.
+type structuredOutput struct {
+	Query  string `json:"query" description:"The text compared to the contents of the documents"`
+	Filter string `json:"filter" description:"The filter expression, or NO_FILTER"`
+}
+
+// Retriever is a retriever translating the queries into a semantic query and
+// a metadata filter with a chat model, searched in a vector store.
+type Retriever struct {
+	model              llms.ChatLLM
+	store              vectorstores.VectorStore
+	contentDescription string
+	attributes         []AttributeInfo
+	numDocuments       int
+	options            []vectorstores.Option
+	examples           []Example
+}
+
+var _ schema.Retriever = Retriever{}
+
+// New creates a new Retriever of the documents of the store, described to the
+// model by the description of their contents, e.g. "Abstracts of research
+// papers", and their metadata attributes.
+func New(
+	model llms.ChatLLM,
+	store vectorstores.VectorStore,
+	contentDescription string,
+	attributes []AttributeInfo,
+	opts ...Option,
+) (Retriever, error) {
+	r := Retriever{
+		model:              model,
+		store:              store,
+		contentDescription: contentDescription,
+		attributes:         attributes,
+		numDocuments:       _defaultNumDocuments,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	if r.model == nil || r.store == nil {
+		return Retriever{}, fmt.Errorf("%w: missing model or store", ErrInvalidOptions)
+	}
+	if r.numDocuments <= 0 {
+		return Retriever{}, fmt.Errorf("%w: number of documents must be positive", ErrInvalidOptions)
+	}
+	for _, attribute := range r.attributes {
+		switch attribute.Type {
+		case TypeString, TypeInteger, TypeNumber, TypeBoolean:
+		default:
+			return Retriever{}, fmt.Errorf("%w: attribute %s has unknown type %q", ErrInvalidOptions,
+				attribute.Name, attribute.Type)
+		}
+	}
+	return r, nil
+}
+
+// GetRelevantDocuments structures the query and searches the store for the
+// documents most similar to its semantic query matching its filter.
+func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	structured, err := r.StructureQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	options, err := r.searchOptions(structured.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.SimilaritySearch(ctx, structured.Query, r.numDocuments, options...)
+}
+
+// StructureQuery translates the query into a semantic query and a filter with
+// the model. The filter only refers to the attributes of the retriever, the
+// strings compared to the numbers and the booleans being converted. The
+// semantic query is the query if the model left it empty.
+func (r Retriever) StructureQuery(ctx context.Context, query string) (StructuredQuery, error) {
+	output, err := llms.GenerateStructured[structuredOutput](ctx, r.model, r.prompt(query))
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+
+	structured := StructuredQuery{Query: strings.TrimSpace(output.Query)}
+	if structured.Query == "" {
+		structured.Query = query
+	}
+	expression := strings.TrimSpace(output.Filter)
+	if expression == "" || strings.EqualFold(expression, _noFilter) {
+		return structured, nil
+	}
+	filter, err := ParseFilter(expression)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	filter, err = r.checkFilter(filter)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	structured.Filter = &filter
+	return structured, nil
+}
+
+// prompt returns the prompt structuring the query.
+func (r Retriever) prompt(query string) string {
+	var attributes strings.Builder
+	for _, attribute := range r.attributes {
+		fmt.Fprintf(&attributes, "- %s (%s): %s\n", attribute.Name, attribute.Type, attribute.Description)
+	}
+
+	var examples strings.Builder
+	for i, example := range r.examples {
+		filter := _noFilter
+		if example.Structured.Filter != nil {
+			filter = FormatFilter(*example.Structured.Filter)
+		}
+		output, _ := json.Marshal(structuredOutput{Query: example.Structured.Query, Filter: filter})
+		fmt.Fprintf(&examples, "\nExample %d:\nQuery: %s\nResponse: %s\n", i+1, example.Query, output)
+	}
+
+	return fmt.Sprintf(_prompt, r.contentDescription, attributes.String(), examples.String(), query)
+}
+
+// searchOptions returns the options of the search with the filter, combined
+// with the filter of the options of the retriever.
+func (r Retriever) searchOptions(filter *filters.Filter) ([]vectorstores.Option, error) {
+	options := r.options[:len(r.options):len(r.options)]
+	if filter == nil {
+		return options, nil
+	}
+
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	switch base := opts.Filters.(type) {
+	case nil:
+		return append(options, vectorstores.WithFilters(*filter)), nil
+	case filters.Filter:
+		return append(options, vectorstores.WithFilters(base.And(*filter))), nil
+	default:
+		return nil, fmt.Errorf("%w: the filters of the search options must be a filters.Filter, got %T",
+			filters.ErrInvalidFilter, opts.Filters)
+	}
+}
+
+// checkFilter returns the filter with the values converted to the types of
+// their attributes, or an error if it refers to an unknown attribute.
+func (r Retriever) checkFilter(f filters.Filter) (filters.Filter, error) {
+	switch f.Op { //nolint:exhaustive
+	case filters.OpAnd, filters.OpOr, filters.OpNot:
+		checked := make([]filters.Filter, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			c, err := r.checkFilter(operand)
+			if err != nil {
+				return filters.Filter{}, err
+			}
+			checked = append(checked, c)
+		}
+		f.Filters = checked
+		return f, nil
+	}
+
+	var attribute *AttributeInfo
+	for i := range r.attributes {
+		if r.attributes[i].Name == f.Field {
+			attribute = &r.attributes[i]
+		}
+	}
+	if attribute == nil {
+		return filters.Filter{}, fmt.Errorf("%w: unknown attribute %q", filters.ErrInvalidFilter, f.Field)
+	}
+	if values, ok := f.Value.([]any); ok {
+		converted := make([]any, 0, len(values))
+		for _, value := range values {
+			converted = append(converted, convert(attribute.Type, value))
+		}
+		f.Value = converted
+	} else {
+		f.Value = convert(attribute.Type, f.Value)
+	}
+	return f, nil
+}
+
+// convert returns the value converted to the type if it is a string of the
+// type, e.g. "2021" for an integer.
+func convert(typ AttributeType, value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch typ { //nolint:exhaustive
+	case TypeInteger:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case TypeNumber:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case TypeBoolean:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// FormatFilter returns the filter as an expression parsed by ParseFilter.
+func FormatFilter(f filters.Filter) string {
+	switch f.Op {
+	case filters.OpAnd, filters.OpOr, filters.OpNot:
+		operands := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			operands = append(operands, FormatFilter(operand))
+		}
+		return fmt.Sprintf("%s(%s)", f.Op, strings.Join(operands, ", "))
+	case filters.OpIn, filters.OpNin:
+		values, _ := f.Values()
+		arguments := []string{strconv.Quote(f.Field)}
+		for _, value := range values {
+			arguments = append(arguments, formatValue(value))
+		}
+		return fmt.Sprintf("%s(%s)", f.Op, strings.Join(arguments, ", "))
+	default:
+		return fmt.Sprintf("%s(%s, %s)", f.Op, strconv.Quote(f.Field), formatValue(f.Value))
+	}
+}
+
+func formatValue(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
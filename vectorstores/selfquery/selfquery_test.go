@@ -0,0 +1,154 @@
+package selfquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+	"github.com/tmc/langchaingo/vectorstores/inmemory"
+)
+
+type fakeChatLLM struct {
+	response string
+	prompts  []string
+}
+
+func (f *fakeChatLLM) Call(_ context.Context, messages []schema.ChatMessage, _ ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	f.prompts = append(f.prompts, messages[len(messages)-1].GetContent())
+	return &schema.AIChatMessage{Content: f.response}, nil
+}
+
+func (f *fakeChatLLM) Generate(context.Context, [][]schema.ChatMessage, ...llms.CallOption) ([]*llms.Generation, error) {
+	return nil, nil
+}
+
+var testAttributes = []AttributeInfo{ //nolint:gochecknoglobals
+	{Name: "year", Description: "The year the paper was published", Type: TypeInteger},
+	{Name: "topic", Description: "The topic of the paper", Type: TypeString},
+}
+
+func newTestStore(t *testing.T) *inmemory.Store {
+	t.Helper()
+
+	store, err := inmemory.New(inmemory.WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "attention is all you need transformers", Metadata: map[string]any{"year": 2017, "topic": "nlp"}},
+		{PageContent: "scaling transformers", Metadata: map[string]any{"year": 2022, "topic": "nlp"}},
+		{PageContent: "transformers for images", Metadata: map[string]any{"year": 2023, "topic": "vision"}},
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	for expression, want := range map[string]filters.Filter{
+		`eq("topic", "nlp")`:    filters.Eq("topic", "nlp"),
+		`gt('year', 2021)`:      filters.Gt("year", int64(2021)),
+		`lte("score", 0.5)`:     filters.Lte("score", 0.5),
+		`ne("open", false)`:     filters.Ne("open", false),
+		`in("topic", "a", "b")`: filters.In("topic", "a", "b"),
+		`nin("year", [1, 2])`:   filters.Nin("year", int64(1), int64(2)),
+		`and(gt("year", 2021), not(eq("topic", "nlp")))`: filters.And(
+			filters.Gt("year", int64(2021)), filters.Not(filters.Eq("topic", "nlp"))),
+		` OR( eq("a", 1) , eq("b", "x, y") ) `: filters.Or(filters.Eq("a", int64(1)), filters.Eq("b", "x, y")),
+	} {
+		filter, err := ParseFilter(expression)
+		require.NoError(t, err, expression)
+		assert.Equal(t, want, filter, expression)
+
+		filter, err = ParseFilter(FormatFilter(want))
+		require.NoError(t, err, expression)
+		assert.Equal(t, want, filter, expression)
+	}
+
+	for _, expression := range []string{
+		``,
+		`year > 2021`,
+		`gt("year")`,
+		`gt("year", 1, 2)`,
+		`gt(2021, "year")`,
+		`like("topic", "nlp")`,
+		`eq("topic", "nlp"`,
+		`eq("topic", nlp)`,
+		`eq("topic", "nlp") extra`,
+	} {
+		_, err := ParseFilter(expression)
+		require.ErrorIs(t, err, filters.ErrInvalidFilter, expression)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	_, err := New(nil, store, "Papers", testAttributes)
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(&fakeChatLLM{}, store, "Papers", []AttributeInfo{{Name: "year", Type: "date"}})
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestStructureQuery(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{response: `{"query": "transformers", "filter": "gte(\"year\", \"2022\")"}`}
+	r, err := New(model, newTestStore(t), "Abstracts of research papers", testAttributes,
+		WithExamples(Example{
+			Query: "vision papers",
+			Structured: StructuredQuery{
+				Query:  "papers",
+				Filter: &filters.Filter{Op: filters.OpEq, Field: "topic", Value: "vision"},
+			},
+		}))
+	require.NoError(t, err)
+
+	structured, err := r.StructureQuery(context.Background(), "papers after 2021 about transformers")
+	require.NoError(t, err)
+	assert.Equal(t, "transformers", structured.Query)
+	require.NotNil(t, structured.Filter)
+	assert.Equal(t, filters.Gte("year", int64(2022)), *structured.Filter)
+
+	require.Len(t, model.prompts, 1)
+	assert.Contains(t, model.prompts[0], "- year (integer): The year the paper was published")
+	assert.Contains(t, model.prompts[0], `eq(\"topic\", \"vision\")`)
+	assert.Contains(t, model.prompts[0], "Query: papers after 2021 about transformers")
+
+	model.response = `{"query": "", "filter": "NO_FILTER"}`
+	structured, err = r.StructureQuery(context.Background(), "transformers")
+	require.NoError(t, err)
+	assert.Equal(t, StructuredQuery{Query: "transformers"}, structured)
+
+	model.response = `{"query": "transformers", "filter": "eq(\"author\", \"bob\")"}`
+	_, err = r.StructureQuery(context.Background(), "transformers by bob")
+	require.ErrorIs(t, err, filters.ErrInvalidFilter)
+}
+
+func TestGetRelevantDocuments(t *testing.T) {
+	t.Parallel()
+
+	model := &fakeChatLLM{response: `{"query": "transformers", "filter": "gt(\"year\", 2021)"}`}
+	r, err := New(model, newTestStore(t), "Abstracts of research papers", testAttributes,
+		WithNumDocuments(5),
+		WithSearchOptions(vectorstores.WithFilters(filters.Eq("topic", "nlp"))))
+	require.NoError(t, err)
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "papers after 2021 about transformers")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "scaling transformers", docs[0].PageContent)
+
+	r, err = New(model, newTestStore(t), "Abstracts of research papers", testAttributes,
+		WithSearchOptions(vectorstores.WithFilters(map[string]any{"topic": "nlp"})))
+	require.NoError(t, err)
+	_, err = r.GetRelevantDocuments(context.Background(), "papers after 2021 about transformers")
+	require.ErrorIs(t, err, filters.ErrInvalidFilter)
+}
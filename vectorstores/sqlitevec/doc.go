@@ -0,0 +1,4 @@
+// Package sqlitevec contains an implementation of the vectorStore
+// interface using SQLite with the sqlite-vec extension, an embedded
+// persistent store for desktop and command line applications.
+package sqlitevec
@@ -0,0 +1,79 @@
+package sqlitevec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const _defaultTableName = "langchain_documents"
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Distance is the distance of the vectors, computed with the distance
+// functions of sqlite-vec.
+type Distance string
+
+const (
+	// DistanceCosine is the cosine distance, vec_distance_cosine.
+	DistanceCosine Distance = "cosine"
+	// DistanceL2 is the Euclidean distance, vec_distance_l2.
+	DistanceL2 Distance = "l2"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithTableName is an option for specifying the name of the table of the
+// documents, langchain_documents by default.
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.tableName = name
+	}
+}
+
+// WithDistance is an option for specifying the distance of the vectors, the
+// cosine distance by default.
+func WithDistance(distance Distance) Option {
+	return func(s *Store) {
+		s.distance = distance
+	}
+}
+
+// WithNameSpace is an option for setting the default name space of the
+// documents, overridden by vectorstores.WithNameSpace.
+func WithNameSpace(nameSpace string) Option {
+	return func(s *Store) {
+		s.nameSpace = nameSpace
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		tableName: _defaultTableName,
+		distance:  DistanceCosine,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if !_identifierRegexp.MatchString(s.tableName) {
+		return Store{}, fmt.Errorf("%w: invalid table name %q", ErrInvalidOptions, s.tableName)
+	}
+	if _, ok := _distanceFunctions[s.distance]; !ok {
+		return Store{}, fmt.Errorf("%w: unknown distance %q", ErrInvalidOptions, s.distance)
+	}
+	return s, nil
+}
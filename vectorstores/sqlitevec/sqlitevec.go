@@ -0,0 +1,440 @@
+package sqlitevec
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	ErrInvalidScoreThreshold = errors.New(
+		"score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are not a
+	// map[string]any of scalar values of the metadata of the documents.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrMissingExtension is returned when the sqlite-vec extension is not
+	// loaded in the database.
+	ErrMissingExtension = errors.New("sqlite-vec extension not loaded")
+)
+
+// nolint:gochecknoglobals
+var _identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// _distanceFunctions are the sqlite-vec functions of the distances.
+var _distanceFunctions = map[Distance]string{ //nolint:gochecknoglobals
+	DistanceCosine: "vec_distance_cosine",
+	DistanceL2:     "vec_distance_l2",
+}
+
+// Store is a wrapper around a SQLite table of documents with their vectors,
+// searched with the distance functions of the sqlite-vec extension. The
+// searches are exact, comparing the query to every vector of the name space,
+// which is fast enough for the collections of desktop applications.
+type Store struct {
+	embedder embeddings.Embedder
+	db       *sql.DB
+	// ownsDB is set when the database is opened by Open and closed by Close.
+	ownsDB bool
+
+	tableName string
+	distance  Distance
+	nameSpace string
+}
+
+var _ vectorstores.VectorStore = Store{}
+
+// New creates a new Store of the database with options. The sqlite-vec
+// extension must be loaded in the connections of the database, e.g. with
+// the Extensions of a sqlite3.SQLiteDriver. The table of the documents is
+// created if it does not exist.
+func New(ctx context.Context, db *sql.DB, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+	s.db = db
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT vec_version()").Scan(&version); err != nil {
+		return Store{}, fmt.Errorf("%w: %w", ErrMissingExtension, err)
+	}
+
+	for _, statement := range s.schemaStatements() {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return Store{}, fmt.Errorf("create schema: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Open opens the SQLite database of the data source name, e.g.
+// "file:documents.db", loading the sqlite-vec extension from its path, e.g.
+// "./vec0.so", and returns a Store using it.
+func Open(ctx context.Context, dsn, extension string, opts ...Option) (Store, error) {
+	db := sql.OpenDB(connector{
+		dsn:    dsn,
+		driver: &sqlite3.SQLiteDriver{Extensions: []string{extension}},
+	})
+	// A single connection serializes the writes, SQLite locking the whole
+	// database.
+	db.SetMaxOpenConns(1)
+	s, err := New(ctx, db, opts...)
+	if err != nil {
+		db.Close()
+		return Store{}, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
+
+// Close closes the database opened by Open. A database given to New is left
+// open.
+func (s Store) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and upserts them into the table in a single transaction.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(
+		"INSERT OR REPLACE INTO %s (id, namespace, content, metadata, embedding) VALUES (?, ?, ?, ?, ?)", s.tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	nameSpace := s.getNameSpace(opts)
+	for i, doc := range docs {
+		metadata, err := marshalMetadata(doc.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := stmt.ExecContext(ctx,
+			ids[i], nameSpace, texts[i], metadata, vectorBlob(vectors[i])); err != nil {
+			return nil, fmt.Errorf("insert document %d: %w", i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete deletes the documents of the name space with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+
+	args := []any{s.getNameSpace(opts)}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE namespace = ? AND id IN (?%s)",
+		s.tableName, strings.Repeat(", ?", len(ids)-1)), args...)
+	return err
+}
+
+// DeleteByFilter deletes the documents of the name space matching the
+// filter, a map[string]any as in SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts.Filters = filter
+
+	f, err := s.getFilter(opts)
+	if err != nil {
+		return err
+	}
+	args := append([]any{s.getNameSpace(opts)}, f.args()...)
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s", s.tableName, whereSQL(len(f.keys))), args...)
+	return err
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. The filters are a
+// map[string]any of scalar values equal to the values of the metadata of the
+// documents, e.g. map[string]any{"country": "France"}. The scores of the
+// documents are the cosine similarity or 1 / (1 + the Euclidean distance).
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
+
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.getFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{vectorBlob(vector), s.getNameSpace(opts)}
+	args = append(args, filter.args()...)
+	args = append(args, fetchK)
+	rows, err := s.db.QueryContext(ctx, s.searchSQL(len(filter.keys)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, fetchK)
+	for rows.Next() {
+		var (
+			doc      schema.Document
+			metadata string
+			distance float64
+		)
+		if err := rows.Scan(&doc.PageContent, &metadata, &distance); err != nil {
+			return nil, err
+		}
+		doc.Score = s.score(distance)
+		// If scoreThreshold is 0, we return all matches.
+		if scoreThreshold != 0 && doc.Score < scoreThreshold {
+			continue
+		}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal metadata: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// schemaStatements returns the statements creating the table and the index
+// of its name spaces.
+func (s Store) schemaStatements() []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT PRIMARY KEY,
+	namespace TEXT NOT NULL DEFAULT '',
+	content TEXT NOT NULL,
+	metadata TEXT NOT NULL DEFAULT '{}',
+	embedding BLOB NOT NULL
+)`, s.tableName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_namespace_idx ON %s (namespace)", s.tableName, s.tableName),
+	}
+}
+
+// searchSQL returns the query of the nearest documents of the name space to
+// the vector, matching the filters on the given number of metadata keys. The
+// arguments are the vector, the name space, the path and the value of each
+// filter and the number of documents.
+func (s Store) searchSQL(filters int) string {
+	return fmt.Sprintf("SELECT content, metadata, %s(embedding, ?) AS distance FROM %s WHERE %s ORDER BY distance LIMIT ?",
+		_distanceFunctions[s.distance], s.tableName, whereSQL(filters))
+}
+
+// whereSQL returns the condition of the documents of the name space matching
+// the filters on the given number of metadata keys, whose arguments are the
+// name space and the arguments of the filter.
+func whereSQL(filters int) string {
+	where := "namespace = ?"
+	for i := 0; i < filters; i++ {
+		where += " AND json_extract(metadata, ?) = ?"
+	}
+	return where
+}
+
+// score returns the similarity of the distance: the cosine similarity, or
+// 1 / (1 + the Euclidean distance).
+func (s Store) score(distance float64) float64 {
+	if s.distance == DistanceL2 {
+		return 1 / (1 + distance)
+	}
+	return 1 - distance
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getNameSpace returns the name space of the call, scoped by its tenant.
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	nameSpace := s.nameSpace
+	if opts.NameSpace != "" {
+		nameSpace = opts.NameSpace
+	}
+	return vectorstores.TenantNameSpace(opts.Tenant, nameSpace)
+}
+
+func (s Store) getScoreThreshold(opts vectorstores.Options) (float64, error) {
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return 0, ErrInvalidScoreThreshold
+	}
+	return opts.ScoreThreshold, nil
+}
+
+// filter is the filters with their keys sorted, for a stable query.
+type filter struct {
+	keys   []string
+	values map[string]any
+}
+
+// args returns the path and the value of each filter.
+func (f filter) args() []any {
+	args := make([]any, 0, 2*len(f.keys))
+	for _, key := range f.keys {
+		args = append(args, jsonPath(key), f.values[key])
+	}
+	return args
+}
+
+// getFilter returns the filters, empty if not set. The booleans are matched
+// as the integers of the JSON functions of SQLite.
+func (s Store) getFilter(opts vectorstores.Options) (filter, error) {
+	if opts.Filters == nil {
+		return filter{}, nil
+	}
+	filters, ok := opts.Filters.(map[string]any)
+	if !ok {
+		return filter{}, fmt.Errorf("%w: map[string]any required, got %T", ErrInvalidFilter, opts.Filters)
+	}
+
+	f := filter{values: make(map[string]any, len(filters))}
+	for key, value := range filters {
+		if strings.Contains(key, `"`) {
+			return filter{}, fmt.Errorf("%w: invalid key %q", ErrInvalidFilter, key)
+		}
+		switch v := value.(type) {
+		case string, int, int64, float64:
+			f.values[key] = v
+		case bool:
+			f.values[key] = 0
+			if v {
+				f.values[key] = 1
+			}
+		default:
+			return filter{}, fmt.Errorf("%w: scalar value required for %q, got %T", ErrInvalidFilter, key, value)
+		}
+		f.keys = append(f.keys, key)
+	}
+	sort.Strings(f.keys)
+	return f, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+// connector opens the connections of the database with the driver.
+type connector struct {
+	dsn    string
+	driver *sqlite3.SQLiteDriver
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// jsonPath returns the JSON path of the key of the metadata.
+func jsonPath(key string) string {
+	return `$."` + key + `"`
+}
+
+// marshalMetadata returns the metadata as a JSON object.
+func marshalMetadata(metadata map[string]any) (string, error) {
+	if metadata == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("marshal metadata: %w", err)
+	}
+	return string(b), nil
+}
+
+// vectorBlob returns the vector in the binary format of sqlite-vec, the
+// little-endian float32 values.
+func vectorBlob(vector []float64) []byte {
+	b := make([]byte, 4*len(vector)) //nolint:gomnd
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(float32(v)))
+	}
+	return b
+}
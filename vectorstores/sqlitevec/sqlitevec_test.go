@@ -0,0 +1,237 @@
+package sqlitevec
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/testutil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func init() { //nolint:gochecknoinits
+	// The distance functions of sqlite-vec used by the store, for the tests
+	// without the extension.
+	sql.Register("sqlite3_fake_vec", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("vec_version", func() string { return "v0.0.0-fake" }, true); err != nil {
+				return err
+			}
+			if err := conn.RegisterFunc("vec_distance_cosine", func(a, b []byte) float64 {
+				x, y := floats(a), floats(b)
+				var dot, nx, ny float64
+				for i := range x {
+					dot += x[i] * y[i]
+					nx += x[i] * x[i]
+					ny += y[i] * y[i]
+				}
+				return 1 - dot/(math.Sqrt(nx)*math.Sqrt(ny))
+			}, true); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("vec_distance_l2", func(a, b []byte) float64 {
+				x, y := floats(a), floats(b)
+				var sum float64
+				for i := range x {
+					sum += (x[i] - y[i]) * (x[i] - y[i])
+				}
+				return math.Sqrt(sum)
+			}, true)
+		},
+	})
+}
+
+func floats(b []byte) []float64 {
+	v := make([]float64, len(b)/4)
+	for i := range v {
+		v[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:])))
+	}
+	return v
+}
+
+var testDocs = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "The cat sleeps.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The dog barks at the cat.", Metadata: map[string]any{"animal": true, "legs": 4}},
+	{PageContent: "The car is red.", Metadata: map[string]any{"animal": false, "color": "red"}},
+}
+
+func newTestStore(t *testing.T, opts ...Option) Store {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3_fake_vec", "file:"+filepath.Join(t.TempDir(), "documents.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := New(context.Background(), db, append([]Option{WithEmbedder(testutil.WordCountEmbedder{})}, opts...)...)
+	require.NoError(t, err)
+	return store
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, distance := range []Distance{DistanceCosine, DistanceL2} {
+		distance := distance
+		t.Run(string(distance), func(t *testing.T) {
+			t.Parallel()
+
+			store := newTestStore(t, WithDistance(distance))
+			_, err := store.AddDocuments(ctx, testDocs)
+			require.NoError(t, err)
+
+			docs, err := store.SimilaritySearch(ctx, "cat", 2)
+			require.NoError(t, err)
+			require.Len(t, docs, 2)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.Equal(t, map[string]any{"animal": true, "legs": float64(4)}, docs[0].Metadata)
+			assert.Equal(t, "The dog barks at the cat.", docs[1].PageContent)
+
+			docs, err = store.SimilaritySearch(ctx, "cat", 3, vectorstores.WithScoreThreshold(0.9))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+			assert.GreaterOrEqual(t, docs[0].Score, 0.9)
+		})
+	}
+}
+
+func TestSimilaritySearchFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestStore(t)
+	_, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "cat", 3,
+		vectorstores.WithFilters(map[string]any{"animal": false, "color": "red"}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(ctx, "car", 3, vectorstores.WithFilters(map[string]any{"legs": 4}))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	_, err = store.SimilaritySearch(ctx, "cat", 3, vectorstores.WithFilters(map[string]any{"legs": []int{4}}))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+	_, err = store.SimilaritySearch(ctx, "cat", 3, vectorstores.WithFilters("animal = 1"))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestSimilaritySearchMMR(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestStore(t)
+	_, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "cat", 2, vectorstores.WithMMR(0.25, 3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+	assert.Equal(t, "The car is red.", docs[1].PageContent)
+}
+
+func TestNameSpaces(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestStore(t, WithNameSpace("pets"))
+	_, err := store.AddDocuments(ctx, testDocs[:2])
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, testDocs[2:], vectorstores.WithNameSpace("cars"))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "car", 3)
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	docs, err = store.SimilaritySearch(ctx, "cat", 3, vectorstores.WithNameSpace("cars"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is red.", docs[0].PageContent)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestStore(t)
+	ids, err := store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "The car is blue.", Metadata: map[string]any{"animal": false, "color": "blue"}},
+	}, vectorstores.WithIDs(ids[2]))
+	require.NoError(t, err)
+	docs, err := store.SimilaritySearch(ctx, "car", 3, vectorstores.WithFilters(map[string]any{"animal": false}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The car is blue.", docs[0].PageContent)
+
+	// The documents of other name spaces are not deleted.
+	require.NoError(t, store.Delete(ctx, ids[:1], vectorstores.WithNameSpace("cars")))
+	require.NoError(t, store.Delete(ctx, ids[:1]))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"color": "blue"}))
+	docs, err = store.SimilaritySearch(ctx, "cat", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The dog barks at the cat.", docs[0].PageContent)
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	_, err = store.AddDocuments(ctx, testDocs, vectorstores.WithIDs("1"))
+	require.ErrorIs(t, err, vectorstores.ErrWrongNumberIDs)
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = New(context.Background(), db)
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), db, WithEmbedder(testutil.WordCountEmbedder{}),
+		WithTableName("documents; DROP TABLE x"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), db, WithEmbedder(testutil.WordCountEmbedder{}), WithDistance("dot"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), db, WithEmbedder(testutil.WordCountEmbedder{}))
+	require.ErrorIs(t, err, ErrMissingExtension)
+}
+
+func TestSQLiteVecStore(t *testing.T) {
+	t.Parallel()
+
+	extension := os.Getenv("SQLITE_VEC_PATH")
+	if extension == "" {
+		t.Skip("Must set SQLITE_VEC_PATH to run test")
+	}
+	ctx := context.Background()
+
+	store, err := Open(ctx, "file:"+filepath.Join(t.TempDir(), "documents.db"), extension,
+		WithEmbedder(testutil.WordCountEmbedder{}))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	_, err = store.AddDocuments(ctx, testDocs)
+	require.NoError(t, err)
+	docs, err := store.SimilaritySearch(ctx, "cat", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "The cat sleeps.", docs[0].PageContent)
+}
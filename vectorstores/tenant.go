@@ -0,0 +1,51 @@
+package vectorstores
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrMissingTenant is returned by the stores requiring a tenant when a call
+// has none, rather than reading or writing the documents outside of the
+// tenants.
+var ErrMissingTenant = errors.New("missing tenant")
+
+// WithTenant returns an Option for setting the tenant of the documents added,
+// searched and deleted, so that the documents of several customers are kept
+// apart in one store. The stores map the tenants to their native
+// multi-tenancy, e.g. the tenants of Weaviate, the namespaces of Pinecone or
+// a tenant column of pgvector, and the other stores scope the name spaces of
+// the documents by tenant, see TenantNameSpace. The documents of a tenant are
+// never returned by the calls of another tenant or without a tenant.
+func WithTenant(tenant string) Option {
+	return func(o *Options) {
+		o.Tenant = tenant
+	}
+}
+
+// ResolveTenant returns the tenant of the options, or the default tenant of
+// the store if not set. If the store requires a tenant, ErrMissingTenant is
+// returned when neither is set, so that a call forgetting its tenant fails
+// rather than running outside of the tenants.
+func ResolveTenant(opts Options, defaultTenant string, required bool) (string, error) {
+	tenant := opts.Tenant
+	if tenant == "" {
+		tenant = defaultTenant
+	}
+	if tenant == "" && required {
+		return "", ErrMissingTenant
+	}
+	return tenant, nil
+}
+
+// TenantNameSpace returns the name space of the documents of the tenant, the
+// name space itself without a tenant, for the stores scoping the tenants by
+// name space. The tenant is escaped, so that two tenants never share a name
+// space, and the name spaces of the documents without a tenant should not
+// contain a slash.
+func TenantNameSpace(tenant, nameSpace string) string {
+	if tenant == "" {
+		return nameSpace
+	}
+	return url.PathEscape(tenant) + "/" + nameSpace
+}
@@ -0,0 +1,59 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestResolveTenant(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{}
+	tenant, err := ResolveTenant(opts, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, tenant)
+
+	_, err = ResolveTenant(opts, "", true)
+	require.ErrorIs(t, err, ErrMissingTenant)
+
+	tenant, err = ResolveTenant(opts, "acme", true)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	WithTenant("globex")(&opts)
+	tenant, err = ResolveTenant(opts, "acme", true)
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenant)
+}
+
+func TestTenantNameSpace(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "docs", TenantNameSpace("", "docs"))
+	assert.Equal(t, "acme/", TenantNameSpace("acme", ""))
+	assert.Equal(t, "acme/docs", TenantNameSpace("acme", "docs"))
+	assert.NotEqual(t, TenantNameSpace("a/b", "c"), TenantNameSpace("a", "b/c"))
+}
+
+func TestDeterministicIDsTenant(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "a"}}
+	opts := Options{}
+	WithDeterministicIDs(ContentHash)(&opts)
+	ids, err := DocumentIDs(opts, docs)
+	require.NoError(t, err)
+
+	WithTenant("acme")(&opts)
+	acme, err := DocumentIDs(opts, docs)
+	require.NoError(t, err)
+	WithTenant("globex")(&opts)
+	globex, err := DocumentIDs(opts, docs)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, ids, acme)
+	assert.NotEqual(t, acme, globex)
+}
@@ -0,0 +1,151 @@
+package vectorstores
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// ExpiresAtKey is the metadata key of the expiry of the documents, the Unix
+// time in seconds at which they expire.
+const ExpiresAtKey = "expires_at"
+
+// WithTTL returns an Option for setting the time to live of the documents
+// added, after which they expire, e.g. for caches of web content or
+// conversation memories. The expiry is set in the metadata of the documents
+// under ExpiresAtKey, unless they already have one, so that the documents
+// expire individually. The stores supporting the expiry natively, e.g.
+// Redis, delete the documents when they expire, the in-memory store does not
+// return them, and the other stores expire them when wrapped in an
+// ExpiringStore.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = ttl
+	}
+}
+
+// ApplyTTL returns the documents with the expiry of the time to live of the
+// options set in their metadata, copied, unless they have one. The documents
+// are returned as is without a time to live.
+func ApplyTTL(opts Options, docs []schema.Document, now time.Time) []schema.Document {
+	if opts.TTL <= 0 {
+		return docs
+	}
+	expiresAt := now.Add(opts.TTL).Unix()
+	expiring := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if _, ok := ExpiresAt(doc); !ok {
+			metadata := make(map[string]any, len(doc.Metadata)+1)
+			for key, value := range doc.Metadata {
+				metadata[key] = value
+			}
+			metadata[ExpiresAtKey] = expiresAt
+			doc.Metadata = metadata
+		}
+		expiring = append(expiring, doc)
+	}
+	return expiring
+}
+
+// ExpiresAt returns the expiry of the document, false if it has none. The
+// expiry is a number of seconds, possibly returned by the stores as a
+// string.
+func ExpiresAt(doc schema.Document) (time.Time, bool) {
+	var seconds int64
+	switch value := doc.Metadata[ExpiresAtKey].(type) {
+	case int:
+		seconds = int64(value)
+	case int64:
+		seconds = value
+	case float64:
+		seconds = int64(value)
+	case json.Number:
+		f, err := value.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		seconds = int64(f)
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		seconds = int64(f)
+	default:
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// Expired reports whether the document expired at the time.
+func Expired(doc schema.Document, now time.Time) bool {
+	expiresAt, ok := ExpiresAt(doc)
+	return ok && !expiresAt.After(now)
+}
+
+// ExpiredFilter returns the filter of the documents expired at the time.
+func ExpiredFilter(now time.Time) filters.Filter {
+	return filters.Lte(ExpiresAtKey, now.Unix())
+}
+
+// DeleteExpired deletes the documents of the store expired now, in the name
+// space of the options, for the stores supporting the filters of the filters
+// package. It is meant to be called periodically, the stores without native
+// expiry keeping the expired documents otherwise.
+func DeleteExpired(ctx context.Context, store VectorStore, options ...Option) error {
+	return store.DeleteByFilter(ctx, ExpiredFilter(time.Now()), options...)
+}
+
+// ExpiringStore is a vector store expiring the documents of a store without
+// native expiry: the time to live of WithTTL is set in the metadata of the
+// documents added, the expired documents are removed from the results of the
+// searches, and they are deleted lazily, when a search finds them, with
+// DeleteByFilter and ExpiredFilter.
+type ExpiringStore struct {
+	VectorStore
+	now func() time.Time
+}
+
+var _ VectorStore = ExpiringStore{}
+
+// NewExpiringStore returns the store expiring the documents of the store.
+func NewExpiringStore(store VectorStore) ExpiringStore {
+	return ExpiringStore{VectorStore: store, now: time.Now}
+}
+
+// AddDocuments adds the documents to the store with the expiry of the time
+// to live of the options.
+func (s ExpiringStore) AddDocuments(ctx context.Context, docs []schema.Document, options ...Option) ([]string, error) { //nolint:lll
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return s.VectorStore.AddDocuments(ctx, ApplyTTL(opts, docs, s.now()), options...)
+}
+
+// SimilaritySearch searches the store and returns the documents found which
+// did not expire, possibly fewer than the number of documents. The expired
+// documents found are deleted from the name space of the options, on a best
+// effort basis: the error of the deletion is not returned, the documents
+// being deleted by the next searches.
+func (s ExpiringStore) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) { //nolint:lll
+	docs, err := s.VectorStore.SimilaritySearch(ctx, query, numDocuments, options...)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	kept := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !Expired(doc, now) {
+			kept = append(kept, doc)
+		}
+	}
+	if len(kept) < len(docs) {
+		_ = s.VectorStore.DeleteByFilter(ctx, ExpiredFilter(now), options...)
+	}
+	return kept, nil
+}
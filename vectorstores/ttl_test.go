@@ -0,0 +1,86 @@
+package vectorstores
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores/filters"
+)
+
+// ttlStore records the documents added and the filters of the deletions.
+type ttlStore struct {
+	fakeStore
+	added   []schema.Document
+	deleted []any
+}
+
+func (s *ttlStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...Option) ([]string, error) {
+	s.added = append(s.added, docs...)
+	return make([]string, len(docs)), nil
+}
+
+func (s *ttlStore) DeleteByFilter(_ context.Context, filter any, _ ...Option) error {
+	s.deleted = append(s.deleted, filter)
+	return nil
+}
+
+func TestApplyTTL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	docs := []schema.Document{
+		{PageContent: "a", Metadata: map[string]any{"source": "a.txt"}},
+		{PageContent: "b", Metadata: map[string]any{ExpiresAtKey: 500}},
+	}
+	assert.Equal(t, docs, ApplyTTL(Options{}, docs, now))
+
+	opts := Options{}
+	WithTTL(time.Minute)(&opts)
+	expiring := ApplyTTL(opts, docs, now)
+	assert.Equal(t, map[string]any{"source": "a.txt", ExpiresAtKey: int64(1060)}, expiring[0].Metadata)
+	assert.Equal(t, map[string]any{ExpiresAtKey: 500}, expiring[1].Metadata)
+	// The metadata of the documents are copied.
+	assert.NotContains(t, docs[0].Metadata, ExpiresAtKey)
+
+	assert.False(t, Expired(expiring[0], now))
+	assert.True(t, Expired(expiring[1], now))
+	assert.True(t, Expired(schema.Document{Metadata: map[string]any{ExpiresAtKey: "999.5"}}, now))
+	assert.True(t, Expired(schema.Document{Metadata: map[string]any{ExpiresAtKey: 1000.0}}, now))
+	assert.False(t, Expired(schema.Document{Metadata: map[string]any{ExpiresAtKey: "never"}}, now))
+	assert.False(t, Expired(schema.Document{}, now))
+
+	assert.Equal(t, filters.Lte(ExpiresAtKey, int64(1000)), ExpiredFilter(now))
+}
+
+func TestExpiringStore(t *testing.T) {
+	t.Parallel()
+
+	store := &ttlStore{fakeStore: fakeStore{docs: []schema.Document{
+		{PageContent: "fresh", Metadata: map[string]any{ExpiresAtKey: 2000}},
+		{PageContent: "expired", Metadata: map[string]any{ExpiresAtKey: 500}},
+		{PageContent: "forever"},
+	}}}
+	expiring := NewExpiringStore(store)
+	expiring.now = func() time.Time { return time.Unix(1000, 0) }
+
+	_, err := expiring.AddDocuments(context.Background(), []schema.Document{{PageContent: "a"}}, WithTTL(time.Second))
+	require.NoError(t, err)
+	require.Len(t, store.added, 1)
+	assert.Equal(t, int64(1001), store.added[0].Metadata[ExpiresAtKey])
+
+	docs, err := expiring.SimilaritySearch(context.Background(), "query", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "fresh", docs[0].PageContent)
+	assert.Equal(t, "forever", docs[1].PageContent)
+	// The expired documents found are deleted.
+	assert.Equal(t, []any{filters.Lte(ExpiresAtKey, int64(1000))}, store.deleted)
+
+	_, err = expiring.SimilaritySearch(context.Background(), "query", 1)
+	require.NoError(t, err)
+	assert.Len(t, store.deleted, 1)
+}
@@ -8,9 +8,17 @@ import (
 
 // VectorStore is the interface for saving and querying documents in the
 // form of vector embeddings.
+//
+// AddDocuments returns the IDs of the documents added, the IDs of WithIDs or
+// generated ones, and replaces the documents of the store with the same IDs,
+// so that the documents are updated by adding them again. Delete and
+// DeleteByFilter delete the documents of the name space of the options with
+// the IDs or matching the filter, the filters being those of WithFilters.
 type VectorStore interface {
-	AddDocuments(context.Context, []schema.Document, ...Option) error
+	AddDocuments(context.Context, []schema.Document, ...Option) ([]string, error)
 	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
+	Delete(ctx context.Context, ids []string, options ...Option) error
+	DeleteByFilter(ctx context.Context, filter any, options ...Option) error
 }
 
 // Retriever is a retriever for vector stores.
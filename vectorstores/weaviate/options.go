@@ -62,6 +62,23 @@ func WithNameSpace(nameSpace string) Option {
 	}
 }
 
+// WithTenant is an option for setting the tenant of the objects added and
+// searched, for a class with multi-tenancy enabled. The tenant of a request
+// can also be set with Store.ForTenant or vectorstores.WithTenant.
+func WithTenant(tenant string) Option {
+	return func(p *Store) {
+		p.tenant = tenant
+	}
+}
+
+// WithRequireTenant is an option for failing the calls without a tenant with
+// vectorstores.ErrMissingTenant, rather than sending them to Weaviate.
+func WithRequireTenant() Option {
+	return func(p *Store) {
+		p.requireTenant = true
+	}
+}
+
 // WithHost is an option for setting the host of the weaviate server.
 func WithHost(host string) Option {
 	return func(p *Store) {
@@ -5,10 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-openapi/strfmt"
-	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
@@ -34,6 +34,7 @@ var (
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
 	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidAlpha  = errors.New("alpha must be between 0 and 1")
 )
 
 // Store is a wrapper around the weaviate client.
@@ -49,6 +50,10 @@ type Store struct {
 	host      string
 	scheme    string
 
+	// optional, the tenant of the objects of a class with multi-tenancy
+	tenant        string
+	requireTenant bool
+
 	// optional
 	apiKey *string
 	// optional
@@ -60,7 +65,10 @@ type Store struct {
 	queryAttrs []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore    = Store{}
+	_ vectorstores.HybridSearcher = Store{}
+)
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -85,22 +93,55 @@ func New(opts ...Option) (Store, error) {
 	return s, nil
 }
 
-func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+// ForTenant returns a copy of the store adding and searching the objects of
+// the tenant, for a class with multi-tenancy enabled. The tenant must exist,
+// see CreateTenants.
+func (s Store) ForTenant(tenant string) Store {
+	s.tenant = tenant
+	return s
+}
+
+// CreateTenants adds the tenants to the class, which must have multi-tenancy
+// enabled.
+func (s Store) CreateTenants(ctx context.Context, tenants ...string) error {
+	ts := make([]models.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		ts = append(ts, models.Tenant{Name: tenant})
+	}
+	return s.client.Schema().TenantsCreator().WithClassName(s.indexName).WithTenants(ts...).Do(ctx)
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and adds them to the class in a batch, replacing the objects with the same
+// IDs. The IDs of the documents are UUIDs.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
 	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
 	nameSpace := s.getNameSpace(opts)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := vectorstores.DocumentIDs(opts, docs)
+	if err != nil {
+		return nil, err
+	}
 
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
 	}
 
-	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(vectors) != len(docs) {
-		return ErrEmbedderWrongNumberVectors
+		return nil, ErrEmbedderWrongNumberVectors
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -119,17 +160,64 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 	for i := range docs {
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         strfmt.UUID(uuid.New().String()),
+			ID:         strfmt.UUID(ids[i]),
 			Vector:     convertVector(vectors[i]),
 			Properties: metadatas[i],
+			Tenant:     tenant,
 		})
 	}
 	if _, err := s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete deletes the objects of the name space with the IDs.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	operands := make([]*filters.WhereBuilder, 0, len(ids))
+	for _, id := range ids {
+		operands = append(operands,
+			filters.Where().WithPath([]string{"id"}).WithOperator(filters.Equal).WithValueString(id))
+	}
+	return s.deleteWhere(ctx, filters.Where().WithOperator(filters.Or).WithOperands(operands), s.getOptions(options...))
+}
+
+// DeleteByFilter deletes the objects of the name space matching the filter,
+// a *filters.WhereBuilder as in SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.deleteWhere(ctx, filter, s.getOptions(options...))
+}
+
+func (s Store) deleteWhere(ctx context.Context, filter any, opts vectorstores.Options) error {
+	tenant, err := s.getTenant(opts)
+	if err != nil {
 		return err
 	}
-	return nil
+	where, err := s.createWhereBuilder(s.getNameSpace(opts), filter)
+	if err != nil {
+		return err
+	}
+	deleter := s.client.Batch().ObjectsBatchDeleter().WithClassName(s.indexName).WithWhere(where)
+	if tenant != "" {
+		deleter = deleter.WithTenant(tenant)
+	}
+	_, err = deleter.Do(ctx)
+	return err
 }
 
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and queries to find the most similar documents. With vectorstores.WithAlpha,
+// the query is a hybrid query fusing the BM25 keyword search of Weaviate, with
+// no sparse embedder, and the vector search, from 0 for a keyword search to 1
+// for a vector search. The score threshold applies to the vector queries only.
+// The scores of the documents are the certainties of the vector queries,
+// between 0 and 1, or the fused scores of the hybrid queries.
 func (s Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -137,7 +225,17 @@ func (s Store) SimilaritySearch(
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if opts.Offset != 0 {
+		return nil, vectorstores.ErrOffsetNotSupported
+	}
 	nameSpace := s.getNameSpace(opts)
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return nil, err
+	}
 	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
 		return nil, err
@@ -147,23 +245,121 @@ func (s Store) SimilaritySearch(
 	if err != nil {
 		return nil, err
 	}
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
 
-	vector, err := s.embedder.EmbedQuery(ctx, query)
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := s.client.GraphQL().
-		Get().
-		WithNearVector(s.client.GraphQL().
+	get := s.client.GraphQL().Get()
+	if opts.Alpha != nil {
+		if *opts.Alpha < 0 || *opts.Alpha > 1 {
+			return nil, ErrInvalidAlpha
+		}
+		// The hybrid query fuses the BM25 keyword search on the query and the
+		// vector search, weighted by alpha.
+		get = get.WithHybrid(s.client.GraphQL().
+			HybridArgumentBuilder().
+			WithQuery(query).
+			WithVector(convertVector(vector)).
+			WithAlpha(float32(*opts.Alpha)),
+		)
+	} else {
+		get = get.WithNearVector(s.client.GraphQL().
 			NearVectorArgBuilder().
 			WithVector(convertVector(vector)).
 			WithCertainty(scoreThreshold),
-		).
-		WithWhere(whereBuilder).
+		)
+	}
+	docs, err := s.search(ctx, get, tenant, whereBuilder, fetchK, opts.Alpha != nil)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// HybridSearch runs a hybrid query fusing the BM25 keyword search of the text
+// of the query and the vector search of its vector, embedded from the text if
+// not set, with the fusion of Weaviate: ranked fusion by default or with
+// vectorstores.FusionRRF, relative score fusion with
+// vectorstores.FusionWeighted. The scores of the documents are the fused
+// scores. The filters are those of SimilaritySearch, the score threshold and
+// the sparse vectors are not supported.
+func (s Store) HybridSearch(
+	ctx context.Context,
+	query vectorstores.HybridQuery,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.SparseEmbedder != nil || query.Sparse != nil {
+		return nil, vectorstores.ErrSparseNotSupported
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	tenant, err := s.getTenant(opts)
+	if err != nil {
+		return nil, err
+	}
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), s.getFilters(opts))
+	if err != nil {
+		return nil, err
+	}
+	fetchK, err := vectorstores.FetchK(opts, numDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := query.Vector
+	if vector == nil {
+		vector, err = s.getEmbedder(opts).EmbedQuery(ctx, query.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hybrid := s.client.GraphQL().
+		HybridArgumentBuilder().
+		WithQuery(query.Text).
+		WithVector(convertVector(vector)).
+		WithAlpha(float32(query.Alpha))
+	switch query.Fusion {
+	case vectorstores.FusionRRF:
+		hybrid = hybrid.WithFusionType(graphql.Ranked)
+	case vectorstores.FusionWeighted:
+		hybrid = hybrid.WithFusionType(graphql.RelativeScore)
+	case vectorstores.FusionDefault:
+	}
+	docs, err := s.search(ctx, s.client.GraphQL().Get().WithHybrid(hybrid), tenant, whereBuilder, fetchK, true)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.RerankMMR(ctx, s.getEmbedder(opts), vector, docs, numDocuments, opts)
+}
+
+// search runs the query of the class, vector or hybrid, in the objects of
+// the tenant if any, and returns the documents found.
+func (s Store) search(
+	ctx context.Context,
+	get *graphql.GetBuilder,
+	tenant string,
+	where *filters.WhereBuilder,
+	limit int,
+	hybrid bool,
+) ([]schema.Document, error) {
+	if tenant != "" {
+		get = get.WithTenant(tenant)
+	}
+	res, err := get.
+		WithWhere(where).
 		WithClassName(s.indexName).
-		WithLimit(numDocuments).
-		WithFields(s.createFields()...).Do(ctx)
+		WithLimit(limit).
+		WithFields(s.createFields(hybrid)...).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -201,12 +397,29 @@ func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]s
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    itemMap,
+			Score:       parseScore(itemMap["_additional"]),
 		}
 		docs = append(docs, doc)
 	}
 	return docs, nil
 }
 
+// parseScore returns the certainty of a vector query or the score of a
+// hybrid query of the additional properties of an object, the score being a
+// string, 0 if missing.
+func parseScore(additional any) float64 {
+	properties, _ := additional.(map[string]any)
+	if certainty, ok := properties["certainty"].(float64); ok {
+		return certainty
+	}
+	score, _ := properties["score"].(string)
+	f, err := strconv.ParseFloat(score, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
 func (s Store) getNameSpace(opts vectorstores.Options) string {
 	if opts.NameSpace != "" {
 		return opts.NameSpace
@@ -214,6 +427,19 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.nameSpace
 }
 
+// getTenant returns the tenant of the call, the tenant of the store by
+// default.
+func (s Store) getTenant(opts vectorstores.Options) (string, error) {
+	return vectorstores.ResolveTenant(opts, s.tenant, s.requireTenant)
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, ErrInvalidScoreThreshold
@@ -252,8 +478,14 @@ func (s Store) createWhereBuilder(namespace string, filter any) (*filters.WhereB
 	}), nil
 }
 
-func (s Store) createFields() []graphql.Field {
-	fields := make([]graphql.Field, 0, len(s.queryAttrs))
+// createFields returns the fields of the query, with the score of the
+// hybrid queries instead of the certainty of the vector queries.
+func (s Store) createFields(hybrid bool) []graphql.Field {
+	additional := "certainty"
+	if hybrid {
+		additional = "score"
+	}
+	fields := make([]graphql.Field, 0, len(s.queryAttrs)+1)
 	for _, attr := range s.queryAttrs {
 		fields = append(fields, graphql.Field{
 			Name: attr,
@@ -262,7 +494,7 @@ func (s Store) createFields() []graphql.Field {
 	fields = append(fields, graphql.Field{
 		Name: "_additional",
 		Fields: []graphql.Field{
-			{Name: "certainty"},
+			{Name: additional},
 		},
 	})
 	return fields
@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/chains"
 	openaiEmbeddings "github.com/tmc/langchaingo/embeddings/openai"
+	"github.com/tmc/langchaingo/internal/testutil"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
@@ -82,7 +84,7 @@ func TestWeaviateStoreRest(t *testing.T) {
 	err = createTestClass(context.Background(), store)
 	require.NoError(t, err)
 
-	err = store.AddDocuments(context.Background(), []schema.Document{
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "tokyo", Metadata: map[string]any{
 			"country": "japan",
 		}},
@@ -116,7 +118,7 @@ func TestWeaviateStoreRestWithScoreThreshold(t *testing.T) {
 	err = createTestClass(context.Background(), store)
 	require.NoError(t, err)
 
-	err = store.AddDocuments(context.Background(), []schema.Document{
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "Tokyo"},
 		{PageContent: "Yokohama"},
 		{PageContent: "Osaka"},
@@ -164,7 +166,7 @@ func TestSimilaritySearchWithInvalidScoreThreshold(t *testing.T) {
 	err = createTestClass(context.Background(), store)
 	require.NoError(t, err)
 
-	err = store.AddDocuments(context.Background(), []schema.Document{
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
 		{PageContent: "Tokyo"},
 		{PageContent: "Yokohama"},
 		{PageContent: "Osaka"},
@@ -210,7 +212,7 @@ func TestWeaviateAsRetriever(t *testing.T) {
 	err = createTestClass(context.Background(), store)
 	require.NoError(t, err)
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{PageContent: "The color of the house is blue."},
@@ -256,7 +258,7 @@ func TestWeaviateAsRetrieverWithScoreThreshold(t *testing.T) {
 	err = createTestClass(context.Background(), store)
 	require.NoError(t, err)
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{PageContent: "The color of the house is blue."},
@@ -310,7 +312,7 @@ func TestWeaviateAsRetrieverWithMetadataFilterEqualsClause(t *testing.T) {
 
 	nameSpace := randomizedCamelCaseClass()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -398,7 +400,7 @@ func TestWeaviateAsRetrieverWithMetadataFilterNotSelected(t *testing.T) {
 
 	nameSpace := randomizedCamelCaseClass()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -478,7 +480,7 @@ func TestWeaviateAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	nameSpace := randomizedCamelCaseClass()
 
-	err = store.AddDocuments(
+	_, err = store.AddDocuments(
 		context.Background(),
 		[]schema.Document{
 			{
@@ -537,3 +539,168 @@ func TestWeaviateAsRetrieverWithMetadataFilters(t *testing.T) {
 	require.NotContains(t, result, "orange", "expected not orange in result")
 	require.NotContains(t, result, "yellow", "expected not yellow in result")
 }
+
+func TestWeaviateStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(context.Background(), store)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The error code E1234 means the disk is full."},
+		{PageContent: "The printer is out of paper."},
+		{PageContent: "Restart the router to fix the connection."},
+	})
+	require.NoError(t, err)
+
+	// The keyword search finds the exact code.
+	docs, err := store.SimilaritySearch(context.Background(), "E1234", 1, vectorstores.WithAlpha(0.2))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E1234")
+
+	_, err = store.SimilaritySearch(context.Background(), "E1234", 1, vectorstores.WithAlpha(1.5))
+	require.ErrorIs(t, err, ErrInvalidAlpha)
+	// The relative score fusion scores the best document 1 with alpha 0.
+	docs, err = vectorstores.HybridSearch(context.Background(), store, vectorstores.HybridQuery{
+		Text:   "E1234",
+		Alpha:  0,
+		Fusion: vectorstores.FusionWeighted,
+	}, 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E1234")
+	require.InDelta(t, 1, docs[0].Score, 1e-6)
+}
+
+func TestWeaviateStoreDelete(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+		WithQueryAttrs([]string{"country"}),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(context.Background(), store)
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Kyoto", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Paris", Metadata: map[string]any{"country": "france"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	// The documents are updated by adding them with their IDs.
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Lyon", Metadata: map[string]any{"country": "france"}},
+	}, vectorstores.WithIDs(ids[2]))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(context.Background(), ids[:1]))
+	require.NoError(t, store.DeleteByFilter(context.Background(),
+		filters.Where().WithPath([]string{"country"}).WithOperator(filters.Equal).WithValueString("france")))
+
+	docs, err := store.SimilaritySearch(context.Background(), "city", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "Kyoto", docs[0].PageContent)
+}
+
+func TestWeaviateStoreMultiTenancy(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithIndexName(randomizedCamelCaseClass()),
+	)
+	require.NoError(t, err)
+
+	err = store.client.Schema().ClassCreator().WithClass(&models.Class{
+		Class: store.indexName,
+		Properties: []*models.Property{
+			{Name: store.textKey, DataType: []string{"text"}},
+			{Name: store.nameSpaceKey, DataType: []string{"text"}},
+		},
+		MultiTenancyConfig: &models.MultiTenancyConfig{Enabled: true},
+	}).Do(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, store.CreateTenants(context.Background(), "tenantA", "tenantB"))
+
+	_, err = store.ForTenant("tenantA").AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The color of the house is blue."},
+	})
+	require.NoError(t, err)
+	_, err = store.ForTenant("tenantB").AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The color of the car is red."},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.ForTenant("tenantA").SimilaritySearch(context.Background(), "color", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "The color of the house is blue.", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(context.Background(), "color", 5, vectorstores.WithTenant("tenantB"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "The color of the car is red.", docs[0].PageContent)
+}
+
+func TestWeaviateStoreRequireTenant(t *testing.T) {
+	t.Parallel()
+
+	store, err := New(
+		WithScheme("http"),
+		WithHost("localhost:8080"),
+		WithEmbedder(testutil.WordCountEmbedder{}),
+		WithIndexName("Docs"),
+		WithRequireTenant(),
+	)
+	require.NoError(t, err)
+
+	// The calls without a tenant fail before reaching Weaviate.
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "a"}})
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	_, err = store.SimilaritySearch(context.Background(), "a", 1)
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	_, err = store.HybridSearch(context.Background(), vectorstores.HybridQuery{Text: "a", Alpha: 0.5}, 1)
+	require.ErrorIs(t, err, vectorstores.ErrMissingTenant)
+	require.ErrorIs(t, store.Delete(context.Background(), []string{"a"}), vectorstores.ErrMissingTenant)
+
+	tenant, err := store.ForTenant("tenantA").getTenant(vectorstores.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "tenantA", tenant)
+	tenant, err = store.getTenant(vectorstores.Options{Tenant: "tenantB"})
+	require.NoError(t, err)
+	assert.Equal(t, "tenantB", tenant)
+}